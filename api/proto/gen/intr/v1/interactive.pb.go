@@ -170,6 +170,7 @@ type Interactive struct {
 	CollectCnt    int64                  `protobuf:"varint,5,opt,name=collect_cnt,json=collectCnt,proto3" json:"collect_cnt,omitempty"`
 	Liked         bool                   `protobuf:"varint,6,opt,name=liked,proto3" json:"liked,omitempty"`
 	Collected     bool                   `protobuf:"varint,7,opt,name=collected,proto3" json:"collected,omitempty"`
+	CommentCnt    int64                  `protobuf:"varint,8,opt,name=comment_cnt,json=commentCnt,proto3" json:"comment_cnt,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -253,6 +254,13 @@ func (x *Interactive) GetCollected() bool {
 	return false
 }
 
+func (x *Interactive) GetCommentCnt() int64 {
+	if x != nil {
+		return x.CommentCnt
+	}
+	return 0
+}
+
 type GetRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Biz           string                 `protobuf:"bytes,1,opt,name=biz,proto3" json:"biz,omitempty"`
@@ -697,6 +705,230 @@ func (*IncrReadCntResponse) Descriptor() ([]byte, []int) {
 	return file_intr_v1_interactive_proto_rawDescGZIP(), []int{12}
 }
 
+type ExportUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uid           int64                  `protobuf:"varint,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportUserRequest) Reset() {
+	*x = ExportUserRequest{}
+	mi := &file_intr_v1_interactive_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUserRequest) ProtoMessage() {}
+
+func (x *ExportUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_intr_v1_interactive_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUserRequest.ProtoReflect.Descriptor instead.
+func (*ExportUserRequest) Descriptor() ([]byte, []int) {
+	return file_intr_v1_interactive_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ExportUserRequest) GetUid() int64 {
+	if x != nil {
+		return x.Uid
+	}
+	return 0
+}
+
+type ExportUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Likes         []*UserLike            `protobuf:"bytes,1,rep,name=likes,proto3" json:"likes,omitempty"`
+	Collections   []*UserCollection      `protobuf:"bytes,2,rep,name=collections,proto3" json:"collections,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportUserResponse) Reset() {
+	*x = ExportUserResponse{}
+	mi := &file_intr_v1_interactive_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUserResponse) ProtoMessage() {}
+
+func (x *ExportUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_intr_v1_interactive_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUserResponse.ProtoReflect.Descriptor instead.
+func (*ExportUserResponse) Descriptor() ([]byte, []int) {
+	return file_intr_v1_interactive_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ExportUserResponse) GetLikes() []*UserLike {
+	if x != nil {
+		return x.Likes
+	}
+	return nil
+}
+
+func (x *ExportUserResponse) GetCollections() []*UserCollection {
+	if x != nil {
+		return x.Collections
+	}
+	return nil
+}
+
+type UserLike struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Biz           string                 `protobuf:"bytes,1,opt,name=biz,proto3" json:"biz,omitempty"`
+	BizId         int64                  `protobuf:"varint,2,opt,name=biz_id,json=bizId,proto3" json:"biz_id,omitempty"`
+	Ctime         int64                  `protobuf:"varint,3,opt,name=ctime,proto3" json:"ctime,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserLike) Reset() {
+	*x = UserLike{}
+	mi := &file_intr_v1_interactive_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserLike) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserLike) ProtoMessage() {}
+
+func (x *UserLike) ProtoReflect() protoreflect.Message {
+	mi := &file_intr_v1_interactive_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserLike.ProtoReflect.Descriptor instead.
+func (*UserLike) Descriptor() ([]byte, []int) {
+	return file_intr_v1_interactive_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UserLike) GetBiz() string {
+	if x != nil {
+		return x.Biz
+	}
+	return ""
+}
+
+func (x *UserLike) GetBizId() int64 {
+	if x != nil {
+		return x.BizId
+	}
+	return 0
+}
+
+func (x *UserLike) GetCtime() int64 {
+	if x != nil {
+		return x.Ctime
+	}
+	return 0
+}
+
+type UserCollection struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Biz           string                 `protobuf:"bytes,1,opt,name=biz,proto3" json:"biz,omitempty"`
+	BizId         int64                  `protobuf:"varint,2,opt,name=biz_id,json=bizId,proto3" json:"biz_id,omitempty"`
+	Cid           int64                  `protobuf:"varint,3,opt,name=cid,proto3" json:"cid,omitempty"`
+	Ctime         int64                  `protobuf:"varint,4,opt,name=ctime,proto3" json:"ctime,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserCollection) Reset() {
+	*x = UserCollection{}
+	mi := &file_intr_v1_interactive_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserCollection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserCollection) ProtoMessage() {}
+
+func (x *UserCollection) ProtoReflect() protoreflect.Message {
+	mi := &file_intr_v1_interactive_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserCollection.ProtoReflect.Descriptor instead.
+func (*UserCollection) Descriptor() ([]byte, []int) {
+	return file_intr_v1_interactive_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UserCollection) GetBiz() string {
+	if x != nil {
+		return x.Biz
+	}
+	return ""
+}
+
+func (x *UserCollection) GetBizId() int64 {
+	if x != nil {
+		return x.BizId
+	}
+	return 0
+}
+
+func (x *UserCollection) GetCid() int64 {
+	if x != nil {
+		return x.Cid
+	}
+	return 0
+}
+
+func (x *UserCollection) GetCtime() int64 {
+	if x != nil {
+		return x.Ctime
+	}
+	return 0
+}
+
 var File_intr_v1_interactive_proto protoreflect.FileDescriptor
 
 const file_intr_v1_interactive_proto_rawDesc = "" +
@@ -712,7 +944,7 @@ const file_intr_v1_interactive_proto_rawDesc = "" +
 	"\x03key\x18\x01 \x01(\x03R\x03key\x12*\n" +
 	"\x05value\x18\x02 \x01(\v2\x14.intr.v1.InteractiveR\x05value:\x028\x01\"7\n" +
 	"\vGetResponse\x12(\n" +
-	"\x04intr\x18\x01 \x01(\v2\x14.intr.v1.InteractiveR\x04intr\"\xc1\x01\n" +
+	"\x04intr\x18\x01 \x01(\v2\x14.intr.v1.InteractiveR\x04intr\"\xe2\x01\n" +
 	"\vInteractive\x12\x10\n" +
 	"\x03biz\x18\x01 \x01(\tR\x03biz\x12\x15\n" +
 	"\x06biz_id\x18\x02 \x01(\x03R\x05bizId\x12\x19\n" +
@@ -721,7 +953,9 @@ const file_intr_v1_interactive_proto_rawDesc = "" +
 	"\vcollect_cnt\x18\x05 \x01(\x03R\n" +
 	"collectCnt\x12\x14\n" +
 	"\x05liked\x18\x06 \x01(\bR\x05liked\x12\x1c\n" +
-	"\tcollected\x18\a \x01(\bR\tcollected\"G\n" +
+	"\tcollected\x18\a \x01(\bR\tcollected\x12\x1f\n" +
+	"\vcomment_cnt\x18\b \x01(\x03R\n" +
+	"commentCnt\"G\n" +
 	"\n" +
 	"GetRequest\x12\x10\n" +
 	"\x03biz\x18\x01 \x01(\tR\x03biz\x12\x15\n" +
@@ -746,7 +980,21 @@ const file_intr_v1_interactive_proto_rawDesc = "" +
 	"\x12IncrReadCntRequest\x12\x10\n" +
 	"\x03biz\x18\x01 \x01(\tR\x03biz\x12\x15\n" +
 	"\x06biz_id\x18\x02 \x01(\x03R\x05bizId\"\x15\n" +
-	"\x13IncrReadCntResponse2\x8b\x03\n" +
+	"\x13IncrReadCntResponse\"%\n" +
+	"\x11ExportUserRequest\x12\x10\n" +
+	"\x03uid\x18\x01 \x01(\x03R\x03uid\"x\n" +
+	"\x12ExportUserResponse\x12'\n" +
+	"\x05likes\x18\x01 \x03(\v2\x11.intr.v1.UserLikeR\x05likes\x129\n" +
+	"\vcollections\x18\x02 \x03(\v2\x17.intr.v1.UserCollectionR\vcollections\"I\n" +
+	"\bUserLike\x12\x10\n" +
+	"\x03biz\x18\x01 \x01(\tR\x03biz\x12\x15\n" +
+	"\x06biz_id\x18\x02 \x01(\x03R\x05bizId\x12\x14\n" +
+	"\x05ctime\x18\x03 \x01(\x03R\x05ctime\"a\n" +
+	"\x0eUserCollection\x12\x10\n" +
+	"\x03biz\x18\x01 \x01(\tR\x03biz\x12\x15\n" +
+	"\x06biz_id\x18\x02 \x01(\x03R\x05bizId\x12\x10\n" +
+	"\x03cid\x18\x03 \x01(\x03R\x03cid\x12\x14\n" +
+	"\x05ctime\x18\x04 \x01(\x03R\x05ctime2\xd2\x03\n" +
 	"\x12InteractiveService\x12H\n" +
 	"\vIncrReadCnt\x12\x1b.intr.v1.IncrReadCntRequest\x1a\x1c.intr.v1.IncrReadCntResponse\x123\n" +
 	"\x04Like\x12\x14.intr.v1.LikeRequest\x1a\x15.intr.v1.LikeResponse\x12E\n" +
@@ -754,7 +1002,9 @@ const file_intr_v1_interactive_proto_rawDesc = "" +
 	"CancelLike\x12\x1a.intr.v1.CancelLikeRequest\x1a\x1b.intr.v1.CancelLikeResponse\x12<\n" +
 	"\aCollect\x12\x17.intr.v1.CollectRequest\x1a\x18.intr.v1.CollectResponse\x120\n" +
 	"\x03Get\x12\x13.intr.v1.GetRequest\x1a\x14.intr.v1.GetResponse\x12?\n" +
-	"\bGetByIds\x12\x18.intr.v1.GetByIdsRequest\x1a\x19.intr.v1.GetByIdsResponseBz\n" +
+	"\bGetByIds\x12\x18.intr.v1.GetByIdsRequest\x1a\x19.intr.v1.GetByIdsResponse\x12E\n" +
+	"\n" +
+	"ExportUser\x12\x1a.intr.v1.ExportUserRequest\x1a\x1b.intr.v1.ExportUserResponseBz\n" +
 	"\vcom.intr.v1B\x10InteractiveProtoP\x01Z\x1capi/proto/gen/intr/v1;intrv1\xa2\x02\x03IXX\xaa\x02\aIntr.V1\xca\x02\aIntr\\V1\xe2\x02\x13Intr\\V1\\GPBMetadata\xea\x02\bIntr::V1b\x06proto3"
 
 var (
@@ -769,7 +1019,7 @@ func file_intr_v1_interactive_proto_rawDescGZIP() []byte {
 	return file_intr_v1_interactive_proto_rawDescData
 }
 
-var file_intr_v1_interactive_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_intr_v1_interactive_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
 var file_intr_v1_interactive_proto_goTypes = []any{
 	(*GetByIdsRequest)(nil),     // 0: intr.v1.GetByIdsRequest
 	(*GetByIdsResponse)(nil),    // 1: intr.v1.GetByIdsResponse
@@ -784,29 +1034,37 @@ var file_intr_v1_interactive_proto_goTypes = []any{
 	(*LikeResponse)(nil),        // 10: intr.v1.LikeResponse
 	(*IncrReadCntRequest)(nil),  // 11: intr.v1.IncrReadCntRequest
 	(*IncrReadCntResponse)(nil), // 12: intr.v1.IncrReadCntResponse
-	nil,                         // 13: intr.v1.GetByIdsResponse.IntrsEntry
+	(*ExportUserRequest)(nil),   // 13: intr.v1.ExportUserRequest
+	(*ExportUserResponse)(nil),  // 14: intr.v1.ExportUserResponse
+	(*UserLike)(nil),            // 15: intr.v1.UserLike
+	(*UserCollection)(nil),      // 16: intr.v1.UserCollection
+	nil,                         // 17: intr.v1.GetByIdsResponse.IntrsEntry
 }
 var file_intr_v1_interactive_proto_depIdxs = []int32{
-	13, // 0: intr.v1.GetByIdsResponse.intrs:type_name -> intr.v1.GetByIdsResponse.IntrsEntry
+	17, // 0: intr.v1.GetByIdsResponse.intrs:type_name -> intr.v1.GetByIdsResponse.IntrsEntry
 	3,  // 1: intr.v1.GetResponse.intr:type_name -> intr.v1.Interactive
-	3,  // 2: intr.v1.GetByIdsResponse.IntrsEntry.value:type_name -> intr.v1.Interactive
-	11, // 3: intr.v1.InteractiveService.IncrReadCnt:input_type -> intr.v1.IncrReadCntRequest
-	9,  // 4: intr.v1.InteractiveService.Like:input_type -> intr.v1.LikeRequest
-	7,  // 5: intr.v1.InteractiveService.CancelLike:input_type -> intr.v1.CancelLikeRequest
-	6,  // 6: intr.v1.InteractiveService.Collect:input_type -> intr.v1.CollectRequest
-	4,  // 7: intr.v1.InteractiveService.Get:input_type -> intr.v1.GetRequest
-	0,  // 8: intr.v1.InteractiveService.GetByIds:input_type -> intr.v1.GetByIdsRequest
-	12, // 9: intr.v1.InteractiveService.IncrReadCnt:output_type -> intr.v1.IncrReadCntResponse
-	10, // 10: intr.v1.InteractiveService.Like:output_type -> intr.v1.LikeResponse
-	8,  // 11: intr.v1.InteractiveService.CancelLike:output_type -> intr.v1.CancelLikeResponse
-	5,  // 12: intr.v1.InteractiveService.Collect:output_type -> intr.v1.CollectResponse
-	2,  // 13: intr.v1.InteractiveService.Get:output_type -> intr.v1.GetResponse
-	1,  // 14: intr.v1.InteractiveService.GetByIds:output_type -> intr.v1.GetByIdsResponse
-	9,  // [9:15] is the sub-list for method output_type
-	3,  // [3:9] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	15, // 2: intr.v1.ExportUserResponse.likes:type_name -> intr.v1.UserLike
+	16, // 3: intr.v1.ExportUserResponse.collections:type_name -> intr.v1.UserCollection
+	3,  // 4: intr.v1.GetByIdsResponse.IntrsEntry.value:type_name -> intr.v1.Interactive
+	11, // 5: intr.v1.InteractiveService.IncrReadCnt:input_type -> intr.v1.IncrReadCntRequest
+	9,  // 6: intr.v1.InteractiveService.Like:input_type -> intr.v1.LikeRequest
+	7,  // 7: intr.v1.InteractiveService.CancelLike:input_type -> intr.v1.CancelLikeRequest
+	6,  // 8: intr.v1.InteractiveService.Collect:input_type -> intr.v1.CollectRequest
+	4,  // 9: intr.v1.InteractiveService.Get:input_type -> intr.v1.GetRequest
+	0,  // 10: intr.v1.InteractiveService.GetByIds:input_type -> intr.v1.GetByIdsRequest
+	13, // 11: intr.v1.InteractiveService.ExportUser:input_type -> intr.v1.ExportUserRequest
+	12, // 12: intr.v1.InteractiveService.IncrReadCnt:output_type -> intr.v1.IncrReadCntResponse
+	10, // 13: intr.v1.InteractiveService.Like:output_type -> intr.v1.LikeResponse
+	8,  // 14: intr.v1.InteractiveService.CancelLike:output_type -> intr.v1.CancelLikeResponse
+	5,  // 15: intr.v1.InteractiveService.Collect:output_type -> intr.v1.CollectResponse
+	2,  // 16: intr.v1.InteractiveService.Get:output_type -> intr.v1.GetResponse
+	1,  // 17: intr.v1.InteractiveService.GetByIds:output_type -> intr.v1.GetByIdsResponse
+	14, // 18: intr.v1.InteractiveService.ExportUser:output_type -> intr.v1.ExportUserResponse
+	12, // [12:19] is the sub-list for method output_type
+	5,  // [5:12] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_intr_v1_interactive_proto_init() }
@@ -820,7 +1078,7 @@ func file_intr_v1_interactive_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_intr_v1_interactive_proto_rawDesc), len(file_intr_v1_interactive_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   14,
+			NumMessages:   18,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -25,6 +25,7 @@ const (
 	InteractiveService_Collect_FullMethodName     = "/intr.v1.InteractiveService/Collect"
 	InteractiveService_Get_FullMethodName         = "/intr.v1.InteractiveService/Get"
 	InteractiveService_GetByIds_FullMethodName    = "/intr.v1.InteractiveService/GetByIds"
+	InteractiveService_ExportUser_FullMethodName  = "/intr.v1.InteractiveService/ExportUser"
 )
 
 // InteractiveServiceClient is the client API for InteractiveService service.
@@ -37,6 +38,7 @@ type InteractiveServiceClient interface {
 	Collect(ctx context.Context, in *CollectRequest, opts ...grpc.CallOption) (*CollectResponse, error)
 	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
 	GetByIds(ctx context.Context, in *GetByIdsRequest, opts ...grpc.CallOption) (*GetByIdsResponse, error)
+	ExportUser(ctx context.Context, in *ExportUserRequest, opts ...grpc.CallOption) (*ExportUserResponse, error)
 }
 
 type interactiveServiceClient struct {
@@ -107,6 +109,16 @@ func (c *interactiveServiceClient) GetByIds(ctx context.Context, in *GetByIdsReq
 	return out, nil
 }
 
+func (c *interactiveServiceClient) ExportUser(ctx context.Context, in *ExportUserRequest, opts ...grpc.CallOption) (*ExportUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportUserResponse)
+	err := c.cc.Invoke(ctx, InteractiveService_ExportUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InteractiveServiceServer is the server API for InteractiveService service.
 // All implementations must embed UnimplementedInteractiveServiceServer
 // for forward compatibility.
@@ -117,6 +129,7 @@ type InteractiveServiceServer interface {
 	Collect(context.Context, *CollectRequest) (*CollectResponse, error)
 	Get(context.Context, *GetRequest) (*GetResponse, error)
 	GetByIds(context.Context, *GetByIdsRequest) (*GetByIdsResponse, error)
+	ExportUser(context.Context, *ExportUserRequest) (*ExportUserResponse, error)
 	mustEmbedUnimplementedInteractiveServiceServer()
 }
 
@@ -145,6 +158,9 @@ func (UnimplementedInteractiveServiceServer) Get(context.Context, *GetRequest) (
 func (UnimplementedInteractiveServiceServer) GetByIds(context.Context, *GetByIdsRequest) (*GetByIdsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetByIds not implemented")
 }
+func (UnimplementedInteractiveServiceServer) ExportUser(context.Context, *ExportUserRequest) (*ExportUserResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ExportUser not implemented")
+}
 func (UnimplementedInteractiveServiceServer) mustEmbedUnimplementedInteractiveServiceServer() {}
 func (UnimplementedInteractiveServiceServer) testEmbeddedByValue()                            {}
 
@@ -274,6 +290,24 @@ func _InteractiveService_GetByIds_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _InteractiveService_ExportUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InteractiveServiceServer).ExportUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InteractiveService_ExportUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InteractiveServiceServer).ExportUser(ctx, req.(*ExportUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // InteractiveService_ServiceDesc is the grpc.ServiceDesc for InteractiveService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -305,6 +339,10 @@ var InteractiveService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetByIds",
 			Handler:    _InteractiveService_GetByIds_Handler,
 		},
+		{
+			MethodName: "ExportUser",
+			Handler:    _InteractiveService_ExportUser_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "intr/v1/interactive.proto",
@@ -1,11 +1,29 @@
 package domain
 
+import "time"
+
 type Interactive struct {
 	Biz        string
 	BizId      int64
 	ReadCnt    int64
 	LikeCnt    int64
 	CollectCnt int64
+	CommentCnt int64
 	Liked      bool
 	Collected  bool
 }
+
+// UserLike 用户点赞过的资源
+type UserLike struct {
+	Biz   string
+	BizId int64
+	Ctime time.Time
+}
+
+// UserCollection 用户收藏过的资源，Cid 是收藏夹
+type UserCollection struct {
+	Biz   string
+	BizId int64
+	Cid   int64
+	Ctime time.Time
+}
@@ -0,0 +1,69 @@
+package events
+
+import (
+	"context"
+	"ddd_demo/interactive/repository"
+	"ddd_demo/internal/events/comment"
+	"ddd_demo/pkg/logger"
+	"ddd_demo/pkg/samarax"
+	"github.com/IBM/sarama"
+	"time"
+)
+
+// CommentCreatedEventConsumer 有人发表了评论，评论数加一
+type CommentCreatedEventConsumer struct {
+	repo   repository.InteractiveRepository
+	client sarama.Client
+	l      logger.LoggerV1
+}
+
+func NewCommentCreatedEventConsumer(repo repository.InteractiveRepository,
+	client sarama.Client, l logger.LoggerV1) *CommentCreatedEventConsumer {
+	return &CommentCreatedEventConsumer{repo: repo, client: client, l: l}
+}
+
+func (c *CommentCreatedEventConsumer) Start() error {
+	cg, err := sarama.NewConsumerGroupFromClient("interactive_comment_created", c.client)
+	if err != nil {
+		return err
+	}
+	go samarax.ConsumeLoop(cg, []string{comment.TopicCommentCreated},
+		samarax.NewRetryHandler[comment.CreatedEvent](c.l, c.Consume), c.l)
+	return nil
+}
+
+func (c *CommentCreatedEventConsumer) Consume(msg *sarama.ConsumerMessage,
+	evt comment.CreatedEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return c.repo.IncrCommentCnt(ctx, evt.Biz, evt.BizId, 1)
+}
+
+// CommentDeletedEventConsumer 评论被删掉了，评论数减去连着回复一起删掉的条数
+type CommentDeletedEventConsumer struct {
+	repo   repository.InteractiveRepository
+	client sarama.Client
+	l      logger.LoggerV1
+}
+
+func NewCommentDeletedEventConsumer(repo repository.InteractiveRepository,
+	client sarama.Client, l logger.LoggerV1) *CommentDeletedEventConsumer {
+	return &CommentDeletedEventConsumer{repo: repo, client: client, l: l}
+}
+
+func (c *CommentDeletedEventConsumer) Start() error {
+	cg, err := sarama.NewConsumerGroupFromClient("interactive_comment_deleted", c.client)
+	if err != nil {
+		return err
+	}
+	go samarax.ConsumeLoop(cg, []string{comment.TopicCommentDeleted},
+		samarax.NewRetryHandler[comment.DeletedEvent](c.l, c.Consume), c.l)
+	return nil
+}
+
+func (c *CommentDeletedEventConsumer) Consume(msg *sarama.ConsumerMessage,
+	evt comment.DeletedEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return c.repo.IncrCommentCnt(ctx, evt.Biz, evt.BizId, -evt.Cnt)
+}
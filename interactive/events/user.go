@@ -0,0 +1,69 @@
+package events
+
+import (
+	"context"
+	"ddd_demo/interactive/repository"
+	"ddd_demo/internal/events/user"
+	"ddd_demo/pkg/logger"
+	"ddd_demo/pkg/samarax"
+	"github.com/IBM/sarama"
+	"time"
+)
+
+// UserMergedEventConsumer 账号合并之后，把点赞、收藏转给新账号
+type UserMergedEventConsumer struct {
+	repo   repository.InteractiveRepository
+	client sarama.Client
+	l      logger.LoggerV1
+}
+
+func NewUserMergedEventConsumer(repo repository.InteractiveRepository,
+	client sarama.Client, l logger.LoggerV1) *UserMergedEventConsumer {
+	return &UserMergedEventConsumer{repo: repo, client: client, l: l}
+}
+
+func (u *UserMergedEventConsumer) Start() error {
+	cg, err := sarama.NewConsumerGroupFromClient("interactive_user", u.client)
+	if err != nil {
+		return err
+	}
+	go samarax.ConsumeLoop(cg, []string{user.TopicUserMerged},
+		samarax.NewRetryHandler[user.MergedEvent](u.l, u.Consume), u.l)
+	return nil
+}
+
+func (u *UserMergedEventConsumer) Consume(msg *sarama.ConsumerMessage,
+	evt user.MergedEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	return u.repo.MergeUser(ctx, evt.From, evt.To)
+}
+
+// UserDeletedEventConsumer 用户注销之后，删除他的点赞、收藏
+type UserDeletedEventConsumer struct {
+	repo   repository.InteractiveRepository
+	client sarama.Client
+	l      logger.LoggerV1
+}
+
+func NewUserDeletedEventConsumer(repo repository.InteractiveRepository,
+	client sarama.Client, l logger.LoggerV1) *UserDeletedEventConsumer {
+	return &UserDeletedEventConsumer{repo: repo, client: client, l: l}
+}
+
+func (u *UserDeletedEventConsumer) Start() error {
+	cg, err := sarama.NewConsumerGroupFromClient("interactive_user_deleted", u.client)
+	if err != nil {
+		return err
+	}
+	go samarax.ConsumeLoop(cg, []string{user.TopicUserDeleted},
+		samarax.NewRetryHandler[user.DeletedEvent](u.l, u.Consume), u.l)
+	return nil
+}
+
+func (u *UserDeletedEventConsumer) Consume(msg *sarama.ConsumerMessage,
+	evt user.DeletedEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	return u.repo.DeleteUser(ctx, evt.Uid)
+}
@@ -5,6 +5,7 @@ import (
 	"ddd_demo/api/proto/gen/intr/v1"
 	"ddd_demo/interactive/domain"
 	"ddd_demo/interactive/service"
+	"github.com/ecodeclub/ekit/slice"
 	"google.golang.org/grpc"
 )
 
@@ -67,6 +68,30 @@ func (i *InteractiveServiceServer) GetByIds(ctx context.Context, request *intrv1
 	}, nil
 }
 
+func (i *InteractiveServiceServer) ExportUser(ctx context.Context, request *intrv1.ExportUserRequest) (*intrv1.ExportUserResponse, error) {
+	likes, cbs, err := i.svc.ExportUser(ctx, request.GetUid())
+	if err != nil {
+		return nil, err
+	}
+	return &intrv1.ExportUserResponse{
+		Likes: slice.Map(likes, func(idx int, src domain.UserLike) *intrv1.UserLike {
+			return &intrv1.UserLike{
+				Biz:   src.Biz,
+				BizId: src.BizId,
+				Ctime: src.Ctime.UnixMilli(),
+			}
+		}),
+		Collections: slice.Map(cbs, func(idx int, src domain.UserCollection) *intrv1.UserCollection {
+			return &intrv1.UserCollection{
+				Biz:   src.Biz,
+				BizId: src.BizId,
+				Cid:   src.Cid,
+				Ctime: src.Ctime.UnixMilli(),
+			}
+		}),
+	}, nil
+}
+
 func (i *InteractiveServiceServer) toDTO(intr domain.Interactive) *intrv1.Interactive {
 	return &intrv1.Interactive{
 		Biz:        intr.Biz,
@@ -76,5 +101,6 @@ func (i *InteractiveServiceServer) toDTO(intr domain.Interactive) *intrv1.Intera
 		Collected:  intr.Collected,
 		Liked:      intr.Liked,
 		LikeCnt:    intr.LikeCnt,
+		CommentCnt: intr.CommentCnt,
 	}
 }
@@ -52,6 +52,12 @@ func InitSaramaSyncProducer(client sarama.Client) sarama.SyncProducer {
 	return p
 }
 
-func InitConsumers(c1 *events2.InteractiveReadEventConsumer, fixConsumer *fixer.Consumer[dao.Interactive]) []events.Consumer {
-	return []events.Consumer{c1, fixConsumer}
+func InitConsumers(c1 *events2.InteractiveReadEventConsumer,
+	userConsumer *events2.UserMergedEventConsumer,
+	deletedConsumer *events2.UserDeletedEventConsumer,
+	commentCreatedConsumer *events2.CommentCreatedEventConsumer,
+	commentDeletedConsumer *events2.CommentDeletedEventConsumer,
+	fixConsumer *fixer.Consumer[dao.Interactive]) []events.Consumer {
+	return []events.Consumer{c1, userConsumer, deletedConsumer,
+		commentCreatedConsumer, commentDeletedConsumer, fixConsumer}
 }
@@ -18,14 +18,17 @@ var (
 const fieldReadCnt = "read_cnt"
 const fieldLikeCnt = "like_cnt"
 const fieldCollectCnt = "collect_cnt"
+const fieldCommentCnt = "comment_cnt"
 
 type InteractiveCache interface {
 	IncrReadCntIfPresent(ctx context.Context, biz string, bizId int64) error
 	IncrLikeCntIfPresent(ctx context.Context, biz string, id int64) error
 	DecrLikeCntIfPresent(ctx context.Context, biz string, id int64) error
 	IncrCollectCntIfPresent(ctx context.Context, biz string, id int64) error
+	IncrCommentCntIfPresent(ctx context.Context, biz string, id int64, delta int64) error
 	Get(ctx context.Context, biz string, id int64) (domain.Interactive, error)
 	Set(ctx context.Context, biz string, bizId int64, res domain.Interactive) error
+	Del(ctx context.Context, biz string, bizId int64) error
 }
 
 type InteractiveRedisCache struct {
@@ -45,6 +48,7 @@ func (i *InteractiveRedisCache) Set(ctx context.Context,
 	err := i.client.HSet(ctx, key, fieldCollectCnt, res.CollectCnt,
 		fieldReadCnt, res.ReadCnt,
 		fieldLikeCnt, res.LikeCnt,
+		fieldCommentCnt, res.CommentCnt,
 	).Err()
 	if err != nil {
 		return err
@@ -67,6 +71,7 @@ func (i *InteractiveRedisCache) Get(ctx context.Context, biz string, id int64) (
 	intr.CollectCnt, _ = strconv.ParseInt(res[fieldCollectCnt], 10, 64)
 	intr.LikeCnt, _ = strconv.ParseInt(res[fieldLikeCnt], 10, 64)
 	intr.ReadCnt, _ = strconv.ParseInt(res[fieldReadCnt], 10, 64)
+	intr.CommentCnt, _ = strconv.ParseInt(res[fieldCommentCnt], 10, 64)
 	return intr, nil
 }
 
@@ -76,6 +81,12 @@ func (i *InteractiveRedisCache) IncrCollectCntIfPresent(ctx context.Context,
 	return i.client.Eval(ctx, luaIncrCnt, []string{key}, fieldCollectCnt, 1).Err()
 }
 
+func (i *InteractiveRedisCache) IncrCommentCntIfPresent(ctx context.Context,
+	biz string, id int64, delta int64) error {
+	key := i.key(biz, id)
+	return i.client.Eval(ctx, luaIncrCnt, []string{key}, fieldCommentCnt, delta).Err()
+}
+
 func (i *InteractiveRedisCache) IncrLikeCntIfPresent(ctx context.Context,
 	biz string, bizId int64) error {
 	key := i.key(biz, bizId)
@@ -96,6 +107,10 @@ func (i *InteractiveRedisCache) IncrReadCntIfPresent(ctx context.Context,
 	return i.client.Eval(ctx, luaIncrCnt, []string{key}, fieldReadCnt, 1).Err()
 }
 
+func (i *InteractiveRedisCache) Del(ctx context.Context, biz string, bizId int64) error {
+	return i.client.Del(ctx, i.key(biz, bizId)).Err()
+}
+
 func (i *InteractiveRedisCache) key(biz string, bizId int64) string {
 	return fmt.Sprintf("interactive:%s:%d", biz, bizId)
 }
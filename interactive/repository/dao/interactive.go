@@ -3,6 +3,7 @@ package dao
 import (
 	"context"
 	"ddd_demo/pkg/migrator"
+	"fmt"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	"time"
@@ -20,6 +21,17 @@ type InteractiveDAO interface {
 		biz string, id int64, uid int64) (UserCollectionBiz, error)
 	Get(ctx context.Context, biz string, id int64) (Interactive, error)
 	GetByIds(ctx context.Context, biz string, ids []int64) ([]Interactive, error)
+	// MergeUser 把 fromUid 的点赞和收藏转给 toUid，两个人都点赞（收藏）过的，计数要减一。
+	// 返回计数发生了变化的资源，只有 Biz 和 BizId 有值
+	MergeUser(ctx context.Context, fromUid int64, toUid int64) ([]Interactive, error)
+	// GetUserLikes 用户所有有效的点赞
+	GetUserLikes(ctx context.Context, uid int64) ([]UserLikeBiz, error)
+	GetUserCollections(ctx context.Context, uid int64) ([]UserCollectionBiz, error)
+	// DeleteUser 删除用户所有的点赞、收藏，并且扣减对应的计数。
+	// 返回计数发生了变化的资源
+	DeleteUser(ctx context.Context, uid int64) ([]Interactive, error)
+	// IncrCommentCnt 评论数加上 delta，删除评论的时候 delta 是负数
+	IncrCommentCnt(ctx context.Context, biz string, bizId int64, delta int64) error
 }
 
 type GORMInteractiveDAO struct {
@@ -143,6 +155,157 @@ func (dao *GORMInteractiveDAO) DeleteLikeInfo(ctx context.Context,
 	})
 }
 
+func (dao *GORMInteractiveDAO) MergeUser(ctx context.Context,
+	fromUid int64, toUid int64) ([]Interactive, error) {
+	var changed []Interactive
+	now := time.Now().UnixMilli()
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		changed = changed[:0]
+		var likes []UserLikeBiz
+		err := tx.Where("uid = ?", fromUid).Find(&likes).Error
+		if err != nil {
+			return err
+		}
+		for _, like := range likes {
+			var dst UserLikeBiz
+			err = tx.Where("uid = ? AND biz = ? AND biz_id = ?", toUid, like.Biz, like.BizId).
+				First(&dst).Error
+			switch err {
+			case gorm.ErrRecordNotFound:
+				err = tx.Model(&UserLikeBiz{}).Where("id = ?", like.Id).
+					Updates(map[string]any{"uid": toUid, "utime": now}).Error
+			case nil:
+				err = dao.mergeLike(tx, like, dst, now)
+				if err == nil && like.Status == 1 && dst.Status == 1 {
+					changed = append(changed, Interactive{Biz: like.Biz, BizId: like.BizId})
+				}
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		var cbs []UserCollectionBiz
+		err = tx.Where("uid = ?", fromUid).Find(&cbs).Error
+		if err != nil {
+			return err
+		}
+		for _, cb := range cbs {
+			var dst UserCollectionBiz
+			err = tx.Where("uid = ? AND biz = ? AND biz_id = ?", toUid, cb.Biz, cb.BizId).
+				First(&dst).Error
+			switch err {
+			case gorm.ErrRecordNotFound:
+				err = tx.Model(&UserCollectionBiz{}).Where("id = ?", cb.Id).
+					Updates(map[string]any{"uid": toUid, "utime": now}).Error
+			case nil:
+				// 两个人都收藏过，保留 toUid 的收藏
+				err = tx.Delete(&UserCollectionBiz{}, cb.Id).Error
+				if err == nil {
+					err = dao.decrCnt(tx, "collect_cnt", cb.Biz, cb.BizId, now)
+				}
+				if err == nil {
+					changed = append(changed, Interactive{Biz: cb.Biz, BizId: cb.BizId})
+				}
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return changed, err
+}
+
+func (dao *GORMInteractiveDAO) GetUserLikes(ctx context.Context, uid int64) ([]UserLikeBiz, error) {
+	var res []UserLikeBiz
+	err := dao.db.WithContext(ctx).
+		Where("uid = ? AND status = ?", uid, 1).
+		Order("id").
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMInteractiveDAO) GetUserCollections(ctx context.Context, uid int64) ([]UserCollectionBiz, error) {
+	var res []UserCollectionBiz
+	err := dao.db.WithContext(ctx).
+		Where("uid = ?", uid).
+		Order("id").
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMInteractiveDAO) DeleteUser(ctx context.Context, uid int64) ([]Interactive, error) {
+	var changed []Interactive
+	now := time.Now().UnixMilli()
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		changed = changed[:0]
+		var likes []UserLikeBiz
+		err := tx.Where("uid = ?", uid).Find(&likes).Error
+		if err != nil {
+			return err
+		}
+		for _, like := range likes {
+			// 取消了的点赞已经扣过计数了
+			if like.Status == 1 {
+				err = dao.decrCnt(tx, "like_cnt", like.Biz, like.BizId, now)
+				if err != nil {
+					return err
+				}
+				changed = append(changed, Interactive{Biz: like.Biz, BizId: like.BizId})
+			}
+		}
+		err = tx.Where("uid = ?", uid).Delete(&UserLikeBiz{}).Error
+		if err != nil {
+			return err
+		}
+
+		var cbs []UserCollectionBiz
+		err = tx.Where("uid = ?", uid).Find(&cbs).Error
+		if err != nil {
+			return err
+		}
+		for _, cb := range cbs {
+			err = dao.decrCnt(tx, "collect_cnt", cb.Biz, cb.BizId, now)
+			if err != nil {
+				return err
+			}
+			changed = append(changed, Interactive{Biz: cb.Biz, BizId: cb.BizId})
+		}
+		return tx.Where("uid = ?", uid).Delete(&UserCollectionBiz{}).Error
+	})
+	return changed, err
+}
+
+// mergeLike 两个人对同一个资源都有点赞记录
+func (dao *GORMInteractiveDAO) mergeLike(tx *gorm.DB, src, dst UserLikeBiz, now int64) error {
+	if src.Status == 1 && dst.Status != 1 {
+		// toUid 取消过点赞，用 fromUid 的点赞记录代替，计数不变
+		err := tx.Delete(&UserLikeBiz{}, dst.Id).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&UserLikeBiz{}).Where("id = ?", src.Id).
+			Updates(map[string]any{"uid": dst.Uid, "utime": now}).Error
+	}
+	err := tx.Delete(&UserLikeBiz{}, src.Id).Error
+	if err != nil || src.Status != 1 || dst.Status != 1 {
+		return err
+	}
+	// 两个人都点赞了，合并之后只算一个
+	return dao.decrCnt(tx, "like_cnt", src.Biz, src.BizId, now)
+}
+
+func (dao *GORMInteractiveDAO) decrCnt(tx *gorm.DB, field string,
+	biz string, bizId int64, now int64) error {
+	return tx.Model(&Interactive{}).
+		Where("biz = ? AND biz_id = ?", biz, bizId).
+		Updates(map[string]any{
+			field:   gorm.Expr(fmt.Sprintf("`%s` - 1", field)),
+			"utime": now,
+		}).Error
+}
+
 func NewGORMInteractiveDAO(db *gorm.DB) InteractiveDAO {
 	return &GORMInteractiveDAO{db: db}
 }
@@ -176,6 +339,24 @@ func (dao *GORMInteractiveDAO) IncrReadCnt(ctx context.Context, biz string, bizI
 	}).Error
 }
 
+func (dao *GORMInteractiveDAO) IncrCommentCnt(ctx context.Context,
+	biz string, bizId int64, delta int64) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			// 新建和删除是两个 topic，删除的消息可能先到，不能减成负数
+			"comment_cnt": gorm.Expr("GREATEST(`comment_cnt` + ?, 0)", delta),
+			"utime":       now,
+		}),
+	}).Create(&Interactive{
+		Biz:        biz,
+		BizId:      bizId,
+		CommentCnt: max(delta, 0),
+		Ctime:      now,
+		Utime:      now,
+	}).Error
+}
+
 type UserLikeBiz struct {
 	Id     int64  `gorm:"primaryKey,autoIncrement"`
 	Uid    int64  `gorm:"uniqueIndex:uid_biz_type_id"`
@@ -209,6 +390,7 @@ type Interactive struct {
 	ReadCnt    int64
 	LikeCnt    int64
 	CollectCnt int64
+	CommentCnt int64
 	Utime      int64
 	Ctime      int64
 }
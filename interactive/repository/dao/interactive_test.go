@@ -0,0 +1,125 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestGORMInteractiveDAO_MergeUser(t *testing.T) {
+	likeCols := []string{"id", "uid", "biz_id", "biz", "status", "utime", "ctime"}
+	testCases := []struct {
+		name string
+		mock func(mock sqlmock.Sqlmock)
+
+		wantChanged []Interactive
+	}{
+		{
+			name: "toUid 没有点赞过，直接转过去",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `user_like_bizs` WHERE uid = \\?").
+					WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows(likeCols).
+						AddRow(10, 1, 100, "article", 1, 0, 0))
+				mock.ExpectQuery("SELECT \\* FROM `user_like_bizs` WHERE uid = \\? AND biz = \\? AND biz_id = \\?").
+					WillReturnRows(sqlmock.NewRows(likeCols))
+				mock.ExpectExec("UPDATE `user_like_bizs` SET `uid`=\\?,`utime`=\\? WHERE id = \\?").
+					WithArgs(int64(2), sqlmock.AnyArg(), int64(10)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "两个人都点赞了，计数减一",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `user_like_bizs` WHERE uid = \\?").
+					WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows(likeCols).
+						AddRow(10, 1, 100, "article", 1, 0, 0))
+				mock.ExpectQuery("SELECT \\* FROM `user_like_bizs` WHERE uid = \\? AND biz = \\? AND biz_id = \\?").
+					WillReturnRows(sqlmock.NewRows(likeCols).
+						AddRow(20, 2, 100, "article", 1, 0, 0))
+				mock.ExpectExec("DELETE FROM `user_like_bizs` WHERE `user_like_bizs`.`id` = \\?").
+					WithArgs(int64(10)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE `interactives` SET `like_cnt`=`like_cnt` - 1,`utime`=\\? "+
+					"WHERE biz = \\? AND biz_id = \\?").
+					WithArgs(sqlmock.AnyArg(), "article", int64(100)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantChanged: []Interactive{{Biz: "article", BizId: 100}},
+		},
+		{
+			name: "toUid 取消过点赞，用 fromUid 的点赞代替，计数不变",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `user_like_bizs` WHERE uid = \\?").
+					WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows(likeCols).
+						AddRow(10, 1, 100, "article", 1, 0, 0))
+				mock.ExpectQuery("SELECT \\* FROM `user_like_bizs` WHERE uid = \\? AND biz = \\? AND biz_id = \\?").
+					WillReturnRows(sqlmock.NewRows(likeCols).
+						AddRow(20, 2, 100, "article", 0, 0, 0))
+				mock.ExpectExec("DELETE FROM `user_like_bizs` WHERE `user_like_bizs`.`id` = \\?").
+					WithArgs(int64(20)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE `user_like_bizs` SET `uid`=\\?,`utime`=\\? WHERE id = \\?").
+					WithArgs(int64(2), sqlmock.AnyArg(), int64(10)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "fromUid 取消过点赞，保留 toUid 的，计数不变",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `user_like_bizs` WHERE uid = \\?").
+					WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows(likeCols).
+						AddRow(10, 1, 100, "article", 0, 0, 0))
+				mock.ExpectQuery("SELECT \\* FROM `user_like_bizs` WHERE uid = \\? AND biz = \\? AND biz_id = \\?").
+					WillReturnRows(sqlmock.NewRows(likeCols).
+						AddRow(20, 2, 100, "article", 1, 0, 0))
+				mock.ExpectExec("DELETE FROM `user_like_bizs` WHERE `user_like_bizs`.`id` = \\?").
+					WithArgs(int64(10)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			mock.ExpectBegin()
+			tc.mock(mock)
+			// 收藏的合并逻辑一样，这里只看点赞
+			mock.ExpectQuery("SELECT \\* FROM `user_collection_bizs` WHERE uid = \\?").
+				WithArgs(int64(1)).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			mock.ExpectCommit()
+
+			dao := NewGORMInteractiveDAO(newMockGORM(t, sqlDB))
+			changed, err := dao.MergeUser(context.Background(), 1, 2)
+			require.NoError(t, err)
+			assert.Equal(t, len(tc.wantChanged), len(changed))
+			for i, c := range tc.wantChanged {
+				assert.Equal(t, c.Biz, changed[i].Biz)
+				assert.Equal(t, c.BizId, changed[i].BizId)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func newMockGORM(t *testing.T, sqlDB *sql.DB) *gorm.DB {
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	require.NoError(t, err)
+	return db
+}
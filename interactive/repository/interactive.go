@@ -7,6 +7,7 @@ import (
 	"ddd_demo/interactive/repository/dao"
 	"ddd_demo/pkg/logger"
 	"github.com/ecodeclub/ekit/slice"
+	"time"
 )
 
 //go:generate mockgen -source=./interactive.go -package=repomocks -destination=./mocks/interactive.mock.go InteractiveRepository
@@ -21,6 +22,14 @@ type InteractiveRepository interface {
 	Liked(ctx context.Context, biz string, id int64, uid int64) (bool, error)
 	Collected(ctx context.Context, biz string, id int64, uid int64) (bool, error)
 	GetByIds(ctx context.Context, biz string, ids []int64) ([]domain.Interactive, error)
+	// MergeUser 合并账号之后，把 fromUid 的点赞、收藏转给 toUid
+	MergeUser(ctx context.Context, fromUid int64, toUid int64) error
+	// ExportUser 导出用户所有的点赞和收藏
+	ExportUser(ctx context.Context, uid int64) ([]domain.UserLike, []domain.UserCollection, error)
+	// DeleteUser 用户注销之后，删除他所有的点赞和收藏
+	DeleteUser(ctx context.Context, uid int64) error
+	// IncrCommentCnt 评论数加上 delta，删除评论的时候 delta 是负数
+	IncrCommentCnt(ctx context.Context, biz string, bizId int64, delta int64) error
 }
 
 type CachedInteractiveRepository struct {
@@ -150,11 +159,86 @@ func (c *CachedInteractiveRepository) IncrReadCnt(ctx context.Context, biz strin
 	return c.cache.IncrReadCntIfPresent(ctx, biz, bizId)
 }
 
+func (c *CachedInteractiveRepository) MergeUser(ctx context.Context, fromUid int64, toUid int64) error {
+	changed, err := c.dao.MergeUser(ctx, fromUid, toUid)
+	if err != nil {
+		return err
+	}
+	for _, intr := range changed {
+		// 计数变了，直接删掉缓存，下次查询的时候重新加载
+		er := c.cache.Del(ctx, intr.Biz, intr.BizId)
+		if er != nil {
+			c.l.Error("合并账号后删除缓存失败",
+				logger.String("biz", intr.Biz),
+				logger.Int64("bizId", intr.BizId),
+				logger.Error(er))
+		}
+	}
+	return nil
+}
+
+func (c *CachedInteractiveRepository) ExportUser(ctx context.Context,
+	uid int64) ([]domain.UserLike, []domain.UserCollection, error) {
+	likes, err := c.dao.GetUserLikes(ctx, uid)
+	if err != nil {
+		return nil, nil, err
+	}
+	cbs, err := c.dao.GetUserCollections(ctx, uid)
+	if err != nil {
+		return nil, nil, err
+	}
+	return slice.Map(likes, func(idx int, src dao.UserLikeBiz) domain.UserLike {
+			return domain.UserLike{
+				Biz:   src.Biz,
+				BizId: src.BizId,
+				Ctime: time.UnixMilli(src.Ctime),
+			}
+		}), slice.Map(cbs, func(idx int, src dao.UserCollectionBiz) domain.UserCollection {
+			return domain.UserCollection{
+				Biz:   src.Biz,
+				BizId: src.BizId,
+				Cid:   src.Cid,
+				Ctime: time.UnixMilli(src.Ctime),
+			}
+		}), nil
+}
+
+func (c *CachedInteractiveRepository) DeleteUser(ctx context.Context, uid int64) error {
+	changed, err := c.dao.DeleteUser(ctx, uid)
+	if err != nil {
+		return err
+	}
+	for _, intr := range changed {
+		er := c.cache.Del(ctx, intr.Biz, intr.BizId)
+		if er != nil {
+			c.l.Error("注销账号后删除缓存失败",
+				logger.String("biz", intr.Biz),
+				logger.Int64("bizId", intr.BizId),
+				logger.Error(er))
+		}
+	}
+	return nil
+}
+
+func (c *CachedInteractiveRepository) IncrCommentCnt(ctx context.Context,
+	biz string, bizId int64, delta int64) error {
+	err := c.dao.IncrCommentCnt(ctx, biz, bizId, delta)
+	if err != nil {
+		return err
+	}
+	if delta > 0 {
+		return c.cache.IncrCommentCntIfPresent(ctx, biz, bizId, delta)
+	}
+	// 数据库里面不会减成负数，缓存里面不好判断，直接删掉
+	return c.cache.Del(ctx, biz, bizId)
+}
+
 func (c *CachedInteractiveRepository) toDomain(ie dao.Interactive) domain.Interactive {
 	return domain.Interactive{
 		BizId:      ie.BizId,
 		ReadCnt:    ie.ReadCnt,
 		LikeCnt:    ie.LikeCnt,
 		CollectCnt: ie.CollectCnt,
+		CommentCnt: ie.CommentCnt,
 	}
 }
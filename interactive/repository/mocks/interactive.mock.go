@@ -108,6 +108,20 @@ func (mr *MockInteractiveRepositoryMockRecorder) GetByIds(ctx, biz, ids interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIds", reflect.TypeOf((*MockInteractiveRepository)(nil).GetByIds), ctx, biz, ids)
 }
 
+// IncrCommentCnt mocks base method.
+func (m *MockInteractiveRepository) IncrCommentCnt(ctx context.Context, biz string, bizId, delta int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrCommentCnt", ctx, biz, bizId, delta)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrCommentCnt indicates an expected call of IncrCommentCnt.
+func (mr *MockInteractiveRepositoryMockRecorder) IncrCommentCnt(ctx, biz, bizId, delta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrCommentCnt", reflect.TypeOf((*MockInteractiveRepository)(nil).IncrCommentCnt), ctx, biz, bizId, delta)
+}
+
 // IncrLike mocks base method.
 func (m *MockInteractiveRepository) IncrLike(ctx context.Context, biz string, id, uid int64) error {
 	m.ctrl.T.Helper()
@@ -150,3 +164,47 @@ func (mr *MockInteractiveRepositoryMockRecorder) Liked(ctx, biz, id, uid interfa
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Liked", reflect.TypeOf((*MockInteractiveRepository)(nil).Liked), ctx, biz, id, uid)
 }
+
+// MergeUser mocks base method.
+func (m *MockInteractiveRepository) MergeUser(ctx context.Context, fromUid, toUid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeUser", ctx, fromUid, toUid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeUser indicates an expected call of MergeUser.
+func (mr *MockInteractiveRepositoryMockRecorder) MergeUser(ctx, fromUid, toUid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeUser", reflect.TypeOf((*MockInteractiveRepository)(nil).MergeUser), ctx, fromUid, toUid)
+}
+
+// ExportUser mocks base method.
+func (m *MockInteractiveRepository) ExportUser(ctx context.Context, uid int64) ([]domain.UserLike, []domain.UserCollection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportUser", ctx, uid)
+	ret0, _ := ret[0].([]domain.UserLike)
+	ret1, _ := ret[1].([]domain.UserCollection)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ExportUser indicates an expected call of ExportUser.
+func (mr *MockInteractiveRepositoryMockRecorder) ExportUser(ctx, uid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportUser", reflect.TypeOf((*MockInteractiveRepository)(nil).ExportUser), ctx, uid)
+}
+
+// DeleteUser mocks base method.
+func (m *MockInteractiveRepository) DeleteUser(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockInteractiveRepositoryMockRecorder) DeleteUser(ctx, uid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockInteractiveRepository)(nil).DeleteUser), ctx, uid)
+}
@@ -15,6 +15,8 @@ type InteractiveService interface {
 	Collect(ctx context.Context, biz string, bizId, cid, uid int64) error
 	Get(ctx context.Context, biz string, id int64, uid int64) (domain.Interactive, error)
 	GetByIds(ctx context.Context, biz string, ids []int64) (map[int64]domain.Interactive, error)
+	// ExportUser 导出用户的点赞和收藏
+	ExportUser(ctx context.Context, uid int64) ([]domain.UserLike, []domain.UserCollection, error)
 }
 
 type interactiveService struct {
@@ -53,6 +55,11 @@ func (i *interactiveService) CancelLike(c context.Context, biz string, id int64,
 	return i.repo.DecrLike(c, biz, id, uid)
 }
 
+func (i *interactiveService) ExportUser(ctx context.Context,
+	uid int64) ([]domain.UserLike, []domain.UserCollection, error) {
+	return i.repo.ExportUser(ctx, uid)
+}
+
 func NewInteractiveService(repo repository.InteractiveRepository) InteractiveService {
 	return &interactiveService{repo: repo}
 }
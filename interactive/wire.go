@@ -34,6 +34,10 @@ func InitApp() *App {
 		interactiveSvcSet,
 		grpc.NewInteractiveServiceServer,
 		events.NewInteractiveReadEventConsumer,
+		events.NewUserMergedEventConsumer,
+		events.NewUserDeletedEventConsumer,
+		events.NewCommentCreatedEventConsumer,
+		events.NewCommentDeletedEventConsumer,
 		ioc.InitInteractiveProducer,
 		ioc.InitFixerConsumer,
 		ioc.InitConsumers,
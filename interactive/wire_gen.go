@@ -35,8 +35,12 @@ func InitApp() *App {
 	interactiveRepository := repository.NewCachedInteractiveRepository(interactiveDAO, loggerV1, interactiveCache)
 	client := ioc.InitSaramaClient()
 	interactiveReadEventConsumer := events.NewInteractiveReadEventConsumer(interactiveRepository, client, loggerV1)
+	userMergedEventConsumer := events.NewUserMergedEventConsumer(interactiveRepository, client, loggerV1)
+	userDeletedEventConsumer := events.NewUserDeletedEventConsumer(interactiveRepository, client, loggerV1)
+	commentCreatedEventConsumer := events.NewCommentCreatedEventConsumer(interactiveRepository, client, loggerV1)
+	commentDeletedEventConsumer := events.NewCommentDeletedEventConsumer(interactiveRepository, client, loggerV1)
 	consumer := ioc.InitFixerConsumer(client, loggerV1, srcDB, dstDB)
-	v := ioc.InitConsumers(interactiveReadEventConsumer, consumer)
+	v := ioc.InitConsumers(interactiveReadEventConsumer, userMergedEventConsumer, userDeletedEventConsumer, commentCreatedEventConsumer, commentDeletedEventConsumer, consumer)
 	interactiveService := service.NewInteractiveService(interactiveRepository)
 	interactiveServiceServer := grpc.NewInteractiveServiceServer(interactiveService)
 	server := ioc.NewGrpcxServer(interactiveServiceServer, loggerV1)
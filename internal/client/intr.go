@@ -39,6 +39,10 @@ func (i *InteractiveClient) GetByIds(ctx context.Context, in *intrv1.GetByIdsReq
 	return i.selectClient().GetByIds(ctx, in, opts...)
 }
 
+func (i *InteractiveClient) ExportUser(ctx context.Context, in *intrv1.ExportUserRequest, opts ...grpc.CallOption) (*intrv1.ExportUserResponse, error) {
+	return i.selectClient().ExportUser(ctx, in, opts...)
+}
+
 func (i *InteractiveClient) selectClient() intrv1.InteractiveServiceClient {
 	// [0, 100) 的随机数
 	num := rand.Int31n(100)
@@ -5,6 +5,7 @@ import (
 	"ddd_demo/api/proto/gen/intr/v1"
 	"ddd_demo/interactive/domain"
 	"ddd_demo/interactive/service"
+	"github.com/ecodeclub/ekit/slice"
 	"google.golang.org/grpc"
 )
 
@@ -56,6 +57,30 @@ func (l *LocalInteractiveServiceAdapter) GetByIds(ctx context.Context, in *intrv
 	}, nil
 }
 
+func (l *LocalInteractiveServiceAdapter) ExportUser(ctx context.Context, in *intrv1.ExportUserRequest, opts ...grpc.CallOption) (*intrv1.ExportUserResponse, error) {
+	likes, cbs, err := l.svc.ExportUser(ctx, in.GetUid())
+	if err != nil {
+		return nil, err
+	}
+	return &intrv1.ExportUserResponse{
+		Likes: slice.Map(likes, func(idx int, src domain.UserLike) *intrv1.UserLike {
+			return &intrv1.UserLike{
+				Biz:   src.Biz,
+				BizId: src.BizId,
+				Ctime: src.Ctime.UnixMilli(),
+			}
+		}),
+		Collections: slice.Map(cbs, func(idx int, src domain.UserCollection) *intrv1.UserCollection {
+			return &intrv1.UserCollection{
+				Biz:   src.Biz,
+				BizId: src.BizId,
+				Cid:   src.Cid,
+				Ctime: src.Ctime.UnixMilli(),
+			}
+		}),
+	}, nil
+}
+
 func (l *LocalInteractiveServiceAdapter) toDTO(intr domain.Interactive) *intrv1.Interactive {
 	return &intrv1.Interactive{
 		Biz:        intr.Biz,
@@ -65,6 +90,7 @@ func (l *LocalInteractiveServiceAdapter) toDTO(intr domain.Interactive) *intrv1.
 		Collected:  intr.Collected,
 		Liked:      intr.Liked,
 		LikeCnt:    intr.LikeCnt,
+		CommentCnt: intr.CommentCnt,
 	}
 }
 
@@ -8,11 +8,35 @@ type Article struct {
 	Content string
 	Author  Author
 	Status  ArticleStatus
-	Ctime   time.Time
-	Utime   time.Time
+	// Visibility 发表之后谁能看到，零值是公开
+	Visibility ArticleVisibility
+	// Tags 标签，service 里面已经规范化过了
+	Tags []string
+	// Revision 当前版本号，线上库的文章就是它同步过来的那个版本
+	Revision int64
+	// Version 乐观锁的版本号，作者每保存一次加一。
+	// 保存的时候传作者编辑的那个版本，和服务端的对不上说明已经在别的地方改过了
+	Version int64
+	// PublishAt 定时发表的时间，只有 ArticleStatusScheduled 的文章才有
+	PublishAt time.Time
+	// Attachments 内容里面引用了的附件的 Key，service 从内容里面解析出来
+	Attachments []string
+	// Rendered 发表的时候从 Content 渲染出来的，只有线上库的文章有
+	Rendered ArticleRendered
+	Ctime    time.Time
+	Utime    time.Time
+}
+
+// Cursor 以这篇文章结尾的那一页的游标
+func (a Article) Cursor() ArticleCursor {
+	return ArticleCursor{Utime: a.Utime, Id: a.Id}
 }
 
 func (a Article) Abstract() string {
+	if a.Rendered.Abstract != "" {
+		return a.Rendered.Abstract
+	}
+	// 草稿没有渲染过
 	str := []rune(a.Content)
 	// 只取部分作为摘要
 	if len(str) > 128 {
@@ -21,6 +45,31 @@ func (a Article) Abstract() string {
 	return string(str)
 }
 
+// Brief 列表里面只展示摘要，放进缓存之前把大字段去掉
+func (a Article) Brief() Article {
+	a.Content = a.Abstract()
+	a.Rendered.HTML = ""
+	a.Rendered.TOC = nil
+	return a
+}
+
+// ArticleRendered 渲染 Markdown 的结果
+type ArticleRendered struct {
+	// HTML 过滤过的 HTML，可以直接展示
+	HTML string
+	// Abstract 纯文本的摘要
+	Abstract string
+	// TOC 目录
+	TOC []ArticleHeading
+}
+
+type ArticleHeading struct {
+	Level int
+	// Id HTML 里面标题的 id
+	Id    string
+	Title string
+}
+
 type ArticleStatus uint8
 
 func (s ArticleStatus) ToUint8() uint8 {
@@ -36,8 +85,53 @@ const (
 	ArticleStatusPublished
 	// ArticleStatusPrivate 仅自己可见
 	ArticleStatusPrivate
+	// ArticleStatusScheduled 定时发表，还没到时间
+	ArticleStatusScheduled
+	// ArticleStatusReviewing 提交发表了，还在审核。线上库还是上一次审核通过的版本
+	ArticleStatusReviewing
+	// ArticleStatusRejected 审核没通过，作者改了之后可以重新发表
+	ArticleStatusRejected
 )
 
+// ArticleVisibility 已经发表的文章谁能看，和 Status 是两回事：
+// 撤回的文章不管可见范围是什么，都只有作者自己能看
+type ArticleVisibility uint8
+
+const (
+	// ArticleVisibilityPublic 公开，会出现在各种列表、热榜和搜索里面
+	ArticleVisibilityPublic ArticleVisibility = iota
+	// ArticleVisibilityUnlisted 不出现在任何列表里面，拿到分享链接的人都能看
+	ArticleVisibilityUnlisted
+	// ArticleVisibilityFollowers 只有关注了作者的人能看，只出现在作者的主页上
+	ArticleVisibilityFollowers
+	// ArticleVisibilityPrivate 只有作者自己和拿到分享链接的人能看
+	ArticleVisibilityPrivate
+)
+
+func (v ArticleVisibility) ToUint8() uint8 {
+	return uint8(v)
+}
+
+func (v ArticleVisibility) Valid() bool {
+	return v <= ArticleVisibilityPrivate
+}
+
+// Shareable 分享链接只对这两种有意义，公开的直接给链接就可以
+func (v ArticleVisibility) Shareable() bool {
+	return v == ArticleVisibilityUnlisted || v == ArticleVisibilityPrivate
+}
+
+// ArticleCursor 按照 utime、id 倒序翻页的游标，是上一页最后一篇文章的。
+// 零值代表第一页
+type ArticleCursor struct {
+	Utime time.Time
+	Id    int64
+}
+
+func (c ArticleCursor) IsZero() bool {
+	return c.Id == 0
+}
+
 type Author struct {
 	Id   int64
 	Name string
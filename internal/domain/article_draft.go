@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// ArticleDraft 自动保存的草稿，先放在 Redis 里面，定时刷到数据库
+type ArticleDraft struct {
+	Id      int64
+	Author  Author
+	Title   string
+	Content string
+	// Attachments 和 Article.Attachments 一样，刷到数据库的时候一起写进去
+	Attachments []string
+	// Version 和 Article.Version 是同一个序列，每自动保存一次加一
+	Version int64
+	// Base 开始自动保存的时候数据库里面的版本号，刷到数据库的时候用来确认期间没有别人改过
+	Base  int64
+	Utime time.Time
+}
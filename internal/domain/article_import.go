@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// ArticleBundleFormat 批量导入、导出文章的格式
+type ArticleBundleFormat uint8
+
+const (
+	// ArticleBundleMarkdown zip 压缩包，一篇文章一个 Markdown 文件，
+	// 标题、标签、发表时间放在文件开头的 front matter 里面
+	ArticleBundleMarkdown ArticleBundleFormat = iota
+	// ArticleBundleJSON 一个 JSON 文件，字段和 front matter 一样，多了 content
+	ArticleBundleJSON
+)
+
+type ArticleImportStatus uint8
+
+const (
+	// ArticleImportStatusUnknown 没有导入过，或者导入的结果已经过期了
+	ArticleImportStatusUnknown ArticleImportStatus = iota
+	// ArticleImportStatusRunning 正在导入
+	ArticleImportStatusRunning
+	// ArticleImportStatusDone 全部处理完了，有没有失败的要看 Errors
+	ArticleImportStatusDone
+	// ArticleImportStatusFailed 超时了，没有处理完
+	ArticleImportStatusFailed
+)
+
+// ArticleImport 作者批量导入文章的任务，每个作者同一时间只保留最近一次
+type ArticleImport struct {
+	Uid    int64
+	Status ArticleImportStatus
+	// Total 一共有多少篇，Processed 已经处理了多少篇，包括失败的
+	Total     int
+	Processed int
+	// Ids 导入成功的文章，都是草稿
+	Ids    []int64
+	Errors []ArticleImportError
+	Ctime  time.Time
+	Utime  time.Time
+}
+
+// ArticleImportError 导入失败的一篇文章
+type ArticleImportError struct {
+	// File Markdown 格式是压缩包里面的文件名，JSON 格式是 articles[i]
+	File   string
+	Reason string
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// ArticleReview 文章某一个版本的审核记录。
+// 机审能下结论的直接是通过或者拒绝，拿不准的是待审核，进管理员的队列
+type ArticleReview struct {
+	Id       int64
+	ArtId    int64
+	Revision int64
+	Author   Author
+	Title    string
+	// Content 审核的那个版本的内容，只有查单条的时候才有
+	Content string
+	Status  ArticleReviewStatus
+	// Reason 拒绝的原因，或者机审转人工的原因
+	Reason string
+	// Reviewer 处理的管理员，机审是 0
+	Reviewer int64
+	Ctime    time.Time
+	Utime    time.Time
+}
+
+type ArticleReviewStatus uint8
+
+func (s ArticleReviewStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+const (
+	ArticleReviewStatusUnknown ArticleReviewStatus = iota
+	// ArticleReviewStatusPending 等管理员审核
+	ArticleReviewStatusPending
+	ArticleReviewStatusApproved
+	ArticleReviewStatusRejected
+	// ArticleReviewStatusOutdated 还没审完作者就改了文章或者撤回了，这个版本不用再审了
+	ArticleReviewStatusOutdated
+)
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"ddd_demo/pkg/diffx"
+	"time"
+)
+
+// ArticleRevision 文章的一个历史版本，每次保存或者发表都会产生一个
+type ArticleRevision struct {
+	ArticleId int64
+	Version   int64
+	Title     string
+	Content   string
+	Hash      string
+	Ctime     time.Time
+}
+
+// ArticleRevisionDiff 两个版本之间按行的差异
+type ArticleRevisionDiff struct {
+	From    ArticleRevision
+	To      ArticleRevision
+	Title   []diffx.Line
+	Content []diffx.Line
+}
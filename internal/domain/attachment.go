@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// Attachment 文章里面用到的图片和附件，按照内容寻址，同样的文件只存一份
+type Attachment struct {
+	Id int64
+	// Key 处理过之后的内容的 SHA256，也是对象存储上的 key
+	Key string
+	// Uid 第一个上传的人
+	Uid      int64
+	Name     string
+	MimeType string
+	Size     int64
+	// Width、Height 图片才有
+	Width  int
+	Height int
+	// ThumbMimeType 缩略图的类型，空的就是没有缩略图
+	ThumbMimeType string
+	Ctime         time.Time
+	Utime         time.Time
+}
+
+// ThumbKey 缩略图在对象存储上的 key
+func (a Attachment) ThumbKey() string {
+	return a.Key + "_thumb"
+}
+
+func (a Attachment) HasThumb() bool {
+	return a.ThumbMimeType != ""
+}
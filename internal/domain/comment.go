@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+// Comment 评论，只有两层：顶级评论和顶级评论下面的回复。
+// 回复的回复也挂在同一个顶级评论下面，用 ParentId 记录回复的是哪一条
+type Comment struct {
+	Id    int64
+	Biz   string
+	BizId int64
+	// Uid 评论的人
+	Uid     int64
+	Content string
+	// RootId 顶级评论的 ID，顶级评论自己是 0
+	RootId int64
+	// ParentId 回复的那一条评论，顶级评论是 0
+	ParentId int64
+	LikeCnt  int64
+	// ReplyCnt 顶级评论下面有多少条回复，回复自己是 0
+	ReplyCnt int64
+	// Liked 当前用户有没有点赞
+	Liked bool
+	Ctime time.Time
+	Utime time.Time
+}
+
+func (c Comment) IsRoot() bool {
+	return c.RootId == 0
+}
+
+// CommentSort 评论列表的排序方式
+type CommentSort uint8
+
+const (
+	// CommentSortByTime 最新的在前面
+	CommentSortByTime CommentSort = iota
+	// CommentSortByLikes 点赞多的在前面，点赞一样的最新的在前面
+	CommentSortByLikes
+)
+
+// CommentCursor 评论列表的游标，就是上一页最后一条评论的 Id 和 LikeCnt，
+// 第一页传零值。按照时间排序的时候只用 Id
+type CommentCursor struct {
+	Id      int64
+	LikeCnt int64
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// FollowRelation Follower 关注了 Followee
+type FollowRelation struct {
+	// 同时也是分页的游标
+	Id       int64
+	Follower int64
+	Followee int64
+	Ctime    time.Time
+}
+
+// FollowStatics 关注数和粉丝数
+type FollowStatics struct {
+	// 粉丝数
+	Followers int64
+	// 关注了多少人
+	Followees int64
+}
@@ -1,7 +1,11 @@
 package domain
 
+import "time"
+
 type HistoryRecord struct {
 	BizId int64
 	Biz   string
 	Uid   int64
+	// 最近一次浏览的时间
+	Utime time.Time
 }
@@ -0,0 +1,21 @@
+package domain
+
+// OAuth2Info 第三方登录拿到的用户信息
+type OAuth2Info struct {
+	// 第三方的名字，比如说 github
+	Provider string
+	// 用户在第三方那里的唯一标识
+	Subject string
+	Email   string
+	// 第三方确认过邮箱是用户的
+	EmailVerified bool
+	Nickname      string
+}
+
+// OAuth2Identity 用户绑定的第三方账号
+type OAuth2Identity struct {
+	Uid      int64
+	Provider string
+	Subject  string
+	Email    string
+}
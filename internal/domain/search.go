@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// ArticleSearchResult 一页搜索结果，Total 是命中的总数，分页用
+type ArticleSearchResult struct {
+	Total int
+	Hits  []ArticleSearchHit
+}
+
+type ArticleSearchHit struct {
+	Id     int64
+	Author Author
+	Utime  time.Time
+	// Title 和 Snippet 都已经做过 HTML 转义，命中的地方用 <em></em> 包起来
+	Title string
+	// Snippet 内容里面命中的那一段
+	Snippet string
+}
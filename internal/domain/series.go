@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// Series 专栏，作者把几篇文章按顺序组织起来，比如分成好几篇的教程
+type Series struct {
+	Id          int64
+	Author      Author
+	Title       string
+	Description string
+	// Articles 按照目录顺序排好的文章，只有查目录的时候才有，也只有标题、摘要这些元数据
+	Articles []Article
+	Ctime    time.Time
+	Utime    time.Time
+}
+
+// SeriesNav 文章在专栏里面的位置，看文章的时候跳到上一篇、下一篇用
+type SeriesNav struct {
+	// Series 文章所在的专栏，不带 Articles
+	Series Series
+	// Prev 上一篇，Id 是 0 就是没有
+	Prev Article
+	// Next 下一篇，Id 是 0 就是没有
+	Next Article
+}
@@ -0,0 +1,7 @@
+package domain
+
+// TagCount 标签下面有多少篇已经发表的文章
+type TagCount struct {
+	Tag   string
+	Count int64
+}
@@ -0,0 +1,13 @@
+package domain
+
+// TOTP 用户的二次验证配置
+type TOTP struct {
+	Uid    int64
+	Secret string
+	// 绑定之后要验证一次验证码才会启用
+	Enabled bool
+	// 最后一次使用的验证码对应的时间周期，同一个验证码不能用两次
+	LastCounter int64
+	// 恢复码，和密码一样只保存 hash
+	RecoveryCodes []string
+}
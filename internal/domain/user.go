@@ -6,6 +6,8 @@ type User struct {
 	Id       int64
 	Email    string
 	Password string
+	// 邮箱是否验证过了
+	EmailVerified bool
 
 	Nickname string
 	// YYYY-MM-DD
@@ -22,6 +24,31 @@ type User struct {
 	//Addr Address
 }
 
+// IdentityType 可以用来登录的身份
+type IdentityType string
+
+const (
+	IdentityPhone  IdentityType = "phone"
+	IdentityEmail  IdentityType = "email"
+	IdentityWechat IdentityType = "wechat"
+)
+
+// Identities 已经绑定的、可以用来登录的身份
+func (u User) Identities() []IdentityType {
+	res := make([]IdentityType, 0, 3)
+	if u.Phone != "" {
+		res = append(res, IdentityPhone)
+	}
+	// 只有邮箱没有密码，是登录不了的
+	if u.Email != "" && u.Password != "" {
+		res = append(res, IdentityEmail)
+	}
+	if u.WechatInfo.OpenId != "" {
+		res = append(res, IdentityWechat)
+	}
+	return res
+}
+
 // TodayIsBirthday 判定今天是不是我的生日
 func (u User) TodayIsBirthday() bool {
 	now := time.Now()
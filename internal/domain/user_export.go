@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+type UserExportStatus uint8
+
+const (
+	// UserExportStatusUnknown 没有导出过，或者导出的文件已经过期了
+	UserExportStatusUnknown UserExportStatus = iota
+	// UserExportStatusRunning 正在打包
+	UserExportStatusRunning
+	// UserExportStatusDone 打包好了，可以下载
+	UserExportStatusDone
+	// UserExportStatusFailed 打包失败了，可以重新导出
+	UserExportStatusFailed
+)
+
+// UserExport 用户导出个人数据的任务，每个用户同一时间只保留最近一次
+type UserExport struct {
+	Uid    int64
+	Status UserExportStatus
+	// 打包好的文件在存储上的 key
+	File  string
+	Ctime time.Time
+}
@@ -8,12 +8,58 @@ const (
 	UserInvalidOrPassword = 401002
 	// UserDuplicateEmail 用户邮箱冲突
 	UserDuplicateEmail = 401003
+	// UserInvalidToken 重置密码、验证邮箱的链接无效或者过期了
+	UserInvalidToken = 401004
+	// UserTooManyRequests 操作太频繁
+	UserTooManyRequests = 401005
+	// UserIdentityBound 手机号、邮箱、微信已经被别的账号绑定了
+	UserIdentityBound = 401006
+	// UserLastIdentity 不能解绑最后一种登录方式
+	UserLastIdentity = 401007
+	// UserForbidden 没有权限
+	UserForbidden = 401008
+	// UserAccountLocked 密码错误次数太多，账号被锁定了
+	UserAccountLocked = 401009
+	// UserInvalidMFACode 二次验证的验证码不对
+	UserInvalidMFACode = 401010
 	// UserInternalServerError 统一的用户模块的系统错误
 	UserInternalServerError = 501001
 )
 
 const (
 	// ArticleInvalidInput 文章模块的统一的错误码
-	ArticleInvalidInput        = 402001
+	ArticleInvalidInput = 402001
+	// ArticleForbidden 没有权限查看或者分享这篇文章，分享链接无效或者过期了也是这个
+	ArticleForbidden = 402002
+	// ArticleReviewConflict 审核记录已经被别的管理员处理了，或者审核期间作者改了文章
+	ArticleReviewConflict = 402003
+	// ArticleVersionConflict 文章已经在别的标签页或者设备上保存过了，返回的数据是服务端现在的版本
+	ArticleVersionConflict = 402004
+	// ArticleImportRunning 上一次批量导入还没有结束
+	ArticleImportRunning       = 402005
 	ArticleInternalServerError = 502001
 )
+
+const (
+	// CommentInvalidInput 评论模块的统一的输入错误
+	CommentInvalidInput = 403001
+	// CommentForbidden 删除别人的评论
+	CommentForbidden           = 403002
+	CommentInternalServerError = 503001
+)
+
+const (
+	// AttachmentInvalidInput 附件模块的统一的输入错误，类型不支持或者文件坏了
+	AttachmentInvalidInput = 404001
+	// AttachmentTooLarge 文件太大
+	AttachmentTooLarge            = 404002
+	AttachmentInternalServerError = 504001
+)
+
+const (
+	// SeriesInvalidInput 专栏模块的统一的输入错误
+	SeriesInvalidInput = 405001
+	// SeriesArticleConflict 文章已经在别的专栏里面了
+	SeriesArticleConflict     = 405002
+	SeriesInternalServerError = 505001
+)
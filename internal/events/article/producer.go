@@ -3,12 +3,24 @@ package article
 import (
 	"encoding/json"
 	"github.com/IBM/sarama"
+	"strconv"
 )
 
-const TopicReadEvent = "article_read"
+const (
+	TopicReadEvent    = "article_read"
+	TopicPublishEvent = "article_publish"
+	// TopicReviewEvent 文章提交发表了，等着机审
+	TopicReviewEvent = "article_review"
+	// TopicReviewedEvent 审核有结果了
+	TopicReviewedEvent = "article_reviewed"
+)
 
+//go:generate mockgen -source=./producer.go -package=evtmocks -destination=./mocks/producer.mock.go Producer
 type Producer interface {
 	ProduceReadEvent(evt ReadEvent) error
+	ProducePublishEvent(evt PublishEvent) error
+	ProduceReviewEvent(evt ReviewEvent) error
+	ProduceReviewedEvent(evt ReviewedEvent) error
 }
 
 type ReadEvent struct {
@@ -16,6 +28,28 @@ type ReadEvent struct {
 	Uid int64
 }
 
+// PublishEvent 文章发表或者撤回了。
+// 下游按照 Aid 去线上库读最新的状态，所以重复消费、乱序消费都没有关系
+type PublishEvent struct {
+	Aid int64
+	Uid int64
+}
+
+// ReviewEvent 文章提交发表了，机审按照 Aid 去制作库读最新的内容。
+// 作者在这期间又改了的话，文章已经不是审核中的状态，直接跳过
+type ReviewEvent struct {
+	Aid int64
+	Uid int64
+}
+
+// ReviewedEvent 审核有结果了，下游用来通知作者。Rid 是审核记录的 ID
+type ReviewedEvent struct {
+	Rid      int64
+	Aid      int64
+	Uid      int64
+	Approved bool
+}
+
 type BatchReadEvent struct {
 	Aids []int64
 	Uids []int64
@@ -40,3 +74,29 @@ func (s *SaramaSyncProducer) ProduceReadEvent(evt ReadEvent) error {
 	})
 	return err
 }
+
+func (s *SaramaSyncProducer) ProducePublishEvent(evt PublishEvent) error {
+	return s.produceByAid(TopicPublishEvent, evt.Aid, evt)
+}
+
+func (s *SaramaSyncProducer) ProduceReviewEvent(evt ReviewEvent) error {
+	return s.produceByAid(TopicReviewEvent, evt.Aid, evt)
+}
+
+func (s *SaramaSyncProducer) ProduceReviewedEvent(evt ReviewedEvent) error {
+	return s.produceByAid(TopicReviewedEvent, evt.Aid, evt)
+}
+
+func (s *SaramaSyncProducer) produceByAid(topic string, aid int64, evt any) error {
+	val, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	_, _, err = s.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: topic,
+		// 同一篇文章的消息落在同一个分区上
+		Key:   sarama.StringEncoder(strconv.FormatInt(aid, 10)),
+		Value: sarama.StringEncoder(val),
+	})
+	return err
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./producer.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./producer.go -package=evtmocks -destination=./mocks/producer.mock.go Producer
+//
+// Package evtmocks is a generated GoMock package.
+package evtmocks
+
+import (
+	reflect "reflect"
+
+	comment "ddd_demo/internal/events/comment"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProducer is a mock of Producer interface.
+type MockProducer struct {
+	ctrl     *gomock.Controller
+	recorder *MockProducerMockRecorder
+}
+
+// MockProducerMockRecorder is the mock recorder for MockProducer.
+type MockProducerMockRecorder struct {
+	mock *MockProducer
+}
+
+// NewMockProducer creates a new mock instance.
+func NewMockProducer(ctrl *gomock.Controller) *MockProducer {
+	mock := &MockProducer{ctrl: ctrl}
+	mock.recorder = &MockProducerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProducer) EXPECT() *MockProducerMockRecorder {
+	return m.recorder
+}
+
+// ProduceCreatedEvent mocks base method.
+func (m *MockProducer) ProduceCreatedEvent(evt comment.CreatedEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProduceCreatedEvent", evt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProduceCreatedEvent indicates an expected call of ProduceCreatedEvent.
+func (mr *MockProducerMockRecorder) ProduceCreatedEvent(evt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProduceCreatedEvent", reflect.TypeOf((*MockProducer)(nil).ProduceCreatedEvent), evt)
+}
+
+// ProduceDeletedEvent mocks base method.
+func (m *MockProducer) ProduceDeletedEvent(evt comment.DeletedEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProduceDeletedEvent", evt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProduceDeletedEvent indicates an expected call of ProduceDeletedEvent.
+func (mr *MockProducerMockRecorder) ProduceDeletedEvent(evt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProduceDeletedEvent", reflect.TypeOf((*MockProducer)(nil).ProduceDeletedEvent), evt)
+}
@@ -0,0 +1,70 @@
+package comment
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/sarama"
+)
+
+const (
+	TopicCommentCreated = "comment_created"
+	TopicCommentDeleted = "comment_deleted"
+)
+
+//go:generate mockgen -source=./producer.go -package=evtmocks -destination=./mocks/producer.mock.go Producer
+type Producer interface {
+	ProduceCreatedEvent(evt CreatedEvent) error
+	ProduceDeletedEvent(evt DeletedEvent) error
+}
+
+// CreatedEvent 有人发表了评论，下游用来算评论数、发通知
+type CreatedEvent struct {
+	Id    int64
+	Biz   string
+	BizId int64
+	// Uid 评论的人
+	Uid      int64
+	RootId   int64
+	ParentId int64
+	// ParentUid 被回复的人，顶级评论是 0
+	ParentUid int64
+	Ctime     int64
+}
+
+// DeletedEvent 评论被删掉了，Cnt 是连着回复一共删掉了几条
+type DeletedEvent struct {
+	Id    int64
+	Biz   string
+	BizId int64
+	Cnt   int64
+}
+
+type SaramaSyncProducer struct {
+	producer sarama.SyncProducer
+}
+
+func NewSaramaSyncProducer(producer sarama.SyncProducer) Producer {
+	return &SaramaSyncProducer{producer: producer}
+}
+
+func (s *SaramaSyncProducer) ProduceCreatedEvent(evt CreatedEvent) error {
+	return s.produce(TopicCommentCreated, evt.Biz, evt.BizId, evt)
+}
+
+func (s *SaramaSyncProducer) ProduceDeletedEvent(evt DeletedEvent) error {
+	return s.produce(TopicCommentDeleted, evt.Biz, evt.BizId, evt)
+}
+
+func (s *SaramaSyncProducer) produce(topic string, biz string, bizId int64, evt any) error {
+	val, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	_, _, err = s.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: topic,
+		// 同一个资源的评论落在同一个分区上
+		Key:   sarama.StringEncoder(fmt.Sprintf("%s:%d", biz, bizId)),
+		Value: sarama.StringEncoder(val),
+	})
+	return err
+}
@@ -0,0 +1,42 @@
+package follow
+
+import (
+	"encoding/json"
+	"github.com/IBM/sarama"
+	"strconv"
+)
+
+const TopicFollowEvent = "user_follow"
+
+type Producer interface {
+	ProduceFollowEvent(evt FollowEvent) error
+}
+
+// FollowEvent Follow 为 true 是 Follower 关注了 Followee，false 是取消关注
+type FollowEvent struct {
+	Follower int64
+	Followee int64
+	Follow   bool
+}
+
+type SaramaSyncProducer struct {
+	producer sarama.SyncProducer
+}
+
+func NewSaramaSyncProducer(producer sarama.SyncProducer) Producer {
+	return &SaramaSyncProducer{producer: producer}
+}
+
+func (s *SaramaSyncProducer) ProduceFollowEvent(evt FollowEvent) error {
+	val, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	_, _, err = s.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: TopicFollowEvent,
+		// 同一个人的关注、取消关注要保证顺序
+		Key:   sarama.StringEncoder(strconv.FormatInt(evt.Follower, 10)),
+		Value: sarama.StringEncoder(val),
+	})
+	return err
+}
@@ -0,0 +1,51 @@
+package review
+
+import (
+	"context"
+	"ddd_demo/internal/events/article"
+	"ddd_demo/internal/service"
+	"ddd_demo/pkg/logger"
+	"ddd_demo/pkg/samarax"
+	"github.com/IBM/sarama"
+	"time"
+)
+
+// Consumer 文章提交发表之后做机审，审核有结果了通知作者
+type Consumer struct {
+	svc    service.ArticleReviewService
+	client sarama.Client
+	l      logger.LoggerV1
+}
+
+func NewConsumer(svc service.ArticleReviewService, client sarama.Client, l logger.LoggerV1) *Consumer {
+	return &Consumer{svc: svc, client: client, l: l}
+}
+
+func (c *Consumer) Start() error {
+	reviewCg, err := sarama.NewConsumerGroupFromClient("article_review", c.client)
+	if err != nil {
+		return err
+	}
+	notifyCg, err := sarama.NewConsumerGroupFromClient("article_review_notify", c.client)
+	if err != nil {
+		return err
+	}
+	go samarax.ConsumeLoop(reviewCg, []string{article.TopicReviewEvent},
+		samarax.NewRetryHandler[article.ReviewEvent](c.l, c.ConsumeReview), c.l)
+	go samarax.ConsumeLoop(notifyCg, []string{article.TopicReviewedEvent},
+		samarax.NewRetryHandler[article.ReviewedEvent](c.l, c.ConsumeReviewed), c.l)
+	return nil
+}
+
+func (c *Consumer) ConsumeReview(msg *sarama.ConsumerMessage, evt article.ReviewEvent) error {
+	// 审核通过的时候还要渲染、写线上库
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	return c.svc.Review(ctx, evt.Aid)
+}
+
+func (c *Consumer) ConsumeReviewed(msg *sarama.ConsumerMessage, evt article.ReviewedEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	return c.svc.NotifyAuthor(ctx, evt.Rid)
+}
@@ -0,0 +1,79 @@
+package search
+
+import (
+	"context"
+	"ddd_demo/internal/events/article"
+	"ddd_demo/internal/events/user"
+	"ddd_demo/internal/service"
+	"ddd_demo/pkg/logger"
+	"ddd_demo/pkg/samarax"
+	"github.com/IBM/sarama"
+	"time"
+)
+
+// IndexConsumer 文章发表、撤回和用户注销、合并的时候更新搜索索引
+type IndexConsumer struct {
+	svc    service.SearchService
+	client sarama.Client
+	// node 本地索引每个节点各有一份，每个节点都要用自己的消费者组把所有消息消费一遍。
+	// 换成 Elasticsearch 这种共享的索引之后，传同一个值就可以了
+	node string
+	l    logger.LoggerV1
+}
+
+func NewIndexConsumer(svc service.SearchService, client sarama.Client,
+	node string, l logger.LoggerV1) *IndexConsumer {
+	return &IndexConsumer{svc: svc, client: client, node: node, l: l}
+}
+
+func (c *IndexConsumer) Start() error {
+	artCg, err := sarama.NewConsumerGroupFromClient("search_index_article_"+c.node, c.client)
+	if err != nil {
+		return err
+	}
+	deletedCg, err := sarama.NewConsumerGroupFromClient("search_index_user_deleted_"+c.node, c.client)
+	if err != nil {
+		return err
+	}
+	mergedCg, err := sarama.NewConsumerGroupFromClient("search_index_user_merged_"+c.node, c.client)
+	if err != nil {
+		return err
+	}
+	go samarax.ConsumeLoop(artCg, []string{article.TopicPublishEvent},
+		samarax.NewRetryHandler[article.PublishEvent](c.l, c.ConsumePublish), c.l)
+	go samarax.ConsumeLoop(deletedCg, []string{user.TopicUserDeleted},
+		samarax.NewRetryHandler[user.DeletedEvent](c.l, c.ConsumeDeleted), c.l)
+	go samarax.ConsumeLoop(mergedCg, []string{user.TopicUserMerged},
+		samarax.NewRetryHandler[user.MergedEvent](c.l, c.ConsumeMerged), c.l)
+	// 先开始消费再重建，重建期间发生的变更由消息补上
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute*10)
+		defer cancel()
+		err := c.svc.Rebuild(ctx)
+		if err != nil {
+			c.l.Error("重建搜索索引失败", logger.Error(err))
+		}
+	}()
+	return nil
+}
+
+func (c *IndexConsumer) ConsumePublish(msg *sarama.ConsumerMessage,
+	evt article.PublishEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return c.svc.IndexArticle(ctx, evt.Aid)
+}
+
+func (c *IndexConsumer) ConsumeDeleted(msg *sarama.ConsumerMessage,
+	evt user.DeletedEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return c.svc.RemoveAuthor(ctx, evt.Uid)
+}
+
+func (c *IndexConsumer) ConsumeMerged(msg *sarama.ConsumerMessage,
+	evt user.MergedEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	return c.svc.TransferAuthor(ctx, evt.From, evt.To)
+}
@@ -0,0 +1,60 @@
+package user
+
+import (
+	"encoding/json"
+	"github.com/IBM/sarama"
+)
+
+const (
+	TopicUserMerged  = "user_merged"
+	TopicUserDeleted = "user_deleted"
+)
+
+type Producer interface {
+	ProduceMergedEvent(evt MergedEvent) error
+	ProduceDeletedEvent(evt DeletedEvent) error
+}
+
+// MergedEvent From 已经被合并到 To 上了，
+// 各个业务需要把 From 名下的数据转给 To
+type MergedEvent struct {
+	From int64
+	To   int64
+}
+
+// DeletedEvent 用户注销了，各个业务需要删除 Uid 名下的数据
+type DeletedEvent struct {
+	Uid int64
+}
+
+type SaramaSyncProducer struct {
+	producer sarama.SyncProducer
+}
+
+func NewSaramaSyncProducer(producer sarama.SyncProducer) Producer {
+	return &SaramaSyncProducer{producer: producer}
+}
+
+func (s *SaramaSyncProducer) ProduceMergedEvent(evt MergedEvent) error {
+	val, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	_, _, err = s.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: TopicUserMerged,
+		Value: sarama.StringEncoder(val),
+	})
+	return err
+}
+
+func (s *SaramaSyncProducer) ProduceDeletedEvent(evt DeletedEvent) error {
+	val, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	_, _, err = s.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: TopicUserDeleted,
+		Value: sarama.StringEncoder(val),
+	})
+	return err
+}
@@ -68,6 +68,10 @@ func (s *ArticleHandlerSuite) TestArticle_Publish() {
 				s.db.Where("author_id = ?", 123).First(&publishedArt)
 				assert.Equal(t, "hello，你好", publishedArt.Title)
 				assert.Equal(t, "随便试试", publishedArt.Content)
+				// 线上库里面有渲染好的 HTML 和摘要，制作库没有
+				assert.Equal(t, "<p>随便试试</p>\n", publishedArt.Html)
+				assert.Equal(t, "随便试试", publishedArt.Abstract)
+				assert.Equal(t, "", art.Html)
 				assert.Equal(t, int64(123), publishedArt.AuthorId)
 				assert.Equal(t, uint8(2), publishedArt.Status)
 				assert.True(t, publishedArt.Ctime > 0)
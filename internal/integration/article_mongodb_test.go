@@ -5,8 +5,10 @@ import (
 	"context"
 	"ddd_demo/internal/integration/startup"
 	"ddd_demo/internal/repository/dao"
+	"ddd_demo/internal/web"
 	ijwt "ddd_demo/internal/web/jwt"
 	"encoding/json"
+	"fmt"
 	"github.com/bwmarrin/snowflake"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -492,6 +494,180 @@ func (s *ArticleMongoDBHandlerSuite) TestEdit() {
 	}
 }
 
+func (s *ArticleMongoDBHandlerSuite) TestWithdraw() {
+	t := s.T()
+	testCases := []struct {
+		name   string
+		before func(t *testing.T)
+		after  func(t *testing.T)
+		id     int64
+
+		wantRes Result[any]
+	}{
+		{
+			name: "撤回自己的文章",
+			before: func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				art := dao.Article{Id: 31, Title: "我的标题", Content: "我的内容",
+					AuthorId: 123, Status: 2, Ctime: 456, Utime: 789, Tags: []string{}}
+				_, err := s.col.InsertOne(ctx, &art)
+				assert.NoError(t, err)
+				_, err = s.liveCol.InsertOne(ctx, &art)
+				assert.NoError(t, err)
+			},
+			after: func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				// 和 GORM 一样，两个库都变成仅自己可见，并且更新 utime
+				for _, col := range []*mongo.Collection{s.col, s.liveCol} {
+					var art dao.Article
+					err := col.FindOne(ctx, bson.D{bson.E{"id", 31}}).Decode(&art)
+					assert.NoError(t, err)
+					assert.Equal(t, uint8(3), art.Status)
+					assert.True(t, art.Utime > 789)
+				}
+			},
+			id:      31,
+			wantRes: Result[any]{Msg: "OK"},
+		},
+		{
+			name: "撤回别人的文章",
+			before: func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				_, err := s.col.InsertOne(ctx, &dao.Article{Id: 32, Title: "我的标题",
+					AuthorId: 1024, Status: 2, Ctime: 456, Utime: 789})
+				assert.NoError(t, err)
+			},
+			after: func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				var art dao.Article
+				err := s.col.FindOne(ctx, bson.D{bson.E{"id", 32}}).Decode(&art)
+				assert.NoError(t, err)
+				assert.Equal(t, uint8(2), art.Status)
+				assert.Equal(t, int64(789), art.Utime)
+			},
+			id:      32,
+			wantRes: Result[any]{Code: 5, Msg: "系统错误"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.before(t)
+			defer tc.after(t)
+			reqBody, err := json.Marshal(map[string]int64{"id": tc.id})
+			assert.NoError(t, err)
+			req, err := http.NewRequest(http.MethodPost,
+				"/articles/withdraw", bytes.NewReader(reqBody))
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+			s.server.ServeHTTP(recorder, req)
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var res Result[any]
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+func (s *ArticleMongoDBHandlerSuite) TestDetail() {
+	t := s.T()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := s.col.InsertOne(ctx, &dao.Article{Id: 41, Title: "我的标题", Content: "我的内容",
+		AuthorId: 123, Status: 1, Revision: 2, Ctime: 456, Utime: 789, Tags: []string{"go"}})
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name    string
+		id      int64
+		wantRes Result[web.ArticleVo]
+	}{
+		{
+			name: "查询自己的文章",
+			id:   41,
+			wantRes: Result[web.ArticleVo]{Data: web.ArticleVo{
+				Id:       41,
+				Title:    "我的标题",
+				Content:  "我的内容",
+				AuthorId: 123,
+				Status:   1,
+				Revision: 2,
+				Tags:     []string{"go"},
+				Ctime:    time.UnixMilli(456).Format(time.DateTime),
+				Utime:    time.UnixMilli(789).Format(time.DateTime),
+			}},
+		},
+		{
+			// 和 GORM 一样返回找不到，而不是 MongoDB 自己的错误
+			name:    "文章不存在",
+			id:      42,
+			wantRes: Result[web.ArticleVo]{Code: 5, Msg: "系统错误"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet,
+				fmt.Sprintf("/articles/detail/%d", tc.id), nil)
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			s.server.ServeHTTP(recorder, req)
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var res Result[web.ArticleVo]
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+// TestList 同一个 utime 的文章按照 id 倒序，翻页的时候不会重复也不会漏掉
+func (s *ArticleMongoDBHandlerSuite) TestList() {
+	t := s.T()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for _, art := range []dao.Article{
+		{Id: 51, Title: "第一篇", AuthorId: 123, Status: 1, Utime: 1000},
+		{Id: 52, Title: "第二篇", AuthorId: 123, Status: 1, Utime: 2000},
+		{Id: 53, Title: "第三篇", AuthorId: 123, Status: 1, Utime: 2000},
+		{Id: 54, Title: "别人的", AuthorId: 1024, Status: 1, Utime: 3000},
+	} {
+		_, err := s.col.InsertOne(ctx, &art)
+		assert.NoError(t, err)
+	}
+
+	var (
+		ids    []int64
+		cursor string
+	)
+	for i := 0; i < 3; i++ {
+		reqBody, err := json.Marshal(web.CursorPage{Limit: 2, Cursor: cursor})
+		assert.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost,
+			"/articles/list", bytes.NewReader(reqBody))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		s.server.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var res Result[web.ArticleListVo]
+		err = json.NewDecoder(recorder.Body).Decode(&res)
+		assert.NoError(t, err)
+		for _, art := range res.Data.Articles {
+			ids = append(ids, art.Id)
+		}
+		cursor = res.Data.Cursor
+		if cursor == "" {
+			break
+		}
+	}
+	assert.Equal(t, []int64{53, 52, 51}, ids)
+}
+
 func (s *ArticleMongoDBHandlerSuite) TearDownTest() {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
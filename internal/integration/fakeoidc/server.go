@@ -0,0 +1,204 @@
+// Package fakeoidc 一个本地的 OIDC 服务器，测试第三方登录用。
+// 访问授权地址的时候不需要登录，直接带着授权码跳回去，登录的用户是 Server.User
+package fakeoidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"ddd_demo/pkg/jwtx"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/golang-jwt/jwt/v5"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const kid = "fake-oidc"
+
+type User struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type Server struct {
+	*httptest.Server
+	ClientID     string
+	ClientSecret string
+
+	mutex sync.Mutex
+	// 下一次登录的用户
+	user   User
+	codes  map[string]grant
+	tokens map[string]User
+	key    *rsa.PrivateKey
+}
+
+type grant struct {
+	redirectURI string
+	user        User
+}
+
+func NewServer(clientID, clientSecret string) *Server {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	s := &Server{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		user: User{
+			Subject:       "fake-user",
+			Email:         "fake@example.com",
+			EmailVerified: true,
+			Name:          "fake",
+		},
+		codes:  map[string]grant{},
+		tokens: map[string]User{},
+		key:    key,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/authorize", s.authorize)
+	mux.HandleFunc("/token", s.token)
+	mux.HandleFunc("/userinfo", s.userinfo)
+	mux.HandleFunc("/jwks", s.jwks)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// SetUser 设置下一次登录的用户
+func (s *Server) SetUser(u User) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.user = u
+}
+
+func (s *Server) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 s.URL,
+		"authorization_endpoint": s.URL + "/authorize",
+		"token_endpoint":         s.URL + "/token",
+		"userinfo_endpoint":      s.URL + "/userinfo",
+		"jwks_uri":               s.URL + "/jwks",
+	})
+}
+
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != s.ClientID || query.Get("response_type") != "code" {
+		writeError(w, "unauthorized_client")
+		return
+	}
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirectURI.Scheme == "" {
+		writeError(w, "invalid_request")
+		return
+	}
+	code := randomString()
+	s.mutex.Lock()
+	s.codes[code] = grant{redirectURI: redirectURI.String(), user: s.user}
+	s.mutex.Unlock()
+
+	params := redirectURI.Query()
+	params.Set("code", code)
+	params.Set("state", query.Get("state"))
+	redirectURI.RawQuery = params.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil {
+		writeError(w, "invalid_request")
+		return
+	}
+	if r.PostForm.Get("client_id") != s.ClientID ||
+		r.PostForm.Get("client_secret") != s.ClientSecret {
+		writeError(w, "invalid_client")
+		return
+	}
+	s.mutex.Lock()
+	code := r.PostForm.Get("code")
+	g, ok := s.codes[code]
+	// 授权码只能用一次
+	delete(s.codes, code)
+	s.mutex.Unlock()
+	if !ok || g.redirectURI != r.PostForm.Get("redirect_uri") {
+		writeError(w, "invalid_grant")
+		return
+	}
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            s.URL,
+		"sub":            g.user.Subject,
+		"aud":            s.ClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Minute * 5).Unix(),
+		"email":          g.user.Email,
+		"email_verified": g.user.EmailVerified,
+		"name":           g.user.Name,
+	})
+	token.Header["kid"] = kid
+	idToken, err := token.SignedString(s.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	accessToken := randomString()
+	s.mutex.Lock()
+	s.tokens[accessToken] = g.user
+	s.mutex.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func (s *Server) userinfo(w http.ResponseWriter, r *http.Request) {
+	accessToken := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	s.mutex.Lock()
+	u, ok := s.tokens[accessToken]
+	s.mutex.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"sub":            u.Subject,
+		"email":          u.Email,
+		"email_verified": u.EmailVerified,
+		"name":           u.Name,
+	})
+}
+
+func (s *Server) jwks(w http.ResponseWriter, r *http.Request) {
+	jwk, err := jwtx.NewJWK(kid, jwt.SigningMethodRS256.Alg(), &s.key.PublicKey)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, jwtx.JWKSet{Keys: []jwtx.JWK{jwk}})
+}
+
+func writeError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, val any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(val)
+}
+
+func randomString() string {
+	data := make([]byte, 16)
+	_, _ = rand.Read(data)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -0,0 +1,96 @@
+package integration
+
+import (
+	"ddd_demo/internal/integration/fakeoidc"
+	"ddd_demo/internal/integration/startup"
+	"ddd_demo/internal/repository/dao"
+	"ddd_demo/pkg/ginx"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestOAuth2Handler_Login(t *testing.T) {
+	server := startup.InitWebServer()
+	db := startup.InitDB()
+	startup.FakeOIDCServer.SetUser(fakeoidc.User{
+		Subject:       "oauth2-login",
+		Email:         "oauth2@example.com",
+		EmailVerified: true,
+		Name:          "oauth2",
+	})
+	defer func() {
+		var identity dao.UserIdentity
+		err := db.Where("provider = ? AND subject = ?", "fake", "oauth2-login").
+			First(&identity).Error
+		require.NoError(t, err)
+		db.Where("id = ?", identity.Uid).Delete(&dao.User{})
+		db.Where("id = ?", identity.Id).Delete(&dao.UserIdentity{})
+	}()
+
+	var uids []int64
+	// 登录两次，第二次应该还是同一个用户
+	for i := 0; i < 2; i++ {
+		// 拿到跳转的地址，还有 state 的 cookie
+		req, err := http.NewRequest(http.MethodGet, "/oauth2/fake/authurl", nil)
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var res ginx.Result
+		err = json.NewDecoder(recorder.Body).Decode(&res)
+		require.NoError(t, err)
+		cookies := recorder.Result().Cookies()
+		require.Len(t, cookies, 1)
+
+		// 浏览器跳转到第三方，第三方带着授权码跳回来
+		client := &http.Client{
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+		resp, err := client.Get(res.Data.(string))
+		require.NoError(t, err)
+		resp.Body.Close()
+		callback, err := url.Parse(resp.Header.Get("Location"))
+		require.NoError(t, err)
+
+		req, err = http.NewRequest(http.MethodGet,
+			"/oauth2/fake/callback?"+callback.RawQuery, nil)
+		require.NoError(t, err)
+		req.AddCookie(cookies[0])
+		recorder = httptest.NewRecorder()
+		server.ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		err = json.NewDecoder(recorder.Body).Decode(&res)
+		require.NoError(t, err)
+		assert.Equal(t, ginx.Result{Msg: "OK"}, res)
+		assert.NotEmpty(t, recorder.Header().Get("x-jwt-token"))
+
+		var identity dao.UserIdentity
+		err = db.Where("provider = ? AND subject = ?", "fake", "oauth2-login").
+			First(&identity).Error
+		require.NoError(t, err)
+		assert.Equal(t, "oauth2@example.com", identity.Email)
+		uids = append(uids, identity.Uid)
+	}
+	assert.Equal(t, uids[0], uids[1])
+}
+
+func TestOAuth2Handler_Callback_InvalidState(t *testing.T) {
+	server := startup.InitWebServer()
+	req, err := http.NewRequest(http.MethodGet,
+		"/oauth2/fake/callback?code=abc&state=abc", nil)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var res ginx.Result
+	err = json.NewDecoder(recorder.Body).Decode(&res)
+	require.NoError(t, err)
+	assert.Equal(t, "非法请求", res.Msg)
+}
@@ -0,0 +1,7 @@
+package startup
+
+import "ddd_demo/internal/web/middleware"
+
+func InitAdminMiddleware() *middleware.AdminMiddlewareBuilder {
+	return middleware.NewAdminMiddlewareBuilder([]int64{1})
+}
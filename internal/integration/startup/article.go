@@ -0,0 +1,13 @@
+package startup
+
+import (
+	"ddd_demo/internal/service"
+	"time"
+)
+
+func InitArticleImportConfig() service.ArticleImportConfig {
+	return service.ArticleImportConfig{
+		Expiration: time.Hour,
+		Timeout:    time.Minute,
+	}
+}
@@ -0,0 +1,12 @@
+package startup
+
+import (
+	"ddd_demo/pkg/ossx"
+	"os"
+	"path/filepath"
+)
+
+// InitAttachmentObjectStore 测试里面附件放在临时目录
+func InitAttachmentObjectStore() ossx.ObjectStore {
+	return ossx.NewLocalStore(filepath.Join(os.TempDir(), "webook_attachments"))
+}
@@ -0,0 +1,12 @@
+package startup
+
+import (
+	"ddd_demo/internal/service/email"
+	"ddd_demo/internal/service/email/local"
+	"os"
+)
+
+// InitEmailService 测试的时候不真的发邮件，打印出来就可以
+func InitEmailService() email.Service {
+	return local.NewService(os.Stdout)
+}
@@ -0,0 +1,12 @@
+package startup
+
+import ijwt "ddd_demo/internal/web/jwt"
+
+func InitJWTKeys() ijwt.Keys {
+	return ijwt.Keys{
+		Access:  ijwt.NewHMACKeyProvider([]byte("k6CswdUm77WKcbM68UQUuxVsHSpTCwgK")),
+		Refresh: ijwt.NewHMACKeyProvider([]byte("k6CswdUm77WKcbM68UQUuxVsHSpTCwgA")),
+		State:   ijwt.NewHMACKeyProvider([]byte("k6CswdUm77WKcbM68UQUuxVsHSpTCwgB")),
+		Share:   ijwt.NewHMACKeyProvider([]byte("k6CswdUm77WKcbM68UQUuxVsHSpTCwgC")),
+	}
+}
@@ -0,0 +1,39 @@
+package startup
+
+import (
+	"ddd_demo/internal/repository"
+	"ddd_demo/internal/repository/cache"
+	"ddd_demo/internal/service"
+	"ddd_demo/pkg/limiter"
+	"ddd_demo/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+func InitLoginAttemptConfig() cache.LoginAttemptConfig {
+	return cache.LoginAttemptConfig{
+		Window:       time.Minute * 15,
+		DelayAfter:   3,
+		BaseDelay:    time.Second,
+		MaxDelay:     time.Minute,
+		LockAfter:    10,
+		LockDuration: time.Minute * 30,
+		IP: cache.IPAttemptConfig{
+			DelayAfter: 20,
+			BaseDelay:  time.Second,
+			MaxDelay:   time.Minute,
+		},
+	}
+}
+
+// InitLoginGuard 测试里面会多次初始化，所以 counter 不注册到 prometheus
+func InitLoginGuard(repo repository.LoginAttemptRepository,
+	cmd redis.Cmdable, l logger.LoggerV1) service.LoginGuard {
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "login_guard",
+	}, []string{"event"})
+	return service.NewLoginGuard(repo,
+		limiter.NewRedisSlidingWindowLimiter(cmd, time.Minute, 100),
+		l, counter)
+}
@@ -0,0 +1,15 @@
+package startup
+
+import (
+	"ddd_demo/internal/service/moderation"
+	"ddd_demo/internal/service/moderation/local"
+)
+
+// InitModerator 测试的时候没有审核规则，所有文章都直接通过
+func InitModerator() moderation.Moderator {
+	m, err := local.NewModerator(local.Config{})
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
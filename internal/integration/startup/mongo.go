@@ -2,6 +2,7 @@ package startup
 
 import (
 	"context"
+	"ddd_demo/internal/repository/dao"
 	"fmt"
 	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -25,5 +26,10 @@ func InitMongoDB() *mongo.Database {
 	if err != nil {
 		panic(err)
 	}
-	return client.Database("webook")
+	mdb := client.Database("webook")
+	err = dao.InitCollection(mdb)
+	if err != nil {
+		panic(err)
+	}
+	return mdb
 }
@@ -0,0 +1,26 @@
+package startup
+
+import (
+	"ddd_demo/internal/integration/fakeoidc"
+	"ddd_demo/internal/service/oauth2"
+	"ddd_demo/internal/service/oauth2/oidc"
+	"net/http"
+)
+
+// FakeOIDCServer 测试用的 OIDC 服务器，名字是 fake
+var FakeOIDCServer = fakeoidc.NewServer("webook", "webook-secret")
+
+func InitOAuth2Providers() *oauth2.Registry {
+	res, err := oauth2.NewRegistry(oidc.NewProvider(oauth2.Config{
+		Name:         "fake",
+		Type:         "oidc",
+		Issuer:       FakeOIDCServer.URL,
+		ClientID:     FakeOIDCServer.ClientID,
+		ClientSecret: FakeOIDCServer.ClientSecret,
+		RedirectURL:  "http://localhost:8083/oauth2/fake/callback",
+	}, http.DefaultClient))
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
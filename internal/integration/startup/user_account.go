@@ -0,0 +1,21 @@
+package startup
+
+import (
+	"ddd_demo/internal/repository/dao"
+	"ddd_demo/internal/service"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+func InitUserExportConfig() service.UserExportConfig {
+	return service.UserExportConfig{
+		Expiration: time.Hour,
+		Timeout:    time.Minute,
+	}
+}
+
+// InitUserExportFileDAO 测试的时候只有一个节点，放在本地就可以
+func InitUserExportFileDAO() dao.UserExportFileDAO {
+	return dao.NewLocalUserExportFileDAO(filepath.Join(os.TempDir(), "webook_export"))
+}
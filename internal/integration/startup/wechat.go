@@ -6,5 +6,5 @@ import (
 )
 
 func InitWechatService(l logger.LoggerV1) wechat.Service {
-	return wechat.NewService("", "", l)
+	return wechat.NewService("", "", "http://localhost:8083/oauth2/wechat/callback", l)
 }
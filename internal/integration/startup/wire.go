@@ -8,6 +8,9 @@ import (
 	dao2 "ddd_demo/interactive/repository/dao"
 	service2 "ddd_demo/interactive/service"
 	"ddd_demo/internal/events/article"
+	"ddd_demo/internal/events/comment"
+	"ddd_demo/internal/events/follow"
+	"ddd_demo/internal/events/user"
 	"ddd_demo/internal/repository"
 	"ddd_demo/internal/repository/cache"
 	"ddd_demo/internal/repository/dao"
@@ -30,8 +33,15 @@ var thirdPartySet = wire.NewSet( // 第三方依赖
 
 var userSvcProvider = wire.NewSet(
 	dao.NewUserDAO,
+	dao.NewGORMIdentityDAO,
+	dao.NewGORMTOTPDAO,
 	cache.NewUserCache,
+	cache.NewOneTimeTokenCache,
 	repository.NewCachedUserRepository,
+	repository.NewIdentityRepository,
+	repository.NewTOTPRepository,
+	repository.NewOneTimeTokenRepository,
+	InitEmailService,
 	service.NewUserService)
 
 var articlSvcProvider = wire.NewSet(
@@ -40,6 +50,45 @@ var articlSvcProvider = wire.NewSet(
 	dao.NewArticleGORMDAO,
 	service.NewArticleService)
 
+var followRepoProvider = wire.NewSet(
+	dao.NewGORMFollowRelationDAO,
+	cache.NewRedisFollowCache,
+	repository.NewCachedFollowRepository)
+
+var followSvcProvider = wire.NewSet(
+	followRepoProvider,
+	follow.NewSaramaSyncProducer,
+	service.NewFollowService)
+
+var commentSvcProvider = wire.NewSet(
+	dao.NewGORMCommentDAO,
+	repository.NewCommentRepository,
+	comment.NewSaramaSyncProducer,
+	service.NewCommentService)
+
+var attachmentSvcProvider = wire.NewSet(
+	dao.NewGORMAttachmentDAO,
+	repository.NewCachedAttachmentRepository,
+	InitAttachmentObjectStore,
+	service.NewAttachmentService)
+
+var articleReviewSvcProvider = wire.NewSet(
+	dao.NewGORMArticleReviewDAO,
+	repository.NewArticleReviewRepository,
+	InitModerator,
+	service.NewArticleReviewService)
+
+var seriesSvcProvider = wire.NewSet(
+	dao.NewGORMSeriesDAO,
+	repository.NewSeriesRepository,
+	service.NewSeriesService)
+
+var articleBundleSvcProvider = wire.NewSet(
+	cache.NewRedisArticleImportCache,
+	repository.NewArticleImportRepository,
+	InitArticleImportConfig,
+	service.NewArticleBundleService)
+
 var interactiveSvcSet = wire.NewSet(dao2.NewGORMInteractiveDAO,
 	cache2.NewInteractiveRedisCache,
 	repository2.NewCachedInteractiveRepository,
@@ -52,24 +101,57 @@ func InitWebServer() *gin.Engine {
 		thirdPartySet,
 		userSvcProvider,
 		articlSvcProvider,
+		followSvcProvider,
+		commentSvcProvider,
+		attachmentSvcProvider,
+		articleReviewSvcProvider,
+		seriesSvcProvider,
+		articleBundleSvcProvider,
 		interactiveSvcSet,
 		// cache 部分
 		cache.NewCodeCache,
+		InitLoginAttemptConfig,
+		cache.NewLoginAttemptCache,
 
 		// repository 部分
 		repository.NewCodeRepository,
+		repository.NewLoginAttemptRepository,
 
 		article.NewSaramaSyncProducer,
+		user.NewSaramaSyncProducer,
 
 		// Service 部分
 		ioc.InitSMSService,
 		service.NewCodeService,
+		service.NewUserMergeService,
+		dao.NewGORMHistoryRecordDAO,
+		repository.NewHistoryRecordRepository,
+		cache.NewRedisUserExportCache,
+		repository.NewUserExportRepository,
+		InitUserExportConfig,
+		InitUserExportFileDAO,
+		service.NewUserAccountService,
+		ioc.InitSearchIndex,
+		service.NewSearchService,
+		InitLoginGuard,
 		InitWechatService,
+		InitOAuth2Providers,
 
 		// handler 部分
+		InitJWTKeys,
 		web.NewUserHandler,
 		web.NewArticleHandler,
 		web.NewOAuth2WechatHandler,
+		web.NewOAuth2Handler,
+		InitAdminMiddleware,
+		web.NewAdminHandler,
+		web.NewFollowHandler,
+		web.NewUserAccountHandler,
+		web.NewSearchHandler,
+		web.NewCommentHandler,
+		web.NewAttachmentHandler,
+		web.NewSeriesHandler,
+		web.NewArticleBundleHandler,
 		ijwt.NewRedisJWTHandler,
 		ioc.InitGinMiddlewares,
 		ioc.InitWebServer,
@@ -92,8 +174,11 @@ func InitArticleHandler(dao dao.ArticleDAO) *web.ArticleHandler {
 		interactiveSvcSet,
 		repository.NewCachedArticleRepository,
 		cache.NewArticleRedisCache,
+		followRepoProvider,
 		service.NewArticleService,
+		seriesSvcProvider,
 		article.NewSaramaSyncProducer,
+		InitJWTKeys,
 		web.NewArticleHandler)
 	return &web.ArticleHandler{}
 }
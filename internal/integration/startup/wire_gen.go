@@ -12,6 +12,9 @@ import (
 	dao2 "ddd_demo/interactive/repository/dao"
 	service2 "ddd_demo/interactive/service"
 	"ddd_demo/internal/events/article"
+	"ddd_demo/internal/events/comment"
+	"ddd_demo/internal/events/follow"
+	"ddd_demo/internal/events/user"
 	"ddd_demo/internal/repository"
 	"ddd_demo/internal/repository/cache"
 	"ddd_demo/internal/repository/dao"
@@ -29,35 +32,93 @@ import (
 
 func InitWebServer() *gin.Engine {
 	cmdable := InitRedis()
-	handler := jwt.NewRedisJWTHandler(cmdable)
 	loggerV1 := InitLogger()
+	keys := InitJWTKeys()
+	handler := jwt.NewRedisJWTHandler(cmdable, loggerV1, keys)
 	v := ioc.InitGinMiddlewares(cmdable, handler, loggerV1)
 	db := InitDB()
 	userDAO := dao.NewUserDAO(db)
 	userCache := cache.NewUserCache(cmdable)
 	userRepository := repository.NewCachedUserRepository(userDAO, userCache)
-	userService := service.NewUserService(userRepository)
+	identityDAO := dao.NewGORMIdentityDAO(db)
+	identityRepository := repository.NewIdentityRepository(identityDAO)
+	totpdao := dao.NewGORMTOTPDAO(db)
+	totpRepository := repository.NewTOTPRepository(totpdao)
+	oneTimeTokenCache := cache.NewOneTimeTokenCache(cmdable)
+	oneTimeTokenRepository := repository.NewOneTimeTokenRepository(oneTimeTokenCache)
+	emailService := InitEmailService()
+	userService := service.NewUserService(userRepository, identityRepository, totpRepository, oneTimeTokenRepository, emailService)
 	codeCache := cache.NewCodeCache(cmdable)
 	codeRepository := repository.NewCodeRepository(codeCache)
 	smsService := ioc.InitSMSService()
 	codeService := service.NewCodeService(codeRepository, smsService)
-	userHandler := web.NewUserHandler(userService, handler, codeService)
+	loginAttemptConfig := InitLoginAttemptConfig()
+	loginAttemptCache := cache.NewLoginAttemptCache(cmdable, loginAttemptConfig)
+	loginAttemptRepository := repository.NewLoginAttemptRepository(loginAttemptCache)
+	loginGuard := InitLoginGuard(loginAttemptRepository, cmdable, loggerV1)
+	registry := InitOAuth2Providers()
+	userHandler := web.NewUserHandler(userService, handler, codeService, loginGuard, registry)
 	articleDAO := dao.NewArticleGORMDAO(db)
 	articleCache := cache.NewArticleRedisCache(cmdable)
-	articleRepository := repository.NewCachedArticleRepository(articleDAO, userRepository, articleCache)
+	articleRepository := repository.NewCachedArticleRepository(articleDAO, userRepository, articleCache, loggerV1)
+	followRelationDAO := dao.NewGORMFollowRelationDAO(db)
+	followCache := cache.NewRedisFollowCache(cmdable)
+	followRepository := repository.NewCachedFollowRepository(followRelationDAO, followCache, loggerV1)
 	client := InitSaramaClient()
 	syncProducer := InitSyncProducer(client)
 	producer := article.NewSaramaSyncProducer(syncProducer)
-	articleService := service.NewArticleService(articleRepository, producer)
+	articleService := service.NewArticleService(articleRepository, followRepository, producer, loggerV1)
+	seriesDAO := dao.NewGORMSeriesDAO(db)
+	seriesRepository := repository.NewSeriesRepository(seriesDAO)
+	seriesService := service.NewSeriesService(seriesRepository, articleRepository, followRepository)
 	interactiveDAO := dao2.NewGORMInteractiveDAO(db)
 	interactiveCache := cache2.NewInteractiveRedisCache(cmdable)
 	interactiveRepository := repository2.NewCachedInteractiveRepository(interactiveDAO, loggerV1, interactiveCache)
 	interactiveService := service2.NewInteractiveService(interactiveRepository)
 	interactiveServiceClient := ioc.InitIntrClient(interactiveService)
-	articleHandler := web.NewArticleHandler(loggerV1, articleService, interactiveServiceClient)
+	articleHandler := web.NewArticleHandler(loggerV1, articleService, seriesService, interactiveServiceClient, keys)
 	wechatService := InitWechatService(loggerV1)
-	oAuth2WechatHandler := web.NewOAuth2WechatHandler(wechatService, handler, userService)
-	engine := ioc.InitWebServer(v, userHandler, articleHandler, oAuth2WechatHandler)
+	oAuth2WechatHandler := web.NewOAuth2WechatHandler(wechatService, handler, userService, keys)
+	oAuth2Handler := web.NewOAuth2Handler(registry, handler, userService, keys)
+	userProducer := user.NewSaramaSyncProducer(syncProducer)
+	userMergeService := service.NewUserMergeService(userRepository, articleRepository, userProducer)
+	articleReviewDAO := dao.NewGORMArticleReviewDAO(db)
+	articleReviewRepository := repository.NewArticleReviewRepository(articleReviewDAO)
+	moderator := InitModerator()
+	articleReviewService := service.NewArticleReviewService(articleRepository, articleReviewRepository, userRepository, moderator, producer, emailService, loggerV1)
+	adminMiddlewareBuilder := InitAdminMiddleware()
+	adminHandler := web.NewAdminHandler(userMergeService, articleReviewService, handler, adminMiddlewareBuilder)
+	followProducer := follow.NewSaramaSyncProducer(syncProducer)
+	followService := service.NewFollowService(followRepository, userRepository, followProducer, loggerV1)
+	followHandler := web.NewFollowHandler(followService, userService, articleService)
+	historyRecordDAO := dao.NewGORMHistoryRecordDAO(db)
+	historyRecordRepository := repository.NewHistoryRecordRepository(historyRecordDAO)
+	userExportCache := cache.NewRedisUserExportCache(cmdable)
+	userExportFileDAO := InitUserExportFileDAO()
+	userExportRepository := repository.NewUserExportRepository(userExportCache, userExportFileDAO)
+	userExportConfig := InitUserExportConfig()
+	userAccountService := service.NewUserAccountService(userRepository, identityRepository, articleRepository, historyRecordRepository, userExportRepository, interactiveServiceClient, userProducer, userExportConfig, loggerV1)
+	userAccountHandler := web.NewUserAccountHandler(userAccountService, handler, loggerV1)
+	index := ioc.InitSearchIndex()
+	searchService := service.NewSearchService(index, articleRepository, userRepository, loggerV1)
+	searchHandler := web.NewSearchHandler(searchService)
+	commentDAO := dao.NewGORMCommentDAO(db)
+	commentRepository := repository.NewCommentRepository(commentDAO)
+	commentProducer := comment.NewSaramaSyncProducer(syncProducer)
+	commentService := service.NewCommentService(commentRepository, articleRepository, commentProducer, loggerV1)
+	commentHandler := web.NewCommentHandler(commentService, userService)
+	attachmentDAO := dao.NewGORMAttachmentDAO(db)
+	attachmentRepository := repository.NewCachedAttachmentRepository(attachmentDAO)
+	objectStore := InitAttachmentObjectStore()
+	attachmentService := service.NewAttachmentService(attachmentRepository, articleRepository, objectStore, loggerV1)
+	attachmentHandler := web.NewAttachmentHandler(attachmentService, loggerV1)
+	seriesHandler := web.NewSeriesHandler(seriesService, interactiveServiceClient)
+	articleImportCache := cache.NewRedisArticleImportCache(cmdable)
+	articleImportRepository := repository.NewArticleImportRepository(articleImportCache)
+	articleImportConfig := InitArticleImportConfig()
+	articleBundleService := service.NewArticleBundleService(articleService, articleRepository, articleImportRepository, articleImportConfig, loggerV1)
+	articleBundleHandler := web.NewArticleBundleHandler(articleBundleService, loggerV1)
+	engine := ioc.InitWebServer(v, userHandler, articleHandler, oAuth2WechatHandler, oAuth2Handler, adminHandler, followHandler, userAccountHandler, searchHandler, commentHandler, attachmentHandler, seriesHandler, articleBundleHandler)
 	return engine
 }
 
@@ -78,17 +139,24 @@ func InitArticleHandler(dao3 dao.ArticleDAO) *web.ArticleHandler {
 	userCache := cache.NewUserCache(cmdable)
 	userRepository := repository.NewCachedUserRepository(userDAO, userCache)
 	articleCache := cache.NewArticleRedisCache(cmdable)
-	articleRepository := repository.NewCachedArticleRepository(dao3, userRepository, articleCache)
+	articleRepository := repository.NewCachedArticleRepository(dao3, userRepository, articleCache, loggerV1)
+	followRelationDAO := dao.NewGORMFollowRelationDAO(db)
+	followCache := cache.NewRedisFollowCache(cmdable)
+	followRepository := repository.NewCachedFollowRepository(followRelationDAO, followCache, loggerV1)
 	client := InitSaramaClient()
 	syncProducer := InitSyncProducer(client)
 	producer := article.NewSaramaSyncProducer(syncProducer)
-	articleService := service.NewArticleService(articleRepository, producer)
+	articleService := service.NewArticleService(articleRepository, followRepository, producer, loggerV1)
+	seriesDAO := dao.NewGORMSeriesDAO(db)
+	seriesRepository := repository.NewSeriesRepository(seriesDAO)
+	seriesService := service.NewSeriesService(seriesRepository, articleRepository, followRepository)
 	interactiveDAO := dao2.NewGORMInteractiveDAO(db)
 	interactiveCache := cache2.NewInteractiveRedisCache(cmdable)
 	interactiveRepository := repository2.NewCachedInteractiveRepository(interactiveDAO, loggerV1, interactiveCache)
 	interactiveService := service2.NewInteractiveService(interactiveRepository)
 	interactiveServiceClient := ioc.InitIntrClient(interactiveService)
-	articleHandler := web.NewArticleHandler(loggerV1, articleService, interactiveServiceClient)
+	keys := InitJWTKeys()
+	articleHandler := web.NewArticleHandler(loggerV1, articleService, seriesService, interactiveServiceClient, keys)
 	return articleHandler
 }
 
@@ -100,8 +168,24 @@ var thirdPartySet = wire.NewSet(
 	InitSyncProducer,
 	InitLogger)
 
-var userSvcProvider = wire.NewSet(dao.NewUserDAO, cache.NewUserCache, repository.NewCachedUserRepository, service.NewUserService)
+var userSvcProvider = wire.NewSet(dao.NewUserDAO, dao.NewGORMIdentityDAO, dao.NewGORMTOTPDAO, cache.NewUserCache, cache.NewOneTimeTokenCache, repository.NewCachedUserRepository, repository.NewIdentityRepository, repository.NewTOTPRepository, repository.NewOneTimeTokenRepository, InitEmailService, service.NewUserService)
 
 var articlSvcProvider = wire.NewSet(repository.NewCachedArticleRepository, cache.NewArticleRedisCache, dao.NewArticleGORMDAO, service.NewArticleService)
 
+var followRepoProvider = wire.NewSet(dao.NewGORMFollowRelationDAO, cache.NewRedisFollowCache, repository.NewCachedFollowRepository)
+
+var followSvcProvider = wire.NewSet(
+	followRepoProvider, follow.NewSaramaSyncProducer, service.NewFollowService,
+)
+
+var commentSvcProvider = wire.NewSet(dao.NewGORMCommentDAO, repository.NewCommentRepository, comment.NewSaramaSyncProducer, service.NewCommentService)
+
+var attachmentSvcProvider = wire.NewSet(dao.NewGORMAttachmentDAO, repository.NewCachedAttachmentRepository, InitAttachmentObjectStore, service.NewAttachmentService)
+
+var articleReviewSvcProvider = wire.NewSet(dao.NewGORMArticleReviewDAO, repository.NewArticleReviewRepository, InitModerator, service.NewArticleReviewService)
+
+var seriesSvcProvider = wire.NewSet(dao.NewGORMSeriesDAO, repository.NewSeriesRepository, service.NewSeriesService)
+
+var articleBundleSvcProvider = wire.NewSet(cache.NewRedisArticleImportCache, repository.NewArticleImportRepository, InitArticleImportConfig, service.NewArticleBundleService)
+
 var interactiveSvcSet = wire.NewSet(dao2.NewGORMInteractiveDAO, cache2.NewInteractiveRedisCache, repository2.NewCachedInteractiveRepository, service2.NewInteractiveService, ioc.InitIntrClient)
@@ -147,3 +147,78 @@ func TestUserHandler_SendSMSCode(t *testing.T) {
 	}
 
 }
+
+func TestUserHandler_LoginJWT_Guard(t *testing.T) {
+	rdb := startup.InitRedis()
+	server := startup.InitWebServer()
+	const email = "guard@qq.com"
+	testCases := []struct {
+		name   string
+		before func(t *testing.T)
+
+		wantBody ginx.Result
+	}{
+		{
+			name: "账号被锁定",
+			before: func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+				defer cancel()
+				err := rdb.Set(ctx, "login_attempt:lock:"+email, 10, time.Minute).Err()
+				assert.NoError(t, err)
+			},
+			wantBody: ginx.Result{
+				Code: 401009,
+				Msg:  "密码错误次数太多，账号已经被锁定，请使用手机验证码登录解锁",
+			},
+		},
+		{
+			name: "失败次数太多，需要等待",
+			before: func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+				defer cancel()
+				// 失败了 4 次，要等待 2 秒
+				err := rdb.HSet(ctx, "login_attempt:"+email,
+					"cnt", 4, "last", time.Now().UnixMilli()).Err()
+				assert.NoError(t, err)
+			},
+			wantBody: ginx.Result{
+				Code: 401005,
+				Msg:  "密码错误次数太多，请 2 秒后再试",
+			},
+		},
+		{
+			name: "密码错误",
+			before: func(t *testing.T) {
+			},
+			wantBody: ginx.Result{
+				Msg: "用户名或者密码错误",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.before(t)
+			defer func() {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+				defer cancel()
+				err := rdb.Del(ctx, "login_attempt:"+email, "login_attempt:lock:"+email).Err()
+				assert.NoError(t, err)
+			}()
+
+			req, err := http.NewRequest(http.MethodPost,
+				"/users/login",
+				bytes.NewReader([]byte(fmt.Sprintf(`{"email": "%s", "password": "hello#world123"}`, email))))
+			req.Header.Set("Content-Type", "application/json")
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			server.ServeHTTP(recorder, req)
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var res ginx.Result
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantBody, res)
+		})
+	}
+}
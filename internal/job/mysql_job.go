@@ -5,6 +5,7 @@ import (
 	"ddd_demo/internal/domain"
 	"ddd_demo/internal/service"
 	"ddd_demo/pkg/logger"
+	"errors"
 	"fmt"
 	"golang.org/x/sync/semaphore"
 	"time"
@@ -55,6 +56,8 @@ type Scheduler struct {
 	l       logger.LoggerV1
 	execs   map[string]Executor
 	limiter *semaphore.Weighted
+	// idleInterval 没有抢到任务的时候，等多久再抢
+	idleInterval time.Duration
 }
 
 func NewScheduler(svc service.JobService, l logger.LoggerV1) *Scheduler {
@@ -63,6 +66,8 @@ func NewScheduler(svc service.JobService, l logger.LoggerV1) *Scheduler {
 		l:       l,
 		limiter: semaphore.NewWeighted(200), // 本地执行器的并发限制
 		execs:   make(map[string]Executor),
+
+		idleInterval: time.Second,
 	}
 }
 
@@ -91,7 +96,16 @@ func (s *Scheduler) Schedule(ctx context.Context) error {
 		if err != nil {
 			// 你不能 return
 			// 你要继续下一轮
-			s.l.Error("抢占任务失败", logger.Error(err))
+			s.limiter.Release(1)
+			if !errors.Is(err, service.ErrNoMoreJob) {
+				s.l.Error("抢占任务失败", logger.Error(err))
+			}
+			// 没有任务或者数据库出问题了，都歇一会儿再抢，免得把数据库打爆
+			select {
+			case <-ctx.Done():
+			case <-time.After(s.idleInterval):
+			}
+			continue
 		}
 
 		// 判断当前任务对应执行器是否存在
@@ -101,6 +115,12 @@ func (s *Scheduler) Schedule(ctx context.Context) error {
 			// 线上就继续
 			s.l.Error("未找到对应的执行器",
 				logger.String("executor", j.Executor))
+			s.limiter.Release(1)
+			if err1 := j.CancelFunc(); err1 != nil {
+				s.l.Error("释放任务失败",
+					logger.Error(err1),
+					logger.Int64("jid", j.Id))
+			}
 			continue
 		}
 
@@ -5,22 +5,77 @@ import (
 	"ddd_demo/internal/domain"
 	"ddd_demo/internal/repository/cache"
 	"ddd_demo/internal/repository/dao"
+	"ddd_demo/pkg/logger"
 	"time"
 
 	"github.com/ecodeclub/ekit/slice"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrArticleNotFound         = dao.ErrRecordNotFound
+	ErrArticleRevisionNotFound = dao.ErrRecordNotFound
+	ErrArticleNotScheduled     = dao.ErrArticleNotScheduled
+	ErrArticleScheduleChanged  = dao.ErrArticleScheduleChanged
+	ErrArticleReviewChanged    = dao.ErrArticleReviewChanged
+	ErrArticleVersionConflict  = dao.ErrArticleVersionConflict
+	ErrArticleDraftForbidden   = cache.ErrArticleDraftForbidden
+)
+
 //go:generate mockgen -source=./article.go -package=repomocks -destination=./mocks/article.mock.go ArticleRepository
 type ArticleRepository interface {
 	Create(ctx context.Context, art domain.Article) (int64, error)
+	// Update 有自动保存的草稿的话先把草稿刷到数据库，作者保存的时候版本号对不上返回 ErrArticleVersionConflict
 	Update(ctx context.Context, art domain.Article) error
+	// SaveDraft 自动保存草稿，返回新的版本号
+	SaveDraft(ctx context.Context, draft domain.ArticleDraft) (int64, error)
+	// FlushDrafts 把 before 之前就改过的草稿刷到数据库，最多 limit 篇，返回处理了多少篇
+	FlushDrafts(ctx context.Context, before time.Time, limit int) (int, error)
+	// Sync 把审核通过的版本发表出去，art.Revision 是审核的版本
 	Sync(ctx context.Context, art domain.Article) (int64, error)
+	// RejectReview 审核没通过，只改制作库里面的状态
+	RejectReview(ctx context.Context, uid int64, id int64, revision int64) error
 	SyncStatus(ctx context.Context, uid int64, id int64, status domain.ArticleStatus) error
+	// SyncVisibility 修改可见范围，制作库和线上库一起改
+	SyncVisibility(ctx context.Context, uid int64, id int64, visibility domain.ArticleVisibility) error
 	GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error)
+	// GetById 有还没刷到数据库的草稿的话，返回草稿的标题、内容和版本号
 	GetById(ctx context.Context, id int64) (domain.Article, error)
+	// FindById 和 GetById 一样，但是直接查数据库，也不管草稿。审核这种必须拿到提交的那个版本的场景用
+	FindById(ctx context.Context, id int64) (domain.Article, error)
 	GetPubById(ctx context.Context, id int64) (domain.Article, error)
+	// FindPubById 和 GetPubById 一样，但是直接查数据库，既不读缓存也不回写缓存。
+	// 撤回的文章也会返回，调用方自己看 Status。同步搜索索引这种必须拿到最新状态的场景用
+	FindPubById(ctx context.Context, id int64) (domain.Article, error)
+	// GetPubByIds 线上库里面的这些文章，只有标题、摘要这些元数据，不保证顺序。撤回了的也会返回，调用方自己看 Status
+	GetPubByIds(ctx context.Context, ids []int64) ([]domain.Article, error)
 	ListPub(ctx context.Context, start time.Time, offset, limit int) ([]domain.Article, error)
+	// GetByAuthorByCursor 和 GetByAuthor 一样，按照 utime、id 倒序翻页，第一页也会走缓存
+	GetByAuthorByCursor(ctx context.Context, uid int64, cursor domain.ArticleCursor, limit int) ([]domain.Article, error)
+	// ListPubByCursor start 之前更新过的已经发表的文章，按照 utime、id 倒序翻页
+	ListPubByCursor(ctx context.Context, start time.Time, cursor domain.ArticleCursor, limit int) ([]domain.Article, error)
+	TransferAuthor(ctx context.Context, fromUid int64, toUid int64) error
+	// ListPubByAuthor 作者已经发表的文章，只返回 visibilities 里面的可见范围，为空就是全部
+	ListPubByAuthor(ctx context.Context, uid int64, visibilities []domain.ArticleVisibility,
+		offset int, limit int) ([]domain.Article, error)
+	// ListByAuthor 和 GetByAuthorByCursor 一样，但是直接查数据库，既不读缓存也不回写缓存。
+	// 导出数据这种要把所有文章都翻一遍的场景用，缓存里面的首页只有摘要
+	ListByAuthor(ctx context.Context, uid int64, cursor domain.ArticleCursor, limit int) ([]domain.Article, error)
+	// WithdrawByAuthor 撤回作者所有已经发表的文章，注销账号的时候用
+	WithdrawByAuthor(ctx context.Context, uid int64) error
+	// ListRevisions 文章的历史版本，新的在前面，不包含内容
+	ListRevisions(ctx context.Context, id int64, offset int, limit int) ([]domain.ArticleRevision, error)
+	GetRevision(ctx context.Context, id int64, version int64) (domain.ArticleRevision, error)
+	// ListDue 到了发表时间的定时文章
+	ListDue(ctx context.Context, now time.Time, limit int) ([]domain.Article, error)
+	UpdateSchedule(ctx context.Context, uid int64, id int64, publishAt time.Time) error
+	CancelSchedule(ctx context.Context, uid int64, id int64) error
+	// ListPubByTag 标签下面已经发表的文章，新的在前面，cursor 是上一页最后一篇的 ID，第一页传 0
+	ListPubByTag(ctx context.Context, tag string, cursor int64, limit int) ([]domain.Article, error)
+	// ListTagCounts 文章最多的 limit 个标签
+	ListTagCounts(ctx context.Context, limit int) ([]domain.TagCount, error)
+	// ReferencedAttachments keys 里面还被草稿或者已经发表的文章引用着的附件
+	ReferencedAttachments(ctx context.Context, keys []string) ([]string, error)
 }
 
 type CachedArticleRepository struct {
@@ -34,6 +89,7 @@ type CachedArticleRepository struct {
 	authorDAO dao.ArticleAuthorDAO
 
 	db *gorm.DB
+	l  logger.LoggerV1
 }
 
 func (c *CachedArticleRepository) GetPubById(ctx context.Context, id int64) (domain.Article, error) {
@@ -65,10 +121,163 @@ func (c *CachedArticleRepository) GetPubById(ctx context.Context, id int64) (dom
 	return res, nil
 }
 
+func (c *CachedArticleRepository) FindPubById(ctx context.Context, id int64) (domain.Article, error) {
+	art, err := c.dao.GetPubById(ctx, id)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	res := c.toDomain(dao.Article(art))
+	author, err := c.userRepo.FindById(ctx, art.AuthorId)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	res.Author.Name = author.Nickname
+	return res, nil
+}
+
+func (c *CachedArticleRepository) TransferAuthor(ctx context.Context, fromUid int64, toUid int64) error {
+	err := c.dao.TransferAuthor(ctx, fromUid, toUid)
+	if err != nil {
+		return err
+	}
+	err = c.cache.DelFirstPage(ctx, fromUid)
+	if err != nil {
+		return err
+	}
+	return c.cache.DelFirstPage(ctx, toUid)
+}
+
+func (c *CachedArticleRepository) GetPubByIds(ctx context.Context, ids []int64) ([]domain.Article, error) {
+	arts, err := c.dao.GetPubByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(arts, func(idx int, src dao.PublishedArticle) domain.Article {
+		return c.toDomain(dao.Article(src))
+	}), nil
+}
+
+func (c *CachedArticleRepository) ListPubByAuthor(ctx context.Context,
+	uid int64, visibilities []domain.ArticleVisibility, offset int, limit int) ([]domain.Article, error) {
+	var vs []uint8
+	if len(visibilities) > 0 {
+		vs = slice.Map(visibilities, func(idx int, src domain.ArticleVisibility) uint8 {
+			return src.ToUint8()
+		})
+	}
+	arts, err := c.dao.GetPubByAuthor(ctx, uid, vs, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(arts, func(idx int, src dao.PublishedArticle) domain.Article {
+		return c.toDomain(dao.Article(src))
+	}), nil
+}
+
+func (c *CachedArticleRepository) ListByAuthor(ctx context.Context,
+	uid int64, cursor domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	arts, err := c.dao.GetByAuthorByCursor(ctx, uid, cursor.Utime.UnixMilli(), cursor.Id, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(arts, func(idx int, src dao.Article) domain.Article {
+		return c.toDomain(src)
+	}), nil
+}
+
+func (c *CachedArticleRepository) WithdrawByAuthor(ctx context.Context, uid int64) error {
+	ids, err := c.dao.WithdrawByAuthor(ctx, uid)
+	if err != nil {
+		return err
+	}
+	err = c.cache.DelFirstPage(ctx, uid)
+	if err != nil {
+		return err
+	}
+	err = c.cache.DelPub(ctx, ids...)
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+	tags, err := c.dao.GetPubTags(ctx, ids)
+	if err != nil {
+		return err
+	}
+	var all []string
+	for _, ts := range tags {
+		all = append(all, ts...)
+	}
+	err = c.cache.DelTagFirstPage(ctx, all...)
+	if err != nil {
+		return err
+	}
+	return c.cache.DelTagCounts(ctx)
+}
+
+func (c *CachedArticleRepository) ListRevisions(ctx context.Context,
+	id int64, offset int, limit int) ([]domain.ArticleRevision, error) {
+	revs, err := c.dao.ListRevisions(ctx, id, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(revs, func(idx int, src dao.ArticleRevision) domain.ArticleRevision {
+		return c.revisionToDomain(src)
+	}), nil
+}
+
+func (c *CachedArticleRepository) GetRevision(ctx context.Context,
+	id int64, version int64) (domain.ArticleRevision, error) {
+	rev, err := c.dao.GetRevision(ctx, id, version)
+	if err != nil {
+		return domain.ArticleRevision{}, err
+	}
+	return c.revisionToDomain(rev), nil
+}
+
+func (c *CachedArticleRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]domain.Article, error) {
+	arts, err := c.dao.ListDue(ctx, now.UnixMilli(), limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(arts, func(idx int, src dao.Article) domain.Article {
+		return c.toDomain(src)
+	}), nil
+}
+
+func (c *CachedArticleRepository) UpdateSchedule(ctx context.Context,
+	uid int64, id int64, publishAt time.Time) error {
+	err := c.dao.UpdateSchedule(ctx, uid, id, publishAt.UnixMilli())
+	if err == nil {
+		er := c.cache.DelFirstPage(ctx, uid)
+		if er != nil {
+			// 也要记录日志
+		}
+	}
+	return err
+}
+
+func (c *CachedArticleRepository) CancelSchedule(ctx context.Context, uid int64, id int64) error {
+	err := c.dao.CancelSchedule(ctx, uid, id)
+	if err == nil {
+		er := c.cache.DelFirstPage(ctx, uid)
+		if er != nil {
+			// 也要记录日志
+		}
+	}
+	return err
+}
+
+func (c *CachedArticleRepository) FindById(ctx context.Context, id int64) (domain.Article, error) {
+	art, err := c.dao.GetById(ctx, id)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	return c.toDomain(art), nil
+}
+
 func (c *CachedArticleRepository) GetById(ctx context.Context, id int64) (domain.Article, error) {
 	res, err := c.cache.Get(ctx, id)
 	if err == nil {
-		return res, nil
+		return c.withDraft(ctx, res), nil
 	}
 	art, err := c.dao.GetById(ctx, id)
 	if err != nil {
@@ -81,7 +290,7 @@ func (c *CachedArticleRepository) GetById(ctx context.Context, id int64) (domain
 			// 记录日志
 		}
 	}()
-	return res, nil
+	return c.withDraft(ctx, res), nil
 }
 
 func (c *CachedArticleRepository) GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error) {
@@ -132,18 +341,66 @@ func (c *CachedArticleRepository) SyncStatus(ctx context.Context, uid int64, id
 		if er != nil {
 			// 也要记录日志
 		}
+		// 缓存里面的还是原来的状态，不删掉的话撤回了别人还能看到
+		er = c.cache.DelPub(ctx, id)
+		if er != nil {
+			// 也要记录日志
+		}
+		// 撤回之后标签还在，但是不应该再出现在标签的列表里面了
+		c.delTagCache(ctx, c.pubTags(ctx, id))
 	}
 	return err
 }
 
-func (c *CachedArticleRepository) Sync(ctx context.Context, art domain.Article) (int64, error) {
-	id, err := c.dao.Sync(ctx, c.toEntity(art))
+func (c *CachedArticleRepository) RejectReview(ctx context.Context, uid int64, id int64, revision int64) error {
+	err := c.dao.RejectReview(ctx, uid, id, revision)
 	if err == nil {
-		er := c.cache.DelFirstPage(ctx, art.Author.Id)
+		er := c.cache.DelFirstPage(ctx, uid)
 		if er != nil {
 			// 也要记录日志
 		}
 	}
+	return err
+}
+
+func (c *CachedArticleRepository) SyncVisibility(ctx context.Context,
+	uid int64, id int64, visibility domain.ArticleVisibility) error {
+	err := c.dao.SyncVisibility(ctx, uid, id, visibility.ToUint8())
+	if err != nil {
+		return err
+	}
+	er := c.cache.DelFirstPage(ctx, uid)
+	if er != nil {
+		// 也要记录日志
+	}
+	er = c.cache.DelPub(ctx, id)
+	if er != nil {
+		// 也要记录日志
+	}
+	// 从公开改成别的，标签页和标签统计里面要去掉；反过来要加上
+	c.delTagCache(ctx, c.pubTags(ctx, id))
+	return nil
+}
+
+func (c *CachedArticleRepository) Sync(ctx context.Context, art domain.Article) (int64, error) {
+	var oldTags []string
+	if art.Id > 0 {
+		// 改了标签的话，原来那些标签下面的列表也要删掉
+		oldTags = c.pubTags(ctx, art.Id)
+	}
+	id, err := c.dao.Sync(ctx, c.toEntity(art))
+	if err != nil {
+		// 没有同步成功就不能写线上库的缓存，比如审核中的文章已经被作者改了
+		return 0, err
+	}
+	er := c.cache.DelFirstPage(ctx, art.Author.Id)
+	if er != nil {
+		// 也要记录日志
+	}
+	art.Id = id
+	// 审核通过的时候没有带标签，以线上库为准
+	art.Tags = c.pubTags(ctx, id)
+	c.delTagCache(ctx, append(oldTags, art.Tags...))
 	// 在这里尝试，设置缓存
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
@@ -163,7 +420,7 @@ func (c *CachedArticleRepository) Sync(ctx context.Context, art domain.Article)
 			// 记录日志
 		}
 	}()
-	return id, err
+	return id, nil
 }
 
 func (c *CachedArticleRepository) SyncV2(ctx context.Context, art domain.Article) (int64, error) {
@@ -220,12 +477,22 @@ func (c *CachedArticleRepository) SyncV1(ctx context.Context, art domain.Article
 }
 
 func (c *CachedArticleRepository) Update(ctx context.Context, art domain.Article) error {
-	err := c.dao.UpdateById(ctx, c.toEntity(art))
+	// 草稿的版本号比数据库里面的新，不先刷进去的话作者保存的时候版本号对不上
+	err := c.flushDraftBeforeUpdate(ctx, art.Id)
+	if err != nil {
+		return err
+	}
+	err = c.dao.UpdateById(ctx, c.toEntity(art))
 	if err == nil {
 		er := c.cache.DelFirstPage(ctx, art.Author.Id)
 		if er != nil {
 			// 也要记录日志
 		}
+		// 缓存里面还是原来的版本号，作者刷新之后再保存会冲突
+		er = c.cache.Del(ctx, art.Id)
+		if er != nil {
+			// 也要记录日志
+		}
 	}
 	return err
 }
@@ -243,11 +510,13 @@ func (c *CachedArticleRepository) Create(ctx context.Context, art domain.Article
 
 func NewCachedArticleRepository(dao dao.ArticleDAO,
 	userRepo UserRepository,
-	cache cache.ArticleCache) ArticleRepository {
+	cache cache.ArticleCache,
+	l logger.LoggerV1) ArticleRepository {
 	return &CachedArticleRepository{
 		dao:      dao,
 		cache:    cache,
 		userRepo: userRepo,
+		l:        l,
 	}
 }
 
@@ -274,6 +543,32 @@ func (c *CachedArticleRepository) ListPub(ctx context.Context, start time.Time,
 		}), nil
 }
 
+func (c *CachedArticleRepository) GetByAuthorByCursor(ctx context.Context,
+	uid int64, cursor domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	if cursor.IsZero() {
+		// 第一页和 GetByAuthor 的顺序一样，复用它的缓存
+		return c.GetByAuthor(ctx, uid, 0, limit)
+	}
+	arts, err := c.dao.GetByAuthorByCursor(ctx, uid, cursor.Utime.UnixMilli(), cursor.Id, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(arts, func(idx int, src dao.Article) domain.Article {
+		return c.toDomain(src)
+	}), nil
+}
+
+func (c *CachedArticleRepository) ListPubByCursor(ctx context.Context,
+	start time.Time, cursor domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	arts, err := c.dao.ListPubByCursor(ctx, start, cursor.Utime.UnixMilli(), cursor.Id, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(arts, func(idx int, src dao.PublishedArticle) domain.Article {
+		return c.toDomain(dao.Article(src))
+	}), nil
+}
+
 func (c *CachedArticleRepository) toEntity(art domain.Article) dao.Article {
 	return dao.Article{
 		Id:       art.Id,
@@ -281,12 +576,54 @@ func (c *CachedArticleRepository) toEntity(art domain.Article) dao.Article {
 		Content:  art.Content,
 		AuthorId: art.Author.Id,
 		//Status:   uint8(art.Status),
-		Status: art.Status.ToUint8(),
+		Status:      art.Status.ToUint8(),
+		Visibility:  art.Visibility.ToUint8(),
+		Revision:    art.Revision,
+		Version:     art.Version,
+		PublishAt:   c.toMilli(art.PublishAt),
+		Tags:        art.Tags,
+		Html:        art.Rendered.HTML,
+		Attachments: art.Attachments,
+		Abstract:    art.Rendered.Abstract,
+		Toc:         c.tocToEntity(art.Rendered.TOC),
+		// 只有新建的时候用得上，见 dao 的 insert
+		Ctime: c.toMilli(art.Ctime),
 	}
 }
 
+func (c *CachedArticleRepository) ReferencedAttachments(ctx context.Context, keys []string) ([]string, error) {
+	return c.dao.ReferencedAttachments(ctx, keys)
+}
+
+// tocToEntity 没有目录的时候保持 nil
+func (c *CachedArticleRepository) tocToEntity(toc []domain.ArticleHeading) []dao.ArticleHeading {
+	if len(toc) == 0 {
+		return nil
+	}
+	return slice.Map(toc, func(idx int, src domain.ArticleHeading) dao.ArticleHeading {
+		return dao.ArticleHeading{Level: src.Level, Id: src.Id, Title: src.Title}
+	})
+}
+
+func (c *CachedArticleRepository) tocToDomain(toc []dao.ArticleHeading) []domain.ArticleHeading {
+	if len(toc) == 0 {
+		return nil
+	}
+	return slice.Map(toc, func(idx int, src dao.ArticleHeading) domain.ArticleHeading {
+		return domain.ArticleHeading{Level: src.Level, Id: src.Id, Title: src.Title}
+	})
+}
+
+// toMilli 零值的时间就是没有设置，存 0
+func (c *CachedArticleRepository) toMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
 func (c *CachedArticleRepository) toDomain(art dao.Article) domain.Article {
-	return domain.Article{
+	res := domain.Article{
 		Id:      art.Id,
 		Title:   art.Title,
 		Content: art.Content,
@@ -294,9 +631,34 @@ func (c *CachedArticleRepository) toDomain(art dao.Article) domain.Article {
 			// 这里有一个错误
 			Id: art.AuthorId,
 		},
-		Ctime:  time.UnixMilli(art.Ctime),
-		Utime:  time.UnixMilli(art.Utime),
-		Status: domain.ArticleStatus(art.Status),
+		Ctime:       time.UnixMilli(art.Ctime),
+		Utime:       time.UnixMilli(art.Utime),
+		Status:      domain.ArticleStatus(art.Status),
+		Visibility:  domain.ArticleVisibility(art.Visibility),
+		Revision:    art.Revision,
+		Version:     art.Version,
+		Tags:        art.Tags,
+		Attachments: art.Attachments,
+		Rendered: domain.ArticleRendered{
+			HTML:     art.Html,
+			Abstract: art.Abstract,
+			TOC:      c.tocToDomain(art.Toc),
+		},
+	}
+	if art.PublishAt > 0 {
+		res.PublishAt = time.UnixMilli(art.PublishAt)
+	}
+	return res
+}
+
+func (c *CachedArticleRepository) revisionToDomain(rev dao.ArticleRevision) domain.ArticleRevision {
+	return domain.ArticleRevision{
+		ArticleId: rev.ArticleId,
+		Version:   rev.Version,
+		Title:     rev.Title,
+		Content:   rev.Content,
+		Hash:      rev.Hash,
+		Ctime:     time.UnixMilli(rev.Ctime),
 	}
 }
 
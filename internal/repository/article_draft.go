@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository/cache"
+	"ddd_demo/internal/repository/dao"
+	"ddd_demo/pkg/logger"
+	"time"
+)
+
+func (c *CachedArticleRepository) SaveDraft(ctx context.Context, draft domain.ArticleDraft) (int64, error) {
+	// 大部分时候已经有草稿了，不用查数据库
+	draft.Base = -1
+	version, err := c.cache.SaveDraft(ctx, draft)
+	if err == cache.ErrArticleDraftMissing {
+		var art dao.Article
+		art, err = c.dao.GetById(ctx, draft.Id)
+		if err != nil {
+			return 0, err
+		}
+		if art.AuthorId != draft.Author.Id {
+			return 0, ErrArticleDraftForbidden
+		}
+		draft.Base = art.Version
+		version, err = c.cache.SaveDraft(ctx, draft)
+	}
+	if err == cache.ErrArticleDraftConflict {
+		return 0, ErrArticleVersionConflict
+	}
+	return version, err
+}
+
+func (c *CachedArticleRepository) FlushDrafts(ctx context.Context, before time.Time, limit int) (int, error) {
+	ids, err := c.cache.ListDirtyDrafts(ctx, before, limit)
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		d, err := c.cache.GetDraft(ctx, id)
+		if err == cache.ErrKeyNotExist {
+			// 草稿过期了
+			err = c.cache.DelDraft(ctx, id)
+		} else if err == nil {
+			err = c.flushDraft(ctx, d)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(ids), nil
+}
+
+// flushDraft 草稿刷到数据库之后，缓存里面的文章和作者的第一页都过时了
+func (c *CachedArticleRepository) flushDraft(ctx context.Context, d domain.ArticleDraft) error {
+	err := c.dao.FlushDraft(ctx, dao.Article{
+		Id:          d.Id,
+		AuthorId:    d.Author.Id,
+		Title:       d.Title,
+		Content:     d.Content,
+		Attachments: d.Attachments,
+		Version:     d.Version,
+	}, d.Base)
+	if err == dao.ErrArticleVersionConflict {
+		// 只有几个标签页同时保存、自动保存的时候才会出现，数据库里面的更新，草稿作废
+		c.l.Warn("自动保存的草稿和数据库里面的冲突，丢弃草稿",
+			logger.Int64("aid", d.Id),
+			logger.Int64("version", d.Version))
+		return c.cache.DelDraft(ctx, d.Id)
+	}
+	if err != nil {
+		return err
+	}
+	er := c.cache.Del(ctx, d.Id)
+	if er != nil {
+		// 也要记录日志
+	}
+	er = c.cache.DelFirstPage(ctx, d.Author.Id)
+	if er != nil {
+		// 也要记录日志
+	}
+	return c.cache.DraftFlushed(ctx, d.Id, d.Version)
+}
+
+// flushDraftBeforeUpdate 作者保存、发表之前先把草稿刷进去。
+// Redis 出问题的时候直接往下走，真的有草稿的话版本号会对不上，不会冲掉草稿
+func (c *CachedArticleRepository) flushDraftBeforeUpdate(ctx context.Context, id int64) error {
+	d, err := c.cache.GetDraft(ctx, id)
+	switch err {
+	case nil:
+		return c.flushDraft(ctx, d)
+	case cache.ErrKeyNotExist:
+		return nil
+	default:
+		c.l.Error("查询自动保存的草稿失败",
+			logger.Int64("aid", id),
+			logger.Error(err))
+		return nil
+	}
+}
+
+// withDraft 作者看到的应该是自动保存的最新内容
+func (c *CachedArticleRepository) withDraft(ctx context.Context, art domain.Article) domain.Article {
+	d, err := c.cache.GetDraft(ctx, art.Id)
+	if err != nil {
+		// 没有草稿，或者 Redis 出问题了，都只能用数据库里面的
+		return art
+	}
+	art.Title = d.Title
+	art.Content = d.Content
+	art.Attachments = d.Attachments
+	art.Version = d.Version
+	art.Utime = d.Utime
+	return art
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository/cache"
+	cachemocks "ddd_demo/internal/repository/cache/mocks"
+	"ddd_demo/internal/repository/dao"
+	daomocks "ddd_demo/internal/repository/dao/mocks"
+	"ddd_demo/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestCachedArticleRepository_SaveDraft(t *testing.T) {
+	draft := domain.ArticleDraft{
+		Id:      1,
+		Author:  domain.Author{Id: 123},
+		Title:   "标题",
+		Content: "内容",
+		Version: 4,
+	}
+	unknown := draft
+	unknown.Base = -1
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (dao.ArticleDAO, cache.ArticleCache)
+
+		wantVersion int64
+		wantErr     error
+	}{
+		{
+			name: "已经有草稿了",
+			mock: func(ctrl *gomock.Controller) (dao.ArticleDAO, cache.ArticleCache) {
+				c := cachemocks.NewMockArticleCache(ctrl)
+				c.EXPECT().SaveDraft(gomock.Any(), unknown).Return(int64(5), nil)
+				return daomocks.NewMockArticleDAO(ctrl), c
+			},
+			wantVersion: 5,
+		},
+		{
+			name: "第一次自动保存，从数据库里面的版本开始",
+			mock: func(ctrl *gomock.Controller) (dao.ArticleDAO, cache.ArticleCache) {
+				d := daomocks.NewMockArticleDAO(ctrl)
+				c := cachemocks.NewMockArticleCache(ctrl)
+				c.EXPECT().SaveDraft(gomock.Any(), unknown).Return(int64(0), cache.ErrArticleDraftMissing)
+				d.EXPECT().GetById(gomock.Any(), int64(1)).
+					Return(dao.Article{Id: 1, AuthorId: 123, Version: 4}, nil)
+				withBase := draft
+				withBase.Base = 4
+				c.EXPECT().SaveDraft(gomock.Any(), withBase).Return(int64(5), nil)
+				return d, c
+			},
+			wantVersion: 5,
+		},
+		{
+			name: "不是作者",
+			mock: func(ctrl *gomock.Controller) (dao.ArticleDAO, cache.ArticleCache) {
+				d := daomocks.NewMockArticleDAO(ctrl)
+				c := cachemocks.NewMockArticleCache(ctrl)
+				c.EXPECT().SaveDraft(gomock.Any(), unknown).Return(int64(0), cache.ErrArticleDraftMissing)
+				d.EXPECT().GetById(gomock.Any(), int64(1)).
+					Return(dao.Article{Id: 1, AuthorId: 456, Version: 4}, nil)
+				return d, c
+			},
+			wantErr: ErrArticleDraftForbidden,
+		},
+		{
+			name: "别的标签页已经保存过了",
+			mock: func(ctrl *gomock.Controller) (dao.ArticleDAO, cache.ArticleCache) {
+				c := cachemocks.NewMockArticleCache(ctrl)
+				c.EXPECT().SaveDraft(gomock.Any(), unknown).Return(int64(0), cache.ErrArticleDraftConflict)
+				return daomocks.NewMockArticleDAO(ctrl), c
+			},
+			wantErr: ErrArticleVersionConflict,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			d, c := tc.mock(ctrl)
+			repo := NewCachedArticleRepository(d, nil, c, logger.NewNopLogger())
+			version, err := repo.SaveDraft(context.Background(), draft)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantVersion, version)
+		})
+	}
+}
+
+// TestCachedArticleRepository_UpdateWithDraft 作者手动保存之前先把草稿刷进去，版本号才对得上
+func TestCachedArticleRepository_UpdateWithDraft(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	d := daomocks.NewMockArticleDAO(ctrl)
+	c := cachemocks.NewMockArticleCache(ctrl)
+	c.EXPECT().GetDraft(gomock.Any(), int64(1)).Return(domain.ArticleDraft{
+		Id:          1,
+		Author:      domain.Author{Id: 123},
+		Title:       "草稿的标题",
+		Content:     "草稿的内容",
+		Attachments: []string{"a.png"},
+		Version:     5,
+		Base:        3,
+		Utime:       time.UnixMilli(100),
+	}, nil)
+	gomock.InOrder(
+		d.EXPECT().FlushDraft(gomock.Any(), dao.Article{
+			Id:          1,
+			AuthorId:    123,
+			Title:       "草稿的标题",
+			Content:     "草稿的内容",
+			Attachments: []string{"a.png"},
+			Version:     5,
+		}, int64(3)).Return(nil),
+		c.EXPECT().DraftFlushed(gomock.Any(), int64(1), int64(5)).Return(nil),
+		d.EXPECT().UpdateById(gomock.Any(), dao.Article{
+			Id:       1,
+			AuthorId: 123,
+			Title:    "标题",
+			Content:  "内容",
+			Status:   domain.ArticleStatusUnpublished,
+			Version:  5,
+		}).Return(nil),
+	)
+	c.EXPECT().Del(gomock.Any(), int64(1)).Return(nil).Times(2)
+	c.EXPECT().DelFirstPage(gomock.Any(), int64(123)).Return(nil).Times(2)
+
+	repo := NewCachedArticleRepository(d, nil, c, logger.NewNopLogger())
+	err := repo.Update(context.Background(), domain.Article{
+		Id:      1,
+		Author:  domain.Author{Id: 123},
+		Title:   "标题",
+		Content: "内容",
+		Status:  domain.ArticleStatusUnpublished,
+		Version: 5,
+	})
+	assert.NoError(t, err)
+}
+
+func TestCachedArticleRepository_FlushDrafts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	before := time.Now()
+	d := daomocks.NewMockArticleDAO(ctrl)
+	c := cachemocks.NewMockArticleCache(ctrl)
+	c.EXPECT().ListDirtyDrafts(gomock.Any(), before, 10).Return([]int64{1, 2}, nil)
+	// 草稿过期了，从集合里面去掉
+	c.EXPECT().GetDraft(gomock.Any(), int64(1)).Return(domain.ArticleDraft{}, cache.ErrKeyNotExist)
+	c.EXPECT().DelDraft(gomock.Any(), int64(1)).Return(nil)
+	// 开始自动保存之后数据库被改过了，草稿作废
+	c.EXPECT().GetDraft(gomock.Any(), int64(2)).Return(domain.ArticleDraft{
+		Id:      2,
+		Author:  domain.Author{Id: 123},
+		Version: 5,
+		Base:    3,
+	}, nil)
+	d.EXPECT().FlushDraft(gomock.Any(), gomock.Any(), int64(3)).Return(dao.ErrArticleVersionConflict)
+	c.EXPECT().DelDraft(gomock.Any(), int64(2)).Return(nil)
+
+	repo := NewCachedArticleRepository(d, nil, c, logger.NewNopLogger())
+	n, err := repo.FlushDrafts(context.Background(), before, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository/cache"
+	"time"
+)
+
+var ErrArticleImportRunning = cache.ErrArticleImportRunning
+
+//go:generate mockgen -source=./article_import.go -package=repomocks -destination=./mocks/article_import.mock.go ArticleImportRepository
+type ArticleImportRepository interface {
+	// Get 没有导入过，或者已经过期了，返回 Status 为 ArticleImportStatusUnknown
+	Get(ctx context.Context, uid int64) (domain.ArticleImport, error)
+	Save(ctx context.Context, imp domain.ArticleImport, expiration time.Duration) error
+	// Claim 开始导入，同时保存 imp。同一个用户同时只能有一个导入，已经有了返回 ErrArticleImportRunning。
+	// timeout 之后就认为上一次导入已经结束了
+	Claim(ctx context.Context, imp domain.ArticleImport, timeout time.Duration, expiration time.Duration) error
+	// Release 导入结束之后调用，下一次导入不用等到超时
+	Release(ctx context.Context, imp domain.ArticleImport) error
+}
+
+// ArticleImportRepositoryImpl 导入任务的状态和导出一样只是临时数据，只放在 Redis 里面，
+// 这样导入的时候请求落到哪个节点上都能查到进度
+type ArticleImportRepositoryImpl struct {
+	cache cache.ArticleImportCache
+}
+
+func NewArticleImportRepository(cache cache.ArticleImportCache) ArticleImportRepository {
+	return &ArticleImportRepositoryImpl{cache: cache}
+}
+
+func (repo *ArticleImportRepositoryImpl) Get(ctx context.Context, uid int64) (domain.ArticleImport, error) {
+	res, err := repo.cache.Get(ctx, uid)
+	if err == cache.ErrKeyNotExist {
+		return domain.ArticleImport{Uid: uid}, nil
+	}
+	return res, err
+}
+
+func (repo *ArticleImportRepositoryImpl) Save(ctx context.Context,
+	imp domain.ArticleImport, expiration time.Duration) error {
+	return repo.cache.Set(ctx, imp, expiration)
+}
+
+func (repo *ArticleImportRepositoryImpl) Claim(ctx context.Context,
+	imp domain.ArticleImport, timeout time.Duration, expiration time.Duration) error {
+	return repo.cache.Claim(ctx, imp, timeout, expiration)
+}
+
+func (repo *ArticleImportRepositoryImpl) Release(ctx context.Context, imp domain.ArticleImport) error {
+	return repo.cache.Release(ctx, imp)
+}
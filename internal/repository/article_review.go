@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository/dao"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+)
+
+var (
+	ErrArticleReviewNotFound = dao.ErrRecordNotFound
+	ErrArticleReviewHandled  = dao.ErrArticleReviewHandled
+)
+
+//go:generate mockgen -source=./article_review.go -package=repomocks -destination=./mocks/article_review.mock.go ArticleReviewRepository
+type ArticleReviewRepository interface {
+	// Save 同一个版本重复保存会覆盖原来的结果，返回审核记录的 ID
+	Save(ctx context.Context, r domain.ArticleReview) (int64, error)
+	// FindById 不存在返回 ErrArticleReviewNotFound
+	FindById(ctx context.Context, id int64) (domain.ArticleReview, error)
+	// ListPending 等管理员审核的，先提交的在前面
+	ListPending(ctx context.Context, offset int, limit int) ([]domain.ArticleReview, error)
+	// UpdateStatus 处理还在等审核的记录，已经处理过了返回 ErrArticleReviewHandled
+	UpdateStatus(ctx context.Context, r domain.ArticleReview) error
+}
+
+type articleReviewRepository struct {
+	dao dao.ArticleReviewDAO
+}
+
+func NewArticleReviewRepository(dao dao.ArticleReviewDAO) ArticleReviewRepository {
+	return &articleReviewRepository{dao: dao}
+}
+
+func (repo *articleReviewRepository) Save(ctx context.Context, r domain.ArticleReview) (int64, error) {
+	return repo.dao.Upsert(ctx, repo.toEntity(r))
+}
+
+func (repo *articleReviewRepository) FindById(ctx context.Context, id int64) (domain.ArticleReview, error) {
+	r, err := repo.dao.FindById(ctx, id)
+	if err != nil {
+		return domain.ArticleReview{}, err
+	}
+	return repo.toDomain(r), nil
+}
+
+func (repo *articleReviewRepository) ListPending(ctx context.Context,
+	offset int, limit int) ([]domain.ArticleReview, error) {
+	rs, err := repo.dao.ListByStatus(ctx, domain.ArticleReviewStatusPending.ToUint8(), offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(rs, func(idx int, src dao.ArticleReview) domain.ArticleReview {
+		return repo.toDomain(src)
+	}), nil
+}
+
+func (repo *articleReviewRepository) UpdateStatus(ctx context.Context, r domain.ArticleReview) error {
+	return repo.dao.UpdateStatus(ctx, r.Id, r.Status.ToUint8(), r.Reviewer, r.Reason)
+}
+
+func (repo *articleReviewRepository) toEntity(r domain.ArticleReview) dao.ArticleReview {
+	return dao.ArticleReview{
+		Id:       r.Id,
+		ArtId:    r.ArtId,
+		Revision: r.Revision,
+		AuthorId: r.Author.Id,
+		Title:    r.Title,
+		Status:   r.Status.ToUint8(),
+		Reason:   r.Reason,
+		Reviewer: r.Reviewer,
+	}
+}
+
+func (repo *articleReviewRepository) toDomain(r dao.ArticleReview) domain.ArticleReview {
+	return domain.ArticleReview{
+		Id:       r.Id,
+		ArtId:    r.ArtId,
+		Revision: r.Revision,
+		Author:   domain.Author{Id: r.AuthorId},
+		Title:    r.Title,
+		Status:   domain.ArticleReviewStatus(r.Status),
+		Reason:   r.Reason,
+		Reviewer: r.Reviewer,
+		Ctime:    time.UnixMilli(r.Ctime),
+		Utime:    time.UnixMilli(r.Utime),
+	}
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository/dao"
+	"ddd_demo/pkg/logger"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+)
+
+const (
+	// tagFirstPageSize 标签第一页缓存多少篇，请求的 limit 不超过这个数就从缓存里面切
+	tagFirstPageSize = 100
+	// tagCountsSize 缓存多少个标签的计数
+	tagCountsSize = 100
+)
+
+func (c *CachedArticleRepository) ListPubByTag(ctx context.Context,
+	tag string, cursor int64, limit int) ([]domain.Article, error) {
+	// 只有第一页走缓存，后面的页面访问的人少
+	useCache := cursor == 0 && limit <= tagFirstPageSize
+	if useCache {
+		res, err := c.cache.GetTagFirstPage(ctx, tag)
+		if err == nil {
+			return res[:min(limit, len(res))], nil
+		}
+	}
+	size := limit
+	if useCache {
+		size = tagFirstPageSize
+	}
+	arts, err := c.dao.ListPubByTag(ctx, tag, cursor, size)
+	if err != nil {
+		return nil, err
+	}
+	res := slice.Map(arts, func(idx int, src dao.PublishedArticle) domain.Article {
+		return c.toDomain(dao.Article(src))
+	})
+	if useCache {
+		// SetTagFirstPage 会把内容换成摘要，所以要复制一份
+		cached := make([]domain.Article, len(res))
+		copy(cached, res)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			er := c.cache.SetTagFirstPage(ctx, tag, cached)
+			if er != nil {
+				c.l.Error("回写标签第一页缓存失败",
+					logger.String("tag", tag), logger.Error(er))
+			}
+		}()
+	}
+	return res[:min(limit, len(res))], nil
+}
+
+func (c *CachedArticleRepository) ListTagCounts(ctx context.Context, limit int) ([]domain.TagCount, error) {
+	if limit <= tagCountsSize {
+		res, err := c.cache.GetTagCounts(ctx)
+		if err == nil {
+			return res[:min(limit, len(res))], nil
+		}
+	}
+	cnts, err := c.dao.CountPubTags(ctx, max(limit, tagCountsSize))
+	if err != nil {
+		return nil, err
+	}
+	res := slice.Map(cnts, func(idx int, src dao.TagCount) domain.TagCount {
+		return domain.TagCount{Tag: src.Name, Count: src.Cnt}
+	})
+	if limit <= tagCountsSize {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			er := c.cache.SetTagCounts(ctx, res)
+			if er != nil {
+				c.l.Error("回写标签计数缓存失败", logger.Error(er))
+			}
+		}()
+	}
+	return res[:min(limit, len(res))], nil
+}
+
+// pubTags 线上库里面文章的标签，查不到就当没有，只是少删几个缓存
+func (c *CachedArticleRepository) pubTags(ctx context.Context, id int64) []string {
+	tags, err := c.dao.GetPubTags(ctx, []int64{id})
+	if err != nil {
+		c.l.Error("查询文章标签失败",
+			logger.Int64("aid", id), logger.Error(err))
+		return nil
+	}
+	return tags[id]
+}
+
+// delTagCache 文章发表、撤回之后，这些标签的第一页和标签计数都不对了
+func (c *CachedArticleRepository) delTagCache(ctx context.Context, tags []string) {
+	err := c.cache.DelTagFirstPage(ctx, tags...)
+	if err != nil {
+		c.l.Error("删除标签第一页缓存失败",
+			logger.Error(err))
+	}
+	err = c.cache.DelTagCounts(ctx)
+	if err != nil {
+		c.l.Error("删除标签计数缓存失败", logger.Error(err))
+	}
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository/cache"
+	cachemocks "ddd_demo/internal/repository/cache/mocks"
+	"ddd_demo/internal/repository/dao"
+	daomocks "ddd_demo/internal/repository/dao/mocks"
+	"ddd_demo/pkg/logger"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestCachedArticleRepository_ListPubByTag(t *testing.T) {
+	testCases := []struct {
+		name string
+		// 回写缓存是异步的，done 关掉了才算写完
+		mock   func(ctrl *gomock.Controller, done chan struct{}) (dao.ArticleDAO, cache.ArticleCache)
+		cursor int64
+		limit  int
+
+		wantArts []domain.Article
+		wantErr  error
+	}{
+		{
+			name: "第一页命中缓存",
+			mock: func(ctrl *gomock.Controller, done chan struct{}) (dao.ArticleDAO, cache.ArticleCache) {
+				d := daomocks.NewMockArticleDAO(ctrl)
+				c := cachemocks.NewMockArticleCache(ctrl)
+				c.EXPECT().GetTagFirstPage(gomock.Any(), "go").
+					Return([]domain.Article{{Id: 3}, {Id: 2}, {Id: 1}}, nil)
+				close(done)
+				return d, c
+			},
+			limit:    2,
+			wantArts: []domain.Article{{Id: 3}, {Id: 2}},
+		},
+		{
+			name: "第一页没有缓存，多查一些回写缓存",
+			mock: func(ctrl *gomock.Controller, done chan struct{}) (dao.ArticleDAO, cache.ArticleCache) {
+				d := daomocks.NewMockArticleDAO(ctrl)
+				c := cachemocks.NewMockArticleCache(ctrl)
+				c.EXPECT().GetTagFirstPage(gomock.Any(), "go").
+					Return(nil, errors.New("key 不存在"))
+				d.EXPECT().ListPubByTag(gomock.Any(), "go", int64(0), tagFirstPageSize).
+					Return([]dao.PublishedArticle{
+						{Id: 3, Content: "内容", Tags: []string{"go"}},
+						{Id: 2, Content: "内容", Tags: []string{"go"}},
+					}, nil)
+				c.EXPECT().SetTagFirstPage(gomock.Any(), "go", gomock.Len(2)).
+					DoAndReturn(func(ctx context.Context, tag string, arts []domain.Article) error {
+						defer close(done)
+						return errors.New("mock redis 错误")
+					})
+				return d, c
+			},
+			limit: 1,
+			wantArts: []domain.Article{
+				{Id: 3, Content: "内容", Tags: []string{"go"},
+					Ctime: time.UnixMilli(0), Utime: time.UnixMilli(0)},
+			},
+		},
+		{
+			name: "后面的页不走缓存",
+			mock: func(ctrl *gomock.Controller, done chan struct{}) (dao.ArticleDAO, cache.ArticleCache) {
+				d := daomocks.NewMockArticleDAO(ctrl)
+				c := cachemocks.NewMockArticleCache(ctrl)
+				d.EXPECT().ListPubByTag(gomock.Any(), "go", int64(3), 2).
+					Return([]dao.PublishedArticle{{Id: 2}}, nil)
+				close(done)
+				return d, c
+			},
+			cursor: 3,
+			limit:  2,
+			wantArts: []domain.Article{
+				{Id: 2, Ctime: time.UnixMilli(0), Utime: time.UnixMilli(0)},
+			},
+		},
+		{
+			name: "数据库错误",
+			mock: func(ctrl *gomock.Controller, done chan struct{}) (dao.ArticleDAO, cache.ArticleCache) {
+				d := daomocks.NewMockArticleDAO(ctrl)
+				c := cachemocks.NewMockArticleCache(ctrl)
+				d.EXPECT().ListPubByTag(gomock.Any(), "go", int64(3), 2).
+					Return(nil, errors.New("mock db 错误"))
+				close(done)
+				return d, c
+			},
+			cursor:  3,
+			limit:   2,
+			wantErr: errors.New("mock db 错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			done := make(chan struct{})
+			d, c := tc.mock(ctrl, done)
+			repo := NewCachedArticleRepository(d, nil, c, logger.NewNopLogger())
+			arts, err := repo.ListPubByTag(context.Background(), "go", tc.cursor, tc.limit)
+			<-done
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantArts, arts)
+		})
+	}
+}
+
+func TestCachedArticleRepository_ListTagCounts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	d := daomocks.NewMockArticleDAO(ctrl)
+	c := cachemocks.NewMockArticleCache(ctrl)
+	done := make(chan struct{})
+	c.EXPECT().GetTagCounts(gomock.Any()).Return(nil, errors.New("key 不存在"))
+	d.EXPECT().CountPubTags(gomock.Any(), tagCountsSize).
+		Return([]dao.TagCount{{Name: "go", Cnt: 3}, {Name: "后端", Cnt: 1}}, nil)
+	c.EXPECT().SetTagCounts(gomock.Any(), []domain.TagCount{
+		{Tag: "go", Count: 3}, {Tag: "后端", Count: 1},
+	}).DoAndReturn(func(ctx context.Context, cnts []domain.TagCount) error {
+		defer close(done)
+		return nil
+	})
+	repo := NewCachedArticleRepository(d, nil, c, logger.NewNopLogger())
+	res, err := repo.ListTagCounts(context.Background(), 1)
+	<-done
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.TagCount{{Tag: "go", Count: 3}}, res)
+}
@@ -3,11 +3,17 @@ package repository
 import (
 	"context"
 	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository/cache"
+	cachemocks "ddd_demo/internal/repository/cache/mocks"
 	"ddd_demo/internal/repository/dao"
 	daomocks "ddd_demo/internal/repository/dao/mocks"
+	"ddd_demo/pkg/logger"
+	"errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"testing"
+	"time"
 )
 
 func TestCachedArticleRepository_SyncV1(t *testing.T) {
@@ -90,3 +96,123 @@ func TestCachedArticleRepository_SyncV1(t *testing.T) {
 		})
 	}
 }
+
+func TestCachedArticleRepository_WithdrawByAuthor(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) (dao.ArticleDAO, cache.ArticleCache)
+		uid     int64
+		wantErr error
+	}{
+		{
+			name: "撤回成功，删除缓存",
+			mock: func(ctrl *gomock.Controller) (dao.ArticleDAO, cache.ArticleCache) {
+				d := daomocks.NewMockArticleDAO(ctrl)
+				c := cachemocks.NewMockArticleCache(ctrl)
+				d.EXPECT().WithdrawByAuthor(gomock.Any(), int64(123)).
+					Return([]int64{1, 2}, nil)
+				c.EXPECT().DelFirstPage(gomock.Any(), int64(123)).Return(nil)
+				c.EXPECT().DelPub(gomock.Any(), int64(1), int64(2)).Return(nil)
+				d.EXPECT().GetPubTags(gomock.Any(), []int64{1, 2}).
+					Return(map[int64][]string{1: {"go"}, 2: {"后端"}}, nil)
+				c.EXPECT().DelTagFirstPage(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tags ...string) error {
+						assert.ElementsMatch(t, []string{"go", "后端"}, tags)
+						return nil
+					})
+				c.EXPECT().DelTagCounts(gomock.Any()).Return(nil)
+				return d, c
+			},
+			uid: 123,
+		},
+		{
+			name: "数据库错误",
+			mock: func(ctrl *gomock.Controller) (dao.ArticleDAO, cache.ArticleCache) {
+				d := daomocks.NewMockArticleDAO(ctrl)
+				c := cachemocks.NewMockArticleCache(ctrl)
+				d.EXPECT().WithdrawByAuthor(gomock.Any(), int64(123)).
+					Return(nil, errors.New("mock db 错误"))
+				return d, c
+			},
+			uid:     123,
+			wantErr: errors.New("mock db 错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			d, c := tc.mock(ctrl)
+			repo := NewCachedArticleRepository(d, nil, c, logger.NewNopLogger())
+			err := repo.WithdrawByAuthor(context.Background(), tc.uid)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestCachedArticleRepository_ListDue(t *testing.T) {
+	now := time.UnixMilli(time.Now().UnixMilli())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	d := daomocks.NewMockArticleDAO(ctrl)
+	d.EXPECT().ListDue(gomock.Any(), now.UnixMilli(), 10).
+		Return([]dao.Article{
+			{
+				Id:        1,
+				Title:     "标题",
+				Content:   "内容",
+				AuthorId:  123,
+				Status:    domain.ArticleStatusScheduled,
+				Revision:  3,
+				PublishAt: now.UnixMilli() - 1000,
+				Ctime:     100,
+				Utime:     200,
+			},
+		}, nil)
+	repo := NewCachedArticleRepository(d, nil, nil, logger.NewNopLogger())
+	arts, err := repo.ListDue(context.Background(), now, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Article{
+		{
+			Id:        1,
+			Title:     "标题",
+			Content:   "内容",
+			Author:    domain.Author{Id: 123},
+			Status:    domain.ArticleStatusScheduled,
+			Revision:  3,
+			PublishAt: now.Add(-time.Second),
+			Ctime:     time.UnixMilli(100),
+			Utime:     time.UnixMilli(200),
+		},
+	}, arts)
+}
+
+func TestCachedArticleRepository_Sync(t *testing.T) {
+	// 定时发表的文章被作者取消了，不能写缓存
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	publishAt := time.UnixMilli(time.Now().UnixMilli())
+	d := daomocks.NewMockArticleDAO(ctrl)
+	c := cachemocks.NewMockArticleCache(ctrl)
+	d.EXPECT().Sync(gomock.Any(), dao.Article{
+		Id:        1,
+		Title:     "标题",
+		Content:   "内容",
+		AuthorId:  123,
+		Status:    domain.ArticleStatusPublished,
+		Revision:  3,
+		PublishAt: publishAt.UnixMilli(),
+	}).Return(int64(0), dao.ErrArticleScheduleChanged)
+	d.EXPECT().GetPubTags(gomock.Any(), []int64{1}).Return(map[int64][]string{}, nil)
+	repo := NewCachedArticleRepository(d, nil, c, logger.NewNopLogger())
+	_, err := repo.Sync(context.Background(), domain.Article{
+		Id:        1,
+		Title:     "标题",
+		Content:   "内容",
+		Author:    domain.Author{Id: 123},
+		Status:    domain.ArticleStatusPublished,
+		Revision:  3,
+		PublishAt: publishAt,
+	})
+	assert.Equal(t, ErrArticleScheduleChanged, err)
+}
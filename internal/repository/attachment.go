@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository/dao"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+)
+
+var ErrAttachmentNotFound = dao.ErrRecordNotFound
+
+//go:generate mockgen -source=./attachment.go -package=repomocks -destination=./mocks/attachment.mock.go AttachmentRepository
+type AttachmentRepository interface {
+	// Save 同样的内容已经有了的话，返回原本的那个
+	Save(ctx context.Context, a domain.Attachment) (domain.Attachment, error)
+	FindByKey(ctx context.Context, key string) (domain.Attachment, error)
+	// ListStale before 之前就没有再上传过的附件，cursor 是上一页最后一个的 ID
+	ListStale(ctx context.Context, before time.Time, cursor int64, limit int) ([]domain.Attachment, error)
+	// DeleteStale 返回 false 说明在这期间又有人上传了同样的内容，不能删
+	DeleteStale(ctx context.Context, id int64, before time.Time) (bool, error)
+}
+
+// CachedAttachmentRepository 附件的内容本身就有 HTTP 缓存，元数据也只有上传和下载的时候查，暂时不需要缓存
+type CachedAttachmentRepository struct {
+	dao dao.AttachmentDAO
+}
+
+func NewCachedAttachmentRepository(dao dao.AttachmentDAO) AttachmentRepository {
+	return &CachedAttachmentRepository{dao: dao}
+}
+
+func (repo *CachedAttachmentRepository) Save(ctx context.Context, a domain.Attachment) (domain.Attachment, error) {
+	res, err := repo.dao.Upsert(ctx, repo.toEntity(a))
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	return repo.toDomain(res), nil
+}
+
+func (repo *CachedAttachmentRepository) FindByKey(ctx context.Context, key string) (domain.Attachment, error) {
+	res, err := repo.dao.FindByKey(ctx, key)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	return repo.toDomain(res), nil
+}
+
+func (repo *CachedAttachmentRepository) ListStale(ctx context.Context, before time.Time, cursor int64, limit int) ([]domain.Attachment, error) {
+	res, err := repo.dao.ListStale(ctx, before.UnixMilli(), cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(res, func(idx int, src dao.Attachment) domain.Attachment {
+		return repo.toDomain(src)
+	}), nil
+}
+
+func (repo *CachedAttachmentRepository) DeleteStale(ctx context.Context, id int64, before time.Time) (bool, error) {
+	return repo.dao.DeleteStale(ctx, id, before.UnixMilli())
+}
+
+func (repo *CachedAttachmentRepository) toEntity(a domain.Attachment) dao.Attachment {
+	return dao.Attachment{
+		Id:            a.Id,
+		Key:           a.Key,
+		Uid:           a.Uid,
+		Name:          a.Name,
+		MimeType:      a.MimeType,
+		Size:          a.Size,
+		Width:         a.Width,
+		Height:        a.Height,
+		ThumbMimeType: a.ThumbMimeType,
+	}
+}
+
+func (repo *CachedAttachmentRepository) toDomain(a dao.Attachment) domain.Attachment {
+	return domain.Attachment{
+		Id:            a.Id,
+		Key:           a.Key,
+		Uid:           a.Uid,
+		Name:          a.Name,
+		MimeType:      a.MimeType,
+		Size:          a.Size,
+		Width:         a.Width,
+		Height:        a.Height,
+		ThumbMimeType: a.ThumbMimeType,
+		Ctime:         time.UnixMilli(a.Ctime),
+		Utime:         time.UnixMilli(a.Utime),
+	}
+}
@@ -16,8 +16,27 @@ type ArticleCache interface {
 	DelFirstPage(ctx context.Context, uid int64) error
 	Get(ctx context.Context, id int64) (domain.Article, error)
 	Set(ctx context.Context, art domain.Article) error
+	Del(ctx context.Context, id int64) error
 	GetPub(ctx context.Context, id int64) (domain.Article, error)
 	SetPub(ctx context.Context, res domain.Article) error
+	DelPub(ctx context.Context, ids ...int64) error
+	// GetTagFirstPage 标签下面第一页的文章，只有摘要
+	GetTagFirstPage(ctx context.Context, tag string) ([]domain.Article, error)
+	SetTagFirstPage(ctx context.Context, tag string, arts []domain.Article) error
+	DelTagFirstPage(ctx context.Context, tags ...string) error
+	GetTagCounts(ctx context.Context) ([]domain.TagCount, error)
+	SetTagCounts(ctx context.Context, counts []domain.TagCount) error
+	DelTagCounts(ctx context.Context) error
+	// SaveDraft 自动保存草稿，返回新的版本号。draft.Version 是作者编辑的那个版本，
+	// 和已有的草稿对不上返回 ErrArticleDraftConflict。
+	// 还没有草稿的时候和 draft.Base 比较，Base 小于 0 返回 ErrArticleDraftMissing
+	SaveDraft(ctx context.Context, draft domain.ArticleDraft) (int64, error)
+	GetDraft(ctx context.Context, id int64) (domain.ArticleDraft, error)
+	DelDraft(ctx context.Context, id int64) error
+	// DraftFlushed 草稿的 version 版本已经刷到数据库了，期间又自动保存过的话草稿留着下一次再刷
+	DraftFlushed(ctx context.Context, id int64, version int64) error
+	// ListDirtyDrafts before 之前就改过、还没有刷到数据库的草稿，先改的在前面
+	ListDirtyDrafts(ctx context.Context, before time.Time, limit int) ([]int64, error)
 }
 
 type ArticleRedisCache struct {
@@ -46,6 +65,10 @@ func (a *ArticleRedisCache) Set(ctx context.Context, art domain.Article) error {
 	return a.client.Set(ctx, a.key(art.Id), val, time.Minute*10).Err()
 }
 
+func (a *ArticleRedisCache) Del(ctx context.Context, id int64) error {
+	return a.client.Del(ctx, a.key(id)).Err()
+}
+
 func (a *ArticleRedisCache) GetPub(ctx context.Context, id int64) (domain.Article, error) {
 	val, err := a.client.Get(ctx, a.pubKey(id)).Bytes()
 	if err != nil {
@@ -64,6 +87,17 @@ func (a *ArticleRedisCache) SetPub(ctx context.Context, art domain.Article) erro
 	return a.client.Set(ctx, a.pubKey(art.Id), val, time.Minute*10).Err()
 }
 
+func (a *ArticleRedisCache) DelPub(ctx context.Context, ids ...int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, a.pubKey(id))
+	}
+	return a.client.Del(ctx, keys...).Err()
+}
+
 func NewArticleRedisCache(client redis.Cmdable) ArticleCache {
 	return &ArticleRedisCache{
 		client: client,
@@ -84,7 +118,7 @@ func (a *ArticleRedisCache) GetFirstPage(ctx context.Context, uid int64) ([]doma
 
 func (a *ArticleRedisCache) SetFirstPage(ctx context.Context, uid int64, arts []domain.Article) error {
 	for i := 0; i < len(arts); i++ {
-		arts[i].Content = arts[i].Abstract()
+		arts[i] = arts[i].Brief()
 	}
 	key := a.firstKey(uid)
 	val, err := json.Marshal(arts)
@@ -94,6 +128,72 @@ func (a *ArticleRedisCache) SetFirstPage(ctx context.Context, uid int64, arts []
 	return a.client.Set(ctx, key, val, time.Minute*10).Err()
 }
 
+func (a *ArticleRedisCache) GetTagFirstPage(ctx context.Context, tag string) ([]domain.Article, error) {
+	val, err := a.client.Get(ctx, a.tagFirstKey(tag)).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	var res []domain.Article
+	err = json.Unmarshal(val, &res)
+	return res, err
+}
+
+// SetTagFirstPage 大家看到的都是同一份，所以只缓存公开的文章
+func (a *ArticleRedisCache) SetTagFirstPage(ctx context.Context, tag string, arts []domain.Article) error {
+	pub := make([]domain.Article, 0, len(arts))
+	for _, art := range arts {
+		if art.Visibility == domain.ArticleVisibilityPublic {
+			pub = append(pub, art.Brief())
+		}
+	}
+	val, err := json.Marshal(pub)
+	if err != nil {
+		return err
+	}
+	return a.client.Set(ctx, a.tagFirstKey(tag), val, time.Minute*10).Err()
+}
+
+func (a *ArticleRedisCache) DelTagFirstPage(ctx context.Context, tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		keys = append(keys, a.tagFirstKey(tag))
+	}
+	return a.client.Del(ctx, keys...).Err()
+}
+
+func (a *ArticleRedisCache) GetTagCounts(ctx context.Context) ([]domain.TagCount, error) {
+	val, err := a.client.Get(ctx, a.tagCountsKey()).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	var res []domain.TagCount
+	err = json.Unmarshal(val, &res)
+	return res, err
+}
+
+func (a *ArticleRedisCache) SetTagCounts(ctx context.Context, counts []domain.TagCount) error {
+	val, err := json.Marshal(counts)
+	if err != nil {
+		return err
+	}
+	return a.client.Set(ctx, a.tagCountsKey(), val, time.Minute*10).Err()
+}
+
+func (a *ArticleRedisCache) DelTagCounts(ctx context.Context) error {
+	return a.client.Del(ctx, a.tagCountsKey()).Err()
+}
+
+func (a *ArticleRedisCache) tagFirstKey(tag string) string {
+	return fmt.Sprintf("article:tag_first_page:%s", tag)
+}
+
+func (a *ArticleRedisCache) tagCountsKey() string {
+	return "article:tag_counts"
+}
+
 func (a *ArticleRedisCache) pubKey(id int64) string {
 	return fmt.Sprintf("article:pub:detail:%d", id)
 }
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+var (
+	//go:embed lua/save_article_draft.lua
+	luaSaveArticleDraft string
+	//go:embed lua/article_draft_flushed.lua
+	luaArticleDraftFlushed string
+
+	// ErrArticleDraftMissing 还没有草稿，要先查一下数据库里面的版本
+	ErrArticleDraftMissing = errors.New("草稿不存在")
+	// ErrArticleDraftConflict 版本号对不上
+	ErrArticleDraftConflict  = errors.New("草稿已经在别的地方修改过了")
+	ErrArticleDraftForbidden = errors.New("不是草稿的作者")
+)
+
+// articleDraftExpiration 草稿正常情况下很快就会刷到数据库，过期时间只是兜底，
+// 刷草稿的任务停了一段时间也不会丢
+const articleDraftExpiration = time.Hour * 24 * 7
+
+func (a *ArticleRedisCache) SaveDraft(ctx context.Context, draft domain.ArticleDraft) (int64, error) {
+	attachments, err := json.Marshal(draft.Attachments)
+	if err != nil {
+		return 0, err
+	}
+	utime := draft.Utime.UnixMilli()
+	res, err := a.client.Eval(ctx, luaSaveArticleDraft,
+		[]string{a.draftKey(draft.Id), a.dirtyDraftsKey()},
+		draft.Version, draft.Base, draft.Author.Id,
+		draft.Title, draft.Content, utime,
+		int64(articleDraftExpiration/time.Second), draft.Id, attachments).Int64()
+	if err != nil {
+		return 0, err
+	}
+	switch res {
+	case -1:
+		return 0, ErrArticleDraftConflict
+	case -2:
+		return 0, ErrArticleDraftMissing
+	case -3:
+		return 0, ErrArticleDraftForbidden
+	default:
+		return res, nil
+	}
+}
+
+func (a *ArticleRedisCache) GetDraft(ctx context.Context, id int64) (domain.ArticleDraft, error) {
+	data, err := a.client.HGetAll(ctx, a.draftKey(id)).Result()
+	if err != nil {
+		return domain.ArticleDraft{}, err
+	}
+	if len(data) == 0 {
+		return domain.ArticleDraft{}, ErrKeyNotExist
+	}
+	// 这几个字段都是 lua 脚本写进去的，不需要处理错误
+	uid, _ := strconv.ParseInt(data["uid"], 10, 64)
+	version, _ := strconv.ParseInt(data["version"], 10, 64)
+	base, _ := strconv.ParseInt(data["base"], 10, 64)
+	utime, _ := strconv.ParseInt(data["utime"], 10, 64)
+	var attachments []string
+	_ = json.Unmarshal([]byte(data["attachments"]), &attachments)
+	return domain.ArticleDraft{
+		Id:          id,
+		Author:      domain.Author{Id: uid},
+		Title:       data["title"],
+		Content:     data["content"],
+		Attachments: attachments,
+		Version:     version,
+		Base:        base,
+		Utime:       time.UnixMilli(utime),
+	}, nil
+}
+
+func (a *ArticleRedisCache) DelDraft(ctx context.Context, id int64) error {
+	_, err := a.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, a.draftKey(id))
+		pipe.ZRem(ctx, a.dirtyDraftsKey(), id)
+		return nil
+	})
+	return err
+}
+
+func (a *ArticleRedisCache) DraftFlushed(ctx context.Context, id int64, version int64) error {
+	return a.client.Eval(ctx, luaArticleDraftFlushed,
+		[]string{a.draftKey(id), a.dirtyDraftsKey()}, version, id, time.Now().UnixMilli()).Err()
+}
+
+func (a *ArticleRedisCache) ListDirtyDrafts(ctx context.Context, before time.Time, limit int) ([]int64, error) {
+	ids, err := a.client.ZRangeByScore(ctx, a.dirtyDraftsKey(), &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(before.UnixMilli(), 10),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		val, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, val)
+	}
+	return res, nil
+}
+
+func (a *ArticleRedisCache) draftKey(id int64) string {
+	return fmt.Sprintf("article:draft:%d", id)
+}
+
+func (a *ArticleRedisCache) dirtyDraftsKey() string {
+	return "article:draft:dirty"
+}
@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+var (
+	//go:embed lua/claim_article_import.lua
+	luaClaimArticleImport string
+	//go:embed lua/release_article_import.lua
+	luaReleaseArticleImport string
+
+	ErrArticleImportRunning = errors.New("正在导入")
+)
+
+//go:generate mockgen -source=./article_import.go -package=cachemocks -destination=./mocks/article_import.mock.go ArticleImportCache
+type ArticleImportCache interface {
+	Get(ctx context.Context, uid int64) (domain.ArticleImport, error)
+	// Set 过期之后就当作没有导入过
+	Set(ctx context.Context, imp domain.ArticleImport, expiration time.Duration) error
+	// Claim 占住 imp.Uid 的导入，同时把 imp 存下来。已经被占住了返回 ErrArticleImportRunning。
+	// timeout 之后自动释放，节点挂了也不会一直占着
+	Claim(ctx context.Context, imp domain.ArticleImport, timeout time.Duration, expiration time.Duration) error
+	// Release 释放 Claim 占住的导入，imp 要和 Claim 的时候是同一次导入
+	Release(ctx context.Context, imp domain.ArticleImport) error
+}
+
+type RedisArticleImportCache struct {
+	cmd redis.Cmdable
+}
+
+func NewRedisArticleImportCache(cmd redis.Cmdable) ArticleImportCache {
+	return &RedisArticleImportCache{cmd: cmd}
+}
+
+func (c *RedisArticleImportCache) Get(ctx context.Context, uid int64) (domain.ArticleImport, error) {
+	data, err := c.cmd.Get(ctx, c.key(uid)).Bytes()
+	if err != nil {
+		return domain.ArticleImport{}, err
+	}
+	var res domain.ArticleImport
+	err = json.Unmarshal(data, &res)
+	return res, err
+}
+
+func (c *RedisArticleImportCache) Set(ctx context.Context,
+	imp domain.ArticleImport, expiration time.Duration) error {
+	data, err := json.Marshal(imp)
+	if err != nil {
+		return err
+	}
+	return c.cmd.Set(ctx, c.key(imp.Uid), data, expiration).Err()
+}
+
+func (c *RedisArticleImportCache) Claim(ctx context.Context,
+	imp domain.ArticleImport, timeout time.Duration, expiration time.Duration) error {
+	data, err := json.Marshal(imp)
+	if err != nil {
+		return err
+	}
+	res, err := c.cmd.Eval(ctx, luaClaimArticleImport,
+		[]string{c.lockKey(imp.Uid), c.key(imp.Uid)},
+		c.owner(imp), timeout.Milliseconds(), data, expiration.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	if res == -1 {
+		return ErrArticleImportRunning
+	}
+	return nil
+}
+
+func (c *RedisArticleImportCache) Release(ctx context.Context, imp domain.ArticleImport) error {
+	return c.cmd.Eval(ctx, luaReleaseArticleImport,
+		[]string{c.lockKey(imp.Uid)}, c.owner(imp)).Err()
+}
+
+// owner 用开始导入的时间区分是哪一次导入
+func (c *RedisArticleImportCache) owner(imp domain.ArticleImport) string {
+	return strconv.FormatInt(imp.Ctime.UnixNano(), 10)
+}
+
+func (c *RedisArticleImportCache) key(uid int64) string {
+	return fmt.Sprintf("article:import:%d", uid)
+}
+
+func (c *RedisArticleImportCache) lockKey(uid int64) string {
+	return fmt.Sprintf("article:import:lock:%d", uid)
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository/cache/redismocks"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestRedisArticleImportCache_Claim(t *testing.T) {
+	imp := domain.ArticleImport{
+		Uid:    123,
+		Status: domain.ArticleImportStatusRunning,
+		Ctime:  time.Unix(100, 0),
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) redis.Cmdable
+
+		wantErr error
+	}{
+		{
+			name: "占住了",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				res := redismocks.NewMockCmdable(ctrl)
+				cmd := redis.NewCmd(context.Background())
+				cmd.SetVal(int64(0))
+				res.EXPECT().Eval(gomock.Any(), luaClaimArticleImport,
+					[]string{"article:import:lock:123", "article:import:123"},
+					"100000000000", int64(60000), gomock.Any(), int64(3600000)).
+					Return(cmd)
+				return res
+			},
+		},
+		{
+			name: "上一次导入还没有结束",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				res := redismocks.NewMockCmdable(ctrl)
+				cmd := redis.NewCmd(context.Background())
+				cmd.SetVal(int64(-1))
+				res.EXPECT().Eval(gomock.Any(), luaClaimArticleImport,
+					[]string{"article:import:lock:123", "article:import:123"},
+					gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(cmd)
+				return res
+			},
+			wantErr: ErrArticleImportRunning,
+		},
+		{
+			name: "Redis 出错",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				res := redismocks.NewMockCmdable(ctrl)
+				cmd := redis.NewCmd(context.Background())
+				cmd.SetErr(errors.New("mock redis 错误"))
+				res.EXPECT().Eval(gomock.Any(), luaClaimArticleImport,
+					[]string{"article:import:lock:123", "article:import:123"},
+					gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(cmd)
+				return res
+			},
+			wantErr: errors.New("mock redis 错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			c := NewRedisArticleImportCache(tc.mock(ctrl))
+			err := c.Claim(context.Background(), imp, time.Minute, time.Hour)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
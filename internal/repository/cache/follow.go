@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	_ "embed"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+var (
+	//go:embed lua/incr_follow_cnt.lua
+	luaIncrFollowCnt string
+)
+
+const (
+	fieldFollowerCnt = "followers"
+	fieldFolloweeCnt = "followees"
+)
+
+//go:generate mockgen -source=./follow.go -package=cachemocks -destination=./mocks/follow.mock.go FollowCache
+type FollowCache interface {
+	StaticsInfo(ctx context.Context, uid int64) (domain.FollowStatics, error)
+	SetStaticsInfo(ctx context.Context, uid int64, statics domain.FollowStatics) error
+	// Follow follower 关注了 followee，follower 的关注数和 followee 的粉丝数都加一
+	Follow(ctx context.Context, follower int64, followee int64) error
+	// CancelFollow 和 Follow 相反
+	CancelFollow(ctx context.Context, follower int64, followee int64) error
+}
+
+type RedisFollowCache struct {
+	cmd        redis.Cmdable
+	expiration time.Duration
+}
+
+func NewRedisFollowCache(cmd redis.Cmdable) FollowCache {
+	return &RedisFollowCache{
+		cmd:        cmd,
+		expiration: time.Minute * 15,
+	}
+}
+
+func (c *RedisFollowCache) StaticsInfo(ctx context.Context, uid int64) (domain.FollowStatics, error) {
+	data, err := c.cmd.HGetAll(ctx, c.staticsKey(uid)).Result()
+	if err != nil {
+		return domain.FollowStatics{}, err
+	}
+	if len(data) == 0 {
+		return domain.FollowStatics{}, ErrKeyNotExist
+	}
+	var res domain.FollowStatics
+	// 这里不需要处理错误，因为这两个字段都是我们自己写进去的
+	res.Followers, _ = strconv.ParseInt(data[fieldFollowerCnt], 10, 64)
+	res.Followees, _ = strconv.ParseInt(data[fieldFolloweeCnt], 10, 64)
+	return res, nil
+}
+
+func (c *RedisFollowCache) SetStaticsInfo(ctx context.Context, uid int64, statics domain.FollowStatics) error {
+	key := c.staticsKey(uid)
+	err := c.cmd.HMSet(ctx, key,
+		fieldFollowerCnt, statics.Followers,
+		fieldFolloweeCnt, statics.Followees).Err()
+	if err != nil {
+		return err
+	}
+	return c.cmd.Expire(ctx, key, c.expiration).Err()
+}
+
+func (c *RedisFollowCache) Follow(ctx context.Context, follower int64, followee int64) error {
+	return c.updateStaticsInfo(ctx, follower, followee, 1)
+}
+
+func (c *RedisFollowCache) CancelFollow(ctx context.Context, follower int64, followee int64) error {
+	return c.updateStaticsInfo(ctx, follower, followee, -1)
+}
+
+func (c *RedisFollowCache) updateStaticsInfo(ctx context.Context,
+	follower int64, followee int64, delta int64) error {
+	err := c.cmd.Eval(ctx, luaIncrFollowCnt,
+		[]string{c.staticsKey(follower)}, fieldFolloweeCnt, delta).Err()
+	if err != nil {
+		return err
+	}
+	return c.cmd.Eval(ctx, luaIncrFollowCnt,
+		[]string{c.staticsKey(followee)}, fieldFollowerCnt, delta).Err()
+}
+
+func (c *RedisFollowCache) staticsKey(uid int64) string {
+	return fmt.Sprintf("follow:statics:%d", uid)
+}
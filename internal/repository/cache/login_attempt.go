@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"strings"
+	"time"
+)
+
+var (
+	//go:embed lua/check_login.lua
+	luaCheckLogin string
+	//go:embed lua/login_fail.lua
+	luaLoginFail string
+
+	ErrAccountLocked = errors.New("账号已经被锁定")
+)
+
+// LoginAttemptConfig 登录失败的处理策略
+type LoginAttemptConfig struct {
+	// 失败次数在这个时间窗口内累计
+	Window time.Duration `yaml:"window"`
+	// 连续失败 DelayAfter 次之后，每次都要等待一段时间才能重试，
+	// 等待时间从 BaseDelay 开始翻倍，最多等待 MaxDelay
+	DelayAfter int           `yaml:"delayAfter"`
+	BaseDelay  time.Duration `yaml:"baseDelay"`
+	MaxDelay   time.Duration `yaml:"maxDelay"`
+	// 连续失败 LockAfter 次之后，锁定账号 LockDuration
+	LockAfter    int           `yaml:"lockAfter"`
+	LockDuration time.Duration `yaml:"lockDuration"`
+	// IP 同一个 IP 的失败次数，用来对付拿一个密码去试很多账号的情况
+	IP IPAttemptConfig `yaml:"ip"`
+}
+
+// IPAttemptConfig 同一个 IP 连续失败 DelayAfter 次之后，等待时间从 BaseDelay 开始翻倍。
+// 同一个出口 IP 后面可能有很多用户，所以只延迟，不锁定
+type IPAttemptConfig struct {
+	DelayAfter int           `yaml:"delayAfter"`
+	BaseDelay  time.Duration `yaml:"baseDelay"`
+	MaxDelay   time.Duration `yaml:"maxDelay"`
+}
+
+//go:generate mockgen -source=./login_attempt.go -package=cachemocks -destination=./mocks/login_attempt.mock.go LoginAttemptCache
+type LoginAttemptCache interface {
+	// Check 登录之前检查，返回还需要等待多久才能重试。账号被锁定的话返回 ErrAccountLocked
+	Check(ctx context.Context, email string) (time.Duration, error)
+	// Fail 记录一次失败，返回这次失败之后账号是否被锁定了
+	Fail(ctx context.Context, email string) (bool, error)
+	// Reset 清空失败次数，并且解锁账号
+	Reset(ctx context.Context, email string) error
+	// CheckIP 返回这个 IP 还需要等待多久才能重试
+	CheckIP(ctx context.Context, ip string) (time.Duration, error)
+	// FailIP 记录这个 IP 的一次失败
+	FailIP(ctx context.Context, ip string) error
+}
+
+type RedisLoginAttemptCache struct {
+	cmd redis.Cmdable
+	cfg LoginAttemptConfig
+}
+
+func NewLoginAttemptCache(cmd redis.Cmdable, cfg LoginAttemptConfig) LoginAttemptCache {
+	return &RedisLoginAttemptCache{
+		cmd: cmd,
+		cfg: cfg,
+	}
+}
+
+func (c *RedisLoginAttemptCache) Check(ctx context.Context, email string) (time.Duration, error) {
+	return c.check(ctx, c.key(email), c.lockKey(email),
+		c.cfg.DelayAfter, c.cfg.BaseDelay, c.cfg.MaxDelay)
+}
+
+func (c *RedisLoginAttemptCache) CheckIP(ctx context.Context, ip string) (time.Duration, error) {
+	// IP 不会被锁定，锁定的 key 永远不存在
+	return c.check(ctx, c.ipKey(ip), c.ipKey(ip)+":lock",
+		c.cfg.IP.DelayAfter, c.cfg.IP.BaseDelay, c.cfg.IP.MaxDelay)
+}
+
+func (c *RedisLoginAttemptCache) check(ctx context.Context, key, lockKey string,
+	delayAfter int, baseDelay, maxDelay time.Duration) (time.Duration, error) {
+	res, err := c.cmd.Eval(ctx, luaCheckLogin,
+		[]string{key, lockKey},
+		time.Now().UnixMilli(), delayAfter,
+		baseDelay.Milliseconds(), maxDelay.Milliseconds()).Int64()
+	if err != nil {
+		return 0, err
+	}
+	if res < 0 {
+		return 0, ErrAccountLocked
+	}
+	return time.Duration(res) * time.Millisecond, nil
+}
+
+func (c *RedisLoginAttemptCache) Fail(ctx context.Context, email string) (bool, error) {
+	return c.cmd.Eval(ctx, luaLoginFail,
+		[]string{c.key(email), c.lockKey(email)},
+		time.Now().UnixMilli(), c.cfg.Window.Milliseconds(),
+		c.cfg.LockAfter, c.cfg.LockDuration.Milliseconds()).Bool()
+}
+
+func (c *RedisLoginAttemptCache) FailIP(ctx context.Context, ip string) error {
+	// lockAfter 传 0，不锁定
+	return c.cmd.Eval(ctx, luaLoginFail,
+		[]string{c.ipKey(ip), c.ipKey(ip) + ":lock"},
+		time.Now().UnixMilli(), c.cfg.Window.Milliseconds(), 0, 0).Err()
+}
+
+func (c *RedisLoginAttemptCache) Reset(ctx context.Context, email string) error {
+	return c.cmd.Del(ctx, c.key(email), c.lockKey(email)).Err()
+}
+
+func (c *RedisLoginAttemptCache) key(email string) string {
+	return fmt.Sprintf("login_attempt:%s", normalizeEmail(email))
+}
+
+func (c *RedisLoginAttemptCache) lockKey(email string) string {
+	return fmt.Sprintf("login_attempt:lock:%s", normalizeEmail(email))
+}
+
+func (c *RedisLoginAttemptCache) ipKey(ip string) string {
+	return fmt.Sprintf("login_attempt:ip:%s", ip)
+}
+
+// normalizeEmail 邮箱不区分大小写，不然换个大小写就能绕过失败次数的限制
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
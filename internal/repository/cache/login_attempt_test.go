@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"context"
+	"ddd_demo/internal/repository/cache/redismocks"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestRedisLoginAttemptCache_Check(t *testing.T) {
+	testCases := []struct {
+		name  string
+		mock  func(ctrl *gomock.Controller) redis.Cmdable
+		email string
+
+		wantWait time.Duration
+		wantErr  error
+	}{
+		{
+			// 换个大小写、加个空格，还是同一个账号
+			name: "邮箱大小写不敏感",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				res := redismocks.NewMockCmdable(ctrl)
+				cmd := redis.NewCmd(context.Background())
+				cmd.SetVal(int64(1500))
+				res.EXPECT().Eval(gomock.Any(), luaCheckLogin,
+					[]string{"login_attempt:abc@qq.com", "login_attempt:lock:abc@qq.com"},
+					gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(cmd)
+				return res
+			},
+			email:    " ABC@qq.COM ",
+			wantWait: time.Millisecond * 1500,
+		},
+		{
+			name: "账号被锁定",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				res := redismocks.NewMockCmdable(ctrl)
+				cmd := redis.NewCmd(context.Background())
+				cmd.SetVal(int64(-1))
+				res.EXPECT().Eval(gomock.Any(), luaCheckLogin,
+					[]string{"login_attempt:abc@qq.com", "login_attempt:lock:abc@qq.com"},
+					gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(cmd)
+				return res
+			},
+			email:   "abc@qq.com",
+			wantErr: ErrAccountLocked,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			c := NewLoginAttemptCache(tc.mock(ctrl), LoginAttemptConfig{})
+			wait, err := c.Check(context.Background(), tc.email)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantWait, wait)
+		})
+	}
+}
+
+func TestRedisLoginAttemptCache_FailIP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cmdable := redismocks.NewMockCmdable(ctrl)
+	cmd := redis.NewCmd(context.Background())
+	cmd.SetVal(int64(0))
+	// lockAfter 是 0，IP 永远不会被锁定
+	cmdable.EXPECT().Eval(gomock.Any(), luaLoginFail,
+		[]string{"login_attempt:ip:1.2.3.4", "login_attempt:ip:1.2.3.4:lock"},
+		gomock.Any(), int64(time.Minute*15/time.Millisecond), 0, 0).
+		Return(cmd)
+	c := NewLoginAttemptCache(cmdable, LoginAttemptConfig{Window: time.Minute * 15})
+	assert.NoError(t, c.FailIP(context.Background(), "1.2.3.4"))
+}
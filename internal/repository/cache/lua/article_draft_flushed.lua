@@ -0,0 +1,16 @@
+-- 草稿刷到数据库之后调用，KEYS 和 save_article_draft.lua 一样
+local key = KEYS[1]
+-- 刷进去的那个版本
+local version = ARGV[1]
+
+local cur = redis.call("HGET", key, "version")
+if cur == false or cur == version then
+    redis.call("DEL", key)
+    redis.call("ZREM", KEYS[2], ARGV[2])
+    return 1
+end
+-- 刷的过程中作者又自动保存了，草稿留着下一次再刷，数据库里面已经是刷进去的版本了。
+-- 变脏的时间也改成现在，不然这一轮会一直刷它
+redis.call("HSET", key, "base", version)
+redis.call("ZADD", KEYS[2], ARGV[3], ARGV[2])
+return 0
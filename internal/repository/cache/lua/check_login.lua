@@ -0,0 +1,33 @@
+-- 登录失败的计数，hash 结构，cnt 是失败次数，last 是最后一次失败的时间
+local key = KEYS[1]
+-- 账号锁定的 key
+local lockKey = KEYS[2]
+local now = tonumber(ARGV[1])
+-- 失败多少次之后开始要求等待
+local delayAfter = tonumber(ARGV[2])
+-- 毫秒
+local baseDelay = tonumber(ARGV[3])
+local maxDelay = tonumber(ARGV[4])
+
+if redis.call("exists", lockKey) == 1 then
+    -- 账号被锁定了
+    return -1
+end
+
+local vals = redis.call("hmget", key, "cnt", "last")
+local cnt = tonumber(vals[1]) or 0
+if cnt < delayAfter then
+    return 0
+end
+local last = tonumber(vals[2]) or 0
+-- 每多失败一次，等待时间翻倍
+local delay = baseDelay * math.pow(2, cnt - delayAfter)
+if delay > maxDelay then
+    delay = maxDelay
+end
+local wait = last + delay - now
+if wait > 0 then
+    -- 返回还要等待多少毫秒
+    return math.ceil(wait)
+end
+return 0
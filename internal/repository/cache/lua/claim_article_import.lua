@@ -0,0 +1,13 @@
+-- 占住导入的 key，同一个用户同时只能有一个导入
+local lock = KEYS[1]
+-- 导入进度的 key
+local key = KEYS[2]
+-- 占住的是哪一次导入，释放的时候要对得上
+local owner = ARGV[1]
+
+if not redis.call("SET", lock, owner, "NX", "PX", ARGV[2]) then
+    -- 上一次导入还没有结束
+    return -1
+end
+redis.call("SET", key, ARGV[3], "PX", ARGV[4])
+return 0
@@ -0,0 +1,11 @@
+-- 一次性 token 的 key
+local key = KEYS[1]
+
+local val = redis.call("get", key)
+if not val then
+    -- 不存在，或者已经用过了，或者过期了
+    return false
+end
+-- 只能用一次
+redis.call("del", key)
+return val
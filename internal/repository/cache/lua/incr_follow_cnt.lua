@@ -0,0 +1,13 @@
+-- 缓存存在的时候才更新，不存在就等下一次查询的时候从数据库加载
+local key = KEYS[1]
+-- followers 还是 followees
+local cntKey = ARGV[1]
+local delta = tonumber(ARGV[2])
+
+local exist = redis.call("EXISTS", key)
+if exist == 1 then
+    redis.call("HINCRBY", key, cntKey, delta)
+    return 1
+else
+    return 0
+end
@@ -0,0 +1,19 @@
+local key = KEYS[1]
+local lockKey = KEYS[2]
+local now = tonumber(ARGV[1])
+-- 统计失败次数的窗口，毫秒
+local window = tonumber(ARGV[2])
+-- 失败多少次之后锁定账号，0 表示不锁定
+local lockAfter = tonumber(ARGV[3])
+local lockDuration = tonumber(ARGV[4])
+
+local cnt = redis.call("hincrby", key, "cnt", 1)
+redis.call("hset", key, "last", now)
+redis.call("pexpire", key, window)
+if lockAfter > 0 and cnt >= lockAfter then
+    redis.call("set", lockKey, cnt, "PX", lockDuration)
+    -- 解锁之后重新计数
+    redis.call("del", key)
+    return 1
+end
+return 0
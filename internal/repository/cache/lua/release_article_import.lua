@@ -0,0 +1,9 @@
+-- 占住导入的 key，和 claim_article_import.lua 一样
+local lock = KEYS[1]
+
+-- 超时之后可能已经被下一次导入占住了，只释放自己占住的
+if redis.call("GET", lock) == ARGV[1] then
+    redis.call("DEL", lock)
+    return 1
+end
+return 0
@@ -0,0 +1,31 @@
+-- 自动保存草稿。KEYS[1] 是草稿，KEYS[2] 是还没有刷到数据库的草稿的集合
+local key = KEYS[1]
+-- 作者编辑的那个版本
+local version = tonumber(ARGV[1])
+-- 数据库里面的版本，小于 0 表示调用方还没有查数据库
+local base = tonumber(ARGV[2])
+local uid = ARGV[3]
+
+local cur = redis.call("HMGET", key, "version", "uid")
+if cur[1] == false then
+    if base < 0 then
+        -- 还没有草稿，调用方查了数据库再来
+        return -2
+    end
+    if base ~= version then
+        return -1
+    end
+    redis.call("HSET", key, "base", base)
+elseif cur[2] ~= uid then
+    -- 不是作者
+    return -3
+elseif tonumber(cur[1]) ~= version then
+    -- 别的标签页或者设备已经自动保存过了
+    return -1
+end
+redis.call("HSET", key, "version", version + 1, "uid", uid,
+        "title", ARGV[4], "content", ARGV[5], "utime", ARGV[6], "attachments", ARGV[9])
+redis.call("EXPIRE", key, ARGV[7])
+-- 分数是第一次变脏的时间，刷到数据库之前不会变
+redis.call("ZADD", KEYS[2], "NX", ARGV[6], ARGV[8])
+return version + 1
@@ -0,0 +1,25 @@
+-- 一次性 token 的 key
+local key = KEYS[1]
+-- 记录某个主体（比如某个用户）最新的 token 的 key
+local subjectKey = KEYS[2]
+-- token 对应的值
+local val = ARGV[1]
+-- 过期时间，秒
+local ttl = tonumber(ARGV[2])
+-- 两次发放 token 的最小间隔，秒
+local interval = tonumber(ARGV[3])
+
+local subjectTTL = tonumber(redis.call("ttl", subjectKey))
+if subjectTTL > 0 and subjectTTL > ttl - interval then
+    -- 发放太频繁
+    return -1
+end
+
+local old = redis.call("get", subjectKey)
+if old then
+    -- 之前发出去的 token 作废，只有最新的那个有效
+    redis.call("del", old)
+end
+redis.call("set", key, val, "EX", ttl)
+redis.call("set", subjectKey, key, "EX", ttl)
+return 0
@@ -11,6 +11,7 @@ package cachemocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "ddd_demo/internal/domain"
 	gomock "go.uber.org/mock/gomock"
@@ -39,6 +40,34 @@ func (m *MockArticleCache) EXPECT() *MockArticleCacheMockRecorder {
 	return m.recorder
 }
 
+// Del mocks base method.
+func (m *MockArticleCache) Del(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Del", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Del indicates an expected call of Del.
+func (mr *MockArticleCacheMockRecorder) Del(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockArticleCache)(nil).Del), ctx, id)
+}
+
+// DelDraft mocks base method.
+func (m *MockArticleCache) DelDraft(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DelDraft", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DelDraft indicates an expected call of DelDraft.
+func (mr *MockArticleCacheMockRecorder) DelDraft(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DelDraft", reflect.TypeOf((*MockArticleCache)(nil).DelDraft), ctx, id)
+}
+
 // DelFirstPage mocks base method.
 func (m *MockArticleCache) DelFirstPage(ctx context.Context, uid int64) error {
 	m.ctrl.T.Helper()
@@ -53,6 +82,72 @@ func (mr *MockArticleCacheMockRecorder) DelFirstPage(ctx, uid any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DelFirstPage", reflect.TypeOf((*MockArticleCache)(nil).DelFirstPage), ctx, uid)
 }
 
+// DelPub mocks base method.
+func (m *MockArticleCache) DelPub(ctx context.Context, ids ...int64) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DelPub", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DelPub indicates an expected call of DelPub.
+func (mr *MockArticleCacheMockRecorder) DelPub(ctx any, ids ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DelPub", reflect.TypeOf((*MockArticleCache)(nil).DelPub), varargs...)
+}
+
+// DelTagCounts mocks base method.
+func (m *MockArticleCache) DelTagCounts(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DelTagCounts", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DelTagCounts indicates an expected call of DelTagCounts.
+func (mr *MockArticleCacheMockRecorder) DelTagCounts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DelTagCounts", reflect.TypeOf((*MockArticleCache)(nil).DelTagCounts), ctx)
+}
+
+// DelTagFirstPage mocks base method.
+func (m *MockArticleCache) DelTagFirstPage(ctx context.Context, tags ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range tags {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DelTagFirstPage", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DelTagFirstPage indicates an expected call of DelTagFirstPage.
+func (mr *MockArticleCacheMockRecorder) DelTagFirstPage(ctx any, tags ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, tags...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DelTagFirstPage", reflect.TypeOf((*MockArticleCache)(nil).DelTagFirstPage), varargs...)
+}
+
+// DraftFlushed mocks base method.
+func (m *MockArticleCache) DraftFlushed(ctx context.Context, id, version int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DraftFlushed", ctx, id, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DraftFlushed indicates an expected call of DraftFlushed.
+func (mr *MockArticleCacheMockRecorder) DraftFlushed(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DraftFlushed", reflect.TypeOf((*MockArticleCache)(nil).DraftFlushed), ctx, id, version)
+}
+
 // Get mocks base method.
 func (m *MockArticleCache) Get(ctx context.Context, id int64) (domain.Article, error) {
 	m.ctrl.T.Helper()
@@ -68,6 +163,21 @@ func (mr *MockArticleCacheMockRecorder) Get(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockArticleCache)(nil).Get), ctx, id)
 }
 
+// GetDraft mocks base method.
+func (m *MockArticleCache) GetDraft(ctx context.Context, id int64) (domain.ArticleDraft, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDraft", ctx, id)
+	ret0, _ := ret[0].(domain.ArticleDraft)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDraft indicates an expected call of GetDraft.
+func (mr *MockArticleCacheMockRecorder) GetDraft(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDraft", reflect.TypeOf((*MockArticleCache)(nil).GetDraft), ctx, id)
+}
+
 // GetFirstPage mocks base method.
 func (m *MockArticleCache) GetFirstPage(ctx context.Context, uid int64) ([]domain.Article, error) {
 	m.ctrl.T.Helper()
@@ -98,6 +208,66 @@ func (mr *MockArticleCacheMockRecorder) GetPub(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPub", reflect.TypeOf((*MockArticleCache)(nil).GetPub), ctx, id)
 }
 
+// GetTagCounts mocks base method.
+func (m *MockArticleCache) GetTagCounts(ctx context.Context) ([]domain.TagCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagCounts", ctx)
+	ret0, _ := ret[0].([]domain.TagCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagCounts indicates an expected call of GetTagCounts.
+func (mr *MockArticleCacheMockRecorder) GetTagCounts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagCounts", reflect.TypeOf((*MockArticleCache)(nil).GetTagCounts), ctx)
+}
+
+// GetTagFirstPage mocks base method.
+func (m *MockArticleCache) GetTagFirstPage(ctx context.Context, tag string) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagFirstPage", ctx, tag)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagFirstPage indicates an expected call of GetTagFirstPage.
+func (mr *MockArticleCacheMockRecorder) GetTagFirstPage(ctx, tag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagFirstPage", reflect.TypeOf((*MockArticleCache)(nil).GetTagFirstPage), ctx, tag)
+}
+
+// ListDirtyDrafts mocks base method.
+func (m *MockArticleCache) ListDirtyDrafts(ctx context.Context, before time.Time, limit int) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDirtyDrafts", ctx, before, limit)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDirtyDrafts indicates an expected call of ListDirtyDrafts.
+func (mr *MockArticleCacheMockRecorder) ListDirtyDrafts(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDirtyDrafts", reflect.TypeOf((*MockArticleCache)(nil).ListDirtyDrafts), ctx, before, limit)
+}
+
+// SaveDraft mocks base method.
+func (m *MockArticleCache) SaveDraft(ctx context.Context, draft domain.ArticleDraft) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDraft", ctx, draft)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveDraft indicates an expected call of SaveDraft.
+func (mr *MockArticleCacheMockRecorder) SaveDraft(ctx, draft any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDraft", reflect.TypeOf((*MockArticleCache)(nil).SaveDraft), ctx, draft)
+}
+
 // Set mocks base method.
 func (m *MockArticleCache) Set(ctx context.Context, art domain.Article) error {
 	m.ctrl.T.Helper()
@@ -139,3 +309,31 @@ func (mr *MockArticleCacheMockRecorder) SetPub(ctx, res any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPub", reflect.TypeOf((*MockArticleCache)(nil).SetPub), ctx, res)
 }
+
+// SetTagCounts mocks base method.
+func (m *MockArticleCache) SetTagCounts(ctx context.Context, counts []domain.TagCount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTagCounts", ctx, counts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTagCounts indicates an expected call of SetTagCounts.
+func (mr *MockArticleCacheMockRecorder) SetTagCounts(ctx, counts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTagCounts", reflect.TypeOf((*MockArticleCache)(nil).SetTagCounts), ctx, counts)
+}
+
+// SetTagFirstPage mocks base method.
+func (m *MockArticleCache) SetTagFirstPage(ctx context.Context, tag string, arts []domain.Article) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTagFirstPage", ctx, tag, arts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTagFirstPage indicates an expected call of SetTagFirstPage.
+func (mr *MockArticleCacheMockRecorder) SetTagFirstPage(ctx, tag, arts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTagFirstPage", reflect.TypeOf((*MockArticleCache)(nil).SetTagFirstPage), ctx, tag, arts)
+}
@@ -0,0 +1,98 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./article_import.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./article_import.go -package=cachemocks -destination=./mocks/article_import.mock.go ArticleImportCache
+//
+// Package cachemocks is a generated GoMock package.
+package cachemocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "ddd_demo/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockArticleImportCache is a mock of ArticleImportCache interface.
+type MockArticleImportCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockArticleImportCacheMockRecorder
+}
+
+// MockArticleImportCacheMockRecorder is the mock recorder for MockArticleImportCache.
+type MockArticleImportCacheMockRecorder struct {
+	mock *MockArticleImportCache
+}
+
+// NewMockArticleImportCache creates a new mock instance.
+func NewMockArticleImportCache(ctrl *gomock.Controller) *MockArticleImportCache {
+	mock := &MockArticleImportCache{ctrl: ctrl}
+	mock.recorder = &MockArticleImportCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArticleImportCache) EXPECT() *MockArticleImportCacheMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockArticleImportCache) Claim(ctx context.Context, imp domain.ArticleImport, timeout, expiration time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, imp, timeout, expiration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockArticleImportCacheMockRecorder) Claim(ctx, imp, timeout, expiration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockArticleImportCache)(nil).Claim), ctx, imp, timeout, expiration)
+}
+
+// Get mocks base method.
+func (m *MockArticleImportCache) Get(ctx context.Context, uid int64) (domain.ArticleImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, uid)
+	ret0, _ := ret[0].(domain.ArticleImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockArticleImportCacheMockRecorder) Get(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockArticleImportCache)(nil).Get), ctx, uid)
+}
+
+// Release mocks base method.
+func (m *MockArticleImportCache) Release(ctx context.Context, imp domain.ArticleImport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, imp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockArticleImportCacheMockRecorder) Release(ctx, imp any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockArticleImportCache)(nil).Release), ctx, imp)
+}
+
+// Set mocks base method.
+func (m *MockArticleImportCache) Set(ctx context.Context, imp domain.ArticleImport, expiration time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, imp, expiration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockArticleImportCacheMockRecorder) Set(ctx, imp, expiration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockArticleImportCache)(nil).Set), ctx, imp, expiration)
+}
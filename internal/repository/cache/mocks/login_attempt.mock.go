@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./login_attempt.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./login_attempt.go -package=cachemocks -destination=./mocks/login_attempt.mock.go LoginAttemptCache
+//
+// Package cachemocks is a generated GoMock package.
+package cachemocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLoginAttemptCache is a mock of LoginAttemptCache interface.
+type MockLoginAttemptCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginAttemptCacheMockRecorder
+}
+
+// MockLoginAttemptCacheMockRecorder is the mock recorder for MockLoginAttemptCache.
+type MockLoginAttemptCacheMockRecorder struct {
+	mock *MockLoginAttemptCache
+}
+
+// NewMockLoginAttemptCache creates a new mock instance.
+func NewMockLoginAttemptCache(ctrl *gomock.Controller) *MockLoginAttemptCache {
+	mock := &MockLoginAttemptCache{ctrl: ctrl}
+	mock.recorder = &MockLoginAttemptCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginAttemptCache) EXPECT() *MockLoginAttemptCacheMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockLoginAttemptCache) Check(ctx context.Context, email string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, email)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockLoginAttemptCacheMockRecorder) Check(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockLoginAttemptCache)(nil).Check), ctx, email)
+}
+
+// CheckIP mocks base method.
+func (m *MockLoginAttemptCache) CheckIP(ctx context.Context, ip string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckIP", ctx, ip)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckIP indicates an expected call of CheckIP.
+func (mr *MockLoginAttemptCacheMockRecorder) CheckIP(ctx, ip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIP", reflect.TypeOf((*MockLoginAttemptCache)(nil).CheckIP), ctx, ip)
+}
+
+// Fail mocks base method.
+func (m *MockLoginAttemptCache) Fail(ctx context.Context, email string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fail", ctx, email)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fail indicates an expected call of Fail.
+func (mr *MockLoginAttemptCacheMockRecorder) Fail(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fail", reflect.TypeOf((*MockLoginAttemptCache)(nil).Fail), ctx, email)
+}
+
+// FailIP mocks base method.
+func (m *MockLoginAttemptCache) FailIP(ctx context.Context, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailIP", ctx, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailIP indicates an expected call of FailIP.
+func (mr *MockLoginAttemptCacheMockRecorder) FailIP(ctx, ip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailIP", reflect.TypeOf((*MockLoginAttemptCache)(nil).FailIP), ctx, ip)
+}
+
+// Reset mocks base method.
+func (m *MockLoginAttemptCache) Reset(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockLoginAttemptCacheMockRecorder) Reset(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockLoginAttemptCache)(nil).Reset), ctx, email)
+}
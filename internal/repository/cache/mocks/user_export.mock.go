@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./user_export.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./user_export.go -package=cachemocks -destination=./mocks/user_export.mock.go
+//
+// Package cachemocks is a generated GoMock package.
+package cachemocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "ddd_demo/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserExportCache is a mock of UserExportCache interface.
+type MockUserExportCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserExportCacheMockRecorder
+}
+
+// MockUserExportCacheMockRecorder is the mock recorder for MockUserExportCache.
+type MockUserExportCacheMockRecorder struct {
+	mock *MockUserExportCache
+}
+
+// NewMockUserExportCache creates a new mock instance.
+func NewMockUserExportCache(ctrl *gomock.Controller) *MockUserExportCache {
+	mock := &MockUserExportCache{ctrl: ctrl}
+	mock.recorder = &MockUserExportCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserExportCache) EXPECT() *MockUserExportCacheMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockUserExportCache) Get(ctx context.Context, uid int64) (domain.UserExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, uid)
+	ret0, _ := ret[0].(domain.UserExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockUserExportCacheMockRecorder) Get(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUserExportCache)(nil).Get), ctx, uid)
+}
+
+// Set mocks base method.
+func (m *MockUserExportCache) Set(ctx context.Context, exp domain.UserExport, expiration time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, exp, expiration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockUserExportCacheMockRecorder) Set(ctx, exp, expiration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockUserExportCache)(nil).Set), ctx, exp, expiration)
+}
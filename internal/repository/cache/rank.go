@@ -21,7 +21,7 @@ type RankingRedisCache struct {
 
 func (r *RankingRedisCache) Set(ctx context.Context, arts []domain.Article) error {
 	for i := range arts {
-		arts[i].Content = arts[i].Abstract()
+		arts[i] = arts[i].Brief()
 	}
 	val, err := json.Marshal(arts)
 	if err != nil {
@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+var (
+	//go:embed lua/set_token.lua
+	luaSetToken string
+	//go:embed lua/consume_token.lua
+	luaConsumeToken string
+
+	ErrTokenSendTooMany = errors.New("发放 token 太频繁")
+	ErrTokenNotFound    = errors.New("token 不存在或者已经使用过了")
+)
+
+// OneTimeTokenCache 一次性 token，有过期时间，只能用一次
+// 比如说重置密码、验证邮箱的链接里面带的 token
+type OneTimeTokenCache interface {
+	// Set 为 subject 发放一个 token，之前发给 subject 的 token 会失效
+	Set(ctx context.Context, biz, subject, token, val string, expiration time.Duration) error
+	// Consume 用掉 token，返回 Set 时候的 val
+	Consume(ctx context.Context, biz, token string) (string, error)
+}
+
+type RedisOneTimeTokenCache struct {
+	cmd redis.Cmdable
+	// 同一个 subject 两次发放 token 的最小间隔
+	interval time.Duration
+}
+
+func NewOneTimeTokenCache(cmd redis.Cmdable) OneTimeTokenCache {
+	return &RedisOneTimeTokenCache{
+		cmd:      cmd,
+		interval: time.Minute,
+	}
+}
+
+func (c *RedisOneTimeTokenCache) Set(ctx context.Context,
+	biz, subject, token, val string, expiration time.Duration) error {
+	res, err := c.cmd.Eval(ctx, luaSetToken,
+		[]string{c.key(biz, token), c.subjectKey(biz, subject)},
+		val, int64(expiration.Seconds()), int64(c.interval.Seconds())).Int()
+	if err != nil {
+		return err
+	}
+	if res == -1 {
+		return ErrTokenSendTooMany
+	}
+	return nil
+}
+
+func (c *RedisOneTimeTokenCache) Consume(ctx context.Context, biz, token string) (string, error) {
+	val, err := c.cmd.Eval(ctx, luaConsumeToken, []string{c.key(biz, token)}).Text()
+	if err == redis.Nil {
+		return "", ErrTokenNotFound
+	}
+	return val, err
+}
+
+func (c *RedisOneTimeTokenCache) key(biz, token string) string {
+	return fmt.Sprintf("one_time_token:%s:%s", biz, token)
+}
+
+func (c *RedisOneTimeTokenCache) subjectKey(biz, subject string) string {
+	return fmt.Sprintf("one_time_token:%s:subject:%s", biz, subject)
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"encoding/json"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+//go:generate mockgen -source=./user_export.go -package=cachemocks -destination=./mocks/user_export.mock.go UserExportCache
+type UserExportCache interface {
+	Get(ctx context.Context, uid int64) (domain.UserExport, error)
+	// Set 过期之后就当作没有导出过
+	Set(ctx context.Context, exp domain.UserExport, expiration time.Duration) error
+}
+
+type RedisUserExportCache struct {
+	cmd redis.Cmdable
+}
+
+func NewRedisUserExportCache(cmd redis.Cmdable) UserExportCache {
+	return &RedisUserExportCache{cmd: cmd}
+}
+
+func (c *RedisUserExportCache) Get(ctx context.Context, uid int64) (domain.UserExport, error) {
+	data, err := c.cmd.Get(ctx, c.key(uid)).Bytes()
+	if err != nil {
+		return domain.UserExport{}, err
+	}
+	var res domain.UserExport
+	err = json.Unmarshal(data, &res)
+	return res, err
+}
+
+func (c *RedisUserExportCache) Set(ctx context.Context,
+	exp domain.UserExport, expiration time.Duration) error {
+	data, err := json.Marshal(exp)
+	if err != nil {
+		return err
+	}
+	return c.cmd.Set(ctx, c.key(exp.Uid), data, expiration).Err()
+}
+
+func (c *RedisUserExportCache) key(uid int64) string {
+	return fmt.Sprintf("user:export:%d", uid)
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository/dao"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+)
+
+var ErrCommentNotFound = dao.ErrRecordNotFound
+
+//go:generate mockgen -source=./comment.go -package=repomocks -destination=./mocks/comment.mock.go CommentRepository
+type CommentRepository interface {
+	CreateComment(ctx context.Context, c domain.Comment) (int64, error)
+	// FindById 不存在返回 ErrCommentNotFound
+	FindById(ctx context.Context, id int64) (domain.Comment, error)
+	// FindByBiz 资源下面的顶级评论
+	FindByBiz(ctx context.Context, biz string, bizId int64,
+		sort domain.CommentSort, cursor domain.CommentCursor, limit int) ([]domain.Comment, error)
+	// FindReplies 顶级评论下面的回复，先回复的在前面，cursor 是上一页最后一条的 Id
+	FindReplies(ctx context.Context, rootId int64, cursor int64, limit int) ([]domain.Comment, error)
+	// DeleteComment 返回一共删掉了几条评论
+	DeleteComment(ctx context.Context, c domain.Comment) (int64, error)
+	Like(ctx context.Context, uid int64, id int64) error
+	CancelLike(ctx context.Context, uid int64, id int64) error
+	// Liked 把 uid 点赞过的评论的 Liked 设置为 true
+	Liked(ctx context.Context, uid int64, cs []domain.Comment) error
+}
+
+type commentRepository struct {
+	dao dao.CommentDAO
+}
+
+func NewCommentRepository(dao dao.CommentDAO) CommentRepository {
+	return &commentRepository{dao: dao}
+}
+
+func (repo *commentRepository) CreateComment(ctx context.Context, c domain.Comment) (int64, error) {
+	return repo.dao.Insert(ctx, repo.toEntity(c))
+}
+
+func (repo *commentRepository) FindById(ctx context.Context, id int64) (domain.Comment, error) {
+	c, err := repo.dao.FindById(ctx, id)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	return repo.toDomain(c), nil
+}
+
+func (repo *commentRepository) FindByBiz(ctx context.Context, biz string, bizId int64,
+	sort domain.CommentSort, cursor domain.CommentCursor, limit int) ([]domain.Comment, error) {
+	var (
+		cs  []dao.Comment
+		err error
+	)
+	switch sort {
+	case domain.CommentSortByLikes:
+		cs, err = repo.dao.ListByLikes(ctx, biz, bizId, cursor.LikeCnt, cursor.Id, limit)
+	default:
+		cs, err = repo.dao.ListByTime(ctx, biz, bizId, cursor.Id, limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(cs, func(idx int, src dao.Comment) domain.Comment {
+		return repo.toDomain(src)
+	}), nil
+}
+
+func (repo *commentRepository) FindReplies(ctx context.Context,
+	rootId int64, cursor int64, limit int) ([]domain.Comment, error) {
+	cs, err := repo.dao.ListReplies(ctx, rootId, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(cs, func(idx int, src dao.Comment) domain.Comment {
+		return repo.toDomain(src)
+	}), nil
+}
+
+func (repo *commentRepository) DeleteComment(ctx context.Context, c domain.Comment) (int64, error) {
+	return repo.dao.Delete(ctx, repo.toEntity(c))
+}
+
+func (repo *commentRepository) Like(ctx context.Context, uid int64, id int64) error {
+	return repo.dao.InsertLike(ctx, uid, id)
+}
+
+func (repo *commentRepository) CancelLike(ctx context.Context, uid int64, id int64) error {
+	return repo.dao.DeleteLike(ctx, uid, id)
+}
+
+func (repo *commentRepository) Liked(ctx context.Context, uid int64, cs []domain.Comment) error {
+	ids := slice.Map(cs, func(idx int, src domain.Comment) int64 {
+		return src.Id
+	})
+	liked, err := repo.dao.FindLikedIds(ctx, uid, ids)
+	if err != nil {
+		return err
+	}
+	set := make(map[int64]struct{}, len(liked))
+	for _, id := range liked {
+		set[id] = struct{}{}
+	}
+	for i := range cs {
+		_, cs[i].Liked = set[cs[i].Id]
+	}
+	return nil
+}
+
+func (repo *commentRepository) toEntity(c domain.Comment) dao.Comment {
+	return dao.Comment{
+		Id:       c.Id,
+		Uid:      c.Uid,
+		Biz:      c.Biz,
+		BizId:    c.BizId,
+		RootId:   c.RootId,
+		ParentId: c.ParentId,
+		Content:  c.Content,
+	}
+}
+
+func (repo *commentRepository) toDomain(c dao.Comment) domain.Comment {
+	return domain.Comment{
+		Id:       c.Id,
+		Uid:      c.Uid,
+		Biz:      c.Biz,
+		BizId:    c.BizId,
+		RootId:   c.RootId,
+		ParentId: c.ParentId,
+		Content:  c.Content,
+		LikeCnt:  c.LikeCnt,
+		ReplyCnt: c.ReplyCnt,
+		Ctime:    time.UnixMilli(c.Ctime),
+		Utime:    time.UnixMilli(c.Utime),
+	}
+}
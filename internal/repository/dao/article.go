@@ -12,13 +12,56 @@ import (
 //go:generate mockgen -source=./article.go -package=daomocks -destination=./mocks/article.mock.go ArticleDAO
 type ArticleDAO interface {
 	Insert(ctx context.Context, art Article) (int64, error)
+	// UpdateById 作者保存的时候 Version 是作者编辑的那个版本，和数据库里面的对不上返回 ErrArticleVersionConflict。
+	// 保存成功之后版本号加一
 	UpdateById(ctx context.Context, entity Article) error
+	// FlushDraft 把自动保存的草稿写到数据库，base 是开始自动保存的时候数据库里面的版本，
+	// 版本号直接改成草稿的 Version。和保存草稿一样文章变回未发表，但是不记录历史版本
+	FlushDraft(ctx context.Context, entity Article, base int64) error
+	// Sync 把审核通过的版本同步到线上库，文章必须还是审核中的这个版本，不然返回 ErrArticleReviewChanged
 	Sync(ctx context.Context, entity Article) (int64, error)
 	SyncStatus(ctx context.Context, uid int64, id int64, status uint8) error
+	// RejectReview 审核没通过，只改制作库，线上库还是上一次通过的版本。
+	// 文章已经不是审核中的这个版本了返回 ErrArticleReviewChanged
+	RejectReview(ctx context.Context, uid int64, id int64, revision int64) error
+	// SyncVisibility 同时修改制作库和线上库的可见范围
+	SyncVisibility(ctx context.Context, uid int64, id int64, visibility uint8) error
 	GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]Article, error)
 	GetById(ctx context.Context, id int64) (Article, error)
 	GetPubById(ctx context.Context, id int64) (PublishedArticle, error)
+	// GetPubByIds 线上库里面的这些文章，只有元数据，不返回内容，也不保证顺序。专栏的目录用
+	GetPubByIds(ctx context.Context, ids []int64) ([]PublishedArticle, error)
+	// ListPub 公开的文章，不公开的不会出现在任何列表里面
 	ListPub(ctx context.Context, start time.Time, offset, limit int) ([]PublishedArticle, error)
+	// GetByAuthorByCursor 作者的文章，按照 utime、id 倒序。
+	// cursorUtime 和 cursorId 是上一页最后一篇的，第一页 cursorId 传 0
+	GetByAuthorByCursor(ctx context.Context, uid int64, cursorUtime int64, cursorId int64, limit int) ([]Article, error)
+	// ListPubByCursor start 之前更新过的已经发表的文章，按照 utime、id 倒序，游标和 GetByAuthorByCursor 一样
+	ListPubByCursor(ctx context.Context, start time.Time, cursorUtime int64, cursorId int64, limit int) ([]PublishedArticle, error)
+	// TransferAuthor 把 fromUid 的文章都转给 toUid，合并账号的时候用
+	TransferAuthor(ctx context.Context, fromUid int64, toUid int64) error
+	// GetPubByAuthor 作者已经发表出去的文章，按照 id 排序。撤回了的、定时还没到点的都不算。
+	// visibilities 是可以看到的可见范围，为空就是全部
+	GetPubByAuthor(ctx context.Context, uid int64, visibilities []uint8, offset int, limit int) ([]PublishedArticle, error)
+	// WithdrawByAuthor 撤回作者所有已经发表的文章，返回被撤回的文章 ID
+	WithdrawByAuthor(ctx context.Context, uid int64) ([]int64, error)
+	// ListRevisions 文章的历史版本，按照版本号倒序，不返回内容
+	ListRevisions(ctx context.Context, artId int64, offset int, limit int) ([]ArticleRevision, error)
+	GetRevision(ctx context.Context, artId int64, version int64) (ArticleRevision, error)
+	// ListDue 到了发表时间的定时文章，按照发表时间排序
+	ListDue(ctx context.Context, now int64, limit int) ([]Article, error)
+	// UpdateSchedule 修改定时发表的时间，文章必须还处于定时发表的状态
+	UpdateSchedule(ctx context.Context, uid int64, id int64, publishAt int64) error
+	// CancelSchedule 取消定时发表，文章变回草稿
+	CancelSchedule(ctx context.Context, uid int64, id int64) error
+	// ListPubByTag 标签下面已经发表的文章，按照 ID 倒序，cursor 是上一页最后一篇的 ID，第一页传 0
+	ListPubByTag(ctx context.Context, tag string, cursor int64, limit int) ([]PublishedArticle, error)
+	// CountPubTags 文章最多的 limit 个标签
+	CountPubTags(ctx context.Context, limit int) ([]TagCount, error)
+	// GetPubTags 线上库里面文章的标签，缓存失效的时候用
+	GetPubTags(ctx context.Context, ids []int64) (map[int64][]string, error)
+	// ReferencedAttachments keys 里面还被草稿或者已经发表的文章引用着的附件，清理附件的时候用
+	ReferencedAttachments(ctx context.Context, keys []string) ([]string, error)
 }
 
 type ArticleGORMDAO struct {
@@ -30,25 +73,133 @@ func (a *ArticleGORMDAO) GetPubById(ctx context.Context, id int64) (PublishedArt
 	err := a.db.WithContext(ctx).
 		Where("id = ?", id).
 		First(&res).Error
+	if err != nil {
+		return res, err
+	}
+	arts := []PublishedArticle{res}
+	err = a.fillPubTags(ctx, arts)
+	return arts[0], err
+}
+
+func (a *ArticleGORMDAO) GetPubByIds(ctx context.Context, ids []int64) ([]PublishedArticle, error) {
+	var res []PublishedArticle
+	if len(ids) == 0 {
+		return res, nil
+	}
+	err := a.db.WithContext(ctx).
+		Omit("content", "html").
+		Where("id IN ?", ids).
+		Find(&res).Error
 	return res, err
 }
 
 func (a *ArticleGORMDAO) ListPub(ctx context.Context, start time.Time, offset, limit int) ([]PublishedArticle, error) {
 	var res []PublishedArticle
-	const ArticleStatusPublished = 2
 	err := a.db.WithContext(ctx).
-		Where("utime < ? AND status = ?",
-			start.UnixMilli(), ArticleStatusPublished).
+		Where("utime < ? AND status = ? AND visibility = ?",
+			start.UnixMilli(), articleStatusPublished, articleVisibilityPublic).
 		Offset(offset).Limit(limit).
 		Find(&res).Error
 	return res, err
 }
 
+func (a *ArticleGORMDAO) ListPubByCursor(ctx context.Context, start time.Time,
+	cursorUtime int64, cursorId int64, limit int) ([]PublishedArticle, error) {
+	var res []PublishedArticle
+	query := a.db.WithContext(ctx).
+		Where("utime < ? AND status = ? AND visibility = ?",
+			start.UnixMilli(), articleStatusPublished, articleVisibilityPublic)
+	err := keysetByUtime(query, cursorUtime, cursorId).
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+// keysetByUtime 按照 utime、id 倒序翻页。
+// 翻页过程中被修改过的文章会跑到前面去，后面的页不会再出现，也不会让别的文章重复或者被跳过
+func keysetByUtime(query *gorm.DB, cursorUtime int64, cursorId int64) *gorm.DB {
+	if cursorId > 0 {
+		query = query.Where("utime < ? OR (utime = ? AND id < ?)",
+			cursorUtime, cursorUtime, cursorId)
+	}
+	return query.Order("utime DESC, id DESC")
+}
+
+func (a *ArticleGORMDAO) TransferAuthor(ctx context.Context, fromUid int64, toUid int64) error {
+	now := time.Now().UnixMilli()
+	updates := map[string]any{
+		"author_id": toUid,
+		"utime":     now,
+	}
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&Article{}).
+			Where("author_id = ?", fromUid).
+			Updates(updates).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&PublishedArticle{}).
+			Where("author_id = ?", fromUid).
+			Updates(updates).Error
+	})
+}
+
+func (a *ArticleGORMDAO) GetPubByAuthor(ctx context.Context,
+	uid int64, visibilities []uint8, offset int, limit int) ([]PublishedArticle, error) {
+	var res []PublishedArticle
+	query := a.db.WithContext(ctx).
+		Where("author_id = ? AND status = ?", uid, articleStatusPublished)
+	err := withVisibilities(query, visibilities).
+		Order("id").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+	return res, a.fillPubTags(ctx, res)
+}
+
+func (a *ArticleGORMDAO) WithdrawByAuthor(ctx context.Context, uid int64) ([]int64, error) {
+	var ids []int64
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return a.withdrawByAuthor(tx, &PublishedArticle{}, uid, &ids)
+	})
+	return ids, err
+}
+
+// withdrawByAuthor pub 是线上库的表，ArticleS3DAO 用的是另外一张表
+func (a *ArticleGORMDAO) withdrawByAuthor(tx *gorm.DB, pub any, uid int64, ids *[]int64) error {
+	err := tx.Model(pub).
+		Where("author_id = ? AND status = ?", uid, articleStatusPublished).
+		Pluck("id", ids).Error
+	if err != nil || len(*ids) == 0 {
+		return err
+	}
+	updates := map[string]any{
+		"utime":  time.Now().UnixMilli(),
+		"status": articleStatusPrivate,
+	}
+	err = tx.Model(&Article{}).
+		Where("id IN ?", *ids).
+		Updates(updates).Error
+	if err != nil {
+		return err
+	}
+	return tx.Model(pub).
+		Where("id IN ?", *ids).
+		Updates(updates).Error
+}
+
 func (a *ArticleGORMDAO) GetById(ctx context.Context, id int64) (Article, error) {
 	var art Article
 	err := a.db.WithContext(ctx).
 		Where("id = ?", id).First(&art).Error
-	return art, err
+	if err != nil {
+		return art, err
+	}
+	arts := []Article{art}
+	err = a.fillTags(ctx, arts)
+	return arts[0], err
 }
 
 func (a *ArticleGORMDAO) GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]Article, error) {
@@ -57,16 +208,33 @@ func (a *ArticleGORMDAO) GetByAuthor(ctx context.Context, uid int64, offset int,
 		Where("author_id = ?", uid).
 		Offset(offset).Limit(limit).
 		// a ASC, B DESC
-		Order("utime DESC").
+		// 加上 id 保证和 GetByAuthorByCursor 的顺序一样，第一页可以混用
+		Order("utime DESC, id DESC").
+		Find(&arts).Error
+	if err != nil {
+		return nil, err
+	}
+	return arts, a.fillTags(ctx, arts)
+}
+
+func (a *ArticleGORMDAO) GetByAuthorByCursor(ctx context.Context,
+	uid int64, cursorUtime int64, cursorId int64, limit int) ([]Article, error) {
+	var arts []Article
+	query := a.db.WithContext(ctx).Where("author_id = ?", uid)
+	err := keysetByUtime(query, cursorUtime, cursorId).
+		Limit(limit).
 		Find(&arts).Error
-	return arts, err
+	if err != nil {
+		return nil, err
+	}
+	return arts, a.fillTags(ctx, arts)
 }
 
 func (a *ArticleGORMDAO) SyncStatus(ctx context.Context, uid int64, id int64, status uint8) error {
 	now := time.Now().UnixMilli()
 	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		res := tx.Model(&Article{}).
-			Where("id = ? and author_id = ?", uid, id).
+			Where("id = ? and author_id = ?", id, uid).
 			Updates(map[string]any{
 				"utime":  now,
 				"status": status,
@@ -78,7 +246,7 @@ func (a *ArticleGORMDAO) SyncStatus(ctx context.Context, uid int64, id int64, st
 			return errors.New("ID 不对或者创作者不对")
 		}
 		return tx.Model(&PublishedArticle{}).
-			Where("id = ?", uid).
+			Where("id = ?", id).
 			Updates(map[string]any{
 				"utime":  now,
 				"status": status,
@@ -86,17 +254,24 @@ func (a *ArticleGORMDAO) SyncStatus(ctx context.Context, uid int64, id int64, st
 	})
 }
 
+func (a *ArticleGORMDAO) SyncVisibility(ctx context.Context, uid int64, id int64, visibility uint8) error {
+	now := time.Now().UnixMilli()
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return syncVisibility(tx, &PublishedArticle{}, uid, id, visibility, now)
+	})
+}
+
 func (a *ArticleGORMDAO) Sync(ctx context.Context, art Article) (int64, error) {
 	var id = art.Id
 	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		var (
+			rev int64
 			err error
 		)
-		dao := NewArticleGORMDAO(tx)
 		if id > 0 {
-			err = dao.UpdateById(ctx, art)
+			rev, err = a.updateById(tx, art)
 		} else {
-			id, err = dao.Insert(ctx, art)
+			id, rev, err = a.insert(tx, art)
 		}
 		if err != nil {
 			return err
@@ -106,20 +281,31 @@ func (a *ArticleGORMDAO) Sync(ctx context.Context, art Article) (int64, error) {
 		pubArt := PublishedArticle(art)
 		pubArt.Ctime = now
 		pubArt.Utime = now
+		// 记下线上库是从哪个版本同步过来的
+		pubArt.Revision = rev
 		err = tx.Clauses(clause.OnConflict{
 			// 对MySQL不起效，但是可以兼容别的方言
 			// INSERT xxx ON DUPLICATE KEY SET `title`=?
 			// 别的方言：
 			// sqlite INSERT XXX ON CONFLICT DO UPDATES WHERE
 			Columns: []clause.Column{{Name: "id"}},
-			DoUpdates: clause.Assignments(map[string]interface{}{
-				"title":   pubArt.Title,
-				"content": pubArt.Content,
-				"utime":   now,
-				"status":  pubArt.Status,
-			}),
+			DoUpdates: append(clause.Assignments(map[string]interface{}{
+				"title":      pubArt.Title,
+				"content":    pubArt.Content,
+				"utime":      now,
+				"status":     pubArt.Status,
+				"visibility": pubArt.Visibility,
+				"revision":   pubArt.Revision,
+			}), pubRenderedColumns...),
 		}).Create(&pubArt).Error
-		return err
+		if err != nil {
+			return err
+		}
+		err = a.syncPubTags(tx, id)
+		if err != nil {
+			return err
+		}
+		return a.syncPubAttachments(tx, id)
 	})
 	return id, err
 }
@@ -170,31 +356,128 @@ func (a *ArticleGORMDAO) SyncV1(ctx context.Context, art Article) (int64, error)
 }
 
 func (a *ArticleGORMDAO) UpdateById(ctx context.Context, art Article) error {
-	now := time.Now().UnixMilli()
-	res := a.db.WithContext(ctx).Model(&art).
-		Where("id = ? AND author_id = ?", art.Id, art.AuthorId).Updates(map[string]any{
-		"title":   art.Title,
-		"content": art.Content,
-		"status":  art.Status,
-		"utime":   now,
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		_, err := a.updateById(tx, art)
+		return err
 	})
+}
+
+// updateById 更新文章并且记录一个新版本，返回版本号
+func (a *ArticleGORMDAO) updateById(tx *gorm.DB, art Article) (int64, error) {
+	now := time.Now().UnixMilli()
+	// 先更新，顺便锁住这一行，后面算版本号的时候就不会有并发问题
+	query := tx.Model(&Article{}).
+		Where("id = ? AND author_id = ?", art.Id, art.AuthorId)
+	updates := map[string]any{
+		"title":      art.Title,
+		"content":    art.Content,
+		"status":     art.Status,
+		"visibility": art.Visibility,
+		"publish_at": art.PublishAt,
+		"utime":      now,
+	}
+	due, reviewed := isDue(art), isReviewed(art)
+	switch {
+	case due:
+		query = query.Where("status = ? AND publish_at = ? AND revision = ?",
+			articleStatusScheduled, art.PublishAt, art.Revision)
+	case reviewed:
+		query = query.Where("status = ? AND revision = ?",
+			articleStatusReviewing, art.Revision)
+	default:
+		// 作者自己保存，见 ErrArticleVersionConflict
+		query = query.Where("version = ?", art.Version)
+		updates["version"] = gorm.Expr("version + 1")
+	}
+	res := query.Updates(updates)
 	if res.Error != nil {
-		return res.Error
+		return 0, res.Error
 	}
 	// 我怎么知道有没有更新数据？
 	if res.RowsAffected == 0 {
-		// 创作者不对，说明有人在瞎搞
-		return errors.New("ID 不对或者创作者不对")
+		switch {
+		case due:
+			return 0, ErrArticleScheduleChanged
+		case reviewed:
+			return 0, ErrArticleReviewChanged
+		}
+		return 0, a.versionConflict(tx, art)
+	}
+	// 定时发表到点、审核通过的时候都没有带标签和附件，用作者提交的时候保存的那些
+	if !due && !reviewed {
+		err := a.replaceTags(tx, art.Id, art.Tags, now)
+		if err != nil {
+			return 0, err
+		}
+		err = a.replaceAttachments(tx, art.Id, art.Attachments, now)
+		if err != nil {
+			return 0, err
+		}
+	}
+	rev, err := recordRevision(tx, art, now)
+	if err != nil {
+		return 0, err
 	}
-	return nil
+	return rev, tx.Model(&Article{}).
+		Where("id = ?", art.Id).
+		Update("revision", rev).Error
 }
 
 func (a *ArticleGORMDAO) Insert(ctx context.Context, art Article) (int64, error) {
+	var id int64
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		id, _, err = a.insert(tx, art)
+		return err
+	})
+	return id, err
+}
+
+// insert 新建的文章就是第一个版本
+func (a *ArticleGORMDAO) insert(tx *gorm.DB, art Article) (int64, int64, error) {
 	now := time.Now().UnixMilli()
-	art.Ctime = now
+	// 从别的平台导入的文章保留原来的创建时间
+	if art.Ctime == 0 {
+		art.Ctime = now
+	}
 	art.Utime = now
-	err := a.db.WithContext(ctx).Create(&art).Error
-	return art.Id, err
+	art.Revision = 1
+	art.Version = 1
+	// 渲染结果只存在线上库
+	err := tx.Omit("html", "abstract", "toc").Create(&art).Error
+	if err != nil {
+		return 0, 0, err
+	}
+	err = a.replaceTags(tx, art.Id, art.Tags, now)
+	if err != nil {
+		return 0, 0, err
+	}
+	err = a.replaceAttachments(tx, art.Id, art.Attachments, now)
+	if err != nil {
+		return 0, 0, err
+	}
+	rev, err := recordRevision(tx, art, now)
+	return art.Id, rev, err
+}
+
+func (a *ArticleGORMDAO) ListRevisions(ctx context.Context,
+	artId int64, offset int, limit int) ([]ArticleRevision, error) {
+	var res []ArticleRevision
+	err := a.db.WithContext(ctx).
+		Omit("content").
+		Where("article_id = ?", artId).
+		Order("version DESC").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (a *ArticleGORMDAO) GetRevision(ctx context.Context, artId int64, version int64) (ArticleRevision, error) {
+	var res ArticleRevision
+	err := a.db.WithContext(ctx).
+		Where("article_id = ? AND version = ?", artId, version).
+		First(&res).Error
+	return res, err
 }
 
 func NewArticleGORMDAO(db *gorm.DB) ArticleDAO {
@@ -207,12 +490,40 @@ type Article struct {
 	Id      int64  `gorm:"primaryKey,autoIncrement" bson:"id,omitempty"`
 	Title   string `gorm:"type=varchar(4096)" bson:"title,omitempty"`
 	Content string `gorm:"type=BLOB" bson:"content,omitempty"`
-	// 我要根据创作者ID来查询
-	AuthorId int64 `gorm:"index" bson:"author_id,omitempty"`
-	Status   uint8 `bson:"status,omitempty"`
-	Ctime    int64 `bson:"ctime,omitempty"`
+	// 我要根据创作者ID来查询，按照 utime、id 翻页
+	AuthorId int64 `gorm:"index:author_utime,priority:1" bson:"author_id,omitempty"`
+	Status   uint8 `gorm:"index:status_utime,priority:1" bson:"status,omitempty"`
+	// Visibility 可见范围，0 是公开。不能 omitempty，不然改回公开的时候 $set 不会生效
+	Visibility uint8 `gorm:"not null;default:0" bson:"visibility"`
+	Ctime      int64 `bson:"ctime,omitempty"`
 	// 更新时间
-	Utime int64 `bson:"utime,omitempty"`
+	Utime int64 `gorm:"index:author_utime,priority:2;index:status_utime,priority:2" bson:"utime,omitempty"`
+	// Revision 当前的版本号，线上库里面是同步过来的那个版本
+	Revision int64 `bson:"revision,omitempty"`
+	// Version 乐观锁，作者每保存一次加一，和 Revision 不是一回事。线上库里面没有用
+	Version int64 `gorm:"not null;default:0" bson:"version,omitempty"`
+	// PublishAt 定时发表的时间
+	PublishAt int64 `gorm:"index" bson:"publish_at,omitempty"`
+	// Tags MySQL 里面存在 ArticleTag 和 PublishedArticleTag 里面，MongoDB 直接存在文章里面。
+	// 不能 omitempty，不然去掉所有标签的时候 $set 不会把它清空
+	Tags []string `gorm:"-" bson:"tags"`
+	// Attachments 引用的附件，MySQL 里面存在 ArticleAttachment 和 PublishedArticleAttachment 里面
+	Attachments []string `gorm:"-" bson:"attachments"`
+	// Html、Abstract、Toc 是发表的时候从 Content 渲染出来的，只有线上库有
+	Html     string `gorm:"type:mediumtext" bson:"html,omitempty"`
+	Abstract string `gorm:"type:varchar(1024)" bson:"abstract,omitempty"`
+	// Toc 和 Tags 一样不能 omitempty
+	Toc []ArticleHeading `gorm:"type:text;serializer:json" bson:"toc"`
+}
+
+// pubRenderedColumns 渲染结果直接用要插入的那一行的，toc 要经过 JSON 序列化
+var pubRenderedColumns = clause.AssignmentColumns([]string{"html", "abstract", "toc"})
+
+// ArticleHeading 目录里面的一个标题
+type ArticleHeading struct {
+	Level int    `json:"level" bson:"level"`
+	Id    string `json:"id" bson:"id"`
+	Title string `json:"title" bson:"title"`
 }
 
 type PublishedArticle Article
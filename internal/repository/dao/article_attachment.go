@@ -0,0 +1,76 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"gorm.io/gorm"
+)
+
+// ArticleAttachment 制作库里面文章引用了哪些附件，和标签一样保存草稿的时候整个替换掉。
+// 清理附件的时候靠它判断附件还有没有用。MongoDB 直接存在文章里面
+type ArticleAttachment struct {
+	Id            int64  `gorm:"primaryKey,autoIncrement"`
+	ArticleId     int64  `gorm:"uniqueIndex:article_attachment,priority:1"`
+	AttachmentKey string `gorm:"type:char(64);uniqueIndex:article_attachment,priority:2;index"`
+	Ctime         int64
+}
+
+// PublishedArticleAttachment 线上库的引用，发表的时候从 ArticleAttachment 整个复制过来。
+// 草稿里面删掉了图片，已经发表的那个版本还在用，所以两边都要算
+type PublishedArticleAttachment ArticleAttachment
+
+// ReferencedAttachments keys 里面还被制作库或者线上库引用着的那些
+func (a *ArticleGORMDAO) ReferencedAttachments(ctx context.Context, keys []string) ([]string, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	db := a.db.WithContext(ctx)
+	var draft, pub []string
+	err := db.Model(&ArticleAttachment{}).
+		Where("attachment_key IN ?", keys).
+		Distinct().
+		Pluck("attachment_key", &draft).Error
+	if err != nil {
+		return nil, err
+	}
+	err = db.Model(&PublishedArticleAttachment{}).
+		Where("attachment_key IN ?", keys).
+		Distinct().
+		Pluck("attachment_key", &pub).Error
+	if err != nil {
+		return nil, err
+	}
+	return slice.UnionSet(draft, pub), nil
+}
+
+// replaceAttachments 把制作库里面文章引用的附件整个换成 keys
+func (a *ArticleGORMDAO) replaceAttachments(tx *gorm.DB, artId int64, keys []string, now int64) error {
+	err := tx.Where("article_id = ?", artId).Delete(&ArticleAttachment{}).Error
+	if err != nil || len(keys) == 0 {
+		return err
+	}
+	rels := slice.Map(keys, func(idx int, src string) ArticleAttachment {
+		return ArticleAttachment{ArticleId: artId, AttachmentKey: src, Ctime: now}
+	})
+	return tx.Create(&rels).Error
+}
+
+// syncPubAttachments 发表的时候，线上库的引用和制作库保持一致
+func (a *ArticleGORMDAO) syncPubAttachments(tx *gorm.DB, artId int64) error {
+	err := tx.Where("article_id = ?", artId).Delete(&PublishedArticleAttachment{}).Error
+	if err != nil {
+		return err
+	}
+	var rels []ArticleAttachment
+	err = tx.Where("article_id = ?", artId).Order("id").Find(&rels).Error
+	if err != nil || len(rels) == 0 {
+		return err
+	}
+	now := time.Now().UnixMilli()
+	pubRels := slice.Map(rels, func(idx int, src ArticleAttachment) PublishedArticleAttachment {
+		return PublishedArticleAttachment{ArticleId: src.ArticleId, AttachmentKey: src.AttachmentKey, Ctime: now}
+	})
+	return tx.Create(&pubRels).Error
+}
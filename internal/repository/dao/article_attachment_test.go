@@ -0,0 +1,49 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticleGORMDAO_ReferencedAttachments(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db := newMockGORM(t, sqlDB)
+
+	// 草稿和线上库各引用了一部分，合起来去重
+	mock.ExpectQuery("SELECT DISTINCT `attachment_key` FROM `article_attachments` WHERE attachment_key IN \\(\\?,\\?,\\?\\)").
+		WithArgs("a", "b", "c").
+		WillReturnRows(sqlmock.NewRows([]string{"attachment_key"}).AddRow("a"))
+	mock.ExpectQuery("SELECT DISTINCT `attachment_key` FROM `published_article_attachments` WHERE attachment_key IN \\(\\?,\\?,\\?\\)").
+		WithArgs("a", "b", "c").
+		WillReturnRows(sqlmock.NewRows([]string{"attachment_key"}).AddRow("a").AddRow("b"))
+
+	keys, err := NewArticleGORMDAO(db).ReferencedAttachments(context.Background(), []string{"a", "b", "c"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a", "b"}, keys)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGORMAttachmentDAO_Upsert(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db := newMockGORM(t, sqlDB)
+
+	// 同样的内容已经有了，只更新 utime，返回原本的那条
+	mock.ExpectExec("INSERT INTO `attachments` .* ON DUPLICATE KEY UPDATE `utime`=\\?").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery("SELECT \\* FROM `attachments` WHERE `key` = \\?").
+		WithArgs("abc", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "key", "uid", "name"}).
+			AddRow(1, "abc", 456, "old.png"))
+
+	res, err := NewGORMAttachmentDAO(db).Upsert(context.Background(),
+		Attachment{Key: "abc", Uid: 123, Name: "new.png"})
+	require.NoError(t, err)
+	assert.Equal(t, Attachment{Id: 1, Key: "abc", Uid: 456, Name: "old.png"}, res)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
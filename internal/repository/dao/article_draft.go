@@ -0,0 +1,52 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrArticleVersionConflict 作者保存的时候带上的版本号和数据库里面的对不上，
+// 说明文章已经在别的标签页或者设备上保存过了，直接覆盖的话会把那边的修改冲掉
+var ErrArticleVersionConflict = errors.New("文章已经在别的地方修改过了")
+
+func (a *ArticleGORMDAO) FlushDraft(ctx context.Context, art Article, base int64) error {
+	now := time.Now().UnixMilli()
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&Article{}).
+			Where("id = ? AND author_id = ? AND version = ?", art.Id, art.AuthorId, base).
+			Updates(map[string]any{
+				"title":   art.Title,
+				"content": art.Content,
+				"status":  articleStatusUnpublished,
+				"version": art.Version,
+				"utime":   now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrArticleVersionConflict
+		}
+		// 草稿里面新插入的图片也要算上，不然会被清理附件的任务删掉
+		return a.replaceAttachments(tx, art.Id, art.Attachments, now)
+	})
+}
+
+// versionConflict 作者保存的时候更新了 0 行，区分一下是版本号不对还是 ID、创作者不对
+func (a *ArticleGORMDAO) versionConflict(tx *gorm.DB, art Article) error {
+	var cnt int64
+	err := tx.Model(&Article{}).
+		Where("id = ? AND author_id = ?", art.Id, art.AuthorId).
+		Count(&cnt).Error
+	if err != nil {
+		return err
+	}
+	if cnt > 0 {
+		return ErrArticleVersionConflict
+	}
+	// 创作者不对，说明有人在瞎搞
+	return errors.New("ID 不对或者创作者不对")
+}
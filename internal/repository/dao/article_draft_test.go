@@ -0,0 +1,81 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestArticleGORMDAO_UpdateVersion 两个标签页都在版本 3 上改，后保存的那个冲突
+func TestArticleGORMDAO_UpdateVersion(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db := newMockGORM(t, sqlDB)
+	art := Article{Id: 1, AuthorId: 123, Title: "标题", Content: "内容",
+		Status: articleStatusUnpublished, Version: 3}
+	const update = "UPDATE `articles` SET .*`version`=version \\+ 1.* " +
+		"WHERE \\(id = \\? AND author_id = \\?\\) AND version = \\?"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(update).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `article_tags`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM `article_attachments`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT `version`,`hash` FROM `article_revisions`").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "hash"}).
+			AddRow(2, revisionHash(art.Title, art.Content)))
+	mock.ExpectExec("UPDATE `articles` SET `revision`").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(update).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	// 文章还在，说明是版本号不对
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `articles` WHERE id = \\? AND author_id = \\?").
+		WithArgs(int64(1), int64(123)).
+		WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+	mock.ExpectRollback()
+
+	dao := NewArticleGORMDAO(db)
+	err = dao.UpdateById(context.Background(), art)
+	require.NoError(t, err)
+	err = dao.UpdateById(context.Background(), art)
+	assert.Equal(t, ErrArticleVersionConflict, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArticleGORMDAO_FlushDraft(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db := newMockGORM(t, sqlDB)
+	art := Article{Id: 1, AuthorId: 123, Title: "标题", Content: "内容", Version: 5}
+	const update = "UPDATE `articles` SET .* WHERE id = \\? AND author_id = \\? AND version = \\?"
+
+	// 草稿是从版本 3 开始自动保存的，数据库直接变成草稿的版本
+	mock.ExpectBegin()
+	mock.ExpectExec(update).
+		WithArgs("内容", articleStatusUnpublished, "标题", sqlmock.AnyArg(), int64(5),
+			int64(1), int64(123), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `article_attachments`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(update).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	dao := NewArticleGORMDAO(db)
+	err = dao.FlushDraft(context.Background(), art, 3)
+	require.NoError(t, err)
+	err = dao.FlushDraft(context.Background(), art, 3)
+	assert.Equal(t, ErrArticleVersionConflict, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,143 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrArticleReviewChanged = errors.New("审核中的文章已经被修改")
+	ErrArticleReviewHandled = errors.New("审核记录已经处理过了")
+)
+
+const (
+	articleStatusReviewing = 5
+	articleStatusRejected  = 6
+)
+
+const (
+	articleReviewStatusPending = 1
+)
+
+// isReviewed 判断这次同步是不是审核通过之后在发表审核的那个版本，这种时候会带上 Revision。
+// 和 isDue 一样，UPDATE 的时候加上 status = 审核中 AND revision = ? 的条件，
+// 审核期间作者改了内容或者撤回了，都会更新 0 行，返回 ErrArticleReviewChanged
+func isReviewed(art Article) bool {
+	return art.Status == articleStatusPublished
+}
+
+func (a *ArticleGORMDAO) RejectReview(ctx context.Context, uid int64, id int64, revision int64) error {
+	res := a.db.WithContext(ctx).Model(&Article{}).
+		Where("id = ? AND author_id = ? AND status = ? AND revision = ?",
+			id, uid, articleStatusReviewing, revision).
+		Updates(map[string]any{
+			"status": articleStatusRejected,
+			"utime":  time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrArticleReviewChanged
+	}
+	return nil
+}
+
+//go:generate mockgen -source=./article_review.go -package=daomocks -destination=./mocks/article_review.mock.go ArticleReviewDAO
+type ArticleReviewDAO interface {
+	// Upsert 同一个版本只有一条审核记录，作者没改内容重新发表的时候覆盖原来的结果
+	Upsert(ctx context.Context, r ArticleReview) (int64, error)
+	FindById(ctx context.Context, id int64) (ArticleReview, error)
+	// ListByStatus 按照 id 正序，先提交的先审
+	ListByStatus(ctx context.Context, status uint8, offset int, limit int) ([]ArticleReview, error)
+	// UpdateStatus 只有还在等审核的记录能改，被别人处理过了返回 ErrArticleReviewHandled
+	UpdateStatus(ctx context.Context, id int64, status uint8, reviewer int64, reason string) error
+}
+
+type GORMArticleReviewDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMArticleReviewDAO(db *gorm.DB) ArticleReviewDAO {
+	return &GORMArticleReviewDAO{db: db}
+}
+
+func (dao *GORMArticleReviewDAO) Upsert(ctx context.Context, r ArticleReview) (int64, error) {
+	now := time.Now().UnixMilli()
+	r.Ctime = now
+	r.Utime = now
+	err := dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"title":    r.Title,
+			"status":   r.Status,
+			"reason":   r.Reason,
+			"reviewer": r.Reviewer,
+			"utime":    now,
+		}),
+	}).Create(&r).Error
+	if err != nil {
+		return 0, err
+	}
+	// 冲突的时候 MySQL 返回的自增 ID 不是原来那一行的，重新查一下
+	var res ArticleReview
+	err = dao.db.WithContext(ctx).
+		Where("art_id = ? AND revision = ?", r.ArtId, r.Revision).
+		First(&res).Error
+	return res.Id, err
+}
+
+func (dao *GORMArticleReviewDAO) FindById(ctx context.Context, id int64) (ArticleReview, error) {
+	var res ArticleReview
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&res).Error
+	return res, err
+}
+
+func (dao *GORMArticleReviewDAO) ListByStatus(ctx context.Context,
+	status uint8, offset int, limit int) ([]ArticleReview, error) {
+	var res []ArticleReview
+	err := dao.db.WithContext(ctx).
+		Where("status = ?", status).
+		Order("id").
+		Offset(offset).
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMArticleReviewDAO) UpdateStatus(ctx context.Context,
+	id int64, status uint8, reviewer int64, reason string) error {
+	res := dao.db.WithContext(ctx).Model(&ArticleReview{}).
+		Where("id = ? AND status = ?", id, articleReviewStatusPending).
+		Updates(map[string]any{
+			"status":   status,
+			"reviewer": reviewer,
+			"reason":   reason,
+			"utime":    time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrArticleReviewHandled
+	}
+	return nil
+}
+
+// ArticleReview 文章某个版本的审核记录
+type ArticleReview struct {
+	Id       int64 `gorm:"primaryKey,autoIncrement"`
+	ArtId    int64 `gorm:"uniqueIndex:art_id_revision"`
+	Revision int64 `gorm:"uniqueIndex:art_id_revision"`
+	AuthorId int64
+	Title    string `gorm:"type:varchar(4096)"`
+	// 管理员的队列按照 status 查
+	Status   uint8  `gorm:"index"`
+	Reason   string `gorm:"type:varchar(1024)"`
+	Reviewer int64
+	Ctime    int64
+	Utime    int64
+}
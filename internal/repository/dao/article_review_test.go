@@ -0,0 +1,85 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestArticleGORMDAO_SyncReviewed 审核期间作者改了内容，管理员再点通过也不能把旧的版本发出去
+func TestArticleGORMDAO_SyncReviewed(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db := newMockGORM(t, sqlDB)
+	art := Article{Id: 1, AuthorId: 123, Title: "标题", Content: "内容",
+		Status: articleStatusPublished, Revision: 3}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `articles` SET .* WHERE \\(id = \\? AND author_id = \\?\\) "+
+		"AND \\(status = \\? AND revision = \\?\\)").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), int64(1), int64(123), articleStatusReviewing, int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	_, err = NewArticleGORMDAO(db).Sync(context.Background(), art)
+	assert.Equal(t, ErrArticleReviewChanged, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArticleGORMDAO_RejectReview(t *testing.T) {
+	testCases := []struct {
+		name     string
+		affected int64
+
+		wantErr error
+	}{
+		{
+			name:     "拒绝成功",
+			affected: 1,
+		},
+		{
+			name:     "作者已经改了",
+			affected: 0,
+			wantErr:  ErrArticleReviewChanged,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			db := newMockGORM(t, sqlDB)
+			mock.ExpectExec("UPDATE `articles` SET `status`=\\?,`utime`=\\? "+
+				"WHERE id = \\? AND author_id = \\? AND status = \\? AND revision = \\?").
+				WithArgs(articleStatusRejected, sqlmock.AnyArg(),
+					int64(1), int64(123), articleStatusReviewing, int64(3)).
+				WillReturnResult(sqlmock.NewResult(0, tc.affected))
+
+			err = NewArticleGORMDAO(db).RejectReview(context.Background(), 123, 1, 3)
+			assert.Equal(t, tc.wantErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGORMArticleReviewDAO_UpdateStatus(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db := newMockGORM(t, sqlDB)
+	const update = "UPDATE `article_reviews` SET .* WHERE id = \\? AND status = \\?"
+	mock.ExpectExec(update).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// 两个管理员同时处理，后面的那个更新 0 行
+	mock.ExpectExec(update).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	dao := NewGORMArticleReviewDAO(db)
+	err = dao.UpdateStatus(context.Background(), 1, 2, 100, "")
+	require.NoError(t, err)
+	err = dao.UpdateStatus(context.Background(), 1, 3, 101, "广告")
+	assert.Equal(t, ErrArticleReviewHandled, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,63 @@
+package dao
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ArticleRevision 文章的历史版本，只插入不修改
+type ArticleRevision struct {
+	Id        int64  `gorm:"primaryKey,autoIncrement" bson:"id,omitempty"`
+	ArticleId int64  `gorm:"uniqueIndex:article_version" bson:"article_id,omitempty"`
+	Version   int64  `gorm:"uniqueIndex:article_version" bson:"version,omitempty"`
+	AuthorId  int64  `bson:"author_id,omitempty"`
+	Title     string `gorm:"type:varchar(4096)" bson:"title,omitempty"`
+	Content   string `gorm:"type:BLOB" bson:"content,omitempty"`
+	// Hash 标题和内容的 sha256，用来判断内容有没有变
+	Hash  string `gorm:"type:char(64)" bson:"hash,omitempty"`
+	Ctime int64  `bson:"ctime,omitempty"`
+}
+
+func revisionHash(title, content string) string {
+	h := sha256.New()
+	h.Write([]byte(title))
+	// 分隔一下，免得标题和内容拼起来撞上
+	h.Write([]byte{0})
+	h.Write([]byte(content))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recordRevision 给文章追加一个版本，返回版本号。
+// 内容和最新的版本一样的话，就直接复用最新的版本号。
+// 调用方要保证已经锁住了文章那一行，不然并发的时候版本号会冲突
+func recordRevision(tx *gorm.DB, art Article, now int64) (int64, error) {
+	hash := revisionHash(art.Title, art.Content)
+	var last ArticleRevision
+	err := tx.Select("version", "hash").
+		Where("article_id = ?", art.Id).
+		Order("version DESC").
+		First(&last).Error
+	switch {
+	case err == nil:
+		if last.Hash == hash {
+			return last.Version, nil
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+	default:
+		return 0, err
+	}
+	rev := ArticleRevision{
+		ArticleId: art.Id,
+		Version:   last.Version + 1,
+		AuthorId:  art.AuthorId,
+		Title:     art.Title,
+		Content:   art.Content,
+		Hash:      hash,
+		Ctime:     now,
+	}
+	err = tx.Create(&rev).Error
+	return rev.Version, err
+}
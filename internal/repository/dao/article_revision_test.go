@@ -0,0 +1,108 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordRevision(t *testing.T) {
+	art := Article{Id: 1, AuthorId: 123, Title: "标题", Content: "内容"}
+	testCases := []struct {
+		name string
+		mock func(mock sqlmock.Sqlmock)
+
+		wantVersion int64
+	}{
+		{
+			name: "第一个版本",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT `version`,`hash` FROM `article_revisions`").
+					WillReturnRows(sqlmock.NewRows([]string{"version", "hash"}))
+				mock.ExpectExec("INSERT INTO `article_revisions`").
+					WithArgs(int64(1), int64(1), int64(123), "标题", "内容",
+						revisionHash("标题", "内容"), int64(1000)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			wantVersion: 1,
+		},
+		{
+			// 只是点了一下保存，内容没有变，不会多出一个版本
+			name: "内容没有变",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT `version`,`hash` FROM `article_revisions`").
+					WillReturnRows(sqlmock.NewRows([]string{"version", "hash"}).
+						AddRow(3, revisionHash("标题", "内容")))
+			},
+			wantVersion: 3,
+		},
+		{
+			name: "内容变了",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT `version`,`hash` FROM `article_revisions`").
+					WillReturnRows(sqlmock.NewRows([]string{"version", "hash"}).
+						AddRow(3, revisionHash("标题", "老的内容")))
+				mock.ExpectExec("INSERT INTO `article_revisions`").
+					WithArgs(int64(1), int64(4), int64(123), "标题", "内容",
+						revisionHash("标题", "内容"), int64(1000)).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+			},
+			wantVersion: 4,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			db := newMockGORM(t, sqlDB)
+			tc.mock(mock)
+			version, err := recordRevision(db, art, 1000)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantVersion, version)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestArticleGORMDAO_SyncRevision 线上库要记下是从哪个版本同步过来的
+func TestArticleGORMDAO_SyncRevision(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db := newMockGORM(t, sqlDB)
+	art := Article{Id: 1, AuthorId: 123, Title: "标题", Content: "内容",
+		Status: articleStatusPublished, Revision: 3}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `articles` SET").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// 提交审核的时候已经记录过这个版本了
+	mock.ExpectQuery("SELECT `version`,`hash` FROM `article_revisions`").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "hash"}).
+			AddRow(3, revisionHash("标题", "内容")))
+	mock.ExpectExec("UPDATE `articles` SET `revision`=\\?").
+		WithArgs(int64(3), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// 插入和冲突之后更新的时候，revision 都是审核的那个版本号
+	mock.ExpectExec("INSERT INTO `published_articles` .*`revision`.* ON DUPLICATE KEY UPDATE .*`revision`=\\?").
+		WithArgs("标题", "内容", int64(123), articleStatusPublished, articleVisibilityPublic,
+			sqlmock.AnyArg(), sqlmock.AnyArg(), int64(3), int64(0), int64(0), "", "", sqlmock.AnyArg(), int64(1),
+			"内容", int64(3), articleStatusPublished, "标题", sqlmock.AnyArg(), articleVisibilityPublic).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("DELETE FROM `published_article_tags`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT \\* FROM `article_tags`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tag_id", "article_id", "ctime"}))
+	mock.ExpectExec("DELETE FROM `published_article_attachments`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT \\* FROM `article_attachments`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "article_id", "attachment_key", "ctime"}))
+	mock.ExpectCommit()
+
+	id, err := NewArticleGORMDAO(db).Sync(context.Background(), art)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
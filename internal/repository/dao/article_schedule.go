@@ -0,0 +1,70 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrArticleNotScheduled    = errors.New("文章不是定时发表的状态")
+	ErrArticleScheduleChanged = errors.New("定时发表的文章已经被修改")
+)
+
+const (
+	articleStatusUnpublished = 1
+	articleStatusPublished   = 2
+	articleStatusPrivate     = 3
+	articleStatusScheduled   = 4
+)
+
+// isDue 判断这次更新是不是定时任务在把到点的文章提交审核，这种时候会带上 PublishAt 和 Revision。
+// 真正的并发控制在 updateById 里面：UPDATE 的时候加上
+// status = 定时发表 AND publish_at = ? AND revision = ? 的条件，
+// 作者中途取消、改时间、改内容，或者别的节点已经提交过了，都会更新 0 行，返回 ErrArticleScheduleChanged
+func isDue(art Article) bool {
+	return art.Status == articleStatusReviewing && art.PublishAt > 0
+}
+
+func (a *ArticleGORMDAO) ListDue(ctx context.Context, now int64, limit int) ([]Article, error) {
+	var res []Article
+	err := a.db.WithContext(ctx).
+		Where("status = ? AND publish_at <= ?", articleStatusScheduled, now).
+		Order("publish_at").
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (a *ArticleGORMDAO) UpdateSchedule(ctx context.Context, uid int64, id int64, publishAt int64) error {
+	res := a.db.WithContext(ctx).Model(&Article{}).
+		Where("id = ? AND author_id = ? AND status = ?", id, uid, articleStatusScheduled).
+		Updates(map[string]any{
+			"publish_at": publishAt,
+			"utime":      time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrArticleNotScheduled
+	}
+	return nil
+}
+
+func (a *ArticleGORMDAO) CancelSchedule(ctx context.Context, uid int64, id int64) error {
+	res := a.db.WithContext(ctx).Model(&Article{}).
+		Where("id = ? AND author_id = ? AND status = ?", id, uid, articleStatusScheduled).
+		Updates(map[string]any{
+			"status":     articleStatusUnpublished,
+			"publish_at": 0,
+			"utime":      time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrArticleNotScheduled
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// TestArticleGORMDAO_SubmitDue 两个节点同时把同一篇到点的文章提交审核，只有一个能成功
+func TestArticleGORMDAO_SubmitDue(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db := newMockGORM(t, sqlDB)
+	art := Article{
+		Id:        1,
+		Title:     "标题",
+		Content:   "内容",
+		AuthorId:  123,
+		Status:    articleStatusReviewing,
+		Revision:  3,
+		PublishAt: 1000,
+	}
+	// 定时发表要带上状态、发表时间和版本号做条件
+	const guard = "UPDATE `articles` SET .* WHERE \\(id = \\? AND author_id = \\?\\) " +
+		"AND \\(status = \\? AND publish_at = \\? AND revision = \\?\\)"
+
+	// 第一个节点抢到了，到点的时候不动制作库的标签和附件
+	mock.ExpectBegin()
+	mock.ExpectExec(guard).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), int64(1), int64(123), articleStatusScheduled, int64(1000), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT `version`,`hash` FROM `article_revisions`").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "hash"}).
+			AddRow(3, revisionHash(art.Title, art.Content)))
+	mock.ExpectExec("UPDATE `articles` SET `revision`").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// 第二个节点等第一个提交之后再执行 UPDATE，这时候状态已经是审核中了
+	mock.ExpectBegin()
+	mock.ExpectExec(guard).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	dao := NewArticleGORMDAO(db)
+	err = dao.UpdateById(context.Background(), art)
+	require.NoError(t, err)
+	err = dao.UpdateById(context.Background(), art)
+	assert.Equal(t, ErrArticleScheduleChanged, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func newMockGORM(t *testing.T, sqlDB *sql.DB) *gorm.DB {
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	require.NoError(t, err)
+	return db
+}
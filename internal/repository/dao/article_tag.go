@@ -0,0 +1,193 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Tag 标签，名字是唯一的，所有文章共用。MongoDB 直接把标签存在文章里面，不用这几张表
+type Tag struct {
+	Id    int64  `gorm:"primaryKey,autoIncrement"`
+	Name  string `gorm:"type:varchar(64);uniqueIndex"`
+	Ctime int64
+}
+
+// ArticleTag 制作库里面文章和标签的多对多关系，保存草稿的时候整个替换掉
+type ArticleTag struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// (tag_id, article_id) 既保证不重复，也是按照标签分页查文章的索引
+	TagId     int64 `gorm:"uniqueIndex:tag_article,priority:1"`
+	ArticleId int64 `gorm:"uniqueIndex:tag_article,priority:2;index"`
+	Ctime     int64
+}
+
+// PublishedArticleTag 线上库里面的关系，发表的时候从 ArticleTag 整个复制过来
+type PublishedArticleTag ArticleTag
+
+// TagCount 标签下面有多少篇已经发表的文章
+type TagCount struct {
+	Name string
+	Cnt  int64
+}
+
+// articleTagRow 查询文章标签的时候用
+type articleTagRow struct {
+	ArticleId int64
+	Name      string
+}
+
+func (a *ArticleGORMDAO) ListPubByTag(ctx context.Context,
+	tag string, cursor int64, limit int) ([]PublishedArticle, error) {
+	return a.listPubByTag(ctx, "published_articles", tag, cursor, limit)
+}
+
+// listPubByTag table 是线上库的表，ArticleS3DAO 用的是另外一张表
+func (a *ArticleGORMDAO) listPubByTag(ctx context.Context, table string,
+	tag string, cursor int64, limit int) ([]PublishedArticle, error) {
+	db := a.db.WithContext(ctx)
+	var t Tag
+	err := db.Where("name = ?", tag).First(&t).Error
+	if err == gorm.ErrRecordNotFound {
+		return []PublishedArticle{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// 按照文章 ID 倒序，新发表的在前面，cursor 是上一页最后一篇的 ID
+	query := db.Table(table).
+		Joins("JOIN published_article_tags ON published_article_tags.article_id = "+table+".id").
+		Where("published_article_tags.tag_id = ? AND "+table+".status = ? AND "+table+".visibility = ?",
+			t.Id, articleStatusPublished, articleVisibilityPublic)
+	if cursor > 0 {
+		query = query.Where(table+".id < ?", cursor)
+	}
+	var res []PublishedArticle
+	err = query.Select(table + ".*").
+		Order(table + ".id DESC").
+		Limit(limit).
+		Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+	return res, a.fillPubTags(ctx, res)
+}
+
+func (a *ArticleGORMDAO) CountPubTags(ctx context.Context, limit int) ([]TagCount, error) {
+	return a.countPubTags(ctx, "published_articles", limit)
+}
+
+func (a *ArticleGORMDAO) countPubTags(ctx context.Context, table string, limit int) ([]TagCount, error) {
+	// 撤回的文章还留着标签关系，要和线上库的状态一起过滤
+	var res []TagCount
+	err := a.db.WithContext(ctx).Model(&PublishedArticleTag{}).
+		Select("tags.name AS name, COUNT(*) AS cnt").
+		Joins("JOIN tags ON tags.id = published_article_tags.tag_id").
+		Joins("JOIN "+table+" ON "+table+".id = published_article_tags.article_id").
+		Where(table+".status = ? AND "+table+".visibility = ?", articleStatusPublished, articleVisibilityPublic).
+		Group("published_article_tags.tag_id, tags.name").
+		Order("cnt DESC, name").
+		Limit(limit).
+		Scan(&res).Error
+	return res, err
+}
+
+func (a *ArticleGORMDAO) GetPubTags(ctx context.Context, ids []int64) (map[int64][]string, error) {
+	return a.findTags(ctx, "published_article_tags", ids)
+}
+
+// findTags 按照打标签的顺序返回每篇文章的标签，table 是制作库或者线上库的关系表
+func (a *ArticleGORMDAO) findTags(ctx context.Context, table string, ids []int64) (map[int64][]string, error) {
+	res := make(map[int64][]string, len(ids))
+	if len(ids) == 0 {
+		return res, nil
+	}
+	var rows []articleTagRow
+	err := a.db.WithContext(ctx).Table(table).
+		Select(table+".article_id AS article_id, tags.name AS name").
+		Joins("JOIN tags ON tags.id = "+table+".tag_id").
+		Where(table+".article_id IN ?", ids).
+		Order(table + ".id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		res[row.ArticleId] = append(res[row.ArticleId], row.Name)
+	}
+	return res, nil
+}
+
+func (a *ArticleGORMDAO) fillTags(ctx context.Context, arts []Article) error {
+	tags, err := a.findTags(ctx, "article_tags",
+		slice.Map(arts, func(idx int, src Article) int64 { return src.Id }))
+	if err != nil {
+		return err
+	}
+	for i := range arts {
+		arts[i].Tags = tags[arts[i].Id]
+	}
+	return nil
+}
+
+func (a *ArticleGORMDAO) fillPubTags(ctx context.Context, arts []PublishedArticle) error {
+	tags, err := a.GetPubTags(ctx,
+		slice.Map(arts, func(idx int, src PublishedArticle) int64 { return src.Id }))
+	if err != nil {
+		return err
+	}
+	for i := range arts {
+		arts[i].Tags = tags[arts[i].Id]
+	}
+	return nil
+}
+
+// replaceTags 把制作库里面文章的标签整个换成 names
+func (a *ArticleGORMDAO) replaceTags(tx *gorm.DB, artId int64, names []string, now int64) error {
+	err := tx.Where("article_id = ?", artId).Delete(&ArticleTag{}).Error
+	if err != nil || len(names) == 0 {
+		return err
+	}
+	tags := slice.Map(names, func(idx int, src string) Tag {
+		return Tag{Name: src, Ctime: now}
+	})
+	// 标签可能已经有了，也可能被别人并发创建，冲突了就什么都不做，后面再查一遍 ID
+	err = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error
+	if err != nil {
+		return err
+	}
+	var found []Tag
+	err = tx.Where("name IN ?", names).Find(&found).Error
+	if err != nil {
+		return err
+	}
+	ids := make(map[string]int64, len(found))
+	for _, t := range found {
+		ids[t.Name] = t.Id
+	}
+	rels := slice.Map(names, func(idx int, src string) ArticleTag {
+		return ArticleTag{TagId: ids[src], ArticleId: artId, Ctime: now}
+	})
+	return tx.Create(&rels).Error
+}
+
+// syncPubTags 发表的时候，线上库的标签和制作库保持一致
+func (a *ArticleGORMDAO) syncPubTags(tx *gorm.DB, artId int64) error {
+	err := tx.Where("article_id = ?", artId).Delete(&PublishedArticleTag{}).Error
+	if err != nil {
+		return err
+	}
+	var rels []ArticleTag
+	err = tx.Where("article_id = ?", artId).Order("id").Find(&rels).Error
+	if err != nil || len(rels) == 0 {
+		return err
+	}
+	now := time.Now().UnixMilli()
+	pubRels := slice.Map(rels, func(idx int, src ArticleTag) PublishedArticleTag {
+		return PublishedArticleTag{TagId: src.TagId, ArticleId: src.ArticleId, Ctime: now}
+	})
+	return tx.Create(&pubRels).Error
+}
@@ -0,0 +1,156 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestArticleGORMDAO_SyncTags 制作库的标签整个替换掉，线上库从制作库复制过去
+func TestArticleGORMDAO_SyncTags(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db := newMockGORM(t, sqlDB)
+	art := Article{Id: 1, AuthorId: 123, Title: "标题", Content: "内容",
+		Status: articleStatusReviewing, Revision: 2, Tags: []string{"go", "后端"}}
+
+	// 提交审核的时候替换制作库的标签
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `articles` SET").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `article_tags` WHERE article_id = \\?").
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// 标签已经有了就什么都不做
+	mock.ExpectExec("INSERT INTO `tags` .* ON DUPLICATE KEY UPDATE `id`=`id`").
+		WithArgs("go", sqlmock.AnyArg(), "后端", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(11, 1))
+	mock.ExpectQuery("SELECT \\* FROM `tags` WHERE name IN \\(\\?,\\?\\)").
+		WithArgs("go", "后端").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "ctime"}).
+			AddRow(10, "go", 500).
+			AddRow(11, "后端", 1000))
+	mock.ExpectExec("INSERT INTO `article_tags`").
+		WithArgs(int64(10), int64(1), sqlmock.AnyArg(), int64(11), int64(1), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectExec("DELETE FROM `article_attachments`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT `version`,`hash` FROM `article_revisions`").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "hash"}).
+			AddRow(2, revisionHash("标题", "内容")))
+	mock.ExpectExec("UPDATE `articles` SET `revision`=\\?").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// 审核通过之后线上库从制作库复制过去
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `articles` SET").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT `version`,`hash` FROM `article_revisions`").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "hash"}).
+			AddRow(2, revisionHash("标题", "内容")))
+	mock.ExpectExec("UPDATE `articles` SET `revision`=\\?").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO `published_articles`").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("DELETE FROM `published_article_tags` WHERE article_id = \\?").
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT \\* FROM `article_tags` WHERE article_id = \\? ORDER BY id").
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tag_id", "article_id", "ctime"}).
+			AddRow(1, 10, 1, 1000).
+			AddRow(2, 11, 1, 1000))
+	mock.ExpectExec("INSERT INTO `published_article_tags`").
+		WithArgs(int64(10), int64(1), sqlmock.AnyArg(), int64(11), int64(1), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectExec("DELETE FROM `published_article_attachments`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT \\* FROM `article_attachments`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "article_id", "attachment_key", "ctime"}))
+	mock.ExpectCommit()
+
+	dao := NewArticleGORMDAO(db)
+	err = dao.UpdateById(context.Background(), art)
+	require.NoError(t, err)
+	art.Status = articleStatusPublished
+	id, err := dao.Sync(context.Background(), art)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArticleGORMDAO_ListPubByTag(t *testing.T) {
+	testCases := []struct {
+		name   string
+		mock   func(mock sqlmock.Sqlmock)
+		cursor int64
+
+		wantArts []PublishedArticle
+	}{
+		{
+			name: "第一页",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `tags` WHERE name = \\?").
+					WithArgs("go", 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(10, "go"))
+				mock.ExpectQuery("SELECT published_articles.\\* FROM `published_articles` "+
+					"JOIN published_article_tags ON published_article_tags.article_id = published_articles.id "+
+					"WHERE published_article_tags.tag_id = \\? AND published_articles.status = \\? "+
+					"AND published_articles.visibility = \\? "+
+					"ORDER BY published_articles.id DESC LIMIT \\?").
+					WithArgs(int64(10), articleStatusPublished, articleVisibilityPublic, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).
+						AddRow(3, "第三篇").
+						AddRow(2, "第二篇"))
+				mock.ExpectQuery("SELECT published_article_tags.article_id AS article_id, tags.name AS name "+
+					"FROM `published_article_tags` JOIN tags ON tags.id = published_article_tags.tag_id "+
+					"WHERE published_article_tags.article_id IN \\(\\?,\\?\\) ORDER BY published_article_tags.id").
+					WithArgs(int64(3), int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"article_id", "name"}).
+						AddRow(3, "go").
+						AddRow(3, "后端").
+						AddRow(2, "go"))
+			},
+			wantArts: []PublishedArticle{
+				{Id: 3, Title: "第三篇", Tags: []string{"go", "后端"}},
+				{Id: 2, Title: "第二篇", Tags: []string{"go"}},
+			},
+		},
+		{
+			name: "带上游标",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `tags` WHERE name = \\?").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(10, "go"))
+				mock.ExpectQuery("AND published_articles.id < \\? ORDER BY").
+					WithArgs(int64(10), articleStatusPublished, articleVisibilityPublic, int64(2), 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title"}))
+			},
+			cursor:   2,
+			wantArts: []PublishedArticle{},
+		},
+		{
+			name: "标签不存在",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `tags` WHERE name = \\?").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+			},
+			wantArts: []PublishedArticle{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			db := newMockGORM(t, sqlDB)
+			tc.mock(mock)
+			arts, err := NewArticleGORMDAO(db).ListPubByTag(context.Background(), "go", tc.cursor, 2)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantArts, arts)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestArticleGORMDAO_GetByAuthorByCursor 第一页不带游标，后面的页按照 (utime, id) 往后翻
+func TestArticleGORMDAO_GetByAuthorByCursor(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db := newMockGORM(t, sqlDB)
+
+	mock.ExpectQuery("SELECT \\* FROM `articles` WHERE author_id = \\? "+
+		"ORDER BY utime DESC, id DESC LIMIT \\?").
+		WithArgs(int64(123), 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "author_id", "utime"}).
+			AddRow(3, 123, 2000).
+			AddRow(2, 123, 1000))
+	mock.ExpectQuery("SELECT article_tags.article_id AS article_id, tags.name AS name").
+		WillReturnRows(sqlmock.NewRows([]string{"article_id", "name"}).
+			AddRow(3, "go"))
+	mock.ExpectQuery("SELECT \\* FROM `articles` WHERE author_id = \\? "+
+		"AND \\(utime < \\? OR \\(utime = \\? AND id < \\?\\)\\) "+
+		"ORDER BY utime DESC, id DESC LIMIT \\?").
+		WithArgs(int64(123), int64(1000), int64(1000), int64(2), 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "author_id", "utime"}).
+			AddRow(1, 123, 1000))
+	mock.ExpectQuery("SELECT article_tags.article_id AS article_id, tags.name AS name").
+		WillReturnRows(sqlmock.NewRows([]string{"article_id", "name"}))
+
+	dao := NewArticleGORMDAO(db)
+	arts, err := dao.GetByAuthorByCursor(context.Background(), 123, 0, 0, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []Article{
+		{Id: 3, AuthorId: 123, Utime: 2000, Tags: []string{"go"}},
+		{Id: 2, AuthorId: 123, Utime: 1000},
+	}, arts)
+	last := arts[len(arts)-1]
+	arts, err = dao.GetByAuthorByCursor(context.Background(), 123, last.Utime, last.Id, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []Article{{Id: 1, AuthorId: 123, Utime: 1000}}, arts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArticleDAO_ListPubByCursor(t *testing.T) {
+	start := time.UnixMilli(5000)
+	testCases := []struct {
+		name  string
+		dao   func(sqlDB *ArticleGORMDAO) ArticleDAO
+		table string
+	}{
+		{
+			name:  "GORM",
+			dao:   func(d *ArticleGORMDAO) ArticleDAO { return d },
+			table: "published_articles",
+		},
+		{
+			// 线上库是另外一张表
+			name:  "S3",
+			dao:   func(d *ArticleGORMDAO) ArticleDAO { return &ArticleS3DAO{ArticleGORMDAO: *d} },
+			table: "published_article_v2",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			db := newMockGORM(t, sqlDB)
+			// 只列出公开的文章
+			mock.ExpectQuery("SELECT \\* FROM `"+tc.table+"` WHERE \\(utime < \\? AND status = \\? AND visibility = \\?\\) "+
+				"AND \\(utime < \\? OR \\(utime = \\? AND id < \\?\\)\\) "+
+				"ORDER BY utime DESC, id DESC LIMIT \\?").
+				WithArgs(int64(5000), articleStatusPublished, articleVisibilityPublic, int64(1000), int64(1000), int64(2), 10).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "status", "utime"}).
+					AddRow(1, articleStatusPublished, 1000))
+
+			d := tc.dao(&ArticleGORMDAO{db: db})
+			arts, err := d.ListPubByCursor(context.Background(), start, 1000, 2, 10)
+			require.NoError(t, err)
+			assert.Equal(t, []PublishedArticle{
+				{Id: 1, Status: articleStatusPublished, Utime: 1000},
+			}, arts)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestArticleGORMDAO_SyncStatus 制作库要同时按照 id 和作者过滤，线上库按照 id 更新
+func TestArticleGORMDAO_SyncStatus(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db := newMockGORM(t, sqlDB)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `articles` SET `status`=\\?,`utime`=\\? WHERE id = \\? and author_id = \\?").
+		WithArgs(uint8(3), sqlmock.AnyArg(), int64(1), int64(123)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE `published_articles` SET `status`=\\?,`utime`=\\? WHERE id = \\?").
+		WithArgs(uint8(3), sqlmock.AnyArg(), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// 别人的文章
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `articles`").
+		WithArgs(uint8(3), sqlmock.AnyArg(), int64(1), int64(456)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	dao := NewArticleGORMDAO(db)
+	err = dao.SyncStatus(context.Background(), 123, 1, 3)
+	require.NoError(t, err)
+	err = dao.SyncStatus(context.Background(), 456, 1, 3)
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
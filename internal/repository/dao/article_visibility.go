@@ -0,0 +1,44 @@
+package dao
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// articleVisibilityPublic 只有公开的文章会出现在列表、标签页里面
+const articleVisibilityPublic = 0
+
+// withVisibilities 只查 visibilities 里面的可见范围，为空就不过滤
+func withVisibilities(query *gorm.DB, visibilities []uint8) *gorm.DB {
+	if len(visibilities) == 0 {
+		return query
+	}
+	// []uint8 就是 []byte，直接传进去会被当成一个二进制的参数
+	vs := make([]int, 0, len(visibilities))
+	for _, v := range visibilities {
+		vs = append(vs, int(v))
+	}
+	return query.Where("visibility IN ?", vs)
+}
+
+// syncVisibility 制作库和线上库一起改，pub 是线上库的模型。
+// 还没有发表过的文章线上库没有记录，所以只检查制作库
+func syncVisibility(tx *gorm.DB, pub any, uid int64, id int64, visibility uint8, now int64) error {
+	updates := map[string]any{
+		"utime":      now,
+		"visibility": visibility,
+	}
+	res := tx.Model(&Article{}).
+		Where("id = ? AND author_id = ?", id, uid).
+		Updates(updates)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected != 1 {
+		return errors.New("ID 不对或者创作者不对")
+	}
+	return tx.Model(pub).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
@@ -0,0 +1,76 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticleGORMDAO_GetPubByAuthor(t *testing.T) {
+	testCases := []struct {
+		name         string
+		visibilities []uint8
+		mock         func(mock sqlmock.Sqlmock)
+	}{
+		{
+			// 作者自己看，或者导出数据
+			name: "全部",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `published_articles` WHERE author_id = \\? AND status = \\? "+
+					"ORDER BY id LIMIT \\?").
+					WithArgs(int64(123), articleStatusPublished, 10).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+		},
+		{
+			name:         "只看公开的和关注者可见的",
+			visibilities: []uint8{0, 2},
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `published_articles` WHERE \\(author_id = \\? AND status = \\?\\) "+
+					"AND visibility IN \\(\\?,\\?\\) ORDER BY id LIMIT \\?").
+					WithArgs(int64(123), articleStatusPublished, 0, 2, 10).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			db := newMockGORM(t, sqlDB)
+			tc.mock(mock)
+			_, err = NewArticleGORMDAO(db).GetPubByAuthor(context.Background(), 123, tc.visibilities, 0, 10)
+			require.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestArticleGORMDAO_SyncVisibility(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db := newMockGORM(t, sqlDB)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `articles` SET `utime`=\\?,`visibility`=\\? WHERE id = \\? AND author_id = \\?").
+		WithArgs(sqlmock.AnyArg(), uint8(3), int64(1), int64(123)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE `published_articles` SET `utime`=\\?,`visibility`=\\? WHERE id = \\?").
+		WithArgs(sqlmock.AnyArg(), uint8(3), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err = NewArticleGORMDAO(db).SyncVisibility(context.Background(), 123, 1, 3)
+	require.NoError(t, err)
+
+	// 别人的文章
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `articles` SET").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	err = NewArticleGORMDAO(db).SyncVisibility(context.Background(), 456, 1, 3)
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
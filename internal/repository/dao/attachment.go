@@ -0,0 +1,87 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+//go:generate mockgen -source=./attachment.go -package=daomocks -destination=./mocks/attachment.mock.go AttachmentDAO
+type AttachmentDAO interface {
+	// Upsert 同样的内容已经上传过了的话只更新 utime，返回原本的那条
+	Upsert(ctx context.Context, a Attachment) (Attachment, error)
+	FindByKey(ctx context.Context, key string) (Attachment, error)
+	// ListStale utime 早于 before 的附件，按照 ID 翻页，cursor 是上一页最后一个的 ID
+	ListStale(ctx context.Context, before int64, cursor int64, limit int) ([]Attachment, error)
+	// DeleteStale 删除之前再确认一下 utime，这中间被重新上传过的就不删了
+	DeleteStale(ctx context.Context, id int64, before int64) (bool, error)
+}
+
+type GORMAttachmentDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMAttachmentDAO(db *gorm.DB) AttachmentDAO {
+	return &GORMAttachmentDAO{db: db}
+}
+
+func (dao *GORMAttachmentDAO) Upsert(ctx context.Context, a Attachment) (Attachment, error) {
+	now := time.Now().UnixMilli()
+	a.Ctime = now
+	a.Utime = now
+	db := dao.db.WithContext(ctx)
+	err := db.Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"utime": now,
+		}),
+	}).Create(&a).Error
+	if err != nil {
+		return Attachment{}, err
+	}
+	// 冲突的时候 MySQL 不会返回原本的 ID，重新查一遍
+	var res Attachment
+	err = db.Where("`key` = ?", a.Key).First(&res).Error
+	return res, err
+}
+
+func (dao *GORMAttachmentDAO) FindByKey(ctx context.Context, key string) (Attachment, error) {
+	var res Attachment
+	err := dao.db.WithContext(ctx).Where("`key` = ?", key).First(&res).Error
+	return res, err
+}
+
+func (dao *GORMAttachmentDAO) ListStale(ctx context.Context, before int64, cursor int64, limit int) ([]Attachment, error) {
+	var res []Attachment
+	err := dao.db.WithContext(ctx).
+		Where("utime < ? AND id > ?", before, cursor).
+		Order("id").Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMAttachmentDAO) DeleteStale(ctx context.Context, id int64, before int64) (bool, error) {
+	res := dao.db.WithContext(ctx).
+		Where("id = ? AND utime < ?", id, before).
+		Delete(&Attachment{})
+	return res.RowsAffected > 0, res.Error
+}
+
+// Attachment 上传的图片和文件。Key 是处理过之后的内容的 SHA256，同样的内容只存一份。
+// 文章引用了哪些附件记在 ArticleAttachment 里面
+type Attachment struct {
+	Id  int64  `gorm:"primaryKey,autoIncrement"`
+	Key string `gorm:"type:char(64);uniqueIndex"`
+	// 第一次上传的人
+	Uid      int64
+	Name     string `gorm:"type:varchar(255)"`
+	MimeType string `gorm:"type:varchar(64)"`
+	Size     int64
+	// 图片才有宽高
+	Width  int
+	Height int
+	// 没有缩略图的时候是空的
+	ThumbMimeType string `gorm:"type:varchar(64)"`
+	Ctime         int64
+	// 每次上传都会更新，清理的时候只看很久没有动过的
+	Utime int64 `gorm:"index"`
+}
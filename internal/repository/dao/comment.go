@@ -0,0 +1,192 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+//go:generate mockgen -source=./comment.go -package=daomocks -destination=./mocks/comment.mock.go CommentDAO
+type CommentDAO interface {
+	// Insert 插入评论，回复的话顺便把顶级评论的回复数加一
+	Insert(ctx context.Context, c Comment) (int64, error)
+	FindById(ctx context.Context, id int64) (Comment, error)
+	// ListByTime 顶级评论，按照 id 倒序，cursor 是上一页最后一条的 id，第一页传 0
+	ListByTime(ctx context.Context, biz string, bizId int64, cursor int64, limit int) ([]Comment, error)
+	// ListByLikes 顶级评论，按照 like_cnt、id 倒序，
+	// cursorLikeCnt 和 cursorId 是上一页最后一条的，第一页 cursorId 传 0
+	ListByLikes(ctx context.Context, biz string, bizId int64,
+		cursorLikeCnt int64, cursorId int64, limit int) ([]Comment, error)
+	// ListReplies 顶级评论下面的回复，按照 id 正序，也就是按照回复的先后顺序
+	ListReplies(ctx context.Context, rootId int64, cursor int64, limit int) ([]Comment, error)
+	// Delete 删除评论，顶级评论会连着下面的回复一起删掉。
+	// 返回一共删掉了几条，已经被删掉了返回 0
+	Delete(ctx context.Context, c Comment) (int64, error)
+	// InsertLike 重复点赞不会重复计数
+	InsertLike(ctx context.Context, uid int64, cid int64) error
+	DeleteLike(ctx context.Context, uid int64, cid int64) error
+	// FindLikedIds cids 里面 uid 点赞过的
+	FindLikedIds(ctx context.Context, uid int64, cids []int64) ([]int64, error)
+}
+
+type GORMCommentDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMCommentDAO(db *gorm.DB) CommentDAO {
+	return &GORMCommentDAO{db: db}
+}
+
+func (dao *GORMCommentDAO) Insert(ctx context.Context, c Comment) (int64, error) {
+	now := time.Now().UnixMilli()
+	c.Ctime = now
+	c.Utime = now
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&c).Error
+		if err != nil || c.RootId == 0 {
+			return err
+		}
+		return tx.Model(&Comment{}).Where("id = ?", c.RootId).
+			Updates(map[string]any{
+				"reply_cnt": gorm.Expr("`reply_cnt` + 1"),
+				"utime":     now,
+			}).Error
+	})
+	return c.Id, err
+}
+
+func (dao *GORMCommentDAO) FindById(ctx context.Context, id int64) (Comment, error) {
+	var res Comment
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&res).Error
+	return res, err
+}
+
+func (dao *GORMCommentDAO) ListByTime(ctx context.Context,
+	biz string, bizId int64, cursor int64, limit int) ([]Comment, error) {
+	var res []Comment
+	query := dao.db.WithContext(ctx).
+		Where("biz = ? AND biz_id = ? AND root_id = ?", biz, bizId, 0)
+	if cursor > 0 {
+		query = query.Where("id < ?", cursor)
+	}
+	err := query.Order("id DESC").Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMCommentDAO) ListByLikes(ctx context.Context, biz string, bizId int64,
+	cursorLikeCnt int64, cursorId int64, limit int) ([]Comment, error) {
+	var res []Comment
+	query := dao.db.WithContext(ctx).
+		Where("biz = ? AND biz_id = ? AND root_id = ?", biz, bizId, 0)
+	if cursorId > 0 {
+		// 翻页的过程中点赞数变了，可能会重复或者漏掉几条，评论区可以接受
+		query = query.Where("like_cnt < ? OR (like_cnt = ? AND id < ?)",
+			cursorLikeCnt, cursorLikeCnt, cursorId)
+	}
+	err := query.Order("like_cnt DESC, id DESC").Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMCommentDAO) ListReplies(ctx context.Context,
+	rootId int64, cursor int64, limit int) ([]Comment, error) {
+	var res []Comment
+	err := dao.db.WithContext(ctx).
+		Where("root_id = ? AND id > ?", rootId, cursor).
+		Order("id").Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMCommentDAO) Delete(ctx context.Context, c Comment) (int64, error) {
+	var cnt int64
+	now := time.Now().UnixMilli()
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if c.RootId == 0 {
+			// 顶级评论连着回复一起删，点赞记录也没用了
+			ids := tx.Model(&Comment{}).Select("id").
+				Where("id = ? OR root_id = ?", c.Id, c.Id)
+			err := tx.Where("comment_id IN (?)", ids).Delete(&CommentLike{}).Error
+			if err != nil {
+				return err
+			}
+			res := tx.Where("id = ? OR root_id = ?", c.Id, c.Id).Delete(&Comment{})
+			cnt = res.RowsAffected
+			return res.Error
+		}
+		// 回复的回复保留下来，前端显示回复的评论已经被删除了
+		res := tx.Where("id = ?", c.Id).Delete(&Comment{})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		cnt = res.RowsAffected
+		err := tx.Where("comment_id = ?", c.Id).Delete(&CommentLike{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&Comment{}).Where("id = ?", c.RootId).
+			Updates(map[string]any{
+				"reply_cnt": gorm.Expr("`reply_cnt` - 1"),
+				"utime":     now,
+			}).Error
+	})
+	return cnt, err
+}
+
+func (dao *GORMCommentDAO) InsertLike(ctx context.Context, uid int64, cid int64) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&CommentLike{Uid: uid, CommentId: cid, Ctime: now})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return tx.Model(&Comment{}).Where("id = ?", cid).
+			Update("like_cnt", gorm.Expr("`like_cnt` + 1")).Error
+	})
+}
+
+func (dao *GORMCommentDAO) DeleteLike(ctx context.Context, uid int64, cid int64) error {
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("uid = ? AND comment_id = ?", uid, cid).Delete(&CommentLike{})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return tx.Model(&Comment{}).Where("id = ?", cid).
+			Update("like_cnt", gorm.Expr("`like_cnt` - 1")).Error
+	})
+}
+
+func (dao *GORMCommentDAO) FindLikedIds(ctx context.Context, uid int64, cids []int64) ([]int64, error) {
+	if len(cids) == 0 {
+		return nil, nil
+	}
+	var res []int64
+	err := dao.db.WithContext(ctx).Model(&CommentLike{}).
+		Where("uid = ? AND comment_id IN ?", uid, cids).
+		Pluck("comment_id", &res).Error
+	return res, err
+}
+
+// Comment 评论
+type Comment struct {
+	Id  int64 `gorm:"primaryKey,autoIncrement"`
+	Uid int64 `gorm:"index"`
+	// 顶级评论列表走 biz_type_id_root
+	Biz      string `gorm:"type:varchar(128);index:biz_type_id_root,priority:1"`
+	BizId    int64  `gorm:"index:biz_type_id_root,priority:2"`
+	RootId   int64  `gorm:"index:biz_type_id_root,priority:3;index:root_id"`
+	ParentId int64
+	Content  string `gorm:"type:text"`
+	LikeCnt  int64
+	ReplyCnt int64
+	Ctime    int64
+	Utime    int64
+}
+
+// CommentLike 评论的点赞，取消点赞直接删掉
+type CommentLike struct {
+	Id        int64 `gorm:"primaryKey,autoIncrement"`
+	Uid       int64 `gorm:"uniqueIndex:uid_comment_id"`
+	CommentId int64 `gorm:"uniqueIndex:uid_comment_id;index"`
+	Ctime     int64
+}
@@ -0,0 +1,84 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGORMCommentDAO_Delete 删除回复要把顶级评论的回复数减一，重复删除不能重复减
+func TestGORMCommentDAO_Delete(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db := newMockGORM(t, sqlDB)
+	reply := Comment{Id: 11, Biz: "article", BizId: 1, RootId: 10, ParentId: 10}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `comments` WHERE id = \\?").
+		WithArgs(int64(11)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `comment_likes` WHERE comment_id = \\?").
+		WithArgs(int64(11)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("UPDATE `comments` SET `reply_cnt`=`reply_cnt` - 1,`utime`=\\? WHERE id = \\?").
+		WithArgs(sqlmock.AnyArg(), int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// 并发删除，另外一个请求已经删掉了
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `comments` WHERE id = \\?").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	// 顶级评论连着回复一起删
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `comment_likes` WHERE comment_id IN "+
+		"\\(SELECT `id` FROM `comments` WHERE id = \\? OR root_id = \\?\\)").
+		WithArgs(int64(10), int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("DELETE FROM `comments` WHERE id = \\? OR root_id = \\?").
+		WithArgs(int64(10), int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectCommit()
+
+	dao := NewGORMCommentDAO(db)
+	cnt, err := dao.Delete(context.Background(), reply)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), cnt)
+	cnt, err = dao.Delete(context.Background(), reply)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), cnt)
+	cnt, err = dao.Delete(context.Background(), Comment{Id: 10, Biz: "article", BizId: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), cnt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGORMCommentDAO_InsertLike 重复点赞不会重复计数
+func TestGORMCommentDAO_InsertLike(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db := newMockGORM(t, sqlDB)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `comment_likes` .* ON DUPLICATE KEY UPDATE").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE `comments` SET `like_cnt`=`like_cnt` \\+ 1 WHERE id = \\?").
+		WithArgs(int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `comment_likes` .* ON DUPLICATE KEY UPDATE").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	dao := NewGORMCommentDAO(db)
+	require.NoError(t, dao.InsertLike(context.Background(), 123, 10))
+	require.NoError(t, dao.InsertLike(context.Background(), 123, 10))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
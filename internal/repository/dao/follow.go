@@ -0,0 +1,118 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"time"
+)
+
+var ErrFollowRelationExist = errors.New("已经关注过了")
+
+//go:generate mockgen -source=./follow.go -package=daomocks -destination=./mocks/follow.mock.go FollowRelationDAO
+type FollowRelationDAO interface {
+	// Insert 已经关注过了返回 ErrFollowRelationExist
+	Insert(ctx context.Context, r FollowRelation) error
+	// Delete 没有关注过返回 ErrRecordNotFound
+	Delete(ctx context.Context, follower int64, followee int64) error
+	// FollowerList 关注了 followee 的人，按照 id 倒序，也就是最近关注的在前面。
+	// cursor 是上一页最后一条的 id，第一页传 0
+	FollowerList(ctx context.Context, followee int64, cursor int64, limit int) ([]FollowRelation, error)
+	// FolloweeList follower 关注的人，分页方式和 FollowerList 一样
+	FolloweeList(ctx context.Context, follower int64, cursor int64, limit int) ([]FollowRelation, error)
+	FollowRelationDetail(ctx context.Context, follower int64, followee int64) (FollowRelation, error)
+	CntFollower(ctx context.Context, uid int64) (int64, error)
+	CntFollowee(ctx context.Context, uid int64) (int64, error)
+}
+
+type GORMFollowRelationDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMFollowRelationDAO(db *gorm.DB) FollowRelationDAO {
+	return &GORMFollowRelationDAO{db: db}
+}
+
+func (dao *GORMFollowRelationDAO) Insert(ctx context.Context, r FollowRelation) error {
+	now := time.Now().UnixMilli()
+	r.Ctime = now
+	r.Utime = now
+	err := dao.db.WithContext(ctx).Create(&r).Error
+	if me, ok := err.(*mysql.MySQLError); ok {
+		const duplicateErr uint16 = 1062
+		if me.Number == duplicateErr {
+			return ErrFollowRelationExist
+		}
+	}
+	return err
+}
+
+func (dao *GORMFollowRelationDAO) Delete(ctx context.Context, follower int64, followee int64) error {
+	// 取消关注直接删掉，重新关注的时候会拿到新的 id，排在列表的最前面
+	res := dao.db.WithContext(ctx).
+		Where("follower = ? AND followee = ?", follower, followee).
+		Delete(&FollowRelation{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (dao *GORMFollowRelationDAO) FollowerList(ctx context.Context,
+	followee int64, cursor int64, limit int) ([]FollowRelation, error) {
+	var res []FollowRelation
+	query := dao.db.WithContext(ctx).Where("followee = ?", followee)
+	if cursor > 0 {
+		query = query.Where("id < ?", cursor)
+	}
+	err := query.Order("id DESC").Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMFollowRelationDAO) FolloweeList(ctx context.Context,
+	follower int64, cursor int64, limit int) ([]FollowRelation, error) {
+	var res []FollowRelation
+	query := dao.db.WithContext(ctx).Where("follower = ?", follower)
+	if cursor > 0 {
+		query = query.Where("id < ?", cursor)
+	}
+	err := query.Order("id DESC").Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMFollowRelationDAO) FollowRelationDetail(ctx context.Context,
+	follower int64, followee int64) (FollowRelation, error) {
+	var res FollowRelation
+	err := dao.db.WithContext(ctx).
+		Where("follower = ? AND followee = ?", follower, followee).
+		First(&res).Error
+	return res, err
+}
+
+func (dao *GORMFollowRelationDAO) CntFollower(ctx context.Context, uid int64) (int64, error) {
+	var res int64
+	err := dao.db.WithContext(ctx).Model(&FollowRelation{}).
+		Where("followee = ?", uid).Count(&res).Error
+	return res, err
+}
+
+func (dao *GORMFollowRelationDAO) CntFollowee(ctx context.Context, uid int64) (int64, error) {
+	var res int64
+	err := dao.db.WithContext(ctx).Model(&FollowRelation{}).
+		Where("follower = ?", uid).Count(&res).Error
+	return res, err
+}
+
+// FollowRelation 关注关系
+type FollowRelation struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// 查关注列表走 follower_followee，查粉丝列表走 followee_id
+	Follower int64 `gorm:"uniqueIndex:follower_followee"`
+	Followee int64 `gorm:"uniqueIndex:follower_followee;index:followee_id"`
+	Ctime    int64
+	Utime    int64
+}
@@ -0,0 +1,53 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+//go:generate mockgen -source=./history.go -package=daomocks -destination=./mocks/history.mock.go HistoryRecordDAO
+type HistoryRecordDAO interface {
+	// Upsert 同一个资源只记录一条，重复浏览只更新时间
+	Upsert(ctx context.Context, r HistoryRecord) error
+	FindByUid(ctx context.Context, uid int64) ([]HistoryRecord, error)
+}
+
+type GORMHistoryRecordDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMHistoryRecordDAO(db *gorm.DB) HistoryRecordDAO {
+	return &GORMHistoryRecordDAO{db: db}
+}
+
+func (dao *GORMHistoryRecordDAO) Upsert(ctx context.Context, r HistoryRecord) error {
+	now := time.Now().UnixMilli()
+	r.Ctime = now
+	r.Utime = now
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"utime": now,
+		}),
+	}).Create(&r).Error
+}
+
+func (dao *GORMHistoryRecordDAO) FindByUid(ctx context.Context, uid int64) ([]HistoryRecord, error) {
+	var res []HistoryRecord
+	err := dao.db.WithContext(ctx).
+		Where("uid = ?", uid).
+		Order("utime DESC").
+		Find(&res).Error
+	return res, err
+}
+
+// HistoryRecord 浏览记录
+type HistoryRecord struct {
+	Id    int64  `gorm:"primaryKey,autoIncrement"`
+	Uid   int64  `gorm:"uniqueIndex:uid_biz_type_id"`
+	BizId int64  `gorm:"uniqueIndex:uid_biz_type_id"`
+	Biz   string `gorm:"type:varchar(128);uniqueIndex:uid_biz_type_id"`
+	Ctime int64
+	Utime int64
+}
@@ -0,0 +1,109 @@
+package dao
+
+import (
+	"context"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"time"
+)
+
+//go:generate mockgen -source=./identity.go -package=daomocks -destination=./mocks/identity.mock.go IdentityDAO
+type IdentityDAO interface {
+	// Insert 同一个第三方账号只能绑定一个用户，冲突的时候返回 ErrDuplicateEmail
+	Insert(ctx context.Context, identity UserIdentity) error
+	// InsertWithUser 第一次用第三方登录，同时创建用户和绑定关系，返回用户 id
+	InsertWithUser(ctx context.Context, u User, identity UserIdentity) (int64, error)
+	FindBySubject(ctx context.Context, provider string, subject string) (UserIdentity, error)
+	FindByUid(ctx context.Context, uid int64) ([]UserIdentity, error)
+	// Delete 没有绑定的话返回 ErrRecordNotFound
+	Delete(ctx context.Context, uid int64, provider string) error
+}
+
+type GORMIdentityDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMIdentityDAO(db *gorm.DB) IdentityDAO {
+	return &GORMIdentityDAO{db: db}
+}
+
+func (dao *GORMIdentityDAO) Insert(ctx context.Context, identity UserIdentity) error {
+	now := time.Now().UnixMilli()
+	identity.Ctime = now
+	identity.Utime = now
+	err := dao.db.WithContext(ctx).Create(&identity).Error
+	if me, ok := err.(*mysql.MySQLError); ok {
+		const duplicateErr uint16 = 1062
+		if me.Number == duplicateErr {
+			return ErrDuplicateEmail
+		}
+	}
+	return err
+}
+
+func (dao *GORMIdentityDAO) InsertWithUser(ctx context.Context,
+	u User, identity UserIdentity) (int64, error) {
+	now := time.Now().UnixMilli()
+	u.Ctime = now
+	u.Utime = now
+	identity.Ctime = now
+	identity.Utime = now
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&u).Error
+		if err != nil {
+			return err
+		}
+		identity.Uid = u.Id
+		return tx.Create(&identity).Error
+	})
+	if me, ok := err.(*mysql.MySQLError); ok {
+		const duplicateErr uint16 = 1062
+		if me.Number == duplicateErr {
+			return 0, ErrDuplicateEmail
+		}
+	}
+	return u.Id, err
+}
+
+func (dao *GORMIdentityDAO) FindBySubject(ctx context.Context,
+	provider string, subject string) (UserIdentity, error) {
+	var res UserIdentity
+	err := dao.db.WithContext(ctx).
+		Where("provider = ? AND subject = ?", provider, subject).
+		First(&res).Error
+	return res, err
+}
+
+func (dao *GORMIdentityDAO) FindByUid(ctx context.Context, uid int64) ([]UserIdentity, error) {
+	var res []UserIdentity
+	err := dao.db.WithContext(ctx).
+		Where("uid = ?", uid).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMIdentityDAO) Delete(ctx context.Context, uid int64, provider string) error {
+	res := dao.db.WithContext(ctx).
+		Where("uid = ? AND provider = ?", uid, provider).
+		Delete(&UserIdentity{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// UserIdentity 用户绑定的第三方账号
+type UserIdentity struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// 一个用户在同一个第三方那里只能绑定一个账号
+	Uid      int64  `gorm:"uniqueIndex:uid_provider"`
+	Provider string `gorm:"type:varchar(64);uniqueIndex:provider_subject;uniqueIndex:uid_provider"`
+	Subject  string `gorm:"type:varchar(255);uniqueIndex:provider_subject"`
+	// 第三方那里的邮箱，只是记录一下
+	Email string `gorm:"type:varchar(255)"`
+	Ctime int64
+	Utime int64
+}
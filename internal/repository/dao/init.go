@@ -14,10 +14,26 @@ import (
 func InitTables(db *gorm.DB) error {
 	// 严格来说，这个不是优秀实践
 	return db.AutoMigrate(&User{},
+		&UserIdentity{},
+		&UserTOTP{},
+		&FollowRelation{},
+		&HistoryRecord{},
 		&Article{},
 		&PublishedArticle{},
+		&ArticleRevision{},
+		&Tag{},
+		&ArticleTag{},
+		&PublishedArticleTag{},
+		&ArticleAttachment{},
+		&ArticleReview{},
+		&PublishedArticleAttachment{},
+		&Attachment{},
 		&AsyncSms{},
 		&Job{},
+		&Comment{},
+		&CommentLike{},
+		&Series{},
+		&SeriesArticle{},
 	)
 }
 
@@ -31,7 +47,12 @@ func InitCollection(mdb *mongo.Database) error {
 			Options: options.Index().SetUnique(true),
 		},
 		{
-			Keys: bson.D{bson.E{"author_id", 1}},
+			// 作者的文章列表按照 utime、id 翻页
+			Keys: bson.D{bson.E{Key: "author_id", Value: 1}, bson.E{Key: "utime", Value: -1}, bson.E{Key: "id", Value: -1}},
+		},
+		{
+			// 清理附件的时候查还有没有文章引用
+			Keys: bson.D{bson.E{Key: "attachments", Value: 1}},
 		},
 	})
 	if err != nil {
@@ -46,6 +67,28 @@ func InitCollection(mdb *mongo.Database) error {
 		{
 			Keys: bson.D{bson.E{"author_id", 1}},
 		},
+		{
+			// 按照标签分页查文章
+			Keys: bson.D{bson.E{Key: "tags", Value: 1}, bson.E{Key: "id", Value: -1}},
+		},
+		{
+			Keys: bson.D{bson.E{Key: "attachments", Value: 1}},
+		},
+		{
+			// 热榜按照 utime、id 翻页遍历已发表的文章
+			Keys: bson.D{bson.E{Key: "status", Value: 1}, bson.E{Key: "utime", Value: -1}, bson.E{Key: "id", Value: -1}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	revisionCol := mdb.Collection("article_revisions")
+	_, err = revisionCol.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			bson.E{Key: "article_id", Value: 1},
+			bson.E{Key: "version", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
 	})
 	return err
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type JobDAO interface {
@@ -13,12 +14,27 @@ type JobDAO interface {
 	UpdateUtime(ctx context.Context, id int64) error
 	UpdateNextTime(ctx context.Context, id int64, next time.Time) error
 	Stop(ctx context.Context, id int64) error
+	// Insert 按照任务名字插入，已经有了就什么都不做
+	Insert(ctx context.Context, j Job) error
 }
 
 type GORMJobDAO struct {
 	db *gorm.DB
 }
 
+func NewGORMJobDAO(db *gorm.DB) JobDAO {
+	return &GORMJobDAO{db: db}
+}
+
+func (g *GORMJobDAO) Insert(ctx context.Context, j Job) error {
+	now := time.Now().UnixMilli()
+	j.Ctime = now
+	j.Utime = now
+	return g.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoNothing: true,
+	}).Create(&j).Error
+}
+
 func (g *GORMJobDAO) Preempt(ctx context.Context) (Job, error) {
 	db := g.db.WithContext(ctx)
 	for {
@@ -34,7 +50,7 @@ func (g *GORMJobDAO) Preempt(ctx context.Context) (Job, error) {
 		// 2. 续约失败的任务：status = running AND utime < (now - 3分钟)，表示曾经有人调度但续约失败
 		ddl := now - (time.Minute * 3).Milliseconds()
 		err := db.Where("(status = ? AND next_time <?) OR (status = ? AND utime < ?)",
-			jobStatusWaiting, now, jobStatusRunning, ddl).Error
+			jobStatusWaiting, now, jobStatusRunning, ddl).First(&j).Error
 		// 你找到了，可以被抢占的
 		// 找到之后你要干嘛？你要抢占
 		if err != nil {
@@ -54,7 +70,7 @@ func (g *GORMJobDAO) Preempt(ctx context.Context) (Job, error) {
 				"version": j.Version + 1,
 			})
 		if res.Error != nil {
-			return Job{}, err
+			return Job{}, res.Error
 		}
 		if res.RowsAffected == 0 {
 			// 抢占失败，你只能说，我要继续下一轮
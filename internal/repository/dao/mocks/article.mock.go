@@ -1,16 +1,20 @@
 // Code generated by MockGen. DO NOT EDIT.
 // Source: ./article.go
-
+//
+// Generated by this command:
+//
+//	mockgen -source=./article.go -package=daomocks -destination=./mocks/article.mock.go ArticleDAO
+//
 // Package daomocks is a generated GoMock package.
 package daomocks
 
 import (
 	context "context"
-	dao "ddd_demo/internal/repository/dao"
 	reflect "reflect"
 	time "time"
 
-	gomock "github.com/golang/mock/gomock"
+	dao "ddd_demo/internal/repository/dao"
+	gomock "go.uber.org/mock/gomock"
 )
 
 // MockArticleDAO is a mock of ArticleDAO interface.
@@ -36,6 +40,49 @@ func (m *MockArticleDAO) EXPECT() *MockArticleDAOMockRecorder {
 	return m.recorder
 }
 
+// CancelSchedule mocks base method.
+func (m *MockArticleDAO) CancelSchedule(ctx context.Context, uid, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelSchedule", ctx, uid, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelSchedule indicates an expected call of CancelSchedule.
+func (mr *MockArticleDAOMockRecorder) CancelSchedule(ctx, uid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSchedule", reflect.TypeOf((*MockArticleDAO)(nil).CancelSchedule), ctx, uid, id)
+}
+
+// CountPubTags mocks base method.
+func (m *MockArticleDAO) CountPubTags(ctx context.Context, limit int) ([]dao.TagCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountPubTags", ctx, limit)
+	ret0, _ := ret[0].([]dao.TagCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountPubTags indicates an expected call of CountPubTags.
+func (mr *MockArticleDAOMockRecorder) CountPubTags(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountPubTags", reflect.TypeOf((*MockArticleDAO)(nil).CountPubTags), ctx, limit)
+}
+
+// FlushDraft mocks base method.
+func (m *MockArticleDAO) FlushDraft(ctx context.Context, entity dao.Article, base int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlushDraft", ctx, entity, base)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FlushDraft indicates an expected call of FlushDraft.
+func (mr *MockArticleDAOMockRecorder) FlushDraft(ctx, entity, base any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlushDraft", reflect.TypeOf((*MockArticleDAO)(nil).FlushDraft), ctx, entity, base)
+}
+
 // GetByAuthor mocks base method.
 func (m *MockArticleDAO) GetByAuthor(ctx context.Context, uid int64, offset, limit int) ([]dao.Article, error) {
 	m.ctrl.T.Helper()
@@ -46,11 +93,26 @@ func (m *MockArticleDAO) GetByAuthor(ctx context.Context, uid int64, offset, lim
 }
 
 // GetByAuthor indicates an expected call of GetByAuthor.
-func (mr *MockArticleDAOMockRecorder) GetByAuthor(ctx, uid, offset, limit interface{}) *gomock.Call {
+func (mr *MockArticleDAOMockRecorder) GetByAuthor(ctx, uid, offset, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAuthor", reflect.TypeOf((*MockArticleDAO)(nil).GetByAuthor), ctx, uid, offset, limit)
 }
 
+// GetByAuthorByCursor mocks base method.
+func (m *MockArticleDAO) GetByAuthorByCursor(ctx context.Context, uid, cursorUtime, cursorId int64, limit int) ([]dao.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByAuthorByCursor", ctx, uid, cursorUtime, cursorId, limit)
+	ret0, _ := ret[0].([]dao.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByAuthorByCursor indicates an expected call of GetByAuthorByCursor.
+func (mr *MockArticleDAOMockRecorder) GetByAuthorByCursor(ctx, uid, cursorUtime, cursorId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAuthorByCursor", reflect.TypeOf((*MockArticleDAO)(nil).GetByAuthorByCursor), ctx, uid, cursorUtime, cursorId, limit)
+}
+
 // GetById mocks base method.
 func (m *MockArticleDAO) GetById(ctx context.Context, id int64) (dao.Article, error) {
 	m.ctrl.T.Helper()
@@ -61,11 +123,26 @@ func (m *MockArticleDAO) GetById(ctx context.Context, id int64) (dao.Article, er
 }
 
 // GetById indicates an expected call of GetById.
-func (mr *MockArticleDAOMockRecorder) GetById(ctx, id interface{}) *gomock.Call {
+func (mr *MockArticleDAOMockRecorder) GetById(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockArticleDAO)(nil).GetById), ctx, id)
 }
 
+// GetPubByAuthor mocks base method.
+func (m *MockArticleDAO) GetPubByAuthor(ctx context.Context, uid int64, visibilities []uint8, offset, limit int) ([]dao.PublishedArticle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPubByAuthor", ctx, uid, visibilities, offset, limit)
+	ret0, _ := ret[0].([]dao.PublishedArticle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPubByAuthor indicates an expected call of GetPubByAuthor.
+func (mr *MockArticleDAOMockRecorder) GetPubByAuthor(ctx, uid, visibilities, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPubByAuthor", reflect.TypeOf((*MockArticleDAO)(nil).GetPubByAuthor), ctx, uid, visibilities, offset, limit)
+}
+
 // GetPubById mocks base method.
 func (m *MockArticleDAO) GetPubById(ctx context.Context, id int64) (dao.PublishedArticle, error) {
 	m.ctrl.T.Helper()
@@ -76,11 +153,56 @@ func (m *MockArticleDAO) GetPubById(ctx context.Context, id int64) (dao.Publishe
 }
 
 // GetPubById indicates an expected call of GetPubById.
-func (mr *MockArticleDAOMockRecorder) GetPubById(ctx, id interface{}) *gomock.Call {
+func (mr *MockArticleDAOMockRecorder) GetPubById(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPubById", reflect.TypeOf((*MockArticleDAO)(nil).GetPubById), ctx, id)
 }
 
+// GetPubByIds mocks base method.
+func (m *MockArticleDAO) GetPubByIds(ctx context.Context, ids []int64) ([]dao.PublishedArticle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPubByIds", ctx, ids)
+	ret0, _ := ret[0].([]dao.PublishedArticle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPubByIds indicates an expected call of GetPubByIds.
+func (mr *MockArticleDAOMockRecorder) GetPubByIds(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPubByIds", reflect.TypeOf((*MockArticleDAO)(nil).GetPubByIds), ctx, ids)
+}
+
+// GetPubTags mocks base method.
+func (m *MockArticleDAO) GetPubTags(ctx context.Context, ids []int64) (map[int64][]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPubTags", ctx, ids)
+	ret0, _ := ret[0].(map[int64][]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPubTags indicates an expected call of GetPubTags.
+func (mr *MockArticleDAOMockRecorder) GetPubTags(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPubTags", reflect.TypeOf((*MockArticleDAO)(nil).GetPubTags), ctx, ids)
+}
+
+// GetRevision mocks base method.
+func (m *MockArticleDAO) GetRevision(ctx context.Context, artId, version int64) (dao.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", ctx, artId, version)
+	ret0, _ := ret[0].(dao.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockArticleDAOMockRecorder) GetRevision(ctx, artId, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockArticleDAO)(nil).GetRevision), ctx, artId, version)
+}
+
 // Insert mocks base method.
 func (m *MockArticleDAO) Insert(ctx context.Context, art dao.Article) (int64, error) {
 	m.ctrl.T.Helper()
@@ -91,11 +213,26 @@ func (m *MockArticleDAO) Insert(ctx context.Context, art dao.Article) (int64, er
 }
 
 // Insert indicates an expected call of Insert.
-func (mr *MockArticleDAOMockRecorder) Insert(ctx, art interface{}) *gomock.Call {
+func (mr *MockArticleDAOMockRecorder) Insert(ctx, art any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockArticleDAO)(nil).Insert), ctx, art)
 }
 
+// ListDue mocks base method.
+func (m *MockArticleDAO) ListDue(ctx context.Context, now int64, limit int) ([]dao.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDue", ctx, now, limit)
+	ret0, _ := ret[0].([]dao.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDue indicates an expected call of ListDue.
+func (mr *MockArticleDAOMockRecorder) ListDue(ctx, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDue", reflect.TypeOf((*MockArticleDAO)(nil).ListDue), ctx, now, limit)
+}
+
 // ListPub mocks base method.
 func (m *MockArticleDAO) ListPub(ctx context.Context, start time.Time, offset, limit int) ([]dao.PublishedArticle, error) {
 	m.ctrl.T.Helper()
@@ -106,11 +243,85 @@ func (m *MockArticleDAO) ListPub(ctx context.Context, start time.Time, offset, l
 }
 
 // ListPub indicates an expected call of ListPub.
-func (mr *MockArticleDAOMockRecorder) ListPub(ctx, start, offset, limit interface{}) *gomock.Call {
+func (mr *MockArticleDAOMockRecorder) ListPub(ctx, start, offset, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPub", reflect.TypeOf((*MockArticleDAO)(nil).ListPub), ctx, start, offset, limit)
 }
 
+// ListPubByCursor mocks base method.
+func (m *MockArticleDAO) ListPubByCursor(ctx context.Context, start time.Time, cursorUtime, cursorId int64, limit int) ([]dao.PublishedArticle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPubByCursor", ctx, start, cursorUtime, cursorId, limit)
+	ret0, _ := ret[0].([]dao.PublishedArticle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPubByCursor indicates an expected call of ListPubByCursor.
+func (mr *MockArticleDAOMockRecorder) ListPubByCursor(ctx, start, cursorUtime, cursorId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPubByCursor", reflect.TypeOf((*MockArticleDAO)(nil).ListPubByCursor), ctx, start, cursorUtime, cursorId, limit)
+}
+
+// ListPubByTag mocks base method.
+func (m *MockArticleDAO) ListPubByTag(ctx context.Context, tag string, cursor int64, limit int) ([]dao.PublishedArticle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPubByTag", ctx, tag, cursor, limit)
+	ret0, _ := ret[0].([]dao.PublishedArticle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPubByTag indicates an expected call of ListPubByTag.
+func (mr *MockArticleDAOMockRecorder) ListPubByTag(ctx, tag, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPubByTag", reflect.TypeOf((*MockArticleDAO)(nil).ListPubByTag), ctx, tag, cursor, limit)
+}
+
+// ListRevisions mocks base method.
+func (m *MockArticleDAO) ListRevisions(ctx context.Context, artId int64, offset, limit int) ([]dao.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevisions", ctx, artId, offset, limit)
+	ret0, _ := ret[0].([]dao.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevisions indicates an expected call of ListRevisions.
+func (mr *MockArticleDAOMockRecorder) ListRevisions(ctx, artId, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevisions", reflect.TypeOf((*MockArticleDAO)(nil).ListRevisions), ctx, artId, offset, limit)
+}
+
+// ReferencedAttachments mocks base method.
+func (m *MockArticleDAO) ReferencedAttachments(ctx context.Context, keys []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReferencedAttachments", ctx, keys)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReferencedAttachments indicates an expected call of ReferencedAttachments.
+func (mr *MockArticleDAOMockRecorder) ReferencedAttachments(ctx, keys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReferencedAttachments", reflect.TypeOf((*MockArticleDAO)(nil).ReferencedAttachments), ctx, keys)
+}
+
+// RejectReview mocks base method.
+func (m *MockArticleDAO) RejectReview(ctx context.Context, uid, id, revision int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectReview", ctx, uid, id, revision)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RejectReview indicates an expected call of RejectReview.
+func (mr *MockArticleDAOMockRecorder) RejectReview(ctx, uid, id, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectReview", reflect.TypeOf((*MockArticleDAO)(nil).RejectReview), ctx, uid, id, revision)
+}
+
 // Sync mocks base method.
 func (m *MockArticleDAO) Sync(ctx context.Context, entity dao.Article) (int64, error) {
 	m.ctrl.T.Helper()
@@ -121,7 +332,7 @@ func (m *MockArticleDAO) Sync(ctx context.Context, entity dao.Article) (int64, e
 }
 
 // Sync indicates an expected call of Sync.
-func (mr *MockArticleDAOMockRecorder) Sync(ctx, entity interface{}) *gomock.Call {
+func (mr *MockArticleDAOMockRecorder) Sync(ctx, entity any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockArticleDAO)(nil).Sync), ctx, entity)
 }
@@ -135,11 +346,39 @@ func (m *MockArticleDAO) SyncStatus(ctx context.Context, uid, id int64, status u
 }
 
 // SyncStatus indicates an expected call of SyncStatus.
-func (mr *MockArticleDAOMockRecorder) SyncStatus(ctx, uid, id, status interface{}) *gomock.Call {
+func (mr *MockArticleDAOMockRecorder) SyncStatus(ctx, uid, id, status any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncStatus", reflect.TypeOf((*MockArticleDAO)(nil).SyncStatus), ctx, uid, id, status)
 }
 
+// SyncVisibility mocks base method.
+func (m *MockArticleDAO) SyncVisibility(ctx context.Context, uid, id int64, visibility uint8) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncVisibility", ctx, uid, id, visibility)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncVisibility indicates an expected call of SyncVisibility.
+func (mr *MockArticleDAOMockRecorder) SyncVisibility(ctx, uid, id, visibility any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncVisibility", reflect.TypeOf((*MockArticleDAO)(nil).SyncVisibility), ctx, uid, id, visibility)
+}
+
+// TransferAuthor mocks base method.
+func (m *MockArticleDAO) TransferAuthor(ctx context.Context, fromUid, toUid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferAuthor", ctx, fromUid, toUid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TransferAuthor indicates an expected call of TransferAuthor.
+func (mr *MockArticleDAOMockRecorder) TransferAuthor(ctx, fromUid, toUid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferAuthor", reflect.TypeOf((*MockArticleDAO)(nil).TransferAuthor), ctx, fromUid, toUid)
+}
+
 // UpdateById mocks base method.
 func (m *MockArticleDAO) UpdateById(ctx context.Context, entity dao.Article) error {
 	m.ctrl.T.Helper()
@@ -149,7 +388,36 @@ func (m *MockArticleDAO) UpdateById(ctx context.Context, entity dao.Article) err
 }
 
 // UpdateById indicates an expected call of UpdateById.
-func (mr *MockArticleDAOMockRecorder) UpdateById(ctx, entity interface{}) *gomock.Call {
+func (mr *MockArticleDAOMockRecorder) UpdateById(ctx, entity any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateById", reflect.TypeOf((*MockArticleDAO)(nil).UpdateById), ctx, entity)
 }
+
+// UpdateSchedule mocks base method.
+func (m *MockArticleDAO) UpdateSchedule(ctx context.Context, uid, id, publishAt int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSchedule", ctx, uid, id, publishAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSchedule indicates an expected call of UpdateSchedule.
+func (mr *MockArticleDAOMockRecorder) UpdateSchedule(ctx, uid, id, publishAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSchedule", reflect.TypeOf((*MockArticleDAO)(nil).UpdateSchedule), ctx, uid, id, publishAt)
+}
+
+// WithdrawByAuthor mocks base method.
+func (m *MockArticleDAO) WithdrawByAuthor(ctx context.Context, uid int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawByAuthor", ctx, uid)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WithdrawByAuthor indicates an expected call of WithdrawByAuthor.
+func (mr *MockArticleDAOMockRecorder) WithdrawByAuthor(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawByAuthor", reflect.TypeOf((*MockArticleDAO)(nil).WithdrawByAuthor), ctx, uid)
+}
@@ -0,0 +1,99 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./article_review.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./article_review.go -package=daomocks -destination=./mocks/article_review.mock.go ArticleReviewDAO
+//
+// Package daomocks is a generated GoMock package.
+package daomocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dao "ddd_demo/internal/repository/dao"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockArticleReviewDAO is a mock of ArticleReviewDAO interface.
+type MockArticleReviewDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockArticleReviewDAOMockRecorder
+}
+
+// MockArticleReviewDAOMockRecorder is the mock recorder for MockArticleReviewDAO.
+type MockArticleReviewDAOMockRecorder struct {
+	mock *MockArticleReviewDAO
+}
+
+// NewMockArticleReviewDAO creates a new mock instance.
+func NewMockArticleReviewDAO(ctrl *gomock.Controller) *MockArticleReviewDAO {
+	mock := &MockArticleReviewDAO{ctrl: ctrl}
+	mock.recorder = &MockArticleReviewDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArticleReviewDAO) EXPECT() *MockArticleReviewDAOMockRecorder {
+	return m.recorder
+}
+
+// FindById mocks base method.
+func (m *MockArticleReviewDAO) FindById(ctx context.Context, id int64) (dao.ArticleReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, id)
+	ret0, _ := ret[0].(dao.ArticleReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockArticleReviewDAOMockRecorder) FindById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockArticleReviewDAO)(nil).FindById), ctx, id)
+}
+
+// ListByStatus mocks base method.
+func (m *MockArticleReviewDAO) ListByStatus(ctx context.Context, status uint8, offset, limit int) ([]dao.ArticleReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByStatus", ctx, status, offset, limit)
+	ret0, _ := ret[0].([]dao.ArticleReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByStatus indicates an expected call of ListByStatus.
+func (mr *MockArticleReviewDAOMockRecorder) ListByStatus(ctx, status, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByStatus", reflect.TypeOf((*MockArticleReviewDAO)(nil).ListByStatus), ctx, status, offset, limit)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockArticleReviewDAO) UpdateStatus(ctx context.Context, id int64, status uint8, reviewer int64, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, id, status, reviewer, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockArticleReviewDAOMockRecorder) UpdateStatus(ctx, id, status, reviewer, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockArticleReviewDAO)(nil).UpdateStatus), ctx, id, status, reviewer, reason)
+}
+
+// Upsert mocks base method.
+func (m *MockArticleReviewDAO) Upsert(ctx context.Context, r dao.ArticleReview) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, r)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockArticleReviewDAOMockRecorder) Upsert(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockArticleReviewDAO)(nil).Upsert), ctx, r)
+}
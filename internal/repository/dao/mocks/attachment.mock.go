@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./attachment.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./attachment.go -package=daomocks -destination=./mocks/attachment.mock.go AttachmentDAO
+//
+// Package daomocks is a generated GoMock package.
+package daomocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dao "ddd_demo/internal/repository/dao"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAttachmentDAO is a mock of AttachmentDAO interface.
+type MockAttachmentDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachmentDAOMockRecorder
+}
+
+// MockAttachmentDAOMockRecorder is the mock recorder for MockAttachmentDAO.
+type MockAttachmentDAOMockRecorder struct {
+	mock *MockAttachmentDAO
+}
+
+// NewMockAttachmentDAO creates a new mock instance.
+func NewMockAttachmentDAO(ctrl *gomock.Controller) *MockAttachmentDAO {
+	mock := &MockAttachmentDAO{ctrl: ctrl}
+	mock.recorder = &MockAttachmentDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttachmentDAO) EXPECT() *MockAttachmentDAOMockRecorder {
+	return m.recorder
+}
+
+// DeleteStale mocks base method.
+func (m *MockAttachmentDAO) DeleteStale(ctx context.Context, id, before int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStale", ctx, id, before)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteStale indicates an expected call of DeleteStale.
+func (mr *MockAttachmentDAOMockRecorder) DeleteStale(ctx, id, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStale", reflect.TypeOf((*MockAttachmentDAO)(nil).DeleteStale), ctx, id, before)
+}
+
+// FindByKey mocks base method.
+func (m *MockAttachmentDAO) FindByKey(ctx context.Context, key string) (dao.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByKey", ctx, key)
+	ret0, _ := ret[0].(dao.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByKey indicates an expected call of FindByKey.
+func (mr *MockAttachmentDAOMockRecorder) FindByKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByKey", reflect.TypeOf((*MockAttachmentDAO)(nil).FindByKey), ctx, key)
+}
+
+// ListStale mocks base method.
+func (m *MockAttachmentDAO) ListStale(ctx context.Context, before, cursor int64, limit int) ([]dao.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStale", ctx, before, cursor, limit)
+	ret0, _ := ret[0].([]dao.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStale indicates an expected call of ListStale.
+func (mr *MockAttachmentDAOMockRecorder) ListStale(ctx, before, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStale", reflect.TypeOf((*MockAttachmentDAO)(nil).ListStale), ctx, before, cursor, limit)
+}
+
+// Upsert mocks base method.
+func (m *MockAttachmentDAO) Upsert(ctx context.Context, a dao.Attachment) (dao.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, a)
+	ret0, _ := ret[0].(dao.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockAttachmentDAOMockRecorder) Upsert(ctx, a any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockAttachmentDAO)(nil).Upsert), ctx, a)
+}
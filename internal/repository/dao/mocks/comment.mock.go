@@ -0,0 +1,173 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./comment.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./comment.go -package=daomocks -destination=./mocks/comment.mock.go CommentDAO
+//
+// Package daomocks is a generated GoMock package.
+package daomocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dao "ddd_demo/internal/repository/dao"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCommentDAO is a mock of CommentDAO interface.
+type MockCommentDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommentDAOMockRecorder
+}
+
+// MockCommentDAOMockRecorder is the mock recorder for MockCommentDAO.
+type MockCommentDAOMockRecorder struct {
+	mock *MockCommentDAO
+}
+
+// NewMockCommentDAO creates a new mock instance.
+func NewMockCommentDAO(ctrl *gomock.Controller) *MockCommentDAO {
+	mock := &MockCommentDAO{ctrl: ctrl}
+	mock.recorder = &MockCommentDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCommentDAO) EXPECT() *MockCommentDAOMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockCommentDAO) Delete(ctx context.Context, c dao.Comment) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, c)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCommentDAOMockRecorder) Delete(ctx, c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCommentDAO)(nil).Delete), ctx, c)
+}
+
+// DeleteLike mocks base method.
+func (m *MockCommentDAO) DeleteLike(ctx context.Context, uid, cid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLike", ctx, uid, cid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLike indicates an expected call of DeleteLike.
+func (mr *MockCommentDAOMockRecorder) DeleteLike(ctx, uid, cid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLike", reflect.TypeOf((*MockCommentDAO)(nil).DeleteLike), ctx, uid, cid)
+}
+
+// FindById mocks base method.
+func (m *MockCommentDAO) FindById(ctx context.Context, id int64) (dao.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, id)
+	ret0, _ := ret[0].(dao.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockCommentDAOMockRecorder) FindById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockCommentDAO)(nil).FindById), ctx, id)
+}
+
+// FindLikedIds mocks base method.
+func (m *MockCommentDAO) FindLikedIds(ctx context.Context, uid int64, cids []int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLikedIds", ctx, uid, cids)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLikedIds indicates an expected call of FindLikedIds.
+func (mr *MockCommentDAOMockRecorder) FindLikedIds(ctx, uid, cids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLikedIds", reflect.TypeOf((*MockCommentDAO)(nil).FindLikedIds), ctx, uid, cids)
+}
+
+// Insert mocks base method.
+func (m *MockCommentDAO) Insert(ctx context.Context, c dao.Comment) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, c)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockCommentDAOMockRecorder) Insert(ctx, c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockCommentDAO)(nil).Insert), ctx, c)
+}
+
+// InsertLike mocks base method.
+func (m *MockCommentDAO) InsertLike(ctx context.Context, uid, cid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertLike", ctx, uid, cid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertLike indicates an expected call of InsertLike.
+func (mr *MockCommentDAOMockRecorder) InsertLike(ctx, uid, cid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertLike", reflect.TypeOf((*MockCommentDAO)(nil).InsertLike), ctx, uid, cid)
+}
+
+// ListByLikes mocks base method.
+func (m *MockCommentDAO) ListByLikes(ctx context.Context, biz string, bizId, cursorLikeCnt, cursorId int64, limit int) ([]dao.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByLikes", ctx, biz, bizId, cursorLikeCnt, cursorId, limit)
+	ret0, _ := ret[0].([]dao.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByLikes indicates an expected call of ListByLikes.
+func (mr *MockCommentDAOMockRecorder) ListByLikes(ctx, biz, bizId, cursorLikeCnt, cursorId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByLikes", reflect.TypeOf((*MockCommentDAO)(nil).ListByLikes), ctx, biz, bizId, cursorLikeCnt, cursorId, limit)
+}
+
+// ListByTime mocks base method.
+func (m *MockCommentDAO) ListByTime(ctx context.Context, biz string, bizId, cursor int64, limit int) ([]dao.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByTime", ctx, biz, bizId, cursor, limit)
+	ret0, _ := ret[0].([]dao.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByTime indicates an expected call of ListByTime.
+func (mr *MockCommentDAOMockRecorder) ListByTime(ctx, biz, bizId, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByTime", reflect.TypeOf((*MockCommentDAO)(nil).ListByTime), ctx, biz, bizId, cursor, limit)
+}
+
+// ListReplies mocks base method.
+func (m *MockCommentDAO) ListReplies(ctx context.Context, rootId, cursor int64, limit int) ([]dao.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReplies", ctx, rootId, cursor, limit)
+	ret0, _ := ret[0].([]dao.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReplies indicates an expected call of ListReplies.
+func (mr *MockCommentDAOMockRecorder) ListReplies(ctx, rootId, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReplies", reflect.TypeOf((*MockCommentDAO)(nil).ListReplies), ctx, rootId, cursor, limit)
+}
@@ -0,0 +1,143 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./follow.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./follow.go -package=daomocks -destination=./mocks/follow.mock.go
+//
+// Package daomocks is a generated GoMock package.
+package daomocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dao "ddd_demo/internal/repository/dao"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFollowRelationDAO is a mock of FollowRelationDAO interface.
+type MockFollowRelationDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockFollowRelationDAOMockRecorder
+}
+
+// MockFollowRelationDAOMockRecorder is the mock recorder for MockFollowRelationDAO.
+type MockFollowRelationDAOMockRecorder struct {
+	mock *MockFollowRelationDAO
+}
+
+// NewMockFollowRelationDAO creates a new mock instance.
+func NewMockFollowRelationDAO(ctrl *gomock.Controller) *MockFollowRelationDAO {
+	mock := &MockFollowRelationDAO{ctrl: ctrl}
+	mock.recorder = &MockFollowRelationDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFollowRelationDAO) EXPECT() *MockFollowRelationDAOMockRecorder {
+	return m.recorder
+}
+
+// CntFollowee mocks base method.
+func (m *MockFollowRelationDAO) CntFollowee(ctx context.Context, uid int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CntFollowee", ctx, uid)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CntFollowee indicates an expected call of CntFollowee.
+func (mr *MockFollowRelationDAOMockRecorder) CntFollowee(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CntFollowee", reflect.TypeOf((*MockFollowRelationDAO)(nil).CntFollowee), ctx, uid)
+}
+
+// CntFollower mocks base method.
+func (m *MockFollowRelationDAO) CntFollower(ctx context.Context, uid int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CntFollower", ctx, uid)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CntFollower indicates an expected call of CntFollower.
+func (mr *MockFollowRelationDAOMockRecorder) CntFollower(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CntFollower", reflect.TypeOf((*MockFollowRelationDAO)(nil).CntFollower), ctx, uid)
+}
+
+// Delete mocks base method.
+func (m *MockFollowRelationDAO) Delete(ctx context.Context, follower, followee int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, follower, followee)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockFollowRelationDAOMockRecorder) Delete(ctx, follower, followee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFollowRelationDAO)(nil).Delete), ctx, follower, followee)
+}
+
+// FollowRelationDetail mocks base method.
+func (m *MockFollowRelationDAO) FollowRelationDetail(ctx context.Context, follower, followee int64) (dao.FollowRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowRelationDetail", ctx, follower, followee)
+	ret0, _ := ret[0].(dao.FollowRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FollowRelationDetail indicates an expected call of FollowRelationDetail.
+func (mr *MockFollowRelationDAOMockRecorder) FollowRelationDetail(ctx, follower, followee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowRelationDetail", reflect.TypeOf((*MockFollowRelationDAO)(nil).FollowRelationDetail), ctx, follower, followee)
+}
+
+// FolloweeList mocks base method.
+func (m *MockFollowRelationDAO) FolloweeList(ctx context.Context, follower, cursor int64, limit int) ([]dao.FollowRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FolloweeList", ctx, follower, cursor, limit)
+	ret0, _ := ret[0].([]dao.FollowRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FolloweeList indicates an expected call of FolloweeList.
+func (mr *MockFollowRelationDAOMockRecorder) FolloweeList(ctx, follower, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FolloweeList", reflect.TypeOf((*MockFollowRelationDAO)(nil).FolloweeList), ctx, follower, cursor, limit)
+}
+
+// FollowerList mocks base method.
+func (m *MockFollowRelationDAO) FollowerList(ctx context.Context, followee, cursor int64, limit int) ([]dao.FollowRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowerList", ctx, followee, cursor, limit)
+	ret0, _ := ret[0].([]dao.FollowRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FollowerList indicates an expected call of FollowerList.
+func (mr *MockFollowRelationDAOMockRecorder) FollowerList(ctx, followee, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowerList", reflect.TypeOf((*MockFollowRelationDAO)(nil).FollowerList), ctx, followee, cursor, limit)
+}
+
+// Insert mocks base method.
+func (m *MockFollowRelationDAO) Insert(ctx context.Context, r dao.FollowRelation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockFollowRelationDAOMockRecorder) Insert(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockFollowRelationDAO)(nil).Insert), ctx, r)
+}
@@ -0,0 +1,69 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./history.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./history.go -package=daomocks -destination=./mocks/history.mock.go
+//
+// Package daomocks is a generated GoMock package.
+package daomocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dao "ddd_demo/internal/repository/dao"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockHistoryRecordDAO is a mock of HistoryRecordDAO interface.
+type MockHistoryRecordDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistoryRecordDAOMockRecorder
+}
+
+// MockHistoryRecordDAOMockRecorder is the mock recorder for MockHistoryRecordDAO.
+type MockHistoryRecordDAOMockRecorder struct {
+	mock *MockHistoryRecordDAO
+}
+
+// NewMockHistoryRecordDAO creates a new mock instance.
+func NewMockHistoryRecordDAO(ctrl *gomock.Controller) *MockHistoryRecordDAO {
+	mock := &MockHistoryRecordDAO{ctrl: ctrl}
+	mock.recorder = &MockHistoryRecordDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistoryRecordDAO) EXPECT() *MockHistoryRecordDAOMockRecorder {
+	return m.recorder
+}
+
+// FindByUid mocks base method.
+func (m *MockHistoryRecordDAO) FindByUid(ctx context.Context, uid int64) ([]dao.HistoryRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUid", ctx, uid)
+	ret0, _ := ret[0].([]dao.HistoryRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUid indicates an expected call of FindByUid.
+func (mr *MockHistoryRecordDAOMockRecorder) FindByUid(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUid", reflect.TypeOf((*MockHistoryRecordDAO)(nil).FindByUid), ctx, uid)
+}
+
+// Upsert mocks base method.
+func (m *MockHistoryRecordDAO) Upsert(ctx context.Context, r dao.HistoryRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockHistoryRecordDAOMockRecorder) Upsert(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockHistoryRecordDAO)(nil).Upsert), ctx, r)
+}
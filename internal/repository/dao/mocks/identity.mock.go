@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./identity.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./identity.go -package=daomocks -destination=./mocks/identity.mock.go IdentityDAO
+//
+// Package daomocks is a generated GoMock package.
+package daomocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dao "ddd_demo/internal/repository/dao"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIdentityDAO is a mock of IdentityDAO interface.
+type MockIdentityDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityDAOMockRecorder
+}
+
+// MockIdentityDAOMockRecorder is the mock recorder for MockIdentityDAO.
+type MockIdentityDAOMockRecorder struct {
+	mock *MockIdentityDAO
+}
+
+// NewMockIdentityDAO creates a new mock instance.
+func NewMockIdentityDAO(ctrl *gomock.Controller) *MockIdentityDAO {
+	mock := &MockIdentityDAO{ctrl: ctrl}
+	mock.recorder = &MockIdentityDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentityDAO) EXPECT() *MockIdentityDAOMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockIdentityDAO) Delete(ctx context.Context, uid int64, provider string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, uid, provider)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockIdentityDAOMockRecorder) Delete(ctx, uid, provider any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIdentityDAO)(nil).Delete), ctx, uid, provider)
+}
+
+// FindBySubject mocks base method.
+func (m *MockIdentityDAO) FindBySubject(ctx context.Context, provider, subject string) (dao.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBySubject", ctx, provider, subject)
+	ret0, _ := ret[0].(dao.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBySubject indicates an expected call of FindBySubject.
+func (mr *MockIdentityDAOMockRecorder) FindBySubject(ctx, provider, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBySubject", reflect.TypeOf((*MockIdentityDAO)(nil).FindBySubject), ctx, provider, subject)
+}
+
+// FindByUid mocks base method.
+func (m *MockIdentityDAO) FindByUid(ctx context.Context, uid int64) ([]dao.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUid", ctx, uid)
+	ret0, _ := ret[0].([]dao.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUid indicates an expected call of FindByUid.
+func (mr *MockIdentityDAOMockRecorder) FindByUid(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUid", reflect.TypeOf((*MockIdentityDAO)(nil).FindByUid), ctx, uid)
+}
+
+// Insert mocks base method.
+func (m *MockIdentityDAO) Insert(ctx context.Context, identity dao.UserIdentity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockIdentityDAOMockRecorder) Insert(ctx, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockIdentityDAO)(nil).Insert), ctx, identity)
+}
+
+// InsertWithUser mocks base method.
+func (m *MockIdentityDAO) InsertWithUser(ctx context.Context, u dao.User, identity dao.UserIdentity) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWithUser", ctx, u, identity)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWithUser indicates an expected call of InsertWithUser.
+func (mr *MockIdentityDAOMockRecorder) InsertWithUser(ctx, u, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWithUser", reflect.TypeOf((*MockIdentityDAO)(nil).InsertWithUser), ctx, u, identity)
+}
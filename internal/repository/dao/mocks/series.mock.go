@@ -0,0 +1,157 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./series.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./series.go -package=daomocks -destination=./mocks/series.mock.go SeriesDAO
+//
+// Package daomocks is a generated GoMock package.
+package daomocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dao "ddd_demo/internal/repository/dao"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSeriesDAO is a mock of SeriesDAO interface.
+type MockSeriesDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockSeriesDAOMockRecorder
+}
+
+// MockSeriesDAOMockRecorder is the mock recorder for MockSeriesDAO.
+type MockSeriesDAOMockRecorder struct {
+	mock *MockSeriesDAO
+}
+
+// NewMockSeriesDAO creates a new mock instance.
+func NewMockSeriesDAO(ctrl *gomock.Controller) *MockSeriesDAO {
+	mock := &MockSeriesDAO{ctrl: ctrl}
+	mock.recorder = &MockSeriesDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSeriesDAO) EXPECT() *MockSeriesDAOMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockSeriesDAO) Delete(ctx context.Context, uid, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, uid, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSeriesDAOMockRecorder) Delete(ctx, uid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSeriesDAO)(nil).Delete), ctx, uid, id)
+}
+
+// FindArticleIds mocks base method.
+func (m *MockSeriesDAO) FindArticleIds(ctx context.Context, id int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindArticleIds", ctx, id)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindArticleIds indicates an expected call of FindArticleIds.
+func (mr *MockSeriesDAOMockRecorder) FindArticleIds(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindArticleIds", reflect.TypeOf((*MockSeriesDAO)(nil).FindArticleIds), ctx, id)
+}
+
+// FindByArticleId mocks base method.
+func (m *MockSeriesDAO) FindByArticleId(ctx context.Context, aid int64) (dao.SeriesArticle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByArticleId", ctx, aid)
+	ret0, _ := ret[0].(dao.SeriesArticle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByArticleId indicates an expected call of FindByArticleId.
+func (mr *MockSeriesDAOMockRecorder) FindByArticleId(ctx, aid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByArticleId", reflect.TypeOf((*MockSeriesDAO)(nil).FindByArticleId), ctx, aid)
+}
+
+// FindByAuthor mocks base method.
+func (m *MockSeriesDAO) FindByAuthor(ctx context.Context, uid int64) ([]dao.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByAuthor", ctx, uid)
+	ret0, _ := ret[0].([]dao.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByAuthor indicates an expected call of FindByAuthor.
+func (mr *MockSeriesDAOMockRecorder) FindByAuthor(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByAuthor", reflect.TypeOf((*MockSeriesDAO)(nil).FindByAuthor), ctx, uid)
+}
+
+// FindById mocks base method.
+func (m *MockSeriesDAO) FindById(ctx context.Context, id int64) (dao.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, id)
+	ret0, _ := ret[0].(dao.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockSeriesDAOMockRecorder) FindById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockSeriesDAO)(nil).FindById), ctx, id)
+}
+
+// Insert mocks base method.
+func (m *MockSeriesDAO) Insert(ctx context.Context, s dao.Series) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, s)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockSeriesDAOMockRecorder) Insert(ctx, s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockSeriesDAO)(nil).Insert), ctx, s)
+}
+
+// ReplaceArticles mocks base method.
+func (m *MockSeriesDAO) ReplaceArticles(ctx context.Context, uid, id int64, aids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceArticles", ctx, uid, id, aids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceArticles indicates an expected call of ReplaceArticles.
+func (mr *MockSeriesDAOMockRecorder) ReplaceArticles(ctx, uid, id, aids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceArticles", reflect.TypeOf((*MockSeriesDAO)(nil).ReplaceArticles), ctx, uid, id, aids)
+}
+
+// UpdateById mocks base method.
+func (m *MockSeriesDAO) UpdateById(ctx context.Context, s dao.Series) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateById", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateById indicates an expected call of UpdateById.
+func (mr *MockSeriesDAOMockRecorder) UpdateById(ctx, s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateById", reflect.TypeOf((*MockSeriesDAO)(nil).UpdateById), ctx, s)
+}
@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./totp.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./totp.go -package=daomocks -destination=./mocks/totp.mock.go TOTPDAO
+//
+// Package daomocks is a generated GoMock package.
+package daomocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dao "ddd_demo/internal/repository/dao"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTOTPDAO is a mock of TOTPDAO interface.
+type MockTOTPDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockTOTPDAOMockRecorder
+}
+
+// MockTOTPDAOMockRecorder is the mock recorder for MockTOTPDAO.
+type MockTOTPDAOMockRecorder struct {
+	mock *MockTOTPDAO
+}
+
+// NewMockTOTPDAO creates a new mock instance.
+func NewMockTOTPDAO(ctrl *gomock.Controller) *MockTOTPDAO {
+	mock := &MockTOTPDAO{ctrl: ctrl}
+	mock.recorder = &MockTOTPDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTOTPDAO) EXPECT() *MockTOTPDAOMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockTOTPDAO) Delete(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTOTPDAOMockRecorder) Delete(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTOTPDAO)(nil).Delete), ctx, uid)
+}
+
+// Enable mocks base method.
+func (m *MockTOTPDAO) Enable(ctx context.Context, uid, counter int64, recoveryCodes string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enable", ctx, uid, counter, recoveryCodes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enable indicates an expected call of Enable.
+func (mr *MockTOTPDAOMockRecorder) Enable(ctx, uid, counter, recoveryCodes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enable", reflect.TypeOf((*MockTOTPDAO)(nil).Enable), ctx, uid, counter, recoveryCodes)
+}
+
+// FindByUid mocks base method.
+func (m *MockTOTPDAO) FindByUid(ctx context.Context, uid int64) (dao.UserTOTP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUid", ctx, uid)
+	ret0, _ := ret[0].(dao.UserTOTP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUid indicates an expected call of FindByUid.
+func (mr *MockTOTPDAOMockRecorder) FindByUid(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUid", reflect.TypeOf((*MockTOTPDAO)(nil).FindByUid), ctx, uid)
+}
+
+// UpdateLastCounter mocks base method.
+func (m *MockTOTPDAO) UpdateLastCounter(ctx context.Context, uid, counter int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastCounter", ctx, uid, counter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLastCounter indicates an expected call of UpdateLastCounter.
+func (mr *MockTOTPDAOMockRecorder) UpdateLastCounter(ctx, uid, counter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastCounter", reflect.TypeOf((*MockTOTPDAO)(nil).UpdateLastCounter), ctx, uid, counter)
+}
+
+// UpdateRecoveryCodes mocks base method.
+func (m *MockTOTPDAO) UpdateRecoveryCodes(ctx context.Context, uid int64, old, codes string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRecoveryCodes", ctx, uid, old, codes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRecoveryCodes indicates an expected call of UpdateRecoveryCodes.
+func (mr *MockTOTPDAOMockRecorder) UpdateRecoveryCodes(ctx, uid, old, codes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRecoveryCodes", reflect.TypeOf((*MockTOTPDAO)(nil).UpdateRecoveryCodes), ctx, uid, old, codes)
+}
+
+// Upsert mocks base method.
+func (m *MockTOTPDAO) Upsert(ctx context.Context, t dao.UserTOTP) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockTOTPDAOMockRecorder) Upsert(ctx, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockTOTPDAO)(nil).Upsert), ctx, t)
+}
@@ -3,13 +3,14 @@
 //
 // Generated by this command:
 //
-//	mockgen -source=./user.go -package=daomocks -destination=./mocks/user.mock.go UserDAO
+//	mockgen -source=./user.go -package=daomocks -destination=./mocks/user.mock.go
 //
 // Package daomocks is a generated GoMock package.
 package daomocks
 
 import (
 	context "context"
+	sql "database/sql"
 	reflect "reflect"
 
 	dao "ddd_demo/internal/repository/dao"
@@ -39,6 +40,20 @@ func (m *MockUserDAO) EXPECT() *MockUserDAOMockRecorder {
 	return m.recorder
 }
 
+// Anonymize mocks base method.
+func (m *MockUserDAO) Anonymize(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Anonymize", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Anonymize indicates an expected call of Anonymize.
+func (mr *MockUserDAOMockRecorder) Anonymize(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Anonymize", reflect.TypeOf((*MockUserDAO)(nil).Anonymize), ctx, uid)
+}
+
 // FindByEmail mocks base method.
 func (m *MockUserDAO) FindByEmail(ctx context.Context, email string) (dao.User, error) {
 	m.ctrl.T.Helper()
@@ -113,6 +128,20 @@ func (mr *MockUserDAOMockRecorder) Insert(ctx, u any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockUserDAO)(nil).Insert), ctx, u)
 }
 
+// Merge mocks base method.
+func (m *MockUserDAO) Merge(ctx context.Context, fromId, toId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, fromId, toId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockUserDAOMockRecorder) Merge(ctx, fromId, toId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockUserDAO)(nil).Merge), ctx, fromId, toId)
+}
+
 // UpdateById mocks base method.
 func (m *MockUserDAO) UpdateById(ctx context.Context, entity dao.User) error {
 	m.ctrl.T.Helper()
@@ -126,3 +155,73 @@ func (mr *MockUserDAOMockRecorder) UpdateById(ctx, entity any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateById", reflect.TypeOf((*MockUserDAO)(nil).UpdateById), ctx, entity)
 }
+
+// UpdateEmail mocks base method.
+func (m *MockUserDAO) UpdateEmail(ctx context.Context, id int64, email sql.NullString) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEmail", ctx, id, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEmail indicates an expected call of UpdateEmail.
+func (mr *MockUserDAOMockRecorder) UpdateEmail(ctx, id, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEmail", reflect.TypeOf((*MockUserDAO)(nil).UpdateEmail), ctx, id, email)
+}
+
+// UpdateEmailVerified mocks base method.
+func (m *MockUserDAO) UpdateEmailVerified(ctx context.Context, id int64, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEmailVerified", ctx, id, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEmailVerified indicates an expected call of UpdateEmailVerified.
+func (mr *MockUserDAOMockRecorder) UpdateEmailVerified(ctx, id, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEmailVerified", reflect.TypeOf((*MockUserDAO)(nil).UpdateEmailVerified), ctx, id, email)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockUserDAO) UpdatePassword(ctx context.Context, id int64, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, id, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockUserDAOMockRecorder) UpdatePassword(ctx, id, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockUserDAO)(nil).UpdatePassword), ctx, id, password)
+}
+
+// UpdatePhone mocks base method.
+func (m *MockUserDAO) UpdatePhone(ctx context.Context, id int64, phone sql.NullString) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePhone", ctx, id, phone)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePhone indicates an expected call of UpdatePhone.
+func (mr *MockUserDAOMockRecorder) UpdatePhone(ctx, id, phone any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePhone", reflect.TypeOf((*MockUserDAO)(nil).UpdatePhone), ctx, id, phone)
+}
+
+// UpdateWechat mocks base method.
+func (m *MockUserDAO) UpdateWechat(ctx context.Context, id int64, openId, unionId sql.NullString) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWechat", ctx, id, openId, unionId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWechat indicates an expected call of UpdateWechat.
+func (mr *MockUserDAOMockRecorder) UpdateWechat(ctx, id, openId, unionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWechat", reflect.TypeOf((*MockUserDAO)(nil).UpdateWechat), ctx, id, openId, unionId)
+}
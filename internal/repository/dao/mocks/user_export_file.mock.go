@@ -0,0 +1,83 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./user_export_file.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./user_export_file.go -package=daomocks -destination=./mocks/user_export_file.mock.go UserExportFileDAO
+//
+// Package daomocks is a generated GoMock package.
+package daomocks
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserExportFileDAO is a mock of UserExportFileDAO interface.
+type MockUserExportFileDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserExportFileDAOMockRecorder
+}
+
+// MockUserExportFileDAOMockRecorder is the mock recorder for MockUserExportFileDAO.
+type MockUserExportFileDAOMockRecorder struct {
+	mock *MockUserExportFileDAO
+}
+
+// NewMockUserExportFileDAO creates a new mock instance.
+func NewMockUserExportFileDAO(ctrl *gomock.Controller) *MockUserExportFileDAO {
+	mock := &MockUserExportFileDAO{ctrl: ctrl}
+	mock.recorder = &MockUserExportFileDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserExportFileDAO) EXPECT() *MockUserExportFileDAOMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockUserExportFileDAO) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockUserExportFileDAOMockRecorder) Delete(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserExportFileDAO)(nil).Delete), ctx, key)
+}
+
+// Get mocks base method.
+func (m *MockUserExportFileDAO) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockUserExportFileDAOMockRecorder) Get(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUserExportFileDAO)(nil).Get), ctx, key)
+}
+
+// Put mocks base method.
+func (m *MockUserExportFileDAO) Put(ctx context.Context, key string, body io.ReadSeeker) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, key, body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockUserExportFileDAOMockRecorder) Put(ctx, key, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockUserExportFileDAO)(nil).Put), ctx, key, body)
+}
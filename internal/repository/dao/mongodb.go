@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"github.com/bwmarrin/snowflake"
+	"github.com/ecodeclub/ekit/slice"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -11,80 +12,381 @@ import (
 )
 
 type MongoDBArticleDAO struct {
-	node    *snowflake.Node
-	col     *mongo.Collection
-	liveCol *mongo.Collection
+	node        *snowflake.Node
+	col         *mongo.Collection
+	liveCol     *mongo.Collection
+	revisionCol *mongo.Collection
 }
 
 func (m *MongoDBArticleDAO) ListPub(ctx context.Context, start time.Time, offset, limit int) ([]PublishedArticle, error) {
-	//TODO implement me
-	panic("implement me")
+	filter := bson.D{
+		bson.E{Key: "status", Value: articleStatusPublished},
+		m.publicOnly(),
+		bson.E{Key: "utime", Value: bson.D{bson.E{Key: "$lt", Value: start.UnixMilli()}}},
+	}
+	cursor, err := m.liveCol.Find(ctx, filter, m.keysetOpts(limit).SetSkip(int64(offset)))
+	if err != nil {
+		return nil, err
+	}
+	var res []PublishedArticle
+	err = cursor.All(ctx, &res)
+	return res, err
 }
 
 func (m *MongoDBArticleDAO) GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]Article, error) {
-	//TODO implement me
-	panic("implement me")
+	cursor, err := m.col.Find(ctx, bson.D{bson.E{Key: "author_id", Value: uid}},
+		m.keysetOpts(limit).SetSkip(int64(offset)))
+	if err != nil {
+		return nil, err
+	}
+	var res []Article
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+func (m *MongoDBArticleDAO) GetByAuthorByCursor(ctx context.Context,
+	uid int64, cursorUtime int64, cursorId int64, limit int) ([]Article, error) {
+	filter := m.keysetByUtime(bson.D{bson.E{Key: "author_id", Value: uid}}, cursorUtime, cursorId)
+	cursor, err := m.col.Find(ctx, filter, m.keysetOpts(limit))
+	if err != nil {
+		return nil, err
+	}
+	var res []Article
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+func (m *MongoDBArticleDAO) ListPubByCursor(ctx context.Context, start time.Time,
+	cursorUtime int64, cursorId int64, limit int) ([]PublishedArticle, error) {
+	filter := m.keysetByUtime(bson.D{
+		bson.E{Key: "status", Value: articleStatusPublished},
+		m.publicOnly(),
+		bson.E{Key: "utime", Value: bson.D{bson.E{Key: "$lt", Value: start.UnixMilli()}}},
+	}, cursorUtime, cursorId)
+	cursor, err := m.liveCol.Find(ctx, filter, m.keysetOpts(limit))
+	if err != nil {
+		return nil, err
+	}
+	var res []PublishedArticle
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+// keysetByUtime 和 GORM 的实现一样，按照 utime、id 倒序翻页
+func (m *MongoDBArticleDAO) keysetByUtime(filter bson.D, cursorUtime int64, cursorId int64) bson.D {
+	if cursorId <= 0 {
+		return filter
+	}
+	return append(filter, bson.E{Key: "$or", Value: bson.A{
+		bson.D{bson.E{Key: "utime", Value: bson.D{bson.E{Key: "$lt", Value: cursorUtime}}}},
+		bson.D{
+			bson.E{Key: "utime", Value: cursorUtime},
+			bson.E{Key: "id", Value: bson.D{bson.E{Key: "$lt", Value: cursorId}}},
+		},
+	}})
+}
+
+func (m *MongoDBArticleDAO) keysetOpts(limit int) *options.FindOptions {
+	return options.Find().
+		SetSort(bson.D{bson.E{Key: "utime", Value: -1}, bson.E{Key: "id", Value: -1}}).
+		SetLimit(int64(limit))
 }
 
 func (m *MongoDBArticleDAO) GetById(ctx context.Context, id int64) (Article, error) {
-	//TODO implement me
-	panic("implement me")
+	var res Article
+	err := m.col.FindOne(ctx, bson.D{bson.E{Key: "id", Value: id}}).Decode(&res)
+	return res, m.notFound(err)
 }
 
 func (m *MongoDBArticleDAO) GetPubById(ctx context.Context, id int64) (PublishedArticle, error) {
-	//TODO implement me
-	panic("implement me")
+	var res PublishedArticle
+	err := m.liveCol.FindOne(ctx, bson.D{bson.E{Key: "id", Value: id}}).Decode(&res)
+	return res, m.notFound(err)
+}
+
+func (m *MongoDBArticleDAO) GetPubByIds(ctx context.Context, ids []int64) ([]PublishedArticle, error) {
+	res := []PublishedArticle{}
+	if len(ids) == 0 {
+		return res, nil
+	}
+	filter := bson.D{bson.E{Key: "id", Value: bson.D{bson.E{Key: "$in", Value: ids}}}}
+	opts := options.Find().SetProjection(bson.D{
+		bson.E{Key: "content", Value: 0},
+		bson.E{Key: "html", Value: 0},
+	})
+	cursor, err := m.liveCol.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+// notFound 和 GORM 的实现一样返回 ErrRecordNotFound，上面判断找不到的时候不用关心用的是哪个数据库
+func (m *MongoDBArticleDAO) notFound(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrRecordNotFound
+	}
+	return err
+}
+
+func (m *MongoDBArticleDAO) TransferAuthor(ctx context.Context, fromUid int64, toUid int64) error {
+	filter := bson.D{bson.E{Key: "author_id", Value: fromUid}}
+	sets := bson.D{bson.E{Key: "$set", Value: bson.D{
+		bson.E{Key: "author_id", Value: toUid},
+		bson.E{Key: "utime", Value: time.Now().UnixMilli()},
+	}}}
+	_, err := m.col.UpdateMany(ctx, filter, sets)
+	if err != nil {
+		return err
+	}
+	_, err = m.liveCol.UpdateMany(ctx, filter, sets)
+	return err
+}
+
+func (m *MongoDBArticleDAO) GetPubByAuthor(ctx context.Context,
+	uid int64, visibilities []uint8, offset int, limit int) ([]PublishedArticle, error) {
+	filter := bson.D{
+		bson.E{Key: "author_id", Value: uid},
+		bson.E{Key: "status", Value: articleStatusPublished},
+	}
+	if len(visibilities) > 0 {
+		vals := make(bson.A, 0, len(visibilities)+1)
+		for _, v := range visibilities {
+			vals = append(vals, v)
+			// 加这个字段之前的文章没有 visibility，都是公开的
+			if v == articleVisibilityPublic {
+				vals = append(vals, nil)
+			}
+		}
+		filter = append(filter, bson.E{Key: "visibility", Value: bson.D{bson.E{Key: "$in", Value: vals}}})
+	}
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "id", Value: 1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cursor, err := m.liveCol.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []PublishedArticle
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+func (m *MongoDBArticleDAO) WithdrawByAuthor(ctx context.Context, uid int64) ([]int64, error) {
+	cursor, err := m.liveCol.Find(ctx, bson.D{
+		bson.E{Key: "author_id", Value: uid},
+		bson.E{Key: "status", Value: articleStatusPublished},
+	}, options.Find().SetProjection(bson.D{bson.E{Key: "id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var arts []PublishedArticle
+	err = cursor.All(ctx, &arts)
+	if err != nil || len(arts) == 0 {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(arts))
+	for _, art := range arts {
+		ids = append(ids, art.Id)
+	}
+	filter := bson.D{bson.E{Key: "id", Value: bson.D{bson.E{Key: "$in", Value: ids}}}}
+	sets := bson.D{bson.E{Key: "$set", Value: bson.D{
+		bson.E{Key: "status", Value: articleStatusPrivate},
+		bson.E{Key: "utime", Value: time.Now().UnixMilli()},
+	}}}
+	_, err = m.col.UpdateMany(ctx, filter, sets)
+	if err != nil {
+		return nil, err
+	}
+	_, err = m.liveCol.UpdateMany(ctx, filter, sets)
+	return ids, err
 }
 
 func (m *MongoDBArticleDAO) Insert(ctx context.Context, art Article) (int64, error) {
+	id, _, err := m.insert(ctx, art)
+	return id, err
+}
+
+func (m *MongoDBArticleDAO) insert(ctx context.Context, art Article) (int64, int64, error) {
 	now := time.Now().UnixMilli()
-	art.Ctime = now
+	if art.Ctime == 0 {
+		art.Ctime = now
+	}
 	art.Utime = now
 	art.Id = m.node.Generate().Int64()
+	art.Revision = 1
+	art.Version = 1
+	art.Tags = m.tags(art.Tags)
+	// 渲染结果只存在线上库
+	art.Html, art.Abstract, art.Toc = "", "", nil
 	_, err := m.col.InsertOne(ctx, &art)
-	return art.Id, err
+	if err != nil {
+		return 0, 0, err
+	}
+	rev, err := m.recordRevision(ctx, art, now)
+	return art.Id, rev, err
 }
 
 func (m *MongoDBArticleDAO) UpdateById(ctx context.Context, art Article) error {
+	_, err := m.updateById(ctx, art)
+	return err
+}
+
+func (m *MongoDBArticleDAO) updateById(ctx context.Context, art Article) (int64, error) {
 	now := time.Now().UnixMilli()
 	filter := bson.D{bson.E{"id", art.Id},
 		bson.E{"author_id", art.AuthorId}}
-	set := bson.D{bson.E{"$set", bson.M{
-		"title":   art.Title,
-		"content": art.Content,
-		"status":  art.Status,
-		"utime":   now,
-	}}}
+	due, reviewed := isDue(art), isReviewed(art)
+	switch {
+	case due:
+		filter = append(filter,
+			bson.E{Key: "status", Value: articleStatusScheduled},
+			bson.E{Key: "publish_at", Value: art.PublishAt},
+			bson.E{Key: "revision", Value: art.Revision})
+	case reviewed:
+		filter = append(filter,
+			bson.E{Key: "status", Value: articleStatusReviewing},
+			bson.E{Key: "revision", Value: art.Revision})
+	default:
+		filter = append(filter, m.version(art.Version))
+	}
+	sets := bson.M{
+		"title":      art.Title,
+		"content":    art.Content,
+		"status":     art.Status,
+		"visibility": art.Visibility,
+		"publish_at": art.PublishAt,
+		"utime":      now,
+	}
+	// 定时发表到点、审核通过的时候都没有带标签和附件，用作者提交的时候保存的那些
+	if !due && !reviewed {
+		sets["tags"] = m.tags(art.Tags)
+		sets["attachments"] = art.Attachments
+		sets["version"] = art.Version + 1
+	}
+	set := bson.D{bson.E{Key: "$set", Value: sets}}
 	res, err := m.col.UpdateOne(ctx, filter, set)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	if res.ModifiedCount == 0 {
-		// 创作者不对，说明有人在瞎搞
-		return errors.New("ID 不对或者创作者不对")
+		switch {
+		case due:
+			return 0, ErrArticleScheduleChanged
+		case reviewed:
+			return 0, ErrArticleReviewChanged
+		}
+		return 0, m.versionConflict(ctx, art)
 	}
-	return nil
+	rev, err := m.recordRevision(ctx, art, now)
+	if err != nil {
+		return 0, err
+	}
+	_, err = m.col.UpdateOne(ctx, bson.D{bson.E{Key: "id", Value: art.Id}},
+		bson.D{bson.E{Key: "$set", Value: bson.D{bson.E{Key: "revision", Value: rev}}}})
+	return rev, err
+}
+
+func (m *MongoDBArticleDAO) versionConflict(ctx context.Context, art Article) error {
+	cnt, err := m.col.CountDocuments(ctx, bson.D{
+		bson.E{Key: "id", Value: art.Id},
+		bson.E{Key: "author_id", Value: art.AuthorId},
+	})
+	if err != nil {
+		return err
+	}
+	if cnt > 0 {
+		return ErrArticleVersionConflict
+	}
+	// 创作者不对，说明有人在瞎搞
+	return errors.New("ID 不对或者创作者不对")
+}
+
+// recordRevision 和 GORM 的实现一样，内容没变就复用最新的版本。
+// MongoDB 这里没有锁，并发修改同一篇文章的时候靠 (article_id, version) 的唯一索引兜底
+func (m *MongoDBArticleDAO) recordRevision(ctx context.Context, art Article, now int64) (int64, error) {
+	hash := revisionHash(art.Title, art.Content)
+	var last ArticleRevision
+	err := m.revisionCol.FindOne(ctx,
+		bson.D{bson.E{Key: "article_id", Value: art.Id}},
+		options.FindOne().
+			SetSort(bson.D{bson.E{Key: "version", Value: -1}}).
+			SetProjection(bson.D{bson.E{Key: "content", Value: 0}})).
+		Decode(&last)
+	switch {
+	case err == nil:
+		if last.Hash == hash {
+			return last.Version, nil
+		}
+	case errors.Is(err, mongo.ErrNoDocuments):
+	default:
+		return 0, err
+	}
+	rev := ArticleRevision{
+		Id:        m.node.Generate().Int64(),
+		ArticleId: art.Id,
+		Version:   last.Version + 1,
+		AuthorId:  art.AuthorId,
+		Title:     art.Title,
+		Content:   art.Content,
+		Hash:      hash,
+		Ctime:     now,
+	}
+	_, err = m.revisionCol.InsertOne(ctx, &rev)
+	return rev.Version, err
+}
+
+func (m *MongoDBArticleDAO) ListRevisions(ctx context.Context,
+	artId int64, offset int, limit int) ([]ArticleRevision, error) {
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "version", Value: -1}}).
+		SetProjection(bson.D{bson.E{Key: "content", Value: 0}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cursor, err := m.revisionCol.Find(ctx,
+		bson.D{bson.E{Key: "article_id", Value: artId}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []ArticleRevision
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+func (m *MongoDBArticleDAO) GetRevision(ctx context.Context, artId int64, version int64) (ArticleRevision, error) {
+	var res ArticleRevision
+	err := m.revisionCol.FindOne(ctx, bson.D{
+		bson.E{Key: "article_id", Value: artId},
+		bson.E{Key: "version", Value: version},
+	}).Decode(&res)
+	return res, m.notFound(err)
 }
 
 func (m *MongoDBArticleDAO) Sync(ctx context.Context, art Article) (int64, error) {
 	var (
 		id  = art.Id
+		rev int64
 		err error
 	)
 	if id > 0 {
-		err = m.UpdateById(ctx, art)
+		rev, err = m.updateById(ctx, art)
 	} else {
-		id, err = m.Insert(ctx, art)
+		id, rev, err = m.insert(ctx, art)
 	}
 	if err != nil {
 		return 0, err
 	}
 	art.Id = id
+	art.Revision = rev
 	now := time.Now().UnixMilli()
 	art.Utime = now
 	// liveCol 是 INSERT or Update 语义
 	filter := bson.D{bson.E{"id", art.Id},
 		bson.E{"author_id", art.AuthorId}}
+	// 定时发表的文章是 ListDue 查出来的，已经带着标签了
+	art.Tags = m.tags(art.Tags)
 	set := bson.D{bson.E{"$set", art},
 		bson.E{"$setOnInsert",
 			bson.D{bson.E{"ctime", now}}}}
@@ -94,28 +396,278 @@ func (m *MongoDBArticleDAO) Sync(ctx context.Context, art Article) (int64, error
 	return id, err
 }
 
+func (m *MongoDBArticleDAO) ListDue(ctx context.Context, now int64, limit int) ([]Article, error) {
+	filter := bson.D{
+		bson.E{Key: "status", Value: articleStatusScheduled},
+		bson.E{Key: "publish_at", Value: bson.D{bson.E{Key: "$lte", Value: now}}},
+	}
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "publish_at", Value: 1}}).
+		SetLimit(int64(limit))
+	cursor, err := m.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []Article
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+func (m *MongoDBArticleDAO) UpdateSchedule(ctx context.Context, uid int64, id int64, publishAt int64) error {
+	return m.updateScheduled(ctx, uid, id, bson.D{
+		bson.E{Key: "publish_at", Value: publishAt},
+		bson.E{Key: "utime", Value: time.Now().UnixMilli()},
+	})
+}
+
+func (m *MongoDBArticleDAO) CancelSchedule(ctx context.Context, uid int64, id int64) error {
+	return m.updateScheduled(ctx, uid, id, bson.D{
+		bson.E{Key: "status", Value: articleStatusUnpublished},
+		bson.E{Key: "publish_at", Value: 0},
+		bson.E{Key: "utime", Value: time.Now().UnixMilli()},
+	})
+}
+
+// updateScheduled 只更新还处于定时发表状态的文章
+func (m *MongoDBArticleDAO) updateScheduled(ctx context.Context, uid int64, id int64, sets bson.D) error {
+	filter := bson.D{
+		bson.E{Key: "id", Value: id},
+		bson.E{Key: "author_id", Value: uid},
+		bson.E{Key: "status", Value: articleStatusScheduled},
+	}
+	res, err := m.col.UpdateOne(ctx, filter, bson.D{bson.E{Key: "$set", Value: sets}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrArticleNotScheduled
+	}
+	return nil
+}
+
 func (m *MongoDBArticleDAO) SyncStatus(ctx context.Context, uid int64, id int64, status uint8) error {
 	filter := bson.D{bson.E{Key: "id", Value: id},
 		bson.E{Key: "author_id", Value: uid}}
 	sets := bson.D{bson.E{Key: "$set",
-		Value: bson.D{bson.E{Key: "status", Value: status}}}}
+		Value: bson.D{
+			bson.E{Key: "status", Value: status},
+			bson.E{Key: "utime", Value: time.Now().UnixMilli()},
+		}}}
 	res, err := m.col.UpdateOne(ctx, filter, sets)
 	if err != nil {
 		return err
 	}
-	if res.ModifiedCount != 1 {
+	if res.MatchedCount != 1 {
 		return errors.New("ID 不对或者创作者不对")
 	}
+	// 还没有发表过的文章线上库里面没有，什么也不会更新
 	_, err = m.liveCol.UpdateOne(ctx, filter, sets)
 	return err
 }
 
+func (m *MongoDBArticleDAO) SyncVisibility(ctx context.Context, uid int64, id int64, visibility uint8) error {
+	filter := bson.D{bson.E{Key: "id", Value: id},
+		bson.E{Key: "author_id", Value: uid}}
+	sets := bson.D{bson.E{Key: "$set",
+		Value: bson.D{
+			bson.E{Key: "visibility", Value: visibility},
+			bson.E{Key: "utime", Value: time.Now().UnixMilli()},
+		}}}
+	res, err := m.col.UpdateOne(ctx, filter, sets)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount != 1 {
+		return errors.New("ID 不对或者创作者不对")
+	}
+	_, err = m.liveCol.UpdateOne(ctx, filter, sets)
+	return err
+}
+
+func (m *MongoDBArticleDAO) RejectReview(ctx context.Context, uid int64, id int64, revision int64) error {
+	filter := bson.D{
+		bson.E{Key: "id", Value: id},
+		bson.E{Key: "author_id", Value: uid},
+		bson.E{Key: "status", Value: articleStatusReviewing},
+		bson.E{Key: "revision", Value: revision},
+	}
+	sets := bson.D{bson.E{Key: "$set",
+		Value: bson.D{
+			bson.E{Key: "status", Value: articleStatusRejected},
+			bson.E{Key: "utime", Value: time.Now().UnixMilli()},
+		}}}
+	res, err := m.col.UpdateOne(ctx, filter, sets)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrArticleReviewChanged
+	}
+	return nil
+}
+
+func (m *MongoDBArticleDAO) FlushDraft(ctx context.Context, art Article, base int64) error {
+	filter := bson.D{
+		bson.E{Key: "id", Value: art.Id},
+		bson.E{Key: "author_id", Value: art.AuthorId},
+		m.version(base),
+	}
+	sets := bson.D{bson.E{Key: "$set",
+		Value: bson.D{
+			bson.E{Key: "title", Value: art.Title},
+			bson.E{Key: "content", Value: art.Content},
+			bson.E{Key: "status", Value: articleStatusUnpublished},
+			bson.E{Key: "version", Value: art.Version},
+			bson.E{Key: "attachments", Value: art.Attachments},
+			bson.E{Key: "utime", Value: time.Now().UnixMilli()},
+		}}}
+	res, err := m.col.UpdateOne(ctx, filter, sets)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrArticleVersionConflict
+	}
+	return nil
+}
+
+// version 加这个字段之前的文章没有 version，相当于 0
+func (m *MongoDBArticleDAO) version(version int64) bson.E {
+	if version > 0 {
+		return bson.E{Key: "version", Value: version}
+	}
+	return bson.E{Key: "version", Value: bson.D{bson.E{Key: "$in",
+		Value: bson.A{int64(0), nil}}}}
+}
+
+// publicOnly 只要公开的文章。加这个字段之前的文章没有 visibility，也是公开的
+func (m *MongoDBArticleDAO) publicOnly() bson.E {
+	return bson.E{Key: "visibility", Value: bson.D{bson.E{Key: "$in",
+		Value: bson.A{articleVisibilityPublic, nil}}}}
+}
+
+func (m *MongoDBArticleDAO) ListPubByTag(ctx context.Context,
+	tag string, cursor int64, limit int) ([]PublishedArticle, error) {
+	filter := bson.D{
+		bson.E{Key: "tags", Value: tag},
+		bson.E{Key: "status", Value: articleStatusPublished},
+		m.publicOnly(),
+	}
+	if cursor > 0 {
+		filter = append(filter, bson.E{Key: "id", Value: bson.D{bson.E{Key: "$lt", Value: cursor}}})
+	}
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "id", Value: -1}}).
+		SetLimit(int64(limit))
+	cur, err := m.liveCol.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	res := []PublishedArticle{}
+	err = cur.All(ctx, &res)
+	return res, err
+}
+
+func (m *MongoDBArticleDAO) CountPubTags(ctx context.Context, limit int) ([]TagCount, error) {
+	pipeline := mongo.Pipeline{
+		bson.D{bson.E{Key: "$match", Value: bson.D{
+			bson.E{Key: "status", Value: articleStatusPublished},
+			m.publicOnly(),
+		}}},
+		bson.D{bson.E{Key: "$unwind", Value: "$tags"}},
+		bson.D{bson.E{Key: "$group", Value: bson.D{
+			bson.E{Key: "_id", Value: "$tags"},
+			bson.E{Key: "cnt", Value: bson.D{bson.E{Key: "$sum", Value: 1}}},
+		}}},
+		bson.D{bson.E{Key: "$sort", Value: bson.D{
+			bson.E{Key: "cnt", Value: -1},
+			bson.E{Key: "_id", Value: 1},
+		}}},
+		bson.D{bson.E{Key: "$limit", Value: limit}},
+	}
+	cur, err := m.liveCol.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		Name string `bson:"_id"`
+		Cnt  int64  `bson:"cnt"`
+	}
+	err = cur.All(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]TagCount, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, TagCount{Name: row.Name, Cnt: row.Cnt})
+	}
+	return res, nil
+}
+
+func (m *MongoDBArticleDAO) GetPubTags(ctx context.Context, ids []int64) (map[int64][]string, error) {
+	res := make(map[int64][]string, len(ids))
+	if len(ids) == 0 {
+		return res, nil
+	}
+	cur, err := m.liveCol.Find(ctx,
+		bson.D{bson.E{Key: "id", Value: bson.D{bson.E{Key: "$in", Value: ids}}}},
+		options.Find().SetProjection(bson.D{
+			bson.E{Key: "id", Value: 1},
+			bson.E{Key: "tags", Value: 1},
+		}))
+	if err != nil {
+		return nil, err
+	}
+	var arts []PublishedArticle
+	err = cur.All(ctx, &arts)
+	if err != nil {
+		return nil, err
+	}
+	for _, art := range arts {
+		if len(art.Tags) > 0 {
+			res[art.Id] = art.Tags
+		}
+	}
+	return res, nil
+}
+
+func (m *MongoDBArticleDAO) ReferencedAttachments(ctx context.Context, keys []string) ([]string, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	filter := bson.D{bson.E{Key: "attachments",
+		Value: bson.D{bson.E{Key: "$in", Value: keys}}}}
+	res := make([]string, 0, len(keys))
+	for _, col := range []*mongo.Collection{m.col, m.liveCol} {
+		vals, err := col.Distinct(ctx, "attachments", filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, val := range vals {
+			// Distinct 会把数组展开，没有命中的 key 也会带出来
+			if key, ok := val.(string); ok && slice.Contains(keys, key) {
+				res = append(res, key)
+			}
+		}
+	}
+	return slice.UnionSet(res, nil), nil
+}
+
+// tags 存一个空数组而不是 null，$unwind 和按照标签查询都不用特殊处理
+func (m *MongoDBArticleDAO) tags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
 var _ ArticleDAO = &MongoDBArticleDAO{}
 
 func NewMongoDBArticleDAO(mdb *mongo.Database, node *snowflake.Node) *MongoDBArticleDAO {
 	return &MongoDBArticleDAO{
-		node:    node,
-		liveCol: mdb.Collection("published_articles"),
-		col:     mdb.Collection("articles"),
+		node:        node,
+		liveCol:     mdb.Collection("published_articles"),
+		col:         mdb.Collection("articles"),
+		revisionCol: mdb.Collection("article_revisions"),
 	}
 }
@@ -3,25 +3,40 @@ package dao
 import (
 	"bytes"
 	"context"
+	"ddd_demo/pkg/ossx"
 	"errors"
-	"github.com/aws/aws-sdk-go/service/s3"
-	"github.com/ecodeclub/ekit"
+	"fmt"
+	lru "github.com/hashicorp/golang-lru"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
-	"strconv"
+	"io"
 	"time"
 )
 
+// contentCacheSize 本地缓存多少篇文章的内容
+const contentCacheSize = 1000
+
+// ArticleS3DAO 线上库的内容放在对象存储上，数据库里面只有元数据。
+// 名字是历史原因，store 可以是 S3，也可以是本地磁盘
 type ArticleS3DAO struct {
 	ArticleGORMDAO
-	oss *s3.S3
+	store ossx.ObjectStore
+	// contents 内容的 key 里面带着版本号，同一个 key 的内容不会变，所以缓存不需要失效
+	contents *lru.Cache
+}
+
+// pubContent 对象存储上一个版本的原文和渲染出来的 HTML
+type pubContent struct {
+	content string
+	html    string
 }
 
 func (a *ArticleS3DAO) SyncStatus(ctx context.Context, uid int64, id int64, status uint8) error {
 	now := time.Now().UnixMilli()
+	var revs []int64
 	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		res := tx.Model(&Article{}).
-			Where("id = ? and author_id = ?", uid, id).
+			Where("id = ? and author_id = ?", id, uid).
 			Updates(map[string]any{
 				"utime":  now,
 				"status": status,
@@ -32,77 +47,326 @@ func (a *ArticleS3DAO) SyncStatus(ctx context.Context, uid int64, id int64, stat
 		if res.RowsAffected != 1 {
 			return errors.New("ID 不对或者创作者不对")
 		}
-		return tx.Model(&PublishedArticleV2{}).
-			Where("id = ?", uid).
+		err := tx.Model(&PublishedArticleV2{}).
+			Where("id = ?", id).
 			Updates(map[string]any{
 				"utime":  now,
 				"status": status,
 			}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&PublishedArticleV2{}).
+			Where("id = ?", id).
+			Pluck("revision", &revs).Error
 	})
 	if err != nil {
 		return err
 	}
-	const statusPrivate = 3
-	if status == statusPrivate {
-		_, err = a.oss.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
-			Bucket: ekit.ToPtr[string]("webook-1314583317"),
-			Key:    ekit.ToPtr[string](strconv.FormatInt(id, 10)),
-		})
+	if status == articleStatusPrivate && len(revs) > 0 {
+		err = a.deleteContent(ctx, id, revs[0])
 	}
 	return err
 }
 
+// SyncVisibility 只改可见范围，内容不用动
+func (a *ArticleS3DAO) SyncVisibility(ctx context.Context, uid int64, id int64, visibility uint8) error {
+	now := time.Now().UnixMilli()
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return syncVisibility(tx, &PublishedArticleV2{}, uid, id, visibility, now)
+	})
+}
+
+// TransferAuthor 内容存在 OSS 上，key 只和文章 ID、版本号有关，所以只需要改数据库
+func (a *ArticleS3DAO) TransferAuthor(ctx context.Context, fromUid int64, toUid int64) error {
+	now := time.Now().UnixMilli()
+	updates := map[string]any{
+		"author_id": toUid,
+		"utime":     now,
+	}
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&Article{}).
+			Where("author_id = ?", fromUid).
+			Updates(updates).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&PublishedArticleV2{}).
+			Where("author_id = ?", fromUid).
+			Updates(updates).Error
+	})
+}
+
+// GetPubByAuthor 内容在 OSS 上，这里只返回元数据
+func (a *ArticleS3DAO) GetPubByAuthor(ctx context.Context,
+	uid int64, visibilities []uint8, offset int, limit int) ([]PublishedArticle, error) {
+	var arts []PublishedArticleV2
+	query := a.db.WithContext(ctx).
+		Where("author_id = ? AND status = ?", uid, articleStatusPublished)
+	err := withVisibilities(query, visibilities).
+		Order("id").
+		Offset(offset).Limit(limit).
+		Find(&arts).Error
+	if err != nil {
+		return nil, err
+	}
+	res := a.toPublished(arts)
+	return res, a.fillPubTags(ctx, res)
+}
+
+func (a *ArticleS3DAO) toPublished(arts []PublishedArticleV2) []PublishedArticle {
+	res := make([]PublishedArticle, 0, len(arts))
+	for _, art := range arts {
+		res = append(res, PublishedArticle{
+			Id:         art.Id,
+			Title:      art.Title,
+			AuthorId:   art.AuthorId,
+			Status:     art.Status,
+			Visibility: art.Visibility,
+			Ctime:      art.Ctime,
+			Utime:      art.Utime,
+			Revision:   art.Revision,
+			Abstract:   art.Abstract,
+			Toc:        art.Toc,
+		})
+	}
+	return res
+}
+
+// GetPubById 元数据在数据库里面，内容要去对象存储上拿
+func (a *ArticleS3DAO) GetPubById(ctx context.Context, id int64) (PublishedArticle, error) {
+	var art PublishedArticleV2
+	err := a.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&art).Error
+	if err != nil {
+		return PublishedArticle{}, err
+	}
+	arts := a.toPublished([]PublishedArticleV2{art})
+	err = a.fillPubTags(ctx, arts)
+	if err != nil {
+		return PublishedArticle{}, err
+	}
+	// 撤回了的文章内容已经删掉了
+	if art.Status != articleStatusPublished {
+		return arts[0], nil
+	}
+	c, err := a.getContent(ctx, art.Id, art.Revision)
+	if err != nil {
+		return PublishedArticle{}, err
+	}
+	arts[0].Content, arts[0].Html = c.content, c.html
+	return arts[0], nil
+}
+
+// GetPubByIds 本来就只要元数据，不用去对象存储上拿内容
+func (a *ArticleS3DAO) GetPubByIds(ctx context.Context, ids []int64) ([]PublishedArticle, error) {
+	if len(ids) == 0 {
+		return []PublishedArticle{}, nil
+	}
+	var arts []PublishedArticleV2
+	err := a.db.WithContext(ctx).
+		Where("id IN ?", ids).
+		Find(&arts).Error
+	if err != nil {
+		return nil, err
+	}
+	return a.toPublished(arts), nil
+}
+
+// ListPubByCursor 和 GetPubByAuthor 一样只返回元数据
+func (a *ArticleS3DAO) ListPubByCursor(ctx context.Context, start time.Time,
+	cursorUtime int64, cursorId int64, limit int) ([]PublishedArticle, error) {
+	var arts []PublishedArticleV2
+	query := a.db.WithContext(ctx).
+		Where("utime < ? AND status = ? AND visibility = ?",
+			start.UnixMilli(), articleStatusPublished, articleVisibilityPublic)
+	err := keysetByUtime(query, cursorUtime, cursorId).
+		Limit(limit).
+		Find(&arts).Error
+	if err != nil {
+		return nil, err
+	}
+	return a.toPublished(arts), nil
+}
+
+// ListPubByTag 和 GetPubByAuthor 一样只返回元数据
+func (a *ArticleS3DAO) ListPubByTag(ctx context.Context,
+	tag string, cursor int64, limit int) ([]PublishedArticle, error) {
+	return a.listPubByTag(ctx, "published_article_v2", tag, cursor, limit)
+}
+
+func (a *ArticleS3DAO) CountPubTags(ctx context.Context, limit int) ([]TagCount, error) {
+	return a.countPubTags(ctx, "published_article_v2", limit)
+}
+
+func (a *ArticleS3DAO) WithdrawByAuthor(ctx context.Context, uid int64) ([]int64, error) {
+	var ids []int64
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return a.withdrawByAuthor(tx, &PublishedArticleV2{}, uid, &ids)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// 和 SyncStatus 一样，仅自己可见的文章要从 OSS 上删掉
+	var arts []PublishedArticleV2
+	err = a.db.WithContext(ctx).Select("id", "revision").
+		Where("id IN ?", ids).Find(&arts).Error
+	if err != nil {
+		return ids, err
+	}
+	for _, art := range arts {
+		err = a.deleteContent(ctx, art.Id, art.Revision)
+		if err != nil {
+			return ids, err
+		}
+	}
+	return ids, nil
+}
+
+// Sync 内容在事务里面上传，新版本的 key 在提交之前没有人用，
+// 这样读的时候只要数据库里面有这个版本，对象存储上就一定有内容
 func (a *ArticleS3DAO) Sync(ctx context.Context, art Article) (int64, error) {
-	var id = art.Id
+	var (
+		id          = art.Id
+		rev, oldRev int64
+	)
 	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		var (
-			err error
-		)
-		dao := NewArticleGORMDAO(tx)
+		var err error
 		if id > 0 {
-			err = dao.UpdateById(ctx, art)
+			rev, err = a.updateById(tx, art)
 		} else {
-			id, err = dao.Insert(ctx, art)
+			id, rev, err = a.insert(tx, art)
 		}
 		if err != nil {
 			return err
 		}
 		art.Id = id
+		var revs []int64
+		err = tx.Model(&PublishedArticleV2{}).
+			Where("id = ?", id).
+			Pluck("revision", &revs).Error
+		if err != nil {
+			return err
+		}
+		if len(revs) > 0 {
+			oldRev = revs[0]
+		}
+		err = a.putContent(ctx, id, rev, art)
+		if err != nil {
+			return err
+		}
 		now := time.Now().UnixMilli()
 		pubArt := PublishedArticleV2{
-			Id:       art.Id,
-			Title:    art.Title,
-			AuthorId: art.AuthorId,
-			Ctime:    now,
-			Utime:    now,
-			Status:   art.Status,
+			Id:         art.Id,
+			Title:      art.Title,
+			AuthorId:   art.AuthorId,
+			Ctime:      now,
+			Utime:      now,
+			Status:     art.Status,
+			Visibility: art.Visibility,
+			Revision:   rev,
+			Abstract:   art.Abstract,
+			Toc:        art.Toc,
 		}
-		pubArt.Ctime = now
-		pubArt.Utime = now
 		err = tx.Clauses(clause.OnConflict{
 			Columns: []clause.Column{{Name: "id"}},
-			DoUpdates: clause.Assignments(map[string]interface{}{
-				"title":  pubArt.Title,
-				"utime":  now,
-				"status": pubArt.Status,
-			}),
+			DoUpdates: append(clause.Assignments(map[string]interface{}{
+				"title":      pubArt.Title,
+				"utime":      now,
+				"status":     pubArt.Status,
+				"visibility": pubArt.Visibility,
+				"revision":   pubArt.Revision,
+			}), clause.AssignmentColumns([]string{"abstract", "toc"})...),
 		}).Create(&pubArt).Error
-		return err
+		if err != nil {
+			return err
+		}
+		err = a.syncPubTags(tx, id)
+		if err != nil {
+			return err
+		}
+		return a.syncPubAttachments(tx, id)
 	})
 	if err != nil {
 		return 0, err
 	}
-	_, err = a.oss.PutObjectWithContext(ctx, &s3.PutObjectInput{
-		Bucket:      ekit.ToPtr[string]("webook-1314583317"),
-		Key:         ekit.ToPtr[string](strconv.FormatInt(art.Id, 10)),
-		Body:        bytes.NewReader([]byte(art.Content)),
-		ContentType: ekit.ToPtr[string]("text/plain;charset=utf-8"),
-	})
-	return id, err
+	if oldRev > 0 && oldRev != rev {
+		// 老版本已经没有人读了，删不掉也只是多占一点空间
+		_ = a.deleteContent(ctx, id, oldRev)
+	}
+	return id, nil
+}
+
+// putContent 原文和 HTML 分成两个对象存
+func (a *ArticleS3DAO) putContent(ctx context.Context, id int64, rev int64, art Article) error {
+	key := a.contentKey(id, rev)
+	err := a.store.Put(ctx, key,
+		bytes.NewReader([]byte(art.Content)), "text/plain;charset=utf-8")
+	if err != nil {
+		return err
+	}
+	return a.store.Put(ctx, a.htmlKey(key),
+		bytes.NewReader([]byte(art.Html)), "text/html;charset=utf-8")
+}
+
+func (a *ArticleS3DAO) getContent(ctx context.Context, id int64, rev int64) (pubContent, error) {
+	key := a.contentKey(id, rev)
+	if val, ok := a.contents.Get(key); ok {
+		return val.(pubContent), nil
+	}
+	content, err := a.getObject(ctx, key)
+	if err != nil {
+		return pubContent{}, err
+	}
+	html, err := a.getObject(ctx, a.htmlKey(key))
+	// 渲染之前发表的文章没有 HTML
+	if err != nil && !errors.Is(err, ossx.ErrObjectNotFound) {
+		return pubContent{}, err
+	}
+	res := pubContent{content: content, html: html}
+	a.contents.Add(key, res)
+	return res, nil
+}
+
+func (a *ArticleS3DAO) getObject(ctx context.Context, key string) (string, error) {
+	rc, err := a.store.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	return string(data), err
 }
 
-func NewArticleS3DAO(db *gorm.DB, oss *s3.S3) *ArticleS3DAO {
-	return &ArticleS3DAO{ArticleGORMDAO: ArticleGORMDAO{db: db}, oss: oss}
+func (a *ArticleS3DAO) deleteContent(ctx context.Context, id int64, rev int64) error {
+	key := a.contentKey(id, rev)
+	// 别的节点上的缓存删不掉，不过 GetPubById 会先看状态，撤回了的文章不会去读内容
+	a.contents.Remove(key)
+	err := a.store.Delete(ctx, key)
+	if err != nil {
+		return err
+	}
+	return a.store.Delete(ctx, a.htmlKey(key))
+}
+
+// contentKey 带上版本号，同一个 key 的内容不会变
+func (a *ArticleS3DAO) contentKey(id int64, rev int64) string {
+	return fmt.Sprintf("%d/%d", id, rev)
+}
+
+func (a *ArticleS3DAO) htmlKey(contentKey string) string {
+	return contentKey + ".html"
+}
+
+func NewArticleS3DAO(db *gorm.DB, store ossx.ObjectStore) *ArticleS3DAO {
+	// 只有 size 不是正数的时候才会出错
+	contents, _ := lru.New(contentCacheSize)
+	return &ArticleS3DAO{
+		ArticleGORMDAO: ArticleGORMDAO{db: db},
+		store:          store,
+		contents:       contents,
+	}
 }
 
 type PublishedArticleV2 struct {
@@ -110,8 +374,14 @@ type PublishedArticleV2 struct {
 	Title string `gorm:"type=varchar(4096)" bson:"title,omitempty"`
 	// 我要根据创作者ID来查询
 	AuthorId int64 `gorm:"index" bson:"author_id,omitempty"`
-	Status   uint8 `bson:"status,omitempty"`
-	Ctime    int64 `bson:"ctime,omitempty"`
+	Status   uint8 `gorm:"index:status_utime,priority:1" bson:"status,omitempty"`
+	// Visibility 可见范围，和 Article 一样
+	Visibility uint8 `gorm:"not null;default:0" bson:"visibility"`
+	Ctime      int64 `bson:"ctime,omitempty"`
 	// 更新时间
-	Utime int64 `bson:"utime,omitempty"`
+	Utime    int64 `gorm:"index:status_utime,priority:2" bson:"utime,omitempty"`
+	Revision int64 `bson:"revision,omitempty"`
+	// Abstract、Toc 是渲染出来的，HTML 和内容一样放在对象存储上
+	Abstract string           `gorm:"type:varchar(1024)" bson:"abstract,omitempty"`
+	Toc      []ArticleHeading `gorm:"type:text;serializer:json" bson:"toc"`
 }
@@ -3,12 +3,15 @@ package dao
 import (
 	"bytes"
 	"context"
+	"ddd_demo/pkg/ossx"
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/ecodeclub/ekit"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"io"
 	"os"
 	"testing"
@@ -53,3 +56,73 @@ func TestS3(t *testing.T) {
 	assert.NoError(t, err)
 	t.Log(string(data))
 }
+
+// TestArticleS3DAO_GetPubById 内容从对象存储上拿，第二次直接走本地缓存
+func TestArticleS3DAO_GetPubById(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db := newMockGORM(t, sqlDB)
+	store := ossx.NewLocalStore(t.TempDir())
+	ctx := context.Background()
+	err = store.Put(ctx, "1/2", bytes.NewReader([]byte("内容")), "text/plain;charset=utf-8")
+	require.NoError(t, err)
+	err = store.Put(ctx, "1/2.html", bytes.NewReader([]byte("<p>内容</p>")), "text/html;charset=utf-8")
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		mock.ExpectQuery("SELECT \\* FROM `published_article_v2` WHERE id = \\?").
+			WithArgs(int64(1), 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "author_id", "status", "revision", "abstract", "toc"}).
+				AddRow(1, "标题", 123, articleStatusPublished, 2, "内容", `[{"level":2,"id":"a","title":"A"}]`))
+		mock.ExpectQuery("SELECT published_article_tags.article_id AS article_id, tags.name AS name").
+			WillReturnRows(sqlmock.NewRows([]string{"article_id", "name"}))
+	}
+
+	dao := NewArticleS3DAO(db, store)
+	want := PublishedArticle{Id: 1, Title: "标题", AuthorId: 123,
+		Status: articleStatusPublished, Revision: 2, Content: "内容",
+		Html: "<p>内容</p>", Abstract: "内容", Toc: []ArticleHeading{{Level: 2, Id: "a", Title: "A"}}}
+	art, err := dao.GetPubById(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, want, art)
+	// 对象存储上没有了也能从缓存里面拿到
+	require.NoError(t, store.Delete(ctx, "1/2"))
+	require.NoError(t, store.Delete(ctx, "1/2.html"))
+	art, err = dao.GetPubById(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, want, art)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestArticleS3DAO_SyncStatus 设置成仅自己可见，内容要从对象存储上删掉
+func TestArticleS3DAO_SyncStatus(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db := newMockGORM(t, sqlDB)
+	store := ossx.NewLocalStore(t.TempDir())
+	ctx := context.Background()
+	err = store.Put(ctx, "1/2", bytes.NewReader([]byte("内容")), "text/plain;charset=utf-8")
+	require.NoError(t, err)
+	err = store.Put(ctx, "1/2.html", bytes.NewReader([]byte("<p>内容</p>")), "text/html;charset=utf-8")
+	require.NoError(t, err)
+
+	const statusPrivate = 3
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `articles` SET").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE `published_article_v2` SET").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT `revision` FROM `published_article_v2` WHERE id = \\?").
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(2))
+	mock.ExpectCommit()
+
+	dao := NewArticleS3DAO(db, store)
+	err = dao.SyncStatus(ctx, 123, 1, statusPrivate)
+	require.NoError(t, err)
+	_, err = store.Get(ctx, "1/2")
+	assert.Equal(t, ossx.ErrObjectNotFound, err)
+	_, err = store.Get(ctx, "1/2.html")
+	assert.Equal(t, ossx.ErrObjectNotFound, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
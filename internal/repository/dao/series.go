@@ -0,0 +1,182 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrSeriesArticleConflict 一篇文章只能放在一个专栏里面
+var ErrSeriesArticleConflict = errors.New("文章已经在别的专栏里面了")
+
+//go:generate mockgen -source=./series.go -package=daomocks -destination=./mocks/series.mock.go SeriesDAO
+type SeriesDAO interface {
+	Insert(ctx context.Context, s Series) (int64, error)
+	// UpdateById 只改标题和简介，ID 或者创作者不对返回 ErrRecordNotFound
+	UpdateById(ctx context.Context, s Series) error
+	// Delete 连着目录一起删掉，已经删掉了也返回 nil
+	Delete(ctx context.Context, uid int64, id int64) error
+	FindById(ctx context.Context, id int64) (Series, error)
+	// FindByAuthor 作者所有的专栏，新建的在前面
+	FindByAuthor(ctx context.Context, uid int64) ([]Series, error)
+	// ReplaceArticles 整个替换专栏的目录，aids 的顺序就是目录的顺序。
+	// 有文章已经在别的专栏里面了返回 ErrSeriesArticleConflict
+	ReplaceArticles(ctx context.Context, uid int64, id int64, aids []int64) error
+	// FindArticleIds 专栏里面的文章，按照目录顺序
+	FindArticleIds(ctx context.Context, id int64) ([]int64, error)
+	// FindByArticleId 文章在哪个专栏里面，不在任何专栏里面返回 ErrRecordNotFound
+	FindByArticleId(ctx context.Context, aid int64) (SeriesArticle, error)
+}
+
+type GORMSeriesDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMSeriesDAO(db *gorm.DB) SeriesDAO {
+	return &GORMSeriesDAO{db: db}
+}
+
+func (dao *GORMSeriesDAO) Insert(ctx context.Context, s Series) (int64, error) {
+	now := time.Now().UnixMilli()
+	s.Ctime = now
+	s.Utime = now
+	err := dao.db.WithContext(ctx).Create(&s).Error
+	return s.Id, err
+}
+
+func (dao *GORMSeriesDAO) UpdateById(ctx context.Context, s Series) error {
+	res := dao.db.WithContext(ctx).Model(&Series{}).
+		Where("id = ? AND author_id = ?", s.Id, s.AuthorId).
+		Updates(map[string]any{
+			"title":       s.Title,
+			"description": s.Description,
+			"utime":       time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (dao *GORMSeriesDAO) Delete(ctx context.Context, uid int64, id int64) error {
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("id = ? AND author_id = ?", id, uid).Delete(&Series{})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		// 文章本身不动，只是不再属于这个专栏
+		return tx.Where("series_id = ?", id).Delete(&SeriesArticle{}).Error
+	})
+}
+
+func (dao *GORMSeriesDAO) FindById(ctx context.Context, id int64) (Series, error) {
+	var res Series
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&res).Error
+	return res, err
+}
+
+func (dao *GORMSeriesDAO) FindByAuthor(ctx context.Context, uid int64) ([]Series, error) {
+	var res []Series
+	err := dao.db.WithContext(ctx).
+		Where("author_id = ?", uid).
+		Order("id DESC").
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMSeriesDAO) ReplaceArticles(ctx context.Context, uid int64, id int64, aids []int64) error {
+	now := time.Now().UnixMilli()
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 锁住专栏，同一个专栏的目录并发修改的时候一个一个来，后提交的覆盖先提交的
+		var s Series
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND author_id = ?", id, uid).
+			First(&s).Error
+		if err != nil {
+			return err
+		}
+		if len(aids) > 0 {
+			var cnt int64
+			err = tx.Model(&SeriesArticle{}).
+				Where("article_id IN ? AND series_id <> ?", aids, id).
+				Count(&cnt).Error
+			if err != nil {
+				return err
+			}
+			if cnt > 0 {
+				return ErrSeriesArticleConflict
+			}
+		}
+		err = tx.Where("series_id = ?", id).Delete(&SeriesArticle{}).Error
+		if err != nil {
+			return err
+		}
+		if len(aids) > 0 {
+			sas := make([]SeriesArticle, 0, len(aids))
+			for i, aid := range aids {
+				sas = append(sas, SeriesArticle{
+					SeriesId:  id,
+					ArticleId: aid,
+					Position:  i + 1,
+					Ctime:     now,
+				})
+			}
+			err = tx.Create(&sas).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Model(&Series{}).Where("id = ?", id).Update("utime", now).Error
+	})
+	// 同时把同一篇文章加到两个专栏里面，上面的检查拦不住，靠唯一索引
+	if me, ok := err.(*mysql.MySQLError); ok {
+		const duplicateErr uint16 = 1062
+		if me.Number == duplicateErr {
+			return ErrSeriesArticleConflict
+		}
+	}
+	return err
+}
+
+func (dao *GORMSeriesDAO) FindArticleIds(ctx context.Context, id int64) ([]int64, error) {
+	var res []int64
+	err := dao.db.WithContext(ctx).Model(&SeriesArticle{}).
+		Where("series_id = ?", id).
+		Order("position").
+		Pluck("article_id", &res).Error
+	return res, err
+}
+
+func (dao *GORMSeriesDAO) FindByArticleId(ctx context.Context, aid int64) (SeriesArticle, error) {
+	var res SeriesArticle
+	err := dao.db.WithContext(ctx).Where("article_id = ?", aid).First(&res).Error
+	return res, err
+}
+
+// Series 专栏
+type Series struct {
+	Id          int64  `gorm:"primaryKey,autoIncrement"`
+	AuthorId    int64  `gorm:"index"`
+	Title       string `gorm:"type:varchar(1024)"`
+	Description string `gorm:"type:text"`
+	Ctime       int64
+	Utime       int64
+}
+
+// SeriesArticle 专栏的目录，修改的时候整个替换掉
+type SeriesArticle struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// 按照 (series_id, position) 排序就是目录
+	SeriesId int64 `gorm:"index:series_position,priority:1"`
+	Position int   `gorm:"index:series_position,priority:2"`
+	// ArticleId 一篇文章最多在一个专栏里面，不然不知道上一篇、下一篇是哪个专栏的
+	ArticleId int64 `gorm:"uniqueIndex"`
+	Ctime     int64
+}
@@ -0,0 +1,98 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGORMSeriesDAO_ReplaceArticles 整个替换目录，position 从 1 开始；
+// 文章在别的专栏里面的时候不能替换，并发加到两个专栏里面的靠唯一索引拦住
+func TestGORMSeriesDAO_ReplaceArticles(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db := newMockGORM(t, sqlDB)
+	lockSeries := func() {
+		mock.ExpectQuery("SELECT \\* FROM `series` WHERE id = \\? AND author_id = \\? "+
+			"ORDER BY `series`.`id` LIMIT \\? FOR UPDATE").
+			WithArgs(int64(10), int64(123), 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "author_id"}).AddRow(10, 123))
+	}
+	countOthers := func(cnt int64) {
+		mock.ExpectQuery("SELECT count\\(\\*\\) FROM `series_articles` "+
+			"WHERE article_id IN \\(\\?,\\?\\) AND series_id <> \\?").
+			WithArgs(int64(3), int64(1), int64(10)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(cnt))
+	}
+
+	mock.ExpectBegin()
+	lockSeries()
+	countOthers(0)
+	mock.ExpectExec("DELETE FROM `series_articles` WHERE series_id = \\?").
+		WithArgs(int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO `series_articles` \\(`series_id`,`position`,`article_id`,`ctime`\\) "+
+		"VALUES \\(\\?,\\?,\\?,\\?\\),\\(\\?,\\?,\\?,\\?\\)").
+		WithArgs(int64(10), 1, int64(3), sqlmock.AnyArg(), int64(10), 2, int64(1), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectExec("UPDATE `series` SET `utime`=\\? WHERE id = \\?").
+		WithArgs(sqlmock.AnyArg(), int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// 有文章在别的专栏里面
+	mock.ExpectBegin()
+	lockSeries()
+	countOthers(1)
+	mock.ExpectRollback()
+
+	// 检查完之后别人刚好把文章加到了另外一个专栏里面
+	mock.ExpectBegin()
+	lockSeries()
+	countOthers(0)
+	mock.ExpectExec("DELETE FROM `series_articles` WHERE series_id = \\?").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO `series_articles`").
+		WillReturnError(&mysql.MySQLError{Number: 1062})
+	mock.ExpectRollback()
+
+	dao := NewGORMSeriesDAO(db)
+	err = dao.ReplaceArticles(context.Background(), 123, 10, []int64{3, 1})
+	require.NoError(t, err)
+	err = dao.ReplaceArticles(context.Background(), 123, 10, []int64{3, 1})
+	assert.Equal(t, ErrSeriesArticleConflict, err)
+	err = dao.ReplaceArticles(context.Background(), 123, 10, []int64{3, 1})
+	assert.Equal(t, ErrSeriesArticleConflict, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGORMSeriesDAO_Delete 连着目录一起删，不是自己的或者已经删掉了就什么也不做
+func TestGORMSeriesDAO_Delete(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db := newMockGORM(t, sqlDB)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `series` WHERE id = \\? AND author_id = \\?").
+		WithArgs(int64(10), int64(123)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `series_articles` WHERE series_id = \\?").
+		WithArgs(int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `series` WHERE id = \\? AND author_id = \\?").
+		WithArgs(int64(10), int64(456)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	dao := NewGORMSeriesDAO(db)
+	require.NoError(t, dao.Delete(context.Background(), 123, 10))
+	require.NoError(t, dao.Delete(context.Background(), 456, 10))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
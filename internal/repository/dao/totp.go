@@ -0,0 +1,106 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+//go:generate mockgen -source=./totp.go -package=daomocks -destination=./mocks/totp.mock.go TOTPDAO
+type TOTPDAO interface {
+	// Upsert 重新绑定，会覆盖掉原本没有启用的密钥
+	Upsert(ctx context.Context, t UserTOTP) error
+	FindByUid(ctx context.Context, uid int64) (UserTOTP, error)
+	// Enable 启用，同时保存恢复码
+	Enable(ctx context.Context, uid int64, counter int64, recoveryCodes string) error
+	// UpdateLastCounter 只有 counter 比原来的大才会更新，没有更新返回 ErrRecordNotFound
+	UpdateLastCounter(ctx context.Context, uid int64, counter int64) error
+	// UpdateRecoveryCodes 用掉一个恢复码。old 是更新前的值，被别人并发修改了返回 ErrRecordNotFound
+	UpdateRecoveryCodes(ctx context.Context, uid int64, old string, codes string) error
+	Delete(ctx context.Context, uid int64) error
+}
+
+type GORMTOTPDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMTOTPDAO(db *gorm.DB) TOTPDAO {
+	return &GORMTOTPDAO{db: db}
+}
+
+func (dao *GORMTOTPDAO) Upsert(ctx context.Context, t UserTOTP) error {
+	now := time.Now().UnixMilli()
+	t.Ctime = now
+	t.Utime = now
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"secret":         t.Secret,
+			"enabled":        false,
+			"last_counter":   0,
+			"recovery_codes": "",
+			"utime":          now,
+		}),
+	}).Create(&t).Error
+}
+
+func (dao *GORMTOTPDAO) FindByUid(ctx context.Context, uid int64) (UserTOTP, error) {
+	var res UserTOTP
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).First(&res).Error
+	return res, err
+}
+
+func (dao *GORMTOTPDAO) Enable(ctx context.Context, uid int64, counter int64, recoveryCodes string) error {
+	return dao.update(dao.db.WithContext(ctx).
+		Where("uid = ? AND enabled = ? AND last_counter < ?", uid, false, counter),
+		map[string]any{
+			"enabled":        true,
+			"last_counter":   counter,
+			"recovery_codes": recoveryCodes,
+		})
+}
+
+func (dao *GORMTOTPDAO) UpdateLastCounter(ctx context.Context, uid int64, counter int64) error {
+	return dao.update(dao.db.WithContext(ctx).
+		Where("uid = ? AND last_counter < ?", uid, counter),
+		map[string]any{
+			"last_counter": counter,
+		})
+}
+
+func (dao *GORMTOTPDAO) UpdateRecoveryCodes(ctx context.Context, uid int64, old string, codes string) error {
+	return dao.update(dao.db.WithContext(ctx).
+		Where("uid = ? AND recovery_codes = ?", uid, old),
+		map[string]any{
+			"recovery_codes": codes,
+		})
+}
+
+func (dao *GORMTOTPDAO) Delete(ctx context.Context, uid int64) error {
+	return dao.db.WithContext(ctx).Where("uid = ?", uid).Delete(&UserTOTP{}).Error
+}
+
+func (dao *GORMTOTPDAO) update(db *gorm.DB, updates map[string]any) error {
+	updates["utime"] = time.Now().UnixMilli()
+	res := db.Model(&UserTOTP{}).Updates(updates)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+type UserTOTP struct {
+	Id  int64 `gorm:"primaryKey,autoIncrement"`
+	Uid int64 `gorm:"uniqueIndex"`
+	// base32 编码的密钥
+	Secret      string `gorm:"type:varchar(64)"`
+	Enabled     bool
+	LastCounter int64
+	// JSON 数组，里面是恢复码的 bcrypt hash
+	RecoveryCodes string `gorm:"type:text"`
+	Ctime         int64
+	Utime         int64
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
@@ -22,6 +23,15 @@ type UserDAO interface {
 	FindById(ctx context.Context, uid int64) (User, error)
 	FindByPhone(ctx context.Context, phone string) (User, error)
 	FindByWechat(ctx context.Context, openId string) (User, error)
+	UpdatePassword(ctx context.Context, id int64, password string) error
+	UpdateEmailVerified(ctx context.Context, id int64, email string) error
+	UpdatePhone(ctx context.Context, id int64, phone sql.NullString) error
+	UpdateEmail(ctx context.Context, id int64, email sql.NullString) error
+	UpdateWechat(ctx context.Context, id int64, openId sql.NullString, unionId sql.NullString) error
+	// Merge 把 fromId 的登录身份合并到 toId 上，并且删除 fromId
+	Merge(ctx context.Context, fromId int64, toId int64) error
+	// Anonymize 注销账号，抹掉个人信息和所有的登录方式，只保留一个空壳
+	Anonymize(ctx context.Context, uid int64) error
 }
 
 type GORMUserDAO struct {
@@ -75,6 +85,162 @@ func (dao *GORMUserDAO) UpdateById(ctx context.Context, entity User) error {
 		}).Error
 }
 
+func (dao *GORMUserDAO) UpdatePassword(ctx context.Context, id int64, password string) error {
+	return dao.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).
+		Updates(map[string]any{
+			"utime":    time.Now().UnixMilli(),
+			"password": password,
+		}).Error
+}
+
+// UpdateEmailVerified 标记邮箱已经验证过了
+// 带上 email 作为条件，防止验证邮件发出去之后邮箱被改掉了
+func (dao *GORMUserDAO) UpdateEmailVerified(ctx context.Context, id int64, email string) error {
+	res := dao.db.WithContext(ctx).Model(&User{}).
+		Where("id = ? AND email = ?", id, email).
+		Updates(map[string]any{
+			"utime":          time.Now().UnixMilli(),
+			"email_verified": true,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (dao *GORMUserDAO) UpdatePhone(ctx context.Context, id int64, phone sql.NullString) error {
+	return dao.updateIdentity(ctx, id, map[string]any{
+		"phone": phone,
+	})
+}
+
+// UpdateEmail 换了邮箱，就要重新验证
+func (dao *GORMUserDAO) UpdateEmail(ctx context.Context, id int64, email sql.NullString) error {
+	return dao.updateIdentity(ctx, id, map[string]any{
+		"email":          email,
+		"email_verified": false,
+	})
+}
+
+func (dao *GORMUserDAO) UpdateWechat(ctx context.Context, id int64,
+	openId sql.NullString, unionId sql.NullString) error {
+	return dao.updateIdentity(ctx, id, map[string]any{
+		"wechat_open_id":  openId,
+		"wechat_union_id": unionId,
+	})
+}
+
+func (dao *GORMUserDAO) updateIdentity(ctx context.Context, id int64, updates map[string]any) error {
+	updates["utime"] = time.Now().UnixMilli()
+	res := dao.db.WithContext(ctx).Model(&User{}).
+		Where("id = ?", id).Updates(updates)
+	if me, ok := res.Error.(*mysql.MySQLError); ok {
+		const duplicateErr uint16 = 1062
+		if me.Number == duplicateErr {
+			// 已经被别的账号绑定了
+			return ErrDuplicateEmail
+		}
+	}
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (dao *GORMUserDAO) Merge(ctx context.Context, fromId int64, toId int64) error {
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var from, to User
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", fromId).First(&from).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", toId).First(&to).Error
+		if err != nil {
+			return err
+		}
+		// to 上面已经有的身份，以 to 为准
+		updates := map[string]any{
+			"utime": time.Now().UnixMilli(),
+		}
+		if !to.Phone.Valid && from.Phone.Valid {
+			updates["phone"] = from.Phone
+		}
+		if !to.Email.Valid && from.Email.Valid {
+			updates["email"] = from.Email
+			updates["email_verified"] = from.EmailVerified
+			if to.Password == "" {
+				updates["password"] = from.Password
+			}
+		}
+		if !to.WechatOpenId.Valid && from.WechatOpenId.Valid {
+			updates["wechat_open_id"] = from.WechatOpenId
+			updates["wechat_union_id"] = from.WechatUnionId
+		}
+		// 第三方账号也一样，to 已经绑定了同一个第三方的，以 to 为准
+		var providers []string
+		err = tx.Model(&UserIdentity{}).Where("uid = ?", toId).
+			Pluck("provider", &providers).Error
+		if err != nil {
+			return err
+		}
+		if len(providers) > 0 {
+			err = tx.Where("uid = ? AND provider IN ?", fromId, providers).
+				Delete(&UserIdentity{}).Error
+			if err != nil {
+				return err
+			}
+		}
+		err = tx.Model(&UserIdentity{}).Where("uid = ?", fromId).
+			Updates(map[string]any{"uid": toId, "utime": updates["utime"]}).Error
+		if err != nil {
+			return err
+		}
+		// 先删掉 from，把唯一索引让出来
+		err = tx.Where("id = ?", fromId).Delete(&User{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&User{}).Where("id = ?", toId).Updates(updates).Error
+	})
+}
+
+func (dao *GORMUserDAO) Anonymize(ctx context.Context, uid int64) error {
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&User{}).Where("id = ?", uid).
+			Updates(map[string]any{
+				"email":           sql.NullString{},
+				"password":        "",
+				"email_verified":  false,
+				"nickname":        "已注销用户",
+				"birthday":        0,
+				"about_me":        "",
+				"phone":           sql.NullString{},
+				"wechat_open_id":  sql.NullString{},
+				"wechat_union_id": sql.NullString{},
+				"utime":           time.Now().UnixMilli(),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrRecordNotFound
+		}
+		err := tx.Where("uid = ?", uid).Delete(&UserIdentity{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("uid = ?", uid).Delete(&UserTOTP{}).Error
+	})
+}
+
 func (dao *GORMUserDAO) FindById(ctx context.Context, uid int64) (User, error) {
 	var res User
 	err := dao.db.WithContext(ctx).Where("id = ?", uid).First(&res).Error
@@ -93,6 +259,8 @@ type User struct {
 	//Email    *string
 	Email    sql.NullString `gorm:"unique"`
 	Password string
+	// 邮箱是否验证过了
+	EmailVerified bool
 
 	Nickname string `gorm:"type=varchar(128)"`
 	// YYYY-MM-DD
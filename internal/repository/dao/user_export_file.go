@@ -0,0 +1,121 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/ecodeclub/ekit"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+var ErrFileNotFound = errors.New("文件不存在")
+
+// UserExportFileDAO 存放打包好的导出文件。
+// 导出状态在 Redis 里面，所有节点都能看到，所以文件也要放在所有节点都能读到的地方
+//
+//go:generate mockgen -source=./user_export_file.go -package=daomocks -destination=./mocks/user_export_file.mock.go UserExportFileDAO
+type UserExportFileDAO interface {
+	Put(ctx context.Context, key string, body io.ReadSeeker) error
+	// Get 文件不存在返回 ErrFileNotFound，调用方要负责关闭
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete 文件不存在也不会返回 error
+	Delete(ctx context.Context, key string) error
+}
+
+// S3UserExportFileDAO 放在兼容 S3 的对象存储上。
+// bucket 最好配置一个生命周期规则，过期的导出文件由对象存储自己删掉
+type S3UserExportFileDAO struct {
+	oss    *s3.S3
+	bucket string
+}
+
+func NewS3UserExportFileDAO(oss *s3.S3, bucket string) UserExportFileDAO {
+	return &S3UserExportFileDAO{
+		oss:    oss,
+		bucket: bucket,
+	}
+}
+
+func (d *S3UserExportFileDAO) Put(ctx context.Context, key string, body io.ReadSeeker) error {
+	_, err := d.oss.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      ekit.ToPtr[string](d.bucket),
+		Key:         ekit.ToPtr[string](key),
+		Body:        body,
+		ContentType: ekit.ToPtr[string]("application/zip"),
+	})
+	return err
+}
+
+func (d *S3UserExportFileDAO) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	res, err := d.oss.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: ekit.ToPtr[string](d.bucket),
+		Key:    ekit.ToPtr[string](key),
+	})
+	var aerr awserr.Error
+	if errors.As(err, &aerr) && aerr.Code() == s3.ErrCodeNoSuchKey {
+		return nil, ErrFileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+func (d *S3UserExportFileDAO) Delete(ctx context.Context, key string) error {
+	_, err := d.oss.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: ekit.ToPtr[string](d.bucket),
+		Key:    ekit.ToPtr[string](key),
+	})
+	return err
+}
+
+// LocalUserExportFileDAO 放在本地磁盘上，只能在单机部署或者测试的时候用
+type LocalUserExportFileDAO struct {
+	dir string
+}
+
+func NewLocalUserExportFileDAO(dir string) UserExportFileDAO {
+	return &LocalUserExportFileDAO{dir: dir}
+}
+
+func (d *LocalUserExportFileDAO) Put(ctx context.Context, key string, body io.ReadSeeker) error {
+	path := d.path(key)
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, body)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (d *LocalUserExportFileDAO) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(d.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrFileNotFound
+	}
+	return f, err
+}
+
+func (d *LocalUserExportFileDAO) Delete(ctx context.Context, key string) error {
+	err := os.Remove(d.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (d *LocalUserExportFileDAO) path(key string) string {
+	// key 是自己生成的，不过还是防一下 ../
+	return filepath.Join(d.dir, filepath.Clean("/"+key))
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository/cache"
+	"ddd_demo/internal/repository/dao"
+	"ddd_demo/pkg/logger"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+)
+
+var (
+	ErrFollowRelationExist    = dao.ErrFollowRelationExist
+	ErrFollowRelationNotFound = dao.ErrRecordNotFound
+)
+
+//go:generate mockgen -source=./follow.go -package=repomocks -destination=./mocks/follow.mock.go FollowRepository
+type FollowRepository interface {
+	// AddFollowRelation 已经关注过了返回 ErrFollowRelationExist
+	AddFollowRelation(ctx context.Context, follower int64, followee int64) error
+	// RemoveFollowRelation 没有关注过返回 ErrFollowRelationNotFound
+	RemoveFollowRelation(ctx context.Context, follower int64, followee int64) error
+	GetFollowers(ctx context.Context, uid int64, cursor int64, limit int) ([]domain.FollowRelation, error)
+	GetFollowees(ctx context.Context, uid int64, cursor int64, limit int) ([]domain.FollowRelation, error)
+	// FollowInfo 没有关注过返回 ErrFollowRelationNotFound
+	FollowInfo(ctx context.Context, follower int64, followee int64) (domain.FollowRelation, error)
+	GetFollowStatics(ctx context.Context, uid int64) (domain.FollowStatics, error)
+}
+
+type CachedFollowRepository struct {
+	dao   dao.FollowRelationDAO
+	cache cache.FollowCache
+	l     logger.LoggerV1
+}
+
+func NewCachedFollowRepository(dao dao.FollowRelationDAO,
+	cache cache.FollowCache, l logger.LoggerV1) FollowRepository {
+	return &CachedFollowRepository{
+		dao:   dao,
+		cache: cache,
+		l:     l,
+	}
+}
+
+func (repo *CachedFollowRepository) AddFollowRelation(ctx context.Context, follower int64, followee int64) error {
+	err := repo.dao.Insert(ctx, dao.FollowRelation{
+		Follower: follower,
+		Followee: followee,
+	})
+	if err != nil {
+		return err
+	}
+	// 缓存更新失败了，计数会有一点偏差，等缓存过期就好了
+	return repo.cache.Follow(ctx, follower, followee)
+}
+
+func (repo *CachedFollowRepository) RemoveFollowRelation(ctx context.Context, follower int64, followee int64) error {
+	err := repo.dao.Delete(ctx, follower, followee)
+	if err != nil {
+		return err
+	}
+	return repo.cache.CancelFollow(ctx, follower, followee)
+}
+
+func (repo *CachedFollowRepository) GetFollowers(ctx context.Context,
+	uid int64, cursor int64, limit int) ([]domain.FollowRelation, error) {
+	res, err := repo.dao.FollowerList(ctx, uid, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(res, func(idx int, src dao.FollowRelation) domain.FollowRelation {
+		return repo.toDomain(src)
+	}), nil
+}
+
+func (repo *CachedFollowRepository) GetFollowees(ctx context.Context,
+	uid int64, cursor int64, limit int) ([]domain.FollowRelation, error) {
+	res, err := repo.dao.FolloweeList(ctx, uid, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(res, func(idx int, src dao.FollowRelation) domain.FollowRelation {
+		return repo.toDomain(src)
+	}), nil
+}
+
+func (repo *CachedFollowRepository) FollowInfo(ctx context.Context,
+	follower int64, followee int64) (domain.FollowRelation, error) {
+	res, err := repo.dao.FollowRelationDetail(ctx, follower, followee)
+	if err != nil {
+		return domain.FollowRelation{}, err
+	}
+	return repo.toDomain(res), nil
+}
+
+func (repo *CachedFollowRepository) GetFollowStatics(ctx context.Context, uid int64) (domain.FollowStatics, error) {
+	res, err := repo.cache.StaticsInfo(ctx, uid)
+	if err == nil {
+		return res, nil
+	}
+	res.Followers, err = repo.dao.CntFollower(ctx, uid)
+	if err != nil {
+		return domain.FollowStatics{}, err
+	}
+	res.Followees, err = repo.dao.CntFollowee(ctx, uid)
+	if err != nil {
+		return domain.FollowStatics{}, err
+	}
+	err = repo.cache.SetStaticsInfo(ctx, uid, res)
+	if err != nil {
+		// 缓存回写失败不影响返回结果
+		repo.l.Error("回写关注数缓存失败",
+			logger.Int64("uid", uid),
+			logger.Error(err))
+	}
+	return res, nil
+}
+
+func (repo *CachedFollowRepository) toDomain(r dao.FollowRelation) domain.FollowRelation {
+	return domain.FollowRelation{
+		Id:       r.Id,
+		Follower: r.Follower,
+		Followee: r.Followee,
+		Ctime:    time.UnixMilli(r.Ctime),
+	}
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository/cache"
+	cachemocks "ddd_demo/internal/repository/cache/mocks"
+	"ddd_demo/internal/repository/dao"
+	daomocks "ddd_demo/internal/repository/dao/mocks"
+	"ddd_demo/pkg/logger"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestCachedFollowRepository_GetFollowStatics(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (cache.FollowCache, dao.FollowRelationDAO)
+
+		uid int64
+
+		wantStatics domain.FollowStatics
+		wantErr     error
+	}{
+		{
+			name: "缓存命中",
+			mock: func(ctrl *gomock.Controller) (cache.FollowCache, dao.FollowRelationDAO) {
+				c := cachemocks.NewMockFollowCache(ctrl)
+				d := daomocks.NewMockFollowRelationDAO(ctrl)
+				c.EXPECT().StaticsInfo(gomock.Any(), int64(123)).
+					Return(domain.FollowStatics{Followers: 10, Followees: 2}, nil)
+				return c, d
+			},
+			uid:         123,
+			wantStatics: domain.FollowStatics{Followers: 10, Followees: 2},
+		},
+		{
+			name: "缓存未命中，回写缓存",
+			mock: func(ctrl *gomock.Controller) (cache.FollowCache, dao.FollowRelationDAO) {
+				c := cachemocks.NewMockFollowCache(ctrl)
+				d := daomocks.NewMockFollowRelationDAO(ctrl)
+				c.EXPECT().StaticsInfo(gomock.Any(), int64(123)).
+					Return(domain.FollowStatics{}, cache.ErrKeyNotExist)
+				d.EXPECT().CntFollower(gomock.Any(), int64(123)).Return(int64(10), nil)
+				d.EXPECT().CntFollowee(gomock.Any(), int64(123)).Return(int64(2), nil)
+				c.EXPECT().SetStaticsInfo(gomock.Any(), int64(123),
+					domain.FollowStatics{Followers: 10, Followees: 2}).Return(nil)
+				return c, d
+			},
+			uid:         123,
+			wantStatics: domain.FollowStatics{Followers: 10, Followees: 2},
+		},
+		{
+			name: "回写缓存失败",
+			mock: func(ctrl *gomock.Controller) (cache.FollowCache, dao.FollowRelationDAO) {
+				c := cachemocks.NewMockFollowCache(ctrl)
+				d := daomocks.NewMockFollowRelationDAO(ctrl)
+				c.EXPECT().StaticsInfo(gomock.Any(), int64(123)).
+					Return(domain.FollowStatics{}, cache.ErrKeyNotExist)
+				d.EXPECT().CntFollower(gomock.Any(), int64(123)).Return(int64(10), nil)
+				d.EXPECT().CntFollowee(gomock.Any(), int64(123)).Return(int64(2), nil)
+				c.EXPECT().SetStaticsInfo(gomock.Any(), int64(123),
+					domain.FollowStatics{Followers: 10, Followees: 2}).
+					Return(errors.New("redis 错误"))
+				return c, d
+			},
+			uid:         123,
+			wantStatics: domain.FollowStatics{Followers: 10, Followees: 2},
+		},
+		{
+			name: "数据库错误",
+			mock: func(ctrl *gomock.Controller) (cache.FollowCache, dao.FollowRelationDAO) {
+				c := cachemocks.NewMockFollowCache(ctrl)
+				d := daomocks.NewMockFollowRelationDAO(ctrl)
+				c.EXPECT().StaticsInfo(gomock.Any(), int64(123)).
+					Return(domain.FollowStatics{}, cache.ErrKeyNotExist)
+				d.EXPECT().CntFollower(gomock.Any(), int64(123)).
+					Return(int64(0), errors.New("mock db 错误"))
+				return c, d
+			},
+			uid:     123,
+			wantErr: errors.New("mock db 错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			c, d := tc.mock(ctrl)
+			repo := NewCachedFollowRepository(d, c, logger.NewNopLogger())
+			statics, err := repo.GetFollowStatics(context.Background(), tc.uid)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantStatics, statics)
+		})
+	}
+}
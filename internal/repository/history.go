@@ -3,8 +3,45 @@ package repository
 import (
 	"context"
 	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository/dao"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
 )
 
+//go:generate mockgen -source=./history.go -package=repomocks -destination=./mocks/history.mock.go HistoryRecordRepository
 type HistoryRecordRepository interface {
 	AddRecord(ctx context.Context, record domain.HistoryRecord) error
+	// FindByUid 用户所有的浏览记录，最近浏览的在前面
+	FindByUid(ctx context.Context, uid int64) ([]domain.HistoryRecord, error)
+}
+
+type historyRecordRepository struct {
+	dao dao.HistoryRecordDAO
+}
+
+func NewHistoryRecordRepository(dao dao.HistoryRecordDAO) HistoryRecordRepository {
+	return &historyRecordRepository{dao: dao}
+}
+
+func (repo *historyRecordRepository) AddRecord(ctx context.Context, record domain.HistoryRecord) error {
+	return repo.dao.Upsert(ctx, dao.HistoryRecord{
+		Uid:   record.Uid,
+		Biz:   record.Biz,
+		BizId: record.BizId,
+	})
+}
+
+func (repo *historyRecordRepository) FindByUid(ctx context.Context, uid int64) ([]domain.HistoryRecord, error) {
+	res, err := repo.dao.FindByUid(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(res, func(idx int, src dao.HistoryRecord) domain.HistoryRecord {
+		return domain.HistoryRecord{
+			Uid:   src.Uid,
+			Biz:   src.Biz,
+			BizId: src.BizId,
+			Utime: time.UnixMilli(src.Utime),
+		}
+	}), nil
 }
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository/dao"
+	"github.com/ecodeclub/ekit/slice"
+)
+
+//go:generate mockgen -source=./identity.go -package=repomocks -destination=./mocks/identity.mock.go IdentityRepository
+type IdentityRepository interface {
+	// Create 第三方账号已经绑定了别的用户，返回 ErrDuplicateUser
+	Create(ctx context.Context, identity domain.OAuth2Identity) error
+	// CreateWithUser 同时创建用户和绑定关系，返回用户 id
+	CreateWithUser(ctx context.Context, u domain.User, identity domain.OAuth2Identity) (int64, error)
+	// FindBySubject 没有绑定的话返回 ErrUserNotFound
+	FindBySubject(ctx context.Context, provider string, subject string) (domain.OAuth2Identity, error)
+	FindByUid(ctx context.Context, uid int64) ([]domain.OAuth2Identity, error)
+	Delete(ctx context.Context, uid int64, provider string) error
+}
+
+type identityRepository struct {
+	dao dao.IdentityDAO
+}
+
+func NewIdentityRepository(dao dao.IdentityDAO) IdentityRepository {
+	return &identityRepository{dao: dao}
+}
+
+func (repo *identityRepository) Create(ctx context.Context, identity domain.OAuth2Identity) error {
+	return repo.dao.Insert(ctx, dao.UserIdentity{
+		Uid:      identity.Uid,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	})
+}
+
+func (repo *identityRepository) CreateWithUser(ctx context.Context,
+	u domain.User, identity domain.OAuth2Identity) (int64, error) {
+	return repo.dao.InsertWithUser(ctx, dao.User{
+		Nickname: u.Nickname,
+	}, dao.UserIdentity{
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	})
+}
+
+func (repo *identityRepository) FindBySubject(ctx context.Context,
+	provider string, subject string) (domain.OAuth2Identity, error) {
+	res, err := repo.dao.FindBySubject(ctx, provider, subject)
+	if err != nil {
+		return domain.OAuth2Identity{}, err
+	}
+	return repo.toDomain(res), nil
+}
+
+func (repo *identityRepository) FindByUid(ctx context.Context, uid int64) ([]domain.OAuth2Identity, error) {
+	res, err := repo.dao.FindByUid(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(res, func(idx int, src dao.UserIdentity) domain.OAuth2Identity {
+		return repo.toDomain(src)
+	}), nil
+}
+
+func (repo *identityRepository) Delete(ctx context.Context, uid int64, provider string) error {
+	return repo.dao.Delete(ctx, uid, provider)
+}
+
+func (repo *identityRepository) toDomain(i dao.UserIdentity) domain.OAuth2Identity {
+	return domain.OAuth2Identity{
+		Uid:      i.Uid,
+		Provider: i.Provider,
+		Subject:  i.Subject,
+		Email:    i.Email,
+	}
+}
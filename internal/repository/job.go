@@ -7,18 +7,36 @@ import (
 	"time"
 )
 
+// ErrNoMoreJob 没有可以抢占的任务
+var ErrNoMoreJob = dao.ErrRecordNotFound
+
 type JobRepository interface {
 	Preempt(ctx context.Context) (domain.Job, error)
 	Release(ctx context.Context, id int64) error
 	UpdateUtime(ctx context.Context, id int64) error
 	UpdateNextTime(ctx context.Context, id int64, next time.Time) error
 	Stop(ctx context.Context, id int64) error
+	AddJob(ctx context.Context, j domain.Job) error
 }
 
 type PreemptCronJobRepository struct {
 	dao dao.JobDAO
 }
 
+func NewPreemptCronJobRepository(dao dao.JobDAO) JobRepository {
+	return &PreemptCronJobRepository{dao: dao}
+}
+
+func (g *PreemptCronJobRepository) AddJob(ctx context.Context, j domain.Job) error {
+	return g.dao.Insert(ctx, dao.Job{
+		Name:     j.Name,
+		Config:   j.Cfg,
+		Executor: j.Executor,
+		Cron:     j.Cron,
+		NextTime: j.NextTime().UnixMilli(),
+	})
+}
+
 func (g *PreemptCronJobRepository) Preempt(ctx context.Context) (domain.Job, error) {
 	j, err := g.dao.Preempt(ctx)
 	if err != nil {
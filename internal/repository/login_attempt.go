@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"ddd_demo/internal/repository/cache"
+	"time"
+)
+
+var ErrAccountLocked = cache.ErrAccountLocked
+
+//go:generate mockgen -source=./login_attempt.go -package=repomocks -destination=./mocks/login_attempt.mock.go LoginAttemptRepository
+type LoginAttemptRepository interface {
+	Check(ctx context.Context, email string) (time.Duration, error)
+	Fail(ctx context.Context, email string) (bool, error)
+	Reset(ctx context.Context, email string) error
+	CheckIP(ctx context.Context, ip string) (time.Duration, error)
+	FailIP(ctx context.Context, ip string) error
+}
+
+type CachedLoginAttemptRepository struct {
+	cache cache.LoginAttemptCache
+}
+
+func NewLoginAttemptRepository(c cache.LoginAttemptCache) LoginAttemptRepository {
+	return &CachedLoginAttemptRepository{
+		cache: c,
+	}
+}
+
+func (repo *CachedLoginAttemptRepository) Check(ctx context.Context, email string) (time.Duration, error) {
+	return repo.cache.Check(ctx, email)
+}
+
+func (repo *CachedLoginAttemptRepository) Fail(ctx context.Context, email string) (bool, error) {
+	return repo.cache.Fail(ctx, email)
+}
+
+func (repo *CachedLoginAttemptRepository) Reset(ctx context.Context, email string) error {
+	return repo.cache.Reset(ctx, email)
+}
+
+func (repo *CachedLoginAttemptRepository) CheckIP(ctx context.Context, ip string) (time.Duration, error) {
+	return repo.cache.CheckIP(ctx, ip)
+}
+
+func (repo *CachedLoginAttemptRepository) FailIP(ctx context.Context, ip string) error {
+	return repo.cache.FailIP(ctx, ip)
+}
@@ -1,16 +1,20 @@
 // Code generated by MockGen. DO NOT EDIT.
 // Source: ./article.go
-
+//
+// Generated by this command:
+//
+//	mockgen -source=./article.go -package=repomocks -destination=./mocks/article.mock.go ArticleRepository
+//
 // Package repomocks is a generated GoMock package.
 package repomocks
 
 import (
 	context "context"
-	domain "ddd_demo/internal/domain"
 	reflect "reflect"
 	time "time"
 
-	gomock "github.com/golang/mock/gomock"
+	domain "ddd_demo/internal/domain"
+	gomock "go.uber.org/mock/gomock"
 )
 
 // MockArticleRepository is a mock of ArticleRepository interface.
@@ -36,6 +40,20 @@ func (m *MockArticleRepository) EXPECT() *MockArticleRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CancelSchedule mocks base method.
+func (m *MockArticleRepository) CancelSchedule(ctx context.Context, uid, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelSchedule", ctx, uid, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelSchedule indicates an expected call of CancelSchedule.
+func (mr *MockArticleRepositoryMockRecorder) CancelSchedule(ctx, uid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSchedule", reflect.TypeOf((*MockArticleRepository)(nil).CancelSchedule), ctx, uid, id)
+}
+
 // Create mocks base method.
 func (m *MockArticleRepository) Create(ctx context.Context, art domain.Article) (int64, error) {
 	m.ctrl.T.Helper()
@@ -46,11 +64,56 @@ func (m *MockArticleRepository) Create(ctx context.Context, art domain.Article)
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockArticleRepositoryMockRecorder) Create(ctx, art interface{}) *gomock.Call {
+func (mr *MockArticleRepositoryMockRecorder) Create(ctx, art any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockArticleRepository)(nil).Create), ctx, art)
 }
 
+// FindById mocks base method.
+func (m *MockArticleRepository) FindById(ctx context.Context, id int64) (domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, id)
+	ret0, _ := ret[0].(domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockArticleRepositoryMockRecorder) FindById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockArticleRepository)(nil).FindById), ctx, id)
+}
+
+// FindPubById mocks base method.
+func (m *MockArticleRepository) FindPubById(ctx context.Context, id int64) (domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPubById", ctx, id)
+	ret0, _ := ret[0].(domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPubById indicates an expected call of FindPubById.
+func (mr *MockArticleRepositoryMockRecorder) FindPubById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPubById", reflect.TypeOf((*MockArticleRepository)(nil).FindPubById), ctx, id)
+}
+
+// FlushDrafts mocks base method.
+func (m *MockArticleRepository) FlushDrafts(ctx context.Context, before time.Time, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlushDrafts", ctx, before, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FlushDrafts indicates an expected call of FlushDrafts.
+func (mr *MockArticleRepositoryMockRecorder) FlushDrafts(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlushDrafts", reflect.TypeOf((*MockArticleRepository)(nil).FlushDrafts), ctx, before, limit)
+}
+
 // GetByAuthor mocks base method.
 func (m *MockArticleRepository) GetByAuthor(ctx context.Context, uid int64, offset, limit int) ([]domain.Article, error) {
 	m.ctrl.T.Helper()
@@ -61,11 +124,26 @@ func (m *MockArticleRepository) GetByAuthor(ctx context.Context, uid int64, offs
 }
 
 // GetByAuthor indicates an expected call of GetByAuthor.
-func (mr *MockArticleRepositoryMockRecorder) GetByAuthor(ctx, uid, offset, limit interface{}) *gomock.Call {
+func (mr *MockArticleRepositoryMockRecorder) GetByAuthor(ctx, uid, offset, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAuthor", reflect.TypeOf((*MockArticleRepository)(nil).GetByAuthor), ctx, uid, offset, limit)
 }
 
+// GetByAuthorByCursor mocks base method.
+func (m *MockArticleRepository) GetByAuthorByCursor(ctx context.Context, uid int64, cursor domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByAuthorByCursor", ctx, uid, cursor, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByAuthorByCursor indicates an expected call of GetByAuthorByCursor.
+func (mr *MockArticleRepositoryMockRecorder) GetByAuthorByCursor(ctx, uid, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAuthorByCursor", reflect.TypeOf((*MockArticleRepository)(nil).GetByAuthorByCursor), ctx, uid, cursor, limit)
+}
+
 // GetById mocks base method.
 func (m *MockArticleRepository) GetById(ctx context.Context, id int64) (domain.Article, error) {
 	m.ctrl.T.Helper()
@@ -76,7 +154,7 @@ func (m *MockArticleRepository) GetById(ctx context.Context, id int64) (domain.A
 }
 
 // GetById indicates an expected call of GetById.
-func (mr *MockArticleRepositoryMockRecorder) GetById(ctx, id interface{}) *gomock.Call {
+func (mr *MockArticleRepositoryMockRecorder) GetById(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockArticleRepository)(nil).GetById), ctx, id)
 }
@@ -91,11 +169,71 @@ func (m *MockArticleRepository) GetPubById(ctx context.Context, id int64) (domai
 }
 
 // GetPubById indicates an expected call of GetPubById.
-func (mr *MockArticleRepositoryMockRecorder) GetPubById(ctx, id interface{}) *gomock.Call {
+func (mr *MockArticleRepositoryMockRecorder) GetPubById(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPubById", reflect.TypeOf((*MockArticleRepository)(nil).GetPubById), ctx, id)
 }
 
+// GetPubByIds mocks base method.
+func (m *MockArticleRepository) GetPubByIds(ctx context.Context, ids []int64) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPubByIds", ctx, ids)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPubByIds indicates an expected call of GetPubByIds.
+func (mr *MockArticleRepositoryMockRecorder) GetPubByIds(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPubByIds", reflect.TypeOf((*MockArticleRepository)(nil).GetPubByIds), ctx, ids)
+}
+
+// GetRevision mocks base method.
+func (m *MockArticleRepository) GetRevision(ctx context.Context, id, version int64) (domain.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", ctx, id, version)
+	ret0, _ := ret[0].(domain.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockArticleRepositoryMockRecorder) GetRevision(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockArticleRepository)(nil).GetRevision), ctx, id, version)
+}
+
+// ListByAuthor mocks base method.
+func (m *MockArticleRepository) ListByAuthor(ctx context.Context, uid int64, cursor domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByAuthor", ctx, uid, cursor, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByAuthor indicates an expected call of ListByAuthor.
+func (mr *MockArticleRepositoryMockRecorder) ListByAuthor(ctx, uid, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByAuthor", reflect.TypeOf((*MockArticleRepository)(nil).ListByAuthor), ctx, uid, cursor, limit)
+}
+
+// ListDue mocks base method.
+func (m *MockArticleRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDue", ctx, now, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDue indicates an expected call of ListDue.
+func (mr *MockArticleRepositoryMockRecorder) ListDue(ctx, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDue", reflect.TypeOf((*MockArticleRepository)(nil).ListDue), ctx, now, limit)
+}
+
 // ListPub mocks base method.
 func (m *MockArticleRepository) ListPub(ctx context.Context, start time.Time, offset, limit int) ([]domain.Article, error) {
 	m.ctrl.T.Helper()
@@ -106,11 +244,130 @@ func (m *MockArticleRepository) ListPub(ctx context.Context, start time.Time, of
 }
 
 // ListPub indicates an expected call of ListPub.
-func (mr *MockArticleRepositoryMockRecorder) ListPub(ctx, start, offset, limit interface{}) *gomock.Call {
+func (mr *MockArticleRepositoryMockRecorder) ListPub(ctx, start, offset, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPub", reflect.TypeOf((*MockArticleRepository)(nil).ListPub), ctx, start, offset, limit)
 }
 
+// ListPubByAuthor mocks base method.
+func (m *MockArticleRepository) ListPubByAuthor(ctx context.Context, uid int64, visibilities []domain.ArticleVisibility, offset, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPubByAuthor", ctx, uid, visibilities, offset, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPubByAuthor indicates an expected call of ListPubByAuthor.
+func (mr *MockArticleRepositoryMockRecorder) ListPubByAuthor(ctx, uid, visibilities, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPubByAuthor", reflect.TypeOf((*MockArticleRepository)(nil).ListPubByAuthor), ctx, uid, visibilities, offset, limit)
+}
+
+// ListPubByCursor mocks base method.
+func (m *MockArticleRepository) ListPubByCursor(ctx context.Context, start time.Time, cursor domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPubByCursor", ctx, start, cursor, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPubByCursor indicates an expected call of ListPubByCursor.
+func (mr *MockArticleRepositoryMockRecorder) ListPubByCursor(ctx, start, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPubByCursor", reflect.TypeOf((*MockArticleRepository)(nil).ListPubByCursor), ctx, start, cursor, limit)
+}
+
+// ListPubByTag mocks base method.
+func (m *MockArticleRepository) ListPubByTag(ctx context.Context, tag string, cursor int64, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPubByTag", ctx, tag, cursor, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPubByTag indicates an expected call of ListPubByTag.
+func (mr *MockArticleRepositoryMockRecorder) ListPubByTag(ctx, tag, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPubByTag", reflect.TypeOf((*MockArticleRepository)(nil).ListPubByTag), ctx, tag, cursor, limit)
+}
+
+// ListRevisions mocks base method.
+func (m *MockArticleRepository) ListRevisions(ctx context.Context, id int64, offset, limit int) ([]domain.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevisions", ctx, id, offset, limit)
+	ret0, _ := ret[0].([]domain.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevisions indicates an expected call of ListRevisions.
+func (mr *MockArticleRepositoryMockRecorder) ListRevisions(ctx, id, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevisions", reflect.TypeOf((*MockArticleRepository)(nil).ListRevisions), ctx, id, offset, limit)
+}
+
+// ListTagCounts mocks base method.
+func (m *MockArticleRepository) ListTagCounts(ctx context.Context, limit int) ([]domain.TagCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTagCounts", ctx, limit)
+	ret0, _ := ret[0].([]domain.TagCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTagCounts indicates an expected call of ListTagCounts.
+func (mr *MockArticleRepositoryMockRecorder) ListTagCounts(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTagCounts", reflect.TypeOf((*MockArticleRepository)(nil).ListTagCounts), ctx, limit)
+}
+
+// ReferencedAttachments mocks base method.
+func (m *MockArticleRepository) ReferencedAttachments(ctx context.Context, keys []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReferencedAttachments", ctx, keys)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReferencedAttachments indicates an expected call of ReferencedAttachments.
+func (mr *MockArticleRepositoryMockRecorder) ReferencedAttachments(ctx, keys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReferencedAttachments", reflect.TypeOf((*MockArticleRepository)(nil).ReferencedAttachments), ctx, keys)
+}
+
+// RejectReview mocks base method.
+func (m *MockArticleRepository) RejectReview(ctx context.Context, uid, id, revision int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectReview", ctx, uid, id, revision)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RejectReview indicates an expected call of RejectReview.
+func (mr *MockArticleRepositoryMockRecorder) RejectReview(ctx, uid, id, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectReview", reflect.TypeOf((*MockArticleRepository)(nil).RejectReview), ctx, uid, id, revision)
+}
+
+// SaveDraft mocks base method.
+func (m *MockArticleRepository) SaveDraft(ctx context.Context, draft domain.ArticleDraft) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDraft", ctx, draft)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveDraft indicates an expected call of SaveDraft.
+func (mr *MockArticleRepositoryMockRecorder) SaveDraft(ctx, draft any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDraft", reflect.TypeOf((*MockArticleRepository)(nil).SaveDraft), ctx, draft)
+}
+
 // Sync mocks base method.
 func (m *MockArticleRepository) Sync(ctx context.Context, art domain.Article) (int64, error) {
 	m.ctrl.T.Helper()
@@ -121,7 +378,7 @@ func (m *MockArticleRepository) Sync(ctx context.Context, art domain.Article) (i
 }
 
 // Sync indicates an expected call of Sync.
-func (mr *MockArticleRepositoryMockRecorder) Sync(ctx, art interface{}) *gomock.Call {
+func (mr *MockArticleRepositoryMockRecorder) Sync(ctx, art any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockArticleRepository)(nil).Sync), ctx, art)
 }
@@ -135,11 +392,39 @@ func (m *MockArticleRepository) SyncStatus(ctx context.Context, uid, id int64, s
 }
 
 // SyncStatus indicates an expected call of SyncStatus.
-func (mr *MockArticleRepositoryMockRecorder) SyncStatus(ctx, uid, id, status interface{}) *gomock.Call {
+func (mr *MockArticleRepositoryMockRecorder) SyncStatus(ctx, uid, id, status any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncStatus", reflect.TypeOf((*MockArticleRepository)(nil).SyncStatus), ctx, uid, id, status)
 }
 
+// SyncVisibility mocks base method.
+func (m *MockArticleRepository) SyncVisibility(ctx context.Context, uid, id int64, visibility domain.ArticleVisibility) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncVisibility", ctx, uid, id, visibility)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncVisibility indicates an expected call of SyncVisibility.
+func (mr *MockArticleRepositoryMockRecorder) SyncVisibility(ctx, uid, id, visibility any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncVisibility", reflect.TypeOf((*MockArticleRepository)(nil).SyncVisibility), ctx, uid, id, visibility)
+}
+
+// TransferAuthor mocks base method.
+func (m *MockArticleRepository) TransferAuthor(ctx context.Context, fromUid, toUid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferAuthor", ctx, fromUid, toUid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TransferAuthor indicates an expected call of TransferAuthor.
+func (mr *MockArticleRepositoryMockRecorder) TransferAuthor(ctx, fromUid, toUid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferAuthor", reflect.TypeOf((*MockArticleRepository)(nil).TransferAuthor), ctx, fromUid, toUid)
+}
+
 // Update mocks base method.
 func (m *MockArticleRepository) Update(ctx context.Context, art domain.Article) error {
 	m.ctrl.T.Helper()
@@ -149,7 +434,35 @@ func (m *MockArticleRepository) Update(ctx context.Context, art domain.Article)
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockArticleRepositoryMockRecorder) Update(ctx, art interface{}) *gomock.Call {
+func (mr *MockArticleRepositoryMockRecorder) Update(ctx, art any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockArticleRepository)(nil).Update), ctx, art)
 }
+
+// UpdateSchedule mocks base method.
+func (m *MockArticleRepository) UpdateSchedule(ctx context.Context, uid, id int64, publishAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSchedule", ctx, uid, id, publishAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSchedule indicates an expected call of UpdateSchedule.
+func (mr *MockArticleRepositoryMockRecorder) UpdateSchedule(ctx, uid, id, publishAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSchedule", reflect.TypeOf((*MockArticleRepository)(nil).UpdateSchedule), ctx, uid, id, publishAt)
+}
+
+// WithdrawByAuthor mocks base method.
+func (m *MockArticleRepository) WithdrawByAuthor(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawByAuthor", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithdrawByAuthor indicates an expected call of WithdrawByAuthor.
+func (mr *MockArticleRepositoryMockRecorder) WithdrawByAuthor(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawByAuthor", reflect.TypeOf((*MockArticleRepository)(nil).WithdrawByAuthor), ctx, uid)
+}
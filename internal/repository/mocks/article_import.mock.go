@@ -0,0 +1,98 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./article_import.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./article_import.go -package=repomocks -destination=./mocks/article_import.mock.go ArticleImportRepository
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "ddd_demo/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockArticleImportRepository is a mock of ArticleImportRepository interface.
+type MockArticleImportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockArticleImportRepositoryMockRecorder
+}
+
+// MockArticleImportRepositoryMockRecorder is the mock recorder for MockArticleImportRepository.
+type MockArticleImportRepositoryMockRecorder struct {
+	mock *MockArticleImportRepository
+}
+
+// NewMockArticleImportRepository creates a new mock instance.
+func NewMockArticleImportRepository(ctrl *gomock.Controller) *MockArticleImportRepository {
+	mock := &MockArticleImportRepository{ctrl: ctrl}
+	mock.recorder = &MockArticleImportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArticleImportRepository) EXPECT() *MockArticleImportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockArticleImportRepository) Claim(ctx context.Context, imp domain.ArticleImport, timeout, expiration time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, imp, timeout, expiration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockArticleImportRepositoryMockRecorder) Claim(ctx, imp, timeout, expiration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockArticleImportRepository)(nil).Claim), ctx, imp, timeout, expiration)
+}
+
+// Get mocks base method.
+func (m *MockArticleImportRepository) Get(ctx context.Context, uid int64) (domain.ArticleImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, uid)
+	ret0, _ := ret[0].(domain.ArticleImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockArticleImportRepositoryMockRecorder) Get(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockArticleImportRepository)(nil).Get), ctx, uid)
+}
+
+// Release mocks base method.
+func (m *MockArticleImportRepository) Release(ctx context.Context, imp domain.ArticleImport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, imp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockArticleImportRepositoryMockRecorder) Release(ctx, imp any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockArticleImportRepository)(nil).Release), ctx, imp)
+}
+
+// Save mocks base method.
+func (m *MockArticleImportRepository) Save(ctx context.Context, imp domain.ArticleImport, expiration time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, imp, expiration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockArticleImportRepositoryMockRecorder) Save(ctx, imp, expiration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockArticleImportRepository)(nil).Save), ctx, imp, expiration)
+}
@@ -0,0 +1,99 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./article_review.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./article_review.go -package=repomocks -destination=./mocks/article_review.mock.go ArticleReviewRepository
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "ddd_demo/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockArticleReviewRepository is a mock of ArticleReviewRepository interface.
+type MockArticleReviewRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockArticleReviewRepositoryMockRecorder
+}
+
+// MockArticleReviewRepositoryMockRecorder is the mock recorder for MockArticleReviewRepository.
+type MockArticleReviewRepositoryMockRecorder struct {
+	mock *MockArticleReviewRepository
+}
+
+// NewMockArticleReviewRepository creates a new mock instance.
+func NewMockArticleReviewRepository(ctrl *gomock.Controller) *MockArticleReviewRepository {
+	mock := &MockArticleReviewRepository{ctrl: ctrl}
+	mock.recorder = &MockArticleReviewRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArticleReviewRepository) EXPECT() *MockArticleReviewRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindById mocks base method.
+func (m *MockArticleReviewRepository) FindById(ctx context.Context, id int64) (domain.ArticleReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, id)
+	ret0, _ := ret[0].(domain.ArticleReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockArticleReviewRepositoryMockRecorder) FindById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockArticleReviewRepository)(nil).FindById), ctx, id)
+}
+
+// ListPending mocks base method.
+func (m *MockArticleReviewRepository) ListPending(ctx context.Context, offset, limit int) ([]domain.ArticleReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPending", ctx, offset, limit)
+	ret0, _ := ret[0].([]domain.ArticleReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPending indicates an expected call of ListPending.
+func (mr *MockArticleReviewRepositoryMockRecorder) ListPending(ctx, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPending", reflect.TypeOf((*MockArticleReviewRepository)(nil).ListPending), ctx, offset, limit)
+}
+
+// Save mocks base method.
+func (m *MockArticleReviewRepository) Save(ctx context.Context, r domain.ArticleReview) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, r)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockArticleReviewRepositoryMockRecorder) Save(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockArticleReviewRepository)(nil).Save), ctx, r)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockArticleReviewRepository) UpdateStatus(ctx context.Context, r domain.ArticleReview) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockArticleReviewRepositoryMockRecorder) UpdateStatus(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockArticleReviewRepository)(nil).UpdateStatus), ctx, r)
+}
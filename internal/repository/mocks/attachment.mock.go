@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./attachment.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./attachment.go -package=repomocks -destination=./mocks/attachment.mock.go AttachmentRepository
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "ddd_demo/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAttachmentRepository is a mock of AttachmentRepository interface.
+type MockAttachmentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachmentRepositoryMockRecorder
+}
+
+// MockAttachmentRepositoryMockRecorder is the mock recorder for MockAttachmentRepository.
+type MockAttachmentRepositoryMockRecorder struct {
+	mock *MockAttachmentRepository
+}
+
+// NewMockAttachmentRepository creates a new mock instance.
+func NewMockAttachmentRepository(ctrl *gomock.Controller) *MockAttachmentRepository {
+	mock := &MockAttachmentRepository{ctrl: ctrl}
+	mock.recorder = &MockAttachmentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttachmentRepository) EXPECT() *MockAttachmentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteStale mocks base method.
+func (m *MockAttachmentRepository) DeleteStale(ctx context.Context, id int64, before time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStale", ctx, id, before)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteStale indicates an expected call of DeleteStale.
+func (mr *MockAttachmentRepositoryMockRecorder) DeleteStale(ctx, id, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStale", reflect.TypeOf((*MockAttachmentRepository)(nil).DeleteStale), ctx, id, before)
+}
+
+// FindByKey mocks base method.
+func (m *MockAttachmentRepository) FindByKey(ctx context.Context, key string) (domain.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByKey", ctx, key)
+	ret0, _ := ret[0].(domain.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByKey indicates an expected call of FindByKey.
+func (mr *MockAttachmentRepositoryMockRecorder) FindByKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByKey", reflect.TypeOf((*MockAttachmentRepository)(nil).FindByKey), ctx, key)
+}
+
+// ListStale mocks base method.
+func (m *MockAttachmentRepository) ListStale(ctx context.Context, before time.Time, cursor int64, limit int) ([]domain.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStale", ctx, before, cursor, limit)
+	ret0, _ := ret[0].([]domain.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStale indicates an expected call of ListStale.
+func (mr *MockAttachmentRepositoryMockRecorder) ListStale(ctx, before, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStale", reflect.TypeOf((*MockAttachmentRepository)(nil).ListStale), ctx, before, cursor, limit)
+}
+
+// Save mocks base method.
+func (m *MockAttachmentRepository) Save(ctx context.Context, a domain.Attachment) (domain.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, a)
+	ret0, _ := ret[0].(domain.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockAttachmentRepositoryMockRecorder) Save(ctx, a any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockAttachmentRepository)(nil).Save), ctx, a)
+}
@@ -0,0 +1,157 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./comment.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./comment.go -package=repomocks -destination=./mocks/comment.mock.go CommentRepository
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "ddd_demo/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCommentRepository is a mock of CommentRepository interface.
+type MockCommentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommentRepositoryMockRecorder
+}
+
+// MockCommentRepositoryMockRecorder is the mock recorder for MockCommentRepository.
+type MockCommentRepositoryMockRecorder struct {
+	mock *MockCommentRepository
+}
+
+// NewMockCommentRepository creates a new mock instance.
+func NewMockCommentRepository(ctrl *gomock.Controller) *MockCommentRepository {
+	mock := &MockCommentRepository{ctrl: ctrl}
+	mock.recorder = &MockCommentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCommentRepository) EXPECT() *MockCommentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CancelLike mocks base method.
+func (m *MockCommentRepository) CancelLike(ctx context.Context, uid, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelLike", ctx, uid, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelLike indicates an expected call of CancelLike.
+func (mr *MockCommentRepositoryMockRecorder) CancelLike(ctx, uid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelLike", reflect.TypeOf((*MockCommentRepository)(nil).CancelLike), ctx, uid, id)
+}
+
+// CreateComment mocks base method.
+func (m *MockCommentRepository) CreateComment(ctx context.Context, c domain.Comment) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateComment", ctx, c)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateComment indicates an expected call of CreateComment.
+func (mr *MockCommentRepositoryMockRecorder) CreateComment(ctx, c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockCommentRepository)(nil).CreateComment), ctx, c)
+}
+
+// DeleteComment mocks base method.
+func (m *MockCommentRepository) DeleteComment(ctx context.Context, c domain.Comment) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteComment", ctx, c)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteComment indicates an expected call of DeleteComment.
+func (mr *MockCommentRepositoryMockRecorder) DeleteComment(ctx, c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockCommentRepository)(nil).DeleteComment), ctx, c)
+}
+
+// FindByBiz mocks base method.
+func (m *MockCommentRepository) FindByBiz(ctx context.Context, biz string, bizId int64, sort domain.CommentSort, cursor domain.CommentCursor, limit int) ([]domain.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByBiz", ctx, biz, bizId, sort, cursor, limit)
+	ret0, _ := ret[0].([]domain.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByBiz indicates an expected call of FindByBiz.
+func (mr *MockCommentRepositoryMockRecorder) FindByBiz(ctx, biz, bizId, sort, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByBiz", reflect.TypeOf((*MockCommentRepository)(nil).FindByBiz), ctx, biz, bizId, sort, cursor, limit)
+}
+
+// FindById mocks base method.
+func (m *MockCommentRepository) FindById(ctx context.Context, id int64) (domain.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, id)
+	ret0, _ := ret[0].(domain.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockCommentRepositoryMockRecorder) FindById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockCommentRepository)(nil).FindById), ctx, id)
+}
+
+// FindReplies mocks base method.
+func (m *MockCommentRepository) FindReplies(ctx context.Context, rootId, cursor int64, limit int) ([]domain.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindReplies", ctx, rootId, cursor, limit)
+	ret0, _ := ret[0].([]domain.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindReplies indicates an expected call of FindReplies.
+func (mr *MockCommentRepositoryMockRecorder) FindReplies(ctx, rootId, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindReplies", reflect.TypeOf((*MockCommentRepository)(nil).FindReplies), ctx, rootId, cursor, limit)
+}
+
+// Like mocks base method.
+func (m *MockCommentRepository) Like(ctx context.Context, uid, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Like", ctx, uid, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Like indicates an expected call of Like.
+func (mr *MockCommentRepositoryMockRecorder) Like(ctx, uid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Like", reflect.TypeOf((*MockCommentRepository)(nil).Like), ctx, uid, id)
+}
+
+// Liked mocks base method.
+func (m *MockCommentRepository) Liked(ctx context.Context, uid int64, cs []domain.Comment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Liked", ctx, uid, cs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Liked indicates an expected call of Liked.
+func (mr *MockCommentRepositoryMockRecorder) Liked(ctx, uid, cs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Liked", reflect.TypeOf((*MockCommentRepository)(nil).Liked), ctx, uid, cs)
+}
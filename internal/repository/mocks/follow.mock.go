@@ -0,0 +1,128 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./follow.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./follow.go -package=repomocks -destination=./mocks/follow.mock.go
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "ddd_demo/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFollowRepository is a mock of FollowRepository interface.
+type MockFollowRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFollowRepositoryMockRecorder
+}
+
+// MockFollowRepositoryMockRecorder is the mock recorder for MockFollowRepository.
+type MockFollowRepositoryMockRecorder struct {
+	mock *MockFollowRepository
+}
+
+// NewMockFollowRepository creates a new mock instance.
+func NewMockFollowRepository(ctrl *gomock.Controller) *MockFollowRepository {
+	mock := &MockFollowRepository{ctrl: ctrl}
+	mock.recorder = &MockFollowRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFollowRepository) EXPECT() *MockFollowRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddFollowRelation mocks base method.
+func (m *MockFollowRepository) AddFollowRelation(ctx context.Context, follower, followee int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddFollowRelation", ctx, follower, followee)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddFollowRelation indicates an expected call of AddFollowRelation.
+func (mr *MockFollowRepositoryMockRecorder) AddFollowRelation(ctx, follower, followee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFollowRelation", reflect.TypeOf((*MockFollowRepository)(nil).AddFollowRelation), ctx, follower, followee)
+}
+
+// FollowInfo mocks base method.
+func (m *MockFollowRepository) FollowInfo(ctx context.Context, follower, followee int64) (domain.FollowRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowInfo", ctx, follower, followee)
+	ret0, _ := ret[0].(domain.FollowRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FollowInfo indicates an expected call of FollowInfo.
+func (mr *MockFollowRepositoryMockRecorder) FollowInfo(ctx, follower, followee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowInfo", reflect.TypeOf((*MockFollowRepository)(nil).FollowInfo), ctx, follower, followee)
+}
+
+// GetFollowStatics mocks base method.
+func (m *MockFollowRepository) GetFollowStatics(ctx context.Context, uid int64) (domain.FollowStatics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowStatics", ctx, uid)
+	ret0, _ := ret[0].(domain.FollowStatics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFollowStatics indicates an expected call of GetFollowStatics.
+func (mr *MockFollowRepositoryMockRecorder) GetFollowStatics(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowStatics", reflect.TypeOf((*MockFollowRepository)(nil).GetFollowStatics), ctx, uid)
+}
+
+// GetFollowees mocks base method.
+func (m *MockFollowRepository) GetFollowees(ctx context.Context, uid, cursor int64, limit int) ([]domain.FollowRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowees", ctx, uid, cursor, limit)
+	ret0, _ := ret[0].([]domain.FollowRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFollowees indicates an expected call of GetFollowees.
+func (mr *MockFollowRepositoryMockRecorder) GetFollowees(ctx, uid, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowees", reflect.TypeOf((*MockFollowRepository)(nil).GetFollowees), ctx, uid, cursor, limit)
+}
+
+// GetFollowers mocks base method.
+func (m *MockFollowRepository) GetFollowers(ctx context.Context, uid, cursor int64, limit int) ([]domain.FollowRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowers", ctx, uid, cursor, limit)
+	ret0, _ := ret[0].([]domain.FollowRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFollowers indicates an expected call of GetFollowers.
+func (mr *MockFollowRepositoryMockRecorder) GetFollowers(ctx, uid, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowers", reflect.TypeOf((*MockFollowRepository)(nil).GetFollowers), ctx, uid, cursor, limit)
+}
+
+// RemoveFollowRelation mocks base method.
+func (m *MockFollowRepository) RemoveFollowRelation(ctx context.Context, follower, followee int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFollowRelation", ctx, follower, followee)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFollowRelation indicates an expected call of RemoveFollowRelation.
+func (mr *MockFollowRepositoryMockRecorder) RemoveFollowRelation(ctx, follower, followee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFollowRelation", reflect.TypeOf((*MockFollowRepository)(nil).RemoveFollowRelation), ctx, follower, followee)
+}
@@ -0,0 +1,69 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./history.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./history.go -package=repomocks -destination=./mocks/history.mock.go
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "ddd_demo/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockHistoryRecordRepository is a mock of HistoryRecordRepository interface.
+type MockHistoryRecordRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistoryRecordRepositoryMockRecorder
+}
+
+// MockHistoryRecordRepositoryMockRecorder is the mock recorder for MockHistoryRecordRepository.
+type MockHistoryRecordRepositoryMockRecorder struct {
+	mock *MockHistoryRecordRepository
+}
+
+// NewMockHistoryRecordRepository creates a new mock instance.
+func NewMockHistoryRecordRepository(ctrl *gomock.Controller) *MockHistoryRecordRepository {
+	mock := &MockHistoryRecordRepository{ctrl: ctrl}
+	mock.recorder = &MockHistoryRecordRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistoryRecordRepository) EXPECT() *MockHistoryRecordRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddRecord mocks base method.
+func (m *MockHistoryRecordRepository) AddRecord(ctx context.Context, record domain.HistoryRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRecord", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRecord indicates an expected call of AddRecord.
+func (mr *MockHistoryRecordRepositoryMockRecorder) AddRecord(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRecord", reflect.TypeOf((*MockHistoryRecordRepository)(nil).AddRecord), ctx, record)
+}
+
+// FindByUid mocks base method.
+func (m *MockHistoryRecordRepository) FindByUid(ctx context.Context, uid int64) ([]domain.HistoryRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUid", ctx, uid)
+	ret0, _ := ret[0].([]domain.HistoryRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUid indicates an expected call of FindByUid.
+func (mr *MockHistoryRecordRepositoryMockRecorder) FindByUid(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUid", reflect.TypeOf((*MockHistoryRecordRepository)(nil).FindByUid), ctx, uid)
+}
@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./identity.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./identity.go -package=repomocks -destination=./mocks/identity.mock.go IdentityRepository
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "ddd_demo/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIdentityRepository is a mock of IdentityRepository interface.
+type MockIdentityRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityRepositoryMockRecorder
+}
+
+// MockIdentityRepositoryMockRecorder is the mock recorder for MockIdentityRepository.
+type MockIdentityRepositoryMockRecorder struct {
+	mock *MockIdentityRepository
+}
+
+// NewMockIdentityRepository creates a new mock instance.
+func NewMockIdentityRepository(ctrl *gomock.Controller) *MockIdentityRepository {
+	mock := &MockIdentityRepository{ctrl: ctrl}
+	mock.recorder = &MockIdentityRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentityRepository) EXPECT() *MockIdentityRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockIdentityRepository) Create(ctx context.Context, identity domain.OAuth2Identity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockIdentityRepositoryMockRecorder) Create(ctx, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIdentityRepository)(nil).Create), ctx, identity)
+}
+
+// CreateWithUser mocks base method.
+func (m *MockIdentityRepository) CreateWithUser(ctx context.Context, u domain.User, identity domain.OAuth2Identity) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWithUser", ctx, u, identity)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWithUser indicates an expected call of CreateWithUser.
+func (mr *MockIdentityRepositoryMockRecorder) CreateWithUser(ctx, u, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWithUser", reflect.TypeOf((*MockIdentityRepository)(nil).CreateWithUser), ctx, u, identity)
+}
+
+// Delete mocks base method.
+func (m *MockIdentityRepository) Delete(ctx context.Context, uid int64, provider string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, uid, provider)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockIdentityRepositoryMockRecorder) Delete(ctx, uid, provider any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIdentityRepository)(nil).Delete), ctx, uid, provider)
+}
+
+// FindBySubject mocks base method.
+func (m *MockIdentityRepository) FindBySubject(ctx context.Context, provider, subject string) (domain.OAuth2Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBySubject", ctx, provider, subject)
+	ret0, _ := ret[0].(domain.OAuth2Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBySubject indicates an expected call of FindBySubject.
+func (mr *MockIdentityRepositoryMockRecorder) FindBySubject(ctx, provider, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBySubject", reflect.TypeOf((*MockIdentityRepository)(nil).FindBySubject), ctx, provider, subject)
+}
+
+// FindByUid mocks base method.
+func (m *MockIdentityRepository) FindByUid(ctx context.Context, uid int64) ([]domain.OAuth2Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUid", ctx, uid)
+	ret0, _ := ret[0].([]domain.OAuth2Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUid indicates an expected call of FindByUid.
+func (mr *MockIdentityRepositoryMockRecorder) FindByUid(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUid", reflect.TypeOf((*MockIdentityRepository)(nil).FindByUid), ctx, uid)
+}
@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./login_attempt.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./login_attempt.go -package=repomocks -destination=./mocks/login_attempt.mock.go LoginAttemptRepository
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLoginAttemptRepository is a mock of LoginAttemptRepository interface.
+type MockLoginAttemptRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginAttemptRepositoryMockRecorder
+}
+
+// MockLoginAttemptRepositoryMockRecorder is the mock recorder for MockLoginAttemptRepository.
+type MockLoginAttemptRepositoryMockRecorder struct {
+	mock *MockLoginAttemptRepository
+}
+
+// NewMockLoginAttemptRepository creates a new mock instance.
+func NewMockLoginAttemptRepository(ctrl *gomock.Controller) *MockLoginAttemptRepository {
+	mock := &MockLoginAttemptRepository{ctrl: ctrl}
+	mock.recorder = &MockLoginAttemptRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginAttemptRepository) EXPECT() *MockLoginAttemptRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockLoginAttemptRepository) Check(ctx context.Context, email string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, email)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockLoginAttemptRepositoryMockRecorder) Check(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockLoginAttemptRepository)(nil).Check), ctx, email)
+}
+
+// CheckIP mocks base method.
+func (m *MockLoginAttemptRepository) CheckIP(ctx context.Context, ip string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckIP", ctx, ip)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckIP indicates an expected call of CheckIP.
+func (mr *MockLoginAttemptRepositoryMockRecorder) CheckIP(ctx, ip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIP", reflect.TypeOf((*MockLoginAttemptRepository)(nil).CheckIP), ctx, ip)
+}
+
+// Fail mocks base method.
+func (m *MockLoginAttemptRepository) Fail(ctx context.Context, email string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fail", ctx, email)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fail indicates an expected call of Fail.
+func (mr *MockLoginAttemptRepositoryMockRecorder) Fail(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fail", reflect.TypeOf((*MockLoginAttemptRepository)(nil).Fail), ctx, email)
+}
+
+// FailIP mocks base method.
+func (m *MockLoginAttemptRepository) FailIP(ctx context.Context, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailIP", ctx, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailIP indicates an expected call of FailIP.
+func (mr *MockLoginAttemptRepositoryMockRecorder) FailIP(ctx, ip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailIP", reflect.TypeOf((*MockLoginAttemptRepository)(nil).FailIP), ctx, ip)
+}
+
+// Reset mocks base method.
+func (m *MockLoginAttemptRepository) Reset(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockLoginAttemptRepositoryMockRecorder) Reset(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockLoginAttemptRepository)(nil).Reset), ctx, email)
+}
@@ -0,0 +1,157 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./series.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./series.go -package=repomocks -destination=./mocks/series.mock.go SeriesRepository
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "ddd_demo/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSeriesRepository is a mock of SeriesRepository interface.
+type MockSeriesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSeriesRepositoryMockRecorder
+}
+
+// MockSeriesRepositoryMockRecorder is the mock recorder for MockSeriesRepository.
+type MockSeriesRepositoryMockRecorder struct {
+	mock *MockSeriesRepository
+}
+
+// NewMockSeriesRepository creates a new mock instance.
+func NewMockSeriesRepository(ctrl *gomock.Controller) *MockSeriesRepository {
+	mock := &MockSeriesRepository{ctrl: ctrl}
+	mock.recorder = &MockSeriesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSeriesRepository) EXPECT() *MockSeriesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSeriesRepository) Create(ctx context.Context, s domain.Series) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, s)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSeriesRepositoryMockRecorder) Create(ctx, s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSeriesRepository)(nil).Create), ctx, s)
+}
+
+// Delete mocks base method.
+func (m *MockSeriesRepository) Delete(ctx context.Context, uid, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, uid, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSeriesRepositoryMockRecorder) Delete(ctx, uid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSeriesRepository)(nil).Delete), ctx, uid, id)
+}
+
+// FindArticleIds mocks base method.
+func (m *MockSeriesRepository) FindArticleIds(ctx context.Context, id int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindArticleIds", ctx, id)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindArticleIds indicates an expected call of FindArticleIds.
+func (mr *MockSeriesRepositoryMockRecorder) FindArticleIds(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindArticleIds", reflect.TypeOf((*MockSeriesRepository)(nil).FindArticleIds), ctx, id)
+}
+
+// FindByAuthor mocks base method.
+func (m *MockSeriesRepository) FindByAuthor(ctx context.Context, uid int64) ([]domain.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByAuthor", ctx, uid)
+	ret0, _ := ret[0].([]domain.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByAuthor indicates an expected call of FindByAuthor.
+func (mr *MockSeriesRepositoryMockRecorder) FindByAuthor(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByAuthor", reflect.TypeOf((*MockSeriesRepository)(nil).FindByAuthor), ctx, uid)
+}
+
+// FindById mocks base method.
+func (m *MockSeriesRepository) FindById(ctx context.Context, id int64) (domain.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, id)
+	ret0, _ := ret[0].(domain.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockSeriesRepositoryMockRecorder) FindById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockSeriesRepository)(nil).FindById), ctx, id)
+}
+
+// FindIdByArticle mocks base method.
+func (m *MockSeriesRepository) FindIdByArticle(ctx context.Context, aid int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindIdByArticle", ctx, aid)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindIdByArticle indicates an expected call of FindIdByArticle.
+func (mr *MockSeriesRepositoryMockRecorder) FindIdByArticle(ctx, aid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIdByArticle", reflect.TypeOf((*MockSeriesRepository)(nil).FindIdByArticle), ctx, aid)
+}
+
+// ReplaceArticles mocks base method.
+func (m *MockSeriesRepository) ReplaceArticles(ctx context.Context, uid, id int64, aids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceArticles", ctx, uid, id, aids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceArticles indicates an expected call of ReplaceArticles.
+func (mr *MockSeriesRepositoryMockRecorder) ReplaceArticles(ctx, uid, id, aids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceArticles", reflect.TypeOf((*MockSeriesRepository)(nil).ReplaceArticles), ctx, uid, id, aids)
+}
+
+// Update mocks base method.
+func (m *MockSeriesRepository) Update(ctx context.Context, s domain.Series) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockSeriesRepositoryMockRecorder) Update(ctx, s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSeriesRepository)(nil).Update), ctx, s)
+}
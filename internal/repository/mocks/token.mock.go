@@ -0,0 +1,69 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./token.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./token.go -package=repomocks -destination=./mocks/token.mock.go OneTimeTokenRepository
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOneTimeTokenRepository is a mock of OneTimeTokenRepository interface.
+type MockOneTimeTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOneTimeTokenRepositoryMockRecorder
+}
+
+// MockOneTimeTokenRepositoryMockRecorder is the mock recorder for MockOneTimeTokenRepository.
+type MockOneTimeTokenRepositoryMockRecorder struct {
+	mock *MockOneTimeTokenRepository
+}
+
+// NewMockOneTimeTokenRepository creates a new mock instance.
+func NewMockOneTimeTokenRepository(ctrl *gomock.Controller) *MockOneTimeTokenRepository {
+	mock := &MockOneTimeTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockOneTimeTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOneTimeTokenRepository) EXPECT() *MockOneTimeTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockOneTimeTokenRepository) Consume(ctx context.Context, biz, token string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, biz, token)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockOneTimeTokenRepositoryMockRecorder) Consume(ctx, biz, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockOneTimeTokenRepository)(nil).Consume), ctx, biz, token)
+}
+
+// Set mocks base method.
+func (m *MockOneTimeTokenRepository) Set(ctx context.Context, biz, subject, token, val string, expiration time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, biz, subject, token, val, expiration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockOneTimeTokenRepositoryMockRecorder) Set(ctx, biz, subject, token, val, expiration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockOneTimeTokenRepository)(nil).Set), ctx, biz, subject, token, val, expiration)
+}
@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./totp.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./totp.go -package=repomocks -destination=./mocks/totp.mock.go TOTPRepository
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "ddd_demo/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTOTPRepository is a mock of TOTPRepository interface.
+type MockTOTPRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTOTPRepositoryMockRecorder
+}
+
+// MockTOTPRepositoryMockRecorder is the mock recorder for MockTOTPRepository.
+type MockTOTPRepositoryMockRecorder struct {
+	mock *MockTOTPRepository
+}
+
+// NewMockTOTPRepository creates a new mock instance.
+func NewMockTOTPRepository(ctrl *gomock.Controller) *MockTOTPRepository {
+	mock := &MockTOTPRepository{ctrl: ctrl}
+	mock.recorder = &MockTOTPRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTOTPRepository) EXPECT() *MockTOTPRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockTOTPRepository) Delete(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTOTPRepositoryMockRecorder) Delete(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTOTPRepository)(nil).Delete), ctx, uid)
+}
+
+// Enable mocks base method.
+func (m *MockTOTPRepository) Enable(ctx context.Context, uid, counter int64, recoveryCodes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enable", ctx, uid, counter, recoveryCodes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enable indicates an expected call of Enable.
+func (mr *MockTOTPRepositoryMockRecorder) Enable(ctx, uid, counter, recoveryCodes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enable", reflect.TypeOf((*MockTOTPRepository)(nil).Enable), ctx, uid, counter, recoveryCodes)
+}
+
+// FindByUid mocks base method.
+func (m *MockTOTPRepository) FindByUid(ctx context.Context, uid int64) (domain.TOTP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUid", ctx, uid)
+	ret0, _ := ret[0].(domain.TOTP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUid indicates an expected call of FindByUid.
+func (mr *MockTOTPRepositoryMockRecorder) FindByUid(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUid", reflect.TypeOf((*MockTOTPRepository)(nil).FindByUid), ctx, uid)
+}
+
+// Save mocks base method.
+func (m *MockTOTPRepository) Save(ctx context.Context, t domain.TOTP) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockTOTPRepositoryMockRecorder) Save(ctx, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockTOTPRepository)(nil).Save), ctx, t)
+}
+
+// UpdateLastCounter mocks base method.
+func (m *MockTOTPRepository) UpdateLastCounter(ctx context.Context, uid, counter int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastCounter", ctx, uid, counter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLastCounter indicates an expected call of UpdateLastCounter.
+func (mr *MockTOTPRepositoryMockRecorder) UpdateLastCounter(ctx, uid, counter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastCounter", reflect.TypeOf((*MockTOTPRepository)(nil).UpdateLastCounter), ctx, uid, counter)
+}
+
+// UpdateRecoveryCodes mocks base method.
+func (m *MockTOTPRepository) UpdateRecoveryCodes(ctx context.Context, uid int64, old, codes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRecoveryCodes", ctx, uid, old, codes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRecoveryCodes indicates an expected call of UpdateRecoveryCodes.
+func (mr *MockTOTPRepositoryMockRecorder) UpdateRecoveryCodes(ctx, uid, old, codes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRecoveryCodes", reflect.TypeOf((*MockTOTPRepository)(nil).UpdateRecoveryCodes), ctx, uid, old, codes)
+}
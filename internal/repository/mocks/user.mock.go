@@ -3,7 +3,7 @@
 //
 // Generated by this command:
 //
-//	mockgen -source=./user.go -package=repomocks -destination=./mocks/user.mock.go UserRepository
+//	mockgen -source=./user.go -package=repomocks -destination=./mocks/user.mock.go
 //
 // Package repomocks is a generated GoMock package.
 package repomocks
@@ -39,6 +39,20 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 	return m.recorder
 }
 
+// Anonymize mocks base method.
+func (m *MockUserRepository) Anonymize(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Anonymize", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Anonymize indicates an expected call of Anonymize.
+func (mr *MockUserRepositoryMockRecorder) Anonymize(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Anonymize", reflect.TypeOf((*MockUserRepository)(nil).Anonymize), ctx, uid)
+}
+
 // Create mocks base method.
 func (m *MockUserRepository) Create(ctx context.Context, u domain.User) error {
 	m.ctrl.T.Helper()
@@ -113,6 +127,48 @@ func (mr *MockUserRepositoryMockRecorder) FindByWechat(ctx, openId any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByWechat", reflect.TypeOf((*MockUserRepository)(nil).FindByWechat), ctx, openId)
 }
 
+// Merge mocks base method.
+func (m *MockUserRepository) Merge(ctx context.Context, fromUid, toUid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, fromUid, toUid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockUserRepositoryMockRecorder) Merge(ctx, fromUid, toUid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockUserRepository)(nil).Merge), ctx, fromUid, toUid)
+}
+
+// UpdateEmail mocks base method.
+func (m *MockUserRepository) UpdateEmail(ctx context.Context, uid int64, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEmail", ctx, uid, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEmail indicates an expected call of UpdateEmail.
+func (mr *MockUserRepositoryMockRecorder) UpdateEmail(ctx, uid, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEmail", reflect.TypeOf((*MockUserRepository)(nil).UpdateEmail), ctx, uid, email)
+}
+
+// UpdateEmailVerified mocks base method.
+func (m *MockUserRepository) UpdateEmailVerified(ctx context.Context, uid int64, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEmailVerified", ctx, uid, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEmailVerified indicates an expected call of UpdateEmailVerified.
+func (mr *MockUserRepositoryMockRecorder) UpdateEmailVerified(ctx, uid, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEmailVerified", reflect.TypeOf((*MockUserRepository)(nil).UpdateEmailVerified), ctx, uid, email)
+}
+
 // UpdateNonZeroFields mocks base method.
 func (m *MockUserRepository) UpdateNonZeroFields(ctx context.Context, user domain.User) error {
 	m.ctrl.T.Helper()
@@ -126,3 +182,45 @@ func (mr *MockUserRepositoryMockRecorder) UpdateNonZeroFields(ctx, user any) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNonZeroFields", reflect.TypeOf((*MockUserRepository)(nil).UpdateNonZeroFields), ctx, user)
 }
+
+// UpdatePassword mocks base method.
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, uid int64, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, uid, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockUserRepositoryMockRecorder) UpdatePassword(ctx, uid, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockUserRepository)(nil).UpdatePassword), ctx, uid, password)
+}
+
+// UpdatePhone mocks base method.
+func (m *MockUserRepository) UpdatePhone(ctx context.Context, uid int64, phone string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePhone", ctx, uid, phone)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePhone indicates an expected call of UpdatePhone.
+func (mr *MockUserRepositoryMockRecorder) UpdatePhone(ctx, uid, phone any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePhone", reflect.TypeOf((*MockUserRepository)(nil).UpdatePhone), ctx, uid, phone)
+}
+
+// UpdateWechat mocks base method.
+func (m *MockUserRepository) UpdateWechat(ctx context.Context, uid int64, info domain.WechatInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWechat", ctx, uid, info)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWechat indicates an expected call of UpdateWechat.
+func (mr *MockUserRepositoryMockRecorder) UpdateWechat(ctx, uid, info any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWechat", reflect.TypeOf((*MockUserRepository)(nil).UpdateWechat), ctx, uid, info)
+}
@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./user_export.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./user_export.go -package=repomocks -destination=./mocks/user_export.mock.go UserExportRepository
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+	time "time"
+
+	domain "ddd_demo/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserExportRepository is a mock of UserExportRepository interface.
+type MockUserExportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserExportRepositoryMockRecorder
+}
+
+// MockUserExportRepositoryMockRecorder is the mock recorder for MockUserExportRepository.
+type MockUserExportRepositoryMockRecorder struct {
+	mock *MockUserExportRepository
+}
+
+// NewMockUserExportRepository creates a new mock instance.
+func NewMockUserExportRepository(ctrl *gomock.Controller) *MockUserExportRepository {
+	mock := &MockUserExportRepository{ctrl: ctrl}
+	mock.recorder = &MockUserExportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserExportRepository) EXPECT() *MockUserExportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteFile mocks base method.
+func (m *MockUserExportRepository) DeleteFile(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFile", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFile indicates an expected call of DeleteFile.
+func (mr *MockUserExportRepositoryMockRecorder) DeleteFile(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFile", reflect.TypeOf((*MockUserExportRepository)(nil).DeleteFile), ctx, key)
+}
+
+// Get mocks base method.
+func (m *MockUserExportRepository) Get(ctx context.Context, uid int64) (domain.UserExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, uid)
+	ret0, _ := ret[0].(domain.UserExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockUserExportRepositoryMockRecorder) Get(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUserExportRepository)(nil).Get), ctx, uid)
+}
+
+// OpenFile mocks base method.
+func (m *MockUserExportRepository) OpenFile(ctx context.Context, key string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenFile", ctx, key)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenFile indicates an expected call of OpenFile.
+func (mr *MockUserExportRepositoryMockRecorder) OpenFile(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenFile", reflect.TypeOf((*MockUserExportRepository)(nil).OpenFile), ctx, key)
+}
+
+// Save mocks base method.
+func (m *MockUserExportRepository) Save(ctx context.Context, exp domain.UserExport, expiration time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, exp, expiration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockUserExportRepositoryMockRecorder) Save(ctx, exp, expiration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockUserExportRepository)(nil).Save), ctx, exp, expiration)
+}
+
+// SaveFile mocks base method.
+func (m *MockUserExportRepository) SaveFile(ctx context.Context, key string, body io.ReadSeeker) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveFile", ctx, key, body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveFile indicates an expected call of SaveFile.
+func (mr *MockUserExportRepositoryMockRecorder) SaveFile(ctx, key, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFile", reflect.TypeOf((*MockUserExportRepository)(nil).SaveFile), ctx, key, body)
+}
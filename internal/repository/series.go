@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository/dao"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+)
+
+var (
+	ErrSeriesNotFound        = dao.ErrRecordNotFound
+	ErrSeriesArticleConflict = dao.ErrSeriesArticleConflict
+)
+
+//go:generate mockgen -source=./series.go -package=repomocks -destination=./mocks/series.mock.go SeriesRepository
+type SeriesRepository interface {
+	Create(ctx context.Context, s domain.Series) (int64, error)
+	// Update 只改标题和简介，ID 或者创作者不对返回 ErrSeriesNotFound
+	Update(ctx context.Context, s domain.Series) error
+	Delete(ctx context.Context, uid int64, id int64) error
+	// FindById 不带目录
+	FindById(ctx context.Context, id int64) (domain.Series, error)
+	FindByAuthor(ctx context.Context, uid int64) ([]domain.Series, error)
+	// ReplaceArticles 整个替换专栏的目录，有文章已经在别的专栏里面了返回 ErrSeriesArticleConflict
+	ReplaceArticles(ctx context.Context, uid int64, id int64, aids []int64) error
+	// FindArticleIds 专栏里面的文章，按照目录顺序
+	FindArticleIds(ctx context.Context, id int64) ([]int64, error)
+	// FindIdByArticle 文章所在的专栏，不在任何专栏里面返回 ErrSeriesNotFound
+	FindIdByArticle(ctx context.Context, aid int64) (int64, error)
+}
+
+type seriesRepository struct {
+	dao dao.SeriesDAO
+}
+
+func NewSeriesRepository(dao dao.SeriesDAO) SeriesRepository {
+	return &seriesRepository{dao: dao}
+}
+
+func (repo *seriesRepository) Create(ctx context.Context, s domain.Series) (int64, error) {
+	return repo.dao.Insert(ctx, repo.toEntity(s))
+}
+
+func (repo *seriesRepository) Update(ctx context.Context, s domain.Series) error {
+	return repo.dao.UpdateById(ctx, repo.toEntity(s))
+}
+
+func (repo *seriesRepository) Delete(ctx context.Context, uid int64, id int64) error {
+	return repo.dao.Delete(ctx, uid, id)
+}
+
+func (repo *seriesRepository) FindById(ctx context.Context, id int64) (domain.Series, error) {
+	s, err := repo.dao.FindById(ctx, id)
+	if err != nil {
+		return domain.Series{}, err
+	}
+	return repo.toDomain(s), nil
+}
+
+func (repo *seriesRepository) FindByAuthor(ctx context.Context, uid int64) ([]domain.Series, error) {
+	ss, err := repo.dao.FindByAuthor(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(ss, func(idx int, src dao.Series) domain.Series {
+		return repo.toDomain(src)
+	}), nil
+}
+
+func (repo *seriesRepository) ReplaceArticles(ctx context.Context, uid int64, id int64, aids []int64) error {
+	return repo.dao.ReplaceArticles(ctx, uid, id, aids)
+}
+
+func (repo *seriesRepository) FindArticleIds(ctx context.Context, id int64) ([]int64, error) {
+	return repo.dao.FindArticleIds(ctx, id)
+}
+
+func (repo *seriesRepository) FindIdByArticle(ctx context.Context, aid int64) (int64, error) {
+	sa, err := repo.dao.FindByArticleId(ctx, aid)
+	if err != nil {
+		return 0, err
+	}
+	return sa.SeriesId, nil
+}
+
+func (repo *seriesRepository) toEntity(s domain.Series) dao.Series {
+	return dao.Series{
+		Id:          s.Id,
+		AuthorId:    s.Author.Id,
+		Title:       s.Title,
+		Description: s.Description,
+	}
+}
+
+func (repo *seriesRepository) toDomain(s dao.Series) domain.Series {
+	return domain.Series{
+		Id:          s.Id,
+		Author:      domain.Author{Id: s.AuthorId},
+		Title:       s.Title,
+		Description: s.Description,
+		Ctime:       time.UnixMilli(s.Ctime),
+		Utime:       time.UnixMilli(s.Utime),
+	}
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"ddd_demo/internal/repository/cache"
+	"time"
+)
+
+var (
+	ErrTokenSendTooMany = cache.ErrTokenSendTooMany
+	ErrTokenNotFound    = cache.ErrTokenNotFound
+)
+
+//go:generate mockgen -source=./token.go -package=repomocks -destination=./mocks/token.mock.go OneTimeTokenRepository
+type OneTimeTokenRepository interface {
+	Set(ctx context.Context, biz, subject, token, val string, expiration time.Duration) error
+	Consume(ctx context.Context, biz, token string) (string, error)
+}
+
+type CachedOneTimeTokenRepository struct {
+	cache cache.OneTimeTokenCache
+}
+
+func NewOneTimeTokenRepository(c cache.OneTimeTokenCache) OneTimeTokenRepository {
+	return &CachedOneTimeTokenRepository{
+		cache: c,
+	}
+}
+
+func (repo *CachedOneTimeTokenRepository) Set(ctx context.Context,
+	biz, subject, token, val string, expiration time.Duration) error {
+	return repo.cache.Set(ctx, biz, subject, token, val, expiration)
+}
+
+func (repo *CachedOneTimeTokenRepository) Consume(ctx context.Context, biz, token string) (string, error) {
+	return repo.cache.Consume(ctx, biz, token)
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository/dao"
+	"encoding/json"
+)
+
+//go:generate mockgen -source=./totp.go -package=repomocks -destination=./mocks/totp.mock.go TOTPRepository
+type TOTPRepository interface {
+	Save(ctx context.Context, t domain.TOTP) error
+	// FindByUid 没有绑定的话返回 ErrUserNotFound
+	FindByUid(ctx context.Context, uid int64) (domain.TOTP, error)
+	Enable(ctx context.Context, uid int64, counter int64, recoveryCodes []string) error
+	// UpdateLastCounter 验证码已经用过了的话返回 ErrUserNotFound
+	UpdateLastCounter(ctx context.Context, uid int64, counter int64) error
+	// UpdateRecoveryCodes 并发使用恢复码的话，只有一个会成功，其它的返回 ErrUserNotFound
+	UpdateRecoveryCodes(ctx context.Context, uid int64, old []string, codes []string) error
+	Delete(ctx context.Context, uid int64) error
+}
+
+type TOTPRepositoryImpl struct {
+	dao dao.TOTPDAO
+}
+
+func NewTOTPRepository(dao dao.TOTPDAO) TOTPRepository {
+	return &TOTPRepositoryImpl{dao: dao}
+}
+
+func (repo *TOTPRepositoryImpl) Save(ctx context.Context, t domain.TOTP) error {
+	return repo.dao.Upsert(ctx, dao.UserTOTP{
+		Uid:    t.Uid,
+		Secret: t.Secret,
+	})
+}
+
+func (repo *TOTPRepositoryImpl) FindByUid(ctx context.Context, uid int64) (domain.TOTP, error) {
+	t, err := repo.dao.FindByUid(ctx, uid)
+	if err != nil {
+		return domain.TOTP{}, err
+	}
+	var codes []string
+	if t.RecoveryCodes != "" {
+		err = json.Unmarshal([]byte(t.RecoveryCodes), &codes)
+		if err != nil {
+			return domain.TOTP{}, err
+		}
+	}
+	return domain.TOTP{
+		Uid:           t.Uid,
+		Secret:        t.Secret,
+		Enabled:       t.Enabled,
+		LastCounter:   t.LastCounter,
+		RecoveryCodes: codes,
+	}, nil
+}
+
+func (repo *TOTPRepositoryImpl) Enable(ctx context.Context, uid int64, counter int64, recoveryCodes []string) error {
+	val, err := json.Marshal(recoveryCodes)
+	if err != nil {
+		return err
+	}
+	return repo.dao.Enable(ctx, uid, counter, string(val))
+}
+
+func (repo *TOTPRepositoryImpl) UpdateLastCounter(ctx context.Context, uid int64, counter int64) error {
+	return repo.dao.UpdateLastCounter(ctx, uid, counter)
+}
+
+func (repo *TOTPRepositoryImpl) UpdateRecoveryCodes(ctx context.Context, uid int64, old []string, codes []string) error {
+	oldVal, err := json.Marshal(old)
+	if err != nil {
+		return err
+	}
+	val, err := json.Marshal(codes)
+	if err != nil {
+		return err
+	}
+	return repo.dao.UpdateRecoveryCodes(ctx, uid, string(oldVal), string(val))
+}
+
+func (repo *TOTPRepositoryImpl) Delete(ctx context.Context, uid int64) error {
+	return repo.dao.Delete(ctx, uid)
+}
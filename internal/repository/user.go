@@ -23,6 +23,17 @@ type UserRepository interface {
 	FindByPhone(ctx context.Context, phone string) (domain.User, error)
 	FindById(ctx context.Context, uid int64) (domain.User, error)
 	FindByWechat(ctx context.Context, openId string) (domain.User, error)
+	UpdatePassword(ctx context.Context, uid int64, password string) error
+	UpdateEmailVerified(ctx context.Context, uid int64, email string) error
+	// UpdatePhone phone 为空就是解绑
+	UpdatePhone(ctx context.Context, uid int64, phone string) error
+	// UpdateEmail email 为空就是解绑
+	UpdateEmail(ctx context.Context, uid int64, email string) error
+	// UpdateWechat OpenId 为空就是解绑
+	UpdateWechat(ctx context.Context, uid int64, info domain.WechatInfo) error
+	Merge(ctx context.Context, fromUid int64, toUid int64) error
+	// Anonymize 注销账号，没有这个用户返回 ErrUserNotFound
+	Anonymize(ctx context.Context, uid int64) error
 }
 
 type CachedUserRepository struct {
@@ -90,14 +101,15 @@ func (repo *CachedUserRepository) FindByEmail(ctx context.Context, email string)
 
 func (repo *CachedUserRepository) toDomain(u dao.User) domain.User {
 	return domain.User{
-		Id:       u.Id,
-		Email:    u.Email.String,
-		Phone:    u.Phone.String,
-		Password: u.Password,
-		AboutMe:  u.AboutMe,
-		Nickname: u.Nickname,
-		Birthday: time.UnixMilli(u.Birthday),
-		Ctime:    time.UnixMilli(u.Ctime),
+		Id:            u.Id,
+		Email:         u.Email.String,
+		EmailVerified: u.EmailVerified,
+		Phone:         u.Phone.String,
+		Password:      u.Password,
+		AboutMe:       u.AboutMe,
+		Nickname:      u.Nickname,
+		Birthday:      time.UnixMilli(u.Birthday),
+		Ctime:         time.UnixMilli(u.Ctime),
 		WechatInfo: domain.WechatInfo{
 			OpenId:  u.WechatOpenId.String,
 			UnionId: u.WechatUnionId.String,
@@ -145,6 +157,78 @@ func (repo *CachedUserRepository) UpdateNonZeroFields(ctx context.Context,
 	return repo.cache.Del(ctx, user.Id)
 }
 
+func (repo *CachedUserRepository) UpdatePassword(ctx context.Context, uid int64, password string) error {
+	err := repo.dao.UpdatePassword(ctx, uid, password)
+	if err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, uid)
+}
+
+func (repo *CachedUserRepository) UpdateEmailVerified(ctx context.Context, uid int64, email string) error {
+	err := repo.dao.UpdateEmailVerified(ctx, uid, email)
+	if err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, uid)
+}
+
+func (repo *CachedUserRepository) UpdatePhone(ctx context.Context, uid int64, phone string) error {
+	err := repo.dao.UpdatePhone(ctx, uid, sql.NullString{
+		String: phone,
+		Valid:  phone != "",
+	})
+	if err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, uid)
+}
+
+func (repo *CachedUserRepository) UpdateEmail(ctx context.Context, uid int64, email string) error {
+	err := repo.dao.UpdateEmail(ctx, uid, sql.NullString{
+		String: email,
+		Valid:  email != "",
+	})
+	if err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, uid)
+}
+
+func (repo *CachedUserRepository) UpdateWechat(ctx context.Context, uid int64, info domain.WechatInfo) error {
+	err := repo.dao.UpdateWechat(ctx, uid, sql.NullString{
+		String: info.OpenId,
+		Valid:  info.OpenId != "",
+	}, sql.NullString{
+		String: info.UnionId,
+		Valid:  info.UnionId != "",
+	})
+	if err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, uid)
+}
+
+func (repo *CachedUserRepository) Merge(ctx context.Context, fromUid int64, toUid int64) error {
+	err := repo.dao.Merge(ctx, fromUid, toUid)
+	if err != nil {
+		return err
+	}
+	err = repo.cache.Del(ctx, fromUid)
+	if err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, toUid)
+}
+
+func (repo *CachedUserRepository) Anonymize(ctx context.Context, uid int64) error {
+	err := repo.dao.Anonymize(ctx, uid)
+	if err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, uid)
+}
+
 func (repo *CachedUserRepository) FindById(ctx context.Context, uid int64) (domain.User, error) {
 	//if ctx.Value("x-stress") != true {
 	//	du, err := repo.cache.Get(ctx, uid)
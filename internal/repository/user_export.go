@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository/cache"
+	"ddd_demo/internal/repository/dao"
+	"io"
+	"time"
+)
+
+var ErrExportFileNotFound = dao.ErrFileNotFound
+
+//go:generate mockgen -source=./user_export.go -package=repomocks -destination=./mocks/user_export.mock.go UserExportRepository
+type UserExportRepository interface {
+	// Get 没有导出过，或者已经过期了，返回 Status 为 UserExportStatusUnknown
+	Get(ctx context.Context, uid int64) (domain.UserExport, error)
+	Save(ctx context.Context, exp domain.UserExport, expiration time.Duration) error
+	// SaveFile 保存打包好的文件
+	SaveFile(ctx context.Context, key string, body io.ReadSeeker) error
+	// OpenFile 文件不存在返回 ErrExportFileNotFound
+	OpenFile(ctx context.Context, key string) (io.ReadCloser, error)
+	DeleteFile(ctx context.Context, key string) error
+}
+
+// UserExportRepositoryImpl 导出任务的状态只是临时数据，所以只放在 Redis 里面，
+// 文件放在所有节点都能访问的存储上
+type UserExportRepositoryImpl struct {
+	cache cache.UserExportCache
+	files dao.UserExportFileDAO
+}
+
+func NewUserExportRepository(cache cache.UserExportCache,
+	files dao.UserExportFileDAO) UserExportRepository {
+	return &UserExportRepositoryImpl{cache: cache, files: files}
+}
+
+func (repo *UserExportRepositoryImpl) Get(ctx context.Context, uid int64) (domain.UserExport, error) {
+	res, err := repo.cache.Get(ctx, uid)
+	if err == cache.ErrKeyNotExist {
+		return domain.UserExport{Uid: uid}, nil
+	}
+	return res, err
+}
+
+func (repo *UserExportRepositoryImpl) Save(ctx context.Context,
+	exp domain.UserExport, expiration time.Duration) error {
+	return repo.cache.Set(ctx, exp, expiration)
+}
+
+func (repo *UserExportRepositoryImpl) SaveFile(ctx context.Context,
+	key string, body io.ReadSeeker) error {
+	return repo.files.Put(ctx, key, body)
+}
+
+func (repo *UserExportRepositoryImpl) OpenFile(ctx context.Context, key string) (io.ReadCloser, error) {
+	return repo.files.Get(ctx, key)
+}
+
+func (repo *UserExportRepositoryImpl) DeleteFile(ctx context.Context, key string) error {
+	return repo.files.Delete(ctx, key)
+}
@@ -6,24 +6,65 @@ import (
 	"ddd_demo/internal/events/article"
 	"ddd_demo/internal/repository"
 	"ddd_demo/pkg/logger"
+	"ddd_demo/pkg/markdownx"
 	"errors"
+	"github.com/ecodeclub/ekit/slice"
 	"time"
 )
 
 //go:generate mockgen -source=./article.go -package=svcmocks -destination=./mocks/article.mock.go ArticleService
 type ArticleService interface {
+	// Save 保存草稿。修改已有的文章的时候 art.Version 是作者编辑的那个版本，
+	// 已经在别的地方保存过了返回 ErrArticleVersionConflict，保存之后版本号加一。
+	// Publish、Schedule 也一样
 	Save(ctx context.Context, art domain.Article) (int64, error)
+	// AutoSave 自动保存，只放在 Redis 里面，由 FlushDrafts 定时刷到数据库。
+	// 版本号的检查和 Save 一样，返回新的版本号
+	AutoSave(ctx context.Context, draft domain.ArticleDraft) (int64, error)
+	// FlushDrafts 把自动保存的草稿刷到数据库，定时任务调用
+	FlushDrafts(ctx context.Context) error
+	// Publish 提交发表，审核通过之后才会真的发表出去，见 ArticleReviewService
 	Publish(ctx context.Context, art domain.Article) (int64, error)
 	Withdraw(ctx context.Context, uid int64, id int64) error
 	GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error)
+	// ListPubByAuthor 作者已经发表的文章，给 viewer 看的，不包含草稿和撤回的文章。
+	// 作者自己能看到全部，关注了作者的人还能看到仅关注者可见的，别人只能看到公开的
+	ListPubByAuthor(ctx context.Context, uid int64, viewer int64, offset int, limit int) ([]domain.Article, error)
 	GetById(ctx context.Context, id int64) (domain.Article, error)
-	GetPubById(ctx context.Context, id, uid int64) (domain.Article, error)
+	// GetPubById uid 是读者，shared 表示读者带着这篇文章有效的分享链接。
+	// 看不了的时候返回 ErrArticleInvisible
+	GetPubById(ctx context.Context, id, uid int64, shared bool) (domain.Article, error)
+	// SetVisibility 修改可见范围，只有作者能改
+	SetVisibility(ctx context.Context, uid int64, id int64, visibility domain.ArticleVisibility) error
+	// CheckShare 生成分享链接之前检查一下，只有作者能分享，而且只有不公开列出、仅自己可见的文章需要分享链接
+	CheckShare(ctx context.Context, uid int64, id int64) error
 	ListPub(ctx context.Context, start time.Time, offset, limit int) ([]domain.Article, error)
+	// GetByAuthorByCursor 作者自己的文章，按照更新时间倒序，cursor 是上一页最后一篇的，第一页传零值
+	GetByAuthorByCursor(ctx context.Context, uid int64, cursor domain.ArticleCursor, limit int) ([]domain.Article, error)
+	// ListPubByCursor start 之前更新过的已经发表的文章，按照更新时间倒序，游标和 GetByAuthorByCursor 一样
+	ListPubByCursor(ctx context.Context, start time.Time, cursor domain.ArticleCursor, limit int) ([]domain.Article, error)
+	// ListRevisions 文章的历史版本，只有作者自己能看
+	ListRevisions(ctx context.Context, uid int64, id int64, offset int, limit int) ([]domain.ArticleRevision, error)
+	// DiffRevisions 比较 from 和 to 两个版本
+	DiffRevisions(ctx context.Context, uid int64, id int64, from int64, to int64) (domain.ArticleRevisionDiff, error)
+	// RestoreRevision 把某个历史版本恢复成当前的草稿，会产生一个新的版本
+	RestoreRevision(ctx context.Context, uid int64, id int64, version int64) error
+	// Schedule 定时发表，art.PublishAt 必须是将来的某个时间
+	Schedule(ctx context.Context, art domain.Article) (int64, error)
+	Reschedule(ctx context.Context, uid int64, id int64, publishAt time.Time) error
+	CancelSchedule(ctx context.Context, uid int64, id int64) error
+	// PublishDue 把所有到点了的定时文章提交审核，定时任务调用
+	PublishDue(ctx context.Context) error
+	// ListPubByTag 标签下面已经发表的文章，cursor 是上一页最后一篇的 ID，第一页传 0
+	ListPubByTag(ctx context.Context, tag string, cursor int64, limit int) ([]domain.Article, error)
+	// ListTags 文章最多的 limit 个标签
+	ListTags(ctx context.Context, limit int) ([]domain.TagCount, error)
 }
 
 type articleService struct {
-	repo     repository.ArticleRepository
-	producer article.Producer
+	repo       repository.ArticleRepository
+	followRepo repository.FollowRepository
+	producer   article.Producer
 
 	// V1 写法专用
 	readerRepo repository.ArticleReaderRepository
@@ -31,8 +72,15 @@ type articleService struct {
 	l          logger.LoggerV1
 }
 
-func (a *articleService) GetPubById(ctx context.Context, id, uid int64) (domain.Article, error) {
+func (a *articleService) GetPubById(ctx context.Context, id, uid int64, shared bool) (domain.Article, error) {
 	res, err := a.repo.GetPubById(ctx, id)
+	if err == nil {
+		// 缓存里面的也是完整的文章，所以每次都要检查
+		err = a.canView(ctx, res, uid, shared)
+		if err != nil {
+			return domain.Article{}, err
+		}
+	}
 	go func() {
 		if err == nil {
 			// 在这里发一个消息
@@ -60,13 +108,87 @@ func (a *articleService) GetByAuthor(ctx context.Context, uid int64, offset int,
 	return a.repo.GetByAuthor(ctx, uid, offset, limit)
 }
 
+func (a *articleService) ListPubByAuthor(ctx context.Context,
+	uid int64, viewer int64, offset int, limit int) ([]domain.Article, error) {
+	vs, err := visibilitiesFor(ctx, a.followRepo, uid, viewer)
+	if err != nil {
+		return nil, err
+	}
+	return a.repo.ListPubByAuthor(ctx, uid, vs, offset, limit)
+}
+
 func (a *articleService) Withdraw(ctx context.Context, uid int64, id int64) error {
-	return a.repo.SyncStatus(ctx, uid, id, domain.ArticleStatusPrivate)
+	err := a.repo.SyncStatus(ctx, uid, id, domain.ArticleStatusPrivate)
+	if err != nil {
+		return err
+	}
+	a.producePublishEvent(id, uid)
+	return nil
 }
 
 func (a *articleService) Publish(ctx context.Context, art domain.Article) (int64, error) {
-	art.Status = domain.ArticleStatusPublished
-	return a.repo.Sync(ctx, art)
+	if !art.Visibility.Valid() {
+		return 0, ErrInvalidVisibility
+	}
+	tags, err := normalizeTags(art.Tags)
+	if err != nil {
+		return 0, err
+	}
+	art.Tags = tags
+	art.Attachments = attachmentKeys(art.Content)
+	// 先保存到制作库，审核通过之后才会同步到线上库
+	art.Status = domain.ArticleStatusReviewing
+	id := art.Id
+	if id > 0 {
+		err = a.repo.Update(ctx, art)
+	} else {
+		id, err = a.repo.Create(ctx, art)
+	}
+	if err != nil {
+		return 0, err
+	}
+	err = a.producer.ProduceReviewEvent(article.ReviewEvent{
+		Aid: id,
+		Uid: art.Author.Id,
+	})
+	if err != nil {
+		// 文章已经保存了，作者重新发表一次就可以
+		return id, err
+	}
+	return id, nil
+}
+
+// render 审核通过的时候把 Markdown 渲染好，和文章一起存到线上库，读的时候就不用再渲染了
+func render(content string) (domain.ArticleRendered, error) {
+	res, err := markdownx.Render(content)
+	if err != nil {
+		return domain.ArticleRendered{}, err
+	}
+	rendered := domain.ArticleRendered{
+		HTML:     res.HTML,
+		Abstract: res.Abstract,
+	}
+	if len(res.TOC) > 0 {
+		rendered.TOC = slice.Map(res.TOC, func(idx int, src markdownx.Heading) domain.ArticleHeading {
+			return domain.ArticleHeading{Level: src.Level, Id: src.Id, Title: src.Title}
+		})
+	}
+	return rendered, nil
+}
+
+// producePublishEvent 线上库已经改好了，消息发不出去不影响这一次操作，只记录日志。
+// 搜索索引会在下一次重建的时候补上
+func (a *articleService) producePublishEvent(aid int64, uid int64) {
+	er := a.producer.ProducePublishEvent(article.PublishEvent{
+		Aid: aid,
+		Uid: uid,
+	})
+	if er != nil {
+		a.l.Error("发送 PublishEvent 失败",
+			logger.Int64("aid", aid),
+			logger.Int64("uid", uid),
+			logger.Error(er))
+	}
 }
 
 func (a *articleService) PublishV1(ctx context.Context, art domain.Article) (int64, error) {
@@ -116,17 +238,29 @@ func NewArticleServiceV1(
 }
 
 func NewArticleService(repo repository.ArticleRepository,
-	producer article.Producer) ArticleService {
+	followRepo repository.FollowRepository,
+	producer article.Producer, l logger.LoggerV1) ArticleService {
 	return &articleService{
-		repo:     repo,
-		producer: producer,
+		repo:       repo,
+		followRepo: followRepo,
+		producer:   producer,
+		l:          l,
 	}
 }
 
 func (a *articleService) Save(ctx context.Context, art domain.Article) (int64, error) {
+	if !art.Visibility.Valid() {
+		return 0, ErrInvalidVisibility
+	}
+	tags, err := normalizeTags(art.Tags)
+	if err != nil {
+		return 0, err
+	}
+	art.Tags = tags
+	art.Attachments = attachmentKeys(art.Content)
 	art.Status = domain.ArticleStatusUnpublished
 	if art.Id > 0 {
-		err := a.repo.Update(ctx, art)
+		err = a.repo.Update(ctx, art)
 		return art.Id, err
 	}
 	return a.repo.Create(ctx, art)
@@ -136,3 +270,13 @@ func (a *articleService) ListPub(ctx context.Context,
 	start time.Time, offset, limit int) ([]domain.Article, error) {
 	return a.repo.ListPub(ctx, start, offset, limit)
 }
+
+func (a *articleService) GetByAuthorByCursor(ctx context.Context,
+	uid int64, cursor domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	return a.repo.GetByAuthorByCursor(ctx, uid, cursor, limit)
+}
+
+func (a *articleService) ListPubByCursor(ctx context.Context,
+	start time.Time, cursor domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	return a.repo.ListPubByCursor(ctx, start, cursor, limit)
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository"
+	"ddd_demo/pkg/logger"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+var (
+	ErrImportRunning = repository.ErrArticleImportRunning
+	// ErrInvalidArticleBundle 导入的文件打不开，里面没有文章，或者文章太多了
+	ErrInvalidArticleBundle = errors.New("导入的文件不对")
+)
+
+const (
+	// MaxArticleBundleSize 导入的文件最大 20MB
+	MaxArticleBundleSize = 20 << 20
+	// maxBundleArticles 一次最多导入多少篇
+	maxBundleArticles = 500
+	// maxBundleFileSize 一篇文章最大 1MB
+	maxBundleFileSize = 1 << 20
+	// maxBundleContentSize 压缩包解压出来最大 50MB
+	maxBundleContentSize = 50 << 20
+)
+
+// ArticleImportConfig 批量导入文章的配置
+type ArticleImportConfig struct {
+	// 导入结果保留多久
+	Expiration time.Duration `yaml:"expiration"`
+	// 导入超时时间，超时之后剩下的文章就不导入了，可以重新导入
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// ArticleBundleService 从别的平台批量导入文章，或者把自己的文章全部导出来。
+// 支持的格式见 domain.ArticleBundleFormat，导出的文件可以直接再导入
+//
+//go:generate mockgen -source=./article_bundle.go -package=svcmocks -destination=./mocks/article_bundle.mock.go ArticleBundleService
+type ArticleBundleService interface {
+	// Import 异步导入，每篇文章都通过 ArticleService.Save 保存成草稿。
+	// 文件本身不对的时候直接返回 ErrInvalidArticleBundle，单篇文章的问题记在导入结果里面。
+	// 正在导入的时候返回 ErrImportRunning
+	Import(ctx context.Context, uid int64, format domain.ArticleBundleFormat, data []byte) error
+	// ImportStatus 最近一次导入的进度和结果
+	ImportStatus(ctx context.Context, uid int64) (domain.ArticleImport, error)
+	// Export 把作者所有的文章写到 w 里面，包括草稿
+	Export(ctx context.Context, uid int64, format domain.ArticleBundleFormat, w io.Writer) error
+}
+
+type articleBundleService struct {
+	artSvc  ArticleService
+	artRepo repository.ArticleRepository
+	repo    repository.ArticleImportRepository
+	cfg     ArticleImportConfig
+	l       logger.LoggerV1
+}
+
+func NewArticleBundleService(artSvc ArticleService,
+	artRepo repository.ArticleRepository,
+	repo repository.ArticleImportRepository,
+	cfg ArticleImportConfig,
+	l logger.LoggerV1) ArticleBundleService {
+	return &articleBundleService{
+		artSvc:  artSvc,
+		artRepo: artRepo,
+		repo:    repo,
+		cfg:     cfg,
+		l:       l,
+	}
+}
+
+func (svc *articleBundleService) Import(ctx context.Context,
+	uid int64, format domain.ArticleBundleFormat, data []byte) error {
+	// 解析很快，同步做，文件本身不对可以直接告诉作者
+	docs, err := decodeBundle(format, data)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	imp := domain.ArticleImport{
+		Uid:    uid,
+		Status: domain.ArticleImportStatusRunning,
+		Total:  len(docs),
+		Ctime:  now,
+		Utime:  now,
+	}
+	// 同时来两个请求也只有一个能占住，超时了就认为上一次导入已经结束了
+	err = svc.repo.Claim(ctx, imp, svc.cfg.Timeout, svc.cfg.Expiration)
+	if err != nil {
+		return err
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), svc.cfg.Timeout)
+		defer cancel()
+		svc.importDocs(ctx, imp, docs)
+	}()
+	return nil
+}
+
+// importDocs 一篇一篇保存，每保存一篇更新一次进度
+func (svc *articleBundleService) importDocs(ctx context.Context,
+	imp domain.ArticleImport, docs []bundleDoc) {
+	imp.Status = domain.ArticleImportStatusDone
+	for _, doc := range docs {
+		if ctx.Err() != nil {
+			imp.Status = domain.ArticleImportStatusFailed
+			break
+		}
+		id, err := svc.importDoc(ctx, imp.Uid, doc)
+		if err != nil {
+			imp.Errors = append(imp.Errors, domain.ArticleImportError{
+				File:   doc.File,
+				Reason: svc.reason(imp.Uid, doc, err),
+			})
+		} else {
+			imp.Ids = append(imp.Ids, id)
+		}
+		imp.Processed++
+		if imp.Processed < imp.Total {
+			imp.Utime = time.Now()
+			svc.saveImport(ctx, imp)
+		}
+	}
+	imp.Utime = time.Now()
+	// 超时了 ctx 已经不能用了，最后的结果一定要保存下来
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	svc.saveImport(ctx, imp)
+	err := svc.repo.Release(ctx, imp)
+	if err != nil {
+		// 释放不了也没关系，超时之后会自动释放
+		svc.l.Error("释放导入失败",
+			logger.Int64("uid", imp.Uid),
+			logger.Error(err))
+	}
+}
+
+func (svc *articleBundleService) importDoc(ctx context.Context, uid int64, doc bundleDoc) (int64, error) {
+	if doc.Err != nil {
+		return 0, doc.Err
+	}
+	art := doc.Art
+	art.Author = domain.Author{Id: uid}
+	return svc.artSvc.Save(ctx, art)
+}
+
+// reason 作者自己能改的问题原样告诉作者，别的都是系统错误
+func (svc *articleBundleService) reason(uid int64, doc bundleDoc, err error) string {
+	switch err {
+	case errInvalidFrontMatter, errInvalidBundleDate, errBundleFileTooLarge,
+		errBrokenBundleFile, errEmptyBundleTitle, ErrInvalidTag, ErrTooManyTags:
+		return err.Error()
+	default:
+		svc.l.Error("导入文章失败",
+			logger.Int64("uid", uid),
+			logger.String("file", doc.File),
+			logger.Error(err))
+		return "系统错误"
+	}
+}
+
+func (svc *articleBundleService) saveImport(ctx context.Context, imp domain.ArticleImport) {
+	err := svc.repo.Save(ctx, imp, svc.cfg.Expiration)
+	if err != nil {
+		svc.l.Error("保存导入进度失败",
+			logger.Int64("uid", imp.Uid),
+			logger.Error(err))
+	}
+}
+
+func (svc *articleBundleService) ImportStatus(ctx context.Context, uid int64) (domain.ArticleImport, error) {
+	return svc.repo.Get(ctx, uid)
+}
+
+func (svc *articleBundleService) Export(ctx context.Context,
+	uid int64, format domain.ArticleBundleFormat, w io.Writer) error {
+	switch format {
+	case domain.ArticleBundleMarkdown:
+		return svc.exportMarkdown(ctx, uid, w)
+	case domain.ArticleBundleJSON:
+		return svc.exportJSON(ctx, uid, w)
+	default:
+		return ErrInvalidArticleBundle
+	}
+}
+
+// exportMarkdown 一篇文章一个文件，文件名是文章的 ID，标题可能有文件名里面不能用的字符
+func (svc *articleBundleService) exportMarkdown(ctx context.Context, uid int64, w io.Writer) error {
+	zw := zip.NewWriter(w)
+	err := svc.eachArticle(ctx, uid, func(art domain.Article) error {
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     fmt.Sprintf("%d.md", art.Id),
+			Method:   zip.Deflate,
+			Modified: art.Utime,
+		})
+		if err != nil {
+			return err
+		}
+		data, err := encodeMarkdown(art)
+		if err != nil {
+			return err
+		}
+		_, err = fw.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// exportJSON 一篇一篇写，不用把所有文章都放在内存里面
+func (svc *articleBundleService) exportJSON(ctx context.Context, uid int64, w io.Writer) error {
+	_, err := io.WriteString(w, `{"articles":[`)
+	if err != nil {
+		return err
+	}
+	first := true
+	err = svc.eachArticle(ctx, uid, func(art domain.Article) error {
+		if !first {
+			_, err := io.WriteString(w, ",")
+			if err != nil {
+				return err
+			}
+		}
+		first = false
+		data, err := json.Marshal(toBundleArticle(art))
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "]}")
+	return err
+}
+
+// eachArticle 按照更新时间倒序，用游标分批查，不走缓存，别的文章不会重复也不会漏掉。
+// 只有导出的过程中作者刚好改了还没导出的那篇，它会跑到游标前面去，这次导出里面就没有它
+func (svc *articleBundleService) eachArticle(ctx context.Context,
+	uid int64, fn func(art domain.Article) error) error {
+	var cursor domain.ArticleCursor
+	for {
+		arts, err := svc.artRepo.ListByAuthor(ctx, uid, cursor, exportBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, art := range arts {
+			err = fn(art)
+			if err != nil {
+				return err
+			}
+		}
+		if len(arts) < exportBatchSize {
+			return nil
+		}
+		cursor = arts[len(arts)-1].Cursor()
+	}
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"ddd_demo/internal/domain"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// 单篇文章导入失败的原因，会原样展示给作者
+var (
+	errInvalidFrontMatter = errors.New("front matter 格式不对")
+	errInvalidBundleDate  = errors.New("date 格式不对，要用 2006-01-02、2006-01-02 15:04:05 或者 RFC3339，而且不能是将来的时间")
+	errBundleFileTooLarge = errors.New("一篇文章不能超过 1MB")
+	errBrokenBundleFile   = errors.New("文件已经损坏")
+	errEmptyBundleTitle   = errors.New("标题不能为空")
+)
+
+// bundleDateLayouts 导入的时候 date 支持的格式，没有时区的按照服务器的时区
+var bundleDateLayouts = []string{time.RFC3339, time.DateTime, time.DateOnly, "2006-01-02T15:04:05"}
+
+// bundleArticle 导入导出的一篇文章。Markdown 格式 Content 以外的字段放在 front matter 里面，
+// Date 是发表时间，导入之后作为文章的创建时间
+type bundleArticle struct {
+	Title   string   `json:"title" yaml:"title"`
+	Tags    []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Date    string   `json:"date,omitempty" yaml:"date,omitempty"`
+	Content string   `json:"content" yaml:"-"`
+}
+
+type bundleJSON struct {
+	Articles []bundleArticle `json:"articles"`
+}
+
+// bundleDoc 从导入的文件里面解析出来的一篇文章，Err 不为 nil 的时候这篇不能导入
+type bundleDoc struct {
+	File string
+	Art  domain.Article
+	Err  error
+}
+
+func toBundleArticle(art domain.Article) bundleArticle {
+	return bundleArticle{
+		Title:   art.Title,
+		Tags:    art.Tags,
+		Date:    art.Ctime.Format(time.RFC3339),
+		Content: art.Content,
+	}
+}
+
+// toArticle 只填标题、内容、标签和创建时间
+func (b bundleArticle) toArticle() (domain.Article, error) {
+	art := domain.Article{
+		Title:   strings.TrimSpace(b.Title),
+		Content: b.Content,
+		Tags:    b.Tags,
+	}
+	if b.Date == "" {
+		return art, nil
+	}
+	for _, layout := range bundleDateLayouts {
+		t, err := time.ParseInLocation(layout, b.Date, time.Local)
+		if err == nil {
+			if t.After(time.Now()) {
+				return domain.Article{}, errInvalidBundleDate
+			}
+			art.Ctime = t
+			return art, nil
+		}
+	}
+	return domain.Article{}, errInvalidBundleDate
+}
+
+// encodeMarkdown front matter 加上正文
+func encodeMarkdown(art domain.Article) ([]byte, error) {
+	fm, err := yaml.Marshal(toBundleArticle(art))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(fm)
+	buf.WriteString("---\n\n")
+	buf.WriteString(art.Content)
+	return buf.Bytes(), nil
+}
+
+// decodeMarkdown 没有 front matter 或者 front matter 里面没有标题的时候，用文件名当标题
+func decodeMarkdown(name string, data []byte) (domain.Article, error) {
+	s := strings.TrimPrefix(string(data), "\ufeff")
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	fm, body, err := splitFrontMatter(s)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	var b bundleArticle
+	err = yaml.Unmarshal([]byte(fm), &b)
+	if err != nil {
+		return domain.Article{}, errInvalidFrontMatter
+	}
+	// encodeMarkdown 在 front matter 后面空了一行
+	b.Content = strings.TrimPrefix(body, "\n")
+	art, err := b.toArticle()
+	if err != nil {
+		return domain.Article{}, err
+	}
+	if art.Title == "" {
+		base := path.Base(name)
+		art.Title = strings.TrimSuffix(base, path.Ext(base))
+	}
+	return art, nil
+}
+
+// splitFrontMatter front matter 是开头两行 --- 之间的 YAML，没有的时候 fm 是空的
+func splitFrontMatter(s string) (fm string, body string, err error) {
+	if !strings.HasPrefix(s, "---\n") {
+		return "", s, nil
+	}
+	rest := s[len("---\n"):]
+	for offset := 0; ; {
+		end := strings.IndexByte(rest[offset:], '\n')
+		line := rest[offset:]
+		if end >= 0 {
+			line = rest[offset : offset+end]
+		}
+		if strings.TrimRight(line, " \t") == "---" {
+			if end < 0 {
+				return rest[:offset], "", nil
+			}
+			return rest[:offset], rest[offset+end+1:], nil
+		}
+		if end < 0 {
+			return "", "", errInvalidFrontMatter
+		}
+		offset += end + 1
+	}
+}
+
+// decodeBundle 整个文件有问题返回 ErrInvalidArticleBundle，单篇文章的问题放在 bundleDoc.Err 里面
+func decodeBundle(format domain.ArticleBundleFormat, data []byte) ([]bundleDoc, error) {
+	var (
+		docs []bundleDoc
+		err  error
+	)
+	switch format {
+	case domain.ArticleBundleMarkdown:
+		docs, err = decodeMarkdownBundle(data)
+	case domain.ArticleBundleJSON:
+		docs, err = decodeJSONBundle(data)
+	default:
+		return nil, ErrInvalidArticleBundle
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 || len(docs) > maxBundleArticles {
+		return nil, ErrInvalidArticleBundle
+	}
+	return docs, nil
+}
+
+// decodeMarkdownBundle 只看 .md 和 .markdown 文件，图片之类的别的文件直接跳过
+func decodeMarkdownBundle(data []byte) ([]bundleDoc, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, ErrInvalidArticleBundle
+	}
+	var (
+		docs  []bundleDoc
+		total int
+	)
+	for _, f := range zr.File {
+		if !isMarkdownFile(f) {
+			continue
+		}
+		if len(docs) >= maxBundleArticles {
+			return nil, ErrInvalidArticleBundle
+		}
+		doc := bundleDoc{File: f.Name}
+		content, er := readZipFile(f)
+		total += len(content)
+		// 解压出来的总大小也要限制住，免得压缩炸弹把内存撑爆
+		if total > maxBundleContentSize {
+			return nil, ErrInvalidArticleBundle
+		}
+		if er == nil {
+			doc.Art, er = decodeMarkdown(f.Name, content)
+		}
+		doc.Err = er
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+func isMarkdownFile(f *zip.File) bool {
+	if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") {
+		return false
+	}
+	base := path.Base(f.Name)
+	if strings.HasPrefix(base, ".") {
+		return false
+	}
+	ext := strings.ToLower(path.Ext(base))
+	return ext == ".md" || ext == ".markdown"
+}
+
+// readZipFile 不相信压缩包里面记录的大小，最多读 maxBundleFileSize + 1 个字节
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, errBrokenBundleFile
+	}
+	defer rc.Close()
+	content, err := io.ReadAll(io.LimitReader(rc, maxBundleFileSize+1))
+	if err != nil {
+		return nil, errBrokenBundleFile
+	}
+	if len(content) > maxBundleFileSize {
+		return nil, errBundleFileTooLarge
+	}
+	return content, nil
+}
+
+func decodeJSONBundle(data []byte) ([]bundleDoc, error) {
+	var b bundleJSON
+	err := json.Unmarshal(data, &b)
+	if err != nil {
+		return nil, ErrInvalidArticleBundle
+	}
+	docs := make([]bundleDoc, 0, len(b.Articles))
+	for i, ba := range b.Articles {
+		doc := bundleDoc{File: fmt.Sprintf("articles[%d]", i)}
+		doc.Art, doc.Err = ba.toArticle()
+		switch {
+		case doc.Err != nil:
+		case len(ba.Content) > maxBundleFileSize:
+			doc.Err = errBundleFileTooLarge
+		case doc.Art.Title == "":
+			doc.Err = errEmptyBundleTitle
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository"
+	repomocks "ddd_demo/internal/repository/mocks"
+	svcmocks "ddd_demo/internal/service/mocks"
+	"ddd_demo/pkg/logger"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func Test_decodeMarkdown(t *testing.T) {
+	testCases := []struct {
+		name string
+		file string
+		data string
+
+		wantArt domain.Article
+		wantErr error
+	}{
+		{
+			name: "完整的 front matter",
+			file: "posts/go.md",
+			data: "---\ntitle: Go 入门\ntags: [go, 入门]\ndate: 2024-01-02\n---\n\n# 安装\n",
+			wantArt: domain.Article{
+				Title:   "Go 入门",
+				Tags:    []string{"go", "入门"},
+				Content: "# 安装\n",
+				Ctime:   time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local),
+			},
+		},
+		{
+			name: "Windows 换行和 BOM",
+			file: "go.md",
+			data: "\ufeff---\r\ntitle: Go 入门\r\ndate: 2024-01-02 15:04:05\r\n---\r\n正文\r\n",
+			wantArt: domain.Article{
+				Title:   "Go 入门",
+				Content: "正文\n",
+				Ctime:   time.Date(2024, 1, 2, 15, 4, 5, 0, time.Local),
+			},
+		},
+		{
+			name: "没有 front matter 用文件名当标题",
+			file: "posts/hello.md",
+			data: "正文",
+			wantArt: domain.Article{
+				Title:   "hello",
+				Content: "正文",
+			},
+		},
+		{
+			name:    "front matter 没有结束",
+			file:    "go.md",
+			data:    "---\ntitle: Go 入门\n正文",
+			wantErr: errInvalidFrontMatter,
+		},
+		{
+			name:    "front matter 不是 YAML",
+			file:    "go.md",
+			data:    "---\ntitle: [Go\n---\n正文",
+			wantErr: errInvalidFrontMatter,
+		},
+		{
+			name:    "日期格式不对",
+			file:    "go.md",
+			data:    "---\ntitle: Go 入门\ndate: 01/02/2024\n---\n正文",
+			wantErr: errInvalidBundleDate,
+		},
+		{
+			name:    "将来的时间",
+			file:    "go.md",
+			data:    "---\ntitle: Go 入门\ndate: 2999-01-02\n---\n正文",
+			wantErr: errInvalidBundleDate,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			art, err := decodeMarkdown(tc.file, []byte(tc.data))
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantArt, art)
+		})
+	}
+}
+
+// Test_articleBundleService_Export 导出来的文件可以原样导入回去
+func Test_articleBundleService_Export(t *testing.T) {
+	arts := []domain.Article{
+		{Id: 2, Title: "第二篇: Go", Tags: []string{"go"},
+			Content: "---\n正文里面也有分隔线", Ctime: time.Unix(2000, 0), Utime: time.Unix(3000, 0)},
+		{Id: 1, Title: "第一篇", Content: "# 标题\n", Ctime: time.Unix(1000, 0), Utime: time.Unix(1000, 0)},
+	}
+	for _, format := range []domain.ArticleBundleFormat{domain.ArticleBundleMarkdown, domain.ArticleBundleJSON} {
+		ctrl := gomock.NewController(t)
+		artRepo := repomocks.NewMockArticleRepository(ctrl)
+		artRepo.EXPECT().ListByAuthor(gomock.Any(), int64(123),
+			domain.ArticleCursor{}, exportBatchSize).Return(arts, nil)
+		svc := NewArticleBundleService(svcmocks.NewMockArticleService(ctrl), artRepo,
+			repomocks.NewMockArticleImportRepository(ctrl), ArticleImportConfig{}, logger.NewNopLogger())
+
+		var buf bytes.Buffer
+		err := svc.Export(context.Background(), 123, format, &buf)
+		require.NoError(t, err)
+		docs, err := decodeBundle(format, buf.Bytes())
+		require.NoError(t, err)
+		require.Len(t, docs, len(arts))
+		for i, doc := range docs {
+			require.NoError(t, doc.Err)
+			assert.Equal(t, arts[i].Title, doc.Art.Title)
+			assert.Equal(t, arts[i].Tags, doc.Art.Tags)
+			assert.Equal(t, arts[i].Content, doc.Art.Content)
+			assert.True(t, arts[i].Ctime.Equal(doc.Art.Ctime))
+		}
+		ctrl.Finish()
+	}
+}
+
+func Test_articleBundleService_Import(t *testing.T) {
+	bundle := zipOf(t, "a.md", "---\ntitle: A\n---\n正文", "images/a.png", "png", "b.md", "正文")
+	testCases := []struct {
+		name   string
+		mock   func(ctrl *gomock.Controller) repository.ArticleImportRepository
+		format domain.ArticleBundleFormat
+		data   []byte
+
+		wantErr error
+	}{
+		{
+			name: "上一次还没有导入完",
+			mock: func(ctrl *gomock.Controller) repository.ArticleImportRepository {
+				repo := repomocks.NewMockArticleImportRepository(ctrl)
+				repo.EXPECT().Claim(gomock.Any(), gomock.Any(), time.Minute, time.Hour).
+					DoAndReturn(func(ctx context.Context, imp domain.ArticleImport,
+						timeout time.Duration, expiration time.Duration) error {
+						assert.Equal(t, int64(123), imp.Uid)
+						assert.Equal(t, domain.ArticleImportStatusRunning, imp.Status)
+						assert.Equal(t, 2, imp.Total)
+						return repository.ErrArticleImportRunning
+					})
+				return repo
+			},
+			format:  domain.ArticleBundleMarkdown,
+			data:    bundle,
+			wantErr: ErrImportRunning,
+		},
+		{
+			name: "不是 zip",
+			mock: func(ctrl *gomock.Controller) repository.ArticleImportRepository {
+				return repomocks.NewMockArticleImportRepository(ctrl)
+			},
+			format:  domain.ArticleBundleMarkdown,
+			data:    []byte("abc"),
+			wantErr: ErrInvalidArticleBundle,
+		},
+		{
+			name: "压缩包里面没有 Markdown 文件",
+			mock: func(ctrl *gomock.Controller) repository.ArticleImportRepository {
+				return repomocks.NewMockArticleImportRepository(ctrl)
+			},
+			format:  domain.ArticleBundleMarkdown,
+			data:    zipOf(t, "a.png", "png"),
+			wantErr: ErrInvalidArticleBundle,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			// 导入本身是异步的，由 importDocs 测
+			svc := NewArticleBundleService(svcmocks.NewMockArticleService(ctrl),
+				repomocks.NewMockArticleRepository(ctrl), tc.mock(ctrl),
+				ArticleImportConfig{Expiration: time.Hour, Timeout: time.Minute},
+				logger.NewNopLogger())
+			err := svc.Import(context.Background(), 123, tc.format, tc.data)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_articleBundleService_importDocs(t *testing.T) {
+	docs := []bundleDoc{
+		{File: "a.md", Art: domain.Article{Title: "A", Ctime: time.Unix(1000, 0)}},
+		{File: "b.md", Err: errInvalidBundleDate},
+		{File: "c.md", Art: domain.Article{Title: "C", Tags: []string{"a/b"}}},
+		{File: "d.md", Art: domain.Article{Title: "D"}},
+	}
+	testCases := []struct {
+		name string
+		ctx  func() context.Context
+
+		// wantSaves 每处理一篇保存一次进度，最后再保存一次结果
+		wantSaves int
+		wantImp   domain.ArticleImport
+	}{
+		{
+			name:      "一篇一篇导入",
+			ctx:       context.Background,
+			wantSaves: 4,
+			wantImp: domain.ArticleImport{
+				Uid:       123,
+				Status:    domain.ArticleImportStatusDone,
+				Total:     4,
+				Processed: 4,
+				Ids:       []int64{1},
+				Errors: []domain.ArticleImportError{
+					{File: "b.md", Reason: errInvalidBundleDate.Error()},
+					{File: "c.md", Reason: ErrInvalidTag.Error()},
+					{File: "d.md", Reason: "系统错误"},
+				},
+			},
+		},
+		{
+			name: "超时了",
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			},
+			wantSaves: 1,
+			wantImp: domain.ArticleImport{
+				Uid:    123,
+				Status: domain.ArticleImportStatusFailed,
+				Total:  4,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			artSvc := svcmocks.NewMockArticleService(ctrl)
+			artSvc.EXPECT().Save(gomock.Any(), domain.Article{Title: "A",
+				Author: domain.Author{Id: 123}, Ctime: time.Unix(1000, 0)}).
+				Return(int64(1), nil).AnyTimes()
+			artSvc.EXPECT().Save(gomock.Any(), domain.Article{Title: "C",
+				Author: domain.Author{Id: 123}, Tags: []string{"a/b"}}).
+				Return(int64(0), ErrInvalidTag).AnyTimes()
+			artSvc.EXPECT().Save(gomock.Any(), domain.Article{Title: "D",
+				Author: domain.Author{Id: 123}}).
+				Return(int64(0), errors.New("mock db 错误")).AnyTimes()
+			repo := repomocks.NewMockArticleImportRepository(ctrl)
+			var saved []domain.ArticleImport
+			repo.EXPECT().Save(gomock.Any(), gomock.Any(), time.Hour).
+				DoAndReturn(func(ctx context.Context, imp domain.ArticleImport, expiration time.Duration) error {
+					saved = append(saved, imp)
+					return nil
+				}).AnyTimes()
+			repo.EXPECT().Release(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, imp domain.ArticleImport) error {
+					// 和 Claim 的时候是同一次导入
+					assert.Equal(t, time.Unix(100, 0), imp.Ctime)
+					return nil
+				})
+			svc := NewArticleBundleService(artSvc, repomocks.NewMockArticleRepository(ctrl), repo,
+				ArticleImportConfig{Expiration: time.Hour, Timeout: time.Minute},
+				logger.NewNopLogger()).(*articleBundleService)
+
+			svc.importDocs(tc.ctx(), domain.ArticleImport{Uid: 123, Total: 4,
+				Status: domain.ArticleImportStatusRunning, Ctime: time.Unix(100, 0)}, docs)
+			require.Len(t, saved, tc.wantSaves)
+			imp := saved[len(saved)-1]
+			imp.Ctime, imp.Utime = time.Time{}, time.Time{}
+			assert.Equal(t, tc.wantImp, imp)
+		})
+	}
+}
+
+// zipOf 参数是文件名、内容、文件名、内容……
+func zipOf(t *testing.T, files ...string) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for i := 0; i < len(files); i += 2 {
+		w, err := zw.Create(files[i])
+		require.NoError(t, err)
+		_, err = w.Write([]byte(files[i+1]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
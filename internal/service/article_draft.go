@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository"
+	"time"
+)
+
+var (
+	// ErrArticleVersionConflict 文章已经在别的标签页或者设备上保存过了
+	ErrArticleVersionConflict = repository.ErrArticleVersionConflict
+	ErrArticleNotFound        = repository.ErrArticleNotFound
+)
+
+// flushDraftsBatchSize 刷草稿的任务每次处理多少篇
+const flushDraftsBatchSize = 100
+
+func (a *articleService) AutoSave(ctx context.Context, draft domain.ArticleDraft) (int64, error) {
+	draft.Attachments = attachmentKeys(draft.Content)
+	draft.Utime = time.Now()
+	version, err := a.repo.SaveDraft(ctx, draft)
+	if err == repository.ErrArticleDraftForbidden {
+		return 0, ErrNotArticleAuthor
+	}
+	return version, err
+}
+
+func (a *articleService) FlushDrafts(ctx context.Context) error {
+	// 只刷任务开始之前改过的，作者一直在打字的话这一轮也能结束
+	before := time.Now()
+	for {
+		n, err := a.repo.FlushDrafts(ctx, before, flushDraftsBatchSize)
+		if err != nil {
+			return err
+		}
+		if n < flushDraftsBatchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository"
+	repomocks "ddd_demo/internal/repository/mocks"
+	"ddd_demo/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"strings"
+	"testing"
+)
+
+func Test_articleService_AutoSave(t *testing.T) {
+	key := strings.Repeat("a", 64)
+	content := "![图](/attachments/" + key + ")"
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.ArticleRepository
+
+		wantVersion int64
+		wantErr     error
+	}{
+		{
+			name: "保存成功",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().SaveDraft(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, draft domain.ArticleDraft) (int64, error) {
+						// 草稿里面引用的附件也要记下来，刷到数据库的时候一起写进去
+						assert.Equal(t, []string{key}, draft.Attachments)
+						assert.False(t, draft.Utime.IsZero())
+						return 4, nil
+					})
+				return repo
+			},
+			wantVersion: 4,
+		},
+		{
+			name: "不是作者",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().SaveDraft(gomock.Any(), gomock.Any()).
+					Return(int64(0), repository.ErrArticleDraftForbidden)
+				return repo
+			},
+			wantErr: ErrNotArticleAuthor,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewArticleService(tc.mock(ctrl), nil, nil, logger.NewNopLogger())
+			version, err := svc.AutoSave(context.Background(), domain.ArticleDraft{
+				Id:      1,
+				Author:  domain.Author{Id: 123},
+				Title:   "标题",
+				Content: content,
+				Version: 3,
+			})
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantVersion, version)
+		})
+	}
+}
+
+func Test_articleService_FlushDrafts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockArticleRepository(ctrl)
+	// 一批刷满了就接着刷，不满一批说明刷完了
+	gomock.InOrder(
+		repo.EXPECT().FlushDrafts(gomock.Any(), gomock.Any(), flushDraftsBatchSize).
+			Return(flushDraftsBatchSize, nil),
+		repo.EXPECT().FlushDrafts(gomock.Any(), gomock.Any(), flushDraftsBatchSize).
+			Return(3, nil),
+	)
+	svc := NewArticleService(repo, nil, nil, logger.NewNopLogger())
+	err := svc.FlushDrafts(context.Background())
+	assert.NoError(t, err)
+}
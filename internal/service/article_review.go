@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/events/article"
+	"ddd_demo/internal/repository"
+	"ddd_demo/internal/service/email"
+	"ddd_demo/internal/service/moderation"
+	"ddd_demo/pkg/logger"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrArticleReviewNotFound = repository.ErrArticleReviewNotFound
+	ErrArticleReviewHandled  = repository.ErrArticleReviewHandled
+	// ErrArticleReviewOutdated 审核期间作者改了文章或者撤回了，这个版本不用再审了
+	ErrArticleReviewOutdated = errors.New("文章已经被作者修改")
+	ErrEmptyRejectReason     = errors.New("拒绝的时候必须说明原因")
+)
+
+//go:generate mockgen -source=./article_review.go -package=svcmocks -destination=./mocks/article_review.mock.go ArticleReviewService
+type ArticleReviewService interface {
+	// Review 机审，aid 是提交发表的文章。没问题的直接发表，肯定不行的直接拒绝，
+	// 拿不准的进管理员的队列。文章已经不在审核中了就什么也不做，所以重复消费没有关系
+	Review(ctx context.Context, aid int64) error
+	// ListPending 等管理员审核的，先提交的在前面
+	ListPending(ctx context.Context, offset int, limit int) ([]domain.ArticleReview, error)
+	// Get 审核记录，带着审核的那个版本的内容
+	Get(ctx context.Context, id int64) (domain.ArticleReview, error)
+	// Approve reviewer 是管理员
+	Approve(ctx context.Context, reviewer int64, id int64) error
+	Reject(ctx context.Context, reviewer int64, id int64, reason string) error
+	// NotifyAuthor 通过邮件把审核结果告诉作者，id 是审核记录。
+	// 没有验证过邮箱的作者只能在自己的文章列表里面看到状态
+	NotifyAuthor(ctx context.Context, id int64) error
+}
+
+type articleReviewService struct {
+	repo       repository.ArticleRepository
+	reviewRepo repository.ArticleReviewRepository
+	userRepo   repository.UserRepository
+	moderator  moderation.Moderator
+	producer   article.Producer
+	emailSvc   email.Service
+	l          logger.LoggerV1
+}
+
+func NewArticleReviewService(repo repository.ArticleRepository,
+	reviewRepo repository.ArticleReviewRepository,
+	userRepo repository.UserRepository,
+	moderator moderation.Moderator,
+	producer article.Producer,
+	emailSvc email.Service,
+	l logger.LoggerV1) ArticleReviewService {
+	return &articleReviewService{
+		repo:       repo,
+		reviewRepo: reviewRepo,
+		userRepo:   userRepo,
+		moderator:  moderator,
+		producer:   producer,
+		emailSvc:   emailSvc,
+		l:          l,
+	}
+}
+
+func (s *articleReviewService) Review(ctx context.Context, aid int64) error {
+	// 缓存里面可能还是作者提交之前的版本
+	art, err := s.repo.FindById(ctx, aid)
+	if err == repository.ErrArticleNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if art.Status != domain.ArticleStatusReviewing {
+		return nil
+	}
+	res, err := s.moderator.Moderate(ctx, art.Title+"\n"+art.Content)
+	if err != nil {
+		return err
+	}
+	r := domain.ArticleReview{
+		ArtId:    art.Id,
+		Revision: art.Revision,
+		Author:   art.Author,
+		Title:    art.Title,
+		Reason:   res.Reason,
+	}
+	switch res.Verdict {
+	case moderation.VerdictPass:
+		r.Status = domain.ArticleReviewStatusApproved
+		err = s.publish(ctx, art)
+	case moderation.VerdictBlock:
+		r.Status = domain.ArticleReviewStatusRejected
+		err = s.repo.RejectReview(ctx, art.Author.Id, art.Id, art.Revision)
+	default:
+		r.Status = domain.ArticleReviewStatusPending
+		_, err = s.reviewRepo.Save(ctx, r)
+		return err
+	}
+	if err == repository.ErrArticleReviewChanged {
+		// 审核期间作者又改了，等新的版本提交上来再审
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	r.Id, err = s.reviewRepo.Save(ctx, r)
+	if err != nil {
+		return err
+	}
+	s.produceReviewedEvent(r)
+	return nil
+}
+
+func (s *articleReviewService) ListPending(ctx context.Context,
+	offset int, limit int) ([]domain.ArticleReview, error) {
+	return s.reviewRepo.ListPending(ctx, offset, limit)
+}
+
+func (s *articleReviewService) Get(ctx context.Context, id int64) (domain.ArticleReview, error) {
+	r, err := s.reviewRepo.FindById(ctx, id)
+	if err != nil {
+		return domain.ArticleReview{}, err
+	}
+	rev, err := s.repo.GetRevision(ctx, r.ArtId, r.Revision)
+	if err != nil {
+		return domain.ArticleReview{}, err
+	}
+	r.Content = rev.Content
+	return r, nil
+}
+
+func (s *articleReviewService) Approve(ctx context.Context, reviewer int64, id int64) error {
+	r, art, err := s.pending(ctx, id)
+	if err != nil {
+		return err
+	}
+	r.Status = domain.ArticleReviewStatusApproved
+	r.Reviewer = reviewer
+	r.Reason = ""
+	return s.handle(ctx, r, s.publish(ctx, art))
+}
+
+func (s *articleReviewService) Reject(ctx context.Context, reviewer int64, id int64, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return ErrEmptyRejectReason
+	}
+	r, art, err := s.pending(ctx, id)
+	if err != nil {
+		return err
+	}
+	r.Status = domain.ArticleReviewStatusRejected
+	r.Reviewer = reviewer
+	r.Reason = reason
+	return s.handle(ctx, r, s.repo.RejectReview(ctx, art.Author.Id, art.Id, art.Revision))
+}
+
+// pending 找到还在等审核的记录和它对应的文章，文章已经不是这个版本了就把记录标记成过期
+func (s *articleReviewService) pending(ctx context.Context, id int64) (domain.ArticleReview, domain.Article, error) {
+	r, err := s.reviewRepo.FindById(ctx, id)
+	if err != nil {
+		return domain.ArticleReview{}, domain.Article{}, err
+	}
+	if r.Status != domain.ArticleReviewStatusPending {
+		return domain.ArticleReview{}, domain.Article{}, ErrArticleReviewHandled
+	}
+	art, err := s.repo.FindById(ctx, r.ArtId)
+	if err != nil {
+		return domain.ArticleReview{}, domain.Article{}, err
+	}
+	if art.Status != domain.ArticleStatusReviewing || art.Revision != r.Revision {
+		return domain.ArticleReview{}, domain.Article{}, s.outdate(ctx, r)
+	}
+	return r, art, nil
+}
+
+// handle 管理员已经改完了文章，err 是改文章的结果，接下来更新审核记录、通知作者
+func (s *articleReviewService) handle(ctx context.Context, r domain.ArticleReview, err error) error {
+	if err == repository.ErrArticleReviewChanged {
+		return s.outdate(ctx, r)
+	}
+	if err != nil {
+		return err
+	}
+	err = s.reviewRepo.UpdateStatus(ctx, r)
+	if err != nil {
+		// 文章已经改好了，只是记录没有更新成功，还是要通知作者
+		s.l.Error("更新审核记录失败",
+			logger.Int64("rid", r.Id),
+			logger.Int64("aid", r.ArtId),
+			logger.Error(err))
+	}
+	s.produceReviewedEvent(r)
+	return nil
+}
+
+func (s *articleReviewService) outdate(ctx context.Context, r domain.ArticleReview) error {
+	r.Status = domain.ArticleReviewStatusOutdated
+	err := s.reviewRepo.UpdateStatus(ctx, r)
+	if err != nil {
+		// 包括 ErrArticleReviewHandled，别的管理员刚刚处理过
+		return err
+	}
+	return ErrArticleReviewOutdated
+}
+
+// publish 审核通过，把审核的这个版本发表出去
+func (s *articleReviewService) publish(ctx context.Context, art domain.Article) error {
+	var err error
+	art.Status = domain.ArticleStatusPublished
+	art.Rendered, err = render(art.Content)
+	if err != nil {
+		return err
+	}
+	_, err = s.repo.Sync(ctx, art)
+	if err != nil {
+		return err
+	}
+	er := s.producer.ProducePublishEvent(article.PublishEvent{
+		Aid: art.Id,
+		Uid: art.Author.Id,
+	})
+	if er != nil {
+		s.l.Error("发送 PublishEvent 失败",
+			logger.Int64("aid", art.Id),
+			logger.Error(er))
+	}
+	return nil
+}
+
+// produceReviewedEvent 审核结果已经落库了，通知发不出去只记录日志
+func (s *articleReviewService) produceReviewedEvent(r domain.ArticleReview) {
+	er := s.producer.ProduceReviewedEvent(article.ReviewedEvent{
+		Rid:      r.Id,
+		Aid:      r.ArtId,
+		Uid:      r.Author.Id,
+		Approved: r.Status == domain.ArticleReviewStatusApproved,
+	})
+	if er != nil {
+		s.l.Error("发送 ReviewedEvent 失败",
+			logger.Int64("rid", r.Id),
+			logger.Int64("aid", r.ArtId),
+			logger.Error(er))
+	}
+}
+
+func (s *articleReviewService) NotifyAuthor(ctx context.Context, id int64) error {
+	r, err := s.reviewRepo.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+	u, err := s.userRepo.FindById(ctx, r.Author.Id)
+	if err == repository.ErrUserNotFound {
+		// 作者已经注销了
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if u.Email == "" || !u.EmailVerified {
+		return nil
+	}
+	switch r.Status {
+	case domain.ArticleReviewStatusApproved:
+		return s.emailSvc.Send(ctx, u.Email, "文章审核通过",
+			fmt.Sprintf("你的文章《%s》已经审核通过并发表了。", r.Title))
+	case domain.ArticleReviewStatusRejected:
+		return s.emailSvc.Send(ctx, u.Email, "文章审核没有通过",
+			fmt.Sprintf("你的文章《%s》没有通过审核，原因：%s\n修改之后可以重新发表。", r.Title, r.Reason))
+	default:
+		return nil
+	}
+}
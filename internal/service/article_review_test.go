@@ -0,0 +1,293 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/events/article"
+	evtmocks "ddd_demo/internal/events/article/mocks"
+	"ddd_demo/internal/repository"
+	repomocks "ddd_demo/internal/repository/mocks"
+	"ddd_demo/internal/service/email/local"
+	"ddd_demo/internal/service/moderation"
+	moderationmocks "ddd_demo/internal/service/moderation/mocks"
+	"ddd_demo/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func Test_articleReviewService_Review(t *testing.T) {
+	reviewing := domain.Article{
+		Id:       1,
+		Title:    "标题",
+		Content:  "## 简介\n\n**Go** 语言<script>alert(1)</script>",
+		Author:   domain.Author{Id: 123},
+		Status:   domain.ArticleStatusReviewing,
+		Revision: 3,
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.ArticleRepository,
+			repository.ArticleReviewRepository, moderation.Moderator, article.Producer)
+
+		wantErr error
+	}{
+		{
+			// 审核通过的时候把 Markdown 渲染好一起同步
+			name: "机审通过直接发表",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository,
+				repository.ArticleReviewRepository, moderation.Moderator, article.Producer) {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(reviewing, nil)
+				published := reviewing
+				published.Status = domain.ArticleStatusPublished
+				published.Rendered = domain.ArticleRendered{
+					HTML:     "<h2 id=\"简介\">简介</h2>\n<p><strong>Go</strong> 语言</p>\n",
+					Abstract: "简介 Go 语言",
+					TOC:      []domain.ArticleHeading{{Level: 2, Id: "简介", Title: "简介"}},
+				}
+				repo.EXPECT().Sync(gomock.Any(), published).Return(int64(1), nil)
+				moderator := moderationmocks.NewMockModerator(ctrl)
+				moderator.EXPECT().Moderate(gomock.Any(), "标题\n"+reviewing.Content).
+					Return(moderation.Result{Verdict: moderation.VerdictPass}, nil)
+				reviewRepo := repomocks.NewMockArticleReviewRepository(ctrl)
+				reviewRepo.EXPECT().Save(gomock.Any(), domain.ArticleReview{
+					ArtId:    1,
+					Revision: 3,
+					Author:   domain.Author{Id: 123},
+					Title:    "标题",
+					Status:   domain.ArticleReviewStatusApproved,
+				}).Return(int64(10), nil)
+				producer := evtmocks.NewMockProducer(ctrl)
+				producer.EXPECT().ProducePublishEvent(article.PublishEvent{Aid: 1, Uid: 123}).Return(nil)
+				producer.EXPECT().ProduceReviewedEvent(article.ReviewedEvent{
+					Rid: 10, Aid: 1, Uid: 123, Approved: true,
+				}).Return(nil)
+				return repo, reviewRepo, moderator, producer
+			},
+		},
+		{
+			name: "机审拒绝",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository,
+				repository.ArticleReviewRepository, moderation.Moderator, article.Producer) {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(reviewing, nil)
+				repo.EXPECT().RejectReview(gomock.Any(), int64(123), int64(1), int64(3)).Return(nil)
+				moderator := moderationmocks.NewMockModerator(ctrl)
+				moderator.EXPECT().Moderate(gomock.Any(), gomock.Any()).
+					Return(moderation.Result{Verdict: moderation.VerdictBlock, Reason: "包含违规内容「赌博」"}, nil)
+				reviewRepo := repomocks.NewMockArticleReviewRepository(ctrl)
+				reviewRepo.EXPECT().Save(gomock.Any(), domain.ArticleReview{
+					ArtId:    1,
+					Revision: 3,
+					Author:   domain.Author{Id: 123},
+					Title:    "标题",
+					Status:   domain.ArticleReviewStatusRejected,
+					Reason:   "包含违规内容「赌博」",
+				}).Return(int64(10), nil)
+				producer := evtmocks.NewMockProducer(ctrl)
+				producer.EXPECT().ProduceReviewedEvent(article.ReviewedEvent{
+					Rid: 10, Aid: 1, Uid: 123,
+				}).Return(nil)
+				return repo, reviewRepo, moderator, producer
+			},
+		},
+		{
+			name: "拿不准的进队列",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository,
+				repository.ArticleReviewRepository, moderation.Moderator, article.Producer) {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(reviewing, nil)
+				moderator := moderationmocks.NewMockModerator(ctrl)
+				moderator.EXPECT().Moderate(gomock.Any(), gomock.Any()).
+					Return(moderation.Result{Verdict: moderation.VerdictReview, Reason: "包含需要人工确认的内容「代购」"}, nil)
+				reviewRepo := repomocks.NewMockArticleReviewRepository(ctrl)
+				reviewRepo.EXPECT().Save(gomock.Any(), domain.ArticleReview{
+					ArtId:    1,
+					Revision: 3,
+					Author:   domain.Author{Id: 123},
+					Title:    "标题",
+					Status:   domain.ArticleReviewStatusPending,
+					Reason:   "包含需要人工确认的内容「代购」",
+				}).Return(int64(10), nil)
+				// 还没有结果，不通知作者
+				return repo, reviewRepo, moderator, evtmocks.NewMockProducer(ctrl)
+			},
+		},
+		{
+			// 重复消费，或者作者提交之后又改回了草稿
+			name: "已经不在审核中了",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository,
+				repository.ArticleReviewRepository, moderation.Moderator, article.Producer) {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				art := reviewing
+				art.Status = domain.ArticleStatusPublished
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(art, nil)
+				return repo, repomocks.NewMockArticleReviewRepository(ctrl),
+					moderationmocks.NewMockModerator(ctrl), evtmocks.NewMockProducer(ctrl)
+			},
+		},
+		{
+			name: "机审期间作者又改了",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository,
+				repository.ArticleReviewRepository, moderation.Moderator, article.Producer) {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(reviewing, nil)
+				repo.EXPECT().RejectReview(gomock.Any(), int64(123), int64(1), int64(3)).
+					Return(repository.ErrArticleReviewChanged)
+				moderator := moderationmocks.NewMockModerator(ctrl)
+				moderator.EXPECT().Moderate(gomock.Any(), gomock.Any()).
+					Return(moderation.Result{Verdict: moderation.VerdictBlock}, nil)
+				return repo, repomocks.NewMockArticleReviewRepository(ctrl),
+					moderator, evtmocks.NewMockProducer(ctrl)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, reviewRepo, moderator, producer := tc.mock(ctrl)
+			svc := NewArticleReviewService(repo, reviewRepo, nil,
+				moderator, producer, nil, logger.NewNopLogger())
+			err := svc.Review(context.Background(), 1)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_articleReviewService_Reject(t *testing.T) {
+	pending := domain.ArticleReview{
+		Id:       10,
+		ArtId:    1,
+		Revision: 3,
+		Author:   domain.Author{Id: 123},
+		Status:   domain.ArticleReviewStatusPending,
+	}
+	testCases := []struct {
+		name   string
+		mock   func(ctrl *gomock.Controller) (repository.ArticleRepository, repository.ArticleReviewRepository, article.Producer)
+		reason string
+
+		wantErr error
+	}{
+		{
+			name: "拒绝",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository, repository.ArticleReviewRepository, article.Producer) {
+				reviewRepo := repomocks.NewMockArticleReviewRepository(ctrl)
+				reviewRepo.EXPECT().FindById(gomock.Any(), int64(10)).Return(pending, nil)
+				rejected := pending
+				rejected.Status = domain.ArticleReviewStatusRejected
+				rejected.Reviewer = 99
+				rejected.Reason = "广告"
+				reviewRepo.EXPECT().UpdateStatus(gomock.Any(), rejected).Return(nil)
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.Article{
+					Id: 1, Author: domain.Author{Id: 123},
+					Status: domain.ArticleStatusReviewing, Revision: 3,
+				}, nil)
+				repo.EXPECT().RejectReview(gomock.Any(), int64(123), int64(1), int64(3)).Return(nil)
+				producer := evtmocks.NewMockProducer(ctrl)
+				producer.EXPECT().ProduceReviewedEvent(article.ReviewedEvent{
+					Rid: 10, Aid: 1, Uid: 123,
+				}).Return(nil)
+				return repo, reviewRepo, producer
+			},
+			reason: " 广告 ",
+		},
+		{
+			name: "没有原因",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository, repository.ArticleReviewRepository, article.Producer) {
+				return repomocks.NewMockArticleRepository(ctrl),
+					repomocks.NewMockArticleReviewRepository(ctrl), evtmocks.NewMockProducer(ctrl)
+			},
+			reason:  "  ",
+			wantErr: ErrEmptyRejectReason,
+		},
+		{
+			name: "别的管理员已经处理过了",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository, repository.ArticleReviewRepository, article.Producer) {
+				reviewRepo := repomocks.NewMockArticleReviewRepository(ctrl)
+				handled := pending
+				handled.Status = domain.ArticleReviewStatusApproved
+				reviewRepo.EXPECT().FindById(gomock.Any(), int64(10)).Return(handled, nil)
+				return repomocks.NewMockArticleRepository(ctrl), reviewRepo, evtmocks.NewMockProducer(ctrl)
+			},
+			reason:  "广告",
+			wantErr: ErrArticleReviewHandled,
+		},
+		{
+			name: "作者已经改了文章",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository, repository.ArticleReviewRepository, article.Producer) {
+				reviewRepo := repomocks.NewMockArticleReviewRepository(ctrl)
+				reviewRepo.EXPECT().FindById(gomock.Any(), int64(10)).Return(pending, nil)
+				outdated := pending
+				outdated.Status = domain.ArticleReviewStatusOutdated
+				reviewRepo.EXPECT().UpdateStatus(gomock.Any(), outdated).Return(nil)
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.Article{
+					Id: 1, Author: domain.Author{Id: 123},
+					Status: domain.ArticleStatusUnpublished, Revision: 4,
+				}, nil)
+				return repo, reviewRepo, evtmocks.NewMockProducer(ctrl)
+			},
+			reason:  "广告",
+			wantErr: ErrArticleReviewOutdated,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, reviewRepo, producer := tc.mock(ctrl)
+			svc := NewArticleReviewService(repo, reviewRepo, nil,
+				nil, producer, nil, logger.NewNopLogger())
+			err := svc.Reject(context.Background(), 99, 10, tc.reason)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_articleReviewService_NotifyAuthor(t *testing.T) {
+	testCases := []struct {
+		name string
+		user domain.User
+
+		wantMail string
+	}{
+		{
+			name: "邮箱验证过了",
+			user: domain.User{Id: 123, Email: "a@qq.com", EmailVerified: true},
+			wantMail: "To: a@qq.com\nSubject: 文章审核没有通过\n\n" +
+				"你的文章《标题》没有通过审核，原因：广告\n修改之后可以重新发表。\n\n",
+		},
+		{
+			name: "邮箱没有验证",
+			user: domain.User{Id: 123, Email: "a@qq.com"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			reviewRepo := repomocks.NewMockArticleReviewRepository(ctrl)
+			reviewRepo.EXPECT().FindById(gomock.Any(), int64(10)).Return(domain.ArticleReview{
+				Id:     10,
+				ArtId:  1,
+				Author: domain.Author{Id: 123},
+				Title:  "标题",
+				Status: domain.ArticleReviewStatusRejected,
+				Reason: "广告",
+			}, nil)
+			userRepo := repomocks.NewMockUserRepository(ctrl)
+			userRepo.EXPECT().FindById(gomock.Any(), int64(123)).Return(tc.user, nil)
+			var buf bytes.Buffer
+			svc := NewArticleReviewService(nil, reviewRepo, userRepo,
+				nil, nil, local.NewService(&buf), logger.NewNopLogger())
+			err := svc.NotifyAuthor(context.Background(), 10)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantMail, buf.String())
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository"
+	"ddd_demo/pkg/diffx"
+	"errors"
+)
+
+var (
+	ErrArticleRevisionNotFound = repository.ErrArticleRevisionNotFound
+	ErrNotArticleAuthor        = errors.New("不是文章的作者")
+)
+
+func (a *articleService) ListRevisions(ctx context.Context,
+	uid int64, id int64, offset int, limit int) ([]domain.ArticleRevision, error) {
+	if err := a.checkAuthor(ctx, uid, id); err != nil {
+		return nil, err
+	}
+	return a.repo.ListRevisions(ctx, id, offset, limit)
+}
+
+func (a *articleService) DiffRevisions(ctx context.Context,
+	uid int64, id int64, from int64, to int64) (domain.ArticleRevisionDiff, error) {
+	if err := a.checkAuthor(ctx, uid, id); err != nil {
+		return domain.ArticleRevisionDiff{}, err
+	}
+	fromRev, err := a.repo.GetRevision(ctx, id, from)
+	if err != nil {
+		return domain.ArticleRevisionDiff{}, err
+	}
+	toRev, err := a.repo.GetRevision(ctx, id, to)
+	if err != nil {
+		return domain.ArticleRevisionDiff{}, err
+	}
+	return domain.ArticleRevisionDiff{
+		From:    fromRev,
+		To:      toRev,
+		Title:   diffx.Lines(fromRev.Title, toRev.Title),
+		Content: diffx.Lines(fromRev.Content, toRev.Content),
+	}, nil
+}
+
+func (a *articleService) RestoreRevision(ctx context.Context,
+	uid int64, id int64, version int64) error {
+	art, err := a.repo.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if art.Author.Id != uid {
+		return ErrNotArticleAuthor
+	}
+	rev, err := a.repo.GetRevision(ctx, id, version)
+	if err != nil {
+		return err
+	}
+	// 和保存草稿一样，线上库不受影响，要再发表一次才会同步过去。
+	// 历史版本里面没有标签，沿用现在的。恢复是在现在的版本上改的，不用作者传版本号
+	_, err = a.Save(ctx, domain.Article{
+		Id:      id,
+		Title:   rev.Title,
+		Content: rev.Content,
+		Author:  domain.Author{Id: uid},
+		Tags:    art.Tags,
+		Version: art.Version,
+	})
+	return err
+}
+
+func (a *articleService) checkAuthor(ctx context.Context, uid int64, id int64) error {
+	art, err := a.repo.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if art.Author.Id != uid {
+		return ErrNotArticleAuthor
+	}
+	return nil
+}
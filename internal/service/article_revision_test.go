@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository"
+	repomocks "ddd_demo/internal/repository/mocks"
+	"ddd_demo/pkg/logger"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func Test_articleService_RestoreRevision(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.ArticleRepository
+
+		uid     int64
+		wantErr error
+	}{
+		{
+			name: "恢复成功",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).
+					Return(domain.Article{Id: 1, Author: domain.Author{Id: 123},
+						Tags: []string{"go"}}, nil)
+				repo.EXPECT().GetRevision(gomock.Any(), int64(1), int64(2)).
+					Return(domain.ArticleRevision{ArticleId: 1, Version: 2,
+						Title: "老的标题", Content: "老的内容"}, nil)
+				// 恢复成草稿，不会直接同步到线上库，标签保持不变
+				repo.EXPECT().Update(gomock.Any(), domain.Article{
+					Id:      1,
+					Title:   "老的标题",
+					Content: "老的内容",
+					Author:  domain.Author{Id: 123},
+					Status:  domain.ArticleStatusUnpublished,
+					Tags:    []string{"go"},
+				}).Return(nil)
+				return repo
+			},
+			uid: 123,
+		},
+		{
+			// 别人的文章，连历史版本都不能读
+			name: "不是作者",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).
+					Return(domain.Article{Id: 1, Author: domain.Author{Id: 123}}, nil)
+				return repo
+			},
+			uid:     234,
+			wantErr: ErrNotArticleAuthor,
+		},
+		{
+			name: "版本不存在",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).
+					Return(domain.Article{Id: 1, Author: domain.Author{Id: 123}}, nil)
+				repo.EXPECT().GetRevision(gomock.Any(), int64(1), int64(2)).
+					Return(domain.ArticleRevision{}, ErrArticleRevisionNotFound)
+				return repo
+			},
+			uid:     123,
+			wantErr: ErrArticleRevisionNotFound,
+		},
+		{
+			name: "查询文章失败",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).
+					Return(domain.Article{}, errors.New("mock db 错误"))
+				return repo
+			},
+			uid:     123,
+			wantErr: errors.New("mock db 错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewArticleService(tc.mock(ctrl), nil, nil, logger.NewNopLogger())
+			err := svc.RestoreRevision(context.Background(), tc.uid, 1, 2)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
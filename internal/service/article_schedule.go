@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/events/article"
+	"ddd_demo/internal/repository"
+	"ddd_demo/pkg/logger"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidPublishTime  = errors.New("定时发表的时间必须在将来")
+	ErrArticleNotScheduled = repository.ErrArticleNotScheduled
+)
+
+// publishDueBatchSize 定时任务每次从数据库里面捞多少篇
+const publishDueBatchSize = 100
+
+func (a *articleService) Schedule(ctx context.Context, art domain.Article) (int64, error) {
+	if !art.PublishAt.After(time.Now()) {
+		return 0, ErrInvalidPublishTime
+	}
+	if !art.Visibility.Valid() {
+		return 0, ErrInvalidVisibility
+	}
+	tags, err := normalizeTags(art.Tags)
+	if err != nil {
+		return 0, err
+	}
+	art.Tags = tags
+	art.Attachments = attachmentKeys(art.Content)
+	art.Status = domain.ArticleStatusScheduled
+	if art.Id > 0 {
+		err = a.repo.Update(ctx, art)
+		return art.Id, err
+	}
+	return a.repo.Create(ctx, art)
+}
+
+func (a *articleService) Reschedule(ctx context.Context, uid int64, id int64, publishAt time.Time) error {
+	if !publishAt.After(time.Now()) {
+		return ErrInvalidPublishTime
+	}
+	return a.repo.UpdateSchedule(ctx, uid, id, publishAt)
+}
+
+func (a *articleService) CancelSchedule(ctx context.Context, uid int64, id int64) error {
+	return a.repo.CancelSchedule(ctx, uid, id)
+}
+
+func (a *articleService) PublishDue(ctx context.Context) error {
+	now := time.Now()
+	for {
+		arts, err := a.repo.ListDue(ctx, now, publishDueBatchSize)
+		if err != nil {
+			return err
+		}
+		submitted := 0
+		for _, art := range arts {
+			// 和作者自己发表一样要先审核。带着 PublishAt 和 Revision 去更新，作者中途取消、改时间或者改内容，
+			// 或者别的节点已经提交了，DAO 都会返回 ErrArticleScheduleChanged
+			art.Status = domain.ArticleStatusReviewing
+			err = a.repo.Update(ctx, art)
+			switch {
+			case err == nil:
+				submitted++
+				a.produceReviewEvent(art.Id, art.Author.Id)
+			case errors.Is(err, repository.ErrArticleScheduleChanged):
+				a.l.Info("定时发表的文章已经被修改，跳过",
+					logger.Int64("aid", art.Id))
+			default:
+				// 下一次定时任务还会再捞到它
+				a.l.Error("定时发表文章失败",
+					logger.Int64("aid", art.Id),
+					logger.Error(err))
+			}
+		}
+		// 一篇都没提交说明剩下的都是失败的，等下一轮再试
+		if len(arts) < publishDueBatchSize || submitted == 0 {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// produceReviewEvent 定时任务里面没有人能重试，消息发不出去只能记录日志，
+// 文章会一直停在审核中，作者重新发表一次就可以
+func (a *articleService) produceReviewEvent(aid int64, uid int64) {
+	er := a.producer.ProduceReviewEvent(article.ReviewEvent{
+		Aid: aid,
+		Uid: uid,
+	})
+	if er != nil {
+		a.l.Error("发送 ReviewEvent 失败",
+			logger.Int64("aid", aid),
+			logger.Int64("uid", uid),
+			logger.Error(er))
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/events/article"
+	evtmocks "ddd_demo/internal/events/article/mocks"
+	"ddd_demo/internal/repository"
+	repomocks "ddd_demo/internal/repository/mocks"
+	"ddd_demo/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+// Test_articleService_PublishDue 两个节点同时捞到了同一篇到点的文章，
+// 只有一个能提交审核，另外一个拿到 ErrArticleScheduleChanged 之后跳过
+func Test_articleService_PublishDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	publishAt := time.UnixMilli(1000)
+	due := domain.Article{
+		Id:        1,
+		Title:     "标题",
+		Content:   "内容",
+		Author:    domain.Author{Id: 123},
+		Status:    domain.ArticleStatusScheduled,
+		Revision:  3,
+		PublishAt: publishAt,
+	}
+	// 和作者自己发表一样，要审核通过之后才会同步到线上库
+	want := due
+	want.Status = domain.ArticleStatusReviewing
+
+	repo := repomocks.NewMockArticleRepository(ctrl)
+	repo.EXPECT().ListDue(gomock.Any(), gomock.Any(), publishDueBatchSize).
+		Return([]domain.Article{due}, nil).Times(2)
+	gomock.InOrder(
+		repo.EXPECT().Update(gomock.Any(), want).Return(nil),
+		repo.EXPECT().Update(gomock.Any(), want).
+			Return(repository.ErrArticleScheduleChanged),
+	)
+
+	// 只有提交成功的那个节点发消息
+	producer := evtmocks.NewMockProducer(ctrl)
+	producer.EXPECT().ProduceReviewEvent(article.ReviewEvent{Aid: 1, Uid: 123}).Return(nil)
+
+	node1 := NewArticleService(repo, nil, producer, logger.NewNopLogger())
+	node2 := NewArticleService(repo, nil, producer, logger.NewNopLogger())
+	require.NoError(t, node1.PublishDue(context.Background()))
+	// 已经被别的节点提交了，不算失败
+	assert.NoError(t, node2.PublishDue(context.Background()))
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrTooManyTags = errors.New("标签太多了")
+	ErrInvalidTag  = errors.New("标签不合法")
+)
+
+const (
+	// maxArticleTags 一篇文章最多几个标签
+	maxArticleTags = 5
+	// maxTagLen 标签最多几个字
+	maxTagLen = 20
+)
+
+func (a *articleService) ListPubByTag(ctx context.Context,
+	tag string, cursor int64, limit int) ([]domain.Article, error) {
+	tag, err := normalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	return a.repo.ListPubByTag(ctx, tag, cursor, limit)
+}
+
+func (a *articleService) ListTags(ctx context.Context, limit int) ([]domain.TagCount, error) {
+	return a.repo.ListTagCounts(ctx, limit)
+}
+
+// normalizeTags 去掉重复的标签，保持作者填写的顺序
+func normalizeTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	res := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, t := range tags {
+		tag, err := normalizeTag(t)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		res = append(res, tag)
+	}
+	if len(res) > maxArticleTags {
+		return nil, ErrTooManyTags
+	}
+	return res, nil
+}
+
+// normalizeTag "#Go " 和 "go" 是同一个标签。
+// 标签会出现在 URL 的路径里面，所以不能有 / ? #
+func normalizeTag(tag string) (string, error) {
+	tag = strings.TrimSpace(tag)
+	tag = strings.TrimPrefix(tag, "#")
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" || utf8.RuneCountInString(tag) > maxTagLen ||
+		strings.ContainsAny(tag, "/?#") {
+		return "", ErrInvalidTag
+	}
+	return tag, nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/events/article"
+	evtmocks "ddd_demo/internal/events/article/mocks"
+	"ddd_demo/internal/repository"
+	repomocks "ddd_demo/internal/repository/mocks"
+	"ddd_demo/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func Test_normalizeTags(t *testing.T) {
+	testCases := []struct {
+		name string
+		tags []string
+
+		wantTags []string
+		wantErr  error
+	}{
+		{
+			name:     "去掉井号和空格，统一小写，去重",
+			tags:     []string{" #Go ", "go", "后端", "GO"},
+			wantTags: []string{"go", "后端"},
+		},
+		{
+			name: "没有标签",
+		},
+		{
+			name:    "空标签",
+			tags:    []string{"go", " # "},
+			wantErr: ErrInvalidTag,
+		},
+		{
+			name:    "标签太长",
+			tags:    []string{"一二三四五六七八九十一二三四五六七八九十一"},
+			wantErr: ErrInvalidTag,
+		},
+		{
+			name:    "标签里面有斜杠",
+			tags:    []string{"c/c++"},
+			wantErr: ErrInvalidTag,
+		},
+		{
+			name:    "标签太多",
+			tags:    []string{"a", "b", "c", "d", "e", "f"},
+			wantErr: ErrTooManyTags,
+		},
+		{
+			// 去重之后没超过就可以
+			name:     "去重之后不多",
+			tags:     []string{"a", "b", "c", "d", "e", "A"},
+			wantTags: []string{"a", "b", "c", "d", "e"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tags, err := normalizeTags(tc.tags)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantTags, tags)
+		})
+	}
+}
+
+func Test_articleService_PublishWithTags(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.ArticleRepository, article.Producer)
+		tags []string
+
+		wantId  int64
+		wantErr error
+	}{
+		{
+			name: "规范化之后提交审核",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository, article.Producer) {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().Create(gomock.Any(), domain.Article{
+					Title:  "标题",
+					Author: domain.Author{Id: 123},
+					Status: domain.ArticleStatusReviewing,
+					Tags:   []string{"go", "后端"},
+				}).Return(int64(1), nil)
+				producer := evtmocks.NewMockProducer(ctrl)
+				producer.EXPECT().ProduceReviewEvent(article.ReviewEvent{Aid: 1, Uid: 123}).
+					Return(nil)
+				return repo, producer
+			},
+			tags:   []string{"#Go", "后端", "go"},
+			wantId: 1,
+		},
+		{
+			name: "标签不合法",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository, article.Producer) {
+				return repomocks.NewMockArticleRepository(ctrl), evtmocks.NewMockProducer(ctrl)
+			},
+			tags:    []string{"a?b"},
+			wantErr: ErrInvalidTag,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, producer := tc.mock(ctrl)
+			svc := NewArticleService(repo, nil, producer, logger.NewNopLogger())
+			id, err := svc.Publish(context.Background(), domain.Article{
+				Title:  "标题",
+				Author: domain.Author{Id: 123},
+				Tags:   tc.tags,
+			})
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantId, id)
+		})
+	}
+}
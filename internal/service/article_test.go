@@ -3,12 +3,15 @@ package service
 import (
 	"context"
 	"ddd_demo/internal/domain"
+	"ddd_demo/internal/events/article"
+	evtmocks "ddd_demo/internal/events/article/mocks"
 	"ddd_demo/internal/repository"
 	repomocks "ddd_demo/internal/repository/mocks"
 	"ddd_demo/pkg/logger"
 	"errors"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"strings"
 	"testing"
 )
 
@@ -214,3 +217,95 @@ func Test_articleService_Publish(t *testing.T) {
 		})
 	}
 }
+
+// Test_articleService_PublishReview 发表只是保存到制作库、提交审核，审核通过之后才会同步到线上库
+func Test_articleService_PublishReview(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.ArticleRepository, article.Producer)
+		art  domain.Article
+
+		wantId  int64
+		wantErr error
+	}{
+		{
+			name: "修改已有的文章",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository, article.Producer) {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().Update(gomock.Any(), domain.Article{
+					Id:      1,
+					Title:   "标题",
+					Content: "内容",
+					Author:  domain.Author{Id: 123},
+					Status:  domain.ArticleStatusReviewing,
+				}).Return(nil)
+				producer := evtmocks.NewMockProducer(ctrl)
+				producer.EXPECT().ProduceReviewEvent(article.ReviewEvent{Aid: 1, Uid: 123}).
+					Return(nil)
+				return repo, producer
+			},
+			art: domain.Article{
+				Id:      1,
+				Title:   "标题",
+				Content: "内容",
+				Author:  domain.Author{Id: 123},
+			},
+			wantId: 1,
+		},
+		{
+			// 文章已经保存了，作者重新发表一次就可以
+			name: "提交审核失败",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository, article.Producer) {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+				producer := evtmocks.NewMockProducer(ctrl)
+				producer.EXPECT().ProduceReviewEvent(article.ReviewEvent{Aid: 1, Uid: 123}).
+					Return(errors.New("mock kafka error"))
+				return repo, producer
+			},
+			art: domain.Article{
+				Title:  "标题",
+				Author: domain.Author{Id: 123},
+			},
+			wantId:  1,
+			wantErr: errors.New("mock kafka error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, producer := tc.mock(ctrl)
+			svc := NewArticleService(repo, nil, producer, logger.NewNopLogger())
+			id, err := svc.Publish(context.Background(), tc.art)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantId, id)
+		})
+	}
+}
+
+func Test_articleService_SaveAttachments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	key := strings.Repeat("a", 64)
+	content := "![图](/attachments/" + key + "/thumb)"
+	repo := repomocks.NewMockArticleRepository(ctrl)
+	repo.EXPECT().Update(gomock.Any(), domain.Article{
+		Id:          1,
+		Title:       "标题",
+		Content:     content,
+		Author:      domain.Author{Id: 123},
+		Status:      domain.ArticleStatusUnpublished,
+		Attachments: []string{key},
+	}).Return(nil)
+
+	svc := NewArticleService(repo, nil, nil, logger.NewNopLogger())
+	id, err := svc.Save(context.Background(), domain.Article{
+		Id:      1,
+		Title:   "标题",
+		Content: content,
+		Author:  domain.Author{Id: 123},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), id)
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository"
+	"errors"
+)
+
+var (
+	ErrInvalidVisibility = errors.New("可见范围不合法")
+	// ErrArticleInvisible 文章存在，但是这个人看不了
+	ErrArticleInvisible = errors.New("没有权限查看这篇文章")
+	// ErrArticleNotShareable 只有已经发表的、不公开列出或者仅自己可见的文章才能生成分享链接
+	ErrArticleNotShareable = errors.New("这篇文章不能分享")
+)
+
+func (a *articleService) SetVisibility(ctx context.Context,
+	uid int64, id int64, visibility domain.ArticleVisibility) error {
+	if !visibility.Valid() {
+		return ErrInvalidVisibility
+	}
+	err := a.repo.SyncVisibility(ctx, uid, id, visibility)
+	if err != nil {
+		return err
+	}
+	// 搜索索引要跟着加上或者去掉这篇文章
+	a.producePublishEvent(id, uid)
+	return nil
+}
+
+func (a *articleService) CheckShare(ctx context.Context, uid int64, id int64) error {
+	art, err := a.repo.GetPubById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if art.Author.Id != uid {
+		return ErrArticleInvisible
+	}
+	if art.Status != domain.ArticleStatusPublished || !art.Visibility.Shareable() {
+		return ErrArticleNotShareable
+	}
+	return nil
+}
+
+// canView uid 能不能看线上库的这篇文章，shared 表示带着这篇文章有效的分享链接。
+// 作者自己什么时候都能看；撤回了的只有作者能看，分享链接也不行。
+// 文章 ID 是连续的，所以不公开列出的和仅自己可见的一样，没有分享链接不能看
+func (a *articleService) canView(ctx context.Context, art domain.Article, uid int64, shared bool) error {
+	if art.Author.Id == uid {
+		return nil
+	}
+	if art.Status != domain.ArticleStatusPublished {
+		return ErrArticleInvisible
+	}
+	switch art.Visibility {
+	case domain.ArticleVisibilityPublic:
+		return nil
+	case domain.ArticleVisibilityFollowers:
+		_, err := a.followRepo.FollowInfo(ctx, uid, art.Author.Id)
+		if err == repository.ErrFollowRelationNotFound {
+			return ErrArticleInvisible
+		}
+		return err
+	case domain.ArticleVisibilityUnlisted, domain.ArticleVisibilityPrivate:
+		if shared {
+			return nil
+		}
+		return ErrArticleInvisible
+	default:
+		return ErrArticleInvisible
+	}
+}
+
+// visibilitiesFor viewer 在 uid 的主页和专栏目录里面能看到哪些文章，nil 就是全部
+func visibilitiesFor(ctx context.Context, followRepo repository.FollowRepository,
+	uid int64, viewer int64) ([]domain.ArticleVisibility, error) {
+	if uid == viewer {
+		return nil, nil
+	}
+	public := []domain.ArticleVisibility{domain.ArticleVisibilityPublic}
+	if viewer <= 0 {
+		return public, nil
+	}
+	_, err := followRepo.FollowInfo(ctx, viewer, uid)
+	switch err {
+	case nil:
+		return append(public, domain.ArticleVisibilityFollowers), nil
+	case repository.ErrFollowRelationNotFound:
+		return public, nil
+	default:
+		return nil, err
+	}
+}
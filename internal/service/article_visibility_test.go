@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/events/article"
+	evtmocks "ddd_demo/internal/events/article/mocks"
+	"ddd_demo/internal/repository"
+	repomocks "ddd_demo/internal/repository/mocks"
+	"ddd_demo/pkg/logger"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func Test_articleService_GetPubById(t *testing.T) {
+	pub := func(status domain.ArticleStatus, v domain.ArticleVisibility) domain.Article {
+		return domain.Article{Id: 1, Author: domain.Author{Id: 123}, Status: status, Visibility: v}
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.ArticleRepository, repository.FollowRepository)
+		// uid 是读者
+		uid    int64
+		shared bool
+
+		wantErr error
+	}{
+		{
+			name: "公开",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository, repository.FollowRepository) {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetPubById(gomock.Any(), int64(1)).
+					Return(pub(domain.ArticleStatusPublished, domain.ArticleVisibilityPublic), nil)
+				return repo, repomocks.NewMockFollowRepository(ctrl)
+			},
+			uid: 456,
+		},
+		{
+			name: "不公开列出，带着分享链接",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository, repository.FollowRepository) {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetPubById(gomock.Any(), int64(1)).
+					Return(pub(domain.ArticleStatusPublished, domain.ArticleVisibilityUnlisted), nil)
+				return repo, repomocks.NewMockFollowRepository(ctrl)
+			},
+			uid:    456,
+			shared: true,
+		},
+		{
+			// 挨个试 ID 也找不到不公开列出的文章
+			name: "不公开列出，没有分享链接",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository, repository.FollowRepository) {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetPubById(gomock.Any(), int64(1)).
+					Return(pub(domain.ArticleStatusPublished, domain.ArticleVisibilityUnlisted), nil)
+				return repo, repomocks.NewMockFollowRepository(ctrl)
+			},
+			uid:     456,
+			wantErr: ErrArticleInvisible,
+		},
+		{
+			name: "仅关注者可见，关注了",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository, repository.FollowRepository) {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetPubById(gomock.Any(), int64(1)).
+					Return(pub(domain.ArticleStatusPublished, domain.ArticleVisibilityFollowers), nil)
+				followRepo := repomocks.NewMockFollowRepository(ctrl)
+				followRepo.EXPECT().FollowInfo(gomock.Any(), int64(456), int64(123)).
+					Return(domain.FollowRelation{Follower: 456, Followee: 123}, nil)
+				return repo, followRepo
+			},
+			uid: 456,
+		},
+		{
+			name: "仅关注者可见，没有关注",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository, repository.FollowRepository) {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetPubById(gomock.Any(), int64(1)).
+					Return(pub(domain.ArticleStatusPublished, domain.ArticleVisibilityFollowers), nil)
+				followRepo := repomocks.NewMockFollowRepository(ctrl)
+				followRepo.EXPECT().FollowInfo(gomock.Any(), int64(456), int64(123)).
+					Return(domain.FollowRelation{}, repository.ErrFollowRelationNotFound)
+				return repo, followRepo
+			},
+			uid:     456,
+			wantErr: ErrArticleInvisible,
+		},
+		{
+			name: "仅关注者可见，查关注关系出错",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository, repository.FollowRepository) {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetPubById(gomock.Any(), int64(1)).
+					Return(pub(domain.ArticleStatusPublished, domain.ArticleVisibilityFollowers), nil)
+				followRepo := repomocks.NewMockFollowRepository(ctrl)
+				followRepo.EXPECT().FollowInfo(gomock.Any(), int64(456), int64(123)).
+					Return(domain.FollowRelation{}, errors.New("mock db 错误"))
+				return repo, followRepo
+			},
+			uid:     456,
+			wantErr: errors.New("mock db 错误"),
+		},
+		{
+			name: "仅自己可见，带着分享链接",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository, repository.FollowRepository) {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetPubById(gomock.Any(), int64(1)).
+					Return(pub(domain.ArticleStatusPublished, domain.ArticleVisibilityPrivate), nil)
+				return repo, repomocks.NewMockFollowRepository(ctrl)
+			},
+			uid:    456,
+			shared: true,
+		},
+		{
+			name: "仅自己可见，没有分享链接",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository, repository.FollowRepository) {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetPubById(gomock.Any(), int64(1)).
+					Return(pub(domain.ArticleStatusPublished, domain.ArticleVisibilityPrivate), nil)
+				return repo, repomocks.NewMockFollowRepository(ctrl)
+			},
+			uid:     456,
+			wantErr: ErrArticleInvisible,
+		},
+		{
+			name: "仅自己可见，作者自己",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository, repository.FollowRepository) {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetPubById(gomock.Any(), int64(1)).
+					Return(pub(domain.ArticleStatusPublished, domain.ArticleVisibilityPrivate), nil)
+				return repo, repomocks.NewMockFollowRepository(ctrl)
+			},
+			uid: 123,
+		},
+		{
+			// 撤回之后分享链接也不行了
+			name: "撤回了",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository, repository.FollowRepository) {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetPubById(gomock.Any(), int64(1)).
+					Return(pub(domain.ArticleStatusPrivate, domain.ArticleVisibilityPublic), nil)
+				return repo, repomocks.NewMockFollowRepository(ctrl)
+			},
+			uid:     456,
+			shared:  true,
+			wantErr: ErrArticleInvisible,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, followRepo := tc.mock(ctrl)
+			producer := evtmocks.NewMockProducer(ctrl)
+			producer.EXPECT().ProduceReadEvent(article.ReadEvent{Aid: 1, Uid: tc.uid}).
+				Return(nil).AnyTimes()
+			svc := NewArticleService(repo, followRepo, producer, logger.NewNopLogger())
+			art, err := svc.GetPubById(context.Background(), 1, tc.uid, tc.shared)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, int64(1), art.Id)
+		})
+	}
+}
+
+func Test_articleService_ListPubByAuthor(t *testing.T) {
+	testCases := []struct {
+		name   string
+		mock   func(ctrl *gomock.Controller) (repository.ArticleRepository, repository.FollowRepository)
+		viewer int64
+	}{
+		{
+			name: "作者自己",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository, repository.FollowRepository) {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().ListPubByAuthor(gomock.Any(), int64(123), nil, 0, 10).Return(nil, nil)
+				return repo, repomocks.NewMockFollowRepository(ctrl)
+			},
+			viewer: 123,
+		},
+		{
+			name: "关注者",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository, repository.FollowRepository) {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().ListPubByAuthor(gomock.Any(), int64(123),
+					[]domain.ArticleVisibility{domain.ArticleVisibilityPublic, domain.ArticleVisibilityFollowers},
+					0, 10).Return(nil, nil)
+				followRepo := repomocks.NewMockFollowRepository(ctrl)
+				followRepo.EXPECT().FollowInfo(gomock.Any(), int64(456), int64(123)).
+					Return(domain.FollowRelation{Follower: 456, Followee: 123}, nil)
+				return repo, followRepo
+			},
+			viewer: 456,
+		},
+		{
+			name: "没有关注",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository, repository.FollowRepository) {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().ListPubByAuthor(gomock.Any(), int64(123),
+					[]domain.ArticleVisibility{domain.ArticleVisibilityPublic}, 0, 10).Return(nil, nil)
+				followRepo := repomocks.NewMockFollowRepository(ctrl)
+				followRepo.EXPECT().FollowInfo(gomock.Any(), int64(456), int64(123)).
+					Return(domain.FollowRelation{}, repository.ErrFollowRelationNotFound)
+				return repo, followRepo
+			},
+			viewer: 456,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, followRepo := tc.mock(ctrl)
+			svc := NewArticleService(repo, followRepo, nil, logger.NewNopLogger())
+			_, err := svc.ListPubByAuthor(context.Background(), 123, tc.viewer, 0, 10)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_articleService_CheckShare(t *testing.T) {
+	testCases := []struct {
+		name string
+		art  domain.Article
+
+		wantErr error
+	}{
+		{
+			name: "仅自己可见",
+			art: domain.Article{Id: 1, Author: domain.Author{Id: 123},
+				Status: domain.ArticleStatusPublished, Visibility: domain.ArticleVisibilityPrivate},
+		},
+		{
+			name: "别人的文章",
+			art: domain.Article{Id: 1, Author: domain.Author{Id: 456},
+				Status: domain.ArticleStatusPublished, Visibility: domain.ArticleVisibilityPrivate},
+			wantErr: ErrArticleInvisible,
+		},
+		{
+			// 公开的直接给链接就可以
+			name: "公开",
+			art: domain.Article{Id: 1, Author: domain.Author{Id: 123},
+				Status: domain.ArticleStatusPublished, Visibility: domain.ArticleVisibilityPublic},
+			wantErr: ErrArticleNotShareable,
+		},
+		{
+			name: "撤回了",
+			art: domain.Article{Id: 1, Author: domain.Author{Id: 123},
+				Status: domain.ArticleStatusPrivate, Visibility: domain.ArticleVisibilityUnlisted},
+			wantErr: ErrArticleNotShareable,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomocks.NewMockArticleRepository(ctrl)
+			repo.EXPECT().GetPubById(gomock.Any(), int64(1)).Return(tc.art, nil)
+			svc := NewArticleService(repo, nil, nil, logger.NewNopLogger())
+			err := svc.CheckShare(context.Background(), 123, 1)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_articleService_SetVisibility(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockArticleRepository(ctrl)
+	repo.EXPECT().SyncVisibility(gomock.Any(), int64(123), int64(1), domain.ArticleVisibilityPrivate).
+		Return(nil)
+	producer := evtmocks.NewMockProducer(ctrl)
+	// 搜索索引要把它去掉
+	producer.EXPECT().ProducePublishEvent(article.PublishEvent{Aid: 1, Uid: 123}).Return(nil)
+	svc := NewArticleService(repo, nil, producer, logger.NewNopLogger())
+
+	err := svc.SetVisibility(context.Background(), 123, 1, domain.ArticleVisibilityPrivate)
+	assert.NoError(t, err)
+	err = svc.SetVisibility(context.Background(), 123, 1, domain.ArticleVisibility(10))
+	assert.Equal(t, ErrInvalidVisibility, err)
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository"
+	"ddd_demo/pkg/imagex"
+	"ddd_demo/pkg/logger"
+	"ddd_demo/pkg/ossx"
+	"encoding/hex"
+	"errors"
+	"github.com/ecodeclub/ekit/slice"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+var (
+	ErrAttachmentNotFound       = repository.ErrAttachmentNotFound
+	ErrAttachmentTooLarge       = errors.New("附件太大了")
+	ErrAttachmentTypeNotAllowed = errors.New("不支持的附件类型")
+	// ErrInvalidAttachment 类型是对的，但是内容解析不了
+	ErrInvalidAttachment = errors.New("附件内容不合法")
+)
+
+const (
+	// MaxAttachmentSize 附件最大 10MB
+	MaxAttachmentSize = 10 << 20
+	// thumbSide 缩略图长边的像素，比这个小的图片不生成缩略图
+	thumbSide = 320
+	// maxAttachmentNameLen 文件名最多多少个字，超过的截掉
+	maxAttachmentNameLen = 255
+	// gcBatchSize 清理附件的时候一批查多少个
+	gcBatchSize = 100
+)
+
+// allowedAttachmentTypes 按照内容识别出来的类型，不信任客户端传的文件名和 Content-Type
+var allowedAttachmentTypes = map[string]bool{
+	imagex.MimeJPEG:   true,
+	imagex.MimePNG:    true,
+	imagex.MimeGIF:    true,
+	"application/pdf": true,
+}
+
+// attachmentRefPattern 文章内容里面引用附件的链接，和 web 层返回的 URL 对应
+var attachmentRefPattern = regexp.MustCompile(`/attachments/([0-9a-f]{64})`)
+
+//go:generate mockgen -source=./attachment.go -package=svcmocks -destination=./mocks/attachment.mock.go AttachmentService
+type AttachmentService interface {
+	// Upload 上传附件，图片会去掉 EXIF 并且生成缩略图。同样的内容只存一份，返回已有的那个
+	Upload(ctx context.Context, uid int64, name string, data []byte) (domain.Attachment, error)
+	// Open 读取附件的内容，thumb 为 true 的时候读缩略图，没有缩略图就读原图。调用方负责关闭
+	Open(ctx context.Context, key string, thumb bool) (domain.Attachment, io.ReadCloser, error)
+	// CollectGarbage 删除 before 之前上传、现在没有任何文章引用的附件，返回删了几个。定时任务调用
+	CollectGarbage(ctx context.Context, before time.Time) (int, error)
+}
+
+type attachmentService struct {
+	repo    repository.AttachmentRepository
+	artRepo repository.ArticleRepository
+	store   ossx.ObjectStore
+	l       logger.LoggerV1
+}
+
+func NewAttachmentService(repo repository.AttachmentRepository,
+	artRepo repository.ArticleRepository,
+	store ossx.ObjectStore,
+	l logger.LoggerV1) AttachmentService {
+	return &attachmentService{
+		repo:    repo,
+		artRepo: artRepo,
+		store:   store,
+		l:       l,
+	}
+}
+
+func (s *attachmentService) Upload(ctx context.Context, uid int64, name string, data []byte) (domain.Attachment, error) {
+	if len(data) == 0 {
+		return domain.Attachment{}, ErrInvalidAttachment
+	}
+	if len(data) > MaxAttachmentSize {
+		return domain.Attachment{}, ErrAttachmentTooLarge
+	}
+	mime := http.DetectContentType(data)
+	if !allowedAttachmentTypes[mime] {
+		return domain.Attachment{}, ErrAttachmentTypeNotAllowed
+	}
+	att := domain.Attachment{
+		Uid:      uid,
+		Name:     truncateName(name),
+		MimeType: mime,
+	}
+	var thumb []byte
+	if mime != "application/pdf" {
+		var err error
+		data, thumb, err = s.processImage(&att, data)
+		if err != nil {
+			return domain.Attachment{}, err
+		}
+	}
+	// key 用处理过之后的内容算，去掉 EXIF 之后一样的图片也是同一个
+	sum := sha256.Sum256(data)
+	att.Key = hex.EncodeToString(sum[:])
+	att.Size = int64(len(data))
+	// 先存对象再写数据库，中间失败了留下的对象会被下一次上传覆盖掉
+	if thumb != nil {
+		err := s.store.Put(ctx, att.ThumbKey(), bytes.NewReader(thumb), att.ThumbMimeType)
+		if err != nil {
+			return domain.Attachment{}, err
+		}
+	}
+	err := s.store.Put(ctx, att.Key, bytes.NewReader(data), mime)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	return s.repo.Save(ctx, att)
+}
+
+// processImage 去掉元数据，填上宽高，图片比较大的时候生成缩略图
+func (s *attachmentService) processImage(att *domain.Attachment, data []byte) ([]byte, []byte, error) {
+	data, err := imagex.Strip(data, att.MimeType)
+	if err != nil {
+		return nil, nil, s.imageErr(err)
+	}
+	att.Width, att.Height, err = imagex.Size(data)
+	if err != nil {
+		return nil, nil, s.imageErr(err)
+	}
+	if max(att.Width, att.Height) <= thumbSide {
+		return data, nil, nil
+	}
+	thumb, thumbMime, err := imagex.Thumbnail(data, att.MimeType, thumbSide)
+	if err != nil {
+		return nil, nil, s.imageErr(err)
+	}
+	att.ThumbMimeType = thumbMime
+	return data, thumb, nil
+}
+
+func (s *attachmentService) imageErr(err error) error {
+	if err == imagex.ErrTooLarge {
+		return ErrAttachmentTooLarge
+	}
+	return ErrInvalidAttachment
+}
+
+func (s *attachmentService) Open(ctx context.Context, key string, thumb bool) (domain.Attachment, io.ReadCloser, error) {
+	att, err := s.repo.FindByKey(ctx, key)
+	if err != nil {
+		return domain.Attachment{}, nil, err
+	}
+	objKey := att.Key
+	if thumb && att.HasThumb() {
+		objKey = att.ThumbKey()
+	}
+	body, err := s.store.Get(ctx, objKey)
+	if err == ossx.ErrObjectNotFound {
+		return domain.Attachment{}, nil, ErrAttachmentNotFound
+	}
+	return att, body, err
+}
+
+// CollectGarbage 按照 ID 把很久没有上传过的附件翻一遍，没有文章引用的删掉。
+// 删除之前会再确认一下 utime，这期间被重新上传过的不删。
+// 还有两个窗口没有处理：
+//  1. 查完引用之后、删除之前，有文章刚好引用了这个附件；
+//  2. 删掉记录之后、删掉对象之前，有人上传了同样的内容，记录又写回去了，但是对象被删了。
+//
+// before 离现在足够远的话，这两种情况都要求作者引用一个很早以前上传、一直没有用过的附件，概率很低
+func (s *attachmentService) CollectGarbage(ctx context.Context, before time.Time) (int, error) {
+	cnt := 0
+	var cursor int64
+	for {
+		atts, err := s.repo.ListStale(ctx, before, cursor, gcBatchSize)
+		if err != nil {
+			return cnt, err
+		}
+		if len(atts) == 0 {
+			return cnt, nil
+		}
+		cursor = atts[len(atts)-1].Id
+		keys := slice.Map(atts, func(idx int, src domain.Attachment) string {
+			return src.Key
+		})
+		refs, err := s.artRepo.ReferencedAttachments(ctx, keys)
+		if err != nil {
+			return cnt, err
+		}
+		for _, att := range atts {
+			if slice.Contains(refs, att.Key) {
+				continue
+			}
+			ok, err := s.repo.DeleteStale(ctx, att.Id, before)
+			if err != nil {
+				return cnt, err
+			}
+			if !ok {
+				continue
+			}
+			cnt++
+			s.deleteObjects(ctx, att)
+		}
+		if len(atts) < gcBatchSize {
+			return cnt, nil
+		}
+	}
+}
+
+// deleteObjects 记录已经删了，对象删不掉也只是多占一点空间，记录日志就可以
+func (s *attachmentService) deleteObjects(ctx context.Context, att domain.Attachment) {
+	keys := []string{att.Key}
+	if att.HasThumb() {
+		keys = append(keys, att.ThumbKey())
+	}
+	for _, key := range keys {
+		err := s.store.Delete(ctx, key)
+		if err != nil {
+			s.l.Error("删除附件对象失败",
+				logger.String("key", key),
+				logger.Error(err))
+		}
+	}
+}
+
+func truncateName(name string) string {
+	runes := []rune(name)
+	if len(runes) <= maxAttachmentNameLen {
+		return name
+	}
+	return string(runes[:maxAttachmentNameLen])
+}
+
+// attachmentKeys 文章内容里面引用了的附件，去掉重复的，保持出现的顺序
+func attachmentKeys(content string) []string {
+	matches := attachmentRefPattern.FindAllStringSubmatch(content, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+	res := make([]string, 0, len(matches))
+	seen := make(map[string]struct{}, len(matches))
+	for _, m := range matches {
+		if _, ok := seen[m[1]]; ok {
+			continue
+		}
+		seen[m[1]] = struct{}{}
+		res = append(res, m[1])
+	}
+	return res
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository"
+	repomocks "ddd_demo/internal/repository/mocks"
+	"ddd_demo/pkg/imagex"
+	"ddd_demo/pkg/logger"
+	"ddd_demo/pkg/ossx"
+	"encoding/hex"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_attachmentService_Upload(t *testing.T) {
+	bigPNG := newTestPNG(t, 640, 320)
+	smallPNG := newTestPNG(t, 10, 10)
+	pdf := []byte("%PDF-1.4\n%test\n")
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.AttachmentRepository
+		data []byte
+
+		wantAtt   domain.Attachment
+		wantThumb bool
+		wantErr   error
+	}{
+		{
+			name: "大图片生成缩略图",
+			mock: func(ctrl *gomock.Controller) repository.AttachmentRepository {
+				repo := repomocks.NewMockAttachmentRepository(ctrl)
+				repo.EXPECT().Save(gomock.Any(), domain.Attachment{
+					Key:           sha256Hex(bigPNG),
+					Uid:           123,
+					Name:          "a.png",
+					MimeType:      imagex.MimePNG,
+					Size:          int64(len(bigPNG)),
+					Width:         640,
+					Height:        320,
+					ThumbMimeType: imagex.MimePNG,
+				}).DoAndReturn(func(ctx context.Context, a domain.Attachment) (domain.Attachment, error) {
+					a.Id = 1
+					return a, nil
+				})
+				return repo
+			},
+			data: bigPNG,
+			wantAtt: domain.Attachment{
+				Id:            1,
+				Key:           sha256Hex(bigPNG),
+				Uid:           123,
+				Name:          "a.png",
+				MimeType:      imagex.MimePNG,
+				Size:          int64(len(bigPNG)),
+				Width:         640,
+				Height:        320,
+				ThumbMimeType: imagex.MimePNG,
+			},
+			wantThumb: true,
+		},
+		{
+			// 已经上传过了，返回原本的那个
+			name: "小图片不用缩略图",
+			mock: func(ctrl *gomock.Controller) repository.AttachmentRepository {
+				repo := repomocks.NewMockAttachmentRepository(ctrl)
+				repo.EXPECT().Save(gomock.Any(), gomock.Any()).
+					Return(domain.Attachment{Id: 2, Key: sha256Hex(smallPNG), Uid: 456,
+						Name: "old.png", MimeType: imagex.MimePNG}, nil)
+				return repo
+			},
+			data: smallPNG,
+			wantAtt: domain.Attachment{Id: 2, Key: sha256Hex(smallPNG), Uid: 456,
+				Name: "old.png", MimeType: imagex.MimePNG},
+		},
+		{
+			name: "PDF 原样保存",
+			mock: func(ctrl *gomock.Controller) repository.AttachmentRepository {
+				repo := repomocks.NewMockAttachmentRepository(ctrl)
+				repo.EXPECT().Save(gomock.Any(), domain.Attachment{
+					Key:      sha256Hex(pdf),
+					Uid:      123,
+					Name:     "a.png",
+					MimeType: "application/pdf",
+					Size:     int64(len(pdf)),
+				}).DoAndReturn(func(ctx context.Context, a domain.Attachment) (domain.Attachment, error) {
+					return a, nil
+				})
+				return repo
+			},
+			data: pdf,
+			wantAtt: domain.Attachment{
+				Key:      sha256Hex(pdf),
+				Uid:      123,
+				Name:     "a.png",
+				MimeType: "application/pdf",
+				Size:     int64(len(pdf)),
+			},
+		},
+		{
+			name: "太大",
+			mock: func(ctrl *gomock.Controller) repository.AttachmentRepository {
+				return repomocks.NewMockAttachmentRepository(ctrl)
+			},
+			data:    make([]byte, MaxAttachmentSize+1),
+			wantErr: ErrAttachmentTooLarge,
+		},
+		{
+			// 文件名是 png，但是内容不是
+			name: "不支持的类型",
+			mock: func(ctrl *gomock.Controller) repository.AttachmentRepository {
+				return repomocks.NewMockAttachmentRepository(ctrl)
+			},
+			data:    []byte("<html><script>alert(1)</script></html>"),
+			wantErr: ErrAttachmentTypeNotAllowed,
+		},
+		{
+			name: "图片坏了",
+			mock: func(ctrl *gomock.Controller) repository.AttachmentRepository {
+				return repomocks.NewMockAttachmentRepository(ctrl)
+			},
+			data:    bigPNG[:100],
+			wantErr: ErrInvalidAttachment,
+		},
+		{
+			name: "数据库错误",
+			mock: func(ctrl *gomock.Controller) repository.AttachmentRepository {
+				repo := repomocks.NewMockAttachmentRepository(ctrl)
+				repo.EXPECT().Save(gomock.Any(), gomock.Any()).
+					Return(domain.Attachment{}, errors.New("mock db 错误"))
+				return repo
+			},
+			data:    pdf,
+			wantErr: errors.New("mock db 错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := ossx.NewLocalStore(t.TempDir())
+			svc := NewAttachmentService(tc.mock(ctrl), nil, store, logger.NewNopLogger())
+			att, err := svc.Upload(context.Background(), 123, "a.png", tc.data)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantAtt, att)
+			// 对象已经存好了
+			assert.Equal(t, tc.data, readObject(t, store, att.Key))
+			_, err = store.Get(context.Background(), att.ThumbKey())
+			assert.Equal(t, tc.wantThumb, err == nil)
+		})
+	}
+}
+
+func Test_attachmentService_Open(t *testing.T) {
+	store := ossx.NewLocalStore(t.TempDir())
+	ctx := context.Background()
+	require.NoError(t, store.Put(ctx, "abc", strings.NewReader("原图"), imagex.MimePNG))
+	require.NoError(t, store.Put(ctx, "abc_thumb", strings.NewReader("缩略图"), imagex.MimePNG))
+	testCases := []struct {
+		name  string
+		mock  func(ctrl *gomock.Controller) repository.AttachmentRepository
+		thumb bool
+
+		wantBody string
+		wantErr  error
+	}{
+		{
+			name: "缩略图",
+			mock: func(ctrl *gomock.Controller) repository.AttachmentRepository {
+				repo := repomocks.NewMockAttachmentRepository(ctrl)
+				repo.EXPECT().FindByKey(gomock.Any(), "abc").
+					Return(domain.Attachment{Key: "abc", ThumbMimeType: imagex.MimePNG}, nil)
+				return repo
+			},
+			thumb:    true,
+			wantBody: "缩略图",
+		},
+		{
+			name: "没有缩略图就用原图",
+			mock: func(ctrl *gomock.Controller) repository.AttachmentRepository {
+				repo := repomocks.NewMockAttachmentRepository(ctrl)
+				repo.EXPECT().FindByKey(gomock.Any(), "abc").
+					Return(domain.Attachment{Key: "abc"}, nil)
+				return repo
+			},
+			thumb:    true,
+			wantBody: "原图",
+		},
+		{
+			name: "不存在",
+			mock: func(ctrl *gomock.Controller) repository.AttachmentRepository {
+				repo := repomocks.NewMockAttachmentRepository(ctrl)
+				repo.EXPECT().FindByKey(gomock.Any(), "abc").
+					Return(domain.Attachment{}, repository.ErrAttachmentNotFound)
+				return repo
+			},
+			wantErr: ErrAttachmentNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewAttachmentService(tc.mock(ctrl), nil, store, logger.NewNopLogger())
+			_, rc, err := svc.Open(ctx, "abc", tc.thumb)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			defer rc.Close()
+			body, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantBody, string(body))
+		})
+	}
+}
+
+func Test_attachmentService_CollectGarbage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	store := ossx.NewLocalStore(t.TempDir())
+	for _, key := range []string{"a", "b", "b_thumb", "c"} {
+		require.NoError(t, store.Put(ctx, key, strings.NewReader(key), imagex.MimePNG))
+	}
+	before := time.Now().Add(-time.Hour)
+	stale := []domain.Attachment{
+		{Id: 1, Key: "a"},
+		{Id: 2, Key: "b", ThumbMimeType: imagex.MimePNG},
+		{Id: 3, Key: "c"},
+	}
+	repo := repomocks.NewMockAttachmentRepository(ctrl)
+	artRepo := repomocks.NewMockArticleRepository(ctrl)
+	repo.EXPECT().ListStale(gomock.Any(), before, int64(0), gcBatchSize).Return(stale, nil)
+	// a 还有文章在用
+	artRepo.EXPECT().ReferencedAttachments(gomock.Any(), []string{"a", "b", "c"}).
+		Return([]string{"a"}, nil)
+	repo.EXPECT().DeleteStale(gomock.Any(), int64(2), before).Return(true, nil)
+	// c 刚刚又被上传了一次
+	repo.EXPECT().DeleteStale(gomock.Any(), int64(3), before).Return(false, nil)
+
+	svc := NewAttachmentService(repo, artRepo, store, logger.NewNopLogger())
+	cnt, err := svc.CollectGarbage(ctx, before)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cnt)
+	for key, exist := range map[string]bool{"a": true, "b": false, "b_thumb": false, "c": true} {
+		_, err = store.Get(ctx, key)
+		assert.Equal(t, exist, err == nil, key)
+	}
+}
+
+func Test_attachmentKeys(t *testing.T) {
+	a := strings.Repeat("a", 64)
+	b := strings.Repeat("b", 64)
+	content := "![图](/attachments/" + a + "/thumb)\n" +
+		"[文档](https://example.com/attachments/" + b + ")\n" +
+		"![又是图](/attachments/" + a + ")\n" +
+		"/attachments/not-a-key"
+	assert.Equal(t, []string{a, b}, attachmentKeys(content))
+	assert.Nil(t, attachmentKeys("没有附件"))
+}
+
+func newTestPNG(t *testing.T, w, h int) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h))))
+	return buf.Bytes()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func readObject(t *testing.T, store ossx.ObjectStore, key string) []byte {
+	rc, err := store.Get(context.Background(), key)
+	require.NoError(t, err)
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	return data
+}
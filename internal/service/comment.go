@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/events/comment"
+	"ddd_demo/internal/repository"
+	"ddd_demo/pkg/logger"
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrCommentNotFound  = repository.ErrCommentNotFound
+	ErrInvalidComment   = errors.New("评论内容不能为空，也不能太长")
+	ErrNotCommentAuthor = errors.New("不是评论的作者")
+	// ErrCommentBizNotFound 评论的文章不存在或者没有发表
+	ErrCommentBizNotFound = errors.New("评论的资源不存在")
+)
+
+const (
+	// maxCommentLen 评论最多多少个字
+	maxCommentLen = 1000
+	bizArticle    = "article"
+)
+
+//go:generate mockgen -source=./comment.go -package=svcmocks -destination=./mocks/comment.mock.go CommentService
+type CommentService interface {
+	// CreateComment 发表评论，ParentId 不为 0 就是回复 ParentId 那一条
+	CreateComment(ctx context.Context, c domain.Comment) (int64, error)
+	// DeleteComment 只有评论的人自己能删，已经删掉了直接返回成功
+	DeleteComment(ctx context.Context, uid int64, id int64) error
+	// GetComments 资源下面的顶级评论，uid 是当前用户，用来标记有没有点赞
+	GetComments(ctx context.Context, uid int64, biz string, bizId int64,
+		sort domain.CommentSort, cursor domain.CommentCursor, limit int) ([]domain.Comment, error)
+	// GetReplies 顶级评论下面的回复，先回复的在前面
+	GetReplies(ctx context.Context, uid int64, rootId int64, cursor int64, limit int) ([]domain.Comment, error)
+	Like(ctx context.Context, uid int64, id int64) error
+	CancelLike(ctx context.Context, uid int64, id int64) error
+}
+
+type commentService struct {
+	repo     repository.CommentRepository
+	artRepo  repository.ArticleRepository
+	producer comment.Producer
+	l        logger.LoggerV1
+}
+
+func NewCommentService(repo repository.CommentRepository,
+	artRepo repository.ArticleRepository,
+	producer comment.Producer,
+	l logger.LoggerV1) CommentService {
+	return &commentService{
+		repo:     repo,
+		artRepo:  artRepo,
+		producer: producer,
+		l:        l,
+	}
+}
+
+func (svc *commentService) CreateComment(ctx context.Context, c domain.Comment) (int64, error) {
+	c.Content = strings.TrimSpace(c.Content)
+	if c.Content == "" || utf8.RuneCountInString(c.Content) > maxCommentLen {
+		return 0, ErrInvalidComment
+	}
+	var parent domain.Comment
+	if c.ParentId > 0 {
+		var err error
+		parent, err = svc.repo.FindById(ctx, c.ParentId)
+		if err != nil {
+			return 0, err
+		}
+		// 回复的必须是同一篇文章下面的评论
+		if parent.Biz != c.Biz || parent.BizId != c.BizId {
+			return 0, ErrCommentNotFound
+		}
+		c.RootId = parent.RootId
+		if parent.IsRoot() {
+			c.RootId = parent.Id
+		}
+	}
+	err := svc.checkBiz(ctx, c.Biz, c.BizId)
+	if err != nil {
+		return 0, err
+	}
+	id, err := svc.repo.CreateComment(ctx, c)
+	if err != nil {
+		return 0, err
+	}
+	er := svc.producer.ProduceCreatedEvent(comment.CreatedEvent{
+		Id:        id,
+		Biz:       c.Biz,
+		BizId:     c.BizId,
+		Uid:       c.Uid,
+		RootId:    c.RootId,
+		ParentId:  c.ParentId,
+		ParentUid: parent.Uid,
+	})
+	if er != nil {
+		// 评论已经保存下来了，评论数会少算一条
+		svc.l.Error("发送评论消息失败",
+			logger.Int64("cid", id),
+			logger.Error(er))
+	}
+	return id, nil
+}
+
+// checkBiz 只能评论已经发表的文章
+func (svc *commentService) checkBiz(ctx context.Context, biz string, bizId int64) error {
+	if biz != bizArticle {
+		return ErrCommentBizNotFound
+	}
+	art, err := svc.artRepo.GetPubById(ctx, bizId)
+	if err == repository.ErrArticleNotFound {
+		return ErrCommentBizNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if art.Status != domain.ArticleStatusPublished {
+		return ErrCommentBizNotFound
+	}
+	return nil
+}
+
+func (svc *commentService) DeleteComment(ctx context.Context, uid int64, id int64) error {
+	c, err := svc.repo.FindById(ctx, id)
+	if err == ErrCommentNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if c.Uid != uid {
+		return ErrNotCommentAuthor
+	}
+	cnt, err := svc.repo.DeleteComment(ctx, c)
+	if err != nil || cnt == 0 {
+		// cnt 是 0 说明并发删除，别人已经发过消息了
+		return err
+	}
+	er := svc.producer.ProduceDeletedEvent(comment.DeletedEvent{
+		Id:    c.Id,
+		Biz:   c.Biz,
+		BizId: c.BizId,
+		Cnt:   cnt,
+	})
+	if er != nil {
+		svc.l.Error("发送删除评论消息失败",
+			logger.Int64("cid", id),
+			logger.Error(er))
+	}
+	return nil
+}
+
+func (svc *commentService) GetComments(ctx context.Context, uid int64, biz string, bizId int64,
+	sort domain.CommentSort, cursor domain.CommentCursor, limit int) ([]domain.Comment, error) {
+	cs, err := svc.repo.FindByBiz(ctx, biz, bizId, sort, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	svc.liked(ctx, uid, cs)
+	return cs, nil
+}
+
+func (svc *commentService) GetReplies(ctx context.Context,
+	uid int64, rootId int64, cursor int64, limit int) ([]domain.Comment, error) {
+	cs, err := svc.repo.FindReplies(ctx, rootId, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	svc.liked(ctx, uid, cs)
+	return cs, nil
+}
+
+// liked 查不到点赞状态就当没有点赞，不影响看评论
+func (svc *commentService) liked(ctx context.Context, uid int64, cs []domain.Comment) {
+	if len(cs) == 0 {
+		return
+	}
+	err := svc.repo.Liked(ctx, uid, cs)
+	if err != nil {
+		svc.l.Error("查询评论点赞状态失败",
+			logger.Int64("uid", uid),
+			logger.Error(err))
+	}
+}
+
+func (svc *commentService) Like(ctx context.Context, uid int64, id int64) error {
+	_, err := svc.repo.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+	return svc.repo.Like(ctx, uid, id)
+}
+
+func (svc *commentService) CancelLike(ctx context.Context, uid int64, id int64) error {
+	return svc.repo.CancelLike(ctx, uid, id)
+}
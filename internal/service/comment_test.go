@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/events/comment"
+	evtmocks "ddd_demo/internal/events/comment/mocks"
+	"ddd_demo/internal/repository"
+	repomocks "ddd_demo/internal/repository/mocks"
+	"ddd_demo/pkg/logger"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func Test_commentService_CreateComment(t *testing.T) {
+	published := domain.Article{Id: 1, Status: domain.ArticleStatusPublished}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.CommentRepository,
+			repository.ArticleRepository, comment.Producer)
+		c domain.Comment
+
+		wantId  int64
+		wantErr error
+	}{
+		{
+			name: "顶级评论",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository,
+				repository.ArticleRepository, comment.Producer) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				producer := evtmocks.NewMockProducer(ctrl)
+				artRepo.EXPECT().GetPubById(gomock.Any(), int64(1)).Return(published, nil)
+				repo.EXPECT().CreateComment(gomock.Any(), domain.Comment{
+					Biz: "article", BizId: 1, Uid: 123, Content: "写得好",
+				}).Return(int64(10), nil)
+				producer.EXPECT().ProduceCreatedEvent(comment.CreatedEvent{
+					Id: 10, Biz: "article", BizId: 1, Uid: 123,
+				}).Return(nil)
+				return repo, artRepo, producer
+			},
+			c:      domain.Comment{Biz: "article", BizId: 1, Uid: 123, Content: " 写得好 "},
+			wantId: 10,
+		},
+		{
+			// 回复的回复挂在同一个顶级评论下面
+			name: "回复别人的回复",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository,
+				repository.ArticleRepository, comment.Producer) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				producer := evtmocks.NewMockProducer(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(11)).
+					Return(domain.Comment{Id: 11, Biz: "article", BizId: 1,
+						Uid: 456, RootId: 10, ParentId: 10}, nil)
+				artRepo.EXPECT().GetPubById(gomock.Any(), int64(1)).Return(published, nil)
+				repo.EXPECT().CreateComment(gomock.Any(), domain.Comment{
+					Biz: "article", BizId: 1, Uid: 123, Content: "同意",
+					RootId: 10, ParentId: 11,
+				}).Return(int64(12), nil)
+				// 消息发送失败不影响评论
+				producer.EXPECT().ProduceCreatedEvent(comment.CreatedEvent{
+					Id: 12, Biz: "article", BizId: 1, Uid: 123,
+					RootId: 10, ParentId: 11, ParentUid: 456,
+				}).Return(errors.New("mock kafka 错误"))
+				return repo, artRepo, producer
+			},
+			c:      domain.Comment{Biz: "article", BizId: 1, Uid: 123, Content: "同意", ParentId: 11},
+			wantId: 12,
+		},
+		{
+			name: "回复的评论不在这篇文章下面",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository,
+				repository.ArticleRepository, comment.Producer) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(11)).
+					Return(domain.Comment{Id: 11, Biz: "article", BizId: 2}, nil)
+				return repo, repomocks.NewMockArticleRepository(ctrl), evtmocks.NewMockProducer(ctrl)
+			},
+			c:       domain.Comment{Biz: "article", BizId: 1, Uid: 123, Content: "同意", ParentId: 11},
+			wantErr: ErrCommentNotFound,
+		},
+		{
+			name: "文章已经撤回了",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository,
+				repository.ArticleRepository, comment.Producer) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPubById(gomock.Any(), int64(1)).
+					Return(domain.Article{Id: 1, Status: domain.ArticleStatusPrivate}, nil)
+				return repomocks.NewMockCommentRepository(ctrl), artRepo, evtmocks.NewMockProducer(ctrl)
+			},
+			c:       domain.Comment{Biz: "article", BizId: 1, Uid: 123, Content: "写得好"},
+			wantErr: ErrCommentBizNotFound,
+		},
+		{
+			name: "评论内容是空的",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository,
+				repository.ArticleRepository, comment.Producer) {
+				return repomocks.NewMockCommentRepository(ctrl),
+					repomocks.NewMockArticleRepository(ctrl), evtmocks.NewMockProducer(ctrl)
+			},
+			c:       domain.Comment{Biz: "article", BizId: 1, Uid: 123, Content: "  "},
+			wantErr: ErrInvalidComment,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, artRepo, producer := tc.mock(ctrl)
+			svc := NewCommentService(repo, artRepo, producer, logger.NewNopLogger())
+			id, err := svc.CreateComment(context.Background(), tc.c)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantId, id)
+		})
+	}
+}
+
+func Test_commentService_DeleteComment(t *testing.T) {
+	root := domain.Comment{Id: 10, Biz: "article", BizId: 1, Uid: 123}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.CommentRepository, comment.Producer)
+		uid  int64
+
+		wantErr error
+	}{
+		{
+			name: "连着回复一起删掉",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, comment.Producer) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				producer := evtmocks.NewMockProducer(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).Return(root, nil)
+				repo.EXPECT().DeleteComment(gomock.Any(), root).Return(int64(3), nil)
+				producer.EXPECT().ProduceDeletedEvent(comment.DeletedEvent{
+					Id: 10, Biz: "article", BizId: 1, Cnt: 3,
+				}).Return(nil)
+				return repo, producer
+			},
+			uid: 123,
+		},
+		{
+			name: "不是自己的评论",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, comment.Producer) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).Return(root, nil)
+				return repo, evtmocks.NewMockProducer(ctrl)
+			},
+			uid:     456,
+			wantErr: ErrNotCommentAuthor,
+		},
+		{
+			name: "已经删掉了",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, comment.Producer) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).
+					Return(domain.Comment{}, repository.ErrCommentNotFound)
+				return repo, evtmocks.NewMockProducer(ctrl)
+			},
+			uid: 123,
+		},
+		{
+			// 并发删除，另一个请求已经发过消息了
+			name: "并发删除",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, comment.Producer) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).Return(root, nil)
+				repo.EXPECT().DeleteComment(gomock.Any(), root).Return(int64(0), nil)
+				return repo, evtmocks.NewMockProducer(ctrl)
+			},
+			uid: 123,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, producer := tc.mock(ctrl)
+			svc := NewCommentService(repo, nil, producer, logger.NewNopLogger())
+			err := svc.DeleteComment(context.Background(), tc.uid, 10)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Service 不真的发邮件，而是把邮件写到 w 里面
+// w 可以是 os.Stdout，也可以是一个文件，测试的时候可以用 bytes.Buffer
+type Service struct {
+	mutex sync.Mutex
+	w     io.Writer
+}
+
+func NewService(w io.Writer) *Service {
+	return &Service{
+		w: w,
+	}
+}
+
+func (s *Service) Send(ctx context.Context, to, subject, content string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, err := fmt.Fprintf(s.w, "To: %s\nSubject: %s\n\n%s\n\n", to, subject, content)
+	return err
+}
@@ -0,0 +1,51 @@
+package smtp
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+// Service 通过 SMTP 发送邮件，大多数邮件服务商都支持
+type Service struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewService(host string, port int, username, password, from string) *Service {
+	return &Service{
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		from: from,
+		// PlainAuth 只会在 TLS 连接或者 localhost 上面发送密码
+		auth: smtp.PlainAuth("", username, password, host),
+	}
+}
+
+func (s *Service) Send(ctx context.Context, to, subject, content string) error {
+	// net/smtp 不支持 context，超时靠服务商那边的连接超时
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if strings.ContainsAny(to, "\r\n") {
+		return fmt.Errorf("非法的收件人 %q", to)
+	}
+	return smtp.SendMail(s.addr, s.auth, s.from, []string{to}, s.message(to, subject, content))
+}
+
+func (s *Service) message(to, subject, content string) []byte {
+	var sb strings.Builder
+	sb.WriteString("From: " + s.from + "\r\n")
+	sb.WriteString("To: " + to + "\r\n")
+	// 标题里面有中文，要编码一下
+	sb.WriteString("Subject: " + mime.BEncoding.Encode("UTF-8", subject) + "\r\n")
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	sb.WriteString("\r\n")
+	sb.WriteString(content)
+	return []byte(sb.String())
+}
@@ -0,0 +1,9 @@
+package email
+
+import "context"
+
+// Service 发送邮件的抽象
+// 屏蔽不同邮件服务商之间的区别
+type Service interface {
+	Send(ctx context.Context, to, subject, content string) error
+}
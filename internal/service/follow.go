@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/events/follow"
+	"ddd_demo/internal/repository"
+	"ddd_demo/pkg/logger"
+	"errors"
+)
+
+var ErrFollowSelf = errors.New("不能关注自己")
+
+//go:generate mockgen -source=./follow.go -package=svcmocks -destination=./mocks/follow.mock.go FollowService
+type FollowService interface {
+	// Follow 重复关注直接返回成功
+	Follow(ctx context.Context, follower int64, followee int64) error
+	// CancelFollow 没有关注过也直接返回成功
+	CancelFollow(ctx context.Context, follower int64, followee int64) error
+	// GetFollowers 粉丝列表，cursor 是上一页最后一条的 Id，第一页传 0
+	GetFollowers(ctx context.Context, uid int64, cursor int64, limit int) ([]domain.FollowRelation, error)
+	// GetFollowees 关注列表，分页方式和 GetFollowers 一样
+	GetFollowees(ctx context.Context, uid int64, cursor int64, limit int) ([]domain.FollowRelation, error)
+	// Followed follower 有没有关注 followee
+	Followed(ctx context.Context, follower int64, followee int64) (bool, error)
+	GetFollowStatics(ctx context.Context, uid int64) (domain.FollowStatics, error)
+}
+
+type followService struct {
+	repo     repository.FollowRepository
+	userRepo repository.UserRepository
+	producer follow.Producer
+	l        logger.LoggerV1
+}
+
+func NewFollowService(repo repository.FollowRepository,
+	userRepo repository.UserRepository,
+	producer follow.Producer,
+	l logger.LoggerV1) FollowService {
+	return &followService{
+		repo:     repo,
+		userRepo: userRepo,
+		producer: producer,
+		l:        l,
+	}
+}
+
+func (svc *followService) Follow(ctx context.Context, follower int64, followee int64) error {
+	if follower == followee {
+		return ErrFollowSelf
+	}
+	// 被关注的人必须存在，不存在返回 ErrUserNotFound
+	_, err := svc.userRepo.FindById(ctx, followee)
+	if err != nil {
+		return err
+	}
+	err = svc.repo.AddFollowRelation(ctx, follower, followee)
+	if err == repository.ErrFollowRelationExist {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	svc.produce(follow.FollowEvent{Follower: follower, Followee: followee, Follow: true})
+	return nil
+}
+
+func (svc *followService) CancelFollow(ctx context.Context, follower int64, followee int64) error {
+	err := svc.repo.RemoveFollowRelation(ctx, follower, followee)
+	if err == repository.ErrFollowRelationNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	svc.produce(follow.FollowEvent{Follower: follower, Followee: followee, Follow: false})
+	return nil
+}
+
+// produce 关注关系已经保存下来了，消息发送失败只记录日志
+func (svc *followService) produce(evt follow.FollowEvent) {
+	err := svc.producer.ProduceFollowEvent(evt)
+	if err != nil {
+		svc.l.Error("发送关注消息失败",
+			logger.Int64("follower", evt.Follower),
+			logger.Int64("followee", evt.Followee),
+			logger.Error(err))
+	}
+}
+
+func (svc *followService) GetFollowers(ctx context.Context,
+	uid int64, cursor int64, limit int) ([]domain.FollowRelation, error) {
+	return svc.repo.GetFollowers(ctx, uid, cursor, limit)
+}
+
+func (svc *followService) GetFollowees(ctx context.Context,
+	uid int64, cursor int64, limit int) ([]domain.FollowRelation, error) {
+	return svc.repo.GetFollowees(ctx, uid, cursor, limit)
+}
+
+func (svc *followService) Followed(ctx context.Context, follower int64, followee int64) (bool, error) {
+	_, err := svc.repo.FollowInfo(ctx, follower, followee)
+	switch err {
+	case nil:
+		return true, nil
+	case repository.ErrFollowRelationNotFound:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+func (svc *followService) GetFollowStatics(ctx context.Context, uid int64) (domain.FollowStatics, error) {
+	return svc.repo.GetFollowStatics(ctx, uid)
+}
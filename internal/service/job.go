@@ -5,12 +5,17 @@ import (
 	"ddd_demo/internal/domain"
 	"ddd_demo/internal/repository"
 	"ddd_demo/pkg/logger"
+	"errors"
 	"time"
 )
 
+var ErrNoMoreJob = repository.ErrNoMoreJob
+
 type JobService interface {
 	Preempt(ctx context.Context) (domain.Job, error)
 	ResetNextTime(ctx context.Context, j domain.Job) error
+	// AddJob 注册一个任务，同名的任务已经存在的话就什么都不做
+	AddJob(ctx context.Context, j domain.Job) error
 }
 
 type cronJobService struct {
@@ -19,23 +24,45 @@ type cronJobService struct {
 	l               logger.LoggerV1
 }
 
+func NewCronJobService(repo repository.JobRepository, l logger.LoggerV1) JobService {
+	return &cronJobService{
+		repo: repo,
+		l:    l,
+		// 续约失败的判定是三分钟没有更新，所以这里要比三分钟短很多
+		refreshInterval: time.Minute,
+	}
+}
+
+func (p *cronJobService) AddJob(ctx context.Context, j domain.Job) error {
+	return p.repo.AddJob(ctx, j)
+}
+
 func (p *cronJobService) Preempt(ctx context.Context) (domain.Job, error) {
 	j, err := p.repo.Preempt(ctx)
 	if err != nil {
-		p.l.Error("preempt job failed", logger.Error(err))
+		if !errors.Is(err, ErrNoMoreJob) {
+			p.l.Error("preempt job failed", logger.Error(err))
+		}
 		return domain.Job{}, err
 	}
 	ticker := time.NewTicker(p.refreshInterval)
+	ch := make(chan struct{})
 	go func() {
-		for range ticker.C {
-			p.refresh(j.Id)
+		for {
+			select {
+			case <-ticker.C:
+				p.refresh(j.Id)
+			case <-ch:
+				// ticker.Stop 不会关闭 ticker.C，所以要靠 ch 退出
+				return
+			}
 		}
 	}()
 
 	// 你抢占之后，你一直抢占着吗？
 	// 你要考虑一个释放的问题
 	j.CancelFunc = func() error {
-		//close(ch)
+		close(ch)
 		// 自己在这里释放掉
 		ticker.Stop()
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
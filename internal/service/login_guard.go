@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"ddd_demo/internal/repository"
+	"ddd_demo/pkg/limiter"
+	"ddd_demo/pkg/logger"
+	"errors"
+	"fmt"
+	"github.com/prometheus/client_golang/prometheus"
+	"time"
+)
+
+var (
+	ErrAccountLocked        = repository.ErrAccountLocked
+	ErrLoginTooManyRequests = errors.New("登录太频繁")
+)
+
+// LoginGuard 防止暴力破解密码。
+// 同一个 IP 的登录次数有限制；同一个账号或者同一个 IP 连续登录失败，需要等待的时间越来越长，
+// 账号失败次数太多会临时锁定账号，用手机验证码登录之后会解锁
+//
+//go:generate mockgen -source=./login_guard.go -package=svcmocks -destination=./mocks/login_guard.mock.go LoginGuard
+type LoginGuard interface {
+	// Check 登录之前调用，返回还需要等待多久才能重试。
+	// 账号被锁定返回 ErrAccountLocked，IP 登录太频繁返回 ErrLoginTooManyRequests
+	Check(ctx context.Context, email string, ip string) (time.Duration, error)
+	// Fail 密码错误的时候调用
+	Fail(ctx context.Context, email string, ip string) error
+	// Succeed 登录成功之后清空失败次数
+	Succeed(ctx context.Context, email string) error
+	// Unlock 通过了别的验证方式，比如说手机验证码，解锁账号
+	Unlock(ctx context.Context, email string) error
+}
+
+type loginGuard struct {
+	repo      repository.LoginAttemptRepository
+	ipLimiter limiter.Limiter
+	l         logger.LoggerV1
+	// event 标签：ip_limited, delayed, locked, unlocked
+	counter *prometheus.CounterVec
+}
+
+func NewLoginGuard(repo repository.LoginAttemptRepository,
+	ipLimiter limiter.Limiter,
+	l logger.LoggerV1,
+	counter *prometheus.CounterVec) LoginGuard {
+	return &loginGuard{
+		repo:      repo,
+		ipLimiter: ipLimiter,
+		l:         l,
+		counter:   counter,
+	}
+}
+
+func (g *loginGuard) Check(ctx context.Context, email string, ip string) (time.Duration, error) {
+	limited, err := g.ipLimiter.Limit(ctx, fmt.Sprintf("login:ip:%s", ip))
+	if err != nil {
+		return 0, err
+	}
+	if limited {
+		g.counter.WithLabelValues("ip_limited").Inc()
+		g.l.Warn("IP 登录太频繁", logger.String("ip", ip))
+		return 0, ErrLoginTooManyRequests
+	}
+	// 登录成功不会清空 IP 的失败次数，不然攻击者拿自己的账号登录一次就能重新开始
+	ipWait, err := g.repo.CheckIP(ctx, ip)
+	if err != nil {
+		return 0, err
+	}
+	wait, err := g.repo.Check(ctx, email)
+	if err != nil {
+		return 0, err
+	}
+	wait = max(wait, ipWait)
+	if wait > 0 {
+		g.counter.WithLabelValues("delayed").Inc()
+	}
+	return wait, nil
+}
+
+func (g *loginGuard) Fail(ctx context.Context, email string, ip string) error {
+	err := g.repo.FailIP(ctx, ip)
+	if err != nil {
+		return err
+	}
+	locked, err := g.repo.Fail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if locked {
+		g.counter.WithLabelValues("locked").Inc()
+		g.l.Warn("登录失败次数太多，锁定账号",
+			logger.String("email", email),
+			logger.String("ip", ip))
+	}
+	return nil
+}
+
+func (g *loginGuard) Succeed(ctx context.Context, email string) error {
+	return g.repo.Reset(ctx, email)
+}
+
+func (g *loginGuard) Unlock(ctx context.Context, email string) error {
+	_, err := g.repo.Check(ctx, email)
+	locked := err == ErrAccountLocked
+	err = g.repo.Reset(ctx, email)
+	if err != nil {
+		return err
+	}
+	if locked {
+		g.counter.WithLabelValues("unlocked").Inc()
+		g.l.Info("解锁账号", logger.String("email", email))
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"ddd_demo/internal/repository"
+	repomocks "ddd_demo/internal/repository/mocks"
+	"ddd_demo/pkg/limiter"
+	limitermocks "ddd_demo/pkg/limiter/mocks"
+	"ddd_demo/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestLoginGuard_Check(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.LoginAttemptRepository, limiter.Limiter)
+
+		wantWait time.Duration
+		wantErr  error
+	}{
+		{
+			name: "不需要等待",
+			mock: func(ctrl *gomock.Controller) (repository.LoginAttemptRepository, limiter.Limiter) {
+				l := limitermocks.NewMockLimiter(ctrl)
+				l.EXPECT().Limit(gomock.Any(), "login:ip:1.2.3.4").Return(false, nil)
+				repo := repomocks.NewMockLoginAttemptRepository(ctrl)
+				repo.EXPECT().CheckIP(gomock.Any(), "1.2.3.4").Return(time.Duration(0), nil)
+				repo.EXPECT().Check(gomock.Any(), "abc@qq.com").Return(time.Duration(0), nil)
+				return repo, l
+			},
+		},
+		{
+			// 拿同一个密码试很多个账号，每个账号都没有失败几次，但是 IP 失败很多次了
+			name: "IP 失败太多次",
+			mock: func(ctrl *gomock.Controller) (repository.LoginAttemptRepository, limiter.Limiter) {
+				l := limitermocks.NewMockLimiter(ctrl)
+				l.EXPECT().Limit(gomock.Any(), "login:ip:1.2.3.4").Return(false, nil)
+				repo := repomocks.NewMockLoginAttemptRepository(ctrl)
+				repo.EXPECT().CheckIP(gomock.Any(), "1.2.3.4").Return(time.Second*4, nil)
+				repo.EXPECT().Check(gomock.Any(), "abc@qq.com").Return(time.Second, nil)
+				return repo, l
+			},
+			wantWait: time.Second * 4,
+		},
+		{
+			name: "账号被锁定",
+			mock: func(ctrl *gomock.Controller) (repository.LoginAttemptRepository, limiter.Limiter) {
+				l := limitermocks.NewMockLimiter(ctrl)
+				l.EXPECT().Limit(gomock.Any(), "login:ip:1.2.3.4").Return(false, nil)
+				repo := repomocks.NewMockLoginAttemptRepository(ctrl)
+				repo.EXPECT().CheckIP(gomock.Any(), "1.2.3.4").Return(time.Second*4, nil)
+				repo.EXPECT().Check(gomock.Any(), "abc@qq.com").
+					Return(time.Duration(0), repository.ErrAccountLocked)
+				return repo, l
+			},
+			wantErr: ErrAccountLocked,
+		},
+		{
+			name: "IP 被限流",
+			mock: func(ctrl *gomock.Controller) (repository.LoginAttemptRepository, limiter.Limiter) {
+				l := limitermocks.NewMockLimiter(ctrl)
+				l.EXPECT().Limit(gomock.Any(), "login:ip:1.2.3.4").Return(true, nil)
+				return repomocks.NewMockLoginAttemptRepository(ctrl), l
+			},
+			wantErr: ErrLoginTooManyRequests,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, l := tc.mock(ctrl)
+			g := NewLoginGuard(repo, l, logger.NewNopLogger(),
+				prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"event"}))
+			wait, err := g.Check(context.Background(), "abc@qq.com", "1.2.3.4")
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantWait, wait)
+		})
+	}
+}
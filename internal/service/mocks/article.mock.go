@@ -1,16 +1,20 @@
 // Code generated by MockGen. DO NOT EDIT.
 // Source: ./article.go
-
+//
+// Generated by this command:
+//
+//	mockgen -source=./article.go -package=svcmocks -destination=./mocks/article.mock.go ArticleService
+//
 // Package svcmocks is a generated GoMock package.
 package svcmocks
 
 import (
 	context "context"
-	domain "ddd_demo/internal/domain"
 	reflect "reflect"
 	time "time"
 
-	gomock "github.com/golang/mock/gomock"
+	domain "ddd_demo/internal/domain"
+	gomock "go.uber.org/mock/gomock"
 )
 
 // MockArticleService is a mock of ArticleService interface.
@@ -36,6 +40,78 @@ func (m *MockArticleService) EXPECT() *MockArticleServiceMockRecorder {
 	return m.recorder
 }
 
+// AutoSave mocks base method.
+func (m *MockArticleService) AutoSave(ctx context.Context, draft domain.ArticleDraft) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AutoSave", ctx, draft)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AutoSave indicates an expected call of AutoSave.
+func (mr *MockArticleServiceMockRecorder) AutoSave(ctx, draft any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AutoSave", reflect.TypeOf((*MockArticleService)(nil).AutoSave), ctx, draft)
+}
+
+// CancelSchedule mocks base method.
+func (m *MockArticleService) CancelSchedule(ctx context.Context, uid, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelSchedule", ctx, uid, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelSchedule indicates an expected call of CancelSchedule.
+func (mr *MockArticleServiceMockRecorder) CancelSchedule(ctx, uid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSchedule", reflect.TypeOf((*MockArticleService)(nil).CancelSchedule), ctx, uid, id)
+}
+
+// CheckShare mocks base method.
+func (m *MockArticleService) CheckShare(ctx context.Context, uid, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckShare", ctx, uid, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckShare indicates an expected call of CheckShare.
+func (mr *MockArticleServiceMockRecorder) CheckShare(ctx, uid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckShare", reflect.TypeOf((*MockArticleService)(nil).CheckShare), ctx, uid, id)
+}
+
+// DiffRevisions mocks base method.
+func (m *MockArticleService) DiffRevisions(ctx context.Context, uid, id, from, to int64) (domain.ArticleRevisionDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffRevisions", ctx, uid, id, from, to)
+	ret0, _ := ret[0].(domain.ArticleRevisionDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffRevisions indicates an expected call of DiffRevisions.
+func (mr *MockArticleServiceMockRecorder) DiffRevisions(ctx, uid, id, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffRevisions", reflect.TypeOf((*MockArticleService)(nil).DiffRevisions), ctx, uid, id, from, to)
+}
+
+// FlushDrafts mocks base method.
+func (m *MockArticleService) FlushDrafts(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlushDrafts", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FlushDrafts indicates an expected call of FlushDrafts.
+func (mr *MockArticleServiceMockRecorder) FlushDrafts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlushDrafts", reflect.TypeOf((*MockArticleService)(nil).FlushDrafts), ctx)
+}
+
 // GetByAuthor mocks base method.
 func (m *MockArticleService) GetByAuthor(ctx context.Context, uid int64, offset, limit int) ([]domain.Article, error) {
 	m.ctrl.T.Helper()
@@ -46,11 +122,26 @@ func (m *MockArticleService) GetByAuthor(ctx context.Context, uid int64, offset,
 }
 
 // GetByAuthor indicates an expected call of GetByAuthor.
-func (mr *MockArticleServiceMockRecorder) GetByAuthor(ctx, uid, offset, limit interface{}) *gomock.Call {
+func (mr *MockArticleServiceMockRecorder) GetByAuthor(ctx, uid, offset, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAuthor", reflect.TypeOf((*MockArticleService)(nil).GetByAuthor), ctx, uid, offset, limit)
 }
 
+// GetByAuthorByCursor mocks base method.
+func (m *MockArticleService) GetByAuthorByCursor(ctx context.Context, uid int64, cursor domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByAuthorByCursor", ctx, uid, cursor, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByAuthorByCursor indicates an expected call of GetByAuthorByCursor.
+func (mr *MockArticleServiceMockRecorder) GetByAuthorByCursor(ctx, uid, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAuthorByCursor", reflect.TypeOf((*MockArticleService)(nil).GetByAuthorByCursor), ctx, uid, cursor, limit)
+}
+
 // GetById mocks base method.
 func (m *MockArticleService) GetById(ctx context.Context, id int64) (domain.Article, error) {
 	m.ctrl.T.Helper()
@@ -61,24 +152,24 @@ func (m *MockArticleService) GetById(ctx context.Context, id int64) (domain.Arti
 }
 
 // GetById indicates an expected call of GetById.
-func (mr *MockArticleServiceMockRecorder) GetById(ctx, id interface{}) *gomock.Call {
+func (mr *MockArticleServiceMockRecorder) GetById(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockArticleService)(nil).GetById), ctx, id)
 }
 
 // GetPubById mocks base method.
-func (m *MockArticleService) GetPubById(ctx context.Context, id, uid int64) (domain.Article, error) {
+func (m *MockArticleService) GetPubById(ctx context.Context, id, uid int64, shared bool) (domain.Article, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPubById", ctx, id, uid)
+	ret := m.ctrl.Call(m, "GetPubById", ctx, id, uid, shared)
 	ret0, _ := ret[0].(domain.Article)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPubById indicates an expected call of GetPubById.
-func (mr *MockArticleServiceMockRecorder) GetPubById(ctx, id, uid interface{}) *gomock.Call {
+func (mr *MockArticleServiceMockRecorder) GetPubById(ctx, id, uid, shared any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPubById", reflect.TypeOf((*MockArticleService)(nil).GetPubById), ctx, id, uid)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPubById", reflect.TypeOf((*MockArticleService)(nil).GetPubById), ctx, id, uid, shared)
 }
 
 // ListPub mocks base method.
@@ -91,11 +182,86 @@ func (m *MockArticleService) ListPub(ctx context.Context, start time.Time, offse
 }
 
 // ListPub indicates an expected call of ListPub.
-func (mr *MockArticleServiceMockRecorder) ListPub(ctx, start, offset, limit interface{}) *gomock.Call {
+func (mr *MockArticleServiceMockRecorder) ListPub(ctx, start, offset, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPub", reflect.TypeOf((*MockArticleService)(nil).ListPub), ctx, start, offset, limit)
 }
 
+// ListPubByAuthor mocks base method.
+func (m *MockArticleService) ListPubByAuthor(ctx context.Context, uid, viewer int64, offset, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPubByAuthor", ctx, uid, viewer, offset, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPubByAuthor indicates an expected call of ListPubByAuthor.
+func (mr *MockArticleServiceMockRecorder) ListPubByAuthor(ctx, uid, viewer, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPubByAuthor", reflect.TypeOf((*MockArticleService)(nil).ListPubByAuthor), ctx, uid, viewer, offset, limit)
+}
+
+// ListPubByCursor mocks base method.
+func (m *MockArticleService) ListPubByCursor(ctx context.Context, start time.Time, cursor domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPubByCursor", ctx, start, cursor, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPubByCursor indicates an expected call of ListPubByCursor.
+func (mr *MockArticleServiceMockRecorder) ListPubByCursor(ctx, start, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPubByCursor", reflect.TypeOf((*MockArticleService)(nil).ListPubByCursor), ctx, start, cursor, limit)
+}
+
+// ListPubByTag mocks base method.
+func (m *MockArticleService) ListPubByTag(ctx context.Context, tag string, cursor int64, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPubByTag", ctx, tag, cursor, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPubByTag indicates an expected call of ListPubByTag.
+func (mr *MockArticleServiceMockRecorder) ListPubByTag(ctx, tag, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPubByTag", reflect.TypeOf((*MockArticleService)(nil).ListPubByTag), ctx, tag, cursor, limit)
+}
+
+// ListRevisions mocks base method.
+func (m *MockArticleService) ListRevisions(ctx context.Context, uid, id int64, offset, limit int) ([]domain.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevisions", ctx, uid, id, offset, limit)
+	ret0, _ := ret[0].([]domain.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevisions indicates an expected call of ListRevisions.
+func (mr *MockArticleServiceMockRecorder) ListRevisions(ctx, uid, id, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevisions", reflect.TypeOf((*MockArticleService)(nil).ListRevisions), ctx, uid, id, offset, limit)
+}
+
+// ListTags mocks base method.
+func (m *MockArticleService) ListTags(ctx context.Context, limit int) ([]domain.TagCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTags", ctx, limit)
+	ret0, _ := ret[0].([]domain.TagCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTags indicates an expected call of ListTags.
+func (mr *MockArticleServiceMockRecorder) ListTags(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTags", reflect.TypeOf((*MockArticleService)(nil).ListTags), ctx, limit)
+}
+
 // Publish mocks base method.
 func (m *MockArticleService) Publish(ctx context.Context, art domain.Article) (int64, error) {
 	m.ctrl.T.Helper()
@@ -106,11 +272,53 @@ func (m *MockArticleService) Publish(ctx context.Context, art domain.Article) (i
 }
 
 // Publish indicates an expected call of Publish.
-func (mr *MockArticleServiceMockRecorder) Publish(ctx, art interface{}) *gomock.Call {
+func (mr *MockArticleServiceMockRecorder) Publish(ctx, art any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockArticleService)(nil).Publish), ctx, art)
 }
 
+// PublishDue mocks base method.
+func (m *MockArticleService) PublishDue(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishDue", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishDue indicates an expected call of PublishDue.
+func (mr *MockArticleServiceMockRecorder) PublishDue(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishDue", reflect.TypeOf((*MockArticleService)(nil).PublishDue), ctx)
+}
+
+// Reschedule mocks base method.
+func (m *MockArticleService) Reschedule(ctx context.Context, uid, id int64, publishAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reschedule", ctx, uid, id, publishAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reschedule indicates an expected call of Reschedule.
+func (mr *MockArticleServiceMockRecorder) Reschedule(ctx, uid, id, publishAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reschedule", reflect.TypeOf((*MockArticleService)(nil).Reschedule), ctx, uid, id, publishAt)
+}
+
+// RestoreRevision mocks base method.
+func (m *MockArticleService) RestoreRevision(ctx context.Context, uid, id, version int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreRevision", ctx, uid, id, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreRevision indicates an expected call of RestoreRevision.
+func (mr *MockArticleServiceMockRecorder) RestoreRevision(ctx, uid, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRevision", reflect.TypeOf((*MockArticleService)(nil).RestoreRevision), ctx, uid, id, version)
+}
+
 // Save mocks base method.
 func (m *MockArticleService) Save(ctx context.Context, art domain.Article) (int64, error) {
 	m.ctrl.T.Helper()
@@ -121,11 +329,40 @@ func (m *MockArticleService) Save(ctx context.Context, art domain.Article) (int6
 }
 
 // Save indicates an expected call of Save.
-func (mr *MockArticleServiceMockRecorder) Save(ctx, art interface{}) *gomock.Call {
+func (mr *MockArticleServiceMockRecorder) Save(ctx, art any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockArticleService)(nil).Save), ctx, art)
 }
 
+// Schedule mocks base method.
+func (m *MockArticleService) Schedule(ctx context.Context, art domain.Article) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Schedule", ctx, art)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Schedule indicates an expected call of Schedule.
+func (mr *MockArticleServiceMockRecorder) Schedule(ctx, art any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Schedule", reflect.TypeOf((*MockArticleService)(nil).Schedule), ctx, art)
+}
+
+// SetVisibility mocks base method.
+func (m *MockArticleService) SetVisibility(ctx context.Context, uid, id int64, visibility domain.ArticleVisibility) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetVisibility", ctx, uid, id, visibility)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetVisibility indicates an expected call of SetVisibility.
+func (mr *MockArticleServiceMockRecorder) SetVisibility(ctx, uid, id, visibility any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVisibility", reflect.TypeOf((*MockArticleService)(nil).SetVisibility), ctx, uid, id, visibility)
+}
+
 // Withdraw mocks base method.
 func (m *MockArticleService) Withdraw(ctx context.Context, uid, id int64) error {
 	m.ctrl.T.Helper()
@@ -135,7 +372,7 @@ func (m *MockArticleService) Withdraw(ctx context.Context, uid, id int64) error
 }
 
 // Withdraw indicates an expected call of Withdraw.
-func (mr *MockArticleServiceMockRecorder) Withdraw(ctx, uid, id interface{}) *gomock.Call {
+func (mr *MockArticleServiceMockRecorder) Withdraw(ctx, uid, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockArticleService)(nil).Withdraw), ctx, uid, id)
 }
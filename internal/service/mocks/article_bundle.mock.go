@@ -0,0 +1,84 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./article_bundle.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./article_bundle.go -package=svcmocks -destination=./mocks/article_bundle.mock.go ArticleBundleService
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+
+	domain "ddd_demo/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockArticleBundleService is a mock of ArticleBundleService interface.
+type MockArticleBundleService struct {
+	ctrl     *gomock.Controller
+	recorder *MockArticleBundleServiceMockRecorder
+}
+
+// MockArticleBundleServiceMockRecorder is the mock recorder for MockArticleBundleService.
+type MockArticleBundleServiceMockRecorder struct {
+	mock *MockArticleBundleService
+}
+
+// NewMockArticleBundleService creates a new mock instance.
+func NewMockArticleBundleService(ctrl *gomock.Controller) *MockArticleBundleService {
+	mock := &MockArticleBundleService{ctrl: ctrl}
+	mock.recorder = &MockArticleBundleServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArticleBundleService) EXPECT() *MockArticleBundleServiceMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockArticleBundleService) Export(ctx context.Context, uid int64, format domain.ArticleBundleFormat, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, uid, format, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockArticleBundleServiceMockRecorder) Export(ctx, uid, format, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockArticleBundleService)(nil).Export), ctx, uid, format, w)
+}
+
+// Import mocks base method.
+func (m *MockArticleBundleService) Import(ctx context.Context, uid int64, format domain.ArticleBundleFormat, data []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, uid, format, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockArticleBundleServiceMockRecorder) Import(ctx, uid, format, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockArticleBundleService)(nil).Import), ctx, uid, format, data)
+}
+
+// ImportStatus mocks base method.
+func (m *MockArticleBundleService) ImportStatus(ctx context.Context, uid int64) (domain.ArticleImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportStatus", ctx, uid)
+	ret0, _ := ret[0].(domain.ArticleImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportStatus indicates an expected call of ImportStatus.
+func (mr *MockArticleBundleServiceMockRecorder) ImportStatus(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportStatus", reflect.TypeOf((*MockArticleBundleService)(nil).ImportStatus), ctx, uid)
+}
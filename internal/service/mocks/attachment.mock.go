@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./attachment.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./attachment.go -package=svcmocks -destination=./mocks/attachment.mock.go AttachmentService
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+	time "time"
+
+	domain "ddd_demo/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAttachmentService is a mock of AttachmentService interface.
+type MockAttachmentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachmentServiceMockRecorder
+}
+
+// MockAttachmentServiceMockRecorder is the mock recorder for MockAttachmentService.
+type MockAttachmentServiceMockRecorder struct {
+	mock *MockAttachmentService
+}
+
+// NewMockAttachmentService creates a new mock instance.
+func NewMockAttachmentService(ctrl *gomock.Controller) *MockAttachmentService {
+	mock := &MockAttachmentService{ctrl: ctrl}
+	mock.recorder = &MockAttachmentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttachmentService) EXPECT() *MockAttachmentServiceMockRecorder {
+	return m.recorder
+}
+
+// CollectGarbage mocks base method.
+func (m *MockAttachmentService) CollectGarbage(ctx context.Context, before time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectGarbage", ctx, before)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CollectGarbage indicates an expected call of CollectGarbage.
+func (mr *MockAttachmentServiceMockRecorder) CollectGarbage(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectGarbage", reflect.TypeOf((*MockAttachmentService)(nil).CollectGarbage), ctx, before)
+}
+
+// Open mocks base method.
+func (m *MockAttachmentService) Open(ctx context.Context, key string, thumb bool) (domain.Attachment, io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", ctx, key, thumb)
+	ret0, _ := ret[0].(domain.Attachment)
+	ret1, _ := ret[1].(io.ReadCloser)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockAttachmentServiceMockRecorder) Open(ctx, key, thumb any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockAttachmentService)(nil).Open), ctx, key, thumb)
+}
+
+// Upload mocks base method.
+func (m *MockAttachmentService) Upload(ctx context.Context, uid int64, name string, data []byte) (domain.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upload", ctx, uid, name, data)
+	ret0, _ := ret[0].(domain.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upload indicates an expected call of Upload.
+func (mr *MockAttachmentServiceMockRecorder) Upload(ctx, uid, name, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockAttachmentService)(nil).Upload), ctx, uid, name, data)
+}
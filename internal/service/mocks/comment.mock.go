@@ -0,0 +1,127 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./comment.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./comment.go -package=svcmocks -destination=./mocks/comment.mock.go CommentService
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "ddd_demo/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCommentService is a mock of CommentService interface.
+type MockCommentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommentServiceMockRecorder
+}
+
+// MockCommentServiceMockRecorder is the mock recorder for MockCommentService.
+type MockCommentServiceMockRecorder struct {
+	mock *MockCommentService
+}
+
+// NewMockCommentService creates a new mock instance.
+func NewMockCommentService(ctrl *gomock.Controller) *MockCommentService {
+	mock := &MockCommentService{ctrl: ctrl}
+	mock.recorder = &MockCommentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCommentService) EXPECT() *MockCommentServiceMockRecorder {
+	return m.recorder
+}
+
+// CancelLike mocks base method.
+func (m *MockCommentService) CancelLike(ctx context.Context, uid, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelLike", ctx, uid, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelLike indicates an expected call of CancelLike.
+func (mr *MockCommentServiceMockRecorder) CancelLike(ctx, uid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelLike", reflect.TypeOf((*MockCommentService)(nil).CancelLike), ctx, uid, id)
+}
+
+// CreateComment mocks base method.
+func (m *MockCommentService) CreateComment(ctx context.Context, c domain.Comment) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateComment", ctx, c)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateComment indicates an expected call of CreateComment.
+func (mr *MockCommentServiceMockRecorder) CreateComment(ctx, c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockCommentService)(nil).CreateComment), ctx, c)
+}
+
+// DeleteComment mocks base method.
+func (m *MockCommentService) DeleteComment(ctx context.Context, uid, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteComment", ctx, uid, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteComment indicates an expected call of DeleteComment.
+func (mr *MockCommentServiceMockRecorder) DeleteComment(ctx, uid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockCommentService)(nil).DeleteComment), ctx, uid, id)
+}
+
+// GetComments mocks base method.
+func (m *MockCommentService) GetComments(ctx context.Context, uid int64, biz string, bizId int64, sort domain.CommentSort, cursor domain.CommentCursor, limit int) ([]domain.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComments", ctx, uid, biz, bizId, sort, cursor, limit)
+	ret0, _ := ret[0].([]domain.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetComments indicates an expected call of GetComments.
+func (mr *MockCommentServiceMockRecorder) GetComments(ctx, uid, biz, bizId, sort, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComments", reflect.TypeOf((*MockCommentService)(nil).GetComments), ctx, uid, biz, bizId, sort, cursor, limit)
+}
+
+// GetReplies mocks base method.
+func (m *MockCommentService) GetReplies(ctx context.Context, uid, rootId, cursor int64, limit int) ([]domain.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReplies", ctx, uid, rootId, cursor, limit)
+	ret0, _ := ret[0].([]domain.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReplies indicates an expected call of GetReplies.
+func (mr *MockCommentServiceMockRecorder) GetReplies(ctx, uid, rootId, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplies", reflect.TypeOf((*MockCommentService)(nil).GetReplies), ctx, uid, rootId, cursor, limit)
+}
+
+// Like mocks base method.
+func (m *MockCommentService) Like(ctx context.Context, uid, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Like", ctx, uid, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Like indicates an expected call of Like.
+func (mr *MockCommentServiceMockRecorder) Like(ctx, uid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Like", reflect.TypeOf((*MockCommentService)(nil).Like), ctx, uid, id)
+}
@@ -0,0 +1,128 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./follow.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./follow.go -package=svcmocks -destination=./mocks/follow.mock.go
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "ddd_demo/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFollowService is a mock of FollowService interface.
+type MockFollowService struct {
+	ctrl     *gomock.Controller
+	recorder *MockFollowServiceMockRecorder
+}
+
+// MockFollowServiceMockRecorder is the mock recorder for MockFollowService.
+type MockFollowServiceMockRecorder struct {
+	mock *MockFollowService
+}
+
+// NewMockFollowService creates a new mock instance.
+func NewMockFollowService(ctrl *gomock.Controller) *MockFollowService {
+	mock := &MockFollowService{ctrl: ctrl}
+	mock.recorder = &MockFollowServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFollowService) EXPECT() *MockFollowServiceMockRecorder {
+	return m.recorder
+}
+
+// CancelFollow mocks base method.
+func (m *MockFollowService) CancelFollow(ctx context.Context, follower, followee int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelFollow", ctx, follower, followee)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelFollow indicates an expected call of CancelFollow.
+func (mr *MockFollowServiceMockRecorder) CancelFollow(ctx, follower, followee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelFollow", reflect.TypeOf((*MockFollowService)(nil).CancelFollow), ctx, follower, followee)
+}
+
+// Follow mocks base method.
+func (m *MockFollowService) Follow(ctx context.Context, follower, followee int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Follow", ctx, follower, followee)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Follow indicates an expected call of Follow.
+func (mr *MockFollowServiceMockRecorder) Follow(ctx, follower, followee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Follow", reflect.TypeOf((*MockFollowService)(nil).Follow), ctx, follower, followee)
+}
+
+// Followed mocks base method.
+func (m *MockFollowService) Followed(ctx context.Context, follower, followee int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Followed", ctx, follower, followee)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Followed indicates an expected call of Followed.
+func (mr *MockFollowServiceMockRecorder) Followed(ctx, follower, followee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Followed", reflect.TypeOf((*MockFollowService)(nil).Followed), ctx, follower, followee)
+}
+
+// GetFollowStatics mocks base method.
+func (m *MockFollowService) GetFollowStatics(ctx context.Context, uid int64) (domain.FollowStatics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowStatics", ctx, uid)
+	ret0, _ := ret[0].(domain.FollowStatics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFollowStatics indicates an expected call of GetFollowStatics.
+func (mr *MockFollowServiceMockRecorder) GetFollowStatics(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowStatics", reflect.TypeOf((*MockFollowService)(nil).GetFollowStatics), ctx, uid)
+}
+
+// GetFollowees mocks base method.
+func (m *MockFollowService) GetFollowees(ctx context.Context, uid, cursor int64, limit int) ([]domain.FollowRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowees", ctx, uid, cursor, limit)
+	ret0, _ := ret[0].([]domain.FollowRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFollowees indicates an expected call of GetFollowees.
+func (mr *MockFollowServiceMockRecorder) GetFollowees(ctx, uid, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowees", reflect.TypeOf((*MockFollowService)(nil).GetFollowees), ctx, uid, cursor, limit)
+}
+
+// GetFollowers mocks base method.
+func (m *MockFollowService) GetFollowers(ctx context.Context, uid, cursor int64, limit int) ([]domain.FollowRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowers", ctx, uid, cursor, limit)
+	ret0, _ := ret[0].([]domain.FollowRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFollowers indicates an expected call of GetFollowers.
+func (mr *MockFollowServiceMockRecorder) GetFollowers(ctx, uid, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowers", reflect.TypeOf((*MockFollowService)(nil).GetFollowers), ctx, uid, cursor, limit)
+}
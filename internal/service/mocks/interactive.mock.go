@@ -93,6 +93,22 @@ func (mr *MockInteractiveServiceMockRecorder) GetByIds(ctx, biz, ids interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIds", reflect.TypeOf((*MockInteractiveService)(nil).GetByIds), ctx, biz, ids)
 }
 
+// ExportUser mocks base method.
+func (m *MockInteractiveService) ExportUser(ctx context.Context, uid int64) ([]domain.UserLike, []domain.UserCollection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportUser", ctx, uid)
+	ret0, _ := ret[0].([]domain.UserLike)
+	ret1, _ := ret[1].([]domain.UserCollection)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ExportUser indicates an expected call of ExportUser.
+func (mr *MockInteractiveServiceMockRecorder) ExportUser(ctx, uid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportUser", reflect.TypeOf((*MockInteractiveService)(nil).ExportUser), ctx, uid)
+}
+
 // IncrReadCnt mocks base method.
 func (m *MockInteractiveService) IncrReadCnt(ctx context.Context, biz string, bizId int64) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,97 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./login_guard.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./login_guard.go -package=svcmocks -destination=./mocks/login_guard.mock.go LoginGuard
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLoginGuard is a mock of LoginGuard interface.
+type MockLoginGuard struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginGuardMockRecorder
+}
+
+// MockLoginGuardMockRecorder is the mock recorder for MockLoginGuard.
+type MockLoginGuardMockRecorder struct {
+	mock *MockLoginGuard
+}
+
+// NewMockLoginGuard creates a new mock instance.
+func NewMockLoginGuard(ctrl *gomock.Controller) *MockLoginGuard {
+	mock := &MockLoginGuard{ctrl: ctrl}
+	mock.recorder = &MockLoginGuardMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginGuard) EXPECT() *MockLoginGuardMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockLoginGuard) Check(ctx context.Context, email, ip string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, email, ip)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockLoginGuardMockRecorder) Check(ctx, email, ip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockLoginGuard)(nil).Check), ctx, email, ip)
+}
+
+// Fail mocks base method.
+func (m *MockLoginGuard) Fail(ctx context.Context, email, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fail", ctx, email, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Fail indicates an expected call of Fail.
+func (mr *MockLoginGuardMockRecorder) Fail(ctx, email, ip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fail", reflect.TypeOf((*MockLoginGuard)(nil).Fail), ctx, email, ip)
+}
+
+// Succeed mocks base method.
+func (m *MockLoginGuard) Succeed(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Succeed", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Succeed indicates an expected call of Succeed.
+func (mr *MockLoginGuardMockRecorder) Succeed(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Succeed", reflect.TypeOf((*MockLoginGuard)(nil).Succeed), ctx, email)
+}
+
+// Unlock mocks base method.
+func (m *MockLoginGuard) Unlock(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockLoginGuardMockRecorder) Unlock(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockLoginGuard)(nil).Unlock), ctx, email)
+}
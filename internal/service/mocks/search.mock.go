@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./search.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./search.go -package=svcmocks -destination=./mocks/search.mock.go SearchService
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "ddd_demo/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSearchService is a mock of SearchService interface.
+type MockSearchService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSearchServiceMockRecorder
+}
+
+// MockSearchServiceMockRecorder is the mock recorder for MockSearchService.
+type MockSearchServiceMockRecorder struct {
+	mock *MockSearchService
+}
+
+// NewMockSearchService creates a new mock instance.
+func NewMockSearchService(ctrl *gomock.Controller) *MockSearchService {
+	mock := &MockSearchService{ctrl: ctrl}
+	mock.recorder = &MockSearchServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSearchService) EXPECT() *MockSearchServiceMockRecorder {
+	return m.recorder
+}
+
+// IndexArticle mocks base method.
+func (m *MockSearchService) IndexArticle(ctx context.Context, aid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IndexArticle", ctx, aid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IndexArticle indicates an expected call of IndexArticle.
+func (mr *MockSearchServiceMockRecorder) IndexArticle(ctx, aid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexArticle", reflect.TypeOf((*MockSearchService)(nil).IndexArticle), ctx, aid)
+}
+
+// Rebuild mocks base method.
+func (m *MockSearchService) Rebuild(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rebuild", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rebuild indicates an expected call of Rebuild.
+func (mr *MockSearchServiceMockRecorder) Rebuild(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rebuild", reflect.TypeOf((*MockSearchService)(nil).Rebuild), ctx)
+}
+
+// RemoveAuthor mocks base method.
+func (m *MockSearchService) RemoveAuthor(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAuthor", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAuthor indicates an expected call of RemoveAuthor.
+func (mr *MockSearchServiceMockRecorder) RemoveAuthor(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAuthor", reflect.TypeOf((*MockSearchService)(nil).RemoveAuthor), ctx, uid)
+}
+
+// SearchArticles mocks base method.
+func (m *MockSearchService) SearchArticles(ctx context.Context, query string, offset, limit int) (domain.ArticleSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchArticles", ctx, query, offset, limit)
+	ret0, _ := ret[0].(domain.ArticleSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchArticles indicates an expected call of SearchArticles.
+func (mr *MockSearchServiceMockRecorder) SearchArticles(ctx, query, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchArticles", reflect.TypeOf((*MockSearchService)(nil).SearchArticles), ctx, query, offset, limit)
+}
+
+// TransferAuthor mocks base method.
+func (m *MockSearchService) TransferAuthor(ctx context.Context, from, to int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferAuthor", ctx, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TransferAuthor indicates an expected call of TransferAuthor.
+func (mr *MockSearchServiceMockRecorder) TransferAuthor(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferAuthor", reflect.TypeOf((*MockSearchService)(nil).TransferAuthor), ctx, from, to)
+}
@@ -0,0 +1,128 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./series.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./series.go -package=svcmocks -destination=./mocks/series.mock.go SeriesService
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "ddd_demo/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSeriesService is a mock of SeriesService interface.
+type MockSeriesService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSeriesServiceMockRecorder
+}
+
+// MockSeriesServiceMockRecorder is the mock recorder for MockSeriesService.
+type MockSeriesServiceMockRecorder struct {
+	mock *MockSeriesService
+}
+
+// NewMockSeriesService creates a new mock instance.
+func NewMockSeriesService(ctrl *gomock.Controller) *MockSeriesService {
+	mock := &MockSeriesService{ctrl: ctrl}
+	mock.recorder = &MockSeriesServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSeriesService) EXPECT() *MockSeriesServiceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockSeriesService) Delete(ctx context.Context, uid, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, uid, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSeriesServiceMockRecorder) Delete(ctx, uid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSeriesService)(nil).Delete), ctx, uid, id)
+}
+
+// ListByAuthor mocks base method.
+func (m *MockSeriesService) ListByAuthor(ctx context.Context, uid int64) ([]domain.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByAuthor", ctx, uid)
+	ret0, _ := ret[0].([]domain.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByAuthor indicates an expected call of ListByAuthor.
+func (mr *MockSeriesServiceMockRecorder) ListByAuthor(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByAuthor", reflect.TypeOf((*MockSeriesService)(nil).ListByAuthor), ctx, uid)
+}
+
+// Nav mocks base method.
+func (m *MockSeriesService) Nav(ctx context.Context, aid, viewer int64) (domain.SeriesNav, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Nav", ctx, aid, viewer)
+	ret0, _ := ret[0].(domain.SeriesNav)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Nav indicates an expected call of Nav.
+func (mr *MockSeriesServiceMockRecorder) Nav(ctx, aid, viewer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Nav", reflect.TypeOf((*MockSeriesService)(nil).Nav), ctx, aid, viewer)
+}
+
+// Save mocks base method.
+func (m *MockSeriesService) Save(ctx context.Context, s domain.Series) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, s)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockSeriesServiceMockRecorder) Save(ctx, s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockSeriesService)(nil).Save), ctx, s)
+}
+
+// SetArticles mocks base method.
+func (m *MockSeriesService) SetArticles(ctx context.Context, uid, id int64, aids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetArticles", ctx, uid, id, aids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetArticles indicates an expected call of SetArticles.
+func (mr *MockSeriesServiceMockRecorder) SetArticles(ctx, uid, id, aids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetArticles", reflect.TypeOf((*MockSeriesService)(nil).SetArticles), ctx, uid, id, aids)
+}
+
+// Toc mocks base method.
+func (m *MockSeriesService) Toc(ctx context.Context, id, viewer int64) (domain.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Toc", ctx, id, viewer)
+	ret0, _ := ret[0].(domain.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Toc indicates an expected call of Toc.
+func (mr *MockSeriesServiceMockRecorder) Toc(ctx, id, viewer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Toc", reflect.TypeOf((*MockSeriesService)(nil).Toc), ctx, id, viewer)
+}
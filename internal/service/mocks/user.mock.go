@@ -39,6 +39,121 @@ func (m *MockUserService) EXPECT() *MockUserServiceMockRecorder {
 	return m.recorder
 }
 
+// ActivateTOTP mocks base method.
+func (m *MockUserService) ActivateTOTP(ctx context.Context, uid int64, code string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActivateTOTP", ctx, uid, code)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActivateTOTP indicates an expected call of ActivateTOTP.
+func (mr *MockUserServiceMockRecorder) ActivateTOTP(ctx, uid, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivateTOTP", reflect.TypeOf((*MockUserService)(nil).ActivateTOTP), ctx, uid, code)
+}
+
+// BindEmail mocks base method.
+func (m *MockUserService) BindEmail(ctx context.Context, uid int64, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BindEmail", ctx, uid, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BindEmail indicates an expected call of BindEmail.
+func (mr *MockUserServiceMockRecorder) BindEmail(ctx, uid, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BindEmail", reflect.TypeOf((*MockUserService)(nil).BindEmail), ctx, uid, email)
+}
+
+// BindOAuth2 mocks base method.
+func (m *MockUserService) BindOAuth2(ctx context.Context, uid int64, info domain.OAuth2Info) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BindOAuth2", ctx, uid, info)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BindOAuth2 indicates an expected call of BindOAuth2.
+func (mr *MockUserServiceMockRecorder) BindOAuth2(ctx, uid, info any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BindOAuth2", reflect.TypeOf((*MockUserService)(nil).BindOAuth2), ctx, uid, info)
+}
+
+// BindPhone mocks base method.
+func (m *MockUserService) BindPhone(ctx context.Context, uid int64, phone string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BindPhone", ctx, uid, phone)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BindPhone indicates an expected call of BindPhone.
+func (mr *MockUserServiceMockRecorder) BindPhone(ctx, uid, phone any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BindPhone", reflect.TypeOf((*MockUserService)(nil).BindPhone), ctx, uid, phone)
+}
+
+// BindWechat mocks base method.
+func (m *MockUserService) BindWechat(ctx context.Context, uid int64, info domain.WechatInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BindWechat", ctx, uid, info)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BindWechat indicates an expected call of BindWechat.
+func (mr *MockUserServiceMockRecorder) BindWechat(ctx, uid, info any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BindWechat", reflect.TypeOf((*MockUserService)(nil).BindWechat), ctx, uid, info)
+}
+
+// ConfirmBindEmail mocks base method.
+func (m *MockUserService) ConfirmBindEmail(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmBindEmail", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmBindEmail indicates an expected call of ConfirmBindEmail.
+func (mr *MockUserServiceMockRecorder) ConfirmBindEmail(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmBindEmail", reflect.TypeOf((*MockUserService)(nil).ConfirmBindEmail), ctx, token)
+}
+
+// DisableTOTP mocks base method.
+func (m *MockUserService) DisableTOTP(ctx context.Context, uid int64, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTOTP", ctx, uid, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTOTP indicates an expected call of DisableTOTP.
+func (mr *MockUserServiceMockRecorder) DisableTOTP(ctx, uid, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTOTP", reflect.TypeOf((*MockUserService)(nil).DisableTOTP), ctx, uid, code)
+}
+
+// EnrollTOTP mocks base method.
+func (m *MockUserService) EnrollTOTP(ctx context.Context, uid int64) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollTOTP", ctx, uid)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// EnrollTOTP indicates an expected call of EnrollTOTP.
+func (mr *MockUserServiceMockRecorder) EnrollTOTP(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollTOTP", reflect.TypeOf((*MockUserService)(nil).EnrollTOTP), ctx, uid)
+}
+
 // FindById mocks base method.
 func (m *MockUserService) FindById(ctx context.Context, uid int64) (domain.User, error) {
 	m.ctrl.T.Helper()
@@ -69,6 +184,21 @@ func (mr *MockUserServiceMockRecorder) FindOrCreate(ctx, phone any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrCreate", reflect.TypeOf((*MockUserService)(nil).FindOrCreate), ctx, phone)
 }
 
+// FindOrCreateByOAuth2 mocks base method.
+func (m *MockUserService) FindOrCreateByOAuth2(ctx context.Context, info domain.OAuth2Info) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrCreateByOAuth2", ctx, info)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrCreateByOAuth2 indicates an expected call of FindOrCreateByOAuth2.
+func (mr *MockUserServiceMockRecorder) FindOrCreateByOAuth2(ctx, info any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrCreateByOAuth2", reflect.TypeOf((*MockUserService)(nil).FindOrCreateByOAuth2), ctx, info)
+}
+
 // FindOrCreateByWechat mocks base method.
 func (m *MockUserService) FindOrCreateByWechat(ctx context.Context, info domain.WechatInfo) (domain.User, error) {
 	m.ctrl.T.Helper()
@@ -99,6 +229,49 @@ func (mr *MockUserServiceMockRecorder) Login(ctx, email, password any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserService)(nil).Login), ctx, email, password)
 }
 
+// ResetPassword mocks base method.
+func (m *MockUserService) ResetPassword(ctx context.Context, token, password string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, token, password)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockUserServiceMockRecorder) ResetPassword(ctx, token, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockUserService)(nil).ResetPassword), ctx, token, password)
+}
+
+// SendResetPasswordEmail mocks base method.
+func (m *MockUserService) SendResetPasswordEmail(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendResetPasswordEmail", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendResetPasswordEmail indicates an expected call of SendResetPasswordEmail.
+func (mr *MockUserServiceMockRecorder) SendResetPasswordEmail(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendResetPasswordEmail", reflect.TypeOf((*MockUserService)(nil).SendResetPasswordEmail), ctx, email)
+}
+
+// SendVerifyEmail mocks base method.
+func (m *MockUserService) SendVerifyEmail(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendVerifyEmail", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendVerifyEmail indicates an expected call of SendVerifyEmail.
+func (mr *MockUserServiceMockRecorder) SendVerifyEmail(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendVerifyEmail", reflect.TypeOf((*MockUserService)(nil).SendVerifyEmail), ctx, uid)
+}
+
 // Signup mocks base method.
 func (m *MockUserService) Signup(ctx context.Context, u domain.User) error {
 	m.ctrl.T.Helper()
@@ -113,6 +286,35 @@ func (mr *MockUserServiceMockRecorder) Signup(ctx, u any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Signup", reflect.TypeOf((*MockUserService)(nil).Signup), ctx, u)
 }
 
+// TOTPEnabled mocks base method.
+func (m *MockUserService) TOTPEnabled(ctx context.Context, uid int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TOTPEnabled", ctx, uid)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TOTPEnabled indicates an expected call of TOTPEnabled.
+func (mr *MockUserServiceMockRecorder) TOTPEnabled(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TOTPEnabled", reflect.TypeOf((*MockUserService)(nil).TOTPEnabled), ctx, uid)
+}
+
+// Unbind mocks base method.
+func (m *MockUserService) Unbind(ctx context.Context, uid int64, typ domain.IdentityType) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unbind", ctx, uid, typ)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unbind indicates an expected call of Unbind.
+func (mr *MockUserServiceMockRecorder) Unbind(ctx, uid, typ any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unbind", reflect.TypeOf((*MockUserService)(nil).Unbind), ctx, uid, typ)
+}
+
 // UpdateNonSensitiveInfo mocks base method.
 func (m *MockUserService) UpdateNonSensitiveInfo(ctx context.Context, user domain.User) error {
 	m.ctrl.T.Helper()
@@ -126,3 +328,31 @@ func (mr *MockUserServiceMockRecorder) UpdateNonSensitiveInfo(ctx, user any) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNonSensitiveInfo", reflect.TypeOf((*MockUserService)(nil).UpdateNonSensitiveInfo), ctx, user)
 }
+
+// VerifyEmail mocks base method.
+func (m *MockUserService) VerifyEmail(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockUserServiceMockRecorder) VerifyEmail(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockUserService)(nil).VerifyEmail), ctx, token)
+}
+
+// VerifyMFA mocks base method.
+func (m *MockUserService) VerifyMFA(ctx context.Context, uid int64, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyMFA", ctx, uid, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyMFA indicates an expected call of VerifyMFA.
+func (mr *MockUserServiceMockRecorder) VerifyMFA(ctx, uid, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyMFA", reflect.TypeOf((*MockUserService)(nil).VerifyMFA), ctx, uid, code)
+}
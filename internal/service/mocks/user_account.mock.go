@@ -0,0 +1,99 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./user_account.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./user_account.go -package=svcmocks -destination=./mocks/user_account.mock.go UserAccountService
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+
+	domain "ddd_demo/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserAccountService is a mock of UserAccountService interface.
+type MockUserAccountService struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserAccountServiceMockRecorder
+}
+
+// MockUserAccountServiceMockRecorder is the mock recorder for MockUserAccountService.
+type MockUserAccountServiceMockRecorder struct {
+	mock *MockUserAccountService
+}
+
+// NewMockUserAccountService creates a new mock instance.
+func NewMockUserAccountService(ctrl *gomock.Controller) *MockUserAccountService {
+	mock := &MockUserAccountService{ctrl: ctrl}
+	mock.recorder = &MockUserAccountServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserAccountService) EXPECT() *MockUserAccountServiceMockRecorder {
+	return m.recorder
+}
+
+// Deactivate mocks base method.
+func (m *MockUserAccountService) Deactivate(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deactivate", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Deactivate indicates an expected call of Deactivate.
+func (mr *MockUserAccountServiceMockRecorder) Deactivate(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deactivate", reflect.TypeOf((*MockUserAccountService)(nil).Deactivate), ctx, uid)
+}
+
+// Export mocks base method.
+func (m *MockUserAccountService) Export(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockUserAccountServiceMockRecorder) Export(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockUserAccountService)(nil).Export), ctx, uid)
+}
+
+// ExportStatus mocks base method.
+func (m *MockUserAccountService) ExportStatus(ctx context.Context, uid int64) (domain.UserExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportStatus", ctx, uid)
+	ret0, _ := ret[0].(domain.UserExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportStatus indicates an expected call of ExportStatus.
+func (mr *MockUserAccountServiceMockRecorder) ExportStatus(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportStatus", reflect.TypeOf((*MockUserAccountService)(nil).ExportStatus), ctx, uid)
+}
+
+// OpenExportFile mocks base method.
+func (m *MockUserAccountService) OpenExportFile(ctx context.Context, uid int64) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenExportFile", ctx, uid)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenExportFile indicates an expected call of OpenExportFile.
+func (mr *MockUserAccountServiceMockRecorder) OpenExportFile(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenExportFile", reflect.TypeOf((*MockUserAccountService)(nil).OpenExportFile), ctx, uid)
+}
@@ -0,0 +1,53 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./user_merge.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./user_merge.go -package=svcmocks -destination=./mocks/user_merge.mock.go UserMergeService
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserMergeService is a mock of UserMergeService interface.
+type MockUserMergeService struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserMergeServiceMockRecorder
+}
+
+// MockUserMergeServiceMockRecorder is the mock recorder for MockUserMergeService.
+type MockUserMergeServiceMockRecorder struct {
+	mock *MockUserMergeService
+}
+
+// NewMockUserMergeService creates a new mock instance.
+func NewMockUserMergeService(ctrl *gomock.Controller) *MockUserMergeService {
+	mock := &MockUserMergeService{ctrl: ctrl}
+	mock.recorder = &MockUserMergeServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserMergeService) EXPECT() *MockUserMergeServiceMockRecorder {
+	return m.recorder
+}
+
+// Merge mocks base method.
+func (m *MockUserMergeService) Merge(ctx context.Context, fromUid, toUid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, fromUid, toUid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockUserMergeServiceMockRecorder) Merge(ctx, fromUid, toUid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockUserMergeService)(nil).Merge), ctx, fromUid, toUid)
+}
@@ -0,0 +1,113 @@
+package local
+
+import (
+	"context"
+	"ddd_demo/internal/service/moderation"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// Config 审核规则，关键词不区分大小写，正则表达式按照 Go 的语法
+type Config struct {
+	// Block 命中了直接拒绝
+	Block Rules `yaml:"block"`
+	// Review 命中了交给管理员
+	Review Rules `yaml:"review"`
+}
+
+type Rules struct {
+	Keywords []string `yaml:"keywords"`
+	Patterns []string `yaml:"patterns"`
+}
+
+// Moderator 基于关键词和正则表达式的本地审核，规则可以在运行期间通过 Reload 替换
+type Moderator struct {
+	rules atomic.Pointer[compiled]
+}
+
+type compiled struct {
+	block  ruleSet
+	review ruleSet
+}
+
+type ruleSet struct {
+	keywords []string
+	patterns []*regexp.Regexp
+}
+
+func NewModerator(cfg Config) (*Moderator, error) {
+	m := &Moderator{}
+	err := m.Reload(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Reload 新的规则有问题的时候返回 error，继续用原来的规则
+func (m *Moderator) Reload(cfg Config) error {
+	block, err := compile(cfg.Block)
+	if err != nil {
+		return err
+	}
+	review, err := compile(cfg.Review)
+	if err != nil {
+		return err
+	}
+	m.rules.Store(&compiled{block: block, review: review})
+	return nil
+}
+
+func compile(r Rules) (ruleSet, error) {
+	var res ruleSet
+	for _, kw := range r.Keywords {
+		kw = strings.ToLower(strings.TrimSpace(kw))
+		// 空的关键词什么都能命中
+		if kw != "" {
+			res.keywords = append(res.keywords, kw)
+		}
+	}
+	for _, p := range r.Patterns {
+		reg, err := regexp.Compile(p)
+		if err != nil {
+			return ruleSet{}, fmt.Errorf("审核规则 %q 不合法 %w", p, err)
+		}
+		res.patterns = append(res.patterns, reg)
+	}
+	return res, nil
+}
+
+func (m *Moderator) Moderate(ctx context.Context, text string) (moderation.Result, error) {
+	rules := m.rules.Load()
+	lower := strings.ToLower(text)
+	if hit, ok := rules.block.match(text, lower); ok {
+		return moderation.Result{
+			Verdict: moderation.VerdictBlock,
+			Reason:  fmt.Sprintf("包含违规内容「%s」", hit),
+		}, nil
+	}
+	if hit, ok := rules.review.match(text, lower); ok {
+		return moderation.Result{
+			Verdict: moderation.VerdictReview,
+			Reason:  fmt.Sprintf("包含需要人工确认的内容「%s」", hit),
+		}, nil
+	}
+	return moderation.Result{Verdict: moderation.VerdictPass}, nil
+}
+
+// match 返回第一个命中的内容，lower 是转成小写的 text
+func (r ruleSet) match(text string, lower string) (string, bool) {
+	for _, kw := range r.keywords {
+		if strings.Contains(lower, kw) {
+			return kw, true
+		}
+	}
+	for _, p := range r.patterns {
+		if hit := p.FindString(text); hit != "" {
+			return hit, true
+		}
+	}
+	return "", false
+}
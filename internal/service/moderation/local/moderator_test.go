@@ -0,0 +1,91 @@
+package local
+
+import (
+	"context"
+	"ddd_demo/internal/service/moderation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestModerator_Moderate(t *testing.T) {
+	m, err := NewModerator(Config{
+		Block: Rules{
+			Keywords: []string{"赌博", "  ", "Casino"},
+			Patterns: []string{`\d{3}-\d{4}-\d{4}`},
+		},
+		Review: Rules{
+			Keywords: []string{"代购"},
+		},
+	})
+	require.NoError(t, err)
+	testCases := []struct {
+		name string
+		text string
+
+		wantRes moderation.Result
+	}{
+		{
+			name:    "没有问题",
+			text:    "今天学习了 Go 的并发",
+			wantRes: moderation.Result{Verdict: moderation.VerdictPass},
+		},
+		{
+			name: "关键词不区分大小写",
+			text: "欢迎来 CASINO 玩",
+			wantRes: moderation.Result{
+				Verdict: moderation.VerdictBlock,
+				Reason:  "包含违规内容「casino」",
+			},
+		},
+		{
+			name: "正则",
+			text: "联系我 138-0000-0000",
+			wantRes: moderation.Result{
+				Verdict: moderation.VerdictBlock,
+				Reason:  "包含违规内容「138-0000-0000」",
+			},
+		},
+		{
+			name: "转人工",
+			text: "海外代购",
+			wantRes: moderation.Result{
+				Verdict: moderation.VerdictReview,
+				Reason:  "包含需要人工确认的内容「代购」",
+			},
+		},
+		{
+			name: "两种都命中的时候直接拒绝",
+			text: "代购赌博",
+			wantRes: moderation.Result{
+				Verdict: moderation.VerdictBlock,
+				Reason:  "包含违规内容「赌博」",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := m.Moderate(context.Background(), tc.text)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+func TestModerator_Reload(t *testing.T) {
+	m, err := NewModerator(Config{Block: Rules{Keywords: []string{"赌博"}}})
+	require.NoError(t, err)
+
+	// 新规则有问题，继续用原来的
+	err = m.Reload(Config{Block: Rules{Patterns: []string{"("}}})
+	assert.Error(t, err)
+	res, err := m.Moderate(context.Background(), "赌博")
+	require.NoError(t, err)
+	assert.Equal(t, moderation.VerdictBlock, res.Verdict)
+
+	err = m.Reload(Config{Review: Rules{Keywords: []string{"赌博"}}})
+	require.NoError(t, err)
+	res, err = m.Moderate(context.Background(), "赌博")
+	require.NoError(t, err)
+	assert.Equal(t, moderation.VerdictReview, res.Verdict)
+}
@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./types.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./types.go -package=moderationmocks -destination=./mocks/moderator.mock.go Moderator
+//
+// Package moderationmocks is a generated GoMock package.
+package moderationmocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	moderation "ddd_demo/internal/service/moderation"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockModerator is a mock of Moderator interface.
+type MockModerator struct {
+	ctrl     *gomock.Controller
+	recorder *MockModeratorMockRecorder
+}
+
+// MockModeratorMockRecorder is the mock recorder for MockModerator.
+type MockModeratorMockRecorder struct {
+	mock *MockModerator
+}
+
+// NewMockModerator creates a new mock instance.
+func NewMockModerator(ctrl *gomock.Controller) *MockModerator {
+	mock := &MockModerator{ctrl: ctrl}
+	mock.recorder = &MockModeratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockModerator) EXPECT() *MockModeratorMockRecorder {
+	return m.recorder
+}
+
+// Moderate mocks base method.
+func (m *MockModerator) Moderate(ctx context.Context, text string) (moderation.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Moderate", ctx, text)
+	ret0, _ := ret[0].(moderation.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Moderate indicates an expected call of Moderate.
+func (mr *MockModeratorMockRecorder) Moderate(ctx, text any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Moderate", reflect.TypeOf((*MockModerator)(nil).Moderate), ctx, text)
+}
@@ -0,0 +1,28 @@
+package moderation
+
+import "context"
+
+// Moderator 内容审核的抽象
+// 屏蔽本地规则和第三方审核服务之间的区别
+//
+//go:generate mockgen -source=./types.go -package=moderationmocks -destination=./mocks/moderator.mock.go Moderator
+type Moderator interface {
+	Moderate(ctx context.Context, text string) (Result, error)
+}
+
+type Verdict uint8
+
+const (
+	// VerdictPass 没有问题，可以直接发表
+	VerdictPass Verdict = iota
+	// VerdictReview 拿不准，交给管理员
+	VerdictReview
+	// VerdictBlock 肯定不行，直接拒绝
+	VerdictBlock
+)
+
+type Result struct {
+	Verdict Verdict
+	// Reason 不是 VerdictPass 的时候说明命中了什么
+	Reason string
+}
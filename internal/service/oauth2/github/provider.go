@@ -0,0 +1,100 @@
+package github
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/service/oauth2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	authURL     = "https://github.com/login/oauth/authorize"
+	tokenURL    = "https://github.com/login/oauth/access_token"
+	userInfoURL = "https://api.github.com/user"
+	emailsURL   = "https://api.github.com/user/emails"
+)
+
+type Provider struct {
+	cfg    oauth2.Config
+	client *http.Client
+}
+
+func NewProvider(cfg oauth2.Config, client *http.Client) oauth2.Provider {
+	if cfg.Name == "" {
+		cfg.Name = "github"
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"read:user", "user:email"}
+	}
+	return &Provider{cfg: cfg, client: client}
+}
+
+func (p *Provider) Name() string {
+	return p.cfg.Name
+}
+
+func (p *Provider) AuthURL(ctx context.Context, state string) (string, error) {
+	query := url.Values{}
+	query.Set("client_id", p.cfg.ClientID)
+	query.Set("redirect_uri", p.cfg.RedirectURL)
+	query.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	query.Set("state", state)
+	return authURL + "?" + query.Encode(), nil
+}
+
+func (p *Provider) Exchange(ctx context.Context, code string) (oauth2.Token, error) {
+	return oauth2.ExchangeCode(ctx, p.client, tokenURL, p.cfg, code)
+}
+
+func (p *Provider) UserInfo(ctx context.Context, token oauth2.Token) (domain.OAuth2Info, error) {
+	var u struct {
+		Id    int64  `json:"id"`
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	}
+	err := p.get(ctx, token, userInfoURL, &u)
+	if err != nil {
+		return domain.OAuth2Info{}, err
+	}
+	res := domain.OAuth2Info{
+		Provider: p.Name(),
+		// login 是可以改的，只有 id 不会变
+		Subject:  strconv.FormatInt(u.Id, 10),
+		Nickname: u.Name,
+	}
+	if res.Nickname == "" {
+		res.Nickname = u.Login
+	}
+	// 用户资料里面的邮箱可能是空的，要单独查一下主邮箱
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	err = p.get(ctx, token, emailsURL, &emails)
+	if err != nil {
+		// 没有授权 user:email 的时候拿不到，不影响登录
+		return res, nil
+	}
+	for _, e := range emails {
+		if e.Primary {
+			res.Email = e.Email
+			res.EmailVerified = e.Verified
+			break
+		}
+	}
+	return res, nil
+}
+
+func (p *Provider) get(ctx context.Context, token oauth2.Token, url string, val any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	return oauth2.DoJSON(p.client, req, val)
+}
@@ -0,0 +1,142 @@
+package oidc
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/service/oauth2"
+	"ddd_demo/pkg/jwtx"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Provider 通用的 OpenID Connect 登录，
+// 所有的地址都从 issuer 的 discovery 文档里面拿
+type Provider struct {
+	cfg    oauth2.Config
+	client *http.Client
+
+	mutex sync.Mutex
+	// 第一次用的时候才去拉 discovery 文档，拉失败了下次还会再试
+	meta *metadata
+	keys *jwtx.RemoteKeySet
+}
+
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+type Claims struct {
+	jwt.RegisteredClaims
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+}
+
+func NewProvider(cfg oauth2.Config, client *http.Client) oauth2.Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "profile", "email"}
+	}
+	return &Provider{cfg: cfg, client: client}
+}
+
+func (p *Provider) Name() string {
+	return p.cfg.Name
+}
+
+func (p *Provider) AuthURL(ctx context.Context, state string) (string, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.cfg.ClientID)
+	query.Set("redirect_uri", p.cfg.RedirectURL)
+	query.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	query.Set("state", state)
+	return meta.AuthorizationEndpoint + "?" + query.Encode(), nil
+}
+
+func (p *Provider) Exchange(ctx context.Context, code string) (oauth2.Token, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return oauth2.Token{}, err
+	}
+	return oauth2.ExchangeCode(ctx, p.client, meta.TokenEndpoint, p.cfg, code)
+}
+
+func (p *Provider) UserInfo(ctx context.Context, token oauth2.Token) (domain.OAuth2Info, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return domain.OAuth2Info{}, err
+	}
+	var claims Claims
+	if token.IDToken != "" {
+		// 有 id_token 就不需要再调用 userinfo 接口了
+		_, err = jwt.ParseWithClaims(token.IDToken, &claims, p.keys.Keyfunc,
+			jwt.WithIssuer(meta.Issuer),
+			jwt.WithAudience(p.cfg.ClientID),
+			jwt.WithExpirationRequired())
+		if err != nil {
+			return domain.OAuth2Info{}, fmt.Errorf("id_token 校验失败 %w", err)
+		}
+	} else {
+		if meta.UserinfoEndpoint == "" {
+			return domain.OAuth2Info{}, errors.New("没有 id_token，也没有 userinfo 接口")
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, meta.UserinfoEndpoint, nil)
+		if err != nil {
+			return domain.OAuth2Info{}, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+		err = oauth2.DoJSON(p.client, req, &claims)
+		if err != nil {
+			return domain.OAuth2Info{}, err
+		}
+	}
+	if claims.Subject == "" {
+		return domain.OAuth2Info{}, errors.New("没有拿到 sub")
+	}
+	return domain.OAuth2Info{
+		Provider:      p.Name(),
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Nickname:      claims.Name,
+	}, nil
+}
+
+func (p *Provider) discover(ctx context.Context) (*metadata, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.meta != nil {
+		return p.meta, nil
+	}
+	issuer := strings.TrimSuffix(p.cfg.Issuer, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	var meta metadata
+	err = oauth2.DoJSON(p.client, req, &meta)
+	if err != nil {
+		return nil, fmt.Errorf("获取 OIDC 配置失败 %w", err)
+	}
+	// 防止被人用别的 issuer 的配置冒充
+	if meta.Issuer != issuer {
+		return nil, fmt.Errorf("issuer 不匹配 %s %s", meta.Issuer, issuer)
+	}
+	p.meta = &meta
+	p.keys = jwtx.NewRemoteKeySet(meta.JwksURI, p.client)
+	return p.meta, nil
+}
@@ -0,0 +1,100 @@
+package oidc
+
+import (
+	"context"
+	"ddd_demo/internal/integration/fakeoidc"
+	"ddd_demo/internal/service/oauth2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestProvider(t *testing.T) {
+	server := fakeoidc.NewServer("client", "secret")
+	defer server.Close()
+	server.SetUser(fakeoidc.User{
+		Subject:       "123",
+		Email:         "abc@example.com",
+		EmailVerified: true,
+		Name:          "abc",
+	})
+	p := NewProvider(oauth2.Config{
+		Name:         "fake",
+		Issuer:       server.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/oauth2/fake/callback",
+	}, http.DefaultClient)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	code := authorize(t, ctx, p, "my-state")
+
+	token, err := p.Exchange(ctx, code)
+	require.NoError(t, err)
+	info, err := p.UserInfo(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, "fake", info.Provider)
+	assert.Equal(t, "123", info.Subject)
+	assert.Equal(t, "abc@example.com", info.Email)
+	assert.True(t, info.EmailVerified)
+
+	// 没有 id_token 的时候走 userinfo 接口
+	token.IDToken = ""
+	info, err = p.UserInfo(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, "123", info.Subject)
+
+	// 授权码只能用一次
+	_, err = p.Exchange(ctx, code)
+	assert.Error(t, err)
+}
+
+func TestProvider_WrongAudience(t *testing.T) {
+	server := fakeoidc.NewServer("client", "secret")
+	defer server.Close()
+	// id_token 是签给 client 的，换了一个 client_id 去校验
+	other := NewProvider(oauth2.Config{
+		Name:         "fake",
+		Issuer:       server.URL,
+		ClientID:     "other",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/oauth2/fake/callback",
+	}, http.DefaultClient)
+	p := NewProvider(oauth2.Config{
+		Name:         "fake",
+		Issuer:       server.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/oauth2/fake/callback",
+	}, http.DefaultClient)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	token, err := p.Exchange(ctx, authorize(t, ctx, p, "state"))
+	require.NoError(t, err)
+	_, err = other.UserInfo(ctx, token)
+	assert.Error(t, err)
+}
+
+// authorize 模拟浏览器跳转到授权页面，返回回调里面的授权码
+func authorize(t *testing.T, ctx context.Context, p oauth2.Provider, state string) string {
+	authURL, err := p.AuthURL(ctx, state)
+	require.NoError(t, err)
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Get(authURL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	loc, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, state, loc.Query().Get("state"))
+	return loc.Query().Get("code")
+}
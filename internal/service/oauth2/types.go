@@ -0,0 +1,122 @@
+package oauth2
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var ErrUnknownProvider = errors.New("未知的第三方登录")
+
+// Provider 一个 OAuth2 授权码模式的第三方登录
+type Provider interface {
+	// Name 出现在 URL 里面，比如说 /oauth2/github/callback
+	Name() string
+	// AuthURL 跳转去第三方授权的 URL
+	AuthURL(ctx context.Context, state string) (string, error)
+	// Exchange 用回调里面的授权码换 token
+	Exchange(ctx context.Context, code string) (Token, error)
+	// UserInfo 用 token 拿用户信息
+	UserInfo(ctx context.Context, token Token) (domain.OAuth2Info, error)
+}
+
+type Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	// OIDC 才有
+	IDToken string `json:"id_token"`
+}
+
+// Registry 按照名字找 Provider
+type Registry struct {
+	providers map[string]Provider
+}
+
+func NewRegistry(providers ...Provider) (*Registry, error) {
+	r := &Registry{providers: make(map[string]Provider, len(providers))}
+	for _, p := range providers {
+		switch domain.IdentityType(p.Name()) {
+		case domain.IdentityPhone, domain.IdentityEmail, domain.IdentityWechat:
+			// 解绑的时候分不清是哪一个
+			return nil, fmt.Errorf("第三方登录 %s 和内置的登录方式重名了", p.Name())
+		}
+		if _, ok := r.providers[p.Name()]; ok {
+			return nil, fmt.Errorf("第三方登录 %s 重复了", p.Name())
+		}
+		r.providers[p.Name()] = p
+	}
+	return r, nil
+}
+
+func (r *Registry) Get(name string) (Provider, error) {
+	p, ok := r.providers[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	return p, nil
+}
+
+// Config 一个第三方登录的配置
+type Config struct {
+	Name string `yaml:"name"`
+	// github, oidc
+	Type string `yaml:"type"`
+	// OIDC 的 issuer，会从 {issuer}/.well-known/openid-configuration 拿其它的地址
+	Issuer       string   `yaml:"issuer"`
+	ClientID     string   `yaml:"clientId"`
+	ClientSecret string   `yaml:"clientSecret"`
+	RedirectURL  string   `yaml:"redirectURL"`
+	Scopes       []string `yaml:"scopes"`
+}
+
+// ExchangeCode 标准的授权码换 token，client 认证放在表单里面
+func ExchangeCode(ctx context.Context, client *http.Client,
+	tokenURL string, cfg Config, code string) (Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", cfg.RedirectURL)
+	form.Set("client_id", cfg.ClientID)
+	form.Set("client_secret", cfg.ClientSecret)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL,
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return Token{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	var res struct {
+		Token
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	err = DoJSON(client, req, &res)
+	if err != nil {
+		return Token{}, err
+	}
+	if res.Error != "" {
+		return Token{}, fmt.Errorf("授权码换 token 失败 %s %s", res.Error, res.ErrorDescription)
+	}
+	if res.AccessToken == "" {
+		return Token{}, errors.New("授权码换 token 失败，没有返回 access_token")
+	}
+	return res.Token, nil
+}
+
+// DoJSON 发请求，把 JSON 响应解析到 val 里面
+func DoJSON(client *http.Client, req *http.Request, val any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("请求 %s 失败 %s", req.URL.Path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(val)
+}
@@ -15,20 +15,21 @@ type Service interface {
 	VerifyCode(ctx context.Context, code string) (domain.WechatInfo, error)
 }
 
-var redirectURL = url.PathEscape("https://meoying.com/oauth2/wechat/callback")
-
 type service struct {
-	appID     string
-	appSecret string
-	client    *http.Client
-	l         logger.LoggerV1
+	appID       string
+	appSecret   string
+	redirectURL string
+	client      *http.Client
+	l           logger.LoggerV1
 }
 
-func NewService(appID string, appSecret string, l logger.LoggerV1) Service {
+func NewService(appID string, appSecret string, redirectURL string, l logger.LoggerV1) Service {
 	return &service{
-		appID:     appID,
-		appSecret: appSecret,
-		client:    http.DefaultClient,
+		appID:       appID,
+		appSecret:   appSecret,
+		redirectURL: url.PathEscape(redirectURL),
+		client:      http.DefaultClient,
+		l:           l,
 	}
 }
 func (s *service) VerifyCode(ctx context.Context,
@@ -62,7 +63,7 @@ func (s *service) VerifyCode(ctx context.Context,
 
 func (s *service) AuthURL(ctx context.Context, state string) (string, error) {
 	const authURLPattern = `https://open.weixin.qq.com/connect/qrconnect?appid=%s&redirect_uri=%s&response_type=code&scope=snsapi_login&state=%s#wechat_redirect`
-	return fmt.Sprintf(authURLPattern, s.appID, redirectURL, state), nil
+	return fmt.Sprintf(authURLPattern, s.appID, s.redirectURL, state), nil
 }
 
 type Result struct {
@@ -59,7 +59,8 @@ func (b *BatchRankingService) TopN(ctx context.Context) error {
 }
 
 func (b *BatchRankingService) topN(ctx context.Context) ([]domain.Article, error) {
-	offset := 0
+	// 按照 utime、id 翻页，遍历的过程中有文章被修改也不会重复或者漏掉
+	var cursor domain.ArticleCursor
 	start := time.Now()
 	ddl := start.Add(-7 * 24 * time.Hour)
 
@@ -81,7 +82,7 @@ func (b *BatchRankingService) topN(ctx context.Context) ([]domain.Article, error
 
 	for {
 		// 取数据
-		arts, err := b.artSvc.ListPub(ctx, start, offset, b.batchSize)
+		arts, err := b.artSvc.ListPubByCursor(ctx, start, cursor, b.batchSize)
 		if err != nil {
 			return nil, err
 		}
@@ -124,7 +125,7 @@ func (b *BatchRankingService) topN(ctx context.Context) ([]domain.Article, error
 				}
 			}
 		}
-		offset = offset + len(arts)
+		cursor = arts[len(arts)-1].Cursor()
 		// 没有取够一批，我们就直接中断执行
 		// 没有下一批了
 		if len(arts) < b.batchSize ||
@@ -2,12 +2,12 @@ package service
 
 import (
 	"context"
-	domain2 "ddd_demo/interactive/domain"
-	service2 "ddd_demo/interactive/service"
+	intrv1 "ddd_demo/api/proto/gen/intr/v1"
+	intrv1mocks "ddd_demo/api/proto/gen/intr/v1/mocks"
 	"ddd_demo/internal/domain"
 	svcmocks "ddd_demo/internal/service/mocks"
-	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
 	"testing"
 	"time"
 )
@@ -18,59 +18,62 @@ func TestBatchRankingService_TopN(t *testing.T) {
 	testCases := []struct {
 		name string
 
-		mock func(ctrl *gomock.Controller) (service2.InteractiveService, ArticleService)
+		mock func(ctrl *gomock.Controller) (intrv1.InteractiveServiceClient, ArticleService)
 
 		wantArts []domain.Article
 		wantErr  error
 	}{
 		{
 			name: "成功获取",
-			mock: func(ctrl *gomock.Controller) (service2.InteractiveService, ArticleService) {
-				intrSvc := svcmocks.NewMockInteractiveService(ctrl)
+			mock: func(ctrl *gomock.Controller) (intrv1.InteractiveServiceClient, ArticleService) {
+				intrSvc := intrv1mocks.NewMockInteractiveServiceClient(ctrl)
 				artSvc := svcmocks.NewMockArticleService(ctrl)
 				// 先模拟批量获取数据
 				// 先模拟第一批
-				artSvc.EXPECT().ListPub(gomock.Any(), gomock.Any(), 0, 2).
+				artSvc.EXPECT().ListPubByCursor(gomock.Any(), gomock.Any(), domain.ArticleCursor{}, 2).
 					Return([]domain.Article{
-						{Id: 1, Utime: now},
 						{Id: 2, Utime: now},
+						{Id: 1, Utime: now},
 					}, nil)
-				// 模拟第二批
-				artSvc.EXPECT().ListPub(gomock.Any(), gomock.Any(), 2, 2).
+				// 模拟第二批，游标是第一批最后一篇
+				artSvc.EXPECT().ListPubByCursor(gomock.Any(), gomock.Any(),
+					domain.ArticleCursor{Utime: now, Id: 1}, 2).
 					Return([]domain.Article{
-						{Id: 3, Utime: now},
-						{Id: 4, Utime: now},
+						{Id: 4, Utime: now.Add(-time.Second)},
+						{Id: 3, Utime: now.Add(-time.Second)},
 					}, nil)
 				// 模拟第三批
-				artSvc.EXPECT().ListPub(gomock.Any(), gomock.Any(), 4, 2).
+				artSvc.EXPECT().ListPubByCursor(gomock.Any(), gomock.Any(),
+					domain.ArticleCursor{Utime: now.Add(-time.Second), Id: 3}, 2).
 					// 没数据了
 					Return([]domain.Article{}, nil)
 
 				// 第一批的点赞数据
-				intrSvc.EXPECT().GetByIds(gomock.Any(), "article", []int64{1, 2}).
-					Return(map[int64]domain2.Interactive{
+				intrSvc.EXPECT().GetByIds(gomock.Any(), &intrv1.GetByIdsRequest{
+					Biz: "article", Ids: []int64{2, 1},
+				}).
+					Return(&intrv1.GetByIdsResponse{Intrs: map[int64]*intrv1.Interactive{
 						1: {LikeCnt: 1},
 						2: {LikeCnt: 2},
-					}, nil)
+					}}, nil)
 
 				// 第二批的点赞数据
-				intrSvc.EXPECT().GetByIds(gomock.Any(), "article", []int64{3, 4}).
-					Return(map[int64]domain2.Interactive{
+				intrSvc.EXPECT().GetByIds(gomock.Any(), &intrv1.GetByIdsRequest{
+					Biz: "article", Ids: []int64{4, 3},
+				}).
+					Return(&intrv1.GetByIdsResponse{Intrs: map[int64]*intrv1.Interactive{
 						3: {LikeCnt: 3},
 						4: {LikeCnt: 4},
-					}, nil)
-
-				// 第三批的点赞数据
-				intrSvc.EXPECT().GetByIds(gomock.Any(), "article", []int64{}).
-					Return(map[int64]domain2.Interactive{}, nil)
+					}}, nil)
+				// 第三批没有数据，不会再去取点赞数据了
 
 				return intrSvc, artSvc
 			},
 
 			wantErr: nil,
 			wantArts: []domain.Article{
-				{Id: 4, Utime: now},
-				{Id: 3, Utime: now},
+				{Id: 4, Utime: now.Add(-time.Second)},
+				{Id: 3, Utime: now.Add(-time.Second)},
 				{Id: 2, Utime: now},
 			},
 		},
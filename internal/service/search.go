@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository"
+	"ddd_demo/internal/service/search"
+	"ddd_demo/pkg/logger"
+	"errors"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+)
+
+//go:generate mockgen -source=./search.go -package=svcmocks -destination=./mocks/search.mock.go SearchService
+type SearchService interface {
+	// SearchArticles 搜索已经发表的文章，标题、内容、作者名字都会搜
+	SearchArticles(ctx context.Context, query string, offset int, limit int) (domain.ArticleSearchResult, error)
+	// IndexArticle 按照线上库里面的最新状态更新索引，
+	// 撤回了的或者不存在的文章会从索引里面删掉，所以重复调用、乱序调用都没有问题
+	IndexArticle(ctx context.Context, aid int64) error
+	// RemoveAuthor 注销账号之后，删除作者所有的文章
+	RemoveAuthor(ctx context.Context, uid int64) error
+	// TransferAuthor 合并账号之后，from 的文章都已经转给了 to，作者名字要跟着变
+	TransferAuthor(ctx context.Context, from int64, to int64) error
+	// Rebuild 把所有已经发表的文章重新写一遍索引，本地索引启动的时候用
+	Rebuild(ctx context.Context) error
+}
+
+type searchService struct {
+	idx       search.Index
+	artRepo   repository.ArticleRepository
+	userRepo  repository.UserRepository
+	batchSize int
+	l         logger.LoggerV1
+}
+
+func NewSearchService(idx search.Index,
+	artRepo repository.ArticleRepository,
+	userRepo repository.UserRepository,
+	l logger.LoggerV1) SearchService {
+	return &searchService{
+		idx:       idx,
+		artRepo:   artRepo,
+		userRepo:  userRepo,
+		batchSize: 100,
+		l:         l,
+	}
+}
+
+func (s *searchService) SearchArticles(ctx context.Context, query string,
+	offset int, limit int) (domain.ArticleSearchResult, error) {
+	hits, total, err := s.idx.Search(ctx, query, offset, limit)
+	if err != nil {
+		return domain.ArticleSearchResult{}, err
+	}
+	return domain.ArticleSearchResult{
+		Total: total,
+		Hits: slice.Map(hits, func(idx int, src search.Hit) domain.ArticleSearchHit {
+			return domain.ArticleSearchHit{
+				Id: src.Id,
+				Author: domain.Author{
+					Id:   src.AuthorId,
+					Name: src.AuthorName,
+				},
+				Utime:   src.Utime,
+				Title:   src.Title,
+				Snippet: src.Snippet,
+			}
+		}),
+	}, nil
+}
+
+func (s *searchService) IndexArticle(ctx context.Context, aid int64) error {
+	// 消息里面只有 ID，内容以线上库为准，这样消息重复、乱序都不会把旧的内容写进去
+	art, err := s.artRepo.FindPubById(ctx, aid)
+	// 文章或者作者已经没了，都不应该再被搜到
+	if errors.Is(err, repository.ErrArticleNotFound) {
+		return s.idx.Delete(ctx, aid)
+	}
+	if err != nil {
+		return err
+	}
+	// 只有公开的文章能被搜到
+	if art.Status != domain.ArticleStatusPublished ||
+		art.Visibility != domain.ArticleVisibilityPublic {
+		return s.idx.Delete(ctx, aid)
+	}
+	return s.idx.Upsert(ctx, s.toDocument(art))
+}
+
+func (s *searchService) RemoveAuthor(ctx context.Context, uid int64) error {
+	return s.idx.DeleteByAuthor(ctx, uid)
+}
+
+func (s *searchService) TransferAuthor(ctx context.Context, from int64, to int64) error {
+	err := s.idx.DeleteByAuthor(ctx, from)
+	if err != nil {
+		return err
+	}
+	author, err := s.userRepo.FindById(ctx, to)
+	if err != nil {
+		return err
+	}
+	for offset := 0; ; offset += s.batchSize {
+		arts, err := s.artRepo.ListPubByAuthor(ctx, to,
+			[]domain.ArticleVisibility{domain.ArticleVisibilityPublic}, offset, s.batchSize)
+		if err != nil {
+			return err
+		}
+		for _, art := range arts {
+			art.Author.Name = author.Nickname
+			err = s.idx.Upsert(ctx, s.toDocument(art))
+			if err != nil {
+				return err
+			}
+		}
+		if len(arts) < s.batchSize {
+			return nil
+		}
+	}
+}
+
+func (s *searchService) Rebuild(ctx context.Context) error {
+	start := time.Now()
+	// 同一个作者的文章很多，名字只查一次
+	names := make(map[int64]string)
+	cnt := 0
+	var cursor domain.ArticleCursor
+	for {
+		arts, err := s.artRepo.ListPubByCursor(ctx, start, cursor, s.batchSize)
+		if err != nil {
+			return err
+		}
+		for _, art := range arts {
+			name, ok := names[art.Author.Id]
+			if !ok {
+				u, err := s.userRepo.FindById(ctx, art.Author.Id)
+				if errors.Is(err, repository.ErrUserNotFound) {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				name = u.Nickname
+				names[art.Author.Id] = name
+			}
+			art.Author.Name = name
+			err = s.idx.Upsert(ctx, s.toDocument(art))
+			if err != nil {
+				return err
+			}
+			cnt++
+		}
+		if len(arts) < s.batchSize {
+			break
+		}
+		cursor = arts[len(arts)-1].Cursor()
+	}
+	s.l.Info("重建搜索索引完成", logger.Int("count", cnt),
+		logger.String("duration", time.Since(start).String()))
+	return nil
+}
+
+func (s *searchService) toDocument(art domain.Article) search.Document {
+	return search.Document{
+		Id:         art.Id,
+		Title:      art.Title,
+		Content:    art.Content,
+		AuthorId:   art.Author.Id,
+		AuthorName: art.Author.Name,
+		Utime:      art.Utime,
+	}
+}
@@ -0,0 +1,88 @@
+package local
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	highlightPre  = "<em>"
+	highlightPost = "</em>"
+	// snippetSize 摘要最多多少个字
+	snippetSize = 120
+)
+
+// highlight 把命中的地方用 <em> 包起来，其它部分做 HTML 转义
+func highlight(text string, terms map[string]struct{}) string {
+	type span struct{ start, end int }
+	var spans []span
+	for _, tk := range tokenize(text) {
+		if _, ok := terms[tk.term]; ok {
+			spans = append(spans, span{start: tk.start, end: tk.end})
+		}
+	}
+	if len(spans) == 0 {
+		return html.EscapeString(text)
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].start < spans[j].start
+	})
+	// bigram 之间会重叠，相邻的也合并成一段
+	merged := spans[:1]
+	for _, s := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if s.start <= last.end {
+			last.end = max(last.end, s.end)
+			continue
+		}
+		merged = append(merged, s)
+	}
+	var sb strings.Builder
+	pos := 0
+	for _, s := range merged {
+		sb.WriteString(html.EscapeString(text[pos:s.start]))
+		sb.WriteString(highlightPre)
+		sb.WriteString(html.EscapeString(text[s.start:s.end]))
+		sb.WriteString(highlightPost)
+		pos = s.end
+	}
+	sb.WriteString(html.EscapeString(text[pos:]))
+	return sb.String()
+}
+
+// snippet 截取第一个命中的地方附近的一段内容，再做高亮
+func snippet(text string, terms map[string]struct{}) string {
+	first := -1
+	for _, tk := range tokenize(text) {
+		if _, ok := terms[tk.term]; ok && (first < 0 || tk.start < first) {
+			first = tk.start
+		}
+	}
+	// 每个字在原文里面的起始位置，最后一个是 len(text)
+	offsets := make([]int, 0, utf8.RuneCountInString(text)+1)
+	for i := range text {
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(text))
+	runes := len(offsets) - 1
+	if runes <= snippetSize {
+		return highlight(text, terms)
+	}
+	begin := 0
+	if first > 0 {
+		// 命中的地方前面留一点上下文
+		begin = max(sort.SearchInts(offsets, first)-snippetSize/4, 0)
+	}
+	end := min(begin+snippetSize, runes)
+	begin = max(end-snippetSize, 0)
+	res := highlight(text[offsets[begin]:offsets[end]], terms)
+	if begin > 0 {
+		res = "..." + res
+	}
+	if end < runes {
+		res += "..."
+	}
+	return res
+}
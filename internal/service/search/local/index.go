@@ -0,0 +1,203 @@
+package local
+
+import (
+	"context"
+	"ddd_demo/internal/service/search"
+	"math"
+	"sort"
+	"sync"
+)
+
+// 不同字段命中的权重，标题最重要
+const (
+	titleWeight   = 3
+	authorWeight  = 2
+	contentWeight = 1
+)
+
+// Index 放在内存里面的倒排索引，不依赖任何外部服务。
+// 每个节点各有一份，重启之后要重建，适合开发环境和数据量不大的时候用
+type Index struct {
+	mu   sync.RWMutex
+	docs map[int64]*entry
+	// 倒排表，词 -> 文档 ID
+	postings map[string]map[int64]struct{}
+	// 作者 -> 文档 ID，删除作者所有文档的时候用
+	authors map[int64]map[int64]struct{}
+}
+
+type entry struct {
+	doc   search.Document
+	terms map[string]termFreq
+}
+
+// termFreq 一个词在各个字段里面出现的次数
+type termFreq struct {
+	title   int
+	content int
+	author  int
+}
+
+func NewIndex() search.Index {
+	return &Index{
+		docs:     make(map[int64]*entry),
+		postings: make(map[string]map[int64]struct{}),
+		authors:  make(map[int64]map[int64]struct{}),
+	}
+}
+
+func (idx *Index) Upsert(ctx context.Context, doc search.Document) error {
+	e := &entry{
+		doc:   doc,
+		terms: make(map[string]termFreq),
+	}
+	count := func(text string, inc func(tf *termFreq)) {
+		for _, tk := range tokenize(text) {
+			tf := e.terms[tk.term]
+			inc(&tf)
+			e.terms[tk.term] = tf
+		}
+	}
+	count(doc.Title, func(tf *termFreq) { tf.title++ })
+	count(doc.Content, func(tf *termFreq) { tf.content++ })
+	count(doc.AuthorName, func(tf *termFreq) { tf.author++ })
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(doc.Id)
+	idx.docs[doc.Id] = e
+	for term := range e.terms {
+		ids, ok := idx.postings[term]
+		if !ok {
+			ids = make(map[int64]struct{})
+			idx.postings[term] = ids
+		}
+		ids[doc.Id] = struct{}{}
+	}
+	ids, ok := idx.authors[doc.AuthorId]
+	if !ok {
+		ids = make(map[int64]struct{})
+		idx.authors[doc.AuthorId] = ids
+	}
+	ids[doc.Id] = struct{}{}
+	return nil
+}
+
+func (idx *Index) Delete(ctx context.Context, id int64) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(id)
+	return nil
+}
+
+func (idx *Index) DeleteByAuthor(ctx context.Context, authorId int64) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for id := range idx.authors[authorId] {
+		idx.remove(id)
+	}
+	return nil
+}
+
+// remove 调用方要持有写锁
+func (idx *Index) remove(id int64) {
+	e, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+	delete(idx.docs, id)
+	for term := range e.terms {
+		ids := idx.postings[term]
+		delete(ids, id)
+		if len(ids) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	ids := idx.authors[e.doc.AuthorId]
+	delete(ids, id)
+	if len(ids) == 0 {
+		delete(idx.authors, e.doc.AuthorId)
+	}
+}
+
+// Search 所有的词都要命中，按照 TF-IDF 打分
+func (idx *Index) Search(ctx context.Context, query string,
+	offset int, limit int) ([]search.Hit, int, error) {
+	terms := make(map[string]struct{})
+	for _, tk := range tokenizeQuery(query) {
+		terms[tk.term] = struct{}{}
+	}
+	if len(terms) == 0 {
+		return nil, 0, nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	// 从最短的倒排表开始求交集
+	var shortest map[int64]struct{}
+	for term := range terms {
+		ids := idx.postings[term]
+		if len(ids) == 0 {
+			return nil, 0, nil
+		}
+		if shortest == nil || len(ids) < len(shortest) {
+			shortest = ids
+		}
+	}
+	type scored struct {
+		e     *entry
+		score float64
+	}
+	matched := make([]scored, 0, len(shortest))
+	total := float64(len(idx.docs))
+	for id := range shortest {
+		e := idx.docs[id]
+		var score float64
+		for term := range terms {
+			tf, ok := e.terms[term]
+			if !ok {
+				score = -1
+				break
+			}
+			idf := math.Log(1 + total/float64(len(idx.postings[term])))
+			w := float64(titleWeight*tf.title + authorWeight*tf.author)
+			if tf.content > 0 {
+				// 内容很长的时候，出现次数多不代表更相关
+				w += contentWeight * (1 + math.Log(float64(tf.content)))
+			}
+			score += w * idf
+		}
+		if score >= 0 {
+			matched = append(matched, scored{e: e, score: score})
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		if matched[i].score != matched[j].score {
+			return matched[i].score > matched[j].score
+		}
+		// 分数一样的时候，新的在前面
+		if !matched[i].e.doc.Utime.Equal(matched[j].e.doc.Utime) {
+			return matched[i].e.doc.Utime.After(matched[j].e.doc.Utime)
+		}
+		return matched[i].e.doc.Id > matched[j].e.doc.Id
+	})
+
+	if offset >= len(matched) {
+		return []search.Hit{}, len(matched), nil
+	}
+	end := min(offset+limit, len(matched))
+	hits := make([]search.Hit, 0, end-offset)
+	for _, m := range matched[offset:end] {
+		doc := m.e.doc
+		hits = append(hits, search.Hit{
+			Id:         doc.Id,
+			AuthorId:   doc.AuthorId,
+			AuthorName: doc.AuthorName,
+			Utime:      doc.Utime,
+			Title:      highlight(doc.Title, terms),
+			Snippet:    snippet(doc.Content, terms),
+			Score:      m.score,
+		})
+	}
+	return hits, len(matched), nil
+}
@@ -0,0 +1,196 @@
+package local
+
+import (
+	"context"
+	"ddd_demo/internal/service/search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIndex_Search(t *testing.T) {
+	docs := []search.Document{
+		{
+			Id:         1,
+			Title:      "Go 语言入门",
+			Content:    "学习 Golang 的第一步，从安装开始",
+			AuthorId:   100,
+			AuthorName: "大明",
+			Utime:      time.UnixMilli(1000),
+		},
+		{
+			Id:         2,
+			Title:      "搜索引擎原理",
+			Content:    "倒排索引是搜索引擎的核心，Go 也能写",
+			AuthorId:   200,
+			AuthorName: "小明",
+			Utime:      time.UnixMilli(2000),
+		},
+		{
+			Id:         3,
+			Title:      "<script>alert(1)</script> 安全",
+			Content:    "前端要做好转义",
+			AuthorId:   100,
+			AuthorName: "大明",
+			Utime:      time.UnixMilli(3000),
+		},
+	}
+	testCases := []struct {
+		name  string
+		query string
+
+		wantIds    []int64
+		wantTotal  int
+		wantTitles []string
+	}{
+		{
+			name:       "英文不区分大小写，标题命中的排在前面",
+			query:      "GO",
+			wantIds:    []int64{1, 2},
+			wantTotal:  2,
+			wantTitles: []string{"<em>Go</em> 语言入门", "搜索引擎原理"},
+		},
+		{
+			name:       "中文按照 bigram 匹配",
+			query:      "搜索引擎",
+			wantIds:    []int64{2},
+			wantTotal:  1,
+			wantTitles: []string{"<em>搜索引擎</em>原理"},
+		},
+		{
+			name:      "单个字也能搜到",
+			query:     "明",
+			wantIds:   []int64{3, 2, 1},
+			wantTotal: 3,
+		},
+		{
+			name:      "所有的词都要命中",
+			query:     "Go 安装",
+			wantIds:   []int64{1},
+			wantTotal: 1,
+		},
+		{
+			name:      "作者名字也能搜",
+			query:     "小明",
+			wantIds:   []int64{2},
+			wantTotal: 1,
+		},
+		{
+			name:       "标题要做 HTML 转义",
+			query:      "安全",
+			wantIds:    []int64{3},
+			wantTotal:  1,
+			wantTitles: []string{"&lt;script&gt;alert(1)&lt;/script&gt; <em>安全</em>"},
+		},
+		{
+			name:  "没有命中",
+			query: "Java",
+		},
+		{
+			name:  "只有标点符号",
+			query: "，。!",
+		},
+	}
+	idx := NewIndex()
+	for _, doc := range docs {
+		require.NoError(t, idx.Upsert(context.Background(), doc))
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hits, total, err := idx.Search(context.Background(), tc.query, 0, 10)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantTotal, total)
+			var ids []int64
+			var titles []string
+			for _, h := range hits {
+				ids = append(ids, h.Id)
+				titles = append(titles, h.Title)
+			}
+			assert.Equal(t, tc.wantIds, ids)
+			if tc.wantTitles != nil {
+				assert.Equal(t, tc.wantTitles, titles)
+			}
+		})
+	}
+}
+
+func TestIndex_Update(t *testing.T) {
+	ctx := context.Background()
+	idx := NewIndex()
+	require.NoError(t, idx.Upsert(ctx, search.Document{Id: 1, Title: "旧标题", AuthorId: 100}))
+	require.NoError(t, idx.Upsert(ctx, search.Document{Id: 2, Title: "另一篇标题", AuthorId: 100}))
+	require.NoError(t, idx.Upsert(ctx, search.Document{Id: 3, Title: "别人的标题", AuthorId: 200}))
+
+	// 覆盖之后，旧的内容就搜不到了
+	require.NoError(t, idx.Upsert(ctx, search.Document{Id: 1, Title: "新标题", AuthorId: 100}))
+	_, total, err := idx.Search(ctx, "旧标题", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+	_, total, err = idx.Search(ctx, "新标题", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+
+	// 分页
+	hits, total, err := idx.Search(ctx, "标题", 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Len(t, hits, 1)
+	hits, total, err = idx.Search(ctx, "标题", 10, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Empty(t, hits)
+
+	require.NoError(t, idx.Delete(ctx, 3))
+	// 删除不存在的文档不算错
+	require.NoError(t, idx.Delete(ctx, 3))
+	_, total, err = idx.Search(ctx, "标题", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+
+	require.NoError(t, idx.DeleteByAuthor(ctx, 100))
+	_, total, err = idx.Search(ctx, "标题", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+	// 倒排表也要清理干净
+	assert.Empty(t, idx.(*Index).postings)
+	assert.Empty(t, idx.(*Index).authors)
+}
+
+func TestSnippet(t *testing.T) {
+	terms := map[string]struct{}{"命中": {}}
+	testCases := []struct {
+		name string
+		text string
+
+		want string
+	}{
+		{
+			name: "内容很短，不截断",
+			text: "这里命中了",
+			want: "这里<em>命中</em>了",
+		},
+		{
+			name: "命中的地方在后面，前面留一点上下文",
+			text: strings.Repeat("前", 200) + "命中" + strings.Repeat("后", 200),
+			want: "..." + strings.Repeat("前", 30) + "<em>命中</em>" +
+				strings.Repeat("后", 88) + "...",
+		},
+		{
+			name: "命中的地方在最后",
+			text: strings.Repeat("前", 200) + "命中",
+			want: "..." + strings.Repeat("前", 118) + "<em>命中</em>",
+		},
+		{
+			name: "没有命中，取开头",
+			text: strings.Repeat("字", 200),
+			want: strings.Repeat("字", 120) + "...",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, snippet(tc.text, terms))
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package local
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// token 分词的结果，start 和 end 是在原文里面的字节偏移，高亮的时候用
+type token struct {
+	term  string
+	start int
+	end   int
+}
+
+// tokenize 英文、数字按照单词切分并且转成小写；
+// 中日韩文字没有空格，按照单字和相邻两个字（bigram）切分。
+// 建索引的时候单字和 bigram 都要，这样搜一个字也能搜到
+func tokenize(text string) []token {
+	return split(text, true)
+}
+
+// tokenizeQuery 和 tokenize 的区别是，连续的中文只用 bigram，
+// 只有一个字的时候才用单字，不然搜"搜索引擎"会把只有"搜"字的文章也搜出来
+func tokenizeQuery(text string) []token {
+	return split(text, false)
+}
+
+func split(text string, unigram bool) []token {
+	var (
+		res []token
+		// 正在处理的英文单词
+		word      []rune
+		wordStart = -1
+		// 正在处理的连续中文，记录每个字的起止位置
+		cjk []token
+	)
+	flushWord := func(end int) {
+		if wordStart >= 0 {
+			res = append(res, token{term: string(word), start: wordStart, end: end})
+			word = word[:0]
+			wordStart = -1
+		}
+	}
+	flushCJK := func() {
+		if len(cjk) == 1 || (unigram && len(cjk) > 0) {
+			res = append(res, cjk...)
+		}
+		for i := 0; i+1 < len(cjk); i++ {
+			res = append(res, token{
+				term:  cjk[i].term + cjk[i+1].term,
+				start: cjk[i].start,
+				end:   cjk[i+1].end,
+			})
+		}
+		cjk = cjk[:0]
+	}
+	for i, r := range text {
+		switch {
+		case isCJK(r):
+			flushWord(i)
+			cjk = append(cjk, token{term: string(r), start: i, end: i + utf8.RuneLen(r)})
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			if wordStart < 0 {
+				wordStart = i
+			}
+			word = append(word, unicode.ToLower(r))
+		default:
+			flushWord(i)
+			flushCJK()
+		}
+	}
+	flushWord(len(text))
+	flushCJK()
+	return res
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
@@ -0,0 +1,98 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./types.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./types.go -package=searchmocks -destination=./mocks/index.mock.go Index
+//
+// Package searchmocks is a generated GoMock package.
+package searchmocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	search "ddd_demo/internal/service/search"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIndex is a mock of Index interface.
+type MockIndex struct {
+	ctrl     *gomock.Controller
+	recorder *MockIndexMockRecorder
+}
+
+// MockIndexMockRecorder is the mock recorder for MockIndex.
+type MockIndexMockRecorder struct {
+	mock *MockIndex
+}
+
+// NewMockIndex creates a new mock instance.
+func NewMockIndex(ctrl *gomock.Controller) *MockIndex {
+	mock := &MockIndex{ctrl: ctrl}
+	mock.recorder = &MockIndexMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIndex) EXPECT() *MockIndexMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockIndex) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockIndexMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIndex)(nil).Delete), ctx, id)
+}
+
+// DeleteByAuthor mocks base method.
+func (m *MockIndex) DeleteByAuthor(ctx context.Context, authorId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByAuthor", ctx, authorId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByAuthor indicates an expected call of DeleteByAuthor.
+func (mr *MockIndexMockRecorder) DeleteByAuthor(ctx, authorId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByAuthor", reflect.TypeOf((*MockIndex)(nil).DeleteByAuthor), ctx, authorId)
+}
+
+// Search mocks base method.
+func (m *MockIndex) Search(ctx context.Context, query string, offset, limit int) ([]search.Hit, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query, offset, limit)
+	ret0, _ := ret[0].([]search.Hit)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockIndexMockRecorder) Search(ctx, query, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockIndex)(nil).Search), ctx, query, offset, limit)
+}
+
+// Upsert mocks base method.
+func (m *MockIndex) Upsert(ctx context.Context, doc search.Document) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, doc)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockIndexMockRecorder) Upsert(ctx, doc any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockIndex)(nil).Upsert), ctx, doc)
+}
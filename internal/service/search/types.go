@@ -0,0 +1,44 @@
+package search
+
+import (
+	"context"
+	"time"
+)
+
+// Index 全文索引的抽象
+// 屏蔽本地索引和 Elasticsearch 这些外部搜索引擎之间的区别
+//
+//go:generate mockgen -source=./types.go -package=searchmocks -destination=./mocks/index.mock.go Index
+type Index interface {
+	// Upsert 写入一篇文档，已经有了就整个覆盖掉
+	Upsert(ctx context.Context, doc Document) error
+	// Delete 文档不存在也不会返回 error
+	Delete(ctx context.Context, id int64) error
+	// DeleteByAuthor 删除作者所有的文档
+	DeleteByAuthor(ctx context.Context, authorId int64) error
+	// Search 按照相关度从高到低排序，total 是命中的总数
+	Search(ctx context.Context, query string, offset int, limit int) (hits []Hit, total int, err error)
+}
+
+// Document 索引里面的一篇文章
+type Document struct {
+	Id         int64
+	Title      string
+	Content    string
+	AuthorId   int64
+	AuthorName string
+	Utime      time.Time
+}
+
+// Hit 一条搜索结果
+type Hit struct {
+	Id         int64
+	AuthorId   int64
+	AuthorName string
+	Utime      time.Time
+	// Title 和 Snippet 都已经做过 HTML 转义，命中的地方用 <em></em> 包起来
+	Title string
+	// Snippet 内容里面命中的那一段
+	Snippet string
+	Score   float64
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository"
+	repomocks "ddd_demo/internal/repository/mocks"
+	"ddd_demo/internal/service/search"
+	searchmocks "ddd_demo/internal/service/search/mocks"
+	"ddd_demo/pkg/logger"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func Test_searchService_IndexArticle(t *testing.T) {
+	now := time.UnixMilli(1000)
+	testCases := []struct {
+		name string
+
+		mock func(ctrl *gomock.Controller) (search.Index, repository.ArticleRepository)
+
+		wantErr error
+	}{
+		{
+			name: "已经发表，写入索引",
+			mock: func(ctrl *gomock.Controller) (search.Index, repository.ArticleRepository) {
+				idx := searchmocks.NewMockIndex(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().FindPubById(gomock.Any(), int64(1)).
+					Return(domain.Article{
+						Id:      1,
+						Title:   "标题",
+						Content: "内容",
+						Author:  domain.Author{Id: 123, Name: "大明"},
+						Status:  domain.ArticleStatusPublished,
+						Utime:   now,
+					}, nil)
+				idx.EXPECT().Upsert(gomock.Any(), search.Document{
+					Id:         1,
+					Title:      "标题",
+					Content:    "内容",
+					AuthorId:   123,
+					AuthorName: "大明",
+					Utime:      now,
+				}).Return(nil)
+				return idx, artRepo
+			},
+		},
+		{
+			name: "已经撤回，从索引里面删掉",
+			mock: func(ctrl *gomock.Controller) (search.Index, repository.ArticleRepository) {
+				idx := searchmocks.NewMockIndex(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().FindPubById(gomock.Any(), int64(1)).
+					Return(domain.Article{
+						Id:     1,
+						Status: domain.ArticleStatusPrivate,
+					}, nil)
+				idx.EXPECT().Delete(gomock.Any(), int64(1)).Return(nil)
+				return idx, artRepo
+			},
+		},
+		{
+			name: "文章不存在，从索引里面删掉",
+			mock: func(ctrl *gomock.Controller) (search.Index, repository.ArticleRepository) {
+				idx := searchmocks.NewMockIndex(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().FindPubById(gomock.Any(), int64(1)).
+					Return(domain.Article{}, repository.ErrArticleNotFound)
+				idx.EXPECT().Delete(gomock.Any(), int64(1)).Return(nil)
+				return idx, artRepo
+			},
+		},
+		{
+			name: "查询文章失败",
+			mock: func(ctrl *gomock.Controller) (search.Index, repository.ArticleRepository) {
+				idx := searchmocks.NewMockIndex(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().FindPubById(gomock.Any(), int64(1)).
+					Return(domain.Article{}, errors.New("mock db error"))
+				return idx, artRepo
+			},
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			idx, artRepo := tc.mock(ctrl)
+			svc := NewSearchService(idx, artRepo, nil, logger.NewNopLogger())
+			err := svc.IndexArticle(context.Background(), 1)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_searchService_Rebuild(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	idx := searchmocks.NewMockIndex(ctrl)
+	artRepo := repomocks.NewMockArticleRepository(ctrl)
+	userRepo := repomocks.NewMockUserRepository(ctrl)
+
+	gomock.InOrder(
+		artRepo.EXPECT().ListPubByCursor(gomock.Any(), gomock.Any(), domain.ArticleCursor{}, 2).
+			Return([]domain.Article{
+				{Id: 1, Author: domain.Author{Id: 123}, Utime: time.UnixMilli(200)},
+				{Id: 2, Author: domain.Author{Id: 456}, Utime: time.UnixMilli(100)},
+			}, nil),
+		artRepo.EXPECT().ListPubByCursor(gomock.Any(), gomock.Any(),
+			domain.ArticleCursor{Utime: time.UnixMilli(100), Id: 2}, 2).
+			Return([]domain.Article{
+				{Id: 3, Author: domain.Author{Id: 123}},
+			}, nil),
+	)
+	// 同一个作者只查一次
+	userRepo.EXPECT().FindById(gomock.Any(), int64(123)).
+		Return(domain.User{Id: 123, Nickname: "大明"}, nil)
+	// 作者已经注销了，跳过
+	userRepo.EXPECT().FindById(gomock.Any(), int64(456)).
+		Return(domain.User{}, repository.ErrUserNotFound)
+	idx.EXPECT().Upsert(gomock.Any(), search.Document{
+		Id: 1, AuthorId: 123, AuthorName: "大明", Utime: time.UnixMilli(200),
+	}).Return(nil)
+	idx.EXPECT().Upsert(gomock.Any(), search.Document{
+		Id: 3, AuthorId: 123, AuthorName: "大明",
+	}).Return(nil)
+
+	svc := NewSearchService(idx, artRepo, userRepo, logger.NewNopLogger())
+	svc.(*searchService).batchSize = 2
+	assert.NoError(t, svc.Rebuild(context.Background()))
+}
+
+func Test_searchService_TransferAuthor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	idx := searchmocks.NewMockIndex(ctrl)
+	artRepo := repomocks.NewMockArticleRepository(ctrl)
+	userRepo := repomocks.NewMockUserRepository(ctrl)
+
+	idx.EXPECT().DeleteByAuthor(gomock.Any(), int64(123)).Return(nil)
+	userRepo.EXPECT().FindById(gomock.Any(), int64(456)).
+		Return(domain.User{Id: 456, Nickname: "小明"}, nil)
+	artRepo.EXPECT().ListPubByAuthor(gomock.Any(), int64(456),
+		[]domain.ArticleVisibility{domain.ArticleVisibilityPublic}, 0, 100).
+		Return([]domain.Article{
+			{Id: 1, Title: "标题", Author: domain.Author{Id: 456}},
+		}, nil)
+	idx.EXPECT().Upsert(gomock.Any(), search.Document{
+		Id: 1, Title: "标题", AuthorId: 456, AuthorName: "小明",
+	}).Return(nil)
+
+	svc := NewSearchService(idx, artRepo, userRepo, logger.NewNopLogger())
+	assert.NoError(t, svc.TransferAuthor(context.Background(), 123, 456))
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository"
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrSeriesNotFound = repository.ErrSeriesNotFound
+	// ErrSeriesArticleConflict 一篇文章只能放在一个专栏里面
+	ErrSeriesArticleConflict = repository.ErrSeriesArticleConflict
+	ErrInvalidSeries         = errors.New("专栏标题不能为空，标题和简介也不能太长")
+	// ErrInvalidSeriesArticles 文章重复了、太多了，或者不是作者自己发表过的文章
+	ErrInvalidSeriesArticles = errors.New("专栏里面的文章不对")
+)
+
+const (
+	maxSeriesTitleLen       = 128
+	maxSeriesDescriptionLen = 1000
+	// maxSeriesArticles 一个专栏最多多少篇文章
+	maxSeriesArticles = 200
+)
+
+//go:generate mockgen -source=./series.go -package=svcmocks -destination=./mocks/series.mock.go SeriesService
+type SeriesService interface {
+	// Save Id 为 0 是新建，返回专栏的 ID。只改标题和简介，目录用 SetArticles 改
+	Save(ctx context.Context, s domain.Series) (int64, error)
+	// Delete 文章本身不会删掉
+	Delete(ctx context.Context, uid int64, id int64) error
+	// SetArticles 整个替换专栏的目录，aids 的顺序就是目录的顺序。
+	// 只能放作者自己发表过的文章，一篇文章只能放在一个专栏里面
+	SetArticles(ctx context.Context, uid int64, id int64, aids []int64) error
+	// ListByAuthor 作者所有的专栏，不带目录
+	ListByAuthor(ctx context.Context, uid int64) ([]domain.Series, error)
+	// Toc 专栏和目录，目录里面只有 viewer 在作者主页上也能看到的文章
+	Toc(ctx context.Context, id int64, viewer int64) (domain.Series, error)
+	// Nav 文章在专栏里面的上一篇、下一篇，跳过 viewer 看不到的。
+	// 文章不在任何专栏里面返回 ErrSeriesNotFound
+	Nav(ctx context.Context, aid int64, viewer int64) (domain.SeriesNav, error)
+}
+
+type seriesService struct {
+	repo       repository.SeriesRepository
+	artRepo    repository.ArticleRepository
+	followRepo repository.FollowRepository
+}
+
+func NewSeriesService(repo repository.SeriesRepository,
+	artRepo repository.ArticleRepository,
+	followRepo repository.FollowRepository) SeriesService {
+	return &seriesService{
+		repo:       repo,
+		artRepo:    artRepo,
+		followRepo: followRepo,
+	}
+}
+
+func (svc *seriesService) Save(ctx context.Context, s domain.Series) (int64, error) {
+	s.Title = strings.TrimSpace(s.Title)
+	s.Description = strings.TrimSpace(s.Description)
+	if s.Title == "" ||
+		utf8.RuneCountInString(s.Title) > maxSeriesTitleLen ||
+		utf8.RuneCountInString(s.Description) > maxSeriesDescriptionLen {
+		return 0, ErrInvalidSeries
+	}
+	if s.Id > 0 {
+		return s.Id, svc.repo.Update(ctx, s)
+	}
+	return svc.repo.Create(ctx, s)
+}
+
+func (svc *seriesService) Delete(ctx context.Context, uid int64, id int64) error {
+	return svc.repo.Delete(ctx, uid, id)
+}
+
+func (svc *seriesService) SetArticles(ctx context.Context, uid int64, id int64, aids []int64) error {
+	if len(aids) > maxSeriesArticles {
+		return ErrInvalidSeriesArticles
+	}
+	seen := make(map[int64]struct{}, len(aids))
+	for _, aid := range aids {
+		if _, ok := seen[aid]; ok {
+			return ErrInvalidSeriesArticles
+		}
+		seen[aid] = struct{}{}
+	}
+	// 从来没有发表过的草稿线上库里面没有，放进目录里面谁也看不到，所以不让放。
+	// 撤回了的还在线上库里面，可以放，只是除了作者自己别人看不到
+	arts, err := svc.artRepo.GetPubByIds(ctx, aids)
+	if err != nil {
+		return err
+	}
+	if len(arts) != len(aids) {
+		return ErrInvalidSeriesArticles
+	}
+	for _, art := range arts {
+		if art.Author.Id != uid {
+			return ErrInvalidSeriesArticles
+		}
+	}
+	return svc.repo.ReplaceArticles(ctx, uid, id, aids)
+}
+
+func (svc *seriesService) ListByAuthor(ctx context.Context, uid int64) ([]domain.Series, error) {
+	return svc.repo.FindByAuthor(ctx, uid)
+}
+
+func (svc *seriesService) Toc(ctx context.Context, id int64, viewer int64) (domain.Series, error) {
+	s, err := svc.repo.FindById(ctx, id)
+	if err != nil {
+		return domain.Series{}, err
+	}
+	aids, err := svc.repo.FindArticleIds(ctx, id)
+	if err != nil {
+		return domain.Series{}, err
+	}
+	s.Articles, err = svc.visibleArticles(ctx, s.Author.Id, viewer, aids)
+	return s, err
+}
+
+func (svc *seriesService) Nav(ctx context.Context, aid int64, viewer int64) (domain.SeriesNav, error) {
+	sid, err := svc.repo.FindIdByArticle(ctx, aid)
+	if err != nil {
+		return domain.SeriesNav{}, err
+	}
+	s, err := svc.repo.FindById(ctx, sid)
+	if err != nil {
+		return domain.SeriesNav{}, err
+	}
+	aids, err := svc.repo.FindArticleIds(ctx, sid)
+	if err != nil {
+		return domain.SeriesNav{}, err
+	}
+	arts, err := svc.visibleArticles(ctx, s.Author.Id, viewer, aids)
+	if err != nil {
+		return domain.SeriesNav{}, err
+	}
+	// 正在看的这篇可能是不公开列出的，或者是分享链接打开的，不在 arts 里面，
+	// 所以按照它在整个目录里面的位置找前后能看到的
+	pos := make(map[int64]int, len(aids))
+	for i, id := range aids {
+		pos[id] = i
+	}
+	cur, ok := pos[aid]
+	if !ok {
+		// 刚刚被移出专栏
+		return domain.SeriesNav{}, ErrSeriesNotFound
+	}
+	res := domain.SeriesNav{Series: s}
+	for _, art := range arts {
+		p := pos[art.Id]
+		if p < cur {
+			res.Prev = art
+		}
+		if p > cur {
+			res.Next = art
+			break
+		}
+	}
+	return res, nil
+}
+
+// visibleArticles 按照 aids 的顺序返回 viewer 能看到的文章，uid 是专栏的作者。
+// 目录里面都是作者自己的文章，所以和作者主页的规则一样，不公开列出的也不出现在目录里面
+func (svc *seriesService) visibleArticles(ctx context.Context,
+	uid int64, viewer int64, aids []int64) ([]domain.Article, error) {
+	if len(aids) == 0 {
+		return []domain.Article{}, nil
+	}
+	arts, err := svc.artRepo.GetPubByIds(ctx, aids)
+	if err != nil {
+		return nil, err
+	}
+	vs, err := visibilitiesFor(ctx, svc.followRepo, uid, viewer)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[int64]domain.Article, len(arts))
+	for _, art := range arts {
+		if svc.visible(art, uid, viewer, vs) {
+			m[art.Id] = art
+		}
+	}
+	res := make([]domain.Article, 0, len(m))
+	for _, aid := range aids {
+		if art, ok := m[aid]; ok {
+			res = append(res, art)
+		}
+	}
+	return res, nil
+}
+
+func (svc *seriesService) visible(art domain.Article,
+	uid int64, viewer int64, vs []domain.ArticleVisibility) bool {
+	if uid == viewer {
+		return true
+	}
+	if art.Status != domain.ArticleStatusPublished {
+		return false
+	}
+	for _, v := range vs {
+		if art.Visibility == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository"
+	repomocks "ddd_demo/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func Test_seriesService_SetArticles(t *testing.T) {
+	pub := func(id int64, uid int64) domain.Article {
+		return domain.Article{Id: id, Author: domain.Author{Id: uid}}
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.SeriesRepository, repository.ArticleRepository)
+		aids []int64
+
+		wantErr error
+	}{
+		{
+			name: "按照传进来的顺序替换",
+			mock: func(ctrl *gomock.Controller) (repository.SeriesRepository, repository.ArticleRepository) {
+				repo := repomocks.NewMockSeriesRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPubByIds(gomock.Any(), []int64{3, 1, 2}).
+					Return([]domain.Article{pub(1, 123), pub(2, 123), pub(3, 123)}, nil)
+				repo.EXPECT().ReplaceArticles(gomock.Any(), int64(123), int64(10), []int64{3, 1, 2}).
+					Return(nil)
+				return repo, artRepo
+			},
+			aids: []int64{3, 1, 2},
+		},
+		{
+			name: "清空目录",
+			mock: func(ctrl *gomock.Controller) (repository.SeriesRepository, repository.ArticleRepository) {
+				repo := repomocks.NewMockSeriesRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPubByIds(gomock.Any(), []int64{}).Return(nil, nil)
+				repo.EXPECT().ReplaceArticles(gomock.Any(), int64(123), int64(10), []int64{}).
+					Return(nil)
+				return repo, artRepo
+			},
+			aids: []int64{},
+		},
+		{
+			name: "文章重复了",
+			mock: func(ctrl *gomock.Controller) (repository.SeriesRepository, repository.ArticleRepository) {
+				return repomocks.NewMockSeriesRepository(ctrl), repomocks.NewMockArticleRepository(ctrl)
+			},
+			aids:    []int64{1, 2, 1},
+			wantErr: ErrInvalidSeriesArticles,
+		},
+		{
+			name: "没有发表过的草稿",
+			mock: func(ctrl *gomock.Controller) (repository.SeriesRepository, repository.ArticleRepository) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPubByIds(gomock.Any(), []int64{1, 2}).
+					Return([]domain.Article{pub(1, 123)}, nil)
+				return repomocks.NewMockSeriesRepository(ctrl), artRepo
+			},
+			aids:    []int64{1, 2},
+			wantErr: ErrInvalidSeriesArticles,
+		},
+		{
+			name: "别人的文章",
+			mock: func(ctrl *gomock.Controller) (repository.SeriesRepository, repository.ArticleRepository) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPubByIds(gomock.Any(), []int64{1, 2}).
+					Return([]domain.Article{pub(1, 123), pub(2, 456)}, nil)
+				return repomocks.NewMockSeriesRepository(ctrl), artRepo
+			},
+			aids:    []int64{1, 2},
+			wantErr: ErrInvalidSeriesArticles,
+		},
+		{
+			name: "文章已经在别的专栏里面了",
+			mock: func(ctrl *gomock.Controller) (repository.SeriesRepository, repository.ArticleRepository) {
+				repo := repomocks.NewMockSeriesRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPubByIds(gomock.Any(), []int64{1}).
+					Return([]domain.Article{pub(1, 123)}, nil)
+				repo.EXPECT().ReplaceArticles(gomock.Any(), int64(123), int64(10), []int64{1}).
+					Return(repository.ErrSeriesArticleConflict)
+				return repo, artRepo
+			},
+			aids:    []int64{1},
+			wantErr: ErrSeriesArticleConflict,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, artRepo := tc.mock(ctrl)
+			svc := NewSeriesService(repo, artRepo, repomocks.NewMockFollowRepository(ctrl))
+			err := svc.SetArticles(context.Background(), 123, 10, tc.aids)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_seriesService_Nav(t *testing.T) {
+	series := domain.Series{Id: 10, Author: domain.Author{Id: 123}, Title: "Go 入门"}
+	pub := func(id int64, status domain.ArticleStatus, v domain.ArticleVisibility) domain.Article {
+		return domain.Article{Id: id, Title: "第几篇", Author: domain.Author{Id: 123},
+			Status: status, Visibility: v}
+	}
+	// 目录是 1 2 3 4 5：2 是仅关注者可见，3 是不公开列出，4 撤回了
+	arts := []domain.Article{
+		pub(5, domain.ArticleStatusPublished, domain.ArticleVisibilityPublic),
+		pub(1, domain.ArticleStatusPublished, domain.ArticleVisibilityPublic),
+		pub(2, domain.ArticleStatusPublished, domain.ArticleVisibilityFollowers),
+		pub(3, domain.ArticleStatusPublished, domain.ArticleVisibilityUnlisted),
+		pub(4, domain.ArticleStatusPrivate, domain.ArticleVisibilityPublic),
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.SeriesRepository,
+			repository.ArticleRepository, repository.FollowRepository)
+		aid    int64
+		viewer int64
+
+		wantPrev int64
+		wantNext int64
+		wantErr  error
+	}{
+		{
+			name: "没关注作者，跳过看不到的",
+			mock: func(ctrl *gomock.Controller) (repository.SeriesRepository,
+				repository.ArticleRepository, repository.FollowRepository) {
+				followRepo := repomocks.NewMockFollowRepository(ctrl)
+				followRepo.EXPECT().FollowInfo(gomock.Any(), int64(456), int64(123)).
+					Return(domain.FollowRelation{}, repository.ErrFollowRelationNotFound)
+				return navSeriesRepo(ctrl, series, 2), navArticleRepo(ctrl, arts), followRepo
+			},
+			aid:      2,
+			viewer:   456,
+			wantPrev: 1,
+			wantNext: 5,
+		},
+		{
+			name: "关注了作者",
+			mock: func(ctrl *gomock.Controller) (repository.SeriesRepository,
+				repository.ArticleRepository, repository.FollowRepository) {
+				followRepo := repomocks.NewMockFollowRepository(ctrl)
+				followRepo.EXPECT().FollowInfo(gomock.Any(), int64(456), int64(123)).
+					Return(domain.FollowRelation{Follower: 456, Followee: 123}, nil)
+				return navSeriesRepo(ctrl, series, 3), navArticleRepo(ctrl, arts), followRepo
+			},
+			aid:      3,
+			viewer:   456,
+			wantPrev: 2,
+			wantNext: 5,
+		},
+		{
+			name: "作者自己什么都能看到",
+			mock: func(ctrl *gomock.Controller) (repository.SeriesRepository,
+				repository.ArticleRepository, repository.FollowRepository) {
+				return navSeriesRepo(ctrl, series, 5), navArticleRepo(ctrl, arts),
+					repomocks.NewMockFollowRepository(ctrl)
+			},
+			aid:      5,
+			viewer:   123,
+			wantPrev: 4,
+		},
+		{
+			name: "不在专栏里面",
+			mock: func(ctrl *gomock.Controller) (repository.SeriesRepository,
+				repository.ArticleRepository, repository.FollowRepository) {
+				repo := repomocks.NewMockSeriesRepository(ctrl)
+				repo.EXPECT().FindIdByArticle(gomock.Any(), int64(6)).
+					Return(int64(0), repository.ErrSeriesNotFound)
+				return repo, repomocks.NewMockArticleRepository(ctrl),
+					repomocks.NewMockFollowRepository(ctrl)
+			},
+			aid:     6,
+			viewer:  456,
+			wantErr: ErrSeriesNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewSeriesService(tc.mock(ctrl))
+			nav, err := svc.Nav(context.Background(), tc.aid, tc.viewer)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, series, nav.Series)
+			assert.Equal(t, tc.wantPrev, nav.Prev.Id)
+			assert.Equal(t, tc.wantNext, nav.Next.Id)
+		})
+	}
+}
+
+// navSeriesRepo aid 在专栏 s 里面，目录是 1 2 3 4 5
+func navSeriesRepo(ctrl *gomock.Controller, s domain.Series, aid int64) repository.SeriesRepository {
+	repo := repomocks.NewMockSeriesRepository(ctrl)
+	repo.EXPECT().FindIdByArticle(gomock.Any(), aid).Return(s.Id, nil)
+	repo.EXPECT().FindById(gomock.Any(), s.Id).Return(s, nil)
+	repo.EXPECT().FindArticleIds(gomock.Any(), s.Id).Return([]int64{1, 2, 3, 4, 5}, nil)
+	return repo
+}
+
+func navArticleRepo(ctrl *gomock.Controller, arts []domain.Article) repository.ArticleRepository {
+	repo := repomocks.NewMockArticleRepository(ctrl)
+	repo.EXPECT().GetPubByIds(gomock.Any(), []int64{1, 2, 3, 4, 5}).Return(arts, nil)
+	return repo
+}
@@ -2,16 +2,39 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
 	"ddd_demo/internal/domain"
 	"ddd_demo/internal/repository"
+	"ddd_demo/internal/service/email"
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
+	"strconv"
+	"strings"
+	"time"
 )
 
 var (
 	ErrDuplicateEmail        = repository.ErrDuplicateUser
+	ErrUserNotFound          = repository.ErrUserNotFound
 	ErrInvalidUserOrPassword = errors.New("用户不存在或者密码不对")
+	ErrTokenSendTooMany      = repository.ErrTokenSendTooMany
+	ErrInvalidToken          = errors.New("链接无效或者已经过期")
+	ErrEmailNotBound         = errors.New("没有绑定邮箱")
+	ErrIdentityBound         = errors.New("已经被别的账号绑定了")
+	ErrLastIdentity          = errors.New("至少要保留一种登录方式")
+)
+
+const (
+	bizResetPassword = "reset_password"
+	bizVerifyEmail   = "verify_email"
+	bizBindEmail     = "bind_email"
+
+	resetPasswordURLPattern = "https://meoying.com/users/password/reset?token=%s"
+	verifyEmailURLPattern   = "https://meoying.com/users/email/verify?token=%s"
+	bindEmailURLPattern     = "https://meoying.com/users/bind/email/confirm?token=%s"
 )
 
 //go:generate mockgen -source=./user.go -package=svcmocks -destination=./mocks/user.mock.go UserService
@@ -24,16 +47,64 @@ type UserService interface {
 		uid int64) (domain.User, error)
 	FindOrCreate(ctx context.Context, phone string) (domain.User, error)
 	FindOrCreateByWechat(ctx context.Context, info domain.WechatInfo) (domain.User, error)
+
+	// SendResetPasswordEmail 发送重置密码的邮件，邮箱没有注册、发送太频繁都不会返回 error，
+	// 防止有人用这个接口探测哪些邮箱注册过
+	SendResetPasswordEmail(ctx context.Context, email string) error
+	// ResetPassword 用邮件里面的 token 重置密码，返回被重置的用户 id
+	ResetPassword(ctx context.Context, token string, password string) (int64, error)
+	// SendVerifyEmail 给用户绑定的邮箱发送验证邮件
+	SendVerifyEmail(ctx context.Context, uid int64) error
+	// VerifyEmail 用邮件里面的 token 验证邮箱
+	VerifyEmail(ctx context.Context, token string) error
+
+	// BindPhone 手机号需要调用方先校验验证码
+	BindPhone(ctx context.Context, uid int64, phone string) error
+	// BindEmail 给要绑定的邮箱发一封确认邮件，这时候还没有绑定
+	BindEmail(ctx context.Context, uid int64, email string) error
+	// ConfirmBindEmail 用确认邮件里面的 token 绑定邮箱。
+	// 能收到邮件说明邮箱是自己的，绑定之后就是验证过的状态
+	ConfirmBindEmail(ctx context.Context, token string) error
+	BindWechat(ctx context.Context, uid int64, info domain.WechatInfo) error
+	// Unbind 解绑之后没有任何登录方式的话，返回 ErrLastIdentity。
+	// typ 不是手机号、邮箱、微信的话，就当成是第三方登录的名字
+	Unbind(ctx context.Context, uid int64, typ domain.IdentityType) error
+
+	// FindOrCreateByOAuth2 第三方账号没有绑定过的话，创建一个新用户
+	FindOrCreateByOAuth2(ctx context.Context, info domain.OAuth2Info) (domain.User, error)
+	BindOAuth2(ctx context.Context, uid int64, info domain.OAuth2Info) error
+
+	// EnrollTOTP 生成新的密钥，返回密钥和 otpauth URI。
+	// 用户用验证码激活之后才会启用
+	EnrollTOTP(ctx context.Context, uid int64) (string, string, error)
+	// ActivateTOTP 启用二次验证，返回恢复码的明文，只有这一次机会能看到
+	ActivateTOTP(ctx context.Context, uid int64, code string) ([]string, error)
+	DisableTOTP(ctx context.Context, uid int64, code string) error
+	TOTPEnabled(ctx context.Context, uid int64) (bool, error)
+	// VerifyMFA code 可以是验证码，也可以是恢复码，恢复码只能用一次
+	VerifyMFA(ctx context.Context, uid int64, code string) error
 }
 
 type userService struct {
-	repo repository.UserRepository
+	repo         repository.UserRepository
+	identityRepo repository.IdentityRepository
+	totpRepo     repository.TOTPRepository
+	tokenRepo    repository.OneTimeTokenRepository
+	emailSvc     email.Service
 	//logger *zap.Logger
 }
 
-func NewUserService(repo repository.UserRepository) UserService {
+func NewUserService(repo repository.UserRepository,
+	identityRepo repository.IdentityRepository,
+	totpRepo repository.TOTPRepository,
+	tokenRepo repository.OneTimeTokenRepository,
+	emailSvc email.Service) UserService {
 	return &userService{
-		repo: repo,
+		repo:         repo,
+		identityRepo: identityRepo,
+		totpRepo:     totpRepo,
+		tokenRepo:    tokenRepo,
+		emailSvc:     emailSvc,
 		//logger: zap.L(),
 	}
 }
@@ -114,3 +185,232 @@ func (svc *userService) FindOrCreateByWechat(ctx context.Context, wechatInfo dom
 	}
 	return svc.repo.FindByWechat(ctx, wechatInfo.OpenId)
 }
+
+func (svc *userService) SendResetPasswordEmail(ctx context.Context, email string) error {
+	u, err := svc.repo.FindByEmail(ctx, email)
+	if err == repository.ErrUserNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	token, err := svc.generateToken()
+	if err != nil {
+		return err
+	}
+	uid := strconv.FormatInt(u.Id, 10)
+	err = svc.tokenRepo.Set(ctx, bizResetPassword, uid, token, uid, time.Minute*15)
+	if err == repository.ErrTokenSendTooMany {
+		// 没有注册的邮箱不会走到这里，要是返回发送太频繁，
+		// 就等于告诉对方这个邮箱注册过了。之前的邮件还有效，直接忽略就可以
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return svc.emailSvc.Send(ctx, email, "重置密码",
+		fmt.Sprintf("点击下面的链接重置密码，15 分钟内有效：\n"+resetPasswordURLPattern, token))
+}
+
+func (svc *userService) ResetPassword(ctx context.Context, token string, password string) (int64, error) {
+	val, err := svc.tokenRepo.Consume(ctx, bizResetPassword, token)
+	if err == repository.ErrTokenNotFound {
+		return 0, ErrInvalidToken
+	}
+	if err != nil {
+		return 0, err
+	}
+	uid, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return 0, err
+	}
+	return uid, svc.repo.UpdatePassword(ctx, uid, string(hash))
+}
+
+func (svc *userService) SendVerifyEmail(ctx context.Context, uid int64) error {
+	u, err := svc.repo.FindById(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if u.Email == "" {
+		return ErrEmailNotBound
+	}
+	token, err := svc.generateToken()
+	if err != nil {
+		return err
+	}
+	// 把邮箱也记下来，验证的时候邮箱已经换了的话，就不能算验证通过
+	val := fmt.Sprintf("%d:%s", u.Id, u.Email)
+	err = svc.tokenRepo.Set(ctx, bizVerifyEmail, strconv.FormatInt(u.Id, 10),
+		token, val, time.Hour*24)
+	if err != nil {
+		return err
+	}
+	return svc.emailSvc.Send(ctx, u.Email, "验证邮箱",
+		fmt.Sprintf("点击下面的链接验证邮箱，24 小时内有效：\n"+verifyEmailURLPattern, token))
+}
+
+func (svc *userService) VerifyEmail(ctx context.Context, token string) error {
+	val, err := svc.tokenRepo.Consume(ctx, bizVerifyEmail, token)
+	if err == repository.ErrTokenNotFound {
+		return ErrInvalidToken
+	}
+	if err != nil {
+		return err
+	}
+	segs := strings.SplitN(val, ":", 2)
+	if len(segs) != 2 {
+		return fmt.Errorf("非法的验证邮箱 token 值 %s", val)
+	}
+	uid, err := strconv.ParseInt(segs[0], 10, 64)
+	if err != nil {
+		return err
+	}
+	err = svc.repo.UpdateEmailVerified(ctx, uid, segs[1])
+	if err == repository.ErrUserNotFound {
+		// 邮箱已经换掉了
+		return ErrInvalidToken
+	}
+	return err
+}
+
+func (svc *userService) BindPhone(ctx context.Context, uid int64, phone string) error {
+	return svc.bindErr(svc.repo.UpdatePhone(ctx, uid, phone))
+}
+
+func (svc *userService) BindEmail(ctx context.Context, uid int64, email string) error {
+	token, err := svc.generateToken()
+	if err != nil {
+		return err
+	}
+	// 和验证邮箱一样把邮箱记下来，确认的时候绑定的就是收到邮件的这个邮箱
+	val := fmt.Sprintf("%d:%s", uid, email)
+	err = svc.tokenRepo.Set(ctx, bizBindEmail, strconv.FormatInt(uid, 10),
+		token, val, time.Minute*30)
+	if err != nil {
+		return err
+	}
+	return svc.emailSvc.Send(ctx, email, "绑定邮箱",
+		fmt.Sprintf("点击下面的链接绑定邮箱，30 分钟内有效：\n"+bindEmailURLPattern, token))
+}
+
+func (svc *userService) ConfirmBindEmail(ctx context.Context, token string) error {
+	val, err := svc.tokenRepo.Consume(ctx, bizBindEmail, token)
+	if err == repository.ErrTokenNotFound {
+		return ErrInvalidToken
+	}
+	if err != nil {
+		return err
+	}
+	segs := strings.SplitN(val, ":", 2)
+	if len(segs) != 2 {
+		return fmt.Errorf("非法的绑定邮箱 token 值 %s", val)
+	}
+	uid, err := strconv.ParseInt(segs[0], 10, 64)
+	if err != nil {
+		return err
+	}
+	err = svc.repo.UpdateEmail(ctx, uid, segs[1])
+	if err != nil {
+		return svc.bindErr(err)
+	}
+	// 这一步失败了邮箱也已经绑上了，只是要用户自己再验证一次
+	return svc.repo.UpdateEmailVerified(ctx, uid, segs[1])
+}
+
+func (svc *userService) BindWechat(ctx context.Context, uid int64, info domain.WechatInfo) error {
+	return svc.bindErr(svc.repo.UpdateWechat(ctx, uid, info))
+}
+
+func (svc *userService) bindErr(err error) error {
+	if err == repository.ErrDuplicateUser {
+		return ErrIdentityBound
+	}
+	return err
+}
+
+func (svc *userService) Unbind(ctx context.Context, uid int64, typ domain.IdentityType) error {
+	u, err := svc.repo.FindById(ctx, uid)
+	if err != nil {
+		return err
+	}
+	oauth2Ids, err := svc.identityRepo.FindByUid(ctx, uid)
+	if err != nil {
+		return err
+	}
+	ids := u.Identities()
+	for _, id := range oauth2Ids {
+		ids = append(ids, domain.IdentityType(id.Provider))
+	}
+	// 解绑的是最后一种登录方式
+	if len(ids) == 1 && ids[0] == typ {
+		return ErrLastIdentity
+	}
+	switch typ {
+	case domain.IdentityPhone:
+		return svc.repo.UpdatePhone(ctx, uid, "")
+	case domain.IdentityEmail:
+		return svc.repo.UpdateEmail(ctx, uid, "")
+	case domain.IdentityWechat:
+		return svc.repo.UpdateWechat(ctx, uid, domain.WechatInfo{})
+	default:
+		err = svc.identityRepo.Delete(ctx, uid, string(typ))
+		if err == repository.ErrUserNotFound {
+			// 本来就没有绑定
+			return nil
+		}
+		return err
+	}
+}
+
+func (svc *userService) FindOrCreateByOAuth2(ctx context.Context,
+	info domain.OAuth2Info) (domain.User, error) {
+	identity, err := svc.identityRepo.FindBySubject(ctx, info.Provider, info.Subject)
+	if err == nil {
+		return svc.repo.FindById(ctx, identity.Uid)
+	}
+	if err != repository.ErrUserNotFound {
+		return domain.User{}, err
+	}
+	uid, err := svc.identityRepo.CreateWithUser(ctx, domain.User{
+		Nickname: info.Nickname,
+	}, domain.OAuth2Identity{
+		Provider: info.Provider,
+		Subject:  info.Subject,
+		Email:    info.Email,
+	})
+	if err == repository.ErrDuplicateUser {
+		// 并发登录，别人已经创建好了
+		identity, err = svc.identityRepo.FindBySubject(ctx, info.Provider, info.Subject)
+		if err != nil {
+			return domain.User{}, err
+		}
+		uid = identity.Uid
+	} else if err != nil {
+		return domain.User{}, err
+	}
+	return svc.repo.FindById(ctx, uid)
+}
+
+func (svc *userService) BindOAuth2(ctx context.Context, uid int64, info domain.OAuth2Info) error {
+	return svc.bindErr(svc.identityRepo.Create(ctx, domain.OAuth2Identity{
+		Uid:      uid,
+		Provider: info.Provider,
+		Subject:  info.Subject,
+		Email:    info.Email,
+	}))
+}
+
+// generateToken 生成一个随机的，可以放在 URL 里面的 token
+func (svc *userService) generateToken() (string, error) {
+	data := make([]byte, 32)
+	_, err := rand.Read(data)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	intrv1 "ddd_demo/api/proto/gen/intr/v1"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/events/user"
+	"ddd_demo/internal/repository"
+	"ddd_demo/pkg/logger"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+var (
+	ErrExportRunning  = errors.New("正在导出")
+	ErrExportNotReady = errors.New("还没有可以下载的导出文件")
+)
+
+// exportBatchSize 分批查询文章
+const exportBatchSize = 100
+
+// UserExportConfig 导出个人数据的配置
+type UserExportConfig struct {
+	// 打包好的文件保留多久
+	Expiration time.Duration `yaml:"expiration"`
+	// 打包超时时间，超时之后可以重新导出
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// UserAccountService 注销账号、导出个人数据
+//
+//go:generate mockgen -source=./user_account.go -package=svcmocks -destination=./mocks/user_account.mock.go UserAccountService
+type UserAccountService interface {
+	// Deactivate 注销账号：撤回所有已经发表的文章，抹掉个人信息和登录方式，
+	// 并且通知别的服务删除这个用户的数据。可以重复调用
+	Deactivate(ctx context.Context, uid int64) error
+	// Export 异步打包个人数据，正在打包的时候返回 ErrExportRunning
+	Export(ctx context.Context, uid int64) error
+	// ExportStatus 最近一次导出的状态
+	ExportStatus(ctx context.Context, uid int64) (domain.UserExport, error)
+	// OpenExportFile 打开打包好的文件，调用方要负责关闭。没有打包好返回 ErrExportNotReady
+	OpenExportFile(ctx context.Context, uid int64) (io.ReadCloser, error)
+}
+
+type userAccountService struct {
+	userRepo     repository.UserRepository
+	identityRepo repository.IdentityRepository
+	artRepo      repository.ArticleRepository
+	historyRepo  repository.HistoryRecordRepository
+	exportRepo   repository.UserExportRepository
+	intrSvc      intrv1.InteractiveServiceClient
+	producer     user.Producer
+	cfg          UserExportConfig
+	l            logger.LoggerV1
+}
+
+func NewUserAccountService(userRepo repository.UserRepository,
+	identityRepo repository.IdentityRepository,
+	artRepo repository.ArticleRepository,
+	historyRepo repository.HistoryRecordRepository,
+	exportRepo repository.UserExportRepository,
+	intrSvc intrv1.InteractiveServiceClient,
+	producer user.Producer,
+	cfg UserExportConfig,
+	l logger.LoggerV1) UserAccountService {
+	return &userAccountService{
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		artRepo:      artRepo,
+		historyRepo:  historyRepo,
+		exportRepo:   exportRepo,
+		intrSvc:      intrSvc,
+		producer:     producer,
+		cfg:          cfg,
+		l:            l,
+	}
+}
+
+func (svc *userAccountService) Deactivate(ctx context.Context, uid int64) error {
+	// 每一步都是幂等的，中间失败了重试一遍就可以
+	err := svc.artRepo.WithdrawByAuthor(ctx, uid)
+	if err != nil {
+		return err
+	}
+	err = svc.userRepo.Anonymize(ctx, uid)
+	if err != nil {
+		return err
+	}
+	// 点赞、收藏在 interactive 服务里面，通过消息通知它删除
+	return svc.producer.ProduceDeletedEvent(user.DeletedEvent{Uid: uid})
+}
+
+func (svc *userAccountService) Export(ctx context.Context, uid int64) error {
+	exp, err := svc.exportRepo.Get(ctx, uid)
+	if err != nil {
+		return err
+	}
+	// 超时了就认为上一次打包已经失败了
+	if exp.Status == domain.UserExportStatusRunning &&
+		time.Since(exp.Ctime) < svc.cfg.Timeout {
+		return ErrExportRunning
+	}
+	if exp.File != "" {
+		// 每个用户只保留最近一次的导出文件
+		er := svc.exportRepo.DeleteFile(ctx, exp.File)
+		if er != nil {
+			svc.l.Warn("删除旧的导出文件失败",
+				logger.Int64("uid", uid),
+				logger.String("file", exp.File),
+				logger.Error(er))
+		}
+	}
+	now := time.Now()
+	exp = domain.UserExport{
+		Uid:    uid,
+		Status: domain.UserExportStatusRunning,
+		File:   fmt.Sprintf("user_export/%d/%d.zip", uid, now.UnixMilli()),
+		Ctime:  now,
+	}
+	err = svc.exportRepo.Save(ctx, exp, svc.cfg.Expiration)
+	if err != nil {
+		return err
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), svc.cfg.Timeout)
+		defer cancel()
+		exp.Status = domain.UserExportStatusDone
+		er := svc.archive(ctx, uid, exp.File)
+		if er != nil {
+			svc.l.Error("导出个人数据失败",
+				logger.Int64("uid", uid),
+				logger.Error(er))
+			exp.Status = domain.UserExportStatusFailed
+			exp.File = ""
+		}
+		er = svc.exportRepo.Save(ctx, exp, svc.cfg.Expiration)
+		if er != nil {
+			svc.l.Error("保存导出状态失败",
+				logger.Int64("uid", uid),
+				logger.Error(er))
+		}
+	}()
+	return nil
+}
+
+func (svc *userAccountService) ExportStatus(ctx context.Context, uid int64) (domain.UserExport, error) {
+	return svc.exportRepo.Get(ctx, uid)
+}
+
+func (svc *userAccountService) OpenExportFile(ctx context.Context, uid int64) (io.ReadCloser, error) {
+	exp, err := svc.exportRepo.Get(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if exp.Status != domain.UserExportStatusDone {
+		return nil, ErrExportNotReady
+	}
+	rc, err := svc.exportRepo.OpenFile(ctx, exp.File)
+	if err == repository.ErrExportFileNotFound {
+		// 状态还在，文件已经被存储的生命周期规则删掉了
+		return nil, ErrExportNotReady
+	}
+	return rc, err
+}
+
+// archive 先在本地的临时文件里面打包，再上传到所有节点都能访问的存储上
+func (svc *userAccountService) archive(ctx context.Context, uid int64, key string) error {
+	f, err := os.CreateTemp("", "webook-export-*.zip")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+	err = svc.writeArchive(ctx, uid, f)
+	if err != nil {
+		return err
+	}
+	_, err = f.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+	return svc.exportRepo.SaveFile(ctx, key, f)
+}
+
+// writeArchive 每一类数据一个 JSON 文件，打包成一个 zip
+func (svc *userAccountService) writeArchive(ctx context.Context, uid int64, w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	u, err := svc.userRepo.FindById(ctx, uid)
+	if err != nil {
+		return err
+	}
+	identities, err := svc.identityRepo.FindByUid(ctx, uid)
+	if err != nil {
+		return err
+	}
+	err = svc.writeJSON(zw, "user.json", svc.userData(u, identities))
+	if err != nil {
+		return err
+	}
+
+	var (
+		arts   []domain.Article
+		cursor domain.ArticleCursor
+	)
+	for {
+		batch, er := svc.artRepo.ListByAuthor(ctx, uid, cursor, exportBatchSize)
+		if er != nil {
+			return er
+		}
+		arts = append(arts, batch...)
+		if len(batch) < exportBatchSize {
+			break
+		}
+		cursor = batch[len(batch)-1].Cursor()
+	}
+	err = svc.writeJSON(zw, "articles.json", arts)
+	if err != nil {
+		return err
+	}
+
+	var pubArts []domain.Article
+	for offset := 0; ; offset += exportBatchSize {
+		// 导出的是自己的数据，不管可见范围
+		batch, er := svc.artRepo.ListPubByAuthor(ctx, uid, nil, offset, exportBatchSize)
+		if er != nil {
+			return er
+		}
+		pubArts = append(pubArts, batch...)
+		if len(batch) < exportBatchSize {
+			break
+		}
+	}
+	err = svc.writeJSON(zw, "published_articles.json", pubArts)
+	if err != nil {
+		return err
+	}
+
+	intrs, err := svc.intrSvc.ExportUser(ctx, &intrv1.ExportUserRequest{Uid: uid})
+	if err != nil {
+		return err
+	}
+	err = svc.writeJSON(zw, "likes.json", intrs.GetLikes())
+	if err != nil {
+		return err
+	}
+	err = svc.writeJSON(zw, "collections.json", intrs.GetCollections())
+	if err != nil {
+		return err
+	}
+
+	history, err := svc.historyRepo.FindByUid(ctx, uid)
+	if err != nil {
+		return err
+	}
+	err = svc.writeJSON(zw, "history.json", history)
+	if err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func (svc *userAccountService) writeJSON(zw *zip.Writer, name string, val any) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(val)
+}
+
+// userData 导出的用户信息，不包含密码
+func (svc *userAccountService) userData(u domain.User,
+	identities []domain.OAuth2Identity) any {
+	type Identity struct {
+		Provider string `json:"provider"`
+		Subject  string `json:"subject"`
+		Email    string `json:"email"`
+	}
+	type User struct {
+		Id            int64      `json:"id"`
+		Email         string     `json:"email"`
+		EmailVerified bool       `json:"emailVerified"`
+		Phone         string     `json:"phone"`
+		Nickname      string     `json:"nickname"`
+		Birthday      string     `json:"birthday"`
+		AboutMe       string     `json:"aboutMe"`
+		WechatOpenId  string     `json:"wechatOpenId"`
+		Identities    []Identity `json:"identities"`
+		Ctime         string     `json:"ctime"`
+	}
+	res := User{
+		Id:            u.Id,
+		Email:         u.Email,
+		EmailVerified: u.EmailVerified,
+		Phone:         u.Phone,
+		Nickname:      u.Nickname,
+		Birthday:      u.Birthday.Format(time.DateOnly),
+		AboutMe:       u.AboutMe,
+		WechatOpenId:  u.WechatInfo.OpenId,
+		Ctime:         u.Ctime.Format(time.DateTime),
+	}
+	for _, id := range identities {
+		res.Identities = append(res.Identities, Identity{
+			Provider: id.Provider,
+			Subject:  id.Subject,
+			Email:    id.Email,
+		})
+	}
+	return res
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	intrv1 "ddd_demo/api/proto/gen/intr/v1"
+	intrv1mocks "ddd_demo/api/proto/gen/intr/v1/mocks"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository"
+	repomocks "ddd_demo/internal/repository/mocks"
+	"ddd_demo/pkg/logger"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_userAccountService_OpenExportFile(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.UserExportRepository
+
+		wantContent string
+		wantErr     error
+	}{
+		{
+			name: "打包好了",
+			mock: func(ctrl *gomock.Controller) repository.UserExportRepository {
+				repo := repomocks.NewMockUserExportRepository(ctrl)
+				repo.EXPECT().Get(gomock.Any(), int64(123)).Return(domain.UserExport{
+					Uid:    123,
+					Status: domain.UserExportStatusDone,
+					File:   "user_export/123/1.zip",
+				}, nil)
+				repo.EXPECT().OpenFile(gomock.Any(), "user_export/123/1.zip").
+					Return(io.NopCloser(strings.NewReader("zip")), nil)
+				return repo
+			},
+			wantContent: "zip",
+		},
+		{
+			name: "还在打包",
+			mock: func(ctrl *gomock.Controller) repository.UserExportRepository {
+				repo := repomocks.NewMockUserExportRepository(ctrl)
+				repo.EXPECT().Get(gomock.Any(), int64(123)).Return(domain.UserExport{
+					Uid:    123,
+					Status: domain.UserExportStatusRunning,
+					File:   "user_export/123/1.zip",
+				}, nil)
+				return repo
+			},
+			wantErr: ErrExportNotReady,
+		},
+		{
+			name: "文件已经被删掉了",
+			mock: func(ctrl *gomock.Controller) repository.UserExportRepository {
+				repo := repomocks.NewMockUserExportRepository(ctrl)
+				repo.EXPECT().Get(gomock.Any(), int64(123)).Return(domain.UserExport{
+					Uid:    123,
+					Status: domain.UserExportStatusDone,
+					File:   "user_export/123/1.zip",
+				}, nil)
+				repo.EXPECT().OpenFile(gomock.Any(), "user_export/123/1.zip").
+					Return(nil, repository.ErrExportFileNotFound)
+				return repo
+			},
+			wantErr: ErrExportNotReady,
+		},
+		{
+			name: "存储出错",
+			mock: func(ctrl *gomock.Controller) repository.UserExportRepository {
+				repo := repomocks.NewMockUserExportRepository(ctrl)
+				repo.EXPECT().Get(gomock.Any(), int64(123)).Return(domain.UserExport{
+					Uid:    123,
+					Status: domain.UserExportStatusDone,
+					File:   "user_export/123/1.zip",
+				}, nil)
+				repo.EXPECT().OpenFile(gomock.Any(), "user_export/123/1.zip").
+					Return(nil, errors.New("网络错误"))
+				return repo
+			},
+			wantErr: errors.New("网络错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewUserAccountService(nil, nil, nil, nil, tc.mock(ctrl),
+				nil, nil, UserExportConfig{}, logger.NewNopLogger())
+			rc, err := svc.OpenExportFile(context.Background(), 123)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			defer rc.Close()
+			data, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantContent, string(data))
+		})
+	}
+}
+
+func Test_userAccountService_Export(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.UserExportRepository
+
+		wantErr error
+	}{
+		{
+			name: "正在打包",
+			mock: func(ctrl *gomock.Controller) repository.UserExportRepository {
+				repo := repomocks.NewMockUserExportRepository(ctrl)
+				repo.EXPECT().Get(gomock.Any(), int64(123)).Return(domain.UserExport{
+					Uid:    123,
+					Status: domain.UserExportStatusRunning,
+					Ctime:  time.Now(),
+				}, nil)
+				return repo
+			},
+			wantErr: ErrExportRunning,
+		},
+		{
+			name: "查询状态失败",
+			mock: func(ctrl *gomock.Controller) repository.UserExportRepository {
+				repo := repomocks.NewMockUserExportRepository(ctrl)
+				repo.EXPECT().Get(gomock.Any(), int64(123)).
+					Return(domain.UserExport{}, errors.New("redis 错误"))
+				return repo
+			},
+			wantErr: errors.New("redis 错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewUserAccountService(nil, nil, nil, nil, tc.mock(ctrl),
+				nil, nil, UserExportConfig{Timeout: time.Minute}, logger.NewNopLogger())
+			err := svc.Export(context.Background(), 123)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+// Test_userAccountService_archive 草稿分批从 DAO 读，不走作者第一页的缓存，
+// 打包好之后上传到共享存储
+func Test_userAccountService_archive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := repomocks.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().FindById(gomock.Any(), int64(123)).
+		Return(domain.User{Id: 123, Email: "123@qq.com", Password: "hashed"}, nil)
+	identityRepo := repomocks.NewMockIdentityRepository(ctrl)
+	identityRepo.EXPECT().FindByUid(gomock.Any(), int64(123)).Return(nil, nil)
+
+	artRepo := repomocks.NewMockArticleRepository(ctrl)
+	firstBatch := make([]domain.Article, exportBatchSize)
+	for i := range firstBatch {
+		firstBatch[i] = domain.Article{Id: int64(exportBatchSize + 1 - i), Utime: time.UnixMilli(1000)}
+	}
+	gomock.InOrder(
+		artRepo.EXPECT().ListByAuthor(gomock.Any(), int64(123), domain.ArticleCursor{}, exportBatchSize).
+			Return(firstBatch, nil),
+		artRepo.EXPECT().ListByAuthor(gomock.Any(), int64(123),
+			domain.ArticleCursor{Utime: time.UnixMilli(1000), Id: 2}, exportBatchSize).
+			Return([]domain.Article{{Id: 1}}, nil),
+	)
+	artRepo.EXPECT().ListPubByAuthor(gomock.Any(), int64(123), nil, 0, exportBatchSize).
+		Return([]domain.Article{{Id: 1}}, nil)
+
+	intrSvc := intrv1mocks.NewMockInteractiveServiceClient(ctrl)
+	intrSvc.EXPECT().ExportUser(gomock.Any(), &intrv1.ExportUserRequest{Uid: 123}).
+		Return(&intrv1.ExportUserResponse{}, nil)
+	historyRepo := repomocks.NewMockHistoryRecordRepository(ctrl)
+	historyRepo.EXPECT().FindByUid(gomock.Any(), int64(123)).Return(nil, nil)
+
+	var uploaded []byte
+	exportRepo := repomocks.NewMockUserExportRepository(ctrl)
+	exportRepo.EXPECT().SaveFile(gomock.Any(), "user_export/123/1.zip", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, key string, r io.ReadSeeker) error {
+			var err error
+			uploaded, err = io.ReadAll(r)
+			return err
+		})
+
+	svc := NewUserAccountService(userRepo, identityRepo, artRepo, historyRepo,
+		exportRepo, intrSvc, nil, UserExportConfig{}, logger.NewNopLogger()).(*userAccountService)
+	err := svc.archive(context.Background(), 123, "user_export/123/1.zip")
+	require.NoError(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(uploaded), int64(len(uploaded)))
+	require.NoError(t, err)
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"user.json", "articles.json", "published_articles.json",
+		"likes.json", "collections.json", "history.json"}, names)
+	f, err := zr.Open("user.json")
+	require.NoError(t, err)
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "hashed")
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"ddd_demo/internal/events/user"
+	"ddd_demo/internal/repository"
+	"errors"
+)
+
+var ErrMergeSameUser = errors.New("不能合并同一个账号")
+
+// UserMergeService 把重复注册的账号合并到一起，只有管理员可以用
+//
+//go:generate mockgen -source=./user_merge.go -package=svcmocks -destination=./mocks/user_merge.mock.go UserMergeService
+type UserMergeService interface {
+	// Merge 把 fromUid 的登录方式和名下的数据都转给 toUid，然后删除 fromUid
+	Merge(ctx context.Context, fromUid int64, toUid int64) error
+}
+
+type userMergeService struct {
+	userRepo repository.UserRepository
+	artRepo  repository.ArticleRepository
+	producer user.Producer
+}
+
+func NewUserMergeService(userRepo repository.UserRepository,
+	artRepo repository.ArticleRepository,
+	producer user.Producer) UserMergeService {
+	return &userMergeService{
+		userRepo: userRepo,
+		artRepo:  artRepo,
+		producer: producer,
+	}
+}
+
+func (svc *userMergeService) Merge(ctx context.Context, fromUid int64, toUid int64) error {
+	if fromUid == toUid {
+		return ErrMergeSameUser
+	}
+	// 先确认两个账号都在
+	_, err := svc.userRepo.FindById(ctx, fromUid)
+	if err != nil {
+		return err
+	}
+	_, err = svc.userRepo.FindById(ctx, toUid)
+	if err != nil {
+		return err
+	}
+	// 先迁移数据，最后再删除 fromUid。
+	// 中间任何一步失败了，重试一遍就可以，前面的步骤都是幂等的
+	err = svc.artRepo.TransferAuthor(ctx, fromUid, toUid)
+	if err != nil {
+		return err
+	}
+	// 点赞、收藏在 interactive 服务里面，通过消息通知它
+	err = svc.producer.ProduceMergedEvent(user.MergedEvent{
+		From: fromUid,
+		To:   toUid,
+	})
+	if err != nil {
+		return err
+	}
+	return svc.userRepo.Merge(ctx, fromUid, toUid)
+}
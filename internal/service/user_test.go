@@ -1,10 +1,12 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"ddd_demo/internal/domain"
 	"ddd_demo/internal/repository"
 	repomocks "ddd_demo/internal/repository/mocks"
+	"ddd_demo/internal/service/email/local"
 	"errors"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -118,10 +120,390 @@ func Test_userService_Login(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			repo := tc.mock(ctrl)
-			svc := NewUserService(repo)
+			svc := NewUserService(repo, nil, nil, nil, nil)
 			user, err := svc.Login(tc.ctx, tc.email, tc.password)
 			assert.Equal(t, tc.wantErr, err)
 			assert.Equal(t, tc.wantUser, user)
 		})
 	}
 }
+
+func Test_userService_SendResetPasswordEmail(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.UserRepository,
+			repository.OneTimeTokenRepository)
+
+		email string
+
+		wantErr  error
+		wantSent bool
+	}{
+		{
+			name: "发送成功",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository,
+				repository.OneTimeTokenRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindByEmail(gomock.Any(), "123@qq.com").
+					Return(domain.User{Id: 1, Email: "123@qq.com"}, nil)
+				tokenRepo := repomocks.NewMockOneTimeTokenRepository(ctrl)
+				tokenRepo.EXPECT().Set(gomock.Any(), bizResetPassword, "1",
+					gomock.Any(), "1", gomock.Any()).Return(nil)
+				return repo, tokenRepo
+			},
+			email:    "123@qq.com",
+			wantSent: true,
+		},
+		{
+			name: "邮箱没有注册",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository,
+				repository.OneTimeTokenRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindByEmail(gomock.Any(), "123@qq.com").
+					Return(domain.User{}, repository.ErrUserNotFound)
+				return repo, repomocks.NewMockOneTimeTokenRepository(ctrl)
+			},
+			email: "123@qq.com",
+		},
+		{
+			// 和没有注册的邮箱一样的结果，不能被用来探测邮箱
+			name: "发送太频繁",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository,
+				repository.OneTimeTokenRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindByEmail(gomock.Any(), "123@qq.com").
+					Return(domain.User{Id: 1, Email: "123@qq.com"}, nil)
+				tokenRepo := repomocks.NewMockOneTimeTokenRepository(ctrl)
+				tokenRepo.EXPECT().Set(gomock.Any(), bizResetPassword, "1",
+					gomock.Any(), "1", gomock.Any()).
+					Return(repository.ErrTokenSendTooMany)
+				return repo, tokenRepo
+			},
+			email: "123@qq.com",
+		},
+		{
+			name: "系统错误",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository,
+				repository.OneTimeTokenRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindByEmail(gomock.Any(), "123@qq.com").
+					Return(domain.User{Id: 1, Email: "123@qq.com"}, nil)
+				tokenRepo := repomocks.NewMockOneTimeTokenRepository(ctrl)
+				tokenRepo.EXPECT().Set(gomock.Any(), bizResetPassword, "1",
+					gomock.Any(), "1", gomock.Any()).
+					Return(errors.New("redis 错误"))
+				return repo, tokenRepo
+			},
+			email:   "123@qq.com",
+			wantErr: errors.New("redis 错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, tokenRepo := tc.mock(ctrl)
+			buf := &bytes.Buffer{}
+			svc := NewUserService(repo, nil, nil, tokenRepo, local.NewService(buf))
+			err := svc.SendResetPasswordEmail(context.Background(), tc.email)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantSent, buf.Len() > 0)
+		})
+	}
+}
+
+func Test_userService_Bind(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.UserRepository, repository.IdentityRepository)
+		bind func(svc UserService) error
+
+		wantErr error
+	}{
+		{
+			name: "绑定手机号",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.IdentityRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().UpdatePhone(gomock.Any(), int64(123), "15212345678").Return(nil)
+				return repo, nil
+			},
+			bind: func(svc UserService) error {
+				return svc.BindPhone(context.Background(), 123, "15212345678")
+			},
+		},
+		{
+			name: "微信已经被别的账号绑定了",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.IdentityRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().UpdateWechat(gomock.Any(), int64(123),
+					domain.WechatInfo{OpenId: "open", UnionId: "union"}).
+					Return(repository.ErrDuplicateUser)
+				return repo, nil
+			},
+			bind: func(svc UserService) error {
+				return svc.BindWechat(context.Background(), 123,
+					domain.WechatInfo{OpenId: "open", UnionId: "union"})
+			},
+			wantErr: ErrIdentityBound,
+		},
+		{
+			name: "GitHub 已经被别的账号绑定了",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.IdentityRepository) {
+				identityRepo := repomocks.NewMockIdentityRepository(ctrl)
+				identityRepo.EXPECT().Create(gomock.Any(), domain.OAuth2Identity{
+					Uid:      123,
+					Provider: "github",
+					Subject:  "456",
+				}).Return(repository.ErrDuplicateUser)
+				return nil, identityRepo
+			},
+			bind: func(svc UserService) error {
+				return svc.BindOAuth2(context.Background(), 123,
+					domain.OAuth2Info{Provider: "github", Subject: "456"})
+			},
+			wantErr: ErrIdentityBound,
+		},
+		{
+			name: "数据库错误",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.IdentityRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().UpdatePhone(gomock.Any(), int64(123), "15212345678").
+					Return(errors.New("mock db 错误"))
+				return repo, nil
+			},
+			bind: func(svc UserService) error {
+				return svc.BindPhone(context.Background(), 123, "15212345678")
+			},
+			wantErr: errors.New("mock db 错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, identityRepo := tc.mock(ctrl)
+			svc := NewUserService(repo, identityRepo, nil, nil, nil)
+			err := tc.bind(svc)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_userService_BindEmail(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.UserRepository,
+			repository.OneTimeTokenRepository)
+		bind func(svc UserService) error
+
+		wantErr  error
+		wantSent bool
+	}{
+		{
+			// 这时候还没有绑定，要等邮箱的主人点了确认链接
+			name: "发送确认邮件",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository,
+				repository.OneTimeTokenRepository) {
+				tokenRepo := repomocks.NewMockOneTimeTokenRepository(ctrl)
+				tokenRepo.EXPECT().Set(gomock.Any(), bizBindEmail, "123",
+					gomock.Any(), "123:123@qq.com", gomock.Any()).Return(nil)
+				return repomocks.NewMockUserRepository(ctrl), tokenRepo
+			},
+			bind: func(svc UserService) error {
+				return svc.BindEmail(context.Background(), 123, "123@qq.com")
+			},
+			wantSent: true,
+		},
+		{
+			name: "确认邮件发送太频繁",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository,
+				repository.OneTimeTokenRepository) {
+				tokenRepo := repomocks.NewMockOneTimeTokenRepository(ctrl)
+				tokenRepo.EXPECT().Set(gomock.Any(), bizBindEmail, "123",
+					gomock.Any(), "123:123@qq.com", gomock.Any()).
+					Return(repository.ErrTokenSendTooMany)
+				return repomocks.NewMockUserRepository(ctrl), tokenRepo
+			},
+			bind: func(svc UserService) error {
+				return svc.BindEmail(context.Background(), 123, "123@qq.com")
+			},
+			wantErr: ErrTokenSendTooMany,
+		},
+		{
+			name: "确认绑定",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository,
+				repository.OneTimeTokenRepository) {
+				tokenRepo := repomocks.NewMockOneTimeTokenRepository(ctrl)
+				tokenRepo.EXPECT().Consume(gomock.Any(), bizBindEmail, "abc").
+					Return("123:123@qq.com", nil)
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().UpdateEmail(gomock.Any(), int64(123), "123@qq.com").Return(nil)
+				repo.EXPECT().UpdateEmailVerified(gomock.Any(), int64(123), "123@qq.com").Return(nil)
+				return repo, tokenRepo
+			},
+			bind: func(svc UserService) error {
+				return svc.ConfirmBindEmail(context.Background(), "abc")
+			},
+		},
+		{
+			name: "token 无效",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository,
+				repository.OneTimeTokenRepository) {
+				tokenRepo := repomocks.NewMockOneTimeTokenRepository(ctrl)
+				tokenRepo.EXPECT().Consume(gomock.Any(), bizBindEmail, "abc").
+					Return("", repository.ErrTokenNotFound)
+				return repomocks.NewMockUserRepository(ctrl), tokenRepo
+			},
+			bind: func(svc UserService) error {
+				return svc.ConfirmBindEmail(context.Background(), "abc")
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "邮箱已经被别的账号绑定了",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository,
+				repository.OneTimeTokenRepository) {
+				tokenRepo := repomocks.NewMockOneTimeTokenRepository(ctrl)
+				tokenRepo.EXPECT().Consume(gomock.Any(), bizBindEmail, "abc").
+					Return("123:123@qq.com", nil)
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().UpdateEmail(gomock.Any(), int64(123), "123@qq.com").
+					Return(repository.ErrDuplicateUser)
+				return repo, tokenRepo
+			},
+			bind: func(svc UserService) error {
+				return svc.ConfirmBindEmail(context.Background(), "abc")
+			},
+			wantErr: ErrIdentityBound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, tokenRepo := tc.mock(ctrl)
+			buf := &bytes.Buffer{}
+			svc := NewUserService(repo, nil, nil, tokenRepo, local.NewService(buf))
+			err := tc.bind(svc)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantSent, buf.Len() > 0)
+		})
+	}
+}
+
+func Test_userService_Unbind(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.UserRepository, repository.IdentityRepository)
+		typ  domain.IdentityType
+
+		wantErr error
+	}{
+		{
+			name: "还有别的登录方式，解绑手机号",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.IdentityRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).Return(domain.User{
+					Id:       123,
+					Phone:    "15212345678",
+					Email:    "123@qq.com",
+					Password: "hashed",
+				}, nil)
+				repo.EXPECT().UpdatePhone(gomock.Any(), int64(123), "").Return(nil)
+				identityRepo := repomocks.NewMockIdentityRepository(ctrl)
+				identityRepo.EXPECT().FindByUid(gomock.Any(), int64(123)).Return(nil, nil)
+				return repo, identityRepo
+			},
+			typ: domain.IdentityPhone,
+		},
+		{
+			name: "最后一种登录方式",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.IdentityRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).Return(domain.User{
+					Id:         123,
+					WechatInfo: domain.WechatInfo{OpenId: "open"},
+				}, nil)
+				identityRepo := repomocks.NewMockIdentityRepository(ctrl)
+				identityRepo.EXPECT().FindByUid(gomock.Any(), int64(123)).Return(nil, nil)
+				return repo, identityRepo
+			},
+			typ:     domain.IdentityWechat,
+			wantErr: ErrLastIdentity,
+		},
+		{
+			name: "只有邮箱没有密码，不算登录方式",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.IdentityRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).Return(domain.User{
+					Id:    123,
+					Phone: "15212345678",
+					Email: "123@qq.com",
+				}, nil)
+				identityRepo := repomocks.NewMockIdentityRepository(ctrl)
+				identityRepo.EXPECT().FindByUid(gomock.Any(), int64(123)).Return(nil, nil)
+				return repo, identityRepo
+			},
+			typ:     domain.IdentityPhone,
+			wantErr: ErrLastIdentity,
+		},
+		{
+			name: "还绑定了 GitHub，可以解绑微信",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.IdentityRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).Return(domain.User{
+					Id:         123,
+					WechatInfo: domain.WechatInfo{OpenId: "open"},
+				}, nil)
+				repo.EXPECT().UpdateWechat(gomock.Any(), int64(123), domain.WechatInfo{}).Return(nil)
+				identityRepo := repomocks.NewMockIdentityRepository(ctrl)
+				identityRepo.EXPECT().FindByUid(gomock.Any(), int64(123)).
+					Return([]domain.OAuth2Identity{{Uid: 123, Provider: "github"}}, nil)
+				return repo, identityRepo
+			},
+			typ: domain.IdentityWechat,
+		},
+		{
+			name: "解绑 GitHub",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.IdentityRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).Return(domain.User{
+					Id:    123,
+					Phone: "15212345678",
+				}, nil)
+				identityRepo := repomocks.NewMockIdentityRepository(ctrl)
+				identityRepo.EXPECT().FindByUid(gomock.Any(), int64(123)).
+					Return([]domain.OAuth2Identity{{Uid: 123, Provider: "github"}}, nil)
+				identityRepo.EXPECT().Delete(gomock.Any(), int64(123), "github").Return(nil)
+				return repo, identityRepo
+			},
+			typ: domain.IdentityType("github"),
+		},
+		{
+			name: "本来就没有绑定 GitHub",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.IdentityRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).Return(domain.User{
+					Id:    123,
+					Phone: "15212345678",
+				}, nil)
+				identityRepo := repomocks.NewMockIdentityRepository(ctrl)
+				identityRepo.EXPECT().FindByUid(gomock.Any(), int64(123)).Return(nil, nil)
+				identityRepo.EXPECT().Delete(gomock.Any(), int64(123), "github").
+					Return(repository.ErrUserNotFound)
+				return repo, identityRepo
+			},
+			typ: domain.IdentityType("github"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, identityRepo := tc.mock(ctrl)
+			svc := NewUserService(repo, identityRepo, nil, nil, nil)
+			err := svc.Unbind(context.Background(), 123, tc.typ)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
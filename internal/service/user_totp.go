@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/repository"
+	"ddd_demo/pkg/totp"
+	"encoding/base32"
+	"errors"
+	"golang.org/x/crypto/bcrypt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrTOTPEnabled     = errors.New("已经启用了二次验证")
+	ErrTOTPNotEnrolled = errors.New("没有绑定二次验证")
+	ErrInvalidMFACode  = errors.New("验证码不对")
+)
+
+const (
+	totpIssuer = "webook"
+	// 允许前后一个周期的时钟误差
+	totpSkew          = 1
+	recoveryCodeCount = 10
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func (svc *userService) EnrollTOTP(ctx context.Context, uid int64) (string, string, error) {
+	u, err := svc.repo.FindById(ctx, uid)
+	if err != nil {
+		return "", "", err
+	}
+	t, err := svc.totpRepo.FindByUid(ctx, uid)
+	if err == nil && t.Enabled {
+		// 要换手机的话，先关掉再重新绑定
+		return "", "", ErrTOTPEnabled
+	}
+	if err != nil && err != repository.ErrUserNotFound {
+		return "", "", err
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", "", err
+	}
+	err = svc.totpRepo.Save(ctx, domain.TOTP{
+		Uid:    uid,
+		Secret: secret,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return secret, totp.URI(totpIssuer, svc.totpAccount(u), secret), nil
+}
+
+func (svc *userService) ActivateTOTP(ctx context.Context, uid int64, code string) ([]string, error) {
+	t, err := svc.findTOTP(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if t.Enabled {
+		return nil, ErrTOTPEnabled
+	}
+	counter, ok := totp.Validate(t.Secret, code, time.Now(), totpSkew)
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		c, err := svc.generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(svc.normalizeRecoveryCode(c)), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, c)
+		hashes = append(hashes, string(hash))
+	}
+	err = svc.totpRepo.Enable(ctx, uid, counter, hashes)
+	if err == repository.ErrUserNotFound {
+		// 并发激活，或者验证码被用过了
+		return nil, ErrInvalidMFACode
+	}
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (svc *userService) DisableTOTP(ctx context.Context, uid int64, code string) error {
+	err := svc.VerifyMFA(ctx, uid, code)
+	if err != nil {
+		return err
+	}
+	return svc.totpRepo.Delete(ctx, uid)
+}
+
+func (svc *userService) TOTPEnabled(ctx context.Context, uid int64) (bool, error) {
+	t, err := svc.totpRepo.FindByUid(ctx, uid)
+	if err == repository.ErrUserNotFound {
+		return false, nil
+	}
+	return t.Enabled, err
+}
+
+func (svc *userService) VerifyMFA(ctx context.Context, uid int64, code string) error {
+	t, err := svc.findTOTP(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if !t.Enabled {
+		return ErrTOTPNotEnrolled
+	}
+	if _, err = strconv.Atoi(code); err == nil {
+		counter, ok := totp.Validate(t.Secret, code, time.Now(), totpSkew)
+		if !ok || counter <= t.LastCounter {
+			return ErrInvalidMFACode
+		}
+		err = svc.totpRepo.UpdateLastCounter(ctx, uid, counter)
+		if err == repository.ErrUserNotFound {
+			// 同一个验证码并发使用
+			return ErrInvalidMFACode
+		}
+		return err
+	}
+	return svc.useRecoveryCode(ctx, t, code)
+}
+
+func (svc *userService) useRecoveryCode(ctx context.Context, t domain.TOTP, code string) error {
+	code = svc.normalizeRecoveryCode(code)
+	for i, hash := range t.RecoveryCodes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(code)) != nil {
+			continue
+		}
+		remain := make([]string, 0, len(t.RecoveryCodes)-1)
+		remain = append(remain, t.RecoveryCodes[:i]...)
+		remain = append(remain, t.RecoveryCodes[i+1:]...)
+		err := svc.totpRepo.UpdateRecoveryCodes(ctx, t.Uid, t.RecoveryCodes, remain)
+		if err == repository.ErrUserNotFound {
+			return ErrInvalidMFACode
+		}
+		return err
+	}
+	return ErrInvalidMFACode
+}
+
+func (svc *userService) findTOTP(ctx context.Context, uid int64) (domain.TOTP, error) {
+	t, err := svc.totpRepo.FindByUid(ctx, uid)
+	if err == repository.ErrUserNotFound {
+		return domain.TOTP{}, ErrTOTPNotEnrolled
+	}
+	return t, err
+}
+
+// totpAccount 显示在 App 里面的账号名
+func (svc *userService) totpAccount(u domain.User) string {
+	switch {
+	case u.Email != "":
+		return u.Email
+	case u.Phone != "":
+		return u.Phone
+	default:
+		return strconv.FormatInt(u.Id, 10)
+	}
+}
+
+// generateRecoveryCode 形如 abcde-fghij
+func (svc *userService) generateRecoveryCode() (string, error) {
+	data := make([]byte, 7)
+	_, err := rand.Read(data)
+	if err != nil {
+		return "", err
+	}
+	code := strings.ToLower(recoveryCodeEncoding.EncodeToString(data))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+func (svc *userService) normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
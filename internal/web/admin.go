@@ -0,0 +1,80 @@
+package web
+
+import (
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	ijwt "ddd_demo/internal/web/jwt"
+	"ddd_demo/internal/web/middleware"
+	"ddd_demo/pkg/ginx"
+	"fmt"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler 管理后台的接口
+type AdminHandler struct {
+	mergeSvc  service.UserMergeService
+	reviewSvc service.ArticleReviewService
+	hdl       ijwt.Handler
+	mdl       *middleware.AdminMiddlewareBuilder
+}
+
+func NewAdminHandler(mergeSvc service.UserMergeService,
+	reviewSvc service.ArticleReviewService,
+	hdl ijwt.Handler,
+	mdl *middleware.AdminMiddlewareBuilder) *AdminHandler {
+	return &AdminHandler{
+		mergeSvc:  mergeSvc,
+		reviewSvc: reviewSvc,
+		hdl:       hdl,
+		mdl:       mdl,
+	}
+}
+
+func (h *AdminHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/admin", h.mdl.Build())
+	g.POST("/users/merge", ginx.WrapBodyAndClaims(h.MergeUser))
+	// 文章审核的队列，机审拿不准的才会进来
+	g.GET("/articles/reviews", ginx.WrapBodyAndClaims(h.ListReviews))
+	g.GET("/articles/reviews/:id", ginx.Wrap(h.ReviewDetail))
+	g.POST("/articles/reviews/approve", ginx.WrapBodyAndClaims(h.ApproveReview))
+	g.POST("/articles/reviews/reject", ginx.WrapBodyAndClaims(h.RejectReview))
+}
+
+// MergeUser 把 From 合并到 To 上，From 会被删除
+func (h *AdminHandler) MergeUser(ctx *gin.Context,
+	req MergeUserReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.mergeSvc.Merge(ctx, req.From, req.To)
+	switch err {
+	case nil:
+	case service.ErrMergeSameUser:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "不能合并同一个账号",
+		}, nil
+	case service.ErrUserNotFound:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "用户不存在",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	// 被合并的账号已经不存在了，让它所有的设备都下线
+	err = h.hdl.ClearSessions(ctx, req.From)
+	if err != nil {
+		return ginx.Result{
+			Msg: "合并成功",
+		}, fmt.Errorf("合并账号后清理会话失败 uid %d %w", req.From, err)
+	}
+	return ginx.Result{
+		Msg: "合并成功",
+	}, nil
+}
+
+type MergeUserReq struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+}
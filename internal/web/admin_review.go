@@ -0,0 +1,150 @@
+package web
+
+import (
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	ijwt "ddd_demo/internal/web/jwt"
+	"ddd_demo/pkg/ginx"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultReviewPageSize = 20
+	maxReviewPageSize     = 100
+)
+
+// ListReviews 等审核的文章，先提交的在前面，不返回内容
+func (h *AdminHandler) ListReviews(ctx *gin.Context,
+	req ArticleReviewListReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	limit := req.Limit
+	if limit <= 0 || limit > maxReviewPageSize {
+		limit = defaultReviewPageSize
+	}
+	rs, err := h.reviewSvc.ListPending(ctx, req.Offset, limit)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.ArticleInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	return ginx.Result{
+		Data: slice.Map(rs, func(idx int, src domain.ArticleReview) ArticleReviewVo {
+			return h.toReviewVo(src)
+		}),
+	}, nil
+}
+
+// ReviewDetail 审核记录和审核的那个版本的内容
+func (h *AdminHandler) ReviewDetail(ctx *gin.Context) (ginx.Result, error) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.ArticleInvalidInput,
+			Msg:  "id 参数错误",
+		}, nil
+	}
+	r, err := h.reviewSvc.Get(ctx, id)
+	if err != nil {
+		return h.reviewErrResult(err)
+	}
+	return ginx.Result{
+		Data: h.toReviewVo(r),
+	}, nil
+}
+
+func (h *AdminHandler) ApproveReview(ctx *gin.Context,
+	req ArticleReviewApproveReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.reviewSvc.Approve(ctx, uc.Uid, req.Id)
+	if err != nil {
+		return h.reviewErrResult(err)
+	}
+	return ginx.Result{
+		Msg: "OK",
+	}, nil
+}
+
+func (h *AdminHandler) RejectReview(ctx *gin.Context,
+	req ArticleReviewRejectReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.reviewSvc.Reject(ctx, uc.Uid, req.Id, req.Reason)
+	if err != nil {
+		return h.reviewErrResult(err)
+	}
+	return ginx.Result{
+		Msg: "OK",
+	}, nil
+}
+
+func (h *AdminHandler) reviewErrResult(err error) (ginx.Result, error) {
+	switch err {
+	case service.ErrArticleReviewNotFound:
+		return ginx.Result{
+			Code: errs.ArticleInvalidInput,
+			Msg:  "审核记录不存在",
+		}, nil
+	case service.ErrEmptyRejectReason:
+		return ginx.Result{
+			Code: errs.ArticleInvalidInput,
+			Msg:  "拒绝的时候必须说明原因",
+		}, nil
+	case service.ErrArticleReviewHandled:
+		return ginx.Result{
+			Code: errs.ArticleReviewConflict,
+			Msg:  "已经被别的管理员处理过了",
+		}, nil
+	case service.ErrArticleReviewOutdated:
+		return ginx.Result{
+			Code: errs.ArticleReviewConflict,
+			Msg:  "作者已经修改或者撤回了文章，不用再审了",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.ArticleInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+func (h *AdminHandler) toReviewVo(r domain.ArticleReview) ArticleReviewVo {
+	return ArticleReviewVo{
+		Id:       r.Id,
+		ArtId:    r.ArtId,
+		Revision: r.Revision,
+		AuthorId: r.Author.Id,
+		Title:    r.Title,
+		Content:  r.Content,
+		Status:   r.Status.ToUint8(),
+		Reason:   r.Reason,
+		Ctime:    r.Ctime.Format(time.DateTime),
+	}
+}
+
+type ArticleReviewListReq struct {
+	Offset int `form:"offset"`
+	Limit  int `form:"limit"`
+}
+
+type ArticleReviewApproveReq struct {
+	Id int64 `json:"id"`
+}
+
+type ArticleReviewRejectReq struct {
+	Id     int64  `json:"id"`
+	Reason string `json:"reason"`
+}
+
+type ArticleReviewVo struct {
+	Id       int64  `json:"id"`
+	ArtId    int64  `json:"artId"`
+	Revision int64  `json:"revision"`
+	AuthorId int64  `json:"authorId"`
+	Title    string `json:"title"`
+	Content  string `json:"content,omitempty"`
+	Status   uint8  `json:"status"`
+	// Reason 机审转人工的原因
+	Reason string `json:"reason"`
+	Ctime  string `json:"ctime"`
+}
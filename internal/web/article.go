@@ -3,10 +3,13 @@ package web
 import (
 	intrv1 "ddd_demo/api/proto/gen/intr/v1"
 	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
 	"ddd_demo/internal/service"
 	"ddd_demo/internal/web/jwt"
 	"ddd_demo/pkg/ginx"
 	"ddd_demo/pkg/logger"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"github.com/ecodeclub/ekit/slice"
 	"github.com/gin-gonic/gin"
@@ -16,21 +19,31 @@ import (
 	"time"
 )
 
+// maxArticlePageSize 作者文章列表一页最多多少篇
+const maxArticlePageSize = 100
+
 type ArticleHandler struct {
-	svc     service.ArticleService
-	intrSvc intrv1.InteractiveServiceClient
-	l       logger.LoggerV1
-	biz     string
+	svc       service.ArticleService
+	seriesSvc service.SeriesService
+	intrSvc   intrv1.InteractiveServiceClient
+	// shareKey 分享链接的签名
+	shareKey jwt.KeyProvider
+	l        logger.LoggerV1
+	biz      string
 }
 
 func NewArticleHandler(l logger.LoggerV1,
 	svc service.ArticleService,
-	intrSvc intrv1.InteractiveServiceClient) *ArticleHandler {
+	seriesSvc service.SeriesService,
+	intrSvc intrv1.InteractiveServiceClient,
+	keys jwt.Keys) *ArticleHandler {
 	return &ArticleHandler{
-		l:       l,
-		svc:     svc,
-		intrSvc: intrSvc,
-		biz:     "article",
+		l:         l,
+		svc:       svc,
+		seriesSvc: seriesSvc,
+		intrSvc:   intrSvc,
+		shareKey:  keys.Share,
+		biz:       "article",
 	}
 }
 
@@ -39,40 +52,74 @@ func (h *ArticleHandler) RegisterRoutes(server *gin.Engine) {
 
 	//g.PUT("/", h.Edit)
 	g.POST("/edit", ginx.WrapBodyAndClaims(h.Edit))
+	// 编辑器定时调用，只保存标题和内容
+	g.POST("/autosave", ginx.WrapBodyAndClaims(h.AutoSave))
 	g.POST("/publish", ginx.WrapBodyAndClaims(h.Publish))
 	g.POST("/withdraw", ginx.WrapBodyAndClaims(h.Withdraw))
+	// 可见范围和分享链接
+	g.POST("/visibility", ginx.WrapBodyAndClaims(h.SetVisibility))
+	g.POST("/share", ginx.WrapBodyAndClaims(h.Share))
+	// 定时发表
+	g.POST("/schedule", ginx.WrapBodyAndClaims(h.Schedule))
+	g.POST("/schedule/update", ginx.WrapBodyAndClaims(h.Reschedule))
+	g.POST("/schedule/cancel", ginx.WrapBodyAndClaims(h.CancelSchedule))
 
 	// 创作者接口
 	g.GET("/detail/:id", h.Detail)
 	// 按照道理来说，这边就是 GET 方法
-	// /list?offset=?&limit=?
+	// /list，body 里面传 limit 和上一页返回的 cursor
 	g.POST("/list", h.List)
 
+	// 历史版本
+	// GET /articles/revisions/123?offset=0&limit=20
+	g.GET("/revisions/:id", ginx.WrapBodyAndClaims(h.Revisions))
+	// GET /articles/revisions/123/diff?from=1&to=2
+	g.GET("/revisions/:id/diff", ginx.WrapBodyAndClaims(h.DiffRevisions))
+	g.POST("/revisions/restore", ginx.WrapBodyAndClaims(h.RestoreRevision))
+
 	pub := g.Group("/pub")
+	// 不公开的文章可以带上分享链接里面的 token：/articles/pub/123?share=xxx
 	pub.GET("/:id", h.PubDetail)
+	// 标签
+	// GET /articles/pub/tags?limit=20
+	pub.GET("/tags", ginx.WrapBody(h.Tags))
+	// GET /articles/pub/tag/go?cursor=0&limit=10
+	pub.GET("/tag/:tag", ginx.WrapBody(h.ListByTag))
 	// 传入一个参数，true 就是点赞, false 就是不点赞
 	pub.POST("/like", ginx.WrapBodyAndClaims(h.Like))
 	pub.POST("/collect", ginx.WrapBodyAndClaims(h.Collect))
 }
 
-// Edit 接收 Article 输入，返回一个 ID，文章的 ID
+// Edit 接收 Article 输入，返回文章的 ID 和新的版本号
 func (h *ArticleHandler) Edit(ctx *gin.Context,
 	req ArticleEditReq, uc jwt.UserClaims) (ginx.Result, error) {
 	id, err := h.svc.Save(ctx, domain.Article{
-		Id:      req.Id,
-		Title:   req.Title,
-		Content: req.Content,
+		Id:         req.Id,
+		Title:      req.Title,
+		Content:    req.Content,
+		Tags:       req.Tags,
+		Visibility: domain.ArticleVisibility(req.Visibility),
+		Version:    req.Version,
 		Author: domain.Author{
 			Id: uc.Uid,
 		},
 	})
+	if res, ok := h.inputErrResult(err); ok {
+		return res, nil
+	}
+	if err == service.ErrArticleVersionConflict {
+		return h.versionConflictResult(ctx, req.Id, uc.Uid)
+	}
 	if err != nil {
 		return ginx.Result{
 			Msg: "系统错误",
 		}, err
 	}
 	return ginx.Result{
-		Data: id,
+		Data: ArticleSaveVo{
+			Id:      id,
+			Version: h.savedVersion(req.Id, req.Version),
+		},
 	}, nil
 }
 
@@ -88,13 +135,22 @@ func (h *ArticleHandler) Publish(ctx *gin.Context,
 	//	return
 	//}
 	id, err := h.svc.Publish(ctx, domain.Article{
-		Id:      req.Id,
-		Title:   req.Title,
-		Content: req.Content,
+		Id:         req.Id,
+		Title:      req.Title,
+		Content:    req.Content,
+		Tags:       req.Tags,
+		Visibility: domain.ArticleVisibility(req.Visibility),
+		Version:    req.Version,
 		Author: domain.Author{
 			Id: uc.Uid,
 		},
 	})
+	if res, ok := h.inputErrResult(err); ok {
+		return res, nil
+	}
+	if err == service.ErrArticleVersionConflict {
+		return h.versionConflictResult(ctx, req.Id, uc.Uid)
+	}
 	if err != nil {
 		return ginx.Result{
 			Msg:  "系统错误",
@@ -121,13 +177,25 @@ func (h *ArticleHandler) Withdraw(ctx *gin.Context,
 }
 
 func (h *ArticleHandler) List(ctx *gin.Context) {
-	var page Page
+	var page CursorPage
 	if err := ctx.Bind(&page); err != nil {
 		return
 	}
+	cursor, err := h.decodeCursor(page.Cursor)
+	if err != nil {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 4,
+			Msg:  "cursor 参数错误",
+		})
+		return
+	}
+	// 第一页刚好 100 条的时候走缓存
+	if page.Limit <= 0 || page.Limit > maxArticlePageSize {
+		page.Limit = maxArticlePageSize
+	}
 	// 我要不要检测一下？
 	uc := ctx.MustGet("user").(jwt.UserClaims)
-	arts, err := h.svc.GetByAuthor(ctx, uc.Uid, page.Offset, page.Limit)
+	arts, err := h.svc.GetByAuthorByCursor(ctx, uc.Uid, cursor, page.Limit)
 	if err != nil {
 		ctx.JSON(http.StatusOK, ginx.Result{
 			Code: 5,
@@ -135,13 +203,13 @@ func (h *ArticleHandler) List(ctx *gin.Context) {
 		})
 		h.l.Error("查找文章列表失败",
 			logger.Error(err),
-			logger.Int("offset", page.Offset),
+			logger.String("cursor", page.Cursor),
 			logger.Int("limit", page.Limit),
 			logger.Int64("uid", uc.Uid))
 		return
 	}
-	ctx.JSON(http.StatusOK, ginx.Result{
-		Data: slice.Map[domain.Article, ArticleVo](arts, func(idx int, src domain.Article) ArticleVo {
+	vo := ArticleListVo{
+		Articles: slice.Map[domain.Article, ArticleVo](arts, func(idx int, src domain.Article) ArticleVo {
 			return ArticleVo{
 				Id:       src.Id,
 				Title:    src.Title,
@@ -150,14 +218,50 @@ func (h *ArticleHandler) List(ctx *gin.Context) {
 				//Content:  src.Content,
 				AuthorId: src.Author.Id,
 				// 列表，你不需要
-				Status: src.Status.ToUint8(),
-				Ctime:  src.Ctime.Format(time.DateTime),
-				Utime:  src.Utime.Format(time.DateTime),
+				Status:     src.Status.ToUint8(),
+				Visibility: src.Visibility.ToUint8(),
+				PublishAt:  h.formatPublishAt(src.PublishAt),
+				Ctime:      src.Ctime.Format(time.DateTime),
+				Utime:      src.Utime.Format(time.DateTime),
 			}
 		}),
+	}
+	// 不满一页说明已经没有了
+	if len(arts) == page.Limit {
+		vo.Cursor = h.encodeCursor(arts[len(arts)-1].Cursor())
+	}
+	ctx.JSON(http.StatusOK, ginx.Result{
+		Data: vo,
 	})
 }
 
+// encodeCursor 游标对前端是不透明的，以后换了排序方式前端也不用改
+func (h *ArticleHandler) encodeCursor(c domain.ArticleCursor) string {
+	raw := fmt.Sprintf("%d_%d", c.Utime.UnixMilli(), c.Id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func (h *ArticleHandler) decodeCursor(cursor string) (domain.ArticleCursor, error) {
+	var res domain.ArticleCursor
+	if cursor == "" {
+		return res, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return res, err
+	}
+	var utime int64
+	_, err = fmt.Sscanf(string(raw), "%d_%d", &utime, &res.Id)
+	if err != nil {
+		return res, err
+	}
+	if res.Id <= 0 {
+		return res, errors.New("cursor 里面的 id 不对")
+	}
+	res.Utime = time.UnixMilli(utime)
+	return res, nil
+}
+
 func (h *ArticleHandler) Detail(ctx *gin.Context) {
 	idstr := ctx.Param("id")
 	id, err := strconv.ParseInt(idstr, 10, 64)
@@ -195,7 +299,12 @@ func (h *ArticleHandler) Detail(ctx *gin.Context) {
 		return
 	}
 
-	vo := ArticleVo{
+	ctx.JSON(http.StatusOK, ginx.Result{Data: h.toAuthorVo(art)})
+}
+
+// toAuthorVo 作者自己编辑的时候看到的文章
+func (h *ArticleHandler) toAuthorVo(art domain.Article) ArticleVo {
+	return ArticleVo{
 		Id:    art.Id,
 		Title: art.Title,
 		//Abstract: art.Abstract(),
@@ -203,11 +312,15 @@ func (h *ArticleHandler) Detail(ctx *gin.Context) {
 		Content:  art.Content,
 		AuthorId: art.Author.Id,
 		// 列表，你不需要
-		Status: art.Status.ToUint8(),
-		Ctime:  art.Ctime.Format(time.DateTime),
-		Utime:  art.Utime.Format(time.DateTime),
+		Status:     art.Status.ToUint8(),
+		Visibility: art.Visibility.ToUint8(),
+		Revision:   art.Revision,
+		Version:    art.Version,
+		PublishAt:  h.formatPublishAt(art.PublishAt),
+		Tags:       art.Tags,
+		Ctime:      art.Ctime.Format(time.DateTime),
+		Utime:      art.Utime.Format(time.DateTime),
 	}
-	ctx.JSON(http.StatusOK, ginx.Result{Data: vo})
 }
 
 func (h *ArticleHandler) PubDetail(ctx *gin.Context) {
@@ -228,12 +341,25 @@ func (h *ArticleHandler) PubDetail(ctx *gin.Context) {
 		eg   errgroup.Group
 		art  domain.Article
 		intr *intrv1.GetResponse
+		nav  *SeriesNavVo
 	)
 
 	uc := ctx.MustGet("user").(jwt.UserClaims)
+	shared := false
+	if token := ctx.Query("share"); token != "" {
+		// 链接坏了或者过期了直接告诉用户，不要让人以为是文章不让看
+		if h.parseShareToken(token) != id {
+			ctx.JSON(http.StatusOK, ginx.Result{
+				Code: errs.ArticleForbidden,
+				Msg:  "分享链接无效或者已经过期",
+			})
+			return
+		}
+		shared = true
+	}
 	eg.Go(func() error {
 		var er error
-		art, er = h.svc.GetPubById(ctx, id, uc.Uid)
+		art, er = h.svc.GetPubById(ctx, id, uc.Uid, shared)
 		return er
 	})
 	eg.Go(func() error {
@@ -243,9 +369,30 @@ func (h *ArticleHandler) PubDetail(ctx *gin.Context) {
 		})
 		return er
 	})
+	eg.Go(func() error {
+		// 上一篇、下一篇只是导航，查不到也不影响看文章
+		n, er := h.seriesSvc.Nav(ctx, id, uc.Uid)
+		switch er {
+		case nil:
+			nav = h.toSeriesNavVo(n)
+		case service.ErrSeriesNotFound:
+		default:
+			h.l.Error("查询文章所在的专栏失败",
+				logger.Int64("aid", id),
+				logger.Error(er))
+		}
+		return nil
+	})
 
 	// 等待结果
 	err = eg.Wait()
+	if errors.Is(err, service.ErrArticleInvisible) {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: errs.ArticleForbidden,
+			Msg:  "没有权限查看这篇文章",
+		})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusOK, ginx.Result{
 			Msg:  "系统错误",
@@ -273,25 +420,52 @@ func (h *ArticleHandler) PubDetail(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, ginx.Result{
 		Data: ArticleVo{
-			Id:    art.Id,
-			Title: art.Title,
+			Id:       art.Id,
+			Title:    art.Title,
+			Abstract: art.Abstract(),
 
 			Content:    art.Content,
+			Html:       art.Rendered.HTML,
+			Toc:        h.toHeadingVos(art.Rendered.TOC),
 			AuthorId:   art.Author.Id,
 			AuthorName: art.Author.Name,
 			ReadCnt:    intr.Intr.ReadCnt,
 			CollectCnt: intr.Intr.CollectCnt,
 			LikeCnt:    intr.Intr.LikeCnt,
+			CommentCnt: intr.Intr.CommentCnt,
 			Liked:      intr.Intr.Liked,
 			Collected:  intr.Intr.Collected,
+			Series:     nav,
 
-			Status: art.Status.ToUint8(),
-			Ctime:  art.Ctime.Format(time.DateTime),
-			Utime:  art.Utime.Format(time.DateTime),
+			Status:     art.Status.ToUint8(),
+			Visibility: art.Visibility.ToUint8(),
+			Tags:       art.Tags,
+			Ctime:      art.Ctime.Format(time.DateTime),
+			Utime:      art.Utime.Format(time.DateTime),
 		},
 	})
 }
 
+func (h *ArticleHandler) toHeadingVos(toc []domain.ArticleHeading) []ArticleHeadingVo {
+	return slice.Map(toc, func(idx int, src domain.ArticleHeading) ArticleHeadingVo {
+		return ArticleHeadingVo{Level: src.Level, Id: src.Id, Title: src.Title}
+	})
+}
+
+func (h *ArticleHandler) toSeriesNavVo(n domain.SeriesNav) *SeriesNavVo {
+	res := &SeriesNavVo{
+		Id:    n.Series.Id,
+		Title: n.Series.Title,
+	}
+	if n.Prev.Id > 0 {
+		res.Prev = &SeriesArticleVo{Id: n.Prev.Id, Title: n.Prev.Title}
+	}
+	if n.Next.Id > 0 {
+		res.Next = &SeriesArticleVo{Id: n.Next.Id, Title: n.Next.Title}
+	}
+	return res
+}
+
 func (h *ArticleHandler) Like(c *gin.Context,
 	req ArticleLikeReq, uc jwt.UserClaims) (ginx.Result, error) {
 	var err error
@@ -0,0 +1,192 @@
+package web
+
+import (
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	ijwt "ddd_demo/internal/web/jwt"
+	"ddd_demo/pkg/ginx"
+	"ddd_demo/pkg/logger"
+	"errors"
+	"fmt"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ArticleBundleHandler 批量导入、导出文章
+type ArticleBundleHandler struct {
+	svc service.ArticleBundleService
+	l   logger.LoggerV1
+}
+
+func NewArticleBundleHandler(svc service.ArticleBundleService, l logger.LoggerV1) *ArticleBundleHandler {
+	return &ArticleBundleHandler{
+		svc: svc,
+		l:   l,
+	}
+}
+
+func (h *ArticleBundleHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/articles")
+	// POST 上传文件开始导入，multipart 表单，文件放在 file 字段里面。
+	// .zip 是 Markdown 格式，.json 是 JSON 格式。GET 查询导入的进度和结果
+	g.POST("/import", ginx.WrapClaims(h.Import))
+	g.GET("/import", ginx.WrapClaims(h.ImportStatus))
+	// GET /articles/export?format=json，默认是 Markdown 格式
+	g.GET("/export", h.Export)
+}
+
+func (h *ArticleBundleHandler) Import(ctx *gin.Context,
+	uc ijwt.UserClaims) (ginx.Result, error) {
+	// 多留 1MB 给 multipart 的边界和表单里面的其它字段
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, service.MaxArticleBundleSize+1<<20)
+	fh, err := ctx.FormFile("file")
+	if err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			return h.tooLarge(), nil
+		}
+		return ginx.Result{
+			Code: errs.ArticleInvalidInput,
+			Msg:  "请选择要导入的文件",
+		}, nil
+	}
+	if fh.Size > service.MaxArticleBundleSize {
+		return h.tooLarge(), nil
+	}
+	var format domain.ArticleBundleFormat
+	switch strings.ToLower(filepath.Ext(fh.Filename)) {
+	case ".zip":
+		format = domain.ArticleBundleMarkdown
+	case ".json":
+		format = domain.ArticleBundleJSON
+	default:
+		return ginx.Result{
+			Code: errs.ArticleInvalidInput,
+			Msg:  "只支持 Markdown 文件打包成的 zip 和 JSON 文件",
+		}, nil
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return ginx.Result{
+			Code: errs.ArticleInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.ArticleInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	err = h.svc.Import(ctx, uc.Uid, format, data)
+	switch err {
+	case nil:
+		return ginx.Result{
+			Msg: "正在导入，请稍后查看",
+		}, nil
+	case service.ErrImportRunning:
+		return ginx.Result{
+			Code: errs.ArticleImportRunning,
+			Msg:  "上一次导入还没有结束，请稍后查看",
+		}, nil
+	case service.ErrInvalidArticleBundle:
+		return ginx.Result{
+			Code: errs.ArticleInvalidInput,
+			Msg:  "文件打不开，或者里面没有文章。一次最多导入 500 篇，解压之后不能超过 50MB",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.ArticleInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+func (h *ArticleBundleHandler) tooLarge() ginx.Result {
+	return ginx.Result{
+		Code: errs.ArticleInvalidInput,
+		Msg:  "文件不能超过 20MB",
+	}
+}
+
+func (h *ArticleBundleHandler) ImportStatus(ctx *gin.Context,
+	uc ijwt.UserClaims) (ginx.Result, error) {
+	imp, err := h.svc.ImportStatus(ctx, uc.Uid)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.ArticleInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	vo := ArticleImportVo{
+		Status:    uint8(imp.Status),
+		Total:     imp.Total,
+		Processed: imp.Processed,
+		Ids:       imp.Ids,
+		Errors: slice.Map(imp.Errors, func(idx int, src domain.ArticleImportError) ArticleImportErrorVo {
+			return ArticleImportErrorVo{File: src.File, Reason: src.Reason}
+		}),
+	}
+	if imp.Status != domain.ArticleImportStatusUnknown {
+		vo.Ctime = imp.Ctime.Format(time.DateTime)
+		vo.Utime = imp.Utime.Format(time.DateTime)
+	}
+	return ginx.Result{
+		Data: vo,
+	}, nil
+}
+
+// Export 边查边写，直接返回文件，所以不能用 ginx 包装
+func (h *ArticleBundleHandler) Export(ctx *gin.Context) {
+	uc := ctx.MustGet("user").(ijwt.UserClaims)
+	format, contentType, ext := domain.ArticleBundleMarkdown, "application/zip", "zip"
+	if ctx.Query("format") == "json" {
+		format, contentType, ext = domain.ArticleBundleJSON, "application/json", "json"
+	}
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="webook-articles-%d.%s"`, uc.Uid, ext))
+	ctx.Status(http.StatusOK)
+	err := h.svc.Export(ctx, uc.Uid, format, ctx.Writer)
+	if err == nil {
+		return
+	}
+	h.l.Error("导出文章失败",
+		logger.Int64("uid", uc.Uid),
+		logger.Error(err))
+	// 已经开始写文件了就只能这样断掉，客户端拿到的是不完整的文件
+	if ctx.Writer.Written() {
+		return
+	}
+	ctx.Writer.Header().Del("Content-Type")
+	ctx.Writer.Header().Del("Content-Disposition")
+	ctx.JSON(http.StatusOK, ginx.Result{
+		Code: errs.ArticleInternalServerError,
+		Msg:  "系统错误",
+	})
+}
+
+type ArticleImportVo struct {
+	// 0 没有导入过，1 正在导入，2 导入完了，3 超时了没有导入完
+	Status    uint8 `json:"status"`
+	Total     int   `json:"total"`
+	Processed int   `json:"processed"`
+	// Ids 导入成功的草稿
+	Ids    []int64                `json:"ids"`
+	Errors []ArticleImportErrorVo `json:"errors"`
+	Ctime  string                 `json:"ctime"`
+	Utime  string                 `json:"utime"`
+}
+
+type ArticleImportErrorVo struct {
+	File   string `json:"file"`
+	Reason string `json:"reason"`
+}
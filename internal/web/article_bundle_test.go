@@ -0,0 +1,118 @@
+package web
+
+import (
+	"bytes"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	svcmocks "ddd_demo/internal/service/mocks"
+	ijwt "ddd_demo/internal/web/jwt"
+	"ddd_demo/pkg/ginx"
+	"ddd_demo/pkg/logger"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestArticleBundleHandler_Import(t *testing.T) {
+	testCases := []struct {
+		name     string
+		mock     func(ctrl *gomock.Controller) service.ArticleBundleService
+		filename string
+
+		wantRes ginx.Result
+	}{
+		{
+			name: "zip 是 Markdown 格式",
+			mock: func(ctrl *gomock.Controller) service.ArticleBundleService {
+				svc := svcmocks.NewMockArticleBundleService(ctrl)
+				svc.EXPECT().Import(gomock.Any(), int64(123),
+					domain.ArticleBundleMarkdown, []byte("data")).Return(nil)
+				return svc
+			},
+			filename: "articles.ZIP",
+			wantRes: ginx.Result{
+				Msg: "正在导入，请稍后查看",
+			},
+		},
+		{
+			name: "JSON 格式，上一次还没有导入完",
+			mock: func(ctrl *gomock.Controller) service.ArticleBundleService {
+				svc := svcmocks.NewMockArticleBundleService(ctrl)
+				svc.EXPECT().Import(gomock.Any(), int64(123),
+					domain.ArticleBundleJSON, []byte("data")).Return(service.ErrImportRunning)
+				return svc
+			},
+			filename: "articles.json",
+			wantRes: ginx.Result{
+				Code: errs.ArticleImportRunning,
+				Msg:  "上一次导入还没有结束，请稍后查看",
+			},
+		},
+		{
+			name: "文件打不开",
+			mock: func(ctrl *gomock.Controller) service.ArticleBundleService {
+				svc := svcmocks.NewMockArticleBundleService(ctrl)
+				svc.EXPECT().Import(gomock.Any(), int64(123),
+					domain.ArticleBundleMarkdown, []byte("data")).Return(service.ErrInvalidArticleBundle)
+				return svc
+			},
+			filename: "articles.zip",
+			wantRes: ginx.Result{
+				Code: errs.ArticleInvalidInput,
+				Msg:  "文件打不开，或者里面没有文章。一次最多导入 500 篇，解压之后不能超过 50MB",
+			},
+		},
+		{
+			name: "不支持的格式",
+			mock: func(ctrl *gomock.Controller) service.ArticleBundleService {
+				return svcmocks.NewMockArticleBundleService(ctrl)
+			},
+			filename: "articles.tar.gz",
+			wantRes: ginx.Result{
+				Code: errs.ArticleInvalidInput,
+				Msg:  "只支持 Markdown 文件打包成的 zip 和 JSON 文件",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			hdl := NewArticleBundleHandler(tc.mock(ctrl), logger.NewNopLogger())
+			server := gin.Default()
+			server.Use(func(ctx *gin.Context) {
+				ctx.Set("user", ijwt.UserClaims{
+					Uid: 123,
+				})
+			})
+			hdl.RegisterRoutes(server)
+
+			var body bytes.Buffer
+			w := multipart.NewWriter(&body)
+			fw, err := w.CreateFormFile("file", tc.filename)
+			require.NoError(t, err)
+			_, err = fw.Write([]byte("data"))
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+			req, err := http.NewRequest(http.MethodPost, "/articles/import", &body)
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", w.FormDataContentType())
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var res ginx.Result
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package web
+
+import (
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	"ddd_demo/internal/web/jwt"
+	"ddd_demo/pkg/ginx"
+	"github.com/gin-gonic/gin"
+)
+
+// AutoSave 自动保存，新建的文章要先调用 Edit 拿到 ID
+func (h *ArticleHandler) AutoSave(ctx *gin.Context,
+	req ArticleAutoSaveReq, uc jwt.UserClaims) (ginx.Result, error) {
+	if req.Id <= 0 {
+		return ginx.Result{
+			Code: errs.ArticleInvalidInput,
+			Msg:  "新建的文章要先保存一次",
+		}, nil
+	}
+	version, err := h.svc.AutoSave(ctx, domain.ArticleDraft{
+		Id:      req.Id,
+		Title:   req.Title,
+		Content: req.Content,
+		Version: req.Version,
+		Author: domain.Author{
+			Id: uc.Uid,
+		},
+	})
+	switch err {
+	case nil:
+		return ginx.Result{
+			Data: ArticleSaveVo{
+				Id:      req.Id,
+				Version: version,
+			},
+		}, nil
+	case service.ErrArticleVersionConflict:
+		return h.versionConflictResult(ctx, req.Id, uc.Uid)
+	case service.ErrNotArticleAuthor, service.ErrArticleNotFound:
+		return ginx.Result{
+			Code: errs.ArticleInvalidInput,
+			Msg:  "文章不存在",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.ArticleInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+// versionConflictResult 保存冲突的时候把服务端现在的版本带回去，让作者决定保留哪边的修改
+func (h *ArticleHandler) versionConflictResult(ctx *gin.Context, id int64, uid int64) (ginx.Result, error) {
+	art, err := h.svc.GetById(ctx, id)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.ArticleInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	if art.Author.Id != uid {
+		// 版本号冲突说明是作者自己的文章，走到这里是有人在搞鬼
+		return ginx.Result{
+			Code: errs.ArticleInvalidInput,
+			Msg:  "文章不存在",
+		}, nil
+	}
+	return ginx.Result{
+		Code: errs.ArticleVersionConflict,
+		Msg:  "文章已经在别的地方修改过了",
+		Data: h.toAuthorVo(art),
+	}, nil
+}
+
+// savedVersion 保存成功之后的版本号。修改的时候数据库里面的版本号一定是作者带过来的那个，加一就是新的
+func (h *ArticleHandler) savedVersion(id int64, version int64) int64 {
+	if id > 0 {
+		return version + 1
+	}
+	return 1
+}
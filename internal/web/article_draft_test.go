@@ -0,0 +1,199 @@
+package web
+
+import (
+	"bytes"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	svcmocks "ddd_demo/internal/service/mocks"
+	ijwt "ddd_demo/internal/web/jwt"
+	"ddd_demo/pkg/ginx"
+	"ddd_demo/pkg/logger"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestArticleHandler_Edit(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) service.ArticleService
+		reqBody string
+
+		wantRes ginx.Result
+	}{
+		{
+			name: "新建",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().Save(gomock.Any(), domain.Article{
+					Title:   "标题",
+					Content: "内容",
+					Author:  domain.Author{Id: 123},
+				}).Return(int64(1), nil)
+				return svc
+			},
+			reqBody: `{"title":"标题","content":"内容"}`,
+			wantRes: ginx.Result{
+				Data: map[string]any{"id": float64(1), "version": float64(1)},
+			},
+		},
+		{
+			name: "修改之后版本号加一",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().Save(gomock.Any(), domain.Article{
+					Id:      1,
+					Title:   "标题",
+					Content: "内容",
+					Version: 3,
+					Author:  domain.Author{Id: 123},
+				}).Return(int64(1), nil)
+				return svc
+			},
+			reqBody: `{"id":1,"title":"标题","content":"内容","version":3}`,
+			wantRes: ginx.Result{
+				Data: map[string]any{"id": float64(1), "version": float64(4)},
+			},
+		},
+		{
+			name: "冲突的时候返回服务端的版本",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().Save(gomock.Any(), gomock.Any()).
+					Return(int64(0), service.ErrArticleVersionConflict)
+				svc.EXPECT().GetById(gomock.Any(), int64(1)).Return(domain.Article{
+					Id:      1,
+					Title:   "另一个标签页的标题",
+					Content: "另一个标签页的内容",
+					Author:  domain.Author{Id: 123},
+					Status:  domain.ArticleStatusUnpublished,
+					Version: 4,
+					Ctime:   time.UnixMilli(0),
+					Utime:   time.UnixMilli(0),
+				}, nil)
+				return svc
+			},
+			reqBody: `{"id":1,"title":"标题","content":"内容","version":3}`,
+			wantRes: ginx.Result{
+				Code: errs.ArticleVersionConflict,
+				Msg:  "文章已经在别的地方修改过了",
+				Data: map[string]any{
+					"id":         float64(1),
+					"title":      "另一个标签页的标题",
+					"content":    "另一个标签页的内容",
+					"authorId":   float64(123),
+					"status":     float64(domain.ArticleStatusUnpublished),
+					"version":    float64(4),
+					"ctime":      time.UnixMilli(0).Format(time.DateTime),
+					"utime":      time.UnixMilli(0).Format(time.DateTime),
+					"readCnt":    float64(0),
+					"likeCnt":    float64(0),
+					"collectCnt": float64(0),
+					"commentCnt": float64(0),
+					"liked":      false,
+					"collected":  false,
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			res := doArticleDraftReq(t, tc.mock(ctrl), "/articles/edit", tc.reqBody)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+func TestArticleHandler_AutoSave(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) service.ArticleService
+		reqBody string
+
+		wantRes ginx.Result
+	}{
+		{
+			name: "保存成功",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().AutoSave(gomock.Any(), domain.ArticleDraft{
+					Id:      1,
+					Title:   "标题",
+					Content: "内容",
+					Version: 3,
+					Author:  domain.Author{Id: 123},
+				}).Return(int64(4), nil)
+				return svc
+			},
+			reqBody: `{"id":1,"title":"标题","content":"内容","version":3}`,
+			wantRes: ginx.Result{
+				Data: map[string]any{"id": float64(1), "version": float64(4)},
+			},
+		},
+		{
+			name: "新建的文章",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				return svcmocks.NewMockArticleService(ctrl)
+			},
+			reqBody: `{"title":"标题","content":"内容"}`,
+			wantRes: ginx.Result{
+				Code: errs.ArticleInvalidInput,
+				Msg:  "新建的文章要先保存一次",
+			},
+		},
+		{
+			name: "别人的文章",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().AutoSave(gomock.Any(), gomock.Any()).
+					Return(int64(0), service.ErrNotArticleAuthor)
+				return svc
+			},
+			reqBody: `{"id":1,"title":"标题","content":"内容","version":3}`,
+			wantRes: ginx.Result{
+				Code: errs.ArticleInvalidInput,
+				Msg:  "文章不存在",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			res := doArticleDraftReq(t, tc.mock(ctrl), "/articles/autosave", tc.reqBody)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+func doArticleDraftReq(t *testing.T, svc service.ArticleService, path string, body string) ginx.Result {
+	hdl := NewArticleHandler(logger.NewNopLogger(), svc, nil, nil, ijwt.Keys{})
+	server := gin.Default()
+	server.Use(func(ctx *gin.Context) {
+		ctx.Set("user", ijwt.UserClaims{
+			Uid: 123,
+		})
+	})
+	hdl.RegisterRoutes(server)
+
+	req, err := http.NewRequest(http.MethodPost, path, bytes.NewReader([]byte(body)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var res ginx.Result
+	err = json.NewDecoder(recorder.Body).Decode(&res)
+	require.NoError(t, err)
+	return res
+}
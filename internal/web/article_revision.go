@@ -0,0 +1,112 @@
+package web
+
+import (
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	"ddd_demo/internal/web/jwt"
+	"ddd_demo/pkg/ginx"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRevisionPageSize = 20
+	maxRevisionPageSize     = 100
+)
+
+// Revisions 文章的历史版本列表，不返回内容
+func (h *ArticleHandler) Revisions(ctx *gin.Context,
+	req ArticleRevisionListReq, uc jwt.UserClaims) (ginx.Result, error) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.ArticleInvalidInput,
+			Msg:  "id 参数错误",
+		}, nil
+	}
+	limit := req.Limit
+	if limit <= 0 || limit > maxRevisionPageSize {
+		limit = defaultRevisionPageSize
+	}
+	revs, err := h.svc.ListRevisions(ctx, uc.Uid, id, req.Offset, limit)
+	if err != nil {
+		return h.revisionErrResult(err)
+	}
+	return ginx.Result{
+		Data: slice.Map(revs, func(idx int, src domain.ArticleRevision) ArticleRevisionVo {
+			return h.toRevisionVo(src)
+		}),
+	}, nil
+}
+
+func (h *ArticleHandler) DiffRevisions(ctx *gin.Context,
+	req ArticleRevisionDiffReq, uc jwt.UserClaims) (ginx.Result, error) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.ArticleInvalidInput,
+			Msg:  "id 参数错误",
+		}, nil
+	}
+	diff, err := h.svc.DiffRevisions(ctx, uc.Uid, id, req.From, req.To)
+	if err != nil {
+		return h.revisionErrResult(err)
+	}
+	// 差异里面已经有内容了，这里就不再返回两个版本的全文
+	from, to := h.toRevisionVo(diff.From), h.toRevisionVo(diff.To)
+	from.Content, to.Content = "", ""
+	return ginx.Result{
+		Data: ArticleRevisionDiffVo{
+			From:    from,
+			To:      to,
+			Title:   diff.Title,
+			Content: diff.Content,
+		},
+	}, nil
+}
+
+// RestoreRevision 把历史版本恢复成草稿，要重新发表才会出现在线上
+func (h *ArticleHandler) RestoreRevision(ctx *gin.Context,
+	req ArticleRevisionRestoreReq, uc jwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.RestoreRevision(ctx, uc.Uid, req.Id, req.Version)
+	if err != nil {
+		return h.revisionErrResult(err)
+	}
+	return ginx.Result{
+		Msg: "OK",
+	}, nil
+}
+
+func (h *ArticleHandler) revisionErrResult(err error) (ginx.Result, error) {
+	switch err {
+	case service.ErrArticleRevisionNotFound:
+		return ginx.Result{
+			Code: errs.ArticleInvalidInput,
+			Msg:  "文章或者版本不存在",
+		}, nil
+	case service.ErrNotArticleAuthor:
+		// 有人在搞鬼，和 Detail 一样要记下来
+		return ginx.Result{
+			Code: errs.ArticleInvalidInput,
+			Msg:  "文章或者版本不存在",
+		}, err
+	default:
+		return ginx.Result{
+			Code: errs.ArticleInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+func (h *ArticleHandler) toRevisionVo(rev domain.ArticleRevision) ArticleRevisionVo {
+	return ArticleRevisionVo{
+		Version: rev.Version,
+		Title:   rev.Title,
+		Content: rev.Content,
+		Hash:    rev.Hash,
+		Ctime:   rev.Ctime.Format(time.DateTime),
+	}
+}
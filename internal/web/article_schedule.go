@@ -0,0 +1,95 @@
+package web
+
+import (
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	"ddd_demo/internal/web/jwt"
+	"ddd_demo/pkg/ginx"
+	"github.com/gin-gonic/gin"
+	"time"
+)
+
+// Schedule 保存文章并且设置定时发表，返回文章 ID
+func (h *ArticleHandler) Schedule(ctx *gin.Context,
+	req ArticleScheduleReq, uc jwt.UserClaims) (ginx.Result, error) {
+	id, err := h.svc.Schedule(ctx, domain.Article{
+		Id:         req.Id,
+		Title:      req.Title,
+		Content:    req.Content,
+		Tags:       req.Tags,
+		PublishAt:  time.UnixMilli(req.PublishAt),
+		Visibility: domain.ArticleVisibility(req.Visibility),
+		Version:    req.Version,
+		Author: domain.Author{
+			Id: uc.Uid,
+		},
+	})
+	if res, ok := h.inputErrResult(err); ok {
+		return res, nil
+	}
+	switch err {
+	case nil:
+		return ginx.Result{
+			Data: id,
+		}, nil
+	case service.ErrInvalidPublishTime:
+		return ginx.Result{
+			Code: errs.ArticleInvalidInput,
+			Msg:  "定时发表的时间必须在将来",
+		}, nil
+	case service.ErrArticleVersionConflict:
+		return h.versionConflictResult(ctx, req.Id, uc.Uid)
+	default:
+		return ginx.Result{
+			Code: errs.ArticleInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+// Reschedule 修改定时发表的时间
+func (h *ArticleHandler) Reschedule(ctx *gin.Context,
+	req ArticleRescheduleReq, uc jwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.Reschedule(ctx, uc.Uid, req.Id, time.UnixMilli(req.PublishAt))
+	return h.scheduleResult(err)
+}
+
+// CancelSchedule 取消定时发表，文章变回草稿
+func (h *ArticleHandler) CancelSchedule(ctx *gin.Context,
+	req ArticleCancelScheduleReq, uc jwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.CancelSchedule(ctx, uc.Uid, req.Id)
+	return h.scheduleResult(err)
+}
+
+func (h *ArticleHandler) scheduleResult(err error) (ginx.Result, error) {
+	switch err {
+	case nil:
+		return ginx.Result{
+			Msg: "OK",
+		}, nil
+	case service.ErrInvalidPublishTime:
+		return ginx.Result{
+			Code: errs.ArticleInvalidInput,
+			Msg:  "定时发表的时间必须在将来",
+		}, nil
+	case service.ErrArticleNotScheduled:
+		// 已经发表了，或者已经取消了
+		return ginx.Result{
+			Code: errs.ArticleInvalidInput,
+			Msg:  "文章不是定时发表的状态",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.ArticleInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+func (h *ArticleHandler) formatPublishAt(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.DateTime)
+}
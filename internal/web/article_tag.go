@@ -0,0 +1,120 @@
+package web
+
+import (
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	"ddd_demo/pkg/ginx"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"time"
+)
+
+const (
+	defaultTagPageSize    = 10
+	maxTagPageSize        = 50
+	defaultTagCountsLimit = 20
+	maxTagCountsLimit     = 100
+)
+
+// Tags 文章最多的标签
+func (h *ArticleHandler) Tags(ctx *gin.Context, req ArticleTagsReq) (ginx.Result, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultTagCountsLimit
+	}
+	limit = min(limit, maxTagCountsLimit)
+	cnts, err := h.svc.ListTags(ctx, limit)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.ArticleInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	return ginx.Result{
+		Data: slice.Map(cnts, func(idx int, src domain.TagCount) TagVo {
+			return TagVo{Tag: src.Tag, Count: src.Count}
+		}),
+	}, nil
+}
+
+// ListByTag 标签下面已经发表的文章，按照游标翻页
+func (h *ArticleHandler) ListByTag(ctx *gin.Context, req ArticleTagListReq) (ginx.Result, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultTagPageSize
+	}
+	limit = min(limit, maxTagPageSize)
+	arts, err := h.svc.ListPubByTag(ctx, ctx.Param("tag"), max(req.Cursor, 0), limit)
+	if res, ok := h.inputErrResult(err); ok {
+		return res, nil
+	}
+	if err != nil {
+		return ginx.Result{
+			Code: errs.ArticleInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	vo := ArticleTagListVo{
+		Articles: slice.Map(arts, func(idx int, src domain.Article) ArticleVo {
+			return ArticleVo{
+				Id:       src.Id,
+				Title:    src.Title,
+				Abstract: src.Abstract(),
+				AuthorId: src.Author.Id,
+				Tags:     src.Tags,
+				Ctime:    src.Ctime.Format(time.DateTime),
+				Utime:    src.Utime.Format(time.DateTime),
+			}
+		}),
+	}
+	// 不满一页说明没有了
+	if len(arts) == limit {
+		vo.Cursor = arts[len(arts)-1].Id
+	}
+	return ginx.Result{Data: vo}, nil
+}
+
+// inputErrResult 标签、可见范围不合法是用户的输入有问题，不是系统错误
+func (h *ArticleHandler) inputErrResult(err error) (ginx.Result, bool) {
+	switch err {
+	case service.ErrInvalidTag:
+		return ginx.Result{
+			Code: errs.ArticleInvalidInput,
+			Msg:  "标签不能为空，不能超过 20 个字，也不能包含 / ? #",
+		}, true
+	case service.ErrTooManyTags:
+		return ginx.Result{
+			Code: errs.ArticleInvalidInput,
+			Msg:  "最多 5 个标签",
+		}, true
+	case service.ErrInvalidVisibility:
+		return ginx.Result{
+			Code: errs.ArticleInvalidInput,
+			Msg:  "可见范围不合法",
+		}, true
+	default:
+		return ginx.Result{}, false
+	}
+}
+
+type ArticleTagsReq struct {
+	Limit int `form:"limit"`
+}
+
+type ArticleTagListReq struct {
+	// Cursor 上一页返回的 cursor，第一页不传
+	Cursor int64 `form:"cursor"`
+	Limit  int   `form:"limit"`
+}
+
+type TagVo struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+type ArticleTagListVo struct {
+	Articles []ArticleVo `json:"articles"`
+	// Cursor 下一页的游标，0 就是没有下一页了
+	Cursor int64 `json:"cursor"`
+}
@@ -0,0 +1,142 @@
+package web
+
+import (
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	svcmocks "ddd_demo/internal/service/mocks"
+	ijwt "ddd_demo/internal/web/jwt"
+	"ddd_demo/pkg/logger"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestArticleHandler_ListByTag(t *testing.T) {
+	utime := time.UnixMilli(1000)
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) service.ArticleService
+		url  string
+
+		wantCode int
+		wantMsg  string
+		wantData ArticleTagListVo
+	}{
+		{
+			name: "满一页，返回下一页的游标",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().ListPubByTag(gomock.Any(), "后端", int64(0), 2).
+					Return([]domain.Article{
+						{Id: 5, Title: "标题5", Content: "内容", Tags: []string{"后端"}, Utime: utime, Ctime: utime},
+						{Id: 3, Title: "标题3", Content: "内容", Tags: []string{"后端"}, Utime: utime, Ctime: utime},
+					}, nil)
+				return svc
+			},
+			url: "/articles/pub/tag/%E5%90%8E%E7%AB%AF?limit=2",
+			wantData: ArticleTagListVo{
+				Articles: []ArticleVo{
+					{Id: 5, Title: "标题5", Abstract: "内容", Tags: []string{"后端"},
+						Ctime: utime.Format(time.DateTime), Utime: utime.Format(time.DateTime)},
+					{Id: 3, Title: "标题3", Abstract: "内容", Tags: []string{"后端"},
+						Ctime: utime.Format(time.DateTime), Utime: utime.Format(time.DateTime)},
+				},
+				Cursor: 3,
+			},
+		},
+		{
+			name: "最后一页，游标是 0",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().ListPubByTag(gomock.Any(), "go", int64(3), defaultTagPageSize).
+					Return([]domain.Article{}, nil)
+				return svc
+			},
+			url:      "/articles/pub/tag/go?cursor=3",
+			wantData: ArticleTagListVo{Articles: []ArticleVo{}},
+		},
+		{
+			name: "标签不合法",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().ListPubByTag(gomock.Any(), "a?b", int64(0), defaultTagPageSize).
+					Return(nil, service.ErrInvalidTag)
+				return svc
+			},
+			url:      "/articles/pub/tag/a%3Fb",
+			wantCode: errs.ArticleInvalidInput,
+			wantMsg:  "标签不能为空，不能超过 20 个字，也不能包含 / ? #",
+		},
+		{
+			name: "系统错误",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().ListPubByTag(gomock.Any(), "go", int64(0), maxTagPageSize).
+					Return(nil, errors.New("mock db 错误"))
+				return svc
+			},
+			url:      "/articles/pub/tag/go?limit=1000",
+			wantCode: errs.ArticleInternalServerError,
+			wantMsg:  "系统错误",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			hdl := NewArticleHandler(logger.NewNopLogger(), tc.mock(ctrl), nil, nil, ijwt.Keys{})
+			server := gin.Default()
+			server.Use(func(ctx *gin.Context) {
+				ctx.Set("user", ijwt.UserClaims{Uid: 123})
+			})
+			hdl.RegisterRoutes(server)
+
+			req, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+			require.Equal(t, http.StatusOK, recorder.Code)
+
+			var res struct {
+				Code int              `json:"code"`
+				Msg  string           `json:"msg"`
+				Data ArticleTagListVo `json:"data"`
+			}
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantCode, res.Code)
+			assert.Equal(t, tc.wantMsg, res.Msg)
+			assert.Equal(t, tc.wantData, res.Data)
+		})
+	}
+}
+
+func TestArticleHandler_Tags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc := svcmocks.NewMockArticleService(ctrl)
+	svc.EXPECT().ListTags(gomock.Any(), defaultTagCountsLimit).
+		Return([]domain.TagCount{{Tag: "go", Count: 3}, {Tag: "后端", Count: 1}}, nil)
+	hdl := NewArticleHandler(logger.NewNopLogger(), svc, nil, nil, ijwt.Keys{})
+	server := gin.Default()
+	hdl.RegisterRoutes(server)
+
+	req, err := http.NewRequest(http.MethodGet, "/articles/pub/tags", nil)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var res struct {
+		Data []TagVo `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&res))
+	assert.Equal(t, []TagVo{{Tag: "go", Count: 3}, {Tag: "后端", Count: 1}}, res.Data)
+}
@@ -16,6 +16,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestArticleHandler_Publish(t *testing.T) {
@@ -134,7 +135,7 @@ func TestArticleHandler_Publish(t *testing.T) {
 
 			// 构造 handler
 			svc := tc.mock(ctrl)
-			hdl := NewArticleHandler(logger.NewNopLogger(), svc)
+			hdl := NewArticleHandler(logger.NewNopLogger(), svc, nil, nil, ijwt.Keys{})
 
 			// 准备服务器，注册路由
 			server := gin.Default()
@@ -166,3 +167,113 @@ func TestArticleHandler_Publish(t *testing.T) {
 		})
 	}
 }
+
+func TestArticleHandler_List(t *testing.T) {
+	utime := time.UnixMilli(1000)
+	// base64 之后的 1000_2
+	const cursor = "MTAwMF8y"
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) service.ArticleService
+
+		reqBody string
+		wantRes ginx.Result
+	}{
+		{
+			name: "第一页",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().GetByAuthorByCursor(gomock.Any(), int64(123),
+					domain.ArticleCursor{}, 1).
+					Return([]domain.Article{{
+						Id:      2,
+						Title:   "标题",
+						Content: "内容",
+						Author:  domain.Author{Id: 123},
+						Status:  domain.ArticleStatusPublished,
+						Ctime:   utime,
+						Utime:   utime,
+					}}, nil)
+				return svc
+			},
+			reqBody: `{"limit":1}`,
+			wantRes: ginx.Result{
+				Data: map[string]any{
+					"articles": []any{map[string]any{
+						"id":         float64(2),
+						"title":      "标题",
+						"abstract":   "内容",
+						"authorId":   float64(123),
+						"status":     float64(domain.ArticleStatusPublished),
+						"ctime":      utime.Format(time.DateTime),
+						"utime":      utime.Format(time.DateTime),
+						"readCnt":    float64(0),
+						"likeCnt":    float64(0),
+						"collectCnt": float64(0),
+						"commentCnt": float64(0),
+						"liked":      false,
+						"collected":  false,
+					}},
+					"cursor": cursor,
+				},
+			},
+		},
+		{
+			name: "最后一页",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().GetByAuthorByCursor(gomock.Any(), int64(123),
+					domain.ArticleCursor{Utime: utime, Id: 2}, maxArticlePageSize).
+					Return([]domain.Article{}, nil)
+				return svc
+			},
+			reqBody: `{"cursor":"` + cursor + `"}`,
+			wantRes: ginx.Result{
+				Data: map[string]any{
+					"articles": []any{},
+					"cursor":   "",
+				},
+			},
+		},
+		{
+			name: "游标不对",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				return svcmocks.NewMockArticleService(ctrl)
+			},
+			reqBody: `{"cursor":"1000_2"}`,
+			wantRes: ginx.Result{
+				Code: 4,
+				Msg:  "cursor 参数错误",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc := tc.mock(ctrl)
+			hdl := NewArticleHandler(logger.NewNopLogger(), svc, nil, nil, ijwt.Keys{})
+			server := gin.Default()
+			server.Use(func(ctx *gin.Context) {
+				ctx.Set("user", ijwt.UserClaims{
+					Uid: 123,
+				})
+			})
+			hdl.RegisterRoutes(server)
+
+			req, err := http.NewRequest(http.MethodPost,
+				"/articles/list", bytes.NewReader([]byte(tc.reqBody)))
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var res ginx.Result
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
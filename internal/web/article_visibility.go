@@ -0,0 +1,101 @@
+package web
+
+import (
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	ijwt "ddd_demo/internal/web/jwt"
+	"ddd_demo/pkg/ginx"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"time"
+)
+
+const (
+	// defaultShareExpire 分享链接默认的有效期
+	defaultShareExpire = 7 * 24
+	// maxShareExpire 分享链接最长的有效期，单位是小时
+	maxShareExpire = 30 * 24
+)
+
+// ArticleShareClaims 分享链接里面的 token，只能用来看 Aid 这一篇文章
+type ArticleShareClaims struct {
+	jwt.RegisteredClaims
+	Aid int64
+}
+
+// SetVisibility 修改已经发表或者还是草稿的文章的可见范围
+func (h *ArticleHandler) SetVisibility(ctx *gin.Context,
+	req ArticleVisibilityReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.SetVisibility(ctx, uc.Uid, req.Id, domain.ArticleVisibility(req.Visibility))
+	if res, ok := h.inputErrResult(err); ok {
+		return res, nil
+	}
+	if err != nil {
+		return ginx.Result{
+			Code: errs.ArticleInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	return ginx.Result{
+		Msg: "OK",
+	}, nil
+}
+
+// Share 作者生成一个有有效期的分享链接，拿到链接的人在过期之前都能看，包括仅自己可见的文章
+func (h *ArticleHandler) Share(ctx *gin.Context,
+	req ArticleShareReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	expire := req.Expire
+	if expire <= 0 {
+		expire = defaultShareExpire
+	}
+	expire = min(expire, maxShareExpire)
+	err := h.svc.CheckShare(ctx, uc.Uid, req.Id)
+	switch err {
+	case nil:
+	case service.ErrArticleInvisible:
+		return ginx.Result{
+			Code: errs.ArticleForbidden,
+			Msg:  "只能分享自己的文章",
+		}, nil
+	case service.ErrArticleNotShareable:
+		return ginx.Result{
+			Code: errs.ArticleInvalidInput,
+			Msg:  "只有已经发表的不公开列出、仅自己可见的文章需要分享链接",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.ArticleInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	expireAt := time.Now().Add(time.Duration(expire) * time.Hour)
+	token, err := h.shareKey.Sign(ArticleShareClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expireAt),
+		},
+		Aid: req.Id,
+	})
+	if err != nil {
+		return ginx.Result{
+			Code: errs.ArticleInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	return ginx.Result{
+		Data: ArticleShareVo{
+			Token:    token,
+			ExpireAt: expireAt.UnixMilli(),
+		},
+	}, nil
+}
+
+// parseShareToken 返回 token 对应的文章 ID，签名不对或者过期了返回 0
+func (h *ArticleHandler) parseShareToken(token string) int64 {
+	var sc ArticleShareClaims
+	t, err := jwt.ParseWithClaims(token, &sc, h.shareKey.Keyfunc)
+	if err != nil || !t.Valid {
+		return 0
+	}
+	return sc.Aid
+}
@@ -0,0 +1,209 @@
+package web
+
+import (
+	"bytes"
+	intrv1 "ddd_demo/api/proto/gen/intr/v1"
+	intrv1mocks "ddd_demo/api/proto/gen/intr/v1/mocks"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	svcmocks "ddd_demo/internal/service/mocks"
+	ijwt "ddd_demo/internal/web/jwt"
+	"ddd_demo/pkg/ginx"
+	"ddd_demo/pkg/logger"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestArticleHandler_Share(t *testing.T) {
+	keys := ijwt.Keys{Share: ijwt.NewHMACKeyProvider([]byte("share-key"))}
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) service.ArticleService
+		reqBody string
+
+		wantCode   int
+		wantExpire time.Duration
+	}{
+		{
+			name: "默认 7 天",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().CheckShare(gomock.Any(), int64(123), int64(1)).Return(nil)
+				return svc
+			},
+			reqBody:    `{"id":1}`,
+			wantExpire: 7 * 24 * time.Hour,
+		},
+		{
+			name: "最多 30 天",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().CheckShare(gomock.Any(), int64(123), int64(1)).Return(nil)
+				return svc
+			},
+			reqBody:    `{"id":1,"expire":10000}`,
+			wantExpire: 30 * 24 * time.Hour,
+		},
+		{
+			name: "不能分享",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().CheckShare(gomock.Any(), int64(123), int64(1)).
+					Return(service.ErrArticleNotShareable)
+				return svc
+			},
+			reqBody:  `{"id":1}`,
+			wantCode: errs.ArticleInvalidInput,
+		},
+		{
+			name: "别人的文章",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().CheckShare(gomock.Any(), int64(123), int64(1)).
+					Return(service.ErrArticleInvisible)
+				return svc
+			},
+			reqBody:  `{"id":1}`,
+			wantCode: errs.ArticleForbidden,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			hdl := NewArticleHandler(logger.NewNopLogger(), tc.mock(ctrl), nil, nil, keys)
+			server := gin.Default()
+			server.Use(func(ctx *gin.Context) {
+				ctx.Set("user", ijwt.UserClaims{
+					Uid: 123,
+				})
+			})
+			hdl.RegisterRoutes(server)
+
+			req, err := http.NewRequest(http.MethodPost,
+				"/articles/share", bytes.NewReader([]byte(tc.reqBody)))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var res struct {
+				Code int            `json:"code"`
+				Data ArticleShareVo `json:"data"`
+			}
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantCode, res.Code)
+			if tc.wantCode != 0 {
+				return
+			}
+			// 签出来的 token 只能看这一篇
+			assert.Equal(t, int64(1), hdl.parseShareToken(res.Data.Token))
+			expire := time.Until(time.UnixMilli(res.Data.ExpireAt))
+			assert.True(t, tc.wantExpire-expire < time.Minute)
+		})
+	}
+}
+
+func TestArticleHandler_PubDetailShare(t *testing.T) {
+	key := ijwt.NewHMACKeyProvider([]byte("share-key"))
+	sign := func(aid int64, expire time.Duration) string {
+		token, err := key.Sign(ArticleShareClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(expire)),
+			},
+			Aid: aid,
+		})
+		require.NoError(t, err)
+		return token
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) service.ArticleService
+		url  string
+
+		wantRes ginx.Result
+	}{
+		{
+			name: "分享的是别的文章",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				return svcmocks.NewMockArticleService(ctrl)
+			},
+			url: "/articles/pub/1?share=" + sign(2, time.Hour),
+			wantRes: ginx.Result{
+				Code: errs.ArticleForbidden,
+				Msg:  "分享链接无效或者已经过期",
+			},
+		},
+		{
+			name: "过期了",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				return svcmocks.NewMockArticleService(ctrl)
+			},
+			url: "/articles/pub/1?share=" + sign(1, -time.Hour),
+			wantRes: ginx.Result{
+				Code: errs.ArticleForbidden,
+				Msg:  "分享链接无效或者已经过期",
+			},
+		},
+		{
+			name: "没有权限",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().GetPubById(gomock.Any(), int64(1), int64(123), false).
+					Return(domain.Article{}, service.ErrArticleInvisible)
+				return svc
+			},
+			url: "/articles/pub/1",
+			wantRes: ginx.Result{
+				Code: errs.ArticleForbidden,
+				Msg:  "没有权限查看这篇文章",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// 查看文章的时候会同时去查互动数据
+			intrSvc := intrv1mocks.NewMockInteractiveServiceClient(ctrl)
+			intrSvc.EXPECT().Get(gomock.Any(), gomock.Any()).
+				Return(&intrv1.GetResponse{Intr: &intrv1.Interactive{}}, nil).AnyTimes()
+			seriesSvc := svcmocks.NewMockSeriesService(ctrl)
+			seriesSvc.EXPECT().Nav(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(domain.SeriesNav{}, service.ErrSeriesNotFound).AnyTimes()
+			hdl := NewArticleHandler(logger.NewNopLogger(), tc.mock(ctrl),
+				seriesSvc, intrSvc, ijwt.Keys{Share: key})
+			server := gin.Default()
+			server.Use(func(ctx *gin.Context) {
+				ctx.Set("user", ijwt.UserClaims{
+					Uid: 123,
+				})
+			})
+			hdl.RegisterRoutes(server)
+
+			req, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var res ginx.Result
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
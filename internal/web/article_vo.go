@@ -1,39 +1,126 @@
 package web
 
+import "ddd_demo/pkg/diffx"
+
 type ArticleVo struct {
-	Id         int64  `json:"id,omitempty"`
-	Title      string `json:"title,omitempty"`
-	Abstract   string `json:"abstract,omitempty"`
-	Content    string `json:"content,omitempty"`
-	AuthorId   int64  `json:"authorId,omitempty"`
-	AuthorName string `json:"authorName,omitempty"`
-	Status     uint8  `json:"status,omitempty"`
-	Ctime      string `json:"ctime,omitempty"`
-	Utime      string `json:"utime,omitempty"`
+	Id       int64  `json:"id,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Abstract string `json:"abstract,omitempty"`
+	Content  string `json:"content,omitempty"`
+	// Html 渲染好的内容，前端直接展示，Content 是原始的 Markdown
+	Html       string             `json:"html,omitempty"`
+	Toc        []ArticleHeadingVo `json:"toc,omitempty"`
+	AuthorId   int64              `json:"authorId,omitempty"`
+	AuthorName string             `json:"authorName,omitempty"`
+	Status     uint8              `json:"status,omitempty"`
+	Visibility uint8              `json:"visibility,omitempty"`
+	Revision   int64              `json:"revision,omitempty"`
+	Version    int64              `json:"version,omitempty"`
+	PublishAt  string             `json:"publishAt,omitempty"`
+	Tags       []string           `json:"tags,omitempty"`
+	Ctime      string             `json:"ctime,omitempty"`
+	Utime      string             `json:"utime,omitempty"`
 
 	ReadCnt    int64 `json:"readCnt"`
 	LikeCnt    int64 `json:"likeCnt"`
 	CollectCnt int64 `json:"collectCnt"`
+	CommentCnt int64 `json:"commentCnt"`
 	Liked      bool  `json:"liked"`
 	Collected  bool  `json:"collected"`
+	// Series 文章所在的专栏，不在专栏里面就没有
+	Series *SeriesNavVo `json:"series,omitempty"`
+}
+
+// ArticleHeadingVo 目录里面的一项，Id 是 HTML 里面标题的 id
+type ArticleHeadingVo struct {
+	Level int    `json:"level"`
+	Id    string `json:"id"`
+	Title string `json:"title"`
+}
+
+type ArticleListVo struct {
+	Articles []ArticleVo `json:"articles"`
+	// Cursor 下一页的游标，空字符串就是没有下一页了
+	Cursor string `json:"cursor"`
 }
 
 type PublishReq struct {
 	Id      int64
-	Title   string `json:"title"`
-	Content string `json:"content"`
+	Title   string   `json:"title"`
+	Content string   `json:"content"`
+	Tags    []string `json:"tags"`
+	// Visibility 可见范围，不传就是公开
+	Visibility uint8 `json:"visibility"`
+	// Version 和 ArticleEditReq 一样
+	Version int64 `json:"version"`
 }
 
 type ArticleEditReq struct {
-	Id      int64
+	Id         int64
+	Title      string   `json:"title"`
+	Content    string   `json:"content"`
+	Tags       []string `json:"tags"`
+	Visibility uint8    `json:"visibility"`
+	// Version 编辑器打开的时候拿到的版本号，之后用每次保存返回的。新建的文章不用传
+	Version int64 `json:"version"`
+}
+
+// ArticleAutoSaveReq 自动保存只有标题和内容，标签、可见范围等到作者手动保存的时候再改
+type ArticleAutoSaveReq struct {
+	Id      int64  `json:"id"`
 	Title   string `json:"title"`
 	Content string `json:"content"`
+	Version int64  `json:"version"`
+}
+
+// ArticleSaveVo 保存之后的版本号，下一次保存的时候带上
+type ArticleSaveVo struct {
+	Id      int64 `json:"id"`
+	Version int64 `json:"version"`
 }
 
 type ArticleWithdrawReq struct {
 	Id int64
 }
 
+type ArticleScheduleReq struct {
+	Id      int64
+	Title   string   `json:"title"`
+	Content string   `json:"content"`
+	Tags    []string `json:"tags"`
+	// PublishAt 定时发表的时间，毫秒数
+	PublishAt  int64 `json:"publishAt"`
+	Visibility uint8 `json:"visibility"`
+	Version    int64 `json:"version"`
+}
+
+type ArticleRescheduleReq struct {
+	Id        int64 `json:"id"`
+	PublishAt int64 `json:"publishAt"`
+}
+
+type ArticleCancelScheduleReq struct {
+	Id int64 `json:"id"`
+}
+
+type ArticleVisibilityReq struct {
+	Id         int64 `json:"id"`
+	Visibility uint8 `json:"visibility"`
+}
+
+type ArticleShareReq struct {
+	Id int64 `json:"id"`
+	// Expire 分享链接多少个小时之后过期，不传就是 7 天，最多 30 天
+	Expire int `json:"expire"`
+}
+
+type ArticleShareVo struct {
+	// Token 放到 /articles/pub/:id?share= 后面
+	Token string `json:"token"`
+	// ExpireAt 过期时间，毫秒数
+	ExpireAt int64 `json:"expireAt"`
+}
+
 type ArticleLikeReq struct {
 	Id int64 `json:"id"`
 	// true 是点赞，false 是不点赞
@@ -44,3 +131,33 @@ type ArticleCollectReq struct {
 	Id  int64 `json:"id"`
 	Cid int64 `json:"cid"`
 }
+
+type ArticleRevisionListReq struct {
+	Offset int `form:"offset"`
+	Limit  int `form:"limit"`
+}
+
+type ArticleRevisionDiffReq struct {
+	From int64 `form:"from"`
+	To   int64 `form:"to"`
+}
+
+type ArticleRevisionRestoreReq struct {
+	Id      int64 `json:"id"`
+	Version int64 `json:"version"`
+}
+
+type ArticleRevisionVo struct {
+	Version int64  `json:"version"`
+	Title   string `json:"title"`
+	Content string `json:"content,omitempty"`
+	Hash    string `json:"hash"`
+	Ctime   string `json:"ctime"`
+}
+
+type ArticleRevisionDiffVo struct {
+	From    ArticleRevisionVo `json:"from"`
+	To      ArticleRevisionVo `json:"to"`
+	Title   []diffx.Line      `json:"title"`
+	Content []diffx.Line      `json:"content"`
+}
@@ -0,0 +1,161 @@
+package web
+
+import (
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	ijwt "ddd_demo/internal/web/jwt"
+	"ddd_demo/pkg/ginx"
+	"ddd_demo/pkg/logger"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+)
+
+// AttachmentHandler 文章里面用的图片和附件
+type AttachmentHandler struct {
+	svc service.AttachmentService
+	l   logger.LoggerV1
+}
+
+func NewAttachmentHandler(svc service.AttachmentService, l logger.LoggerV1) *AttachmentHandler {
+	return &AttachmentHandler{
+		svc: svc,
+		l:   l,
+	}
+}
+
+func (h *AttachmentHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/attachments")
+	// multipart 表单，文件放在 file 字段里面
+	g.POST("/upload", ginx.WrapClaims(h.Upload))
+	// 下载不需要登录，返回的 URL 直接写在文章里面
+	g.GET("/:key", h.Download)
+	g.GET("/:key/thumb", h.Download)
+}
+
+func (h *AttachmentHandler) Upload(ctx *gin.Context,
+	uc ijwt.UserClaims) (ginx.Result, error) {
+	// 多留 1MB 给 multipart 的边界和表单里面的其它字段
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, service.MaxAttachmentSize+1<<20)
+	fh, err := ctx.FormFile("file")
+	if err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			return h.tooLarge(), nil
+		}
+		return ginx.Result{
+			Code: errs.AttachmentInvalidInput,
+			Msg:  "请选择要上传的文件",
+		}, nil
+	}
+	if fh.Size > service.MaxAttachmentSize {
+		return h.tooLarge(), nil
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return ginx.Result{
+			Code: errs.AttachmentInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.AttachmentInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	att, err := h.svc.Upload(ctx, uc.Uid, filepath.Base(fh.Filename), data)
+	switch err {
+	case nil:
+		return ginx.Result{
+			Data: h.toVo(att),
+		}, nil
+	case service.ErrAttachmentTooLarge:
+		return h.tooLarge(), nil
+	case service.ErrAttachmentTypeNotAllowed:
+		return ginx.Result{
+			Code: errs.AttachmentInvalidInput,
+			Msg:  "只支持 JPEG、PNG、GIF 图片和 PDF 文件",
+		}, nil
+	case service.ErrInvalidAttachment:
+		return ginx.Result{
+			Code: errs.AttachmentInvalidInput,
+			Msg:  "文件已经损坏",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.AttachmentInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+func (h *AttachmentHandler) tooLarge() ginx.Result {
+	return ginx.Result{
+		Code: errs.AttachmentTooLarge,
+		Msg:  "文件不能超过 10MB",
+	}
+}
+
+// Download 直接返回文件内容，所以不能用 ginx 包装。
+// 内容和 key 一一对应，永远不会变，浏览器和 CDN 可以一直缓存
+func (h *AttachmentHandler) Download(ctx *gin.Context) {
+	key := ctx.Param("key")
+	thumb := ctx.FullPath() == "/attachments/:key/thumb"
+	att, rc, err := h.svc.Open(ctx, key, thumb)
+	switch err {
+	case nil:
+		defer rc.Close()
+		contentType := att.MimeType
+		if thumb && att.HasThumb() {
+			contentType = att.ThumbMimeType
+		}
+		ctx.DataFromReader(http.StatusOK, -1, contentType, rc, map[string]string{
+			"Cache-Control":          "public, max-age=31536000, immutable",
+			"Content-Disposition":    mime.FormatMediaType("inline", map[string]string{"filename": att.Name}),
+			"X-Content-Type-Options": "nosniff",
+		})
+	case service.ErrAttachmentNotFound:
+		ctx.AbortWithStatus(http.StatusNotFound)
+	default:
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		h.l.Error("读取附件失败",
+			logger.String("key", key),
+			logger.Error(err))
+	}
+}
+
+func (h *AttachmentHandler) toVo(att domain.Attachment) AttachmentVo {
+	vo := AttachmentVo{
+		Key:      att.Key,
+		Name:     att.Name,
+		MimeType: att.MimeType,
+		Size:     att.Size,
+		Width:    att.Width,
+		Height:   att.Height,
+		Url:      "/attachments/" + att.Key,
+	}
+	if att.HasThumb() {
+		vo.ThumbUrl = vo.Url + "/thumb"
+	}
+	return vo
+}
+
+type AttachmentVo struct {
+	Key      string `json:"key"`
+	Name     string `json:"name"`
+	MimeType string `json:"mimeType"`
+	Size     int64  `json:"size"`
+	Width    int    `json:"width,omitempty"`
+	Height   int    `json:"height,omitempty"`
+	// Url 写到文章里面的链接，保存的时候会从内容里面解析出引用了哪些附件
+	Url string `json:"url"`
+	// ThumbUrl 没有缩略图的时候是空的
+	ThumbUrl string `json:"thumbUrl,omitempty"`
+}
@@ -0,0 +1,193 @@
+package web
+
+import (
+	"bytes"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	svcmocks "ddd_demo/internal/service/mocks"
+	ijwt "ddd_demo/internal/web/jwt"
+	"ddd_demo/pkg/ginx"
+	"ddd_demo/pkg/logger"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAttachmentHandler_Upload(t *testing.T) {
+	key := strings.Repeat("a", 64)
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) service.AttachmentService
+		// field 为空就是没有带文件
+		field string
+
+		wantRes ginx.Result
+	}{
+		{
+			name: "上传成功",
+			mock: func(ctrl *gomock.Controller) service.AttachmentService {
+				svc := svcmocks.NewMockAttachmentService(ctrl)
+				svc.EXPECT().Upload(gomock.Any(), int64(123), "a.png", []byte("data")).
+					Return(domain.Attachment{Key: key, Name: "a.png", MimeType: "image/png",
+						Size: 4, Width: 640, Height: 320, ThumbMimeType: "image/png"}, nil)
+				return svc
+			},
+			field: "file",
+			wantRes: ginx.Result{
+				Data: map[string]any{
+					"key":      key,
+					"name":     "a.png",
+					"mimeType": "image/png",
+					"size":     float64(4),
+					"width":    float64(640),
+					"height":   float64(320),
+					"url":      "/attachments/" + key,
+					"thumbUrl": "/attachments/" + key + "/thumb",
+				},
+			},
+		},
+		{
+			name: "没有文件",
+			mock: func(ctrl *gomock.Controller) service.AttachmentService {
+				return svcmocks.NewMockAttachmentService(ctrl)
+			},
+			field: "other",
+			wantRes: ginx.Result{
+				Code: errs.AttachmentInvalidInput,
+				Msg:  "请选择要上传的文件",
+			},
+		},
+		{
+			name: "类型不支持",
+			mock: func(ctrl *gomock.Controller) service.AttachmentService {
+				svc := svcmocks.NewMockAttachmentService(ctrl)
+				svc.EXPECT().Upload(gomock.Any(), int64(123), "a.png", []byte("data")).
+					Return(domain.Attachment{}, service.ErrAttachmentTypeNotAllowed)
+				return svc
+			},
+			field: "file",
+			wantRes: ginx.Result{
+				Code: errs.AttachmentInvalidInput,
+				Msg:  "只支持 JPEG、PNG、GIF 图片和 PDF 文件",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			hdl := NewAttachmentHandler(tc.mock(ctrl), logger.NewNopLogger())
+			server := gin.Default()
+			server.Use(func(ctx *gin.Context) {
+				ctx.Set("user", ijwt.UserClaims{
+					Uid: 123,
+				})
+			})
+			hdl.RegisterRoutes(server)
+
+			var body bytes.Buffer
+			w := multipart.NewWriter(&body)
+			fw, err := w.CreateFormFile(tc.field, "a.png")
+			require.NoError(t, err)
+			_, err = fw.Write([]byte("data"))
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+			req, err := http.NewRequest(http.MethodPost, "/attachments/upload", &body)
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", w.FormDataContentType())
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var res ginx.Result
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+func TestAttachmentHandler_Download(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) service.AttachmentService
+		url  string
+
+		wantCode        int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name: "原图",
+			mock: func(ctrl *gomock.Controller) service.AttachmentService {
+				svc := svcmocks.NewMockAttachmentService(ctrl)
+				svc.EXPECT().Open(gomock.Any(), "abc", false).
+					Return(domain.Attachment{Key: "abc", Name: "a.jpg", MimeType: "image/jpeg",
+						ThumbMimeType: "image/png"}, io.NopCloser(strings.NewReader("原图")), nil)
+				return svc
+			},
+			url:             "/attachments/abc",
+			wantCode:        http.StatusOK,
+			wantContentType: "image/jpeg",
+			wantBody:        "原图",
+		},
+		{
+			name: "缩略图",
+			mock: func(ctrl *gomock.Controller) service.AttachmentService {
+				svc := svcmocks.NewMockAttachmentService(ctrl)
+				svc.EXPECT().Open(gomock.Any(), "abc", true).
+					Return(domain.Attachment{Key: "abc", Name: "a.jpg", MimeType: "image/jpeg",
+						ThumbMimeType: "image/png"}, io.NopCloser(strings.NewReader("缩略图")), nil)
+				return svc
+			},
+			url:             "/attachments/abc/thumb",
+			wantCode:        http.StatusOK,
+			wantContentType: "image/png",
+			wantBody:        "缩略图",
+		},
+		{
+			name: "不存在",
+			mock: func(ctrl *gomock.Controller) service.AttachmentService {
+				svc := svcmocks.NewMockAttachmentService(ctrl)
+				svc.EXPECT().Open(gomock.Any(), "abc", false).
+					Return(domain.Attachment{}, nil, service.ErrAttachmentNotFound)
+				return svc
+			},
+			url:      "/attachments/abc",
+			wantCode: http.StatusNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			hdl := NewAttachmentHandler(tc.mock(ctrl), logger.NewNopLogger())
+			server := gin.Default()
+			hdl.RegisterRoutes(server)
+
+			req, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tc.wantCode, recorder.Code)
+			if tc.wantCode != http.StatusOK {
+				return
+			}
+			assert.Equal(t, tc.wantContentType, recorder.Header().Get("Content-Type"))
+			assert.Equal(t, "public, max-age=31536000, immutable", recorder.Header().Get("Cache-Control"))
+			assert.Equal(t, tc.wantBody, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,297 @@
+package web
+
+import (
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	ijwt "ddd_demo/internal/web/jwt"
+	"ddd_demo/pkg/ginx"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultCommentPageSize = 20
+	maxCommentPageSize     = 50
+	commentSortLikes       = "likes"
+)
+
+// CommentHandler 已经发表的文章的评论
+type CommentHandler struct {
+	svc     service.CommentService
+	userSvc service.UserService
+	biz     string
+}
+
+func NewCommentHandler(svc service.CommentService,
+	userSvc service.UserService) *CommentHandler {
+	return &CommentHandler{
+		svc:     svc,
+		userSvc: userSvc,
+		biz:     "article",
+	}
+}
+
+func (h *CommentHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/comments")
+	g.POST("/create", ginx.WrapBodyAndClaims(h.Create))
+	g.POST("/delete", ginx.WrapBodyAndClaims(h.Delete))
+	g.POST("/like", ginx.WrapBodyAndClaims(h.Like))
+	// GET /comments/article/123?sort=likes&cursor=10_456&limit=20
+	g.GET("/article/:aid", ginx.WrapBodyAndClaims(h.List))
+	// GET /comments/456/replies?cursor=789&limit=20
+	g.GET("/:id/replies", ginx.WrapBodyAndClaims(h.Replies))
+}
+
+// Create 发表评论或者回复，返回评论的 ID
+func (h *CommentHandler) Create(ctx *gin.Context,
+	req CommentCreateReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	id, err := h.svc.CreateComment(ctx, domain.Comment{
+		Biz:      h.biz,
+		BizId:    req.Aid,
+		Uid:      uc.Uid,
+		ParentId: req.ParentId,
+		Content:  req.Content,
+	})
+	switch err {
+	case nil:
+		return ginx.Result{
+			Data: id,
+		}, nil
+	case service.ErrInvalidComment:
+		return ginx.Result{
+			Code: errs.CommentInvalidInput,
+			Msg:  "评论内容不能为空，也不能超过 1000 个字",
+		}, nil
+	case service.ErrCommentBizNotFound:
+		return ginx.Result{
+			Code: errs.CommentInvalidInput,
+			Msg:  "文章不存在",
+		}, nil
+	case service.ErrCommentNotFound:
+		return ginx.Result{
+			Code: errs.CommentInvalidInput,
+			Msg:  "回复的评论不存在",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.CommentInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+func (h *CommentHandler) Delete(ctx *gin.Context,
+	req CommentDeleteReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.DeleteComment(ctx, uc.Uid, req.Id)
+	switch err {
+	case nil:
+		return ginx.Result{
+			Msg: "OK",
+		}, nil
+	case service.ErrNotCommentAuthor:
+		return ginx.Result{
+			Code: errs.CommentForbidden,
+			Msg:  "只能删除自己的评论",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.CommentInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+// Like Like 为 true 是点赞，false 是取消点赞
+func (h *CommentHandler) Like(ctx *gin.Context,
+	req CommentLikeReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	var err error
+	if req.Like {
+		err = h.svc.Like(ctx, uc.Uid, req.Id)
+	} else {
+		err = h.svc.CancelLike(ctx, uc.Uid, req.Id)
+	}
+	switch err {
+	case nil:
+		return ginx.Result{
+			Msg: "OK",
+		}, nil
+	case service.ErrCommentNotFound:
+		return ginx.Result{
+			Code: errs.CommentInvalidInput,
+			Msg:  "评论不存在",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.CommentInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+// List 文章的顶级评论
+func (h *CommentHandler) List(ctx *gin.Context,
+	req CommentListReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	aid, err := strconv.ParseInt(ctx.Param("aid"), 10, 64)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.CommentInvalidInput,
+			Msg:  "aid 参数错误",
+		}, nil
+	}
+	sort := domain.CommentSortByTime
+	if req.Sort == commentSortLikes {
+		sort = domain.CommentSortByLikes
+	}
+	cursor, err := h.parseCursor(sort, req.Cursor)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.CommentInvalidInput,
+			Msg:  "cursor 参数错误",
+		}, nil
+	}
+	limit := h.pageSize(req.Limit)
+	cs, err := h.svc.GetComments(ctx, uc.Uid, h.biz, aid, sort, cursor, limit)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.CommentInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	vo := CommentListVo{Comments: h.toVos(ctx, cs)}
+	// 不满一页说明已经没有了
+	if len(cs) == limit {
+		vo.Cursor = h.formatCursor(sort, cs[len(cs)-1])
+	}
+	return ginx.Result{Data: vo}, nil
+}
+
+// Replies 顶级评论下面的回复
+func (h *CommentHandler) Replies(ctx *gin.Context,
+	req CommentReplyListReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	rootId, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.CommentInvalidInput,
+			Msg:  "id 参数错误",
+		}, nil
+	}
+	limit := h.pageSize(req.Limit)
+	cs, err := h.svc.GetReplies(ctx, uc.Uid, rootId, max(req.Cursor, 0), limit)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.CommentInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	vo := CommentListVo{Comments: h.toVos(ctx, cs)}
+	if len(cs) == limit {
+		vo.Cursor = strconv.FormatInt(cs[len(cs)-1].Id, 10)
+	}
+	return ginx.Result{Data: vo}, nil
+}
+
+// parseCursor 按照时间排序的游标是 id，按照点赞排序的是 likeCnt_id
+func (h *CommentHandler) parseCursor(sort domain.CommentSort, cursor string) (domain.CommentCursor, error) {
+	var res domain.CommentCursor
+	if cursor == "" {
+		return res, nil
+	}
+	var err error
+	if sort == domain.CommentSortByLikes {
+		_, err = fmt.Sscanf(cursor, "%d_%d", &res.LikeCnt, &res.Id)
+	} else {
+		res.Id, err = strconv.ParseInt(cursor, 10, 64)
+	}
+	return res, err
+}
+
+func (h *CommentHandler) formatCursor(sort domain.CommentSort, c domain.Comment) string {
+	if sort == domain.CommentSortByLikes {
+		return fmt.Sprintf("%d_%d", c.LikeCnt, c.Id)
+	}
+	return strconv.FormatInt(c.Id, 10)
+}
+
+func (h *CommentHandler) toVos(ctx *gin.Context, cs []domain.Comment) []CommentVo {
+	res := make([]CommentVo, 0, len(cs))
+	for _, c := range cs {
+		vo := CommentVo{
+			Id:       c.Id,
+			Uid:      c.Uid,
+			Content:  c.Content,
+			RootId:   c.RootId,
+			ParentId: c.ParentId,
+			LikeCnt:  c.LikeCnt,
+			ReplyCnt: c.ReplyCnt,
+			Liked:    c.Liked,
+			Ctime:    c.Ctime.Format(time.DateTime),
+		}
+		// 用户信息有缓存，一页最多 50 条。
+		// 查不到昵称也照样显示评论，注销了的用户就没有昵称
+		u, err := h.userSvc.FindById(ctx, c.Uid)
+		if err == nil {
+			vo.Nickname = u.Nickname
+		}
+		res = append(res, vo)
+	}
+	return res
+}
+
+func (h *CommentHandler) pageSize(limit int) int {
+	if limit <= 0 {
+		return defaultCommentPageSize
+	}
+	return min(limit, maxCommentPageSize)
+}
+
+type CommentCreateReq struct {
+	// Aid 评论的文章
+	Aid int64 `json:"aid"`
+	// ParentId 回复的评论，发表顶级评论不传
+	ParentId int64  `json:"parentId"`
+	Content  string `json:"content"`
+}
+
+type CommentDeleteReq struct {
+	Id int64 `json:"id"`
+}
+
+type CommentLikeReq struct {
+	Id   int64 `json:"id"`
+	Like bool  `json:"like"`
+}
+
+type CommentListReq struct {
+	// Sort 传 likes 按照点赞数排序，默认按照时间排序
+	Sort string `form:"sort"`
+	// Cursor 上一页返回的 cursor，第一页不传
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+type CommentReplyListReq struct {
+	Cursor int64 `form:"cursor"`
+	Limit  int   `form:"limit"`
+}
+
+type CommentListVo struct {
+	Comments []CommentVo `json:"comments"`
+	// Cursor 下一页的游标，空字符串就是没有下一页了
+	Cursor string `json:"cursor"`
+}
+
+type CommentVo struct {
+	Id       int64  `json:"id"`
+	Uid      int64  `json:"uid"`
+	Nickname string `json:"nickname"`
+	Content  string `json:"content"`
+	RootId   int64  `json:"rootId"`
+	ParentId int64  `json:"parentId"`
+	LikeCnt  int64  `json:"likeCnt"`
+	ReplyCnt int64  `json:"replyCnt"`
+	Liked    bool   `json:"liked"`
+	Ctime    string `json:"ctime"`
+}
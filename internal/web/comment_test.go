@@ -0,0 +1,125 @@
+package web
+
+import (
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	svcmocks "ddd_demo/internal/service/mocks"
+	ijwt "ddd_demo/internal/web/jwt"
+	"ddd_demo/pkg/ginx"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCommentHandler_List(t *testing.T) {
+	ctime := time.UnixMilli(1000)
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (service.CommentService, service.UserService)
+		url  string
+
+		wantRes ginx.Result
+	}{
+		{
+			name: "按照点赞数翻页",
+			mock: func(ctrl *gomock.Controller) (service.CommentService, service.UserService) {
+				svc := svcmocks.NewMockCommentService(ctrl)
+				svc.EXPECT().GetComments(gomock.Any(), int64(123), "article", int64(1),
+					domain.CommentSortByLikes, domain.CommentCursor{Id: 456, LikeCnt: 10}, 1).
+					Return([]domain.Comment{{
+						Id:       455,
+						Uid:      2,
+						Content:  "写得好",
+						LikeCnt:  10,
+						ReplyCnt: 3,
+						Liked:    true,
+						Ctime:    ctime,
+					}}, nil)
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				userSvc.EXPECT().FindById(gomock.Any(), int64(2)).
+					Return(domain.User{Id: 2, Nickname: "读者"}, nil)
+				return svc, userSvc
+			},
+			url: "/comments/article/1?sort=likes&cursor=10_456&limit=1",
+			wantRes: ginx.Result{
+				Data: map[string]any{
+					"comments": []any{map[string]any{
+						"id":       float64(455),
+						"uid":      float64(2),
+						"nickname": "读者",
+						"content":  "写得好",
+						"rootId":   float64(0),
+						"parentId": float64(0),
+						"likeCnt":  float64(10),
+						"replyCnt": float64(3),
+						"liked":    true,
+						"ctime":    ctime.Format(time.DateTime),
+					}},
+					"cursor": "10_455",
+				},
+			},
+		},
+		{
+			name: "最后一页",
+			mock: func(ctrl *gomock.Controller) (service.CommentService, service.UserService) {
+				svc := svcmocks.NewMockCommentService(ctrl)
+				svc.EXPECT().GetComments(gomock.Any(), int64(123), "article", int64(1),
+					domain.CommentSortByTime, domain.CommentCursor{Id: 456}, 20).
+					Return([]domain.Comment{}, nil)
+				return svc, svcmocks.NewMockUserService(ctrl)
+			},
+			url: "/comments/article/1?cursor=456",
+			wantRes: ginx.Result{
+				Data: map[string]any{
+					"comments": []any{},
+					"cursor":   "",
+				},
+			},
+		},
+		{
+			name: "游标格式不对",
+			mock: func(ctrl *gomock.Controller) (service.CommentService, service.UserService) {
+				return svcmocks.NewMockCommentService(ctrl), svcmocks.NewMockUserService(ctrl)
+			},
+			url: "/comments/article/1?sort=likes&cursor=abc",
+			wantRes: ginx.Result{
+				Code: errs.CommentInvalidInput,
+				Msg:  "cursor 参数错误",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc, userSvc := tc.mock(ctrl)
+			hdl := NewCommentHandler(svc, userSvc)
+			server := gin.Default()
+			server.Use(func(ctx *gin.Context) {
+				ctx.Set("user", ijwt.UserClaims{
+					Uid: 123,
+				})
+			})
+			hdl.RegisterRoutes(server)
+
+			req, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var res ginx.Result
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
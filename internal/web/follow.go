@@ -0,0 +1,291 @@
+package web
+
+import (
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	ijwt "ddd_demo/internal/web/jwt"
+	"ddd_demo/pkg/ginx"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultFollowPageSize = 20
+	maxFollowPageSize     = 100
+)
+
+// FollowHandler 关注关系和用户的公开主页
+type FollowHandler struct {
+	svc     service.FollowService
+	userSvc service.UserService
+	artSvc  service.ArticleService
+}
+
+func NewFollowHandler(svc service.FollowService,
+	userSvc service.UserService,
+	artSvc service.ArticleService) *FollowHandler {
+	return &FollowHandler{
+		svc:     svc,
+		userSvc: userSvc,
+		artSvc:  artSvc,
+	}
+}
+
+func (h *FollowHandler) RegisterRoutes(server *gin.Engine) {
+	ug := server.Group("/users")
+	ug.POST("/follow", ginx.WrapBodyAndClaims(h.Follow))
+	ug.POST("/follow/cancel", ginx.WrapBodyAndClaims(h.CancelFollow))
+	// GET /users/123/followers?cursor=456&limit=20
+	ug.GET("/:id/followers", ginx.WrapBodyAndClaims(h.Followers))
+	ug.GET("/:id/followees", ginx.WrapBodyAndClaims(h.Followees))
+	// GET /users/123/public?offset=0&limit=20
+	ug.GET("/:id/public", ginx.WrapBodyAndClaims(h.Public))
+}
+
+func (h *FollowHandler) Follow(ctx *gin.Context,
+	req FollowReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.Follow(ctx, uc.Uid, req.Followee)
+	switch err {
+	case nil:
+		return ginx.Result{
+			Msg: "OK",
+		}, nil
+	case service.ErrFollowSelf:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "不能关注自己",
+		}, nil
+	case service.ErrUserNotFound:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "用户不存在",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+func (h *FollowHandler) CancelFollow(ctx *gin.Context,
+	req FollowReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.CancelFollow(ctx, uc.Uid, req.Followee)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	return ginx.Result{
+		Msg: "OK",
+	}, nil
+}
+
+// Followers 粉丝列表
+func (h *FollowHandler) Followers(ctx *gin.Context,
+	req FollowListReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	uid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "id 参数错误",
+		}, nil
+	}
+	limit := h.pageSize(req.Limit)
+	rs, err := h.svc.GetFollowers(ctx, uid, req.Cursor, limit)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	return h.toListResult(ctx, rs, limit, func(r domain.FollowRelation) int64 {
+		return r.Follower
+	})
+}
+
+// Followees 关注列表
+func (h *FollowHandler) Followees(ctx *gin.Context,
+	req FollowListReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	uid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "id 参数错误",
+		}, nil
+	}
+	limit := h.pageSize(req.Limit)
+	rs, err := h.svc.GetFollowees(ctx, uid, req.Cursor, limit)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	return h.toListResult(ctx, rs, limit, func(r domain.FollowRelation) int64 {
+		return r.Followee
+	})
+}
+
+func (h *FollowHandler) toListResult(ctx *gin.Context,
+	rs []domain.FollowRelation, limit int,
+	uidOf func(r domain.FollowRelation) int64) (ginx.Result, error) {
+	users := make([]FollowUserVo, 0, len(rs))
+	for _, r := range rs {
+		// 用户信息有缓存，一页最多 100 个
+		u, err := h.userSvc.FindById(ctx, uidOf(r))
+		if err == service.ErrUserNotFound {
+			// 账号被合并或者注销了
+			continue
+		}
+		if err != nil {
+			return ginx.Result{
+				Code: errs.UserInternalServerError,
+				Msg:  "系统错误",
+			}, err
+		}
+		users = append(users, FollowUserVo{
+			Id:         u.Id,
+			Nickname:   u.Nickname,
+			FollowTime: r.Ctime.Format(time.DateTime),
+		})
+	}
+	var next int64
+	// 不满一页说明已经没有了
+	if len(rs) == limit {
+		next = rs[len(rs)-1].Id
+	}
+	return ginx.Result{
+		Data: FollowListVo{
+			Users:  users,
+			Cursor: next,
+		},
+	}, nil
+}
+
+// Public 用户的公开主页，没有邮箱、手机号这些隐私信息
+func (h *FollowHandler) Public(ctx *gin.Context,
+	req PublicProfileReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	uid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "id 参数错误",
+		}, nil
+	}
+	u, err := h.userSvc.FindById(ctx, uid)
+	switch err {
+	case nil:
+	case service.ErrUserNotFound:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "用户不存在",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	statics, err := h.svc.GetFollowStatics(ctx, uid)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	var followed bool
+	if uc.Uid != uid {
+		followed, err = h.svc.Followed(ctx, uc.Uid, uid)
+		if err != nil {
+			return ginx.Result{
+				Code: errs.UserInternalServerError,
+				Msg:  "系统错误",
+			}, err
+		}
+	}
+	offset := req.Offset
+	if offset < 0 {
+		offset = 0
+	}
+	// 在数据库里过滤掉草稿和撤回的文章，这样每一页都是满的
+	// 关注了作者的人还能看到仅关注者可见的文章
+	arts, err := h.artSvc.ListPubByAuthor(ctx, uid, uc.Uid, offset, h.pageSize(req.Limit))
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	return ginx.Result{
+		Data: PublicProfileVo{
+			Id:        u.Id,
+			Nickname:  u.Nickname,
+			AboutMe:   u.AboutMe,
+			Followers: statics.Followers,
+			Followees: statics.Followees,
+			Followed:  followed,
+			Articles: slice.Map(arts, func(idx int, src domain.Article) ArticleVo {
+				return ArticleVo{
+					Id:       src.Id,
+					Title:    src.Title,
+					Abstract: src.Abstract(),
+					AuthorId: src.Author.Id,
+					Ctime:    src.Ctime.Format(time.DateTime),
+					Utime:    src.Utime.Format(time.DateTime),
+				}
+			}),
+		},
+	}, nil
+}
+
+func (h *FollowHandler) pageSize(limit int) int {
+	if limit <= 0 {
+		return defaultFollowPageSize
+	}
+	if limit > maxFollowPageSize {
+		return maxFollowPageSize
+	}
+	return limit
+}
+
+type FollowReq struct {
+	Followee int64 `json:"followee"`
+}
+
+type FollowListReq struct {
+	// 上一页返回的 cursor，第一页不用传
+	Cursor int64 `form:"cursor"`
+	Limit  int   `form:"limit"`
+}
+
+type PublicProfileReq struct {
+	Offset int `form:"offset"`
+	Limit  int `form:"limit"`
+}
+
+type FollowUserVo struct {
+	Id         int64  `json:"id"`
+	Nickname   string `json:"nickname"`
+	FollowTime string `json:"followTime"`
+}
+
+type FollowListVo struct {
+	Users []FollowUserVo `json:"users"`
+	// 下一页的 cursor，0 表示没有下一页了
+	Cursor int64 `json:"cursor"`
+}
+
+type PublicProfileVo struct {
+	Id        int64       `json:"id"`
+	Nickname  string      `json:"nickname"`
+	AboutMe   string      `json:"aboutMe"`
+	Followers int64       `json:"followers"`
+	Followees int64       `json:"followees"`
+	Followed  bool        `json:"followed"`
+	Articles  []ArticleVo `json:"articles"`
+}
@@ -0,0 +1,125 @@
+package web
+
+import (
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	svcmocks "ddd_demo/internal/service/mocks"
+	ijwt "ddd_demo/internal/web/jwt"
+	"ddd_demo/pkg/ginx"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFollowHandler_Public(t *testing.T) {
+	ctime := time.UnixMilli(1000)
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (service.FollowService,
+			service.UserService, service.ArticleService)
+		url string
+
+		wantRes ginx.Result
+	}{
+		{
+			name: "分页查询已经发表的文章",
+			mock: func(ctrl *gomock.Controller) (service.FollowService,
+				service.UserService, service.ArticleService) {
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				userSvc.EXPECT().FindById(gomock.Any(), int64(2)).
+					Return(domain.User{Id: 2, Nickname: "作者"}, nil)
+				followSvc := svcmocks.NewMockFollowService(ctrl)
+				followSvc.EXPECT().GetFollowStatics(gomock.Any(), int64(2)).
+					Return(domain.FollowStatics{Followers: 3, Followees: 1}, nil)
+				followSvc.EXPECT().Followed(gomock.Any(), int64(123), int64(2)).
+					Return(true, nil)
+				artSvc := svcmocks.NewMockArticleService(ctrl)
+				artSvc.EXPECT().ListPubByAuthor(gomock.Any(), int64(2), int64(123), 10, 5).
+					Return([]domain.Article{{
+						Id:      11,
+						Title:   "标题",
+						Content: "内容",
+						Author:  domain.Author{Id: 2},
+						Ctime:   ctime,
+						Utime:   ctime,
+					}}, nil)
+				return followSvc, userSvc, artSvc
+			},
+			url: "/users/2/public?offset=10&limit=5",
+			wantRes: ginx.Result{
+				Data: map[string]any{
+					"id":        float64(2),
+					"nickname":  "作者",
+					"aboutMe":   "",
+					"followers": float64(3),
+					"followees": float64(1),
+					"followed":  true,
+					"articles": []any{map[string]any{
+						"id":         float64(11),
+						"title":      "标题",
+						"abstract":   "内容",
+						"authorId":   float64(2),
+						"ctime":      ctime.Format(time.DateTime),
+						"utime":      ctime.Format(time.DateTime),
+						"readCnt":    float64(0),
+						"likeCnt":    float64(0),
+						"collectCnt": float64(0),
+						"commentCnt": float64(0),
+						"liked":      false,
+						"collected":  false,
+					}},
+				},
+			},
+		},
+		{
+			name: "用户不存在",
+			mock: func(ctrl *gomock.Controller) (service.FollowService,
+				service.UserService, service.ArticleService) {
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				userSvc.EXPECT().FindById(gomock.Any(), int64(2)).
+					Return(domain.User{}, service.ErrUserNotFound)
+				return svcmocks.NewMockFollowService(ctrl), userSvc,
+					svcmocks.NewMockArticleService(ctrl)
+			},
+			url: "/users/2/public",
+			wantRes: ginx.Result{
+				Code: errs.UserInvalidInput,
+				Msg:  "用户不存在",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			followSvc, userSvc, artSvc := tc.mock(ctrl)
+			hdl := NewFollowHandler(followSvc, userSvc, artSvc)
+			server := gin.Default()
+			server.Use(func(ctx *gin.Context) {
+				ctx.Set("user", ijwt.UserClaims{
+					Uid: 123,
+				})
+			})
+			hdl.RegisterRoutes(server)
+
+			req, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var res ginx.Result
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
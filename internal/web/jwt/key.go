@@ -0,0 +1,237 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"ddd_demo/pkg/jwtx"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"os"
+)
+
+// KeyProvider 管理签名和验签用的 key
+// 签名永远用当前的 key，验签的时候按照头部的 kid 找 key，
+// 所以轮换的时候新老 key 可以同时存在，不会有停机时间
+type KeyProvider interface {
+	// Sign 用当前的 key 签名，头部会带上 kid
+	Sign(claims jwt.Claims) (string, error)
+	// Keyfunc 按照 kid 找到验签的 key，可以直接传给 jwt.ParseWithClaims
+	Keyfunc(token *jwt.Token) (any, error)
+	// JWKS 可以公开出去的公钥，对称加密的 key 不会出现在这里
+	JWKS() jwtx.JWKSet
+}
+
+// Keys 不同用途的 token 用不同的 key
+type Keys struct {
+	// Access 短 token
+	Access KeyProvider
+	// Refresh 长 token
+	Refresh KeyProvider
+	// State OAuth2 的 state
+	State KeyProvider
+	// Share 文章的分享链接，有效期很长，和别的 key 分开，泄露了或者要让所有链接失效的时候单独轮换
+	Share KeyProvider
+}
+
+type KeyConfig struct {
+	// 没有 kid 的 token，会用 kid 为空的 key 来验签，用来兼容老的 token
+	Kid string `yaml:"kid"`
+	// HS512, RS256, ES256
+	Alg string `yaml:"alg"`
+	// 对称加密的密钥，Alg 是 HS512 的时候用
+	Secret string `yaml:"secret"`
+	// PEM 格式的私钥文件，只用来验签的老 key 可以不配。
+	// 私钥不能放进代码仓库，路径要指向部署的时候挂载进来的文件
+	PrivateKey string `yaml:"privateKey"`
+	// PrivateKeyEnv 从这个环境变量里面读 PEM 格式的私钥，和 PrivateKey 二选一
+	PrivateKeyEnv string `yaml:"privateKeyEnv"`
+	// PEM 格式的公钥文件，配了私钥的话可以不配
+	PublicKey string `yaml:"publicKey"`
+	// PublicKeyEnv 从这个环境变量里面读 PEM 格式的公钥，和 PublicKey 二选一
+	PublicKeyEnv string `yaml:"publicKeyEnv"`
+}
+
+type KeySetConfig struct {
+	// 当前用来签名的 kid
+	SigningKid string      `yaml:"signingKid"`
+	Keys       []KeyConfig `yaml:"keys"`
+}
+
+type key struct {
+	kid     string
+	method  jwt.SigningMethod
+	signKey any
+	// 对称加密的时候和 signKey 一样
+	verifyKey any
+}
+
+type StaticKeyProvider struct {
+	signing *key
+	keys    map[string]*key
+	jwks    jwtx.JWKSet
+}
+
+// NewHMACKeyProvider 只有一个对称密钥，签出来的 token 没有 kid
+func NewHMACKeyProvider(secret []byte) KeyProvider {
+	k := &key{
+		method:    jwt.SigningMethodHS512,
+		signKey:   secret,
+		verifyKey: secret,
+	}
+	return &StaticKeyProvider{
+		signing: k,
+		keys:    map[string]*key{"": k},
+		jwks:    jwtx.JWKSet{Keys: []jwtx.JWK{}},
+	}
+}
+
+// NewKeyProvider 按照配置加载 key，密钥文件从磁盘上读取
+func NewKeyProvider(cfg KeySetConfig) (KeyProvider, error) {
+	p := &StaticKeyProvider{
+		keys: make(map[string]*key, len(cfg.Keys)),
+		jwks: jwtx.JWKSet{Keys: []jwtx.JWK{}},
+	}
+	for _, kc := range cfg.Keys {
+		k, pub, err := loadKey(kc)
+		if err != nil {
+			return nil, fmt.Errorf("加载 key %s 失败 %w", kc.Kid, err)
+		}
+		if _, ok := p.keys[kc.Kid]; ok {
+			return nil, fmt.Errorf("kid %s 重复了", kc.Kid)
+		}
+		p.keys[kc.Kid] = k
+		if pub != nil && kc.Kid != "" {
+			jwk, err := jwtx.NewJWK(kc.Kid, kc.Alg, pub)
+			if err != nil {
+				return nil, err
+			}
+			p.jwks.Keys = append(p.jwks.Keys, jwk)
+		}
+	}
+	signing, ok := p.keys[cfg.SigningKid]
+	if !ok {
+		return nil, fmt.Errorf("找不到签名用的 key %s", cfg.SigningKid)
+	}
+	if signing.signKey == nil {
+		return nil, fmt.Errorf("签名用的 key %s 没有配置私钥", cfg.SigningKid)
+	}
+	p.signing = signing
+	return p, nil
+}
+
+func (p *StaticKeyProvider) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(p.signing.method, claims)
+	if p.signing.kid != "" {
+		token.Header["kid"] = p.signing.kid
+	}
+	return token.SignedString(p.signing.signKey)
+}
+
+func (p *StaticKeyProvider) Keyfunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	k, ok := p.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("未知的 kid %s", kid)
+	}
+	// 防止有人把 RS256 的 token 改成 HS512，拿公钥当密钥来伪造
+	if token.Method.Alg() != k.method.Alg() {
+		return nil, fmt.Errorf("kid %s 的签名算法不匹配 %s", kid, token.Method.Alg())
+	}
+	return k.verifyKey, nil
+}
+
+func (p *StaticKeyProvider) JWKS() jwtx.JWKSet {
+	return p.jwks
+}
+
+func loadKey(kc KeyConfig) (*key, crypto.PublicKey, error) {
+	k := &key{kid: kc.Kid}
+	switch kc.Alg {
+	case jwt.SigningMethodHS512.Alg():
+		if kc.Secret == "" {
+			return nil, nil, errors.New("没有配置 secret")
+		}
+		k.method = jwt.SigningMethodHS512
+		k.signKey = []byte(kc.Secret)
+		k.verifyKey = k.signKey
+		return k, nil, nil
+	case jwt.SigningMethodRS256.Alg():
+		k.method = jwt.SigningMethodRS256
+		return loadAsymmetricKey(k, kc,
+			func(data []byte) (crypto.Signer, error) {
+				return jwt.ParseRSAPrivateKeyFromPEM(data)
+			},
+			func(data []byte) (crypto.PublicKey, error) {
+				return jwt.ParseRSAPublicKeyFromPEM(data)
+			})
+	case jwt.SigningMethodES256.Alg():
+		k.method = jwt.SigningMethodES256
+		return loadAsymmetricKey(k, kc,
+			func(data []byte) (crypto.Signer, error) {
+				return jwt.ParseECPrivateKeyFromPEM(data)
+			},
+			func(data []byte) (crypto.PublicKey, error) {
+				return jwt.ParseECPublicKeyFromPEM(data)
+			})
+	default:
+		return nil, nil, fmt.Errorf("不支持的签名算法 %s", kc.Alg)
+	}
+}
+
+func loadAsymmetricKey(k *key, kc KeyConfig,
+	parsePrivate func(data []byte) (crypto.Signer, error),
+	parsePublic func(data []byte) (crypto.PublicKey, error)) (*key, crypto.PublicKey, error) {
+	var pub crypto.PublicKey
+	data, err := readPEM(kc.PrivateKey, kc.PrivateKeyEnv)
+	if err != nil {
+		return nil, nil, err
+	}
+	if data != nil {
+		priv, err := parsePrivate(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		k.signKey = priv
+		pub = priv.Public()
+	}
+	data, err = readPEM(kc.PublicKey, kc.PublicKeyEnv)
+	if err != nil {
+		return nil, nil, err
+	}
+	if data != nil {
+		pub, err = parsePublic(data)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if pub == nil {
+		return nil, nil, errors.New("私钥和公钥至少要配置一个")
+	}
+	switch pub.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+	default:
+		return nil, nil, fmt.Errorf("不支持的公钥类型 %T", pub)
+	}
+	k.verifyKey = pub
+	return k, pub, nil
+}
+
+// readPEM 优先从文件读，其次从环境变量读，都没有配置就返回 nil
+func readPEM(path string, env string) ([]byte, error) {
+	if path != "" && env != "" {
+		return nil, errors.New("文件和环境变量只能配置一个")
+	}
+	if path != "" {
+		return os.ReadFile(path)
+	}
+	if env == "" {
+		return nil, nil
+	}
+	val, ok := os.LookupEnv(env)
+	if !ok || val == "" {
+		return nil, fmt.Errorf("没有找到环境变量 %s", env)
+	}
+	return []byte(val), nil
+}
@@ -0,0 +1,249 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStaticKeyProvider_Rotate(t *testing.T) {
+	dir := t.TempDir()
+	oldKey := writeECKey(t, dir, "old.pem")
+	newKey := writeRSAKey(t, dir, "new.pem")
+
+	// 轮换之前，用老的 key 签名
+	before, err := NewKeyProvider(KeySetConfig{
+		SigningKid: "old",
+		Keys: []KeyConfig{
+			{Kid: "old", Alg: "ES256", PrivateKey: oldKey},
+		},
+	})
+	require.NoError(t, err)
+	oldToken, err := before.Sign(testClaims())
+	require.NoError(t, err)
+
+	// 轮换之后，用新的 key 签名，老的 key 还可以验签
+	after, err := NewKeyProvider(KeySetConfig{
+		SigningKid: "new",
+		Keys: []KeyConfig{
+			{Kid: "new", Alg: "RS256", PrivateKey: newKey},
+			{Kid: "old", Alg: "ES256", PrivateKey: oldKey},
+		},
+	})
+	require.NoError(t, err)
+	newToken, err := after.Sign(testClaims())
+	require.NoError(t, err)
+
+	for _, tokenStr := range []string{oldToken, newToken} {
+		var uc UserClaims
+		token, err := jwt.ParseWithClaims(tokenStr, &uc, after.Keyfunc)
+		require.NoError(t, err)
+		assert.True(t, token.Valid)
+		assert.Equal(t, int64(123), uc.Uid)
+	}
+
+	// 新的 token 老的服务不认识
+	_, err = jwt.ParseWithClaims(newToken, &UserClaims{}, before.Keyfunc)
+	assert.Error(t, err)
+
+	jwks := after.JWKS()
+	assert.Len(t, jwks.Keys, 2)
+	for _, k := range jwks.Keys {
+		_, err = k.PublicKey()
+		assert.NoError(t, err)
+	}
+}
+
+func TestStaticKeyProvider_Keyfunc(t *testing.T) {
+	dir := t.TempDir()
+	rsaKey := writeRSAKey(t, dir, "rsa.pem")
+	ecKey := writeECKey(t, dir, "ec.pem")
+	retiredKey := writeECKey(t, dir, "retired.pem")
+	cfg := KeySetConfig{
+		SigningKid: "rsa",
+		Keys: []KeyConfig{
+			{Kid: "hmac", Alg: "HS512", Secret: "secret"},
+			{Kid: "rsa", Alg: "RS256", PrivateKey: rsaKey},
+			{Kid: "ec", Alg: "ES256", PrivateKey: ecKey},
+		},
+	}
+	kp, err := NewKeyProvider(cfg)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+		// signer 用哪个 key 签名
+		signer KeySetConfig
+
+		wantErr bool
+	}{
+		{
+			name:   "HS512",
+			signer: KeySetConfig{SigningKid: "hmac", Keys: cfg.Keys},
+		},
+		{
+			name:   "RS256",
+			signer: KeySetConfig{SigningKid: "rsa", Keys: cfg.Keys},
+		},
+		{
+			name:   "ES256",
+			signer: KeySetConfig{SigningKid: "ec", Keys: cfg.Keys},
+		},
+		{
+			// 轮换的时候已经从配置里面删掉的 key，签出来的 token 不能再用了
+			name: "已经轮换掉的 kid",
+			signer: KeySetConfig{SigningKid: "retired", Keys: []KeyConfig{
+				{Kid: "retired", Alg: "ES256", PrivateKey: retiredKey},
+			}},
+			wantErr: true,
+		},
+		{
+			// kid 一样，但是私钥不是同一个
+			name: "kid 对得上，签名对不上",
+			signer: KeySetConfig{SigningKid: "ec", Keys: []KeyConfig{
+				{Kid: "ec", Alg: "ES256", PrivateKey: retiredKey},
+			}},
+			wantErr: true,
+		},
+		{
+			// 没有 kid 的 token 找 kid 为空的 key，这里没有配置
+			name: "没有 kid",
+			signer: KeySetConfig{SigningKid: "", Keys: []KeyConfig{
+				{Kid: "", Alg: "HS512", Secret: "secret"},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			signer, err := NewKeyProvider(tc.signer)
+			require.NoError(t, err)
+			tokenStr, err := signer.Sign(testClaims())
+			require.NoError(t, err)
+			var uc UserClaims
+			token, err := jwt.ParseWithClaims(tokenStr, &uc, kp.Keyfunc)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, token.Valid)
+			assert.Equal(t, int64(123), uc.Uid)
+		})
+	}
+	// 对称加密的 key 不能公开出去
+	jwks := kp.JWKS()
+	assert.Len(t, jwks.Keys, 2)
+	_, ok := jwks.Find("hmac")
+	assert.False(t, ok)
+}
+
+func TestNewKeyProvider_Env(t *testing.T) {
+	// testdata 里面的 key 只给测试用，线上的 key 部署的时候通过文件或者环境变量传进来
+	data, err := os.ReadFile("testdata/es256-test.pem")
+	require.NoError(t, err)
+	t.Setenv("WEBOOK_TEST_ACCESS_KEY", string(data))
+
+	kp, err := NewKeyProvider(KeySetConfig{
+		SigningKid: "test",
+		Keys: []KeyConfig{
+			{Kid: "test", Alg: "ES256", PrivateKeyEnv: "WEBOOK_TEST_ACCESS_KEY"},
+		},
+	})
+	require.NoError(t, err)
+	tokenStr, err := kp.Sign(testClaims())
+	require.NoError(t, err)
+	_, err = jwt.ParseWithClaims(tokenStr, &UserClaims{}, kp.Keyfunc)
+	assert.NoError(t, err)
+
+	// 环境变量没有设置
+	_, err = NewKeyProvider(KeySetConfig{
+		SigningKid: "test",
+		Keys: []KeyConfig{
+			{Kid: "test", Alg: "ES256", PrivateKeyEnv: "WEBOOK_TEST_NOT_EXIST"},
+		},
+	})
+	assert.Error(t, err)
+
+	// 文件和环境变量只能配一个
+	_, err = NewKeyProvider(KeySetConfig{
+		SigningKid: "test",
+		Keys: []KeyConfig{
+			{Kid: "test", Alg: "ES256",
+				PrivateKey:    "testdata/es256-test.pem",
+				PrivateKeyEnv: "WEBOOK_TEST_ACCESS_KEY"},
+		},
+	})
+	assert.Error(t, err)
+}
+
+func TestStaticKeyProvider_AlgMismatch(t *testing.T) {
+	dir := t.TempDir()
+	kp, err := NewKeyProvider(KeySetConfig{
+		SigningKid: "k1",
+		Keys: []KeyConfig{
+			{Kid: "k1", Alg: "ES256", PrivateKey: writeECKey(t, dir, "k1.pem")},
+		},
+	})
+	require.NoError(t, err)
+	// 伪造一个 HS512 的 token，但是 kid 指向 ES256 的 key
+	token := jwt.NewWithClaims(jwt.SigningMethodHS512, testClaims())
+	token.Header["kid"] = "k1"
+	tokenStr, err := token.SignedString([]byte("whatever"))
+	require.NoError(t, err)
+	_, err = jwt.ParseWithClaims(tokenStr, &UserClaims{}, kp.Keyfunc)
+	assert.Error(t, err)
+}
+
+func TestNewKeyProvider_MissingSigningKey(t *testing.T) {
+	_, err := NewKeyProvider(KeySetConfig{
+		SigningKid: "not-exist",
+		Keys: []KeyConfig{
+			{Kid: "k1", Alg: "HS512", Secret: "secret"},
+		},
+	})
+	assert.Error(t, err)
+}
+
+func testClaims() UserClaims {
+	return UserClaims{
+		Uid: 123,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+}
+
+func writeECKey(t *testing.T, dir, name string) string {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return writePKCS8(t, dir, name, priv)
+}
+
+func writeRSAKey(t *testing.T, dir, name string) string {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return writePKCS8(t, dir, name, priv)
+}
+
+func writePKCS8(t *testing.T, dir, name string, priv any) string {
+	data, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	path := filepath.Join(dir, name)
+	err = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: data,
+	}), 0600)
+	require.NoError(t, err)
+	return path
+}
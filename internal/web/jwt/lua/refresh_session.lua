@@ -0,0 +1,16 @@
+-- 会话的 key，是一个 hash
+local key = KEYS[1]
+local ssid = ARGV[1]
+-- 最近的刷新时间，毫秒数
+local refreshTime = tonumber(ARGV[2])
+
+local val = redis.call("hget", key, ssid)
+if not val then
+    -- 会话已经被踢掉了，或者过期了
+    return -1
+end
+
+local sess = cjson.decode(val)
+sess["RefreshTime"] = refreshTime
+redis.call("hset", key, ssid, cjson.encode(sess))
+return 0
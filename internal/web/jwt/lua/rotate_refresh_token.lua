@@ -0,0 +1,33 @@
+-- token family 的 key
+local key = KEYS[1]
+-- 宽限期内记录上一个 token 的 id
+local prevKey = KEYS[2]
+-- 前端带过来的长 token 的 id
+local oldJti = ARGV[1]
+-- 新的长 token 的 id
+local newJti = ARGV[2]
+-- 宽限期，毫秒数，0 表示没有宽限期
+local grace = tonumber(ARGV[3])
+
+local cur = redis.call("get", key)
+if not cur then
+    -- family 不存在，要么过期了，要么已经被吊销了
+    return -2
+end
+
+if cur ~= oldJti then
+    if grace > 0 and redis.call("get", prevKey) == oldJti then
+        -- 刚刚被别的请求轮换掉了，多半是同一个设备并发刷新，不算重放
+        return -3
+    end
+    -- 已经轮换过的 token 又被用了一次，很可能被盗了，整个 family 作废
+    redis.call("del", key, prevKey)
+    return -1
+end
+
+-- 过期时间保持不变，轮换不会延长登录有效期
+redis.call("set", key, newJti, "KEEPTTL")
+if grace > 0 then
+    redis.call("set", prevKey, oldJti, "PX", grace)
+end
+return 0
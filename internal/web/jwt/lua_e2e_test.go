@@ -0,0 +1,169 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// 这里的测试直接在 Redis 上跑 lua 脚本，本地没有启动 Redis 的时候跳过
+func newE2ERedis(t *testing.T) redis.Cmdable {
+	rdb := redis.NewClient(&redis.Options{
+		Addr: "localhost:6379",
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Skipf("没有可用的 Redis %v", err)
+	}
+	return rdb
+}
+
+func TestRedisRefreshTokenStore_Rotate_e2e(t *testing.T) {
+	rdb := newE2ERedis(t)
+	const family = "e2e-family"
+	key := "users:rt_family:" + family
+	prevKey := key + ":prev"
+	testCases := []struct {
+		name  string
+		grace time.Duration
+
+		before func(t *testing.T)
+		after  func(t *testing.T)
+
+		oldJti string
+		newJti string
+
+		wantErr error
+	}{
+		{
+			name:  "轮换成功，过期时间不变",
+			grace: time.Second * 10,
+			before: func(t *testing.T) {
+				err := rdb.Set(context.Background(), key, "jti1", time.Hour).Err()
+				require.NoError(t, err)
+			},
+			after: func(t *testing.T) {
+				ctx := context.Background()
+				val, err := rdb.Get(ctx, key).Result()
+				require.NoError(t, err)
+				assert.Equal(t, "jti2", val)
+				ttl, err := rdb.TTL(ctx, key).Result()
+				require.NoError(t, err)
+				assert.True(t, ttl > time.Minute*59)
+				// 宽限期内记住上一个 token
+				val, err = rdb.Get(ctx, prevKey).Result()
+				require.NoError(t, err)
+				assert.Equal(t, "jti1", val)
+				ttl, err = rdb.PTTL(ctx, prevKey).Result()
+				require.NoError(t, err)
+				assert.True(t, ttl <= time.Second*10)
+			},
+			oldJti: "jti1",
+			newJti: "jti2",
+		},
+		{
+			name:  "宽限期内重复使用上一个 token，不吊销",
+			grace: time.Second * 10,
+			before: func(t *testing.T) {
+				ctx := context.Background()
+				require.NoError(t, rdb.Set(ctx, key, "jti2", time.Hour).Err())
+				require.NoError(t, rdb.Set(ctx, prevKey, "jti1", time.Second*10).Err())
+			},
+			after: func(t *testing.T) {
+				val, err := rdb.Get(context.Background(), key).Result()
+				require.NoError(t, err)
+				assert.Equal(t, "jti2", val)
+			},
+			oldJti:  "jti1",
+			newJti:  "jti3",
+			wantErr: ErrRefreshTokenRotated,
+		},
+		{
+			name:  "重复使用更早的 token，吊销整个 family",
+			grace: time.Second * 10,
+			before: func(t *testing.T) {
+				ctx := context.Background()
+				require.NoError(t, rdb.Set(ctx, key, "jti3", time.Hour).Err())
+				require.NoError(t, rdb.Set(ctx, prevKey, "jti2", time.Second*10).Err())
+			},
+			after: func(t *testing.T) {
+				cnt, err := rdb.Exists(context.Background(), key, prevKey).Result()
+				require.NoError(t, err)
+				assert.Equal(t, int64(0), cnt)
+			},
+			oldJti:  "jti1",
+			newJti:  "jti4",
+			wantErr: ErrRefreshTokenReused,
+		},
+		{
+			name: "没有宽限期，重复使用上一个 token 也吊销",
+			before: func(t *testing.T) {
+				ctx := context.Background()
+				require.NoError(t, rdb.Set(ctx, key, "jti2", time.Hour).Err())
+				require.NoError(t, rdb.Set(ctx, prevKey, "jti1", time.Second*10).Err())
+			},
+			after: func(t *testing.T) {
+				cnt, err := rdb.Exists(context.Background(), key, prevKey).Result()
+				require.NoError(t, err)
+				assert.Equal(t, int64(0), cnt)
+			},
+			oldJti:  "jti1",
+			newJti:  "jti3",
+			wantErr: ErrRefreshTokenReused,
+		},
+		{
+			name:   "family 不存在",
+			grace:  time.Second * 10,
+			before: func(t *testing.T) {},
+			after: func(t *testing.T) {
+				cnt, err := rdb.Exists(context.Background(), key).Result()
+				require.NoError(t, err)
+				assert.Equal(t, int64(0), cnt)
+			},
+			oldJti:  "jti1",
+			newJti:  "jti2",
+			wantErr: ErrTokenFamilyNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer rdb.Del(context.Background(), key, prevKey)
+			tc.before(t)
+			s := NewRedisRefreshTokenStore(rdb, tc.grace)
+			err := s.Rotate(context.Background(), family, tc.oldJti, tc.newJti)
+			assert.Equal(t, tc.wantErr, err)
+			tc.after(t)
+		})
+	}
+}
+
+func TestRedisJWTHandler_RefreshSession_e2e(t *testing.T) {
+	rdb := newE2ERedis(t)
+	ctx := context.Background()
+	key := "users:sessions:123"
+	defer rdb.Del(ctx, key)
+	val, err := json.Marshal(session{Ssid: "a", UserAgent: "chrome", LoginTime: 1, RefreshTime: 1})
+	require.NoError(t, err)
+	require.NoError(t, rdb.HSet(ctx, key, "a", val).Err())
+
+	h := &RedisJWTHandler{client: rdb}
+	err = h.RefreshSession(&gin.Context{}, 123, "a")
+	require.NoError(t, err)
+	res, err := rdb.HGet(ctx, key, "a").Result()
+	require.NoError(t, err)
+	var s session
+	require.NoError(t, json.Unmarshal([]byte(res), &s))
+	// 只更新刷新时间，别的字段保持不变
+	assert.Equal(t, "chrome", s.UserAgent)
+	assert.Equal(t, int64(1), s.LoginTime)
+	assert.True(t, s.RefreshTime > 1)
+
+	err = h.RefreshSession(&gin.Context{}, 123, "b")
+	assert.Equal(t, ErrSessionNotFound, err)
+}
@@ -0,0 +1,41 @@
+package jwt
+
+import (
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"time"
+)
+
+const purposeMFAPending = "mfa_pending"
+
+// MFAClaims 密码校验通过了，但是还需要二次验证。
+// 这个 token 不能用来访问别的接口，只能用来换真正的登录 token
+type MFAClaims struct {
+	jwt.RegisteredClaims
+	Uid     int64
+	Purpose string
+}
+
+// SignMFAPendingToken 短期的一次性 token 和 OAuth2 的 state 一样，用 State 的 key 签名
+func (h *RedisJWTHandler) SignMFAPendingToken(uid int64) (string, error) {
+	return h.keys.State.Sign(MFAClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 5)),
+		},
+		Uid:     uid,
+		Purpose: purposeMFAPending,
+	})
+}
+
+func (h *RedisJWTHandler) ParseMFAPendingToken(tokenStr string) (MFAClaims, error) {
+	var mc MFAClaims
+	err := h.parse(tokenStr, &mc, h.keys.State)
+	if err != nil {
+		return MFAClaims{}, err
+	}
+	// 防止拿别的用 State key 签名的 token 来冒充
+	if mc.Purpose != purposeMFAPending || mc.Uid <= 0 {
+		return MFAClaims{}, errors.New("不是二次验证的 token")
+	}
+	return mc, nil
+}
@@ -0,0 +1,49 @@
+package jwt
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestRedisJWTHandler_MFAPendingToken(t *testing.T) {
+	state, err := NewKeyProvider(KeySetConfig{
+		SigningKid: "state",
+		Keys: []KeyConfig{
+			{Kid: "state", Alg: "HS512", Secret: "state-secret"},
+		},
+	})
+	require.NoError(t, err)
+	h := &RedisJWTHandler{keys: Keys{State: state}}
+
+	tokenStr, err := h.SignMFAPendingToken(123)
+	require.NoError(t, err)
+	mc, err := h.ParseMFAPendingToken(tokenStr)
+	require.NoError(t, err)
+	assert.Equal(t, int64(123), mc.Uid)
+
+	// 同一个 key 签名的别的 token，不能拿来冒充
+	other, err := state.Sign(MFAClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+		Uid: 123,
+	})
+	require.NoError(t, err)
+	_, err = h.ParseMFAPendingToken(other)
+	assert.Error(t, err)
+
+	// 过期了
+	expired, err := state.Sign(MFAClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+		},
+		Uid:     123,
+		Purpose: purposeMFAPending,
+	})
+	require.NoError(t, err)
+	_, err = h.ParseMFAPendingToken(expired)
+	assert.Error(t, err)
+}
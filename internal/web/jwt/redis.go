@@ -1,6 +1,8 @@
 package jwt
 
 import (
+	"ddd_demo/pkg/jwtx"
+	"ddd_demo/pkg/logger"
 	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
@@ -11,21 +13,29 @@ import (
 	"time"
 )
 
+// refreshTokenReuseGrace 长 token 轮换之后，老的 token 在这段时间内再被用一次不会吊销整个 family。
+// 同一个设备上多个页面同时刷新的时候，只有一个能成功，别的拿到 401 之后改用新的长 token 就可以
+const refreshTokenReuseGrace = time.Second * 10
+
 type RedisJWTHandler struct {
-	client        redis.Cmdable
-	signingMethod jwt.SigningMethod
-	rcExpiration  time.Duration
+	client       redis.Cmdable
+	rtStore      RefreshTokenStore
+	l            logger.LoggerV1
+	keys         Keys
+	rcExpiration time.Duration
 }
 
-func NewRedisJWTHandler(client redis.Cmdable) Handler {
+func NewRedisJWTHandler(client redis.Cmdable, l logger.LoggerV1, keys Keys) Handler {
 	return &RedisJWTHandler{
-		client:        client,
-		signingMethod: jwt.SigningMethodHS512,
-		rcExpiration:  time.Hour * 24 * 7,
+		client:       client,
+		rtStore:      NewRedisRefreshTokenStore(client, refreshTokenReuseGrace),
+		l:            l,
+		keys:         keys,
+		rcExpiration: time.Hour * 24 * 7,
 	}
 }
 
-func (h *RedisJWTHandler) CheckSession(ctx *gin.Context, ssid string) error {
+func (h *RedisJWTHandler) CheckSession(ctx *gin.Context, uid int64, ssid string) error {
 	cnt, err := h.client.Exists(ctx, fmt.Sprintf("users:ssid:%s", ssid)).Result()
 	if err != nil {
 		return err
@@ -33,6 +43,14 @@ func (h *RedisJWTHandler) CheckSession(ctx *gin.Context, ssid string) error {
 	if cnt > 0 {
 		return errors.New("token 无效")
 	}
+	// 被踢下线，或者在别的地方点了"退出所有设备"
+	ok, err := h.existSession(ctx, uid, ssid)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrSessionNotFound
+	}
 	return nil
 }
 
@@ -54,21 +72,71 @@ var _ Handler = &RedisJWTHandler{}
 
 func (h *RedisJWTHandler) SetLoginToken(ctx *gin.Context, uid int64) error {
 	ssid := uuid.New().String()
-	err := h.setRefreshToken(ctx, uid, ssid)
+	err := h.addSession(ctx, uid, ssid)
+	if err != nil {
+		return err
+	}
+	// 一次登录就是一个 token family，直接用 ssid 作为 family
+	jti := uuid.New().String()
+	err = h.rtStore.Create(ctx, ssid, jti, h.rcExpiration)
+	if err != nil {
+		return err
+	}
+	err = h.setRefreshToken(ctx, uid, ssid, jti, time.Now().Add(h.rcExpiration))
 	if err != nil {
 		return err
 	}
 	return h.SetJWTToken(ctx, uid, ssid)
 }
 
+// RotateRefreshToken 换一个新的长 token，老的长 token 立刻失效
+// 新的长 token 的过期时间和老的一致，也就是说轮换不会延长登录的有效期
+func (h *RedisJWTHandler) RotateRefreshToken(ctx *gin.Context, rc RefreshClaims) error {
+	if rc.ExpiresAt == nil {
+		return errors.New("长 token 没有过期时间")
+	}
+	jti := uuid.New().String()
+	err := h.rtStore.Rotate(ctx, rc.Ssid, rc.ID, jti)
+	switch err {
+	case nil:
+		return h.setRefreshToken(ctx, rc.Uid, rc.Ssid, jti, rc.ExpiresAt.Time)
+	case ErrRefreshTokenReused:
+		// 大概率是 token 被盗了，把这个会话也踢下线
+		h.l.Warn("长 token 被重复使用，吊销整个 token family",
+			logger.Int64("uid", rc.Uid),
+			logger.String("ssid", rc.Ssid),
+			logger.String("jti", rc.ID),
+			logger.String("ip", ctx.ClientIP()),
+			logger.String("userAgent", ctx.GetHeader("User-Agent")))
+		er := h.DeleteSession(ctx, rc.Uid, rc.Ssid)
+		if er != nil && er != ErrSessionNotFound {
+			h.l.Error("吊销会话失败",
+				logger.Int64("uid", rc.Uid),
+				logger.String("ssid", rc.Ssid),
+				logger.Error(er))
+		}
+		return err
+	default:
+		return err
+	}
+}
+
 func (h *RedisJWTHandler) ClearToken(ctx *gin.Context) error {
 	ctx.Header("x-jwt-token", "")
 	ctx.Header("x-refresh-token", "")
 	uc := ctx.MustGet("user").(UserClaims)
 
-	return h.client.Set(ctx,
+	err := h.client.Set(ctx,
 		fmt.Sprintf("users:ssid:%s", uc.Ssid),
 		"", h.rcExpiration).Err()
+	if err != nil {
+		return err
+	}
+	err = h.DeleteSession(ctx, uc.Uid, uc.Ssid)
+	if err == ErrSessionNotFound {
+		return nil
+	}
+	return err
 }
 
 func (h *RedisJWTHandler) SetJWTToken(ctx *gin.Context, uid int64, ssid string) error {
@@ -81,8 +149,7 @@ func (h *RedisJWTHandler) SetJWTToken(ctx *gin.Context, uid int64, ssid string)
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 30)),
 		},
 	}
-	token := jwt.NewWithClaims(h.signingMethod, uc)
-	tokenStr, err := token.SignedString(JWTKey)
+	tokenStr, err := h.keys.Access.Sign(uc)
 	if err != nil {
 		return err
 	}
@@ -90,16 +157,17 @@ func (h *RedisJWTHandler) SetJWTToken(ctx *gin.Context, uid int64, ssid string)
 	return nil
 }
 
-func (h *RedisJWTHandler) setRefreshToken(ctx *gin.Context, uid int64, ssid string) error {
+func (h *RedisJWTHandler) setRefreshToken(ctx *gin.Context, uid int64,
+	ssid string, jti string, expiresAt time.Time) error {
 	rc := RefreshClaims{
 		Uid:  uid,
 		Ssid: ssid,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(h.rcExpiration)),
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 		},
 	}
-	token := jwt.NewWithClaims(h.signingMethod, rc)
-	tokenStr, err := token.SignedString(RCJWTKey)
+	tokenStr, err := h.keys.Refresh.Sign(rc)
 	if err != nil {
 		return err
 	}
@@ -107,8 +175,32 @@ func (h *RedisJWTHandler) setRefreshToken(ctx *gin.Context, uid int64, ssid stri
 	return nil
 }
 
-var JWTKey = []byte("k6CswdUm77WKcbM68UQUuxVsHSpTCwgK")
-var RCJWTKey = []byte("k6CswdUm77WKcbM68UQUuxVsHSpTCwgA")
+func (h *RedisJWTHandler) ParseAccessToken(tokenStr string) (UserClaims, error) {
+	var uc UserClaims
+	err := h.parse(tokenStr, &uc, h.keys.Access)
+	return uc, err
+}
+
+func (h *RedisJWTHandler) ParseRefreshToken(tokenStr string) (RefreshClaims, error) {
+	var rc RefreshClaims
+	err := h.parse(tokenStr, &rc, h.keys.Refresh)
+	return rc, err
+}
+
+func (h *RedisJWTHandler) JWKS() jwtx.JWKSet {
+	return h.keys.Access.JWKS()
+}
+
+func (h *RedisJWTHandler) parse(tokenStr string, claims jwt.Claims, kp KeyProvider) error {
+	token, err := jwt.ParseWithClaims(tokenStr, claims, kp.Keyfunc)
+	if err != nil {
+		return err
+	}
+	if token == nil || !token.Valid {
+		return errors.New("token 无效")
+	}
+	return nil
+}
 
 type RefreshClaims struct {
 	jwt.RegisteredClaims
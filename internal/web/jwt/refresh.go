@@ -0,0 +1,85 @@
+package jwt
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+var (
+	//go:embed lua/rotate_refresh_token.lua
+	luaRotateRefreshToken string
+
+	ErrRefreshTokenReused  = errors.New("长 token 被重复使用")
+	ErrTokenFamilyNotFound = errors.New("token family 不存在或者已经被吊销")
+	// ErrRefreshTokenRotated 长 token 刚刚被轮换过，还在宽限期里面，
+	// 一般是同一个设备上的多个页面同时刷新，family 不会被吊销
+	ErrRefreshTokenRotated = errors.New("长 token 刚刚被轮换过")
+)
+
+// RefreshTokenStore 管理长 token 的轮换
+// 同一次登录签发的所有长 token 属于同一个 family，family 里面只有最新的那个 token 是有效的。
+// 任何 Handler 的实现都可以组合它来实现长 token 的轮换。
+type RefreshTokenStore interface {
+	// Create 登录的时候创建一个 family
+	Create(ctx context.Context, family, jti string, expiration time.Duration) error
+	// Rotate 用 newJti 替换掉 oldJti。
+	// 如果 oldJti 已经被轮换过了，说明发生了重放，整个 family 都会被吊销，并返回 ErrRefreshTokenReused。
+	// 例外是 oldJti 刚好是上一个 token，并且轮换发生在宽限期内，这时候只返回 ErrRefreshTokenRotated，不吊销
+	Rotate(ctx context.Context, family, oldJti, newJti string) error
+	// Revoke 吊销整个 family，family 不存在也不会返回 error
+	Revoke(ctx context.Context, family string) error
+}
+
+type RedisRefreshTokenStore struct {
+	client redis.Cmdable
+	// 轮换之后，上一个 token 在这段时间内再被用一次不算重放。
+	// 0 表示没有宽限期，任何重复使用都会吊销整个 family
+	grace time.Duration
+}
+
+func NewRedisRefreshTokenStore(client redis.Cmdable, grace time.Duration) RefreshTokenStore {
+	return &RedisRefreshTokenStore{
+		client: client,
+		grace:  grace,
+	}
+}
+
+func (s *RedisRefreshTokenStore) Create(ctx context.Context, family, jti string, expiration time.Duration) error {
+	return s.client.Set(ctx, s.key(family), jti, expiration).Err()
+}
+
+func (s *RedisRefreshTokenStore) Rotate(ctx context.Context, family, oldJti, newJti string) error {
+	res, err := s.client.Eval(ctx, luaRotateRefreshToken,
+		[]string{s.key(family), s.prevKey(family)},
+		oldJti, newJti, s.grace.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	switch res {
+	case -1:
+		return ErrRefreshTokenReused
+	case -2:
+		return ErrTokenFamilyNotFound
+	case -3:
+		return ErrRefreshTokenRotated
+	default:
+		return nil
+	}
+}
+
+func (s *RedisRefreshTokenStore) Revoke(ctx context.Context, family string) error {
+	return s.client.Del(ctx, s.key(family), s.prevKey(family)).Err()
+}
+
+func (s *RedisRefreshTokenStore) key(family string) string {
+	return fmt.Sprintf("users:rt_family:%s", family)
+}
+
+// prevKey 宽限期内记录上一个 token 的 id
+func (s *RedisRefreshTokenStore) prevKey(family string) string {
+	return fmt.Sprintf("users:rt_family:%s:prev", family)
+}
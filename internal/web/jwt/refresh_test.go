@@ -0,0 +1,102 @@
+package jwt
+
+import (
+	"context"
+	"ddd_demo/internal/repository/cache/redismocks"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestRedisRefreshTokenStore_Rotate(t *testing.T) {
+	keys := []string{"users:rt_family:ssid", "users:rt_family:ssid:prev"}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) redis.Cmdable
+
+		wantErr error
+	}{
+		{
+			name: "轮换成功",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				client := redismocks.NewMockCmdable(ctrl)
+				cmd := redis.NewCmd(context.Background())
+				cmd.SetVal(int64(0))
+				client.EXPECT().Eval(gomock.Any(), luaRotateRefreshToken, keys,
+					"old", "new", int64(10000)).Return(cmd)
+				return client
+			},
+		},
+		{
+			name: "重放",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				client := redismocks.NewMockCmdable(ctrl)
+				cmd := redis.NewCmd(context.Background())
+				cmd.SetVal(int64(-1))
+				client.EXPECT().Eval(gomock.Any(), luaRotateRefreshToken, keys,
+					"old", "new", int64(10000)).Return(cmd)
+				return client
+			},
+			wantErr: ErrRefreshTokenReused,
+		},
+		{
+			name: "family 已经被吊销",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				client := redismocks.NewMockCmdable(ctrl)
+				cmd := redis.NewCmd(context.Background())
+				cmd.SetVal(int64(-2))
+				client.EXPECT().Eval(gomock.Any(), luaRotateRefreshToken, keys,
+					"old", "new", int64(10000)).Return(cmd)
+				return client
+			},
+			wantErr: ErrTokenFamilyNotFound,
+		},
+		{
+			name: "宽限期内并发刷新",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				client := redismocks.NewMockCmdable(ctrl)
+				cmd := redis.NewCmd(context.Background())
+				cmd.SetVal(int64(-3))
+				client.EXPECT().Eval(gomock.Any(), luaRotateRefreshToken, keys,
+					"old", "new", int64(10000)).Return(cmd)
+				return client
+			},
+			wantErr: ErrRefreshTokenRotated,
+		},
+		{
+			name: "redis 错误",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				client := redismocks.NewMockCmdable(ctrl)
+				cmd := redis.NewCmd(context.Background())
+				cmd.SetErr(errors.New("redis 错误"))
+				client.EXPECT().Eval(gomock.Any(), luaRotateRefreshToken, keys,
+					"old", "new", int64(10000)).Return(cmd)
+				return client
+			},
+			wantErr: errors.New("redis 错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := NewRedisRefreshTokenStore(tc.mock(ctrl), time.Second*10)
+			err := s.Rotate(context.Background(), "ssid", "old", "new")
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestRedisRefreshTokenStore_Revoke(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := redismocks.NewMockCmdable(ctrl)
+	// 宽限期里面的上一个 token 也要一起删掉
+	client.EXPECT().Del(gomock.Any(), "users:rt_family:ssid", "users:rt_family:ssid:prev").
+		Return(redis.NewIntResult(1, nil))
+	s := NewRedisRefreshTokenStore(client, time.Second*10)
+	assert.NoError(t, s.Revoke(context.Background(), "ssid"))
+}
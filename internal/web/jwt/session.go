@@ -0,0 +1,140 @@
+package jwt
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"time"
+)
+
+var (
+	//go:embed lua/refresh_session.lua
+	luaRefreshSession string
+
+	ErrSessionNotFound = errors.New("会话不存在或者已经被踢下线")
+)
+
+// session 是 Session 在 Redis 里面的存储格式
+type session struct {
+	Ssid        string
+	UserAgent   string
+	IP          string
+	LoginTime   int64
+	RefreshTime int64
+}
+
+func (h *RedisJWTHandler) ListSessions(ctx context.Context, uid int64) ([]Session, error) {
+	key := h.sessionKey(uid)
+	vals, err := h.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	res := make([]Session, 0, len(vals))
+	expired := make([]string, 0, len(vals))
+	for ssid, val := range vals {
+		var s session
+		if err = json.Unmarshal([]byte(val), &s); err != nil {
+			return nil, err
+		}
+		sess := h.toSession(s)
+		// 长 token 已经过期了，这个会话不可能再用了
+		if sess.LoginTime.Add(h.rcExpiration).Before(now) {
+			expired = append(expired, ssid)
+			continue
+		}
+		res = append(res, sess)
+	}
+	if len(expired) > 0 {
+		// 顺手清理掉，清理失败也不影响
+		_ = h.client.HDel(ctx, key, expired...).Err()
+	}
+	return res, nil
+}
+
+func (h *RedisJWTHandler) DeleteSession(ctx context.Context, uid int64, ssid string) error {
+	cnt, err := h.client.HDel(ctx, h.sessionKey(uid), ssid).Result()
+	if err != nil {
+		return err
+	}
+	// 会话不存在也要吊销，防止上一次删了会话但是吊销失败了
+	err = h.rtStore.Revoke(ctx, ssid)
+	if err != nil {
+		return err
+	}
+	if cnt == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+func (h *RedisJWTHandler) ClearSessions(ctx context.Context, uid int64) error {
+	key := h.sessionKey(uid)
+	ssids, err := h.client.HKeys(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	// 先吊销长 token，中间失败了会话还在，重试一遍就可以
+	for _, ssid := range ssids {
+		err = h.rtStore.Revoke(ctx, ssid)
+		if err != nil {
+			return err
+		}
+	}
+	return h.client.Del(ctx, key).Err()
+}
+
+func (h *RedisJWTHandler) RefreshSession(ctx *gin.Context, uid int64, ssid string) error {
+	res, err := h.client.Eval(ctx, luaRefreshSession,
+		[]string{h.sessionKey(uid)}, ssid, time.Now().UnixMilli()).Int()
+	if err != nil {
+		return err
+	}
+	if res == -1 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// addSession 登录的时候记录会话
+func (h *RedisJWTHandler) addSession(ctx *gin.Context, uid int64, ssid string) error {
+	now := time.Now().UnixMilli()
+	val, err := json.Marshal(session{
+		Ssid:        ssid,
+		UserAgent:   ctx.GetHeader("User-Agent"),
+		IP:          ctx.ClientIP(),
+		LoginTime:   now,
+		RefreshTime: now,
+	})
+	if err != nil {
+		return err
+	}
+	key := h.sessionKey(uid)
+	pipe := h.client.TxPipeline()
+	pipe.HSet(ctx, key, ssid, val)
+	// 最新的一个会话过期了，整个 key 也就没用了
+	pipe.Expire(ctx, key, h.rcExpiration)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (h *RedisJWTHandler) existSession(ctx context.Context, uid int64, ssid string) (bool, error) {
+	return h.client.HExists(ctx, h.sessionKey(uid), ssid).Result()
+}
+
+func (h *RedisJWTHandler) toSession(s session) Session {
+	return Session{
+		Ssid:        s.Ssid,
+		UserAgent:   s.UserAgent,
+		IP:          s.IP,
+		LoginTime:   time.UnixMilli(s.LoginTime),
+		RefreshTime: time.UnixMilli(s.RefreshTime),
+	}
+}
+
+func (h *RedisJWTHandler) sessionKey(uid int64) string {
+	return fmt.Sprintf("users:sessions:%d", uid)
+}
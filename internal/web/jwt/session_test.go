@@ -0,0 +1,248 @@
+package jwt
+
+import (
+	"context"
+	"ddd_demo/internal/repository/cache/redismocks"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRedisJWTHandler_DeleteSession(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) redis.Cmdable
+
+		wantErr error
+	}{
+		{
+			name: "踢下线，吊销长 token",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				client := redismocks.NewMockCmdable(ctrl)
+				gomock.InOrder(
+					client.EXPECT().HDel(gomock.Any(), "users:sessions:123", "ssid").
+						Return(redis.NewIntResult(1, nil)),
+					client.EXPECT().Del(gomock.Any(),
+						"users:rt_family:ssid", "users:rt_family:ssid:prev").
+						Return(redis.NewIntResult(1, nil)),
+				)
+				return client
+			},
+		},
+		{
+			name: "会话不存在，也要吊销长 token",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				client := redismocks.NewMockCmdable(ctrl)
+				client.EXPECT().HDel(gomock.Any(), "users:sessions:123", "ssid").
+					Return(redis.NewIntResult(0, nil))
+				client.EXPECT().Del(gomock.Any(),
+					"users:rt_family:ssid", "users:rt_family:ssid:prev").
+					Return(redis.NewIntResult(0, nil))
+				return client
+			},
+			wantErr: ErrSessionNotFound,
+		},
+		{
+			name: "吊销失败",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				client := redismocks.NewMockCmdable(ctrl)
+				client.EXPECT().HDel(gomock.Any(), "users:sessions:123", "ssid").
+					Return(redis.NewIntResult(1, nil))
+				client.EXPECT().Del(gomock.Any(),
+					"users:rt_family:ssid", "users:rt_family:ssid:prev").
+					Return(redis.NewIntResult(0, errors.New("redis 错误")))
+				return client
+			},
+			wantErr: errors.New("redis 错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			client := tc.mock(ctrl)
+			h := &RedisJWTHandler{
+				client:  client,
+				rtStore: NewRedisRefreshTokenStore(client, time.Second*10),
+			}
+			err := h.DeleteSession(context.Background(), 123, "ssid")
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestRedisJWTHandler_ClearSessions(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) redis.Cmdable
+
+		wantErr error
+	}{
+		{
+			name: "所有设备退出，吊销所有长 token",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				client := redismocks.NewMockCmdable(ctrl)
+				gomock.InOrder(
+					client.EXPECT().HKeys(gomock.Any(), "users:sessions:123").
+						Return(redis.NewStringSliceResult([]string{"a", "b"}, nil)),
+					client.EXPECT().Del(gomock.Any(),
+						"users:rt_family:a", "users:rt_family:a:prev").
+						Return(redis.NewIntResult(1, nil)),
+					client.EXPECT().Del(gomock.Any(),
+						"users:rt_family:b", "users:rt_family:b:prev").
+						Return(redis.NewIntResult(1, nil)),
+					client.EXPECT().Del(gomock.Any(), "users:sessions:123").
+						Return(redis.NewIntResult(1, nil)),
+				)
+				return client
+			},
+		},
+		{
+			name: "吊销失败，会话保留下来可以重试",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				client := redismocks.NewMockCmdable(ctrl)
+				client.EXPECT().HKeys(gomock.Any(), "users:sessions:123").
+					Return(redis.NewStringSliceResult([]string{"a"}, nil))
+				client.EXPECT().Del(gomock.Any(),
+					"users:rt_family:a", "users:rt_family:a:prev").
+					Return(redis.NewIntResult(0, errors.New("redis 错误")))
+				return client
+			},
+			wantErr: errors.New("redis 错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			client := tc.mock(ctrl)
+			h := &RedisJWTHandler{
+				client:  client,
+				rtStore: NewRedisRefreshTokenStore(client, time.Second*10),
+			}
+			err := h.ClearSessions(context.Background(), 123)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestRedisJWTHandler_ListSessions(t *testing.T) {
+	now := time.Now()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := redismocks.NewMockCmdable(ctrl)
+	active := `{"Ssid":"a","UserAgent":"chrome","IP":"127.0.0.1","LoginTime":` +
+		strconv.FormatInt(now.Add(-time.Hour).UnixMilli(), 10) +
+		`,"RefreshTime":` + strconv.FormatInt(now.UnixMilli(), 10) + `}`
+	expired := `{"Ssid":"b","LoginTime":` +
+		strconv.FormatInt(now.Add(-time.Hour*24*8).UnixMilli(), 10) + `}`
+	client.EXPECT().HGetAll(gomock.Any(), "users:sessions:123").
+		Return(redis.NewMapStringStringResult(map[string]string{
+			"a": active,
+			"b": expired,
+		}, nil))
+	// 长 token 已经过期的会话顺手清理掉
+	client.EXPECT().HDel(gomock.Any(), "users:sessions:123", "b").
+		Return(redis.NewIntResult(1, nil))
+
+	h := &RedisJWTHandler{client: client, rcExpiration: time.Hour * 24 * 7}
+	sessions, err := h.ListSessions(context.Background(), 123)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "a", sessions[0].Ssid)
+	assert.Equal(t, "chrome", sessions[0].UserAgent)
+	assert.Equal(t, "127.0.0.1", sessions[0].IP)
+	assert.Equal(t, now.Add(-time.Hour).UnixMilli(), sessions[0].LoginTime.UnixMilli())
+	assert.Equal(t, now.UnixMilli(), sessions[0].RefreshTime.UnixMilli())
+}
+
+func TestRedisJWTHandler_RefreshSession(t *testing.T) {
+	testCases := []struct {
+		name string
+		res  int64
+
+		wantErr error
+	}{
+		{
+			name: "刷新成功",
+			res:  0,
+		},
+		{
+			name:    "已经被踢下线",
+			res:     -1,
+			wantErr: ErrSessionNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			client := redismocks.NewMockCmdable(ctrl)
+			cmd := redis.NewCmd(context.Background())
+			cmd.SetVal(tc.res)
+			client.EXPECT().Eval(gomock.Any(), luaRefreshSession,
+				[]string{"users:sessions:123"}, "ssid", gomock.Any()).Return(cmd)
+			h := &RedisJWTHandler{client: client}
+			err := h.RefreshSession(&gin.Context{}, 123, "ssid")
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestRedisJWTHandler_CheckSession(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) redis.Cmdable
+
+		wantErr error
+	}{
+		{
+			name: "会话有效",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				client := redismocks.NewMockCmdable(ctrl)
+				client.EXPECT().Exists(gomock.Any(), "users:ssid:ssid").
+					Return(redis.NewIntResult(0, nil))
+				client.EXPECT().HExists(gomock.Any(), "users:sessions:123", "ssid").
+					Return(redis.NewBoolResult(true, nil))
+				return client
+			},
+		},
+		{
+			name: "被踢下线",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				client := redismocks.NewMockCmdable(ctrl)
+				client.EXPECT().Exists(gomock.Any(), "users:ssid:ssid").
+					Return(redis.NewIntResult(0, nil))
+				client.EXPECT().HExists(gomock.Any(), "users:sessions:123", "ssid").
+					Return(redis.NewBoolResult(false, nil))
+				return client
+			},
+			wantErr: ErrSessionNotFound,
+		},
+		{
+			name: "已经退出登录",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				client := redismocks.NewMockCmdable(ctrl)
+				client.EXPECT().Exists(gomock.Any(), "users:ssid:ssid").
+					Return(redis.NewIntResult(1, nil))
+				return client
+			},
+			wantErr: errors.New("token 无效"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			h := &RedisJWTHandler{client: tc.mock(ctrl)}
+			err := h.CheckSession(&gin.Context{}, 123, "ssid")
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
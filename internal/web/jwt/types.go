@@ -1,11 +1,53 @@
 package jwt
 
-import "github.com/gin-gonic/gin"
+import (
+	"context"
+	"ddd_demo/pkg/jwtx"
+	"github.com/gin-gonic/gin"
+	"time"
+)
 
 type Handler interface {
 	ClearToken(ctx *gin.Context) error
 	ExtractToken(ctx *gin.Context) string
 	SetLoginToken(ctx *gin.Context, uid int64) error
 	SetJWTToken(ctx *gin.Context, uid int64, ssid string) error
-	CheckSession(ctx *gin.Context, ssid string) error
+	// ParseAccessToken 校验短 token 的签名和过期时间
+	ParseAccessToken(tokenStr string) (UserClaims, error)
+	// ParseRefreshToken 校验长 token 的签名和过期时间
+	ParseRefreshToken(tokenStr string) (RefreshClaims, error)
+	// JWKS 校验短 token 用的公钥，给别的服务用
+	JWKS() jwtx.JWKSet
+	// RotateRefreshToken 轮换长 token，在响应头部里面放一个新的长 token。
+	// 如果 rc 已经被轮换过了，会吊销整个 token family，并且返回 ErrRefreshTokenReused。
+	// rc 是刚刚被轮换掉的那个 token 并且还在宽限期内的话，返回 ErrRefreshTokenRotated，会话保持有效
+	RotateRefreshToken(ctx *gin.Context, rc RefreshClaims) error
+	// CheckSession 校验 ssid 对应的会话是否还有效
+	// 退出登录、被踢下线的会话都会返回 error
+	CheckSession(ctx *gin.Context, uid int64, ssid string) error
+
+	// RefreshSession 刷新 access_token 的时候，记录一下最近的刷新时间
+	RefreshSession(ctx *gin.Context, uid int64, ssid string) error
+	// ListSessions 列出用户所有的登录会话（设备）
+	ListSessions(ctx context.Context, uid int64) ([]Session, error)
+	// DeleteSession 把某个设备踢下线，这个会话的长 token 也会被吊销
+	DeleteSession(ctx context.Context, uid int64, ssid string) error
+	// ClearSessions 所有设备都退出登录，所有的长 token 都会被吊销
+	ClearSessions(ctx context.Context, uid int64) error
+
+	// SignMFAPendingToken 身份校验通过，但是还要二次验证的时候，签发一个短期的 token
+	SignMFAPendingToken(uid int64) (string, error)
+	// ParseMFAPendingToken 校验二次验证的 token，过期了或者不是这个用途的 token 都会返回 error
+	ParseMFAPendingToken(tokenStr string) (MFAClaims, error)
+}
+
+// Session 一次登录对应的一个会话，也可以理解为一个设备
+type Session struct {
+	Ssid      string
+	UserAgent string
+	IP        string
+	// 登录时间
+	LoginTime time.Time
+	// 最近一次刷新 access_token 的时间
+	RefreshTime time.Time
 }
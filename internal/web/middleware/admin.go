@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	ijwt "ddd_demo/internal/web/jwt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// AdminMiddlewareBuilder 只允许管理员访问，要放在登录校验的后面
+type AdminMiddlewareBuilder struct {
+	uids map[int64]struct{}
+}
+
+func NewAdminMiddlewareBuilder(uids []int64) *AdminMiddlewareBuilder {
+	m := make(map[int64]struct{}, len(uids))
+	for _, uid := range uids {
+		m[uid] = struct{}{}
+	}
+	return &AdminMiddlewareBuilder{uids: m}
+}
+
+func (m *AdminMiddlewareBuilder) Build() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		val, ok := ctx.Get("user")
+		if !ok {
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		uc, ok := val.(ijwt.UserClaims)
+		if !ok {
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		if _, ok = m.uids[uc.Uid]; !ok {
+			ctx.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package web
+
+import (
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	"ddd_demo/internal/service/oauth2"
+	ijwt "ddd_demo/internal/web/jwt"
+	"ddd_demo/pkg/ginx"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	uuid "github.com/lithammer/shortuuid/v4"
+	"net/http"
+	"time"
+)
+
+// OAuth2Handler 通用的第三方登录，第三方在配置里面加，
+// 路由是 /oauth2/:provider/authurl 和 /oauth2/:provider/callback
+type OAuth2Handler struct {
+	providers *oauth2.Registry
+	userSvc   service.UserService
+	ijwt.Handler
+	stateKey        ijwt.KeyProvider
+	stateCookieName string
+}
+
+func NewOAuth2Handler(providers *oauth2.Registry,
+	hdl ijwt.Handler,
+	userSvc service.UserService,
+	keys ijwt.Keys) *OAuth2Handler {
+	return &OAuth2Handler{
+		providers:       providers,
+		userSvc:         userSvc,
+		stateKey:        keys.State,
+		stateCookieName: "jwt-state",
+		Handler:         hdl,
+	}
+}
+
+func (o *OAuth2Handler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/oauth2/:provider")
+	g.GET("/authurl", o.AuthURL)
+	g.Any("/callback", ginx.Wrap(o.Callback))
+	// 已经登录的用户绑定第三方账号，前端拿到回调里面的 code 和 state 之后调用
+	g.POST("/bind", ginx.WrapBodyAndClaims(o.Bind))
+}
+
+func (o *OAuth2Handler) AuthURL(ctx *gin.Context) {
+	p, err := o.providers.Get(ctx.Param("provider"))
+	if err != nil {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "不支持的登录方式",
+		})
+		return
+	}
+	state := uuid.New()
+	val, err := p.AuthURL(ctx, state)
+	if err != nil {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "构造跳转URL失败",
+		})
+		return
+	}
+	err = o.setStateCookie(ctx, p.Name(), state)
+	if err != nil {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "服务器异常",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, ginx.Result{
+		Data: val,
+	})
+}
+
+func (o *OAuth2Handler) Callback(ctx *gin.Context) (ginx.Result, error) {
+	info, err := o.verify(ctx, ctx.Query("code"), ctx.Query("state"))
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "非法请求",
+		}, err
+	}
+	u, err := o.userSvc.FindOrCreateByOAuth2(ctx, info)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	return login(ctx, o.Handler, o.userSvc, u.Id)
+}
+
+func (o *OAuth2Handler) Bind(ctx *gin.Context,
+	req OAuth2BindReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	info, err := o.verify(ctx, req.Code, req.State)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "非法请求",
+		}, err
+	}
+	err = o.userSvc.BindOAuth2(ctx, uc.Uid, info)
+	switch err {
+	case nil:
+		return ginx.Result{
+			Msg: "绑定成功",
+		}, nil
+	case service.ErrIdentityBound:
+		return ginx.Result{
+			Code: errs.UserIdentityBound,
+			Msg:  "该账号已经绑定了别的用户",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+// verify 校验 state，然后用授权码拿到第三方的用户信息
+func (o *OAuth2Handler) verify(ctx *gin.Context, code, state string) (domain.OAuth2Info, error) {
+	p, err := o.providers.Get(ctx.Param("provider"))
+	if err != nil {
+		return domain.OAuth2Info{}, err
+	}
+	err = o.verifyState(ctx, state)
+	if err != nil {
+		return domain.OAuth2Info{}, err
+	}
+	token, err := p.Exchange(ctx, code)
+	if err != nil {
+		return domain.OAuth2Info{}, err
+	}
+	return p.UserInfo(ctx, token)
+}
+
+func (o *OAuth2Handler) verifyState(ctx *gin.Context, state string) error {
+	ck, err := ctx.Cookie(o.stateCookieName)
+	if err != nil {
+		return fmt.Errorf("无法获得 cookie %w", err)
+	}
+	var sc StateClaims
+	_, err = jwt.ParseWithClaims(ck, &sc, o.stateKey.Keyfunc)
+	if err != nil {
+		return fmt.Errorf("解析 token 失败 %w", err)
+	}
+	if state != sc.State {
+		return fmt.Errorf("state 不匹配")
+	}
+	return nil
+}
+
+func (o *OAuth2Handler) setStateCookie(ctx *gin.Context, provider string, state string) error {
+	tokenStr, err := o.stateKey.Sign(StateClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 10)),
+		},
+		State: state,
+	})
+	if err != nil {
+		return err
+	}
+	ctx.SetCookie(o.stateCookieName, tokenStr,
+		600, "/oauth2/"+provider,
+		"", false, true)
+	return nil
+}
+
+type OAuth2BindReq struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
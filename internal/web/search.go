@@ -0,0 +1,100 @@
+package web
+
+import (
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	"ddd_demo/pkg/ginx"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	defaultSearchPageSize = 10
+	maxSearchPageSize     = 50
+	// maxSearchQueryLen 搜索词最多多少个字
+	maxSearchQueryLen = 50
+)
+
+type SearchHandler struct {
+	svc service.SearchService
+}
+
+func NewSearchHandler(svc service.SearchService) *SearchHandler {
+	return &SearchHandler{svc: svc}
+}
+
+func (h *SearchHandler) RegisterRoutes(server *gin.Engine) {
+	// GET /articles/search?q=Go&offset=0&limit=10
+	server.GET("/articles/search", ginx.WrapBody(h.SearchArticles))
+}
+
+func (h *SearchHandler) SearchArticles(ctx *gin.Context,
+	req SearchReq) (ginx.Result, error) {
+	q := strings.TrimSpace(req.Q)
+	if q == "" {
+		return ginx.Result{
+			Code: errs.ArticleInvalidInput,
+			Msg:  "请输入搜索内容",
+		}, nil
+	}
+	if utf8.RuneCountInString(q) > maxSearchQueryLen {
+		return ginx.Result{
+			Code: errs.ArticleInvalidInput,
+			Msg:  "搜索内容太长",
+		}, nil
+	}
+	offset := max(req.Offset, 0)
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSearchPageSize
+	}
+	limit = min(limit, maxSearchPageSize)
+	res, err := h.svc.SearchArticles(ctx, q, offset, limit)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.ArticleInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	return ginx.Result{
+		Data: SearchArticleListVo{
+			Total: res.Total,
+			Articles: slice.Map(res.Hits, func(idx int, src domain.ArticleSearchHit) SearchArticleVo {
+				return SearchArticleVo{
+					Id:         src.Id,
+					Title:      src.Title,
+					Snippet:    src.Snippet,
+					AuthorId:   src.Author.Id,
+					AuthorName: src.Author.Name,
+					Utime:      src.Utime.Format(time.DateTime),
+				}
+			}),
+		},
+	}, nil
+}
+
+type SearchReq struct {
+	Q      string `form:"q"`
+	Offset int    `form:"offset"`
+	Limit  int    `form:"limit"`
+}
+
+type SearchArticleListVo struct {
+	// Total 命中的总数，前端用来算一共有多少页
+	Total    int               `json:"total"`
+	Articles []SearchArticleVo `json:"articles"`
+}
+
+type SearchArticleVo struct {
+	Id int64 `json:"id"`
+	// Title 和 Snippet 是 HTML，已经转义过了，命中的地方用 <em></em> 包起来
+	Title      string `json:"title"`
+	Snippet    string `json:"snippet"`
+	AuthorId   int64  `json:"authorId"`
+	AuthorName string `json:"authorName"`
+	Utime      string `json:"utime"`
+}
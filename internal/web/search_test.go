@@ -0,0 +1,138 @@
+package web
+
+import (
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	svcmocks "ddd_demo/internal/service/mocks"
+	"ddd_demo/pkg/ginx"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSearchHandler_SearchArticles(t *testing.T) {
+	utime := time.UnixMilli(1000)
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) service.SearchService
+		url  string
+
+		wantRes ginx.Result
+	}{
+		{
+			name: "搜索成功",
+			mock: func(ctrl *gomock.Controller) service.SearchService {
+				svc := svcmocks.NewMockSearchService(ctrl)
+				svc.EXPECT().SearchArticles(gomock.Any(), "Go 入门", 10, 5).
+					Return(domain.ArticleSearchResult{
+						Total: 11,
+						Hits: []domain.ArticleSearchHit{{
+							Id:      1,
+							Author:  domain.Author{Id: 2, Name: "大明"},
+							Utime:   utime,
+							Title:   "<em>Go</em> 语言<em>入门</em>",
+							Snippet: "学习 <em>Go</em>",
+						}},
+					}, nil)
+				return svc
+			},
+			url: "/articles/search?q=" + url.QueryEscape(" Go 入门 ") + "&offset=10&limit=5",
+			wantRes: ginx.Result{
+				Data: map[string]any{
+					"total": float64(11),
+					"articles": []any{map[string]any{
+						"id":         float64(1),
+						"title":      "<em>Go</em> 语言<em>入门</em>",
+						"snippet":    "学习 <em>Go</em>",
+						"authorId":   float64(2),
+						"authorName": "大明",
+						"utime":      utime.Format(time.DateTime),
+					}},
+				},
+			},
+		},
+		{
+			name: "分页参数不对，用默认值",
+			mock: func(ctrl *gomock.Controller) service.SearchService {
+				svc := svcmocks.NewMockSearchService(ctrl)
+				svc.EXPECT().SearchArticles(gomock.Any(), "Go", 0, 50).
+					Return(domain.ArticleSearchResult{}, nil)
+				return svc
+			},
+			url: "/articles/search?q=Go&offset=-1&limit=1000",
+			wantRes: ginx.Result{
+				Data: map[string]any{
+					"total":    float64(0),
+					"articles": []any{},
+				},
+			},
+		},
+		{
+			name: "没有搜索内容",
+			mock: func(ctrl *gomock.Controller) service.SearchService {
+				return svcmocks.NewMockSearchService(ctrl)
+			},
+			url: "/articles/search?q=" + url.QueryEscape("  "),
+			wantRes: ginx.Result{
+				Code: errs.ArticleInvalidInput,
+				Msg:  "请输入搜索内容",
+			},
+		},
+		{
+			name: "搜索内容太长",
+			mock: func(ctrl *gomock.Controller) service.SearchService {
+				return svcmocks.NewMockSearchService(ctrl)
+			},
+			url: "/articles/search?q=" + url.QueryEscape(strings.Repeat("长", 51)),
+			wantRes: ginx.Result{
+				Code: errs.ArticleInvalidInput,
+				Msg:  "搜索内容太长",
+			},
+		},
+		{
+			name: "系统错误",
+			mock: func(ctrl *gomock.Controller) service.SearchService {
+				svc := svcmocks.NewMockSearchService(ctrl)
+				svc.EXPECT().SearchArticles(gomock.Any(), "Go", 0, 10).
+					Return(domain.ArticleSearchResult{}, errors.New("mock error"))
+				return svc
+			},
+			url: "/articles/search?q=Go",
+			wantRes: ginx.Result{
+				Code: errs.ArticleInternalServerError,
+				Msg:  "系统错误",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			hdl := NewSearchHandler(tc.mock(ctrl))
+			server := gin.Default()
+			hdl.RegisterRoutes(server)
+
+			req, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var res ginx.Result
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
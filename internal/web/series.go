@@ -0,0 +1,305 @@
+package web
+
+import (
+	intrv1 "ddd_demo/api/proto/gen/intr/v1"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	ijwt "ddd_demo/internal/web/jwt"
+	"ddd_demo/pkg/ginx"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/sync/errgroup"
+	"strconv"
+	"time"
+)
+
+// SeriesHandler 专栏，作者把自己的文章按顺序组织起来。
+// 专栏和文章一样可以点赞、收藏，biz 是 series
+type SeriesHandler struct {
+	svc     service.SeriesService
+	intrSvc intrv1.InteractiveServiceClient
+	biz     string
+}
+
+func NewSeriesHandler(svc service.SeriesService,
+	intrSvc intrv1.InteractiveServiceClient) *SeriesHandler {
+	return &SeriesHandler{
+		svc:     svc,
+		intrSvc: intrSvc,
+		biz:     "series",
+	}
+}
+
+func (h *SeriesHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/series")
+	g.POST("/edit", ginx.WrapBodyAndClaims(h.Edit))
+	g.POST("/delete", ginx.WrapBodyAndClaims(h.Delete))
+	// 整个替换目录，articleIds 的顺序就是目录的顺序
+	g.POST("/articles", ginx.WrapBodyAndClaims(h.SetArticles))
+	// GET /series/author/123
+	g.GET("/author/:uid", ginx.WrapClaims(h.ListByAuthor))
+	// 目录
+	g.GET("/:id", ginx.WrapClaims(h.Toc))
+	g.POST("/like", ginx.WrapBodyAndClaims(h.Like))
+	g.POST("/collect", ginx.WrapBodyAndClaims(h.Collect))
+}
+
+// Edit 新建或者修改专栏，返回专栏的 ID
+func (h *SeriesHandler) Edit(ctx *gin.Context,
+	req SeriesEditReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	id, err := h.svc.Save(ctx, domain.Series{
+		Id:          req.Id,
+		Author:      domain.Author{Id: uc.Uid},
+		Title:       req.Title,
+		Description: req.Description,
+	})
+	if err != nil {
+		return h.errResult(err)
+	}
+	return ginx.Result{
+		Data: id,
+	}, nil
+}
+
+func (h *SeriesHandler) Delete(ctx *gin.Context,
+	req SeriesDeleteReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.Delete(ctx, uc.Uid, req.Id)
+	if err != nil {
+		return h.errResult(err)
+	}
+	return ginx.Result{
+		Msg: "OK",
+	}, nil
+}
+
+func (h *SeriesHandler) SetArticles(ctx *gin.Context,
+	req SeriesArticlesReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.SetArticles(ctx, uc.Uid, req.Id, req.ArticleIds)
+	if err != nil {
+		return h.errResult(err)
+	}
+	return ginx.Result{
+		Msg: "OK",
+	}, nil
+}
+
+// ListByAuthor 作者所有的专栏，不带目录
+func (h *SeriesHandler) ListByAuthor(ctx *gin.Context, uc ijwt.UserClaims) (ginx.Result, error) {
+	uid, err := strconv.ParseInt(ctx.Param("uid"), 10, 64)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.SeriesInvalidInput,
+			Msg:  "uid 参数错误",
+		}, nil
+	}
+	ss, err := h.svc.ListByAuthor(ctx, uid)
+	if err != nil {
+		return h.errResult(err)
+	}
+	vos := slice.Map(ss, func(idx int, src domain.Series) SeriesVo {
+		return h.toVo(src)
+	})
+	if len(ss) == 0 {
+		return ginx.Result{Data: vos}, nil
+	}
+	intrs, err := h.intrSvc.GetByIds(ctx, &intrv1.GetByIdsRequest{
+		Biz: h.biz,
+		Ids: slice.Map(ss, func(idx int, src domain.Series) int64 {
+			return src.Id
+		}),
+	})
+	if err != nil {
+		return h.errResult(err)
+	}
+	for i := range vos {
+		intr, ok := intrs.GetIntrs()[vos[i].Id]
+		if ok {
+			vos[i].LikeCnt = intr.LikeCnt
+			vos[i].CollectCnt = intr.CollectCnt
+		}
+	}
+	return ginx.Result{Data: vos}, nil
+}
+
+// Toc 专栏的目录，只有当前用户能看到的文章
+func (h *SeriesHandler) Toc(ctx *gin.Context, uc ijwt.UserClaims) (ginx.Result, error) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.SeriesInvalidInput,
+			Msg:  "id 参数错误",
+		}, nil
+	}
+	var (
+		eg   errgroup.Group
+		s    domain.Series
+		intr *intrv1.GetResponse
+	)
+	eg.Go(func() error {
+		var er error
+		s, er = h.svc.Toc(ctx, id, uc.Uid)
+		return er
+	})
+	eg.Go(func() error {
+		var er error
+		intr, er = h.intrSvc.Get(ctx, &intrv1.GetRequest{
+			Biz: h.biz, BizId: id, Uid: uc.Uid,
+		})
+		return er
+	})
+	err = eg.Wait()
+	if err != nil {
+		return h.errResult(err)
+	}
+	vo := h.toVo(s)
+	vo.Articles = slice.Map(s.Articles, func(idx int, src domain.Article) SeriesArticleVo {
+		return SeriesArticleVo{
+			Id:       src.Id,
+			Title:    src.Title,
+			Abstract: src.Abstract(),
+			Status:   src.Status.ToUint8(),
+			Utime:    src.Utime.Format(time.DateTime),
+		}
+	})
+	vo.LikeCnt = intr.Intr.LikeCnt
+	vo.CollectCnt = intr.Intr.CollectCnt
+	vo.Liked = intr.Intr.Liked
+	vo.Collected = intr.Intr.Collected
+	return ginx.Result{Data: vo}, nil
+}
+
+// Like Like 为 true 是点赞，false 是取消点赞
+func (h *SeriesHandler) Like(ctx *gin.Context,
+	req SeriesLikeReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	var err error
+	if req.Like {
+		_, err = h.intrSvc.Like(ctx, &intrv1.LikeRequest{
+			Biz: h.biz, BizId: req.Id, Uid: uc.Uid,
+		})
+	} else {
+		_, err = h.intrSvc.CancelLike(ctx, &intrv1.CancelLikeRequest{
+			Biz: h.biz, BizId: req.Id, Uid: uc.Uid,
+		})
+	}
+	if err != nil {
+		return h.errResult(err)
+	}
+	return ginx.Result{
+		Msg: "OK",
+	}, nil
+}
+
+func (h *SeriesHandler) Collect(ctx *gin.Context,
+	req SeriesCollectReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	_, err := h.intrSvc.Collect(ctx, &intrv1.CollectRequest{
+		Biz: h.biz, BizId: req.Id, Uid: uc.Uid, Cid: req.Cid,
+	})
+	if err != nil {
+		return h.errResult(err)
+	}
+	return ginx.Result{
+		Msg: "OK",
+	}, nil
+}
+
+func (h *SeriesHandler) errResult(err error) (ginx.Result, error) {
+	switch err {
+	case service.ErrInvalidSeries:
+		return ginx.Result{
+			Code: errs.SeriesInvalidInput,
+			Msg:  "专栏标题不能为空，标题最多 128 个字，简介最多 1000 个字",
+		}, nil
+	case service.ErrInvalidSeriesArticles:
+		return ginx.Result{
+			Code: errs.SeriesInvalidInput,
+			Msg:  "只能放自己发表过的文章，不能重复，最多 200 篇",
+		}, nil
+	case service.ErrSeriesArticleConflict:
+		return ginx.Result{
+			Code: errs.SeriesArticleConflict,
+			Msg:  "有文章已经在别的专栏里面了",
+		}, nil
+	case service.ErrSeriesNotFound:
+		return ginx.Result{
+			Code: errs.SeriesInvalidInput,
+			Msg:  "专栏不存在",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.SeriesInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+func (h *SeriesHandler) toVo(s domain.Series) SeriesVo {
+	return SeriesVo{
+		Id:          s.Id,
+		Title:       s.Title,
+		Description: s.Description,
+		AuthorId:    s.Author.Id,
+		Ctime:       s.Ctime.Format(time.DateTime),
+		Utime:       s.Utime.Format(time.DateTime),
+	}
+}
+
+type SeriesEditReq struct {
+	// Id 不传就是新建
+	Id          int64  `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+type SeriesDeleteReq struct {
+	Id int64 `json:"id"`
+}
+
+type SeriesArticlesReq struct {
+	Id         int64   `json:"id"`
+	ArticleIds []int64 `json:"articleIds"`
+}
+
+type SeriesLikeReq struct {
+	Id   int64 `json:"id"`
+	Like bool  `json:"like"`
+}
+
+type SeriesCollectReq struct {
+	Id  int64 `json:"id"`
+	Cid int64 `json:"cid"`
+}
+
+type SeriesVo struct {
+	Id          int64  `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	AuthorId    int64  `json:"authorId"`
+	// Articles 目录，列表里面没有
+	Articles   []SeriesArticleVo `json:"articles,omitempty"`
+	LikeCnt    int64             `json:"likeCnt"`
+	CollectCnt int64             `json:"collectCnt"`
+	// Liked、Collected 只有目录里面有
+	Liked     bool   `json:"liked"`
+	Collected bool   `json:"collected"`
+	Ctime     string `json:"ctime"`
+	Utime     string `json:"utime"`
+}
+
+// SeriesArticleVo 目录里面的一篇文章
+type SeriesArticleVo struct {
+	Id       int64  `json:"id"`
+	Title    string `json:"title"`
+	Abstract string `json:"abstract,omitempty"`
+	// Status 作者自己看的时候撤回了的文章也在目录里面，用来区分
+	Status uint8  `json:"status,omitempty"`
+	Utime  string `json:"utime,omitempty"`
+}
+
+// SeriesNavVo 看文章的时候文章所在的专栏，没有上一篇、下一篇的时候是 null
+type SeriesNavVo struct {
+	Id    int64            `json:"id"`
+	Title string           `json:"title"`
+	Prev  *SeriesArticleVo `json:"prev"`
+	Next  *SeriesArticleVo `json:"next"`
+}
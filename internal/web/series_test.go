@@ -0,0 +1,207 @@
+package web
+
+import (
+	intrv1 "ddd_demo/api/proto/gen/intr/v1"
+	intrv1mocks "ddd_demo/api/proto/gen/intr/v1/mocks"
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	svcmocks "ddd_demo/internal/service/mocks"
+	ijwt "ddd_demo/internal/web/jwt"
+	"ddd_demo/pkg/logger"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSeriesHandler_Toc(t *testing.T) {
+	utime := time.UnixMilli(1000)
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (service.SeriesService, intrv1.InteractiveServiceClient)
+		url  string
+
+		wantCode int
+		wantVo   SeriesVo
+	}{
+		{
+			name: "目录和专栏的点赞收藏",
+			mock: func(ctrl *gomock.Controller) (service.SeriesService, intrv1.InteractiveServiceClient) {
+				svc := svcmocks.NewMockSeriesService(ctrl)
+				svc.EXPECT().Toc(gomock.Any(), int64(10), int64(123)).
+					Return(domain.Series{
+						Id:     10,
+						Author: domain.Author{Id: 456},
+						Title:  "Go 入门",
+						Articles: []domain.Article{
+							{Id: 2, Title: "第一篇", Content: "安装",
+								Status: domain.ArticleStatusPublished, Utime: utime},
+							{Id: 1, Title: "第二篇", Content: "语法",
+								Status: domain.ArticleStatusPublished, Utime: utime},
+						},
+						Ctime: utime,
+						Utime: utime,
+					}, nil)
+				intrSvc := intrv1mocks.NewMockInteractiveServiceClient(ctrl)
+				intrSvc.EXPECT().Get(gomock.Any(), &intrv1.GetRequest{
+					Biz: "series", BizId: 10, Uid: 123,
+				}).Return(&intrv1.GetResponse{Intr: &intrv1.Interactive{
+					LikeCnt: 3, CollectCnt: 2, Liked: true,
+				}}, nil)
+				return svc, intrSvc
+			},
+			url: "/series/10",
+			wantVo: SeriesVo{
+				Id:       10,
+				Title:    "Go 入门",
+				AuthorId: 456,
+				Articles: []SeriesArticleVo{
+					{Id: 2, Title: "第一篇", Abstract: "安装", Status: 2,
+						Utime: utime.Format(time.DateTime)},
+					{Id: 1, Title: "第二篇", Abstract: "语法", Status: 2,
+						Utime: utime.Format(time.DateTime)},
+				},
+				LikeCnt:    3,
+				CollectCnt: 2,
+				Liked:      true,
+				Ctime:      utime.Format(time.DateTime),
+				Utime:      utime.Format(time.DateTime),
+			},
+		},
+		{
+			name: "专栏不存在",
+			mock: func(ctrl *gomock.Controller) (service.SeriesService, intrv1.InteractiveServiceClient) {
+				svc := svcmocks.NewMockSeriesService(ctrl)
+				svc.EXPECT().Toc(gomock.Any(), int64(10), int64(123)).
+					Return(domain.Series{}, service.ErrSeriesNotFound)
+				intrSvc := intrv1mocks.NewMockInteractiveServiceClient(ctrl)
+				intrSvc.EXPECT().Get(gomock.Any(), gomock.Any()).
+					Return(&intrv1.GetResponse{Intr: &intrv1.Interactive{}}, nil)
+				return svc, intrSvc
+			},
+			url:      "/series/10",
+			wantCode: errs.SeriesInvalidInput,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			hdl := NewSeriesHandler(tc.mock(ctrl))
+			server := gin.Default()
+			server.Use(func(ctx *gin.Context) {
+				ctx.Set("user", ijwt.UserClaims{
+					Uid: 123,
+				})
+			})
+			hdl.RegisterRoutes(server)
+
+			req, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var res struct {
+				Code int      `json:"code"`
+				Data SeriesVo `json:"data"`
+			}
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantCode, res.Code)
+			assert.Equal(t, tc.wantVo, res.Data)
+		})
+	}
+}
+
+// TestArticleHandler_PubDetailSeries 看文章的时候带上专栏的上一篇、下一篇，查专栏出错不影响看文章
+func TestArticleHandler_PubDetailSeries(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) service.SeriesService
+
+		wantNav *SeriesNavVo
+	}{
+		{
+			name: "第一篇没有上一篇",
+			mock: func(ctrl *gomock.Controller) service.SeriesService {
+				svc := svcmocks.NewMockSeriesService(ctrl)
+				svc.EXPECT().Nav(gomock.Any(), int64(1), int64(123)).
+					Return(domain.SeriesNav{
+						Series: domain.Series{Id: 10, Title: "Go 入门"},
+						Next:   domain.Article{Id: 2, Title: "第二篇"},
+					}, nil)
+				return svc
+			},
+			wantNav: &SeriesNavVo{
+				Id:    10,
+				Title: "Go 入门",
+				Next:  &SeriesArticleVo{Id: 2, Title: "第二篇"},
+			},
+		},
+		{
+			name: "不在专栏里面",
+			mock: func(ctrl *gomock.Controller) service.SeriesService {
+				svc := svcmocks.NewMockSeriesService(ctrl)
+				svc.EXPECT().Nav(gomock.Any(), int64(1), int64(123)).
+					Return(domain.SeriesNav{}, service.ErrSeriesNotFound)
+				return svc
+			},
+		},
+		{
+			name: "查专栏出错",
+			mock: func(ctrl *gomock.Controller) service.SeriesService {
+				svc := svcmocks.NewMockSeriesService(ctrl)
+				svc.EXPECT().Nav(gomock.Any(), int64(1), int64(123)).
+					Return(domain.SeriesNav{}, errors.New("mock db 错误"))
+				return svc
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			artSvc := svcmocks.NewMockArticleService(ctrl)
+			artSvc.EXPECT().GetPubById(gomock.Any(), int64(1), int64(123), false).
+				Return(domain.Article{Id: 1, Title: "第一篇", Status: domain.ArticleStatusPublished}, nil)
+			intrSvc := intrv1mocks.NewMockInteractiveServiceClient(ctrl)
+			intrSvc.EXPECT().Get(gomock.Any(), gomock.Any()).
+				Return(&intrv1.GetResponse{Intr: &intrv1.Interactive{}}, nil)
+			hdl := NewArticleHandler(logger.NewNopLogger(), artSvc,
+				tc.mock(ctrl), intrSvc, ijwt.Keys{})
+			server := gin.Default()
+			server.Use(func(ctx *gin.Context) {
+				ctx.Set("user", ijwt.UserClaims{
+					Uid: 123,
+				})
+			})
+			hdl.RegisterRoutes(server)
+
+			req, err := http.NewRequest(http.MethodGet, "/articles/pub/1", nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var res struct {
+				Code int       `json:"code"`
+				Data ArticleVo `json:"data"`
+			}
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			require.NoError(t, err)
+			assert.Equal(t, 0, res.Code)
+			assert.Equal(t, int64(1), res.Data.Id)
+			assert.Equal(t, tc.wantNav, res.Data.Series)
+		})
+	}
+}
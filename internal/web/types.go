@@ -10,3 +10,9 @@ type Page struct {
 	Limit  int
 	Offset int
 }
+
+// CursorPage 游标翻页，Cursor 是上一页返回的，第一页不传
+type CursorPage struct {
+	Limit  int    `json:"limit"`
+	Cursor string `json:"cursor"`
+}
@@ -4,13 +4,17 @@ import (
 	"ddd_demo/internal/domain"
 	"ddd_demo/internal/errs"
 	"ddd_demo/internal/service"
+	"ddd_demo/internal/service/oauth2"
 	ijwt "ddd_demo/internal/web/jwt"
 	"ddd_demo/pkg/ginx"
+	"fmt"
 	regexp "github.com/dlclark/regexp2"
+	"github.com/ecodeclub/ekit/slice"
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
+	"math"
 	"net/http"
+	"sort"
 	"time"
 )
 
@@ -19,6 +23,7 @@ const (
 	// 和上面比起来，用 ` 看起来就比较清爽
 	passwordRegexPattern = `^(?=.*[A-Za-z])(?=.*\d)(?=.*[$@$!%*#?&])[A-Za-z\d$@$!%*#?&]{8,}$`
 	bizLogin             = "login"
+	bizBindPhone         = "bind_phone"
 )
 
 type UserHandler struct {
@@ -27,16 +32,23 @@ type UserHandler struct {
 	passwordRexExp *regexp.Regexp
 	svc            service.UserService
 	codeSvc        service.CodeService
+	guard          service.LoginGuard
+	// providers 配置了的第三方登录，解绑的时候用来校验
+	providers *oauth2.Registry
 }
 
 func NewUserHandler(svc service.UserService,
 	hdl ijwt.Handler,
-	codeSvc service.CodeService) *UserHandler {
+	codeSvc service.CodeService,
+	guard service.LoginGuard,
+	providers *oauth2.Registry) *UserHandler {
 	return &UserHandler{
 		emailRexExp:    regexp.MustCompile(emailRegexPattern, regexp.None),
 		passwordRexExp: regexp.MustCompile(passwordRegexPattern, regexp.None),
 		svc:            svc,
 		codeSvc:        codeSvc,
+		guard:          guard,
+		providers:      providers,
 		Handler:        hdl,
 	}
 }
@@ -46,12 +58,16 @@ func (h *UserHandler) RegisterRoutes(server *gin.Engine) {
 	//server.POST("/user", h.SignUp)
 	//server.PUT("/user", h.SignUp)
 	//server.GET("/users/:username", h.Profile)
+	// 别的服务从这里拿公钥校验用户的 token
+	server.GET("/.well-known/jwks.json", h.JWKSet)
+
 	ug := server.Group("/users")
 	// POST /users/signup
 	ug.POST("/signup", ginx.WrapBody(h.SignUp))
 	// POST /users/login
 	//ug.POST("/login", h.Login)
 	ug.POST("/login", ginx.WrapBody(h.LoginJWT))
+	ug.POST("/login/mfa", ginx.WrapBody(h.LoginMFA))
 	ug.POST("/logout", h.LogoutJWT)
 	// POST /users/edit
 	ug.POST("/edit", ginx.WrapBodyAndClaims(h.Edit))
@@ -59,6 +75,30 @@ func (h *UserHandler) RegisterRoutes(server *gin.Engine) {
 	ug.GET("/profile", ginx.WrapClaims(h.Profile))
 	ug.GET("/refresh_token", h.RefreshToken)
 
+	// 登录设备管理
+	ug.GET("/sessions", ginx.WrapClaims(h.Sessions))
+	ug.POST("/sessions/kick", ginx.WrapBodyAndClaims(h.KickSession))
+	ug.POST("/sessions/logout_all", ginx.WrapClaims(h.LogoutAll))
+
+	// 忘记密码
+	ug.POST("/password/reset/request", ginx.WrapBody(h.ResetPasswordRequest))
+	ug.POST("/password/reset/confirm", ginx.WrapBody(h.ResetPasswordConfirm))
+	// 验证邮箱
+	ug.POST("/email/verify/send", ginx.WrapClaims(h.SendVerifyEmail))
+	ug.POST("/email/verify", ginx.WrapBody(h.VerifyEmail))
+
+	// 二次验证
+	ug.POST("/totp/enroll", ginx.WrapClaims(h.EnrollTOTP))
+	ug.POST("/totp/activate", ginx.WrapBodyAndClaims(h.ActivateTOTP))
+	ug.POST("/totp/disable", ginx.WrapBodyAndClaims(h.DisableTOTP))
+
+	// 绑定、解绑登录方式
+	ug.POST("/bind/phone/code/send", ginx.WrapBodyAndClaims(h.SendBindPhoneCode))
+	ug.POST("/bind/phone", ginx.WrapBodyAndClaims(h.BindPhone))
+	ug.POST("/bind/email", ginx.WrapBodyAndClaims(h.BindEmail))
+	ug.POST("/bind/email/confirm", ginx.WrapBody(h.ConfirmBindEmail))
+	ug.POST("/unbind", ginx.WrapBodyAndClaims(h.Unbind))
+
 	// 手机验证码登录相关功能
 	ug.POST("/login_sms/code/send", ginx.WrapBody(h.SendSMSLoginCode))
 	ug.POST("/login_sms", ginx.WrapBody(h.LoginSMS))
@@ -87,16 +127,22 @@ func (h *UserHandler) LoginSMS(ctx *gin.Context,
 			Msg:  "系统错误",
 		}, err
 	}
-	err = h.SetLoginToken(ctx, u.Id)
-	if err != nil {
-		return ginx.Result{
-			Code: 5,
-			Msg:  "系统错误",
-		}, err
+	// 手机验证码也能证明是本人，解锁因为密码或者二次验证码错误太多被锁定的账号。
+	// 开启了二次验证的用户下面还是要再验证一次
+	keys := []string{mfaGuardKey(u.Id)}
+	if u.Email != "" {
+		keys = append(keys, u.Email)
 	}
-	return ginx.Result{
-		Msg: "登录成功",
-	}, nil
+	for _, key := range keys {
+		err = h.guard.Unlock(ctx, key)
+		if err != nil {
+			return ginx.Result{
+				Code: 5,
+				Msg:  "系统错误",
+			}, err
+		}
+	}
+	return login(ctx, h.Handler, h.svc, u.Id)
 }
 
 func (h *UserHandler) SendSMSLoginCode(ctx *gin.Context,
@@ -190,20 +236,44 @@ func (h *UserHandler) SignUp(ctx *gin.Context, req SignUpReq) (ginx.Result, erro
 }
 
 func (h *UserHandler) LoginJWT(ctx *gin.Context, req LoginJWTReq) (ginx.Result, error) {
+	wait, err := h.guard.Check(ctx, req.Email, ctx.ClientIP())
+	switch err {
+	case nil:
+	case service.ErrAccountLocked:
+		return ginx.Result{
+			Code: errs.UserAccountLocked,
+			Msg:  "密码错误次数太多，账号已经被锁定，请使用手机验证码登录解锁",
+		}, nil
+	case service.ErrLoginTooManyRequests:
+		return ginx.Result{
+			Code: errs.UserTooManyRequests,
+			Msg:  "登录太频繁，请稍后再试",
+		}, nil
+	default:
+		return ginx.Result{Msg: "系统错误"}, err
+	}
+	if wait > 0 {
+		return ginx.Result{
+			Code: errs.UserTooManyRequests,
+			Msg:  fmt.Sprintf("密码错误次数太多，请 %d 秒后再试", int64(math.Ceil(wait.Seconds()))),
+		}, nil
+	}
 	u, err := h.svc.Login(ctx, req.Email, req.Password)
 	switch err {
 	case nil:
-		err = h.SetLoginToken(ctx, u.Id)
+		err = h.guard.Succeed(ctx, req.Email)
 		if err != nil {
 			return ginx.Result{
 				Code: 5,
 				Msg:  "系统错误",
 			}, err
 		}
-		return ginx.Result{
-			Msg: "OK",
-		}, nil
+		return login(ctx, h.Handler, h.svc, u.Id)
 	case service.ErrInvalidUserOrPassword:
+		err = h.guard.Fail(ctx, req.Email, ctx.ClientIP())
+		if err != nil {
+			return ginx.Result{Msg: "系统错误"}, err
+		}
 		return ginx.Result{Msg: "用户名或者密码错误"}, nil
 	default:
 		return ginx.Result{Msg: "系统错误"}, err
@@ -291,17 +361,19 @@ func (h *UserHandler) Profile(ctx *gin.Context,
 		}, err
 	}
 	type User struct {
-		Nickname string `json:"nickname"`
-		Email    string `json:"email"`
-		AboutMe  string `json:"aboutMe"`
-		Birthday string `json:"birthday"`
+		Nickname      string `json:"nickname"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"emailVerified"`
+		AboutMe       string `json:"aboutMe"`
+		Birthday      string `json:"birthday"`
 	}
 	return ginx.Result{
 		Data: User{
-			Nickname: u.Nickname,
-			Email:    u.Email,
-			AboutMe:  u.AboutMe,
-			Birthday: u.Birthday.Format(time.DateOnly),
+			Nickname:      u.Nickname,
+			Email:         u.Email,
+			EmailVerified: u.EmailVerified,
+			AboutMe:       u.AboutMe,
+			Birthday:      u.Birthday.Format(time.DateOnly),
 		},
 	}, nil
 }
@@ -309,22 +381,29 @@ func (h *UserHandler) Profile(ctx *gin.Context,
 func (h *UserHandler) RefreshToken(ctx *gin.Context) {
 	// 约定，前端在 Authorization 里面带上这个 refresh_token
 	tokenStr := h.ExtractToken(ctx)
-	var rc ijwt.RefreshClaims
-	token, err := jwt.ParseWithClaims(tokenStr, &rc, func(token *jwt.Token) (interface{}, error) {
-		return ijwt.RCJWTKey, nil
-	})
+	rc, err := h.ParseRefreshToken(tokenStr)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	err = h.CheckSession(ctx, rc.Uid, rc.Ssid)
 	if err != nil {
+		// token 无效或者 redis 有问题
 		ctx.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
-	if token == nil || !token.Valid {
+
+	// 每次刷新都换一个新的长 token，老的立刻作废
+	err = h.RotateRefreshToken(ctx, rc)
+	if err != nil {
 		ctx.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
 
-	err = h.CheckSession(ctx, rc.Ssid)
+	err = h.RefreshSession(ctx, rc.Uid, rc.Ssid)
 	if err != nil {
-		// token 无效或者 redis 有问题
+		// 检查完之后刚好被踢下线了
 		ctx.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
@@ -339,6 +418,11 @@ func (h *UserHandler) RefreshToken(ctx *gin.Context) {
 	})
 }
 
+// JWKSet 公开校验短 token 用的公钥，格式参考 RFC 7517
+func (h *UserHandler) JWKSet(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, h.JWKS())
+}
+
 func (h *UserHandler) LogoutJWT(ctx *gin.Context) {
 	err := h.ClearToken(ctx)
 	if err != nil {
@@ -347,3 +431,551 @@ func (h *UserHandler) LogoutJWT(ctx *gin.Context) {
 	}
 	ctx.JSON(http.StatusOK, ginx.Result{Msg: "退出登录成功"})
 }
+
+// Sessions 列出所有的登录设备
+func (h *UserHandler) Sessions(ctx *gin.Context,
+	uc ijwt.UserClaims) (ginx.Result, error) {
+	sessions, err := h.ListSessions(ctx, uc.Uid)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	// 最近登录的排在前面
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LoginTime.After(sessions[j].LoginTime)
+	})
+	return ginx.Result{
+		Data: slice.Map(sessions, func(idx int, src ijwt.Session) SessionVo {
+			return SessionVo{
+				Ssid:        src.Ssid,
+				UserAgent:   src.UserAgent,
+				IP:          src.IP,
+				LoginTime:   src.LoginTime.Format(time.DateTime),
+				RefreshTime: src.RefreshTime.Format(time.DateTime),
+				Current:     src.Ssid == uc.Ssid,
+			}
+		}),
+	}, nil
+}
+
+// KickSession 把某个设备踢下线
+func (h *UserHandler) KickSession(ctx *gin.Context,
+	req KickSessionReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	if req.Ssid == "" {
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "请选择要下线的设备",
+		}, nil
+	}
+	err := h.DeleteSession(ctx, uc.Uid, req.Ssid)
+	switch err {
+	case nil:
+		return ginx.Result{Msg: "OK"}, nil
+	case ijwt.ErrSessionNotFound:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "设备不存在或者已经下线",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+// LogoutAll 退出所有设备，包括当前设备
+func (h *UserHandler) LogoutAll(ctx *gin.Context,
+	uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.ClearSessions(ctx, uc.Uid)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	ctx.Header("x-jwt-token", "")
+	ctx.Header("x-refresh-token", "")
+	return ginx.Result{Msg: "已退出所有设备"}, nil
+}
+
+// ResetPasswordRequest 忘记密码，发送重置密码的邮件
+func (h *UserHandler) ResetPasswordRequest(ctx *gin.Context,
+	req ResetPasswordRequestReq) (ginx.Result, error) {
+	isEmail, err := h.emailRexExp.MatchString(req.Email)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	if !isEmail {
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "非法邮箱格式",
+		}, nil
+	}
+	err = h.svc.SendResetPasswordEmail(ctx, req.Email)
+	switch err {
+	case nil:
+		// 不管邮箱有没有注册，都是一样的提示
+		return ginx.Result{
+			Msg: "如果该邮箱已经注册，您将收到一封重置密码的邮件",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+// ResetPasswordConfirm 用邮件里面的 token 设置新密码
+func (h *UserHandler) ResetPasswordConfirm(ctx *gin.Context,
+	req ResetPasswordConfirmReq) (ginx.Result, error) {
+	if req.Password != req.ConfirmPassword {
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "两次输入的密码不相等",
+		}, nil
+	}
+	isPassword, err := h.passwordRexExp.MatchString(req.Password)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	if !isPassword {
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "密码必须包含字母、数字、特殊字符",
+		}, nil
+	}
+	uid, err := h.svc.ResetPassword(ctx, req.Token, req.Password)
+	switch err {
+	case nil:
+	case service.ErrInvalidToken:
+		return ginx.Result{
+			Code: errs.UserInvalidToken,
+			Msg:  "链接无效或者已经过期",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	// 密码改了，所有设备都要重新登录
+	err = h.ClearSessions(ctx, uid)
+	if err != nil {
+		return ginx.Result{
+			Msg: "密码重置成功",
+		}, fmt.Errorf("重置密码后清理会话失败 uid %d %w", uid, err)
+	}
+	return ginx.Result{
+		Msg: "密码重置成功",
+	}, nil
+}
+
+// SendVerifyEmail 发送验证邮箱的邮件
+func (h *UserHandler) SendVerifyEmail(ctx *gin.Context,
+	uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.SendVerifyEmail(ctx, uc.Uid)
+	switch err {
+	case nil:
+		return ginx.Result{
+			Msg: "验证邮件已经发送",
+		}, nil
+	case service.ErrEmailNotBound:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "还没有绑定邮箱",
+		}, nil
+	case service.ErrTokenSendTooMany:
+		return ginx.Result{
+			Code: errs.UserTooManyRequests,
+			Msg:  "发送太频繁，请稍后再试",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+// VerifyEmail 验证邮件里面的链接点过来的
+func (h *UserHandler) VerifyEmail(ctx *gin.Context,
+	req VerifyEmailReq) (ginx.Result, error) {
+	err := h.svc.VerifyEmail(ctx, req.Token)
+	switch err {
+	case nil:
+		return ginx.Result{
+			Msg: "邮箱验证成功",
+		}, nil
+	case service.ErrInvalidToken:
+		return ginx.Result{
+			Code: errs.UserInvalidToken,
+			Msg:  "链接无效或者已经过期",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+// SendBindPhoneCode 绑定手机号之前，先发一个验证码
+func (h *UserHandler) SendBindPhoneCode(ctx *gin.Context,
+	req SendSMSCodeReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	if req.Phone == "" {
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "请输入手机号码",
+		}, nil
+	}
+	err := h.codeSvc.Send(ctx, bizBindPhone, req.Phone)
+	switch err {
+	case nil:
+		return ginx.Result{
+			Msg: "发送成功",
+		}, nil
+	case service.ErrCodeSendTooMany:
+		return ginx.Result{
+			Code: errs.UserTooManyRequests,
+			Msg:  "短信发送太频繁，请稍后再试",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+func (h *UserHandler) BindPhone(ctx *gin.Context,
+	req BindPhoneReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	ok, err := h.codeSvc.Verify(ctx, bizBindPhone, req.Phone, req.Code)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	if !ok {
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "验证码不对，请重新输入",
+		}, nil
+	}
+	err = h.svc.BindPhone(ctx, uc.Uid, req.Phone)
+	return h.bindResult(err)
+}
+
+// BindEmail 绑定邮箱，先给这个邮箱发一封确认邮件，点了邮件里面的链接才会绑定
+func (h *UserHandler) BindEmail(ctx *gin.Context,
+	req BindEmailReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	isEmail, err := h.emailRexExp.MatchString(req.Email)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	if !isEmail {
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "非法邮箱格式",
+		}, nil
+	}
+	err = h.svc.BindEmail(ctx, uc.Uid, req.Email)
+	switch err {
+	case nil:
+		return ginx.Result{
+			Msg: "确认邮件已经发送，请点击邮件里面的链接完成绑定",
+		}, nil
+	case service.ErrTokenSendTooMany:
+		return ginx.Result{
+			Code: errs.UserTooManyRequests,
+			Msg:  "发送太频繁，请稍后再试",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+// ConfirmBindEmail 绑定邮箱的确认邮件里面的链接点过来的
+func (h *UserHandler) ConfirmBindEmail(ctx *gin.Context,
+	req ConfirmBindEmailReq) (ginx.Result, error) {
+	err := h.svc.ConfirmBindEmail(ctx, req.Token)
+	if err == service.ErrInvalidToken {
+		return ginx.Result{
+			Code: errs.UserInvalidToken,
+			Msg:  "链接无效或者已经过期",
+		}, nil
+	}
+	return h.bindResult(err)
+}
+
+func (h *UserHandler) Unbind(ctx *gin.Context,
+	req UnbindReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	typ := domain.IdentityType(req.Type)
+	switch typ {
+	case domain.IdentityPhone, domain.IdentityEmail, domain.IdentityWechat:
+	default:
+		// 剩下的只能是配置了的第三方登录
+		_, err := h.providers.Get(req.Type)
+		if err != nil {
+			return ginx.Result{
+				Code: errs.UserInvalidInput,
+				Msg:  "未知的登录方式",
+			}, nil
+		}
+	}
+	err := h.svc.Unbind(ctx, uc.Uid, typ)
+	switch err {
+	case nil:
+		return ginx.Result{
+			Msg: "解绑成功",
+		}, nil
+	case service.ErrLastIdentity:
+		return ginx.Result{
+			Code: errs.UserLastIdentity,
+			Msg:  "至少要保留一种登录方式",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+func (h *UserHandler) bindResult(err error) (ginx.Result, error) {
+	switch err {
+	case nil:
+		return ginx.Result{
+			Msg: "绑定成功",
+		}, nil
+	case service.ErrIdentityBound:
+		return ginx.Result{
+			Code: errs.UserIdentityBound,
+			Msg:  "已经被别的账号绑定了",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+// login 所有的登录方式确认了身份之后都要走这里。
+// 开启了二次验证的用户只能拿到 mfa_pending token，不然短信、第三方登录就能绕过二次验证
+func login(ctx *gin.Context, hdl ijwt.Handler,
+	svc service.UserService, uid int64) (ginx.Result, error) {
+	enabled, err := svc.TOTPEnabled(ctx, uid)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	if enabled {
+		// 还要再验证一次，拿这个 token 去 /users/login/mfa 换真正的 token
+		token, err := hdl.SignMFAPendingToken(uid)
+		if err != nil {
+			return ginx.Result{
+				Code: errs.UserInternalServerError,
+				Msg:  "系统错误",
+			}, err
+		}
+		return ginx.Result{
+			Msg:  "需要二次验证",
+			Data: MFAPendingVo{MFAToken: token},
+		}, nil
+	}
+	err = hdl.SetLoginToken(ctx, uid)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	return ginx.Result{
+		Msg: "OK",
+	}, nil
+}
+
+// mfaGuardKey 二次验证码的错误次数按照用户来算
+func mfaGuardKey(uid int64) string {
+	return fmt.Sprintf("mfa:%d", uid)
+}
+
+// LoginMFA 用登录之后拿到的 mfa_pending token 加上验证码，换真正的登录 token
+func (h *UserHandler) LoginMFA(ctx *gin.Context, req LoginMFAReq) (ginx.Result, error) {
+	mc, err := h.ParseMFAPendingToken(req.MFAToken)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInvalidToken,
+			Msg:  "请重新登录",
+		}, nil
+	}
+	// 验证码只有 6 位，同样要防止暴力破解
+	key := mfaGuardKey(mc.Uid)
+	wait, err := h.guard.Check(ctx, key, ctx.ClientIP())
+	switch err {
+	case nil:
+	case service.ErrAccountLocked:
+		return ginx.Result{
+			Code: errs.UserAccountLocked,
+			Msg:  "验证码错误次数太多，账号已经被锁定，请使用手机验证码登录解锁",
+		}, nil
+	case service.ErrLoginTooManyRequests:
+		return ginx.Result{
+			Code: errs.UserTooManyRequests,
+			Msg:  "登录太频繁，请稍后再试",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	if wait > 0 {
+		return ginx.Result{
+			Code: errs.UserTooManyRequests,
+			Msg:  fmt.Sprintf("验证码错误次数太多，请 %d 秒后再试", int64(math.Ceil(wait.Seconds()))),
+		}, nil
+	}
+	err = h.svc.VerifyMFA(ctx, mc.Uid, req.Code)
+	switch err {
+	case nil:
+	case service.ErrInvalidMFACode:
+		err = h.guard.Fail(ctx, key, ctx.ClientIP())
+		if err != nil {
+			return ginx.Result{
+				Code: errs.UserInternalServerError,
+				Msg:  "系统错误",
+			}, err
+		}
+		return ginx.Result{
+			Code: errs.UserInvalidMFACode,
+			Msg:  "验证码不对",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	err = h.guard.Succeed(ctx, key)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	err = h.SetLoginToken(ctx, mc.Uid)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	return ginx.Result{
+		Msg: "OK",
+	}, nil
+}
+
+// EnrollTOTP 返回密钥和 otpauth URI，前端转成二维码给用户扫
+func (h *UserHandler) EnrollTOTP(ctx *gin.Context,
+	uc ijwt.UserClaims) (ginx.Result, error) {
+	secret, uri, err := h.svc.EnrollTOTP(ctx, uc.Uid)
+	switch err {
+	case nil:
+		return ginx.Result{
+			Data: TOTPEnrollVo{
+				Secret: secret,
+				URI:    uri,
+			},
+		}, nil
+	case service.ErrTOTPEnabled:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "已经启用了二次验证",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+// ActivateTOTP 扫码之后输入一次验证码，启用二次验证，返回恢复码
+func (h *UserHandler) ActivateTOTP(ctx *gin.Context,
+	req TOTPCodeReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	codes, err := h.svc.ActivateTOTP(ctx, uc.Uid, req.Code)
+	switch err {
+	case nil:
+		return ginx.Result{
+			Msg:  "二次验证已经启用，请妥善保管恢复码",
+			Data: codes,
+		}, nil
+	case service.ErrTOTPNotEnrolled:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "请先绑定",
+		}, nil
+	case service.ErrTOTPEnabled:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "已经启用了二次验证",
+		}, nil
+	case service.ErrInvalidMFACode:
+		return ginx.Result{
+			Code: errs.UserInvalidMFACode,
+			Msg:  "验证码不对",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+// DisableTOTP 关闭二次验证，需要验证码或者恢复码
+func (h *UserHandler) DisableTOTP(ctx *gin.Context,
+	req TOTPCodeReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.DisableTOTP(ctx, uc.Uid, req.Code)
+	switch err {
+	case nil:
+		return ginx.Result{
+			Msg: "二次验证已经关闭",
+		}, nil
+	case service.ErrTOTPNotEnrolled:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "没有启用二次验证",
+		}, nil
+	case service.ErrInvalidMFACode:
+		return ginx.Result{
+			Code: errs.UserInvalidMFACode,
+			Msg:  "验证码不对",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
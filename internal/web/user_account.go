@@ -0,0 +1,141 @@
+package web
+
+import (
+	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	ijwt "ddd_demo/internal/web/jwt"
+	"ddd_demo/pkg/ginx"
+	"ddd_demo/pkg/logger"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+// UserAccountHandler 注销账号和导出个人数据
+type UserAccountHandler struct {
+	ijwt.Handler
+	svc service.UserAccountService
+	l   logger.LoggerV1
+}
+
+func NewUserAccountHandler(svc service.UserAccountService,
+	hdl ijwt.Handler, l logger.LoggerV1) *UserAccountHandler {
+	return &UserAccountHandler{
+		Handler: hdl,
+		svc:     svc,
+		l:       l,
+	}
+}
+
+func (h *UserAccountHandler) RegisterRoutes(server *gin.Engine) {
+	ug := server.Group("/users")
+	ug.POST("/deactivate", ginx.WrapClaims(h.Deactivate))
+	// POST 开始导出，GET 查询导出的进度
+	ug.POST("/export", ginx.WrapClaims(h.Export))
+	ug.GET("/export", ginx.WrapClaims(h.ExportStatus))
+	ug.GET("/export/download", h.Download)
+}
+
+func (h *UserAccountHandler) Deactivate(ctx *gin.Context,
+	uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.Deactivate(ctx, uc.Uid)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	// 账号已经注销了，所有设备都下线
+	err = h.ClearSessions(ctx, uc.Uid)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	err = h.ClearToken(ctx)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	return ginx.Result{
+		Msg: "账号已注销",
+	}, nil
+}
+
+func (h *UserAccountHandler) Export(ctx *gin.Context,
+	uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.Export(ctx, uc.Uid)
+	switch err {
+	case nil:
+		return ginx.Result{
+			Msg: "正在导出，请稍后查看",
+		}, nil
+	case service.ErrExportRunning:
+		return ginx.Result{
+			Code: errs.UserTooManyRequests,
+			Msg:  "正在导出，请稍后查看",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+func (h *UserAccountHandler) ExportStatus(ctx *gin.Context,
+	uc ijwt.UserClaims) (ginx.Result, error) {
+	exp, err := h.svc.ExportStatus(ctx, uc.Uid)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	vo := UserExportVo{
+		Status: uint8(exp.Status),
+	}
+	if exp.Status != domain.UserExportStatusUnknown {
+		vo.Ctime = exp.Ctime.Format(time.DateTime)
+	}
+	return ginx.Result{
+		Data: vo,
+	}, nil
+}
+
+// Download 直接返回 zip 文件，所以不能用 ginx 包装
+func (h *UserAccountHandler) Download(ctx *gin.Context) {
+	uc := ctx.MustGet("user").(ijwt.UserClaims)
+	rc, err := h.svc.OpenExportFile(ctx, uc.Uid)
+	switch err {
+	case nil:
+		defer rc.Close()
+		ctx.DataFromReader(http.StatusOK, -1, "application/zip", rc, map[string]string{
+			"Content-Disposition": fmt.Sprintf(`attachment; filename="webook-%d.zip"`, uc.Uid),
+		})
+	case service.ErrExportNotReady:
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "还没有可以下载的文件，请先导出",
+		})
+	default:
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		})
+		h.l.Error("下载导出文件失败",
+			logger.Int64("uid", uc.Uid),
+			logger.Error(err))
+	}
+}
+
+type UserExportVo struct {
+	// 0 没有导出过，1 正在导出，2 可以下载，3 导出失败
+	Status uint8  `json:"status"`
+	Ctime  string `json:"ctime"`
+}
@@ -0,0 +1,163 @@
+package web
+
+import (
+	"ddd_demo/internal/errs"
+	"ddd_demo/internal/service"
+	svcmocks "ddd_demo/internal/service/mocks"
+	ijwt "ddd_demo/internal/web/jwt"
+	"ddd_demo/pkg/ginx"
+	"ddd_demo/pkg/logger"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUserAccountHandler_Download(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) service.UserAccountService
+
+		wantType        string
+		wantDisposition string
+		wantBody        string
+		wantRes         ginx.Result
+	}{
+		{
+			name: "下载成功",
+			mock: func(ctrl *gomock.Controller) service.UserAccountService {
+				svc := svcmocks.NewMockUserAccountService(ctrl)
+				svc.EXPECT().OpenExportFile(gomock.Any(), int64(123)).
+					Return(io.NopCloser(strings.NewReader("zip content")), nil)
+				return svc
+			},
+			wantType:        "application/zip",
+			wantDisposition: `attachment; filename="webook-123.zip"`,
+			wantBody:        "zip content",
+		},
+		{
+			name: "还没有打包好",
+			mock: func(ctrl *gomock.Controller) service.UserAccountService {
+				svc := svcmocks.NewMockUserAccountService(ctrl)
+				svc.EXPECT().OpenExportFile(gomock.Any(), int64(123)).
+					Return(nil, service.ErrExportNotReady)
+				return svc
+			},
+			wantRes: ginx.Result{
+				Code: errs.UserInvalidInput,
+				Msg:  "还没有可以下载的文件，请先导出",
+			},
+		},
+		{
+			name: "系统错误",
+			mock: func(ctrl *gomock.Controller) service.UserAccountService {
+				svc := svcmocks.NewMockUserAccountService(ctrl)
+				svc.EXPECT().OpenExportFile(gomock.Any(), int64(123)).
+					Return(nil, errors.New("存储出错"))
+				return svc
+			},
+			wantRes: ginx.Result{
+				Code: errs.UserInternalServerError,
+				Msg:  "系统错误",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			hdl := NewUserAccountHandler(tc.mock(ctrl), nil, logger.NewNopLogger())
+			server := gin.Default()
+			server.Use(func(ctx *gin.Context) {
+				ctx.Set("user", ijwt.UserClaims{
+					Uid: 123,
+				})
+			})
+			hdl.RegisterRoutes(server)
+
+			req, err := http.NewRequest(http.MethodGet, "/users/export/download", nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			if tc.wantType != "" {
+				assert.Equal(t, tc.wantType, recorder.Header().Get("Content-Type"))
+				assert.Equal(t, tc.wantDisposition, recorder.Header().Get("Content-Disposition"))
+				assert.Equal(t, tc.wantBody, recorder.Body.String())
+				return
+			}
+			var res ginx.Result
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+func TestUserAccountHandler_Export(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) service.UserAccountService
+
+		wantRes ginx.Result
+	}{
+		{
+			name: "开始导出",
+			mock: func(ctrl *gomock.Controller) service.UserAccountService {
+				svc := svcmocks.NewMockUserAccountService(ctrl)
+				svc.EXPECT().Export(gomock.Any(), int64(123)).Return(nil)
+				return svc
+			},
+			wantRes: ginx.Result{
+				Msg: "正在导出，请稍后查看",
+			},
+		},
+		{
+			name: "正在导出",
+			mock: func(ctrl *gomock.Controller) service.UserAccountService {
+				svc := svcmocks.NewMockUserAccountService(ctrl)
+				svc.EXPECT().Export(gomock.Any(), int64(123)).Return(service.ErrExportRunning)
+				return svc
+			},
+			wantRes: ginx.Result{
+				Code: errs.UserTooManyRequests,
+				Msg:  "正在导出，请稍后查看",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			hdl := NewUserAccountHandler(tc.mock(ctrl), nil, logger.NewNopLogger())
+			server := gin.Default()
+			server.Use(func(ctx *gin.Context) {
+				ctx.Set("user", ijwt.UserClaims{
+					Uid: 123,
+				})
+			})
+			hdl.RegisterRoutes(server)
+
+			req, err := http.NewRequest(http.MethodPost, "/users/export", nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var res ginx.Result
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
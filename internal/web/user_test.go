@@ -4,8 +4,15 @@ import (
 	"bytes"
 	"context"
 	"ddd_demo/internal/domain"
+	"ddd_demo/internal/errs"
 	"ddd_demo/internal/service"
 	svcmocks "ddd_demo/internal/service/mocks"
+	"ddd_demo/internal/service/oauth2"
+	"ddd_demo/internal/service/oauth2/github"
+	ijwt "ddd_demo/internal/web/jwt"
+	"ddd_demo/pkg/ginx"
+	"ddd_demo/pkg/logger"
+	"encoding/json"
 	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -54,7 +61,7 @@ func TestUserHandler_SignUp(t *testing.T) {
 			},
 
 			wantCode: http.StatusOK,
-			wantBody: "注册成功",
+			wantBody: "OK",
 		},
 		{
 			name: "Bind出错",
@@ -119,7 +126,7 @@ func TestUserHandler_SignUp(t *testing.T) {
 			},
 
 			wantCode: http.StatusOK,
-			wantBody: "两次输入密码不对",
+			wantBody: "两次输入的密码不相等",
 		},
 
 		{
@@ -142,7 +149,7 @@ func TestUserHandler_SignUp(t *testing.T) {
 			},
 
 			wantCode: http.StatusOK,
-			wantBody: "密码必须包含字母、数字、特殊字符，并且不少于八位",
+			wantBody: "密码必须包含字母、数字、特殊字符",
 		},
 
 		{
@@ -196,7 +203,7 @@ func TestUserHandler_SignUp(t *testing.T) {
 			},
 
 			wantCode: http.StatusOK,
-			wantBody: "邮箱冲突，请换一个",
+			wantBody: "邮箱冲突",
 		},
 	}
 
@@ -207,7 +214,7 @@ func TestUserHandler_SignUp(t *testing.T) {
 
 			// 构造 handler
 			userSvc, codeSvc := tc.mock(ctrl)
-			hdl := NewUserHandler(userSvc, nil, codeSvc)
+			hdl := NewUserHandler(userSvc, nil, codeSvc, nil, nil)
 
 			// 准备服务器，注册路由
 			server := gin.Default()
@@ -222,7 +229,13 @@ func TestUserHandler_SignUp(t *testing.T) {
 
 			// 断言结果
 			assert.Equal(t, tc.wantCode, recorder.Code)
-			assert.Equal(t, tc.wantBody, recorder.Body.String())
+			if recorder.Code != http.StatusOK {
+				return
+			}
+			var res ginx.Result
+			err := json.NewDecoder(recorder.Body).Decode(&res)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantBody, res.Msg)
 		})
 	}
 }
@@ -251,7 +264,7 @@ func TestEmailPattern(t *testing.T) {
 		},
 	}
 
-	h := NewUserHandler(nil, nil, nil)
+	h := NewUserHandler(nil, nil, nil, nil, nil)
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -290,3 +303,135 @@ func TestMock(t *testing.T) {
 	})
 	t.Log(err)
 }
+
+func TestUserHandler_LoginSMS(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (service.UserService,
+			service.CodeService, service.LoginGuard)
+
+		wantMsg   string
+		wantToken bool
+	}{
+		{
+			// 短信登录也不能绕过二次验证
+			name: "开启了二次验证",
+			mock: func(ctrl *gomock.Controller) (service.UserService,
+				service.CodeService, service.LoginGuard) {
+				codeSvc := svcmocks.NewMockCodeService(ctrl)
+				codeSvc.EXPECT().Verify(gomock.Any(), bizLogin, "15212345678", "123456").
+					Return(true, nil)
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				userSvc.EXPECT().FindOrCreate(gomock.Any(), "15212345678").
+					Return(domain.User{Id: 1, Email: "123@qq.com"}, nil)
+				userSvc.EXPECT().TOTPEnabled(gomock.Any(), int64(1)).Return(true, nil)
+				guard := svcmocks.NewMockLoginGuard(ctrl)
+				// 二次验证码错误太多被锁定的，也一起解锁
+				guard.EXPECT().Unlock(gomock.Any(), "mfa:1").Return(nil)
+				guard.EXPECT().Unlock(gomock.Any(), "123@qq.com").Return(nil)
+				return userSvc, codeSvc, guard
+			},
+			wantMsg: "需要二次验证",
+		},
+		{
+			name: "验证码不对",
+			mock: func(ctrl *gomock.Controller) (service.UserService,
+				service.CodeService, service.LoginGuard) {
+				codeSvc := svcmocks.NewMockCodeService(ctrl)
+				codeSvc.EXPECT().Verify(gomock.Any(), bizLogin, "15212345678", "123456").
+					Return(false, nil)
+				return svcmocks.NewMockUserService(ctrl), codeSvc,
+					svcmocks.NewMockLoginGuard(ctrl)
+			},
+			wantMsg: "验证码不对，请重新输入",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			userSvc, codeSvc, guard := tc.mock(ctrl)
+			jwtHdl := ijwt.NewRedisJWTHandler(nil, logger.NewNopLogger(), ijwt.Keys{
+				State: ijwt.NewHMACKeyProvider([]byte("state-secret")),
+			})
+			hdl := NewUserHandler(userSvc, jwtHdl, codeSvc, guard, nil)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/users/login_sms", nil)
+			res, err := hdl.LoginSMS(ctx, LoginSMSReq{Phone: "15212345678", Code: "123456"})
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantMsg, res.Msg)
+			assert.Equal(t, tc.wantToken, recorder.Header().Get("x-jwt-token") != "")
+		})
+	}
+}
+
+func TestUserHandler_Unbind(t *testing.T) {
+	providers, err := oauth2.NewRegistry(
+		github.NewProvider(oauth2.Config{Name: "github"}, http.DefaultClient))
+	require.NoError(t, err)
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) service.UserService
+		typ  string
+
+		wantCode int
+		wantMsg  string
+	}{
+		{
+			name: "解绑手机号",
+			mock: func(ctrl *gomock.Controller) service.UserService {
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				userSvc.EXPECT().Unbind(gomock.Any(), int64(1), domain.IdentityPhone).Return(nil)
+				return userSvc
+			},
+			typ:     "phone",
+			wantMsg: "解绑成功",
+		},
+		{
+			name: "解绑配置了的第三方登录",
+			mock: func(ctrl *gomock.Controller) service.UserService {
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				userSvc.EXPECT().Unbind(gomock.Any(), int64(1), domain.IdentityType("github")).
+					Return(nil)
+				return userSvc
+			},
+			typ:     "github",
+			wantMsg: "解绑成功",
+		},
+		{
+			name: "没有配置的第三方登录",
+			mock: func(ctrl *gomock.Controller) service.UserService {
+				return svcmocks.NewMockUserService(ctrl)
+			},
+			typ:      "gitlab",
+			wantCode: errs.UserInvalidInput,
+			wantMsg:  "未知的登录方式",
+		},
+		{
+			name: "最后一种登录方式",
+			mock: func(ctrl *gomock.Controller) service.UserService {
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				userSvc.EXPECT().Unbind(gomock.Any(), int64(1), domain.IdentityType("github")).
+					Return(service.ErrLastIdentity)
+				return userSvc
+			},
+			typ:      "github",
+			wantCode: errs.UserLastIdentity,
+			wantMsg:  "至少要保留一种登录方式",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			hdl := NewUserHandler(tc.mock(ctrl), nil, nil, nil, providers)
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			res, err := hdl.Unbind(ctx, UnbindReq{Type: tc.typ}, ijwt.UserClaims{Uid: 1})
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantCode, res.Code)
+			assert.Equal(t, tc.wantMsg, res.Msg)
+		})
+	}
+}
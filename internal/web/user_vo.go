@@ -25,6 +25,72 @@ type UserEditReq struct {
 	AboutMe  string `json:"aboutMe"`
 }
 
+type ResetPasswordRequestReq struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordConfirmReq struct {
+	Token           string `json:"token"`
+	Password        string `json:"password"`
+	ConfirmPassword string `json:"confirmPassword"`
+}
+
+type VerifyEmailReq struct {
+	Token string `json:"token"`
+}
+
 type SendSMSCodeReq struct {
 	Phone string `json:"phone"`
 }
+
+type BindPhoneReq struct {
+	Phone string `json:"phone"`
+	Code  string `json:"code"`
+}
+
+type BindEmailReq struct {
+	Email string `json:"email"`
+}
+
+type ConfirmBindEmailReq struct {
+	Token string `json:"token"`
+}
+
+type UnbindReq struct {
+	// phone, email, wechat，或者配置了的第三方登录的名字，比如说 github
+	Type string `json:"type"`
+}
+
+type LoginMFAReq struct {
+	MFAToken string `json:"mfaToken"`
+	// 验证码或者恢复码
+	Code string `json:"code"`
+}
+
+type TOTPCodeReq struct {
+	Code string `json:"code"`
+}
+
+type MFAPendingVo struct {
+	MFAToken string `json:"mfaToken"`
+}
+
+type TOTPEnrollVo struct {
+	Secret string `json:"secret"`
+	// otpauth://totp/...
+	URI string `json:"uri"`
+}
+
+type KickSessionReq struct {
+	Ssid string `json:"ssid"`
+}
+
+type SessionVo struct {
+	Ssid        string `json:"ssid"`
+	UserAgent   string `json:"userAgent"`
+	IP          string `json:"ip"`
+	LoginTime   string `json:"loginTime"`
+	RefreshTime string `json:"refreshTime"`
+	// 是不是当前正在使用的设备
+	Current bool `json:"current"`
+}
@@ -1,6 +1,7 @@
 package web
 
 import (
+	"ddd_demo/internal/errs"
 	"ddd_demo/internal/service"
 	"ddd_demo/internal/service/oauth2/wechat"
 	ijwt "ddd_demo/internal/web/jwt"
@@ -16,17 +17,18 @@ type OAuth2WechatHandler struct {
 	svc     wechat.Service
 	userSvc service.UserService
 	ijwt.Handler
-	key             []byte
+	stateKey        ijwt.KeyProvider
 	stateCookieName string
 }
 
 func NewOAuth2WechatHandler(svc wechat.Service,
 	hdl ijwt.Handler,
-	userSvc service.UserService) *OAuth2WechatHandler {
+	userSvc service.UserService,
+	keys ijwt.Keys) *OAuth2WechatHandler {
 	return &OAuth2WechatHandler{
 		svc:             svc,
 		userSvc:         userSvc,
-		key:             []byte("k6CswdUm77WKcbM68UQUuxVsHSpTCwgB"),
+		stateKey:        keys.State,
 		stateCookieName: "jwt-state",
 		Handler:         hdl,
 	}
@@ -35,7 +37,9 @@ func NewOAuth2WechatHandler(svc wechat.Service,
 func (o *OAuth2WechatHandler) RegisterRoutes(server *gin.Engine) {
 	g := server.Group("/oauth2/wechat")
 	g.GET("/authurl", o.Auth2URL)
-	g.Any("/callback", o.Callback)
+	g.Any("/callback", ginx.Wrap(o.Callback))
+	// 已经登录的用户绑定微信，前端拿到回调里面的 code 和 state 之后调用
+	g.POST("/bind", ginx.WrapBodyAndClaims(o.Bind))
 }
 
 func (o *OAuth2WechatHandler) Auth2URL(ctx *gin.Context) {
@@ -60,55 +64,80 @@ func (o *OAuth2WechatHandler) Auth2URL(ctx *gin.Context) {
 	})
 }
 
-func (o *OAuth2WechatHandler) Callback(ctx *gin.Context) {
+func (o *OAuth2WechatHandler) Callback(ctx *gin.Context) (ginx.Result, error) {
 	err := o.verifyState(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusOK, ginx.Result{
+		return ginx.Result{
 			Msg:  "非法请求",
 			Code: 4,
-		})
-		return
+		}, err
 	}
 	// 你校验不校验都可以
 	code := ctx.Query("code")
 	// state := ctx.Query("state")
 	wechatInfo, err := o.svc.VerifyCode(ctx, code)
 	if err != nil {
-		ctx.JSON(http.StatusOK, ginx.Result{
+		return ginx.Result{
 			Msg:  "授权码有误",
 			Code: 4,
-		})
-		return
+		}, err
 	}
 	u, err := o.userSvc.FindOrCreateByWechat(ctx, wechatInfo)
 	if err != nil {
-		ctx.JSON(http.StatusOK, ginx.Result{
+		return ginx.Result{
 			Msg:  "系统错误",
 			Code: 5,
-		})
-		return
+		}, err
 	}
-	err = o.SetLoginToken(ctx, u.Id)
+	return login(ctx, o.Handler, o.userSvc, u.Id)
+}
+
+func (o *OAuth2WechatHandler) Bind(ctx *gin.Context,
+	req WechatBindReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	err := o.checkState(ctx, req.State)
 	if err != nil {
-		ctx.String(http.StatusOK, "系统错误")
-		return
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "非法请求",
+		}, err
+	}
+	wechatInfo, err := o.svc.VerifyCode(ctx, req.Code)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "授权码有误",
+		}, err
+	}
+	err = o.userSvc.BindWechat(ctx, uc.Uid, wechatInfo)
+	switch err {
+	case nil:
+		return ginx.Result{
+			Msg: "绑定成功",
+		}, nil
+	case service.ErrIdentityBound:
+		return ginx.Result{
+			Code: errs.UserIdentityBound,
+			Msg:  "该微信已经绑定了别的账号",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
 	}
-	ctx.JSON(http.StatusOK, ginx.Result{
-		Msg: "OK",
-	})
-	return
 }
 
 func (o *OAuth2WechatHandler) verifyState(ctx *gin.Context) error {
-	state := ctx.Query("state")
+	return o.checkState(ctx, ctx.Query("state"))
+}
+
+func (o *OAuth2WechatHandler) checkState(ctx *gin.Context, state string) error {
 	ck, err := ctx.Cookie(o.stateCookieName)
 	if err != nil {
 		return fmt.Errorf("无法获得 cookie %w", err)
 	}
 	var sc StateClaims
-	_, err = jwt.ParseWithClaims(ck, &sc, func(token *jwt.Token) (interface{}, error) {
-		return o.key, nil
-	})
+	_, err = jwt.ParseWithClaims(ck, &sc, o.stateKey.Keyfunc)
 	if err != nil {
 		return fmt.Errorf("解析 token 失败 %w", err)
 	}
@@ -124,18 +153,22 @@ func (o *OAuth2WechatHandler) setStateCookie(ctx *gin.Context,
 	claims := StateClaims{
 		State: state,
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS512, claims)
-	tokenStr, err := token.SignedString(o.key)
+	tokenStr, err := o.stateKey.Sign(claims)
 	if err != nil {
 
 		return err
 	}
 	ctx.SetCookie(o.stateCookieName, tokenStr,
-		600, "/oauth2/wechat/callback",
+		600, "/oauth2/wechat",
 		"", false, true)
 	return nil
 }
 
+type WechatBindReq struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
+
 type StateClaims struct {
 	jwt.RegisteredClaims
 	State string
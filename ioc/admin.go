@@ -0,0 +1,18 @@
+package ioc
+
+import (
+	"ddd_demo/internal/web/middleware"
+	"github.com/spf13/viper"
+)
+
+func InitAdminMiddleware() *middleware.AdminMiddlewareBuilder {
+	type Config struct {
+		Uids []int64 `yaml:"uids"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("admin", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return middleware.NewAdminMiddlewareBuilder(cfg.Uids)
+}
@@ -0,0 +1,19 @@
+package ioc
+
+import (
+	"ddd_demo/internal/service"
+	"github.com/spf13/viper"
+	"time"
+)
+
+func InitArticleImportConfig() service.ArticleImportConfig {
+	cfg := service.ArticleImportConfig{
+		Expiration: time.Hour * 24,
+		Timeout:    time.Minute * 10,
+	}
+	err := viper.UnmarshalKey("articleImport", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
@@ -0,0 +1,31 @@
+package ioc
+
+import (
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+	"sync"
+)
+
+var (
+	configWatchersMu   sync.Mutex
+	configWatchers     []func(in fsnotify.Event)
+	configWatchersOnce sync.Once
+)
+
+// onConfigChange viper.OnConfigChange 只会保留最后一个回调，
+// 要监听配置变更的地方都通过这里注册，变更的时候按照注册的顺序挨个调用
+func onConfigChange(fn func(in fsnotify.Event)) {
+	configWatchersMu.Lock()
+	configWatchers = append(configWatchers, fn)
+	configWatchersMu.Unlock()
+	configWatchersOnce.Do(func() {
+		viper.OnConfigChange(func(in fsnotify.Event) {
+			configWatchersMu.Lock()
+			fns := append([]func(in fsnotify.Event){}, configWatchers...)
+			configWatchersMu.Unlock()
+			for _, fn := range fns {
+				fn(in)
+			}
+		})
+	})
+}
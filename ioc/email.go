@@ -0,0 +1,36 @@
+package ioc
+
+import (
+	"ddd_demo/internal/service/email"
+	"ddd_demo/internal/service/email/smtp"
+	"github.com/spf13/viper"
+	"os"
+)
+
+func InitEmailService() email.Service {
+	type Config struct {
+		Host     string `yaml:"host"`
+		Port     int    `yaml:"port"`
+		Username string `yaml:"username"`
+		// PasswordEnv 密码不进配置文件，部署的时候通过这个环境变量注入
+		PasswordEnv string `yaml:"passwordEnv"`
+		From        string `yaml:"from"`
+	}
+	cfg := Config{
+		Port: 587,
+	}
+	err := viper.UnmarshalKey("email.smtp", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	// 重置密码、验证邮箱都依赖邮件，没有配置就不要启动，
+	// 免得上线了才发现邮件根本没有发出去
+	if cfg.Host == "" || cfg.From == "" {
+		panic("没有配置 email.smtp")
+	}
+	password, ok := os.LookupEnv(cfg.PasswordEnv)
+	if cfg.PasswordEnv == "" || !ok {
+		panic("找不到 SMTP 的密码 " + cfg.PasswordEnv)
+	}
+	return smtp.NewService(cfg.Host, cfg.Port, cfg.Username, password, cfg.From)
+}
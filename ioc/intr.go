@@ -62,7 +62,7 @@ func InitIntrClient(svc service.InteractiveService) intrv1.InteractiveServiceCli
 	remote := intrv1.NewInteractiveServiceClient(cc)
 	local := client.NewLocalInteractiveServiceAdapter(svc)
 	res := client.NewInteractiveClient(remote, local)
-	viper.OnConfigChange(func(in fsnotify.Event) {
+	onConfigChange(func(in fsnotify.Event) {
 		cfg = Config{}
 		err := viper.UnmarshalKey("grpc.client.intr", &cfg)
 		if err != nil {
@@ -0,0 +1,42 @@
+package ioc
+
+import (
+	ijwt "ddd_demo/internal/web/jwt"
+	"github.com/spf13/viper"
+)
+
+func InitJWTKeys() ijwt.Keys {
+	type Config struct {
+		Access  ijwt.KeySetConfig `yaml:"access"`
+		Refresh ijwt.KeySetConfig `yaml:"refresh"`
+		State   ijwt.KeySetConfig `yaml:"state"`
+		Share   ijwt.KeySetConfig `yaml:"share"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("jwt", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	access, err := ijwt.NewKeyProvider(cfg.Access)
+	if err != nil {
+		panic(err)
+	}
+	refresh, err := ijwt.NewKeyProvider(cfg.Refresh)
+	if err != nil {
+		panic(err)
+	}
+	state, err := ijwt.NewKeyProvider(cfg.State)
+	if err != nil {
+		panic(err)
+	}
+	share, err := ijwt.NewKeyProvider(cfg.Share)
+	if err != nil {
+		panic(err)
+	}
+	return ijwt.Keys{
+		Access:  access,
+		Refresh: refresh,
+		State:   state,
+		Share:   share,
+	}
+}
@@ -2,6 +2,8 @@ package ioc
 
 import (
 	"ddd_demo/internal/events"
+	"ddd_demo/internal/events/review"
+	"ddd_demo/internal/events/search"
 	"github.com/IBM/sarama"
 	"github.com/spf13/viper"
 	"time"
@@ -49,6 +51,7 @@ func InitSyncProducer(c sarama.Client) sarama.SyncProducer {
 	return p
 }
 
-func InitConsumers() []events.Consumer {
-	return []events.Consumer{}
+func InitConsumers(searchConsumer *search.IndexConsumer,
+	reviewConsumer *review.Consumer) []events.Consumer {
+	return []events.Consumer{searchConsumer, reviewConsumer}
 }
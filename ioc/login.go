@@ -0,0 +1,61 @@
+package ioc
+
+import (
+	"ddd_demo/internal/repository"
+	"ddd_demo/internal/repository/cache"
+	"ddd_demo/internal/service"
+	"ddd_demo/pkg/limiter"
+	"ddd_demo/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+	"time"
+)
+
+func InitLoginAttemptConfig() cache.LoginAttemptConfig {
+	cfg := cache.LoginAttemptConfig{
+		Window:       time.Minute * 15,
+		DelayAfter:   3,
+		BaseDelay:    time.Second,
+		MaxDelay:     time.Minute,
+		LockAfter:    10,
+		LockDuration: time.Minute * 30,
+		IP: cache.IPAttemptConfig{
+			DelayAfter: 20,
+			BaseDelay:  time.Second,
+			MaxDelay:   time.Minute,
+		},
+	}
+	err := viper.UnmarshalKey("login.attempt", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+func InitLoginGuard(repo repository.LoginAttemptRepository,
+	cmd redis.Cmdable, l logger.LoggerV1) service.LoginGuard {
+	type Config struct {
+		// 同一个 IP 在 Interval 内最多登录 Rate 次
+		Interval time.Duration `yaml:"interval"`
+		Rate     int           `yaml:"rate"`
+	}
+	cfg := Config{
+		Interval: time.Minute,
+		Rate:     30,
+	}
+	err := viper.UnmarshalKey("login.ipLimit", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "geektime_daming",
+		Subsystem: "webook",
+		Name:      "login_guard",
+		Help:      "统计登录被限流、延迟、锁定、解锁的次数",
+	}, []string{"event"})
+	prometheus.MustRegister(counter)
+	return service.NewLoginGuard(repo,
+		limiter.NewRedisSlidingWindowLimiter(cmd, cfg.Interval, cfg.Rate),
+		l, counter)
+}
@@ -0,0 +1,45 @@
+package ioc
+
+import (
+	"ddd_demo/internal/events/review"
+	"ddd_demo/internal/service"
+	"ddd_demo/internal/service/moderation"
+	"ddd_demo/internal/service/moderation/local"
+	"ddd_demo/pkg/logger"
+	"github.com/IBM/sarama"
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// InitModerator 先用本地的关键词和正则表达式，改了配置文件不用重启就能生效。
+// 以后接第三方的审核服务的话，只需要在这里返回别的实现
+func InitModerator(l logger.LoggerV1) moderation.Moderator {
+	var cfg local.Config
+	err := viper.UnmarshalKey("moderation", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	m, err := local.NewModerator(cfg)
+	if err != nil {
+		panic(err)
+	}
+	onConfigChange(func(in fsnotify.Event) {
+		var cfg local.Config
+		err := viper.UnmarshalKey("moderation", &cfg)
+		if err == nil {
+			err = m.Reload(cfg)
+		}
+		if err != nil {
+			// 改错了不能影响正在跑的审核，继续用原来的规则
+			l.Error("重新加载审核规则失败", logger.Error(err))
+			return
+		}
+		l.Info("重新加载了审核规则")
+	})
+	return m
+}
+
+func InitReviewConsumer(svc service.ArticleReviewService,
+	client sarama.Client, l logger.LoggerV1) *review.Consumer {
+	return review.NewConsumer(svc, client, l)
+}
@@ -9,8 +9,18 @@ import (
 	"time"
 )
 
+const (
+	articleScheduledPublishJob = "article_scheduled_publish"
+	articleDraftFlushJob       = "article_draft_flush"
+	attachmentGCJob            = "attachment_gc"
+	// attachmentGCDelay 上传之后多久还没有文章引用的附件才会被清理，给作者留出写文章的时间
+	attachmentGCDelay = time.Hour * 24
+)
+
 // InitLocalFuncExecutor 初始化本地的执行器
-func InitLocalFuncExecutor(svc service.RankService) *job.LocalFuncExecutor {
+func InitLocalFuncExecutor(svc service.RankService,
+	artSvc service.ArticleService,
+	attachmentSvc service.AttachmentService) *job.LocalFuncExecutor {
 	res := job.NewLocalFuncExecutor()
 	// 要在数据库里面插入一条记录。
 	// ranking job 的记录，通过管理任务接口来插入
@@ -19,12 +29,45 @@ func InitLocalFuncExecutor(svc service.RankService) *job.LocalFuncExecutor {
 		defer cancel()
 		return svc.TopN(ctx)
 	})
+	// 定时发表文章。任务是抢占式的，同一时刻只会有一个节点在跑，
+	// 就算续约失败被别的节点抢走，DAO 发表的时候也会检查文章有没有被发表过
+	res.RegisterFunc(articleScheduledPublishJob, func(ctx context.Context, j domain.Job) error {
+		ctx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+		return artSvc.PublishDue(ctx)
+	})
+	// 把自动保存的草稿刷到数据库
+	res.RegisterFunc(articleDraftFlushJob, func(ctx context.Context, j domain.Job) error {
+		ctx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+		return artSvc.FlushDrafts(ctx)
+	})
+	// 清理没有文章引用的附件
+	res.RegisterFunc(attachmentGCJob, func(ctx context.Context, j domain.Job) error {
+		ctx, cancel := context.WithTimeout(ctx, time.Minute*5)
+		defer cancel()
+		_, err := attachmentSvc.CollectGarbage(ctx, time.Now().Add(-attachmentGCDelay))
+		return err
+	})
 	return res
 }
 
 func InitScheduler(l logger.LoggerV1,
 	local *job.LocalFuncExecutor,
 	svc service.JobService) *job.Scheduler {
+	// 定时发表、刷草稿和清理附件的任务是业务自带的，不依赖管理任务接口，启动的时候确保它们存在
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for _, j := range []domain.Job{
+		{Name: articleScheduledPublishJob, Executor: local.Name(), Cron: "@every 10s"},
+		{Name: articleDraftFlushJob, Executor: local.Name(), Cron: "@every 30s"},
+		{Name: attachmentGCJob, Executor: local.Name(), Cron: "@every 1h"},
+	} {
+		err := svc.AddJob(ctx, j)
+		if err != nil {
+			panic(err)
+		}
+	}
 	// 初始化调度器
 	res := job.NewScheduler(svc, l)
 	// 注册本地的执行器
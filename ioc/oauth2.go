@@ -0,0 +1,37 @@
+package ioc
+
+import (
+	"ddd_demo/internal/service/oauth2"
+	"ddd_demo/internal/service/oauth2/github"
+	"ddd_demo/internal/service/oauth2/oidc"
+	"fmt"
+	"github.com/spf13/viper"
+	"net/http"
+	"time"
+)
+
+// InitOAuth2Providers 加一个第三方登录只需要改配置
+func InitOAuth2Providers() *oauth2.Registry {
+	var cfgs []oauth2.Config
+	err := viper.UnmarshalKey("oauth2.providers", &cfgs)
+	if err != nil {
+		panic(err)
+	}
+	client := &http.Client{Timeout: time.Second * 10}
+	providers := make([]oauth2.Provider, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		switch cfg.Type {
+		case "github":
+			providers = append(providers, github.NewProvider(cfg, client))
+		case "oidc":
+			providers = append(providers, oidc.NewProvider(cfg, client))
+		default:
+			panic(fmt.Errorf("不支持的第三方登录类型 %s", cfg.Type))
+		}
+	}
+	res, err := oauth2.NewRegistry(providers...)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
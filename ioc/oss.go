@@ -0,0 +1,87 @@
+package ioc
+
+import (
+	"ddd_demo/pkg/ossx"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/ecodeclub/ekit"
+	"github.com/spf13/viper"
+	"os"
+)
+
+// S3Config 兼容 S3 的对象存储
+type S3Config struct {
+	Endpoint string `yaml:"endpoint"`
+	Region   string `yaml:"region"`
+	Bucket   string `yaml:"bucket"`
+	// Prefix 加在所有的 key 前面，多个业务共用一个 bucket 的时候用。导出文件用的是单独的 bucket，没有用到
+	Prefix string `yaml:"prefix"`
+	// 密钥不进配置文件，部署的时候通过这两个环境变量注入
+	SecretIdEnv  string `yaml:"secretIdEnv"`
+	SecretKeyEnv string `yaml:"secretKeyEnv"`
+}
+
+// InitArticleObjectStore 文章内容改用 dao.NewArticleS3DAO 存储的时候用。
+// 本地开发可以用 local，多个节点部署必须用 s3
+func InitArticleObjectStore() ossx.ObjectStore {
+	return initObjectStore("article.store", "./data/articles")
+}
+
+// InitAttachmentObjectStore 上传的图片和附件，和文章内容一样可以用 local 或者 s3
+func InitAttachmentObjectStore() ossx.ObjectStore {
+	return initObjectStore("attachment.store", "./data/attachments")
+}
+
+// initObjectStore key 是配置的路径，没有配置的时候用 dir 这个本地目录
+func initObjectStore(key string, dir string) ossx.ObjectStore {
+	type Config struct {
+		// Type s3 或者 local
+		Type string   `yaml:"type"`
+		Dir  string   `yaml:"dir"`
+		S3   S3Config `yaml:"s3"`
+	}
+	var cfg = Config{
+		Type: "local",
+		Dir:  dir,
+	}
+	err := viper.UnmarshalKey(key, &cfg)
+	if err != nil {
+		panic(err)
+	}
+	switch cfg.Type {
+	case "local":
+		return ossx.NewLocalStore(cfg.Dir)
+	case "s3":
+		return ossx.NewS3Store(newS3Client(key+".s3", cfg.S3), cfg.S3.Bucket, cfg.S3.Prefix)
+	default:
+		panic("不支持的对象存储类型 " + cfg.Type)
+	}
+}
+
+// newS3Client key 是配置的路径，只是用来报错
+func newS3Client(key string, cfg S3Config) *s3.S3 {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		panic("没有配置 " + key)
+	}
+	secretId, ok := os.LookupEnv(cfg.SecretIdEnv)
+	if cfg.SecretIdEnv == "" || !ok {
+		panic("找不到对象存储的 secret id " + cfg.SecretIdEnv)
+	}
+	secretKey, ok := os.LookupEnv(cfg.SecretKeyEnv)
+	if cfg.SecretKeyEnv == "" || !ok {
+		panic("找不到对象存储的 secret key " + cfg.SecretKeyEnv)
+	}
+	sess, err := session.NewSession(&aws.Config{
+		Credentials: credentials.NewStaticCredentials(secretId, secretKey, ""),
+		Region:      ekit.ToPtr[string](cfg.Region),
+		Endpoint:    ekit.ToPtr[string](cfg.Endpoint),
+		// 强制使用 /bucket/key 的形态
+		S3ForcePathStyle: ekit.ToPtr[bool](true),
+	})
+	if err != nil {
+		panic(err)
+	}
+	return s3.New(sess)
+}
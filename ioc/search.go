@@ -0,0 +1,28 @@
+package ioc
+
+import (
+	"ddd_demo/internal/events/search"
+	"ddd_demo/internal/service"
+	search2 "ddd_demo/internal/service/search"
+	"ddd_demo/internal/service/search/local"
+	"ddd_demo/pkg/logger"
+	"github.com/IBM/sarama"
+	"os"
+)
+
+// InitSearchIndex 先用本地的倒排索引，不依赖外部服务。
+// 以后换成 Elasticsearch 的话，只需要在这里返回别的实现
+func InitSearchIndex() search2.Index {
+	return local.NewIndex()
+}
+
+// InitSearchIndexConsumer 本地索引每个节点各有一份，
+// 所以消费者组带上主机名，每个节点都消费全部的消息
+func InitSearchIndexConsumer(svc service.SearchService,
+	client sarama.Client, l logger.LoggerV1) *search.IndexConsumer {
+	node, err := os.Hostname()
+	if err != nil {
+		panic(err)
+	}
+	return search.NewIndexConsumer(svc, client, node, l)
+}
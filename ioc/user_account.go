@@ -0,0 +1,30 @@
+package ioc
+
+import (
+	"ddd_demo/internal/repository/dao"
+	"ddd_demo/internal/service"
+	"github.com/spf13/viper"
+	"time"
+)
+
+func InitUserExportConfig() service.UserExportConfig {
+	cfg := service.UserExportConfig{
+		Expiration: time.Hour * 24,
+		Timeout:    time.Minute * 10,
+	}
+	err := viper.UnmarshalKey("userExport", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+// InitUserExportFileDAO 导出的文件放在对象存储上，任何一个节点都能下载
+func InitUserExportFileDAO() dao.UserExportFileDAO {
+	var cfg S3Config
+	err := viper.UnmarshalKey("userExport.s3", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return dao.NewS3UserExportFileDAO(newS3Client("userExport.s3", cfg), cfg.Bucket)
+}
@@ -28,12 +28,30 @@ import (
 func InitWebServer(mdls []gin.HandlerFunc,
 	userHdl *web.UserHandler,
 	artHdl *web.ArticleHandler,
-	wechatHdl *web.OAuth2WechatHandler) *gin.Engine {
+	wechatHdl *web.OAuth2WechatHandler,
+	oauth2Hdl *web.OAuth2Handler,
+	adminHdl *web.AdminHandler,
+	followHdl *web.FollowHandler,
+	accountHdl *web.UserAccountHandler,
+	searchHdl *web.SearchHandler,
+	commentHdl *web.CommentHandler,
+	attachmentHdl *web.AttachmentHandler,
+	seriesHdl *web.SeriesHandler,
+	bundleHdl *web.ArticleBundleHandler) *gin.Engine {
 	server := gin.Default()
 	server.Use(mdls...)
 	userHdl.RegisterRoutes(server)
 	wechatHdl.RegisterRoutes(server)
+	oauth2Hdl.RegisterRoutes(server)
 	artHdl.RegisterRoutes(server)
+	adminHdl.RegisterRoutes(server)
+	followHdl.RegisterRoutes(server)
+	accountHdl.RegisterRoutes(server)
+	searchHdl.RegisterRoutes(server)
+	commentHdl.RegisterRoutes(server)
+	attachmentHdl.RegisterRoutes(server)
+	seriesHdl.RegisterRoutes(server)
+	bundleHdl.RegisterRoutes(server)
 	return server
 }
 
@@ -3,6 +3,7 @@ package ioc
 import (
 	"ddd_demo/internal/service/oauth2/wechat"
 	"ddd_demo/pkg/logger"
+	"github.com/spf13/viper"
 	"os"
 )
 
@@ -15,5 +16,6 @@ func InitWechatService(l logger.LoggerV1) wechat.Service {
 	if !ok {
 		panic("找不到环境变量 WECHAT_APP_SECRET")
 	}
-	return wechat.NewService(appID, appSecret, l)
+	return wechat.NewService(appID, appSecret,
+		viper.GetString("oauth2.wechat.redirectURL"), l)
 }
@@ -2,6 +2,8 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"github.com/fsnotify/fsnotify"
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -29,6 +31,15 @@ func main() {
 		// 等待定时任务退出
 		<-app.cron.Stop().Done()
 	}()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		// 基于 MySQL 的分布式任务调度，定时发表文章就跑在这上面
+		err := app.scheduler.Schedule(ctx)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			zap.L().Error("任务调度退出", zap.Error(err))
+		}
+	}()
 	server := app.server
 	server.GET("/hello", func(ctx *gin.Context) {
 		ctx.String(http.StatusOK, "hello，启动成功了！")
@@ -0,0 +1,107 @@
+package diffx
+
+import "strings"
+
+type Op string
+
+const (
+	OpEqual  Op = "="
+	OpInsert Op = "+"
+	OpDelete Op = "-"
+)
+
+// Line 差异中的一行
+type Line struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// maxCells 动态规划表的上限，超过了就不再精确计算，
+// 直接认为中间那一段全删了再全加上，避免大文章把内存打爆
+const maxCells = 4 << 20
+
+// Lines 按行比较 a 和 b，返回把 a 变成 b 的编辑序列
+func Lines(a, b string) []Line {
+	return diff(split(a), split(b))
+}
+
+func split(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+func diff(a, b []string) []Line {
+	// 先去掉公共的前缀和后缀，大多数修改只动了中间一小段
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	res := make([]Line, 0, len(a)+len(b))
+	for _, l := range a[:prefix] {
+		res = append(res, Line{Op: OpEqual, Text: l})
+	}
+	res = append(res, lcs(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, l := range a[len(a)-suffix:] {
+		res = append(res, Line{Op: OpEqual, Text: l})
+	}
+	return res
+}
+
+// lcs 用最长公共子序列求编辑序列
+func lcs(a, b []string) []Line {
+	n, m := len(a), len(b)
+	if n*m > maxCells {
+		res := make([]Line, 0, n+m)
+		for _, l := range a {
+			res = append(res, Line{Op: OpDelete, Text: l})
+		}
+		for _, l := range b {
+			res = append(res, Line{Op: OpInsert, Text: l})
+		}
+		return res
+	}
+	// dp[i][j] 是 a[i:] 和 b[j:] 的最长公共子序列长度
+	dp := make([][]int32, n+1)
+	for i := range dp {
+		dp[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				dp[i][j] = dp[i+1][j+1] + 1
+			} else {
+				dp[i][j] = max(dp[i+1][j], dp[i][j+1])
+			}
+		}
+	}
+	res := make([]Line, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			res = append(res, Line{Op: OpEqual, Text: a[i]})
+			i++
+			j++
+		case dp[i+1][j] >= dp[i][j+1]:
+			res = append(res, Line{Op: OpDelete, Text: a[i]})
+			i++
+		default:
+			res = append(res, Line{Op: OpInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		res = append(res, Line{Op: OpDelete, Text: a[i]})
+	}
+	for ; j < m; j++ {
+		res = append(res, Line{Op: OpInsert, Text: b[j]})
+	}
+	return res
+}
@@ -0,0 +1,56 @@
+package diffx
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	testCases := []struct {
+		name string
+		a    string
+		b    string
+		want []Line
+	}{
+		{
+			name: "完全一样",
+			a:    "a\nb",
+			b:    "a\nb",
+			want: []Line{{Op: OpEqual, Text: "a"}, {Op: OpEqual, Text: "b"}},
+		},
+		{
+			name: "从空到有",
+			a:    "",
+			b:    "a",
+			want: []Line{{Op: OpInsert, Text: "a"}},
+		},
+		{
+			name: "改了中间一行",
+			a:    "a\nb\nc",
+			b:    "a\nd\nc",
+			want: []Line{
+				{Op: OpEqual, Text: "a"},
+				{Op: OpDelete, Text: "b"},
+				{Op: OpInsert, Text: "d"},
+				{Op: OpEqual, Text: "c"},
+			},
+		},
+		{
+			name: "增删混合",
+			a:    "a\nb\nc\nd",
+			b:    "b\nc\ne\nd",
+			want: []Line{
+				{Op: OpDelete, Text: "a"},
+				{Op: OpEqual, Text: "b"},
+				{Op: OpEqual, Text: "c"},
+				{Op: OpInsert, Text: "e"},
+				{Op: OpEqual, Text: "d"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Lines(tc.a, tc.b))
+		})
+	}
+}
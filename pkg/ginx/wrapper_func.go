@@ -11,7 +11,8 @@ import (
 
 var L logger.LoggerV1 = logger.NewNopLogger()
 
-var vector *prometheus.CounterVec
+// vector 没有调用 InitCounter 的时候（比如说单元测试）也能用，只是不会注册到 prometheus
+var vector = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "biz_code"}, []string{"code"})
 
 func InitCounter(opt prometheus.CounterOpts) {
 	vector = prometheus.NewCounterVec(opt, []string{"code"})
@@ -0,0 +1,242 @@
+package imagex
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"golang.org/x/image/draw"
+	"image"
+	// image.Decode 要注册过才认识 GIF
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+)
+
+const (
+	MimeJPEG = "image/jpeg"
+	MimePNG  = "image/png"
+	MimeGIF  = "image/gif"
+)
+
+// maxPixels 解码之前先看尺寸，防止一张很小的图片解压出来把内存打爆
+const maxPixels = 50_000_000
+
+var (
+	ErrUnsupported = errors.New("不支持的图片格式")
+	ErrTooLarge    = errors.New("图片尺寸太大")
+	errBadJPEG     = errors.New("JPEG 格式错误")
+	errBadPNG      = errors.New("PNG 格式错误")
+)
+
+// Size 图片的宽高，只读文件头
+func Size(data []byte) (int, int, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, err
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return 0, 0, ErrTooLarge
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+// Strip 去掉 EXIF 之类的元数据，里面可能有拍摄的地点和设备。
+// 尽量不重新编码：JPEG 和 PNG 只是把对应的段去掉。
+// 只有 JPEG 的 EXIF 里面带着旋转方向的时候，才会先把图片转正再编码一遍，不然去掉 EXIF 之后图片就歪了。
+// GIF 没有 EXIF，原样返回
+func Strip(data []byte, mime string) ([]byte, error) {
+	switch mime {
+	case MimeJPEG:
+		res, orientation, err := stripJPEG(data)
+		if err != nil || orientation <= 1 || orientation > 8 {
+			return res, err
+		}
+		return reorient(res, orientation)
+	case MimePNG:
+		return stripPNG(data)
+	case MimeGIF:
+		return data, nil
+	default:
+		return nil, ErrUnsupported
+	}
+}
+
+// Thumbnail 把图片等比缩小到长边不超过 maxSide。
+// JPEG 的缩略图还是 JPEG，别的都是 PNG，保留透明背景。GIF 只取第一帧
+func Thumbnail(data []byte, mime string, maxSide int) ([]byte, string, error) {
+	_, _, err := Size(data)
+	if err != nil {
+		return nil, "", err
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w >= h && w > maxSide {
+		w, h = maxSide, max(h*maxSide/w, 1)
+	} else if h > w && h > maxSide {
+		w, h = max(w*maxSide/h, 1), maxSide
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Over, nil)
+	var buf bytes.Buffer
+	if mime == MimeJPEG {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80})
+		return buf.Bytes(), MimeJPEG, err
+	}
+	err = png.Encode(&buf, dst)
+	return buf.Bytes(), MimePNG, err
+}
+
+// stripJPEG 去掉 APP1（EXIF、XMP）、APP13（IPTC）和注释，顺便读出 EXIF 里面的旋转方向
+func stripJPEG(data []byte) ([]byte, int, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, 0, errBadJPEG
+	}
+	res := make([]byte, 0, len(data))
+	res = append(res, 0xFF, 0xD8)
+	orientation := 0
+	i := 2
+	for {
+		if i+4 > len(data) || data[i] != 0xFF {
+			return nil, 0, errBadJPEG
+		}
+		marker := data[i+1]
+		// 填充字节
+		if marker == 0xFF {
+			i++
+			continue
+		}
+		// SOS 后面就是图像数据了，原样拷贝
+		if marker == 0xDA {
+			return append(res, data[i:]...), orientation, nil
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			return nil, 0, errBadJPEG
+		}
+		switch marker {
+		case 0xE1:
+			if o := exifOrientation(data[i+4 : end]); o > 0 {
+				orientation = o
+			}
+		case 0xED, 0xFE:
+		default:
+			res = append(res, data[i:end]...)
+		}
+		i = end
+	}
+}
+
+// exifOrientation EXIF 是一个 TIFF，方向在 IFD0 的 0x0112 里面。读不出来就返回 0
+func exifOrientation(seg []byte) int {
+	const header = "Exif\x00\x00"
+	if len(seg) < len(header)+8 || string(seg[:len(header)]) != header {
+		return 0
+	}
+	tiff := seg[len(header):]
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 0
+	}
+	cnt := int(order.Uint16(tiff[ifd:]))
+	for j := 0; j < cnt; j++ {
+		entry := ifd + 2 + j*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 0
+}
+
+// reorient 按照 EXIF 的方向把图片转正，orientation 的含义见 EXIF 规范
+func reorient(data []byte, orientation int) ([]byte, error) {
+	_, _, err := Size(data)
+	if err != nil {
+		return nil, err
+	}
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	b := img.Bounds()
+	src := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	// 5 到 8 要转 90 度，宽高互换
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			si, di := src.PixOffset(x, y), dst.PixOffset(dx, dy)
+			copy(dst.Pix[di:di+4], src.Pix[si:si+4])
+		}
+	}
+	var buf bytes.Buffer
+	err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 90})
+	return buf.Bytes(), err
+}
+
+// stripPNG 去掉 eXIf 和文本块，文本块里面一般是作者、软件之类的信息
+func stripPNG(data []byte) ([]byte, error) {
+	const sig = "\x89PNG\r\n\x1a\n"
+	if len(data) < len(sig) || string(data[:len(sig)]) != sig {
+		return nil, errBadPNG
+	}
+	res := make([]byte, 0, len(data))
+	res = append(res, sig...)
+	i := len(sig)
+	for i < len(data) {
+		// 长度、类型、数据、CRC
+		if i+12 > len(data) {
+			return nil, errBadPNG
+		}
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		end := i + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, errBadPNG
+		}
+		switch string(data[i+4 : i+8]) {
+		case "eXIf", "tEXt", "zTXt", "iTXt", "tIME":
+		default:
+			res = append(res, data[i:end]...)
+		}
+		i = end
+	}
+	return res, nil
+}
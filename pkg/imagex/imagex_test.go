@@ -0,0 +1,159 @@
+package imagex
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func TestStrip(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []byte
+		mime string
+
+		wantW int
+		wantH int
+	}{
+		{
+			name:  "JPEG 没有旋转",
+			data:  withExif(t, newJPEG(t, 4, 2), 1),
+			mime:  MimeJPEG,
+			wantW: 4,
+			wantH: 2,
+		},
+		{
+			// 手机竖着拍的照片，像素是横着存的
+			name:  "JPEG 要转 90 度",
+			data:  withExif(t, newJPEG(t, 4, 2), 6),
+			mime:  MimeJPEG,
+			wantW: 2,
+			wantH: 4,
+		},
+		{
+			name:  "PNG",
+			data:  withText(t, newPNG(t, 4, 2)),
+			mime:  MimePNG,
+			wantW: 4,
+			wantH: 2,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := Strip(tc.data, tc.mime)
+			require.NoError(t, err)
+			assert.NotContains(t, string(res), "Exif")
+			assert.NotContains(t, string(res), "tEXt")
+			w, h, err := Size(res)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantW, w)
+			assert.Equal(t, tc.wantH, h)
+		})
+	}
+}
+
+func TestStrip_Unsupported(t *testing.T) {
+	_, err := Strip([]byte("%PDF-1.4"), "application/pdf")
+	assert.Equal(t, ErrUnsupported, err)
+	_, err = Strip([]byte("not a jpeg"), MimeJPEG)
+	assert.Error(t, err)
+}
+
+func TestThumbnail(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []byte
+		mime string
+
+		wantMime string
+		wantW    int
+		wantH    int
+	}{
+		{
+			name:     "横着的 JPEG",
+			data:     newJPEG(t, 100, 50),
+			mime:     MimeJPEG,
+			wantMime: MimeJPEG,
+			wantW:    20,
+			wantH:    10,
+		},
+		{
+			name:     "竖着的 PNG",
+			data:     newPNG(t, 50, 100),
+			mime:     MimePNG,
+			wantMime: MimePNG,
+			wantW:    10,
+			wantH:    20,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, mime, err := Thumbnail(tc.data, tc.mime, 20)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantMime, mime)
+			w, h, err := Size(res)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantW, w)
+			assert.Equal(t, tc.wantH, h)
+		})
+	}
+}
+
+func newImage(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		img.Set(x, 0, color.RGBA{R: 255, A: 255})
+	}
+	return img
+}
+
+func newJPEG(t *testing.T, w, h int) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, newImage(w, h), nil))
+	return buf.Bytes()
+}
+
+func newPNG(t *testing.T, w, h int) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, newImage(w, h)))
+	return buf.Bytes()
+}
+
+// withExif 在 SOI 后面插入一个只有方向的 EXIF
+func withExif(t *testing.T, data []byte, orientation uint16) []byte {
+	var tiff bytes.Buffer
+	tiff.WriteString("MM")
+	for _, v := range []any{uint16(42), uint32(8),
+		// 一个条目：0x0112，SHORT，1 个
+		uint16(1), uint16(0x0112), uint16(3), uint32(1), orientation, uint16(0),
+		uint32(0)} {
+		require.NoError(t, binary.Write(&tiff, binary.BigEndian, v))
+	}
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	seg := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(seg[2:], uint16(len(payload)+2))
+	seg = append(seg, payload...)
+	res := append([]byte{}, data[:2]...)
+	res = append(res, seg...)
+	return append(res, data[2:]...)
+}
+
+// withText 在 IHDR 后面插入一个 tEXt 块
+func withText(t *testing.T, data []byte) []byte {
+	body := []byte("tEXtAuthor\x00someone")
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(body)-4))
+	chunk = append(chunk, body...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(body))
+	// 8 字节的签名加上 25 字节的 IHDR
+	const ihdrEnd = 8 + 25
+	res := append([]byte{}, data[:ihdrEnd]...)
+	res = append(res, chunk...)
+	return append(res, data[ihdrEnd:]...)
+}
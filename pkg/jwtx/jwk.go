@@ -0,0 +1,125 @@
+package jwtx
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// JWK 公钥的 JSON 表示，参考 RFC 7517
+// 这里只支持 RSA 和 P-256 的 EC 公钥
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// EC
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// Find 按照 kid 查找
+func (s JWKSet) Find(kid string) (JWK, bool) {
+	for _, k := range s.Keys {
+		if k.Kid == kid {
+			return k, true
+		}
+	}
+	return JWK{}, false
+}
+
+// NewJWK 把公钥转成 JWK
+func NewJWK(kid, alg string, pub crypto.PublicKey) (JWK, error) {
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: alg,
+			N:   encode(key.N.Bytes()),
+			E:   encode(big.NewInt(int64(key.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return JWK{}, errors.New("只支持 P-256 曲线")
+		}
+		size := (key.Curve.Params().BitSize + 7) / 8
+		return JWK{
+			Kty: "EC",
+			Kid: kid,
+			Use: "sig",
+			Alg: alg,
+			Crv: "P-256",
+			X:   encode(key.X.FillBytes(make([]byte, size))),
+			Y:   encode(key.Y.FillBytes(make([]byte, size))),
+		}, nil
+	default:
+		return JWK{}, fmt.Errorf("不支持的公钥类型 %T", pub)
+	}
+}
+
+// PublicKey 把 JWK 还原成公钥
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("不支持的曲线 %s", k.Crv)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		pub := &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, errors.New("非法的 EC 公钥")
+		}
+		return pub, nil
+	default:
+		return nil, fmt.Errorf("不支持的 key 类型 %s", k.Kty)
+	}
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decode(data string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(data)
+}
@@ -0,0 +1,122 @@
+package jwtx
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestJWK_PublicKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecJWK, err := NewJWK("ec", "ES256", &ecKey.PublicKey)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+		jwk  func(t *testing.T) JWK
+
+		wantKey crypto.PublicKey
+		wantErr bool
+	}{
+		{
+			name: "RSA",
+			jwk: func(t *testing.T) JWK {
+				jwk, err := NewJWK("rsa", "RS256", &rsaKey.PublicKey)
+				require.NoError(t, err)
+				return jwk
+			},
+			wantKey: &rsaKey.PublicKey,
+		},
+		{
+			name: "EC",
+			jwk: func(t *testing.T) JWK {
+				return ecJWK
+			},
+			wantKey: &ecKey.PublicKey,
+		},
+		{
+			name: "不认识的 key 类型",
+			jwk: func(t *testing.T) JWK {
+				return JWK{Kty: "OKP", Kid: "ed"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "不支持的曲线",
+			jwk: func(t *testing.T) JWK {
+				jwk := ecJWK
+				jwk.Crv = "P-384"
+				return jwk
+			},
+			wantErr: true,
+		},
+		{
+			// 伪造的点可能被用来做无效曲线攻击
+			name: "点不在曲线上",
+			jwk: func(t *testing.T) JWK {
+				jwk := ecJWK
+				jwk.X, jwk.Y = jwk.Y, jwk.X
+				return jwk
+			},
+			wantErr: true,
+		},
+		{
+			name: "不是 base64url",
+			jwk: func(t *testing.T) JWK {
+				return JWK{Kty: "RSA", Kid: "rsa", N: "+/=", E: "AQAB"}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := tc.jwk(t).PublicKey()
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantKey, key)
+		})
+	}
+}
+
+func TestNewJWK(t *testing.T) {
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	_, err = NewJWK("k1", "ES384", &p384.PublicKey)
+	assert.Error(t, err)
+
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, err = NewJWK("k1", "EdDSA", edPub)
+	assert.Error(t, err)
+}
+
+func TestJWKSet_Find(t *testing.T) {
+	// 和 /.well-known/jwks.json 返回的一样
+	var set JWKSet
+	err := json.Unmarshal([]byte(`{"keys":[
+		{"kty":"RSA","kid":"k1","use":"sig","alg":"RS256","n":"AQAB","e":"AQAB"},
+		{"kty":"EC","kid":"k2","use":"sig","alg":"ES256","crv":"P-256","x":"AA","y":"AA"}
+	]}`), &set)
+	require.NoError(t, err)
+
+	k, ok := set.Find("k2")
+	assert.True(t, ok)
+	assert.Equal(t, "EC", k.Kty)
+	assert.Equal(t, "ES256", k.Alg)
+
+	_, ok = set.Find("k3")
+	assert.False(t, ok)
+}
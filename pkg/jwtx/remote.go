@@ -0,0 +1,96 @@
+package jwtx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RemoteKeySet 从 JWKS 接口拉取公钥来验签
+// 其它服务用它来校验用户的 token，不需要共享密钥
+type RemoteKeySet struct {
+	url    string
+	client *http.Client
+	// 找不到 kid 的时候，最少隔这么久才会重新拉一次，防止被人用随便的 kid 打爆
+	minRefreshInterval time.Duration
+
+	mutex       sync.RWMutex
+	keys        map[string]any
+	lastRefresh time.Time
+}
+
+func NewRemoteKeySet(url string, client *http.Client) *RemoteKeySet {
+	return &RemoteKeySet{
+		url:                url,
+		client:             client,
+		minRefreshInterval: time.Minute,
+		keys:               map[string]any{},
+	}
+}
+
+// Keyfunc 可以直接传给 jwt.Parse
+func (r *RemoteKeySet) Keyfunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("token 头部缺少 kid")
+	}
+	r.mutex.RLock()
+	key, ok := r.keys[kid]
+	r.mutex.RUnlock()
+	if ok {
+		return key, nil
+	}
+	// 可能是刚刚轮换了 key，重新拉一次
+	if err := r.Refresh(context.Background()); err != nil {
+		return nil, err
+	}
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	key, ok = r.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("未知的 kid %s", kid)
+	}
+	return key, nil
+}
+
+// Refresh 重新拉取 JWKS
+func (r *RemoteKeySet) Refresh(ctx context.Context) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if time.Since(r.lastRefresh) < r.minRefreshInterval {
+		return nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("拉取 JWKS 失败，状态码 %d", resp.StatusCode)
+	}
+	var set JWKSet
+	if err = json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return err
+	}
+	keys := make(map[string]any, len(set.Keys))
+	for _, k := range set.Keys {
+		pub, er := k.PublicKey()
+		if er != nil {
+			// 不认识的 key 就跳过，不影响别的 key
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+	r.keys = keys
+	r.lastRefresh = time.Now()
+	return nil
+}
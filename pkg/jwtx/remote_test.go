@@ -0,0 +1,211 @@
+package jwtx
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRemoteKeySet_Keyfunc(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	rsaJWK := mustJWK(t, "rsa", "RS256", &rsaKey.PublicKey)
+	ecJWK := mustJWK(t, "ec", "ES256", &ecKey.PublicKey)
+	newJWK := mustJWK(t, "new", "ES256", &newKey.PublicKey)
+
+	testCases := []struct {
+		name string
+		// before 第一次拉到的 JWKS，after 之后再拉到的，模拟轮换
+		before string
+		after  string
+		// minRefreshInterval 为 0 的时候，每次找不到 kid 都会重新拉
+		minRefreshInterval time.Duration
+		// refresh 验签之前再定时刷新一次
+		refresh bool
+		token   func(t *testing.T) string
+
+		wantErr   bool
+		wantFetch int32
+	}{
+		{
+			name:      "RS256",
+			before:    jwksJSON(t, rsaJWK, ecJWK),
+			token:     signed(jwt.SigningMethodRS256, "rsa", rsaKey),
+			wantFetch: 1,
+		},
+		{
+			name:      "ES256",
+			before:    jwksJSON(t, rsaJWK, ecJWK),
+			token:     signed(jwt.SigningMethodES256, "ec", ecKey),
+			wantFetch: 1,
+		},
+		{
+			// 对方刚刚轮换了 key，缓存里面没有，重新拉一次就有了
+			name:      "轮换之后的新 kid",
+			before:    jwksJSON(t, rsaJWK, ecJWK),
+			after:     jwksJSON(t, rsaJWK, ecJWK, newJWK),
+			token:     signed(jwt.SigningMethodES256, "new", newKey),
+			wantFetch: 2,
+		},
+		{
+			// 对方已经把老的 key 删掉了，刷新之后老的 key 签出来的 token 就不能用了
+			name:      "已经轮换掉的 kid",
+			before:    jwksJSON(t, rsaJWK, ecJWK),
+			after:     jwksJSON(t, ecJWK, newJWK),
+			refresh:   true,
+			token:     signed(jwt.SigningMethodRS256, "rsa", rsaKey),
+			wantErr:   true,
+			wantFetch: 3,
+		},
+		{
+			// 随便编一个 kid 不能让我们一直去拉 JWKS
+			name:               "未知的 kid，刚刚拉过",
+			before:             jwksJSON(t, ecJWK),
+			minRefreshInterval: time.Minute,
+			token:              signed(jwt.SigningMethodES256, "unknown", ecKey),
+			wantErr:            true,
+			wantFetch:          1,
+		},
+		{
+			name:      "没有 kid",
+			before:    jwksJSON(t, ecJWK),
+			token:     signed(jwt.SigningMethodES256, "", ecKey),
+			wantErr:   true,
+			wantFetch: 1,
+		},
+		{
+			// 不认识的 key 跳过，不影响别的 key
+			name:      "JWKS 里面有不认识的 key",
+			before:    `{"keys":[{"kty":"OKP","kid":"ed"},{"kty":"EC","kid":"bad","crv":"P-256","x":"!","y":"!"},` + jwkJSON(t, ecJWK) + `]}`,
+			token:     signed(jwt.SigningMethodES256, "ec", ecKey),
+			wantFetch: 1,
+		},
+		{
+			name:      "JWKS 格式不对",
+			before:    jwksJSON(t, ecJWK),
+			after:     `{"keys":[`,
+			token:     signed(jwt.SigningMethodES256, "new", newKey),
+			wantErr:   true,
+			wantFetch: 2,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var fetch atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body := tc.before
+				if fetch.Add(1) > 1 && tc.after != "" {
+					body = tc.after
+				}
+				_, _ = w.Write([]byte(body))
+			}))
+			defer server.Close()
+			r := NewRemoteKeySet(server.URL, server.Client())
+			r.minRefreshInterval = tc.minRefreshInterval
+			require.NoError(t, r.Refresh(context.Background()))
+			if tc.refresh {
+				require.NoError(t, r.Refresh(context.Background()))
+			}
+
+			_, err := jwt.Parse(tc.token(t), r.Keyfunc)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.wantFetch, fetch.Load())
+		})
+	}
+}
+
+func TestRemoteKeySet_Refresh(t *testing.T) {
+	testCases := []struct {
+		name   string
+		status int
+		body   string
+
+		wantErr bool
+	}{
+		{
+			name:   "没有 key",
+			status: http.StatusOK,
+			body:   `{"keys":[]}`,
+		},
+		{
+			name:    "不是 JSON",
+			status:  http.StatusOK,
+			body:    `<html></html>`,
+			wantErr: true,
+		},
+		{
+			name:    "状态码不对",
+			status:  http.StatusInternalServerError,
+			body:    `{"keys":[]}`,
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+			r := NewRemoteKeySet(server.URL, server.Client())
+			err := r.Refresh(context.Background())
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func mustJWK(t *testing.T, kid, alg string, pub crypto.PublicKey) JWK {
+	jwk, err := NewJWK(kid, alg, pub)
+	require.NoError(t, err)
+	return jwk
+}
+
+func jwkJSON(t *testing.T, jwk JWK) string {
+	data, err := json.Marshal(jwk)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func jwksJSON(t *testing.T, keys ...JWK) string {
+	data, err := json.Marshal(JWKSet{Keys: keys})
+	require.NoError(t, err)
+	return string(data)
+}
+
+func signed(method jwt.SigningMethod, kid string, key crypto.Signer) func(t *testing.T) string {
+	return func(t *testing.T) string {
+		token := jwt.NewWithClaims(method, jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		})
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		tokenStr, err := token.SignedString(key)
+		require.NoError(t, err)
+		return tokenStr
+	}
+}
@@ -0,0 +1,243 @@
+package markdownx
+
+import (
+	"bytes"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"golang.org/x/net/html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// AbstractLen 摘要最多多少个字
+const AbstractLen = 128
+
+// Heading 目录里面的一项，Id 就是 HTML 里面标题的 id，前端用来跳转
+type Heading struct {
+	Level int
+	Id    string
+	Title string
+}
+
+// Result 渲染出来的东西
+type Result struct {
+	// HTML 过滤过的，可以直接展示
+	HTML string
+	// Text 去掉了所有标记的纯文本
+	Text string
+	// Abstract 从 Text 里面截出来的摘要，不会把一个单词截成两半
+	Abstract string
+	TOC      []Heading
+}
+
+var (
+	// 允许原生的 HTML，坏东西统一交给 policy 过滤
+	md = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		goldmark.WithRendererOptions(gmhtml.WithUnsafe()),
+	)
+	policy = newPolicy()
+)
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	// 默认的 policy 只允许 ASCII 的 id，中文标题的锚点会被去掉
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)).
+		OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	// 代码块的语言，前端用来高亮
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).
+		OnElements("code")
+	return p
+}
+
+// Render 把 Markdown 渲染成 HTML，顺便抽出纯文本、摘要和目录
+func Render(src string) (Result, error) {
+	source := []byte(src)
+	ctx := parser.NewContext(parser.WithIDs(newIDs()))
+	doc := md.Parser().Parse(text.NewReader(source), parser.WithContext(ctx))
+	var buf bytes.Buffer
+	err := md.Renderer().Render(&buf, source, doc)
+	if err != nil {
+		return Result{}, err
+	}
+	sanitized := policy.Sanitize(buf.String())
+	plain := plainText(sanitized)
+	return Result{
+		HTML:     sanitized,
+		Text:     plain,
+		Abstract: Abstract(plain, AbstractLen),
+		TOC:      toc(doc, source),
+	}, nil
+}
+
+func toc(doc ast.Node, source []byte) []Heading {
+	var res []Heading
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		h, ok := n.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		var id string
+		if val, ok := h.AttributeString("id"); ok {
+			if b, ok := val.([]byte); ok {
+				id = string(b)
+			}
+		}
+		res = append(res, Heading{
+			Level: h.Level,
+			Id:    id,
+			Title: headingText(h, source),
+		})
+		return ast.WalkSkipChildren, nil
+	})
+	return res
+}
+
+// headingText 标题里面的文字，不管强调、链接这些标记
+func headingText(h *ast.Heading, source []byte) string {
+	var sb strings.Builder
+	_ = ast.Walk(h, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			sb.Write(node.Segment.Value(source))
+		case *ast.String:
+			sb.Write(node.Value)
+		case *ast.AutoLink:
+			sb.Write(node.Label(source))
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.TrimSpace(sb.String())
+}
+
+// plainText 从过滤过的 HTML 里面取文字，这样原生 HTML 里面的文字也能拿到，
+// 而 script 这种已经被过滤掉了
+func plainText(sanitized string) string {
+	var sb strings.Builder
+	z := html.NewTokenizer(strings.NewReader(sanitized))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			// 只会是 io.EOF，读的是字符串
+			return normalize(sb.String())
+		case html.TextToken:
+			sb.Write(z.Text())
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			if blockTags[string(name)] {
+				sb.WriteByte('\n')
+			}
+		}
+	}
+}
+
+// blockTags 这些标签前后要换行
+var blockTags = map[string]bool{
+	"p": true, "div": true, "br": true, "hr": true, "pre": true, "blockquote": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"ul": true, "ol": true, "li": true, "table": true, "tr": true, "td": true, "th": true,
+}
+
+// normalize 每一行里面的空白压成一个空格，去掉空行
+func normalize(s string) string {
+	lines := strings.Split(s, "\n")
+	res := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line != "" {
+			res = append(res, line)
+		}
+	}
+	return strings.Join(res, "\n")
+}
+
+// Abstract 把空白压成一个空格之后截取前 n 个字。
+// 英文这种用空格分词的，往前退到单词的边界；中文每个字都可以断开
+func Abstract(plain string, n int) string {
+	runes := []rune(strings.Join(strings.Fields(plain), " "))
+	if len(runes) <= n {
+		return string(runes)
+	}
+	end := n
+	// 最多退一半，一个超长的单词就只能硬截了
+	for i := n; i > n/2; i-- {
+		if isBoundary(runes[i-1], runes[i]) {
+			end = i
+			break
+		}
+	}
+	return strings.TrimRightFunc(string(runes[:end]), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + "…"
+}
+
+// isBoundary 能不能在 prev 和 next 中间断开
+func isBoundary(prev, next rune) bool {
+	return !isWordRune(prev) || !isWordRune(next)
+}
+
+// isWordRune 拼音文字的字母和数字才会连成单词，汉字、假名这些自己就是一个词
+func isWordRune(r rune) bool {
+	if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+		return false
+	}
+	return unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_' || r == '\''
+}
+
+// ids 生成标题的 id，和 goldmark 默认的不一样，保留中文
+type ids struct {
+	used map[string]struct{}
+}
+
+func newIDs() *ids {
+	return &ids{used: make(map[string]struct{})}
+}
+
+func (s *ids) Generate(value []byte, kind ast.NodeKind) []byte {
+	var sb strings.Builder
+	lastDash := false
+	for len(value) > 0 {
+		r, size := utf8.DecodeRune(value)
+		value = value[size:]
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			sb.WriteRune(unicode.ToLower(r))
+			lastDash = false
+		case (unicode.IsSpace(r) || r == '-' || r == '_') && sb.Len() > 0 && !lastDash:
+			sb.WriteByte('-')
+			lastDash = true
+		}
+	}
+	id := strings.TrimSuffix(sb.String(), "-")
+	if id == "" {
+		id = "heading"
+	}
+	// 重名的标题加上序号
+	res := id
+	for i := 1; ; i++ {
+		if _, ok := s.used[res]; !ok {
+			break
+		}
+		res = id + "-" + strconv.Itoa(i)
+	}
+	s.used[res] = struct{}{}
+	return []byte(res)
+}
+
+func (s *ids) Put(value []byte) {
+	s.used[string(value)] = struct{}{}
+}
@@ -0,0 +1,114 @@
+package markdownx
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	testCases := []struct {
+		name     string
+		src      string
+		wantHTML string
+		wantText string
+		wantTOC  []Heading
+	}{
+		{
+			name:     "基本语法",
+			src:      "Some **bold** text and `code`.\n\n- a\n- b",
+			wantHTML: "<p>Some <strong>bold</strong> text and <code>code</code>.</p>\n<ul>\n<li>a</li>\n<li>b</li>\n</ul>\n",
+			wantText: "Some bold text and code.\na\nb",
+		},
+		{
+			name:     "中文和重名的标题",
+			src:      "# 你好 世界\n\n## Hello World\n\n## Hello World",
+			wantHTML: "<h1 id=\"你好-世界\">你好 世界</h1>\n<h2 id=\"hello-world\">Hello World</h2>\n<h2 id=\"hello-world-1\">Hello World</h2>\n",
+			wantText: "你好 世界\nHello World\nHello World",
+			wantTOC: []Heading{
+				{Level: 1, Id: "你好-世界", Title: "你好 世界"},
+				{Level: 2, Id: "hello-world", Title: "Hello World"},
+				{Level: 2, Id: "hello-world-1", Title: "Hello World"},
+			},
+		},
+		{
+			name:     "过滤脚本和事件",
+			src:      "<script>alert(1)</script>\n\n<b onclick=\"alert(1)\">hi</b>",
+			wantHTML: "\n<p><b>hi</b></p>\n",
+			wantText: "hi",
+		},
+		{
+			name:     "行内的脚本",
+			src:      "**Go** 语言<script>alert(1)</script>",
+			wantHTML: "<p><strong>Go</strong> 语言</p>\n",
+			wantText: "Go 语言",
+		},
+		{
+			name:     "过滤 javascript 链接",
+			src:      "[点我](javascript:alert(1))",
+			wantHTML: "<p>点我</p>\n",
+			wantText: "点我",
+		},
+		{
+			name:     "代码块保留语言",
+			src:      "```go\nfmt.Println(1)\n```",
+			wantHTML: "<pre><code class=\"language-go\">fmt.Println(1)\n</code></pre>\n",
+			wantText: "fmt.Println(1)",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := Render(tc.src)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantHTML, res.HTML)
+			assert.Equal(t, tc.wantText, res.Text)
+			assert.Equal(t, tc.wantTOC, res.TOC)
+		})
+	}
+}
+
+func TestAbstract(t *testing.T) {
+	testCases := []struct {
+		name  string
+		plain string
+		n     int
+		want  string
+	}{
+		{
+			name:  "不用截",
+			plain: "hello\nworld",
+			n:     20,
+			want:  "hello world",
+		},
+		{
+			name:  "英文退到单词边界",
+			plain: "hello wonderful world",
+			n:     10,
+			want:  "hello…",
+		},
+		{
+			name:  "中文直接截",
+			plain: "今天天气很好，适合出去玩",
+			n:     5,
+			want:  "今天天气很…",
+		},
+		{
+			name:  "去掉结尾的标点",
+			plain: "今天天气很好，适合出去玩",
+			n:     7,
+			want:  "今天天气很好…",
+		},
+		{
+			name:  "超长的单词只能硬截",
+			plain: strings.Repeat("a", 20),
+			n:     10,
+			want:  strings.Repeat("a", 10) + "…",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Abstract(tc.plain, tc.n))
+		})
+	}
+}
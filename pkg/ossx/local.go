@@ -0,0 +1,76 @@
+package ossx
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LocalStore 放在本地磁盘上，只能在单机部署或者开发测试的时候用
+type LocalStore struct {
+	dir string
+}
+
+func NewLocalStore(dir string) *LocalStore {
+	return &LocalStore{dir: dir}
+}
+
+// Put 先写临时文件再改名，Get 不会读到写了一半的文件
+func (s *LocalStore) Put(ctx context.Context, key string, body io.ReadSeeker, contentType string) error {
+	path := s.path(key)
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, body)
+	if err == nil {
+		err = f.Close()
+	} else {
+		_ = f.Close()
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+	return err
+}
+
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(s.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrObjectNotFound
+	}
+	return f, err
+}
+
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	err := os.Remove(s.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Presign 本地文件没有过期的说法，返回的是 file:// 链接，只有同一台机器上能打开
+func (s *LocalStore) Presign(ctx context.Context, key string, expiration time.Duration) (string, error) {
+	path, err := filepath.Abs(s.path(key))
+	if err != nil {
+		return "", err
+	}
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+	return u.String(), nil
+}
+
+func (s *LocalStore) path(key string) string {
+	// 防一下 ../，不能跑到 dir 外面去
+	return filepath.Join(s.dir, filepath.Clean("/"+key))
+}
@@ -0,0 +1,55 @@
+package ossx
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalStore(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocalStore(dir)
+	ctx := context.Background()
+
+	err := store.Put(ctx, "articles/1/2", bytes.NewReader([]byte("第一版")), "text/plain")
+	require.NoError(t, err)
+	// 覆盖
+	err = store.Put(ctx, "articles/1/2", bytes.NewReader([]byte("第二版")), "text/plain")
+	require.NoError(t, err)
+	rc, err := store.Get(ctx, "articles/1/2")
+	require.NoError(t, err)
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	assert.Equal(t, "第二版", string(data))
+
+	// 临时文件都改名了
+	entries, err := os.ReadDir(filepath.Join(dir, "articles", "1"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	url, err := store.Presign(ctx, "articles/1/2", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(url, "file://"))
+	assert.True(t, strings.HasSuffix(url, "/articles/1/2"))
+
+	require.NoError(t, store.Delete(ctx, "articles/1/2"))
+	// 删除不存在的也不会报错
+	require.NoError(t, store.Delete(ctx, "articles/1/2"))
+	_, err = store.Get(ctx, "articles/1/2")
+	assert.Equal(t, ErrObjectNotFound, err)
+}
+
+// TestLocalStore_path key 里面的 ../ 不能跑到目录外面去
+func TestLocalStore_path(t *testing.T) {
+	store := NewLocalStore("/data/oss")
+	assert.Equal(t, "/data/oss/etc/passwd", store.path("../../etc/passwd"))
+	assert.Equal(t, "/data/oss/a/b", store.path("a/./b"))
+}
@@ -0,0 +1,70 @@
+package ossx
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/ecodeclub/ekit"
+	"io"
+	"time"
+)
+
+// S3Store 兼容 S3 的对象存储，腾讯云的 COS、阿里云的 OSS 都可以用。
+// prefix 会加在所有的 key 前面，多个业务可以共用一个 bucket
+type S3Store struct {
+	client *s3.S3
+	bucket string
+	prefix string
+}
+
+func NewS3Store(client *s3.S3, bucket string, prefix string) *S3Store {
+	return &S3Store{
+		client: client,
+		bucket: bucket,
+		prefix: prefix,
+	}
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, body io.ReadSeeker, contentType string) error {
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      ekit.ToPtr[string](s.bucket),
+		Key:         ekit.ToPtr[string](s.prefix + key),
+		Body:        body,
+		ContentType: ekit.ToPtr[string](contentType),
+	})
+	return err
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	res, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: ekit.ToPtr[string](s.bucket),
+		Key:    ekit.ToPtr[string](s.prefix + key),
+	})
+	var aerr awserr.Error
+	if errors.As(err, &aerr) && aerr.Code() == s3.ErrCodeNoSuchKey {
+		return nil, ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: ekit.ToPtr[string](s.bucket),
+		Key:    ekit.ToPtr[string](s.prefix + key),
+	})
+	return err
+}
+
+// Presign 只是在本地签名，不会发请求，所以对象不存在也能拿到链接
+func (s *S3Store) Presign(ctx context.Context, key string, expiration time.Duration) (string, error) {
+	req, _ := s.client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: ekit.ToPtr[string](s.bucket),
+		Key:    ekit.ToPtr[string](s.prefix + key),
+	})
+	req.SetContext(ctx)
+	return req.Presign(expiration)
+}
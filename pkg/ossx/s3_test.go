@@ -0,0 +1,35 @@
+package ossx
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/ecodeclub/ekit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// TestS3Store_Presign 签名不需要连上对象存储
+func TestS3Store_Presign(t *testing.T) {
+	sess, err := session.NewSession(&aws.Config{
+		Credentials:      credentials.NewStaticCredentials("id", "key", ""),
+		Region:           ekit.ToPtr[string]("ap-nanjing"),
+		Endpoint:         ekit.ToPtr[string]("https://cos.ap-nanjing.myqcloud.com"),
+		S3ForcePathStyle: ekit.ToPtr[bool](true),
+	})
+	require.NoError(t, err)
+	store := NewS3Store(s3.New(sess), "webook", "articles/")
+	raw, err := store.Presign(context.Background(), "1/2", time.Minute)
+	require.NoError(t, err)
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "cos.ap-nanjing.myqcloud.com", u.Host)
+	// prefix 加在 key 前面
+	assert.Equal(t, "/webook/articles/1/2", u.Path)
+	assert.Equal(t, "60", u.Query().Get("X-Amz-Expires"))
+}
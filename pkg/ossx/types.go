@@ -0,0 +1,29 @@
+package ossx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+var ErrObjectNotFound = errors.New("对象不存在")
+
+// ObjectStore 对象存储，key 里面可以有 /
+//
+//go:generate mockgen -source=./types.go -package=ossxmocks -destination=./mocks/ossx.mock.go ObjectStore
+type ObjectStore interface {
+	// Put 已经存在的会被覆盖
+	Put(ctx context.Context, key string, body io.ReadSeeker, contentType string) error
+	// Get 对象不存在返回 ErrObjectNotFound，调用方要负责关闭
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete 对象不存在也不会返回 error
+	Delete(ctx context.Context, key string) error
+	// Presign 生成一个 expiration 之内有效的下载链接，不需要鉴权就能直接下载
+	Presign(ctx context.Context, key string, expiration time.Duration) (string, error)
+}
+
+var (
+	_ ObjectStore = &S3Store{}
+	_ ObjectStore = &LocalStore{}
+)
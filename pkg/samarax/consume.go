@@ -0,0 +1,29 @@
+package samarax
+
+import (
+	"context"
+	"ddd_demo/pkg/logger"
+	"errors"
+	"github.com/IBM/sarama"
+	"strings"
+	"time"
+)
+
+// ConsumeLoop 每次 rebalance 或者 RetryHandler 放弃的时候 Consume 都会返回，
+// 要在循环里面重新加入消费者组，不然消息就悄悄地不再被消费了
+func ConsumeLoop(cg sarama.ConsumerGroup, topics []string,
+	handler sarama.ConsumerGroupHandler, l logger.LoggerV1) {
+	for {
+		err := cg.Consume(context.Background(), topics, handler)
+		if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+			l.Info("消费者组已关闭，退出消费", logger.String("topics", strings.Join(topics, ",")))
+			return
+		}
+		if err != nil {
+			l.Error("消费过程中出现错误，稍后重新加入",
+				logger.String("topics", strings.Join(topics, ",")),
+				logger.Error(err))
+			time.Sleep(time.Second)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package samarax
+
+import (
+	"ddd_demo/pkg/logger"
+	"encoding/json"
+	"github.com/IBM/sarama"
+	"time"
+)
+
+// RetryHandler 处理失败的消息会在本地重试，重试耗尽了也不会提交，
+// 而是让 ConsumeClaim 返回错误，结束这一轮 session。
+// 调用方要在循环里面调用 ConsumerGroup.Consume，重新加入之后会从这条消息开始再消费一遍，
+// 所以 fn 必须是幂等的。适合合并账号、注销账号这种丢了就没法补的消息
+type RetryHandler[T any] struct {
+	l  logger.LoggerV1
+	fn func(msg *sarama.ConsumerMessage, event T) error
+	// 本地重试的次数
+	maxRetry int
+	// 第一次重试的间隔，之后每次翻倍
+	interval time.Duration
+}
+
+func NewRetryHandler[T any](l logger.LoggerV1,
+	fn func(msg *sarama.ConsumerMessage, event T) error) *RetryHandler[T] {
+	return &RetryHandler[T]{
+		l:        l,
+		fn:       fn,
+		maxRetry: 3,
+		interval: time.Second,
+	}
+}
+
+func (h *RetryHandler[T]) Setup(session sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *RetryHandler[T]) Cleanup(session sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *RetryHandler[T]) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for msg := range claim.Messages() {
+		var t T
+		err := json.Unmarshal(msg.Value, &t)
+		if err != nil {
+			// 消息本身就是坏的，重试也没有用
+			h.l.Error("反序列消息体失败",
+				logger.String("topic", msg.Topic),
+				logger.Int32("partition", msg.Partition),
+				logger.Int64("offset", msg.Offset),
+				logger.Error(err))
+			session.MarkMessage(msg, "")
+			continue
+		}
+		err = h.handle(session, msg, t)
+		if err != nil {
+			h.l.Error("处理消息失败，重试耗尽，等待重新消费",
+				logger.String("topic", msg.Topic),
+				logger.Int32("partition", msg.Partition),
+				logger.Int64("offset", msg.Offset),
+				logger.Error(err))
+			return err
+		}
+		session.MarkMessage(msg, "")
+	}
+	return nil
+}
+
+func (h *RetryHandler[T]) handle(session sarama.ConsumerGroupSession,
+	msg *sarama.ConsumerMessage, t T) error {
+	interval := h.interval
+	err := h.fn(msg, t)
+	for i := 0; err != nil && i < h.maxRetry; i++ {
+		h.l.Warn("处理消息失败，准备重试",
+			logger.String("topic", msg.Topic),
+			logger.Int64("offset", msg.Offset),
+			logger.Int("retry", i+1),
+			logger.Error(err))
+		select {
+		case <-session.Context().Done():
+			return session.Context().Err()
+		case <-time.After(interval):
+		}
+		interval *= 2
+		err = h.fn(msg, t)
+	}
+	return err
+}
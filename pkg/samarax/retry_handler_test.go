@@ -0,0 +1,74 @@
+package samarax
+
+import (
+	"context"
+	"ddd_demo/pkg/logger"
+	"errors"
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRetryHandler_ConsumeClaim(t *testing.T) {
+	testCases := []struct {
+		name string
+		// 前面失败几次
+		failures   int
+		wantErr    bool
+		wantMarked []int64
+	}{
+		{name: "一次成功", failures: 0, wantMarked: []int64{1, 2}},
+		{name: "重试之后成功", failures: 2, wantMarked: []int64{1, 2}},
+		{name: "重试耗尽，不提交", failures: 10, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+			h := NewRetryHandler[testEvent](logger.NewNopLogger(),
+				func(msg *sarama.ConsumerMessage, evt testEvent) error {
+					calls++
+					if calls <= tc.failures {
+						return errors.New("mock 错误")
+					}
+					return nil
+				})
+			h.interval = time.Millisecond
+			sess := &fakeSession{ctx: context.Background()}
+			claim := &fakeClaim{msgs: make(chan *sarama.ConsumerMessage, 2)}
+			claim.msgs <- &sarama.ConsumerMessage{Offset: 1, Value: []byte(`{"id":1}`)}
+			claim.msgs <- &sarama.ConsumerMessage{Offset: 2, Value: []byte(`{"id":2}`)}
+			close(claim.msgs)
+			err := h.ConsumeClaim(sess, claim)
+			assert.Equal(t, tc.wantErr, err != nil)
+			assert.Equal(t, tc.wantMarked, sess.marked)
+		})
+	}
+}
+
+type testEvent struct {
+	Id int64 `json:"id"`
+}
+
+type fakeSession struct {
+	sarama.ConsumerGroupSession
+	ctx    context.Context
+	marked []int64
+}
+
+func (f *fakeSession) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	f.marked = append(f.marked, msg.Offset)
+}
+
+type fakeClaim struct {
+	sarama.ConsumerGroupClaim
+	msgs chan *sarama.ConsumerMessage
+}
+
+func (f *fakeClaim) Messages() <-chan *sarama.ConsumerMessage {
+	return f.msgs
+}
@@ -0,0 +1,86 @@
+// Package totp 基于时间的一次性密码，参考 RFC 6238。
+// 和 Google Authenticator 之类的 App 兼容：HMAC-SHA1，6 位数字，30 秒一个周期
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	digits = 6
+	period = 30
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret 生成一个 160 位的随机密钥，base32 编码
+func GenerateSecret() (string, error) {
+	data := make([]byte, 20)
+	_, err := rand.Read(data)
+	if err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(data), nil
+}
+
+// URI 生成 otpauth URI，App 扫描这个 URI 转成的二维码就可以绑定
+func URI(issuer, account, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(digits))
+	query.Set("period", fmt.Sprint(period))
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// Counter t 所在的时间周期
+func Counter(t time.Time) int64 {
+	return t.Unix() / period
+}
+
+// Code 计算某个时间周期的验证码
+func Code(secret string, counter int64) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimSpace(secret)))
+	if err != nil {
+		return "", err
+	}
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(counter))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+	// RFC 4226 的动态截断
+	offset := sum[len(sum)-1] & 0x0f
+	val := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", digits, val%1000000), nil
+}
+
+// Validate 校验验证码，允许前后 skew 个周期的时钟误差。
+// 校验通过的话返回验证码对应的时间周期，调用方要记下来防止同一个验证码被用两次
+func Validate(secret, code string, t time.Time, skew int) (int64, bool) {
+	if len(code) != digits {
+		return 0, false
+	}
+	cur := Counter(t)
+	for i := -skew; i <= skew; i++ {
+		expected, err := Code(secret, cur+int64(i))
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return cur + int64(i), true
+		}
+	}
+	return 0, false
+}
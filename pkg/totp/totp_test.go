@@ -0,0 +1,53 @@
+package totp
+
+import (
+	"encoding/base32"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestCode(t *testing.T) {
+	// RFC 6238 附录 B 的 SHA1 测试数据，取后 6 位
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	testCases := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+	for _, tc := range testCases {
+		code, err := Code(secret, Counter(time.Unix(tc.unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, code)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	now := time.Now()
+	code, err := Code(secret, Counter(now.Add(-time.Second*30)))
+	require.NoError(t, err)
+
+	// 上一个周期的验证码，允许一个周期的误差
+	counter, ok := Validate(secret, code, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, Counter(now)-1, counter)
+
+	_, ok = Validate(secret, code, now, 0)
+	assert.False(t, ok)
+	_, ok = Validate(secret, "12345", now, 1)
+	assert.False(t, ok)
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("webook", "abc@qq.com", "JBSWY3DPEHPK3PXP")
+	assert.Equal(t, "otpauth://totp/webook:abc@qq.com?algorithm=SHA1&digits=6&issuer=webook&period=30&secret=JBSWY3DPEHPK3PXP", uri)
+}
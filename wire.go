@@ -8,6 +8,9 @@ import (
 	dao2 "ddd_demo/interactive/repository/dao"
 	service2 "ddd_demo/interactive/service"
 	"ddd_demo/internal/events/article"
+	"ddd_demo/internal/events/comment"
+	"ddd_demo/internal/events/follow"
+	"ddd_demo/internal/events/user"
 	"ddd_demo/internal/repository"
 	"ddd_demo/internal/repository/cache"
 	"ddd_demo/internal/repository/dao"
@@ -42,7 +45,16 @@ func InitWebServer() *App {
 		ioc.InitRlockClient,
 		// DAO 部分
 		dao.NewUserDAO,
+		dao.NewGORMIdentityDAO,
+		dao.NewGORMTOTPDAO,
 		dao.NewArticleGORMDAO,
+		dao.NewGORMFollowRelationDAO,
+		dao.NewGORMHistoryRecordDAO,
+		dao.NewGORMJobDAO,
+		dao.NewGORMCommentDAO,
+		dao.NewGORMAttachmentDAO,
+		dao.NewGORMArticleReviewDAO,
+		dao.NewGORMSeriesDAO,
 
 		//interactiveSvcSet,
 		//ioc.InitIntrClient,
@@ -50,32 +62,88 @@ func InitWebServer() *App {
 		rankingSvcSet,
 		ioc.InitRankingJob,
 		ioc.InitJobs,
+		ioc.InitLocalFuncExecutor,
+		ioc.InitScheduler,
 
 		article.NewSaramaSyncProducer,
+		user.NewSaramaSyncProducer,
+		follow.NewSaramaSyncProducer,
+		comment.NewSaramaSyncProducer,
 		//events.NewInteractiveReadEventConsumer,
+		ioc.InitSearchIndexConsumer,
+		ioc.InitReviewConsumer,
 		ioc.InitConsumers,
 
 		// cache 部分
 		cache.NewCodeCache, cache.NewUserCache,
+		cache.NewOneTimeTokenCache,
+		ioc.InitLoginAttemptConfig,
+		cache.NewLoginAttemptCache,
 		cache.NewArticleRedisCache,
+		cache.NewRedisFollowCache,
+		cache.NewRedisUserExportCache,
+		cache.NewRedisArticleImportCache,
 
 		// repository 部分
 		repository.NewCachedUserRepository,
+		repository.NewIdentityRepository,
+		repository.NewTOTPRepository,
 		repository.NewCodeRepository,
+		repository.NewOneTimeTokenRepository,
+		repository.NewLoginAttemptRepository,
 		repository.NewCachedArticleRepository,
+		repository.NewCachedFollowRepository,
+		repository.NewHistoryRecordRepository,
+		repository.NewUserExportRepository,
+		repository.NewPreemptCronJobRepository,
+		repository.NewCommentRepository,
+		repository.NewCachedAttachmentRepository,
+		repository.NewArticleReviewRepository,
+		repository.NewSeriesRepository,
+		repository.NewArticleImportRepository,
 
 		// Service 部分
 		ioc.InitSMSService,
+		ioc.InitEmailService,
 		ioc.InitWechatService,
+		ioc.InitOAuth2Providers,
 		service.NewUserService,
 		service.NewCodeService,
 		service.NewArticleService,
+		ioc.InitModerator,
+		service.NewArticleReviewService,
+		service.NewSeriesService,
+		ioc.InitArticleImportConfig,
+		service.NewArticleBundleService,
+		service.NewUserMergeService,
+		service.NewFollowService,
+		ioc.InitUserExportConfig,
+		ioc.InitUserExportFileDAO,
+		service.NewUserAccountService,
+		service.NewCronJobService,
+		ioc.InitSearchIndex,
+		service.NewSearchService,
+		service.NewCommentService,
+		ioc.InitAttachmentObjectStore,
+		service.NewAttachmentService,
+		ioc.InitLoginGuard,
 
 		// handler 部分
 		web.NewUserHandler,
 		web.NewArticleHandler,
+		ioc.InitJWTKeys,
 		ijwt.NewRedisJWTHandler,
 		web.NewOAuth2WechatHandler,
+		web.NewOAuth2Handler,
+		ioc.InitAdminMiddleware,
+		web.NewAdminHandler,
+		web.NewFollowHandler,
+		web.NewUserAccountHandler,
+		web.NewSearchHandler,
+		web.NewCommentHandler,
+		web.NewAttachmentHandler,
+		web.NewSeriesHandler,
+		web.NewArticleBundleHandler,
 		ioc.InitGinMiddlewares,
 		ioc.InitWebServer,
 
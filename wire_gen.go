@@ -12,6 +12,9 @@ import (
 	dao2 "ddd_demo/interactive/repository/dao"
 	service2 "ddd_demo/interactive/service"
 	"ddd_demo/internal/events/article"
+	"ddd_demo/internal/events/comment"
+	"ddd_demo/internal/events/follow"
+	"ddd_demo/internal/events/user"
 	"ddd_demo/internal/repository"
 	"ddd_demo/internal/repository/cache"
 	"ddd_demo/internal/repository/dao"
@@ -30,43 +33,109 @@ import (
 
 func InitWebServer() *App {
 	cmdable := ioc.InitRedis()
-	handler := jwt.NewRedisJWTHandler(cmdable)
 	loggerV1 := ioc.InitLogger()
+	keys := ioc.InitJWTKeys()
+	handler := jwt.NewRedisJWTHandler(cmdable, loggerV1, keys)
 	v := ioc.InitGinMiddlewares(cmdable, handler, loggerV1)
 	db := ioc.InitDB(loggerV1)
 	userDAO := dao.NewUserDAO(db)
 	userCache := cache.NewUserCache(cmdable)
 	userRepository := repository.NewCachedUserRepository(userDAO, userCache)
-	userService := service.NewUserService(userRepository)
+	identityDAO := dao.NewGORMIdentityDAO(db)
+	identityRepository := repository.NewIdentityRepository(identityDAO)
+	totpdao := dao.NewGORMTOTPDAO(db)
+	totpRepository := repository.NewTOTPRepository(totpdao)
+	oneTimeTokenCache := cache.NewOneTimeTokenCache(cmdable)
+	oneTimeTokenRepository := repository.NewOneTimeTokenRepository(oneTimeTokenCache)
+	emailService := ioc.InitEmailService()
+	userService := service.NewUserService(userRepository, identityRepository, totpRepository, oneTimeTokenRepository, emailService)
 	codeCache := cache.NewCodeCache(cmdable)
 	codeRepository := repository.NewCodeRepository(codeCache)
 	smsService := ioc.InitSMSService()
 	codeService := service.NewCodeService(codeRepository, smsService)
-	userHandler := web.NewUserHandler(userService, handler, codeService)
+	loginAttemptConfig := ioc.InitLoginAttemptConfig()
+	loginAttemptCache := cache.NewLoginAttemptCache(cmdable, loginAttemptConfig)
+	loginAttemptRepository := repository.NewLoginAttemptRepository(loginAttemptCache)
+	loginGuard := ioc.InitLoginGuard(loginAttemptRepository, cmdable, loggerV1)
+	registry := ioc.InitOAuth2Providers()
+	userHandler := web.NewUserHandler(userService, handler, codeService, loginGuard, registry)
 	articleDAO := dao.NewArticleGORMDAO(db)
 	articleCache := cache.NewArticleRedisCache(cmdable)
-	articleRepository := repository.NewCachedArticleRepository(articleDAO, userRepository, articleCache)
+	articleRepository := repository.NewCachedArticleRepository(articleDAO, userRepository, articleCache, loggerV1)
+	followRelationDAO := dao.NewGORMFollowRelationDAO(db)
+	followCache := cache.NewRedisFollowCache(cmdable)
+	followRepository := repository.NewCachedFollowRepository(followRelationDAO, followCache, loggerV1)
 	client := ioc.InitSaramaClient()
 	syncProducer := ioc.InitSyncProducer(client)
 	producer := article.NewSaramaSyncProducer(syncProducer)
-	articleService := service.NewArticleService(articleRepository, producer)
+	articleService := service.NewArticleService(articleRepository, followRepository, producer, loggerV1)
+	seriesDAO := dao.NewGORMSeriesDAO(db)
+	seriesRepository := repository.NewSeriesRepository(seriesDAO)
+	seriesService := service.NewSeriesService(seriesRepository, articleRepository, followRepository)
 	clientv3Client := ioc.InitEtcd()
 	interactiveServiceClient := ioc.InitIntrClientV1(clientv3Client)
-	articleHandler := web.NewArticleHandler(loggerV1, articleService, interactiveServiceClient)
+	articleHandler := web.NewArticleHandler(loggerV1, articleService, seriesService, interactiveServiceClient, keys)
 	wechatService := ioc.InitWechatService(loggerV1)
-	oAuth2WechatHandler := web.NewOAuth2WechatHandler(wechatService, handler, userService)
-	engine := ioc.InitWebServer(v, userHandler, articleHandler, oAuth2WechatHandler)
-	v2 := ioc.InitConsumers()
+	oAuth2WechatHandler := web.NewOAuth2WechatHandler(wechatService, handler, userService, keys)
+	oAuth2Handler := web.NewOAuth2Handler(registry, handler, userService, keys)
+	userProducer := user.NewSaramaSyncProducer(syncProducer)
+	userMergeService := service.NewUserMergeService(userRepository, articleRepository, userProducer)
+	articleReviewDAO := dao.NewGORMArticleReviewDAO(db)
+	articleReviewRepository := repository.NewArticleReviewRepository(articleReviewDAO)
+	moderator := ioc.InitModerator(loggerV1)
+	articleReviewService := service.NewArticleReviewService(articleRepository, articleReviewRepository, userRepository, moderator, producer, emailService, loggerV1)
+	adminMiddlewareBuilder := ioc.InitAdminMiddleware()
+	adminHandler := web.NewAdminHandler(userMergeService, articleReviewService, handler, adminMiddlewareBuilder)
+	followProducer := follow.NewSaramaSyncProducer(syncProducer)
+	followService := service.NewFollowService(followRepository, userRepository, followProducer, loggerV1)
+	followHandler := web.NewFollowHandler(followService, userService, articleService)
+	historyRecordDAO := dao.NewGORMHistoryRecordDAO(db)
+	historyRecordRepository := repository.NewHistoryRecordRepository(historyRecordDAO)
+	userExportCache := cache.NewRedisUserExportCache(cmdable)
+	userExportFileDAO := ioc.InitUserExportFileDAO()
+	userExportRepository := repository.NewUserExportRepository(userExportCache, userExportFileDAO)
+	userExportConfig := ioc.InitUserExportConfig()
+	userAccountService := service.NewUserAccountService(userRepository, identityRepository, articleRepository, historyRecordRepository, userExportRepository, interactiveServiceClient, userProducer, userExportConfig, loggerV1)
+	userAccountHandler := web.NewUserAccountHandler(userAccountService, handler, loggerV1)
+	index := ioc.InitSearchIndex()
+	searchService := service.NewSearchService(index, articleRepository, userRepository, loggerV1)
+	searchHandler := web.NewSearchHandler(searchService)
+	commentDAO := dao.NewGORMCommentDAO(db)
+	commentRepository := repository.NewCommentRepository(commentDAO)
+	commentProducer := comment.NewSaramaSyncProducer(syncProducer)
+	commentService := service.NewCommentService(commentRepository, articleRepository, commentProducer, loggerV1)
+	commentHandler := web.NewCommentHandler(commentService, userService)
+	attachmentDAO := dao.NewGORMAttachmentDAO(db)
+	attachmentRepository := repository.NewCachedAttachmentRepository(attachmentDAO)
+	objectStore := ioc.InitAttachmentObjectStore()
+	attachmentService := service.NewAttachmentService(attachmentRepository, articleRepository, objectStore, loggerV1)
+	attachmentHandler := web.NewAttachmentHandler(attachmentService, loggerV1)
+	seriesHandler := web.NewSeriesHandler(seriesService, interactiveServiceClient)
+	articleImportCache := cache.NewRedisArticleImportCache(cmdable)
+	articleImportRepository := repository.NewArticleImportRepository(articleImportCache)
+	articleImportConfig := ioc.InitArticleImportConfig()
+	articleBundleService := service.NewArticleBundleService(articleService, articleRepository, articleImportRepository, articleImportConfig, loggerV1)
+	articleBundleHandler := web.NewArticleBundleHandler(articleBundleService, loggerV1)
+	engine := ioc.InitWebServer(v, userHandler, articleHandler, oAuth2WechatHandler, oAuth2Handler, adminHandler, followHandler, userAccountHandler, searchHandler, commentHandler, attachmentHandler, seriesHandler, articleBundleHandler)
+	indexConsumer := ioc.InitSearchIndexConsumer(searchService, client, loggerV1)
+	consumer := ioc.InitReviewConsumer(articleReviewService, client, loggerV1)
+	v2 := ioc.InitConsumers(indexConsumer, consumer)
 	rankingCache := cache.NewRankingRedisCache(cmdable)
 	rankingRepository := repository.NewCachedRankingRepository(rankingCache)
 	rankService := service.NewBatchRankingService(interactiveServiceClient, articleService, rankingRepository)
 	rlockClient := ioc.InitRlockClient(cmdable)
 	rankingJob := ioc.InitRankingJob(rankService, loggerV1, rlockClient)
 	cron := ioc.InitJobs(loggerV1, rankingJob)
+	localFuncExecutor := ioc.InitLocalFuncExecutor(rankService, articleService, attachmentService)
+	jobDAO := dao.NewGORMJobDAO(db)
+	jobRepository := repository.NewPreemptCronJobRepository(jobDAO)
+	jobService := service.NewCronJobService(jobRepository, loggerV1)
+	scheduler := ioc.InitScheduler(loggerV1, localFuncExecutor, jobService)
 	app := &App{
 		server:    engine,
 		consumers: v2,
 		cron:      cron,
+		scheduler: scheduler,
 	}
 	return app
 }